@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/committee"
@@ -127,7 +128,7 @@ func (i seedInput) ToBeHashed() (protocol.HashID, []byte) {
 	return protocol.ProposerSeed, protocol.Encode(&i)
 }
 
-func deriveNewSeed(address basics.Address, vrf *crypto.VRFSecrets, rnd round, period period, ledger LedgerReader) (newSeed committee.Seed, seedProof crypto.VRFProof, reterr error) {
+func deriveNewSeed(address basics.Address, vrf account.ParticipationSigner, rnd round, period period, ledger LedgerReader) (newSeed committee.Seed, seedProof crypto.VRFProof, reterr error) {
 	var ok bool
 	var vrfOut crypto.VrfOutput
 
@@ -144,9 +145,9 @@ func deriveNewSeed(address basics.Address, vrf *crypto.VRFSecrets, rnd round, pe
 	}
 
 	if period == 0 {
-		seedProof, ok = vrf.SK.Prove(prevSeed)
-		if !ok {
-			reterr = fmt.Errorf("could not make seed proof")
+		seedProof, err = vrf.ProveVRF(prevSeed)
+		if err != nil {
+			reterr = fmt.Errorf("could not make seed proof: %v", err)
 			return
 		}
 		vrfOut, ok = seedProof.Hash()
@@ -231,8 +232,15 @@ func verifyNewSeed(p unauthenticatedProposal, ledger LedgerReader) error {
 }
 
 func proposalForBlock(address basics.Address, vrf *crypto.VRFSecrets, ve ValidatedBlock, period period, ledger LedgerReader) (proposal, proposalValue, error) {
+	return participationProposal(account.Participation{Parent: address, VRF: vrf}, ve, period, ledger)
+}
+
+// participationProposal makes the proposal of a block by a participation key,
+// which proves the new seed of the block.
+func participationProposal(part account.Participation, ve ValidatedBlock, period period, ledger LedgerReader) (proposal, proposalValue, error) {
+	address := part.Address()
 	rnd := ve.Block().Round()
-	newSeed, seedProof, err := deriveNewSeed(address, vrf, rnd, period, ledger)
+	newSeed, seedProof, err := deriveNewSeed(address, part, rnd, period, ledger)
 	if err != nil {
 		return proposal{}, proposalValue{}, fmt.Errorf("proposalForBlock: could not derive new seed: %v", err)
 	}
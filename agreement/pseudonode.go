@@ -279,7 +279,7 @@ func (n asyncPseudonode) makeProposals(round basics.Round, period period, accoun
 	votes := make([]unauthenticatedVote, 0, len(accounts))
 	proposals := make([]proposal, 0, len(accounts))
 	for _, account := range accounts {
-		payload, proposal, err := proposalForBlock(account.Address(), account.VRFSecrets(), ve, period, n.ledger)
+		payload, proposal, err := participationProposal(account, ve, period, n.ledger)
 		if err != nil {
 			n.log.Errorf("pseudonode.makeProposals: could not create proposal for block (address %v): %v", account.Address(), err)
 			continue
@@ -287,7 +287,7 @@ func (n asyncPseudonode) makeProposals(round basics.Round, period period, accoun
 
 		// attempt to make the vote
 		rv := rawVote{Sender: account.Address(), Round: round, Period: period, Step: propose, Proposal: proposal}
-		uv, err := makeVote(rv, account.VotingSigner(), account.VRFSecrets(), n.ledger)
+		uv, err := makeParticipationVote(rv, account, n.ledger)
 		if err != nil {
 			n.log.Warnf("pseudonode.makeProposals: could not create vote: %v", err)
 			continue
@@ -307,7 +307,7 @@ func (n asyncPseudonode) makeVotes(round basics.Round, period period, step step,
 	votes := make([]unauthenticatedVote, 0)
 	for _, account := range participation {
 		rv := rawVote{Sender: account.Address(), Round: round, Period: period, Step: step, Proposal: proposal}
-		uv, err := makeVote(rv, account.VotingSigner(), account.VRFSecrets(), n.ledger)
+		uv, err := makeParticipationVote(rv, account, n.ledger)
 		if err != nil {
 			n.log.Warnf("pseudonode.makeVotes: could not create vote: %v", err)
 			continue
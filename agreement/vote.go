@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/committee"
 	"github.com/algorand/go-algorand/logging"
@@ -142,6 +143,20 @@ func (uv unauthenticatedVote) verify(l LedgerReader) (vote, error) {
 //
 // makeVote returns an error it it fails.
 func makeVote(rv rawVote, voting crypto.OneTimeSigner, selection *crypto.VRFSecrets, l Ledger) (unauthenticatedVote, error) {
+	part := account.Participation{
+		Parent:      rv.Sender,
+		VRF:         selection,
+		Voting:      voting.OneTimeSignatureSecrets,
+		KeyDilution: voting.OptionalKeyDilution,
+	}
+	return makeParticipationVote(rv, part, l)
+}
+
+// makeParticipationVote creates a new unauthenticated vote from its
+// constituent components, having the participation key sign it.
+//
+// makeParticipationVote returns an error it it fails.
+func makeParticipationVote(rv rawVote, part account.Participation, l Ledger) (unauthenticatedVote, error) {
 	m, err := membership(l, rv.Sender, rv.Round, rv.Period, rv.Step)
 	if err != nil {
 		return unauthenticatedVote{}, fmt.Errorf("makeVote: could not get membership parameters: %v", err)
@@ -172,13 +187,17 @@ func makeVote(rv rawVote, voting crypto.OneTimeSigner, selection *crypto.VRFSecr
 		}
 	}
 
-	ephID := basics.OneTimeIDForRound(rv.Round, voting.KeyDilution(proto))
-	sig := voting.Sign(ephID, rv)
-	if (sig == crypto.OneTimeSignature{}) {
-		return unauthenticatedVote{}, fmt.Errorf("makeVote: got back empty signature for vote")
+	ephID := basics.OneTimeIDForRound(rv.Round, part.VotingSigner().KeyDilution(proto))
+	sig, err := part.SignVote(ephID, rv)
+	if err != nil {
+		return unauthenticatedVote{}, fmt.Errorf("makeVote: could not sign vote: %v", err)
 	}
 
-	cred := committee.MakeCredential(&selection.SK, m.Selector)
+	proof, err := part.ProveVRF(m.Selector)
+	if err != nil {
+		return unauthenticatedVote{}, fmt.Errorf("makeVote: could not make credential: %v", err)
+	}
+	cred := committee.UnauthenticatedCredential{Proof: proof}
 	return unauthenticatedVote{R: rv, Cred: cred, Sig: sig}, nil
 }
 
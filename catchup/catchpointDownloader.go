@@ -0,0 +1,415 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package catchup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/network"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+)
+
+const (
+	// catchpointChunksPeersFactor is the number of relays we ask for the download chunks of the catchpoint file, for each
+	// relay we download the file from in parallel. Asking more relays than we need allows us to pick the file most of them serve.
+	catchpointChunksPeersFactor = 2
+	// catchpointChunksRequestTimeout is the time we wait for a relay to return the download chunks of the catchpoint file,
+	// which might require the relay to hash the whole file.
+	catchpointChunksRequestTimeout = time.Minute
+	// catchpointChunkRequestTimeout is the time we wait for a relay to start sending a download chunk, on top of the time it
+	// takes to download it at the minimal acceptable speed.
+	catchpointChunkRequestTimeout = 30 * time.Second
+	// maxCatchpointChunksResponseSize is the largest encoded download chunks description we would accept.
+	maxCatchpointChunksResponseSize = ledger.MaxCatchpointDownloadChunks*(crypto.DigestSize+2) + 64
+)
+
+// errNoCatchpointChunks is returned when none of the relays serve the catchpoint file in chunks.
+var errNoCatchpointChunks = errors.New("none of the relays serve the catchpoint file in chunks")
+
+type catchpointDownloaderReporter interface {
+	updateCatchpointChunksProgress(totalChunks, acquiredChunks uint64)
+}
+
+// catchpointPeers is a peersRetriever that returns the fixed set of peers serving the catchpoint file being downloaded.
+type catchpointPeers []network.Peer
+
+// GetPeers returns the peers serving the catchpoint file, regardless of the requested options.
+func (cp catchpointPeers) GetPeers(options ...network.PeerOption) []network.Peer {
+	return cp
+}
+
+// catchpointDownloader downloads a catchpoint file in chunks from several relays in parallel. Each chunk is verified
+// against its hash, and written into the download file of the accessor, so that the download could be resumed after
+// a restart.
+type catchpointDownloader struct {
+	net      network.GossipNode
+	accessor ledger.CatchpointCatchupAccessor
+	log      logging.Logger
+
+	reporter catchpointDownloaderReporter
+	config   config.Local
+}
+
+func makeCatchpointDownloader(net network.GossipNode, accessor ledger.CatchpointCatchupAccessor, log logging.Logger, reporter catchpointDownloaderReporter, cfg config.Local) *catchpointDownloader {
+	return &catchpointDownloader{
+		net:      net,
+		accessor: accessor,
+		log:      log,
+		reporter: reporter,
+		config:   cfg,
+	}
+}
+
+// downloadCatchpointFile downloads the catchpoint file of the given round from the relays picked by relaysSelector,
+// resuming the previous download if the relays still serve the same file. It returns the relays the file was
+// downloaded from, or errNoCatchpointChunks if none of them serve the file in chunks.
+func (cd *catchpointDownloader) downloadCatchpointFile(ctx context.Context, round basics.Round, relaysSelector *peerSelector) (peers []*peerSelectorPeer, err error) {
+	parallelism := cd.config.CatchupLedgerDownloadPeers
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	storedChunks, err := cd.accessor.GetDownloadChunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := relaysSelector.getNextPeers(parallelism * catchpointChunksPeersFactor)
+	if err != nil {
+		return nil, err
+	}
+	peers, chunks := cd.selectPeers(ctx, round, candidates, storedChunks)
+	if len(peers) == 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errNoCatchpointChunks
+	}
+
+	resume := storedChunks != nil && storedChunks.Digest() == chunks.Digest()
+	if !resume {
+		if storedChunks != nil {
+			cd.log.Infof("downloadCatchpointFile: the relays no longer serve the catchpoint file that was being downloaded; restarting the download")
+		}
+		// drop the previously downloaded file, if any, before starting over.
+		err = cd.accessor.SetDownloadChunks(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = cd.accessor.SetDownloadChunks(ctx, &chunks)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	filePath := cd.accessor.GetDownloadFilePath()
+	err = os.MkdirAll(filepath.Dir(filePath), 0700)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	err = file.Truncate(int64(chunks.FileSize))
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []int
+	if resume {
+		pending, err = missingChunks(file, &chunks)
+		if err != nil {
+			return nil, err
+		}
+		cd.log.Infof("downloadCatchpointFile: resuming the download of the catchpoint file for round %d, %d out of %d chunks are missing", round, len(pending), len(chunks.Hashes))
+	} else {
+		pending = make([]int, len(chunks.Hashes))
+		for i := range pending {
+			pending[i] = i
+		}
+	}
+
+	err = cd.fetchChunks(ctx, round, file, &chunks, pending, peers, parallelism)
+	if err != nil {
+		return nil, err
+	}
+	return peers, file.Sync()
+}
+
+// selectPeers asks the candidates for the download chunks of the catchpoint file, and returns the ones that serve the
+// file that was being downloaded, if any of them still does, or otherwise, the ones that serve the file most of them do.
+func (cd *catchpointDownloader) selectPeers(ctx context.Context, round basics.Round, candidates []*peerSelectorPeer, storedChunks *ledger.CatchpointDownloadChunks) (peers []*peerSelectorPeer, chunks ledger.CatchpointDownloadChunks) {
+	peersChunks := make([]*ledger.CatchpointDownloadChunks, len(candidates))
+	var wg sync.WaitGroup
+	for i, psp := range candidates {
+		httpPeer, ok := psp.Peer.(network.HTTPPeer)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, httpPeer network.HTTPPeer) {
+			defer wg.Done()
+			peerChunks, err := cd.getPeerChunks(ctx, httpPeer, round)
+			if err != nil {
+				cd.log.Debugf("selectPeers: unable to get the download chunks of the catchpoint file from %s : %v", httpPeer.GetAddress(), err)
+				return
+			}
+			peersChunks[i] = peerChunks
+		}(i, httpPeer)
+	}
+	wg.Wait()
+
+	groups := make(map[crypto.Digest][]*peerSelectorPeer)
+	var selected crypto.Digest
+	for i, peerChunks := range peersChunks {
+		if peerChunks == nil {
+			continue
+		}
+		digest := peerChunks.Digest()
+		groups[digest] = append(groups[digest], candidates[i])
+		// candidates are ordered by their rank, so that the better ranked group is selected in case of a tie.
+		if len(groups[digest]) > len(groups[selected]) {
+			selected = digest
+			chunks = *peerChunks
+		}
+	}
+	if storedChunks != nil {
+		if storedPeers := groups[storedChunks.Digest()]; len(storedPeers) > 0 {
+			return storedPeers, *storedChunks
+		}
+	}
+	return groups[selected], chunks
+}
+
+// fetchChunks downloads the pending chunks using the given number of parallel workers, which pick the peer to download
+// each chunk from according to the peers ranking. A chunk that fails to download is retried, up to
+// CatchupLedgerDownloadRetryAttempts times.
+func (cd *catchpointDownloader) fetchChunks(ctx context.Context, round basics.Round, file *os.File, chunks *ledger.CatchpointDownloadChunks, pending []int, peers []*peerSelectorPeer, parallelism int) error {
+	totalChunks := uint64(len(chunks.Hashes))
+	acquiredChunks := totalChunks - uint64(len(pending))
+	if cd.reporter != nil {
+		cd.reporter.updateCatchpointChunksProgress(totalChunks, acquiredChunks)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	chunkPeers := make(catchpointPeers, len(peers))
+	for i, psp := range peers {
+		chunkPeers[i] = psp.Peer
+	}
+	selector := makePeerSelector(chunkPeers, []peerClass{{initialRank: peerRankInitialFirstPriority, peerClass: network.PeersPhonebookRelays}})
+
+	queue := make(chan int, len(pending))
+	for _, chunk := range pending {
+		queue <- chunk
+	}
+	workersCtx, cancelWorkers := context.WithCancel(ctx)
+	defer cancelWorkers()
+
+	var mu deadlock.Mutex
+	attempts := make(map[int]int)
+	remaining := len(pending)
+	var fetchErr error
+
+	if parallelism > len(pending) {
+		parallelism = len(pending)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var chunk int
+				select {
+				case <-workersCtx.Done():
+					return
+				case chunk = <-queue:
+				}
+				err := cd.fetchChunk(workersCtx, round, file, chunks, chunk, selector)
+
+				mu.Lock()
+				if err == nil {
+					remaining--
+					acquiredChunks++
+					if cd.reporter != nil {
+						cd.reporter.updateCatchpointChunksProgress(totalChunks, acquiredChunks)
+					}
+					if remaining == 0 {
+						cancelWorkers()
+					}
+				} else if workersCtx.Err() == nil {
+					attempts[chunk]++
+					if attempts[chunk] >= cd.config.CatchupLedgerDownloadRetryAttempts {
+						fetchErr = fmt.Errorf("fetchChunks exceeded number of attempts to download chunk %d : %v", chunk, err)
+						cancelWorkers()
+					} else {
+						cd.log.Debugf("fetchChunks failed to download chunk %d : %v", chunk, err)
+						queue <- chunk
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetchErr != nil {
+		return fetchErr
+	}
+	if remaining > 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// fetchChunk downloads a single chunk from the next peer of the selector, and writes it into the file once it's verified.
+func (cd *catchpointDownloader) fetchChunk(ctx context.Context, round basics.Round, file *os.File, chunks *ledger.CatchpointDownloadChunks, chunk int, selector *peerSelector) error {
+	psp, err := selector.getNextPeer()
+	if err != nil {
+		return err
+	}
+	httpPeer, ok := psp.Peer.(network.HTTPPeer)
+	if !ok {
+		selector.rankPeer(psp, peerRankInvalidDownload)
+		return errNonHTTPPeer
+	}
+	offset, size := chunks.Chunk(chunk)
+	start := time.Now()
+	data, err := cd.getPeerChunk(ctx, httpPeer, round, chunk, size)
+	if err != nil {
+		if ctx.Err() == nil {
+			selector.rankPeer(psp, peerRankDownloadFailed)
+		}
+		return err
+	}
+	downloadDuration := time.Now().Sub(start)
+	err = chunks.VerifyChunk(chunk, data)
+	if err != nil {
+		selector.rankPeer(psp, peerRankInvalidDownload)
+		return err
+	}
+	selector.rankPeer(psp, selector.peerChunkDownloadDurationToRank(psp, downloadDuration))
+	_, err = file.WriteAt(data, int64(offset))
+	return err
+}
+
+// getPeerChunks requests the download chunks of the catchpoint file of the given round from the peer.
+func (cd *catchpointDownloader) getPeerChunks(ctx context.Context, peer network.HTTPPeer, round basics.Round) (*ledger.CatchpointDownloadChunks, error) {
+	ctx, cancel := context.WithTimeout(ctx, catchpointChunksRequestTimeout)
+	defer cancel()
+	body, err := cd.get(ctx, peer, round, "/chunks", rpcs.LedgerChunksResponseContentType, maxCatchpointChunksResponseSize)
+	if err != nil {
+		return nil, err
+	}
+	var chunks ledger.CatchpointDownloadChunks
+	err = protocol.Decode(body, &chunks)
+	if err != nil {
+		return nil, err
+	}
+	err = chunks.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &chunks, nil
+}
+
+// getPeerChunk requests a single download chunk of the catchpoint file of the given round from the peer.
+func (cd *catchpointDownloader) getPeerChunk(ctx context.Context, peer network.HTTPPeer, round basics.Round, chunk int, size uint64) ([]byte, error) {
+	minBytesPerSecond := cd.config.MinCatchpointFileDownloadBytesPerSecond
+	if minBytesPerSecond == 0 {
+		minBytesPerSecond = defaultMinCatchpointFileDownloadBytesPerSecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, catchpointChunkRequestTimeout+time.Duration(size)*time.Second/time.Duration(minBytesPerSecond))
+	defer cancel()
+	return cd.get(ctx, peer, round, "/chunk/"+strconv.Itoa(chunk), rpcs.LedgerChunkResponseContentType, int64(size))
+}
+
+// get sends a GET request for the given suffix of the ledger path of the round to the peer, and returns the response body.
+func (cd *catchpointDownloader) get(ctx context.Context, peer network.HTTPPeer, round basics.Round, pathSuffix string, contentType string, maxSize int64) ([]byte, error) {
+	parsedURL, err := network.ParseHostOrURL(peer.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	parsedURL.Path = cd.net.SubstituteGenesisID(path.Join(parsedURL.Path, "/v1/{genesisID}/ledger/"+strconv.FormatUint(uint64(round), 36)+pathSuffix))
+	request, err := http.NewRequest(http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+	network.SetUserAgentHeader(request.Header)
+	response, err := peer.GetHTTPClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound: // the peer doesn't have the catchpoint file, or doesn't serve it in chunks.
+		return nil, errNoLedgerForRound
+	default:
+		return nil, fmt.Errorf("catchpointDownloader error response status code %d", response.StatusCode)
+	}
+	if response.Header.Get("Content-Type") != contentType {
+		return nil, fmt.Errorf("catchpointDownloader response has an invalid content type : %s", response.Header.Get("Content-Type"))
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("catchpointDownloader response exceeds %d bytes", maxSize)
+	}
+	return body, nil
+}
+
+// missingChunks returns the chunks of the file that don't match their hashes.
+func missingChunks(file *os.File, chunks *ledger.CatchpointDownloadChunks) (missing []int, err error) {
+	buf := make([]byte, chunks.ChunkSize)
+	for chunk := range chunks.Hashes {
+		offset, size := chunks.Chunk(chunk)
+		_, err = file.ReadAt(buf[:size], int64(offset))
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if chunks.VerifyChunk(chunk, buf[:size]) != nil {
+			missing = append(missing, chunk)
+		}
+	}
+	return missing, nil
+}
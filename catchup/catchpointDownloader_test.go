@@ -0,0 +1,191 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package catchup
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/components/mocks"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/network"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/rpcs"
+)
+
+type downloadChunksAccessor struct {
+	mocks.MockCatchpointCatchupAccessor
+	chunks   *ledger.CatchpointDownloadChunks
+	filePath string
+}
+
+func (a *downloadChunksAccessor) GetDownloadChunks(ctx context.Context) (*ledger.CatchpointDownloadChunks, error) {
+	return a.chunks, nil
+}
+
+func (a *downloadChunksAccessor) SetDownloadChunks(ctx context.Context, chunks *ledger.CatchpointDownloadChunks) error {
+	a.chunks = chunks
+	if chunks == nil {
+		os.Remove(a.filePath)
+	}
+	return nil
+}
+
+func (a *downloadChunksAccessor) GetDownloadFilePath() string {
+	return a.filePath
+}
+
+type dummyCatchpointDownloaderReporter struct {
+	totalChunks, acquiredChunks uint64
+}
+
+func (r *dummyCatchpointDownloaderReporter) updateCatchpointChunksProgress(totalChunks, acquiredChunks uint64) {
+	atomic.StoreUint64(&r.totalChunks, totalChunks)
+	atomic.StoreUint64(&r.acquiredChunks, acquiredChunks)
+}
+
+// chunksServer serves the given file in chunks, counting the chunk requests it receives.
+type chunksServer struct {
+	file     []byte
+	chunks   ledger.CatchpointDownloadChunks
+	requests int32
+}
+
+func startChunksServer(t *testing.T, file []byte, chunkSize uint64) (*chunksServer, network.HTTPPeer, func()) {
+	chunks, err := ledger.MakeCatchpointDownloadChunks(bytes.NewReader(file), chunkSize)
+	require.NoError(t, err)
+	cs := &chunksServer{file: file, chunks: chunks}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/chunks") {
+			w.Header().Set("Content-Type", rpcs.LedgerChunksResponseContentType)
+			w.Write(protocol.Encode(&cs.chunks))
+			return
+		}
+		chunk, err := strconv.Atoi(req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:])
+		if err != nil || chunk >= len(cs.chunks.Hashes) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		atomic.AddInt32(&cs.requests, 1)
+		offset, size := cs.chunks.Chunk(chunk)
+		w.Header().Set("Content-Type", rpcs.LedgerChunkResponseContentType)
+		w.Write(cs.file[offset : offset+size])
+	})
+	listener, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	s := &http.Server{Handler: mux}
+	go s.Serve(listener)
+	peer := testHTTPPeer(listener.Addr().String())
+	return cs, &peer, func() {
+		s.Close()
+		listener.Close()
+	}
+}
+
+func TestCatchpointDownloaderDownloadAndResume(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "catchpointdownloader")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	file := make([]byte, 10*1024+100)
+	crypto.RandBytes(file)
+	otherFile := make([]byte, len(file))
+	crypto.RandBytes(otherFile)
+
+	// two peers serve the same file, while a third serves a different one.
+	serverA, peerA, closeA := startChunksServer(t, file, 1024)
+	defer closeA()
+	serverB, peerB, closeB := startChunksServer(t, file, 1024)
+	defer closeB()
+	serverC, peerC, closeC := startChunksServer(t, otherFile, 1024)
+	defer closeC()
+
+	accessor := &downloadChunksAccessor{filePath: filepath.Join(tempDir, "catchpoints", "catchpointcatchup.download")}
+	reporter := &dummyCatchpointDownloaderReporter{}
+	cfg := config.GetDefaultLocal()
+	cfg.CatchupLedgerDownloadPeers = 2
+	cd := makeCatchpointDownloader(&mocks.MockNetwork{}, accessor, logging.TestingLog(t), reporter, cfg)
+	relaysSelector := makePeerSelector(
+		catchpointPeers{peerA, peerB, peerC},
+		[]peerClass{{initialRank: peerRankInitialFirstPriority, peerClass: network.PeersPhonebookRelays}})
+
+	peers, err := cd.downloadCatchpointFile(context.Background(), 1000, relaysSelector)
+	require.NoError(t, err)
+	require.Len(t, peers, 2)
+	for _, psp := range peers {
+		require.NotEqual(t, peerC, psp.Peer)
+	}
+	require.NotNil(t, accessor.chunks)
+	require.Equal(t, serverA.chunks.Digest(), accessor.chunks.Digest())
+	downloaded, err := ioutil.ReadFile(accessor.filePath)
+	require.NoError(t, err)
+	require.Equal(t, file, downloaded)
+	require.Equal(t, uint64(11), atomic.LoadUint64(&reporter.totalChunks))
+	require.Equal(t, uint64(11), atomic.LoadUint64(&reporter.acquiredChunks))
+	require.Equal(t, int32(11), atomic.LoadInt32(&serverA.requests)+atomic.LoadInt32(&serverB.requests))
+	require.Zero(t, atomic.LoadInt32(&serverC.requests))
+
+	// corrupt one of the chunks; resuming the download fetches only that chunk.
+	downloaded[5*1024]++
+	require.NoError(t, ioutil.WriteFile(accessor.filePath, downloaded, 0600))
+	atomic.StoreInt32(&serverA.requests, 0)
+	atomic.StoreInt32(&serverB.requests, 0)
+
+	_, err = cd.downloadCatchpointFile(context.Background(), 1000, relaysSelector)
+	require.NoError(t, err)
+	downloaded, err = ioutil.ReadFile(accessor.filePath)
+	require.NoError(t, err)
+	require.Equal(t, file, downloaded)
+	require.Equal(t, int32(1), atomic.LoadInt32(&serverA.requests)+atomic.LoadInt32(&serverB.requests))
+}
+
+func TestCatchpointDownloaderNoChunks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	listener, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+	s := &http.Server{Handler: mux}
+	go s.Serve(listener)
+	defer s.Close()
+	defer listener.Close()
+
+	peer := testHTTPPeer(listener.Addr().String())
+	cd := makeCatchpointDownloader(&mocks.MockNetwork{}, &downloadChunksAccessor{}, logging.TestingLog(t), nil, config.GetDefaultLocal())
+	relaysSelector := makePeerSelector(
+		catchpointPeers{&peer},
+		[]peerClass{{initialRank: peerRankInitialFirstPriority, peerClass: network.PeersPhonebookRelays}})
+	_, err = cd.downloadCatchpointFile(context.Background(), 1000, relaysSelector)
+	require.Equal(t, errNoCatchpointChunks, err)
+}
@@ -19,6 +19,7 @@ package catchup
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -49,6 +50,8 @@ type CatchpointCatchupStats struct {
 	AcquiredBlocks    uint64
 	VerifiedBlocks    uint64
 	ProcessedBytes    uint64
+	TotalChunks       uint64
+	AcquiredChunks    uint64
 	StartTime         time.Time
 }
 
@@ -102,6 +105,9 @@ func MakeResumedCatchpointCatchupService(ctx context.Context, node CatchpointCat
 		ledger:         l,
 		config:         cfg,
 	}
+	if service.ledgerAccessor == nil {
+		return nil, fmt.Errorf("MakeResumedCatchpointCatchupService: the ledger doesn't support catchpoint catchup")
+	}
 	service.lastBlockHeader, err = l.BlockHdr(l.Latest())
 	if err != nil {
 		return nil, err
@@ -133,6 +139,9 @@ func MakeNewCatchpointCatchupService(catchpoint string, node CatchpointCatchupNo
 		ledger:         l,
 		config:         cfg,
 	}
+	if service.ledgerAccessor == nil {
+		return nil, fmt.Errorf("MakeNewCatchpointCatchupService: the ledger doesn't support catchpoint catchup")
+	}
 	service.lastBlockHeader, err = l.BlockHdr(l.Latest())
 	if err != nil {
 		return nil, err
@@ -269,6 +278,7 @@ func (cs *CatchpointCatchupService) processStageLedgerDownload() (err error) {
 	// download balances file.
 	peerSelector := makePeerSelector(cs.net, []peerClass{{initialRank: peerRankInitialFirstPriority, peerClass: network.PeersPhonebookRelays}})
 	ledgerFetcher := makeLedgerFetcher(cs.net, cs.ledgerAccessor, cs.log, cs, cs.config)
+	downloadInChunks := cs.config.CatchupLedgerDownloadPeers > 0
 	attemptsCount := 0
 
 	for {
@@ -281,22 +291,38 @@ func (cs *CatchpointCatchupService) processStageLedgerDownload() (err error) {
 			}
 			return cs.abort(fmt.Errorf("processStageLedgerDownload failed to reset staging balances : %v", err))
 		}
-		psp, err := peerSelector.getNextPeer()
-		if err != nil {
-			err = fmt.Errorf("processStageLedgerDownload: catchpoint catchup was unable to obtain a list of peers to retrieve the catchpoint file from")
-			return cs.abort(err)
-		}
-		peer := psp.Peer
-		err = ledgerFetcher.downloadLedger(cs.ctx, peer, round)
-		if err == nil {
-			err = cs.ledgerAccessor.BuildMerkleTrie(cs.ctx, cs.updateVerifiedAccounts)
+		if downloadInChunks {
+			err = cs.downloadLedgerInChunks(round, peerSelector, ledgerFetcher)
 			if err == nil {
 				break
 			}
-			// failed to build the merkle trie for the above catchpoint file.
-			peerSelector.rankPeer(psp, peerRankInvalidDownload)
+			if err == errPeerSelectorNoPeerPoolsAvailable {
+				err = fmt.Errorf("processStageLedgerDownload: catchpoint catchup was unable to obtain a list of peers to retrieve the catchpoint file from")
+				return cs.abort(err)
+			}
+			if err == errNoCatchpointChunks {
+				// fall back to downloading the whole file from a single relay.
+				downloadInChunks = false
+			}
 		} else {
-			peerSelector.rankPeer(psp, peerRankDownloadFailed)
+			var psp *peerSelectorPeer
+			psp, err = peerSelector.getNextPeer()
+			if err != nil {
+				err = fmt.Errorf("processStageLedgerDownload: catchpoint catchup was unable to obtain a list of peers to retrieve the catchpoint file from")
+				return cs.abort(err)
+			}
+			peer := psp.Peer
+			err = ledgerFetcher.downloadLedger(cs.ctx, peer, round)
+			if err == nil {
+				err = cs.ledgerAccessor.BuildMerkleTrie(cs.ctx, cs.updateVerifiedAccounts)
+				if err == nil {
+					break
+				}
+				// failed to build the merkle trie for the above catchpoint file.
+				peerSelector.rankPeer(psp, peerRankInvalidDownload)
+			} else {
+				peerSelector.rankPeer(psp, peerRankDownloadFailed)
+			}
 		}
 
 		// instead of testing for err == cs.ctx.Err() , we'll check on the context itself.
@@ -320,6 +346,50 @@ func (cs *CatchpointCatchupService) processStageLedgerDownload() (err error) {
 	return nil
 }
 
+// downloadLedgerInChunks downloads the catchpoint file in chunks from several relays in parallel, and loads it into the
+// staging balances once it's complete. It returns errNoCatchpointChunks if none of the relays serve the file in chunks.
+func (cs *CatchpointCatchupService) downloadLedgerInChunks(round basics.Round, peerSelector *peerSelector, ledgerFetcher *ledgerFetcher) error {
+	downloader := makeCatchpointDownloader(cs.net, cs.ledgerAccessor, cs.log, cs, cs.config)
+	peers, err := downloader.downloadCatchpointFile(cs.ctx, round, peerSelector)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(cs.ledgerAccessor.GetDownloadFilePath())
+	if err != nil {
+		return err
+	}
+	err = ledgerFetcher.loadLedgerFile(cs.ctx, file)
+	file.Close()
+	if err == nil {
+		err = cs.ledgerAccessor.BuildMerkleTrie(cs.ctx, cs.updateVerifiedAccounts)
+	}
+	if err != nil {
+		if cs.ctx.Err() != nil {
+			// keep the downloaded file, so that loading it could be resumed.
+			return err
+		}
+		// all the chunks matched their hashes, so it's the catchpoint file these relays serve that is invalid.
+		for _, psp := range peers {
+			peerSelector.rankPeer(psp, peerRankInvalidDownload)
+		}
+		if err0 := cs.ledgerAccessor.SetDownloadChunks(cs.ctx, nil); err0 != nil {
+			cs.log.Warnf("processStageLedgerDownload failed to remove the invalid catchpoint file : %v", err0)
+		}
+		return err
+	}
+	// the staging balances hold the content of the file by now, so we don't need it anymore.
+	return cs.ledgerAccessor.SetDownloadChunks(cs.ctx, nil)
+}
+
+// updateCatchpointChunksProgress updates the user's statistics for the catchpoint file chunks download
+func (cs *CatchpointCatchupService) updateCatchpointChunksProgress(totalChunks, acquiredChunks uint64) {
+	cs.statsMu.Lock()
+	defer cs.statsMu.Unlock()
+	cs.stats.TotalChunks = totalChunks
+	cs.stats.AcquiredChunks = acquiredChunks
+}
+
 // updateVerifiedAccounts update the user's statistics for the given verified accounts
 func (cs *CatchpointCatchupService) updateVerifiedAccounts(verifiedAccounts uint64) {
 	cs.statsMu.Lock()
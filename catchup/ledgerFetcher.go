@@ -18,6 +18,7 @@ package catchup
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -138,6 +139,30 @@ func (lf *ledgerFetcher) getPeerLedger(ctx context.Context, peer network.HTTPPee
 	watchdogReader := util.MakeWatchdogStreamReader(response.Body, catchpointFileStreamReadSize, 2*maxCatchpointFileChunkSize, maxCatchpointFileChunkDownloadDuration)
 	defer watchdogReader.Close()
 	tarReader := tar.NewReader(watchdogReader)
+	return lf.processTarSections(ctx, tarReader, func() error {
+		if err := watchdogReader.Reset(); err != nil {
+			if err == io.EOF {
+				return io.EOF
+			}
+			return fmt.Errorf("getPeerLedger received the following error while reading the catchpoint file : %v", err)
+		}
+		return nil
+	})
+}
+
+// loadLedgerFile processes the gzip compressed catchpoint file, once it was downloaded in full.
+func (lf *ledgerFetcher) loadLedgerFile(ctx context.Context, file io.Reader) error {
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("loadLedgerFile unable to decompress the catchpoint file : %v", err)
+	}
+	defer gzipReader.Close()
+	return lf.processTarSections(ctx, tar.NewReader(gzipReader), nil)
+}
+
+// processTarSections passes each of the sections of the catchpoint tar stream to the accessor, and reports the progress.
+// nextSection, if provided, is called before moving to the next section; returning io.EOF from it completes the processing.
+func (lf *ledgerFetcher) processTarSections(ctx context.Context, tarReader *tar.Reader, nextSection func() error) error {
 	var downloadProgress ledger.CatchpointCatchupAccessorProgress
 	for {
 		header, err := tarReader.Next()
@@ -148,7 +173,7 @@ func (lf *ledgerFetcher) getPeerLedger(ctx context.Context, peer network.HTTPPee
 			return err
 		}
 		if header.Size > maxCatchpointFileChunkSize || header.Size < 1 {
-			return fmt.Errorf("getPeerLedger received a tar header with data size of %d", header.Size)
+			return fmt.Errorf("processTarSections received a tar header with data size of %d", header.Size)
 		}
 		balancesBlockBytes := make([]byte, header.Size)
 		readComplete := int64(0)
@@ -161,7 +186,7 @@ func (lf *ledgerFetcher) getPeerLedger(ctx context.Context, peer network.HTTPPee
 					if readComplete == header.Size {
 						break
 					}
-					err = fmt.Errorf("getPeerLedger received io.EOF while reading from tar file stream prior of reaching chunk size %d / %d", readComplete, header.Size)
+					err = fmt.Errorf("processTarSections received io.EOF while reading from tar file stream prior of reaching chunk size %d / %d", readComplete, header.Size)
 				}
 				return err
 			}
@@ -173,12 +198,13 @@ func (lf *ledgerFetcher) getPeerLedger(ctx context.Context, peer network.HTTPPee
 		if lf.reporter != nil {
 			lf.reporter.updateLedgerFetcherProgress(&downloadProgress)
 		}
-		if err = watchdogReader.Reset(); err != nil {
-			if err == io.EOF {
-				return nil
+		if nextSection != nil {
+			if err = nextSection(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
 			}
-			err = fmt.Errorf("getPeerLedger received the following error while reading the catchpoint file : %v", err)
-			return err
 		}
 	}
 }
@@ -59,6 +59,11 @@ const (
 	lowBlockDownloadThreshold  = 50 * time.Millisecond
 	highBlockDownloadThreshold = 8 * time.Second
 
+	// once a catchpoint file download chunk is downloaded, the download duration is clamped into the range of
+	// [lowChunkDownloadThreshold..highChunkDownloadThreshold] and then mapped into the a ranking range.
+	lowChunkDownloadThreshold  = 100 * time.Millisecond
+	highChunkDownloadThreshold = 60 * time.Second
+
 	// Is the lookback window size of peer usage statistics
 	peerHistoryWindowSize = 100
 )
@@ -298,6 +303,39 @@ func (ps *peerSelector) getNextPeer() (psp *peerSelectorPeer, err error) {
 	return nil, errPeerSelectorNoPeerPoolsAvailable
 }
 
+// getNextPeers returns up to count distinct peers, starting with the peers of the pool that has the lowest rank value.
+// The peers of each pool are returned in a random order.
+func (ps *peerSelector) getNextPeers(count int) (psps []*peerSelectorPeer, err error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.refreshAvailablePeers()
+	seen := make(map[string]bool)
+	for _, pool := range ps.pools {
+		peers := make([]peerPoolEntry, len(pool.peers))
+		copy(peers, pool.peers)
+		for i := len(peers) - 1; i > 0; i-- {
+			j := crypto.RandUint64() % uint64(i+1)
+			peers[i], peers[j] = peers[j], peers[i]
+		}
+		for _, peer := range peers {
+			if len(psps) >= count {
+				return psps, nil
+			}
+			// the same peer could be listed under several peer classes.
+			address := peerAddress(peer.peer)
+			if seen[address] {
+				continue
+			}
+			seen[address] = true
+			psps = append(psps, &peerSelectorPeer{peer.peer, peer.class.peerClass})
+		}
+	}
+	if len(psps) == 0 {
+		return nil, errPeerSelectorNoPeerPoolsAvailable
+	}
+	return psps, nil
+}
+
 // rankPeer ranks a given peer.
 // return the old value and the new updated value.
 // updated value could be different from the input rank.
@@ -369,6 +407,16 @@ func (ps *peerSelector) rankPeer(psp *peerSelectorPeer, rank int) (int, int) {
 
 // peerDownloadDurationToRank calculates the rank for a peer given a peer and the block download time.
 func (ps *peerSelector) peerDownloadDurationToRank(psp *peerSelectorPeer, blockDownloadDuration time.Duration) (rank int) {
+	return ps.peerDurationToRank(psp, blockDownloadDuration, lowBlockDownloadThreshold, highBlockDownloadThreshold)
+}
+
+// peerChunkDownloadDurationToRank calculates the rank for a peer given a peer and the catchpoint file chunk download time.
+func (ps *peerSelector) peerChunkDownloadDurationToRank(psp *peerSelectorPeer, chunkDownloadDuration time.Duration) (rank int) {
+	return ps.peerDurationToRank(psp, chunkDownloadDuration, lowChunkDownloadThreshold, highChunkDownloadThreshold)
+}
+
+// peerDurationToRank maps the download duration into the rank range of the class of the given peer.
+func (ps *peerSelector) peerDurationToRank(psp *peerSelectorPeer, downloadDuration, lowDownloadThreshold, highDownloadThreshold time.Duration) (rank int) {
 	ps.mu.Lock()
 	defer ps.mu.Unlock()
 	poolIdx, peerIdx := ps.findPeer(psp)
@@ -378,13 +426,13 @@ func (ps *peerSelector) peerDownloadDurationToRank(psp *peerSelectorPeer, blockD
 
 	switch ps.pools[poolIdx].peers[peerIdx].class.initialRank {
 	case peerRankInitialFirstPriority:
-		return downloadDurationToRank(blockDownloadDuration, lowBlockDownloadThreshold, highBlockDownloadThreshold, peerRank0LowBlockTime, peerRank0HighBlockTime)
+		return downloadDurationToRank(downloadDuration, lowDownloadThreshold, highDownloadThreshold, peerRank0LowBlockTime, peerRank0HighBlockTime)
 	case peerRankInitialSecondPriority:
-		return downloadDurationToRank(blockDownloadDuration, lowBlockDownloadThreshold, highBlockDownloadThreshold, peerRank1LowBlockTime, peerRank1HighBlockTime)
+		return downloadDurationToRank(downloadDuration, lowDownloadThreshold, highDownloadThreshold, peerRank1LowBlockTime, peerRank1HighBlockTime)
 	case peerRankInitialThirdPriority:
-		return downloadDurationToRank(blockDownloadDuration, lowBlockDownloadThreshold, highBlockDownloadThreshold, peerRank2LowBlockTime, peerRank2HighBlockTime)
+		return downloadDurationToRank(downloadDuration, lowDownloadThreshold, highDownloadThreshold, peerRank2LowBlockTime, peerRank2HighBlockTime)
 	default: // i.e. peerRankInitialFourthPriority
-		return downloadDurationToRank(blockDownloadDuration, lowBlockDownloadThreshold, highBlockDownloadThreshold, peerRank3LowBlockTime, peerRank3HighBlockTime)
+		return downloadDurationToRank(downloadDuration, lowDownloadThreshold, highDownloadThreshold, peerRank3LowBlockTime, peerRank3HighBlockTime)
 	}
 }
 
@@ -50,6 +50,7 @@ var versionCheck = flag.Bool("v", false, "Display and write current build versio
 var branchCheck = flag.Bool("b", false, "Display the git branch behind the build")
 var channelCheck = flag.Bool("c", false, "Display and release channel behind the build")
 var initAndExit = flag.Bool("x", false, "Initialize the ledger and exit")
+var importBlocksDir = flag.String("importblocks", "", "Import the blocks of the block archive files in the given directory into the ledger before starting")
 var peerOverride = flag.String("p", "", "Override phonebook with peer ip:port (or semicolon separated list: ip:port;ip:port;ip:port...)")
 var listenIP = flag.String("l", "", "Override config.EndpointAddress (REST listening address) with ip:port")
 var sessionGUID = flag.String("s", "", "Telemetry Session GUID to use")
@@ -299,6 +300,15 @@ func run() int {
 		return 1
 	}
 
+	if *importBlocksDir != "" {
+		err = s.ImportBlockArchives(*importBlocksDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			log.Error(err)
+			return 1
+		}
+	}
+
 	if *initAndExit {
 		return 0
 	}
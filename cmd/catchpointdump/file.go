@@ -191,14 +191,16 @@ func printAccountsDatabase(databaseName string, fileHeader ledger.CatchpointFile
 		return err
 	}
 	if fileHeader.Version != 0 {
-		fmt.Fprintf(fileWriter, "Version: %d\nBalances Round: %d\nBlock Round: %d\nBlock Header Digest: %s\nCatchpoint: %s\nTotal Accounts: %d\nTotal Chunks: %d\n",
+		fmt.Fprintf(fileWriter, "Version: %d\nBalances Round: %d\nBlock Round: %d\nBlock Header Digest: %s\nCatchpoint: %s\nTotal Accounts: %d\nTotal Chunks: %d\nTotal Boxes: %d\nTotal Resources: %d\n",
 			fileHeader.Version,
 			fileHeader.BalancesRound,
 			fileHeader.BlocksRound,
 			fileHeader.BlockHeaderDigest.String(),
 			fileHeader.Catchpoint,
 			fileHeader.TotalAccounts,
-			fileHeader.TotalChunks)
+			fileHeader.TotalChunks,
+			fileHeader.TotalBoxes,
+			fileHeader.TotalResources)
 
 		totals := fileHeader.Totals
 		fmt.Fprintf(fileWriter, "AccountTotals - Online Money: %d\nAccountTotals - Online RewardUnits : %d\nAccountTotals - Offline Money: %d\nAccountTotals - Offline RewardUnits : %d\nAccountTotals - Not Participating Money: %d\nAccountTotals - Not Participating Money RewardUnits: %d\nAccountTotals - Rewards Level: %d\n",
@@ -230,8 +232,26 @@ func printAccountsDatabase(databaseName string, fileHeader ledger.CatchpointFile
 		}
 
 		balancesTable := "accountbase"
+		resourcesTable := "resources"
 		if fileHeader.Version != 0 {
 			balancesTable = "catchpointbalances"
+			resourcesTable = "catchpointresources"
+		}
+
+		// the asset holdings and application local states are stored apart from the rest of the account data,
+		// unless the database predates the resources table.
+		var selectResourcesStmt *sql.Stmt
+		var hasResources bool
+		err = tx.QueryRow("SELECT 1 FROM sqlite_master WHERE type='table' AND name=?", resourcesTable).Scan(&hasResources)
+		if err != nil && err != sql.ErrNoRows {
+			return
+		}
+		if hasResources {
+			selectResourcesStmt, err = tx.Prepare(fmt.Sprintf("SELECT aidx, rtype, data FROM %s WHERE address=?", resourcesTable))
+			if err != nil {
+				return
+			}
+			defer selectResourcesStmt.Close()
 		}
 
 		var rowsCount int64
@@ -266,6 +286,12 @@ func printAccountsDatabase(databaseName string, fileHeader ledger.CatchpointFile
 				return
 			}
 			copy(addr[:], addrbuf)
+			if selectResourcesStmt != nil {
+				err = loadAccountResources(selectResourcesStmt, addr, &data)
+				if err != nil {
+					return
+				}
+			}
 			jsonData, err := json.Marshal(data)
 			if err != nil {
 				return err
@@ -286,3 +312,48 @@ func printAccountsDatabase(databaseName string, fileHeader ledger.CatchpointFile
 		return nil
 	})
 }
+
+// loadAccountResources reads the asset holdings and application local states of the given address using
+// selectStmt and adds them to the given account data.
+func loadAccountResources(selectStmt *sql.Stmt, addr basics.Address, data *basics.AccountData) error {
+	rows, err := selectStmt.Query(addr[:])
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var aidx basics.CreatableIndex
+		var rtype basics.CreatableType
+		var buf []byte
+		err = rows.Scan(&aidx, &rtype, &buf)
+		if err != nil {
+			return err
+		}
+		switch rtype {
+		case basics.AssetCreatable:
+			var holding basics.AssetHolding
+			err = protocol.Decode(buf, &holding)
+			if err != nil {
+				return err
+			}
+			if data.Assets == nil {
+				data.Assets = make(map[basics.AssetIndex]basics.AssetHolding)
+			}
+			data.Assets[basics.AssetIndex(aidx)] = holding
+		case basics.AppCreatable:
+			var localState basics.AppLocalState
+			err = protocol.Decode(buf, &localState)
+			if err != nil {
+				return err
+			}
+			if data.AppLocalStates == nil {
+				data.AppLocalStates = make(map[basics.AppIndex]basics.AppLocalState)
+			}
+			data.AppLocalStates[basics.AppIndex(aidx)] = localState
+		default:
+			return fmt.Errorf("unknown resource type %d for creatable %d of account %v", rtype, aidx, addr)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+var blockFileName string
+var expectedLabel string
+
+func init() {
+	verifyCmd.Flags().StringVarP(&tarFile, "tar", "t", "", "Specify the catchpoint file to verify")
+	verifyCmd.Flags().StringVarP(&blockFileName, "block", "b", "", "Specify the file holding the block of the catchpoint round, as returned by /v2/blocks/{round} in either msgpack or json format")
+	verifyCmd.Flags().StringVarP(&expectedLabel, "catchpoint", "c", "", "Specify the catchpoint label the file is expected to match ( optional )")
+	verifyCmd.Flags().StringVarP(&outFileName, "output", "o", "", "Specify an outfile for the verification report")
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a catchpoint file against the header of its block",
+	Long:  "Verify a catchpoint file by rebuilding its accounts merkle trie and recomputing its totals and label, and report the values and the accounts that don't match the header of the block the catchpoint was generated for",
+	Args:  validateNoPosArgsFn,
+	Run: func(cmd *cobra.Command, args []string) {
+		if tarFile == "" || blockFileName == "" {
+			cmd.HelpFunc()(cmd, args)
+			return
+		}
+		hdr, err := loadBlockHeader(blockFileName)
+		if err != nil {
+			reportErrorf("Unable to load block header from '%s' : %v", blockFileName, err)
+		}
+		valid, err := verifyCatchpointFile(tarFile, hdr)
+		if err != nil {
+			reportErrorf("Unable to verify catchpoint file : %v", err)
+		}
+		if !valid {
+			reportErrorf("Catchpoint file '%s' failed the verification", tarFile)
+		}
+	},
+}
+
+// verifyCatchpointFile verifies the given catchpoint file against hdr, and prints the verification report
+func verifyCatchpointFile(catchpointFileName string, hdr bookkeeping.BlockHeader) (valid bool, err error) {
+	catchpointFile, err := os.Open(catchpointFileName)
+	if err != nil {
+		return false, err
+	}
+	defer catchpointFile.Close()
+
+	// rebuild the trie in a temporary database, so that we don't touch any existing ledger files.
+	tempDir, err := ioutil.TempDir("", "catchpointdump")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(tempDir)
+	l, err := ledger.OpenLedger(logging.Base(), filepath.Join(tempDir, "ledger"), false, ledger.InitState{}, config.GetDefaultLocal())
+	if err != nil {
+		return false, fmt.Errorf("unable to open ledger : %v", err)
+	}
+	defer l.Close()
+
+	reportInfof("Verifying catchpoint file %s against block %d", catchpointFileName, hdr.Round)
+	report, err := ledger.VerifyCatchpointFile(context.Background(), l, catchpointFile, hdr)
+	if err != nil {
+		return false, err
+	}
+	if expectedLabel != "" && expectedLabel != report.Label {
+		report.Mismatches = append(report.Mismatches, fmt.Sprintf("catchpoint label: expected %s, calculated %s", expectedLabel, report.Label))
+	}
+
+	outFile := os.Stdout
+	if outFileName != "" {
+		outFile, err = os.OpenFile(outFileName, os.O_RDWR|os.O_TRUNC|os.O_CREATE, 0755)
+		if err != nil {
+			return false, err
+		}
+		defer outFile.Close()
+	}
+	printVerificationReport(outFile, report)
+	return report.Valid(), nil
+}
+
+// loadBlockHeader reads the header of a block from the given file, which can be either the msgpack or the json
+// response of the /v2/blocks/{round} endpoint.
+func loadBlockHeader(filename string) (hdr bookkeeping.BlockHeader, err error) {
+	buf, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	var response struct {
+		Block bookkeeping.Block `codec:"block"`
+	}
+	err = protocol.DecodeReflect(buf, &response)
+	if err != nil {
+		err = protocol.DecodeJSON(buf, &response)
+		if err != nil {
+			return
+		}
+	}
+	if response.Block.CurrentProtocol == "" {
+		return hdr, fmt.Errorf("no block found")
+	}
+	return response.Block.BlockHeader, nil
+}
+
+func printVerificationReport(out io.Writer, report ledger.CatchpointVerificationReport) {
+	fileHeader := report.FileHeader
+	fmt.Fprintf(out, "Blocks Round: %d\nBalances Round: %d\nCatchpoint: %s\nCalculated Catchpoint: %s\nBalances Merkle Root: %v\n",
+		fileHeader.BlocksRound,
+		fileHeader.BalancesRound,
+		fileHeader.Catchpoint,
+		report.Label,
+		report.BalancesRoot)
+	fmt.Fprintf(out, "Accounts: %d\nResources: %d\nBoxes: %d\n", report.Accounts, report.Resources, report.Boxes)
+
+	for _, mismatch := range report.Mismatches {
+		fmt.Fprintf(out, "Mismatch - %s\n", mismatch)
+	}
+	for _, acct := range report.OffendingAccounts {
+		fmt.Fprintf(out, "Offending account - %v : %s\n", acct.Address, acct.Reason)
+	}
+	if report.Valid() {
+		fmt.Fprintf(out, "Catchpoint file verified successfully\n")
+	}
+}
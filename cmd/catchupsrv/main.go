@@ -38,7 +38,7 @@ import (
 
 var addrFlag = flag.String("addr", "127.0.0.1:4160", "Address to listen on")
 var dirFlag = flag.String("dir", "", "Directory containing catchup blocks")
-var tarDirFlag = flag.String("tardir", "", "Directory containing catchup blocks in M_N.tar.bz2")
+var tarDirFlag = flag.String("tardir", "", "Directory containing catchup blocks in M_N.tar.bz2 or M_N.tar.gz")
 
 func main() {
 	flag.Parse()
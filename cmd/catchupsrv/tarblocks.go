@@ -19,6 +19,7 @@ package main
 import (
 	"archive/tar"
 	"compress/bzip2"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
@@ -53,6 +54,12 @@ func openTarBlockDir(path string) (tars *tarBlockSet, err error) {
 	if err != nil {
 		return nil, err
 	}
+	// the blocks pruned by a node with a BlockArchiveDir are exported into gzip compressed tarfiles
+	gzMatches, err := filepath.Glob(filepath.Join(path, "*_*.tar.gz"))
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, gzMatches...)
 	out.entries = make([]*tarBlockFile, 0, len(matches))
 	for _, path := range matches {
 		tbf := parseTarPathname(path)
@@ -96,6 +103,7 @@ type tarBlockFile struct {
 	// fields valid when tarfile is open
 	rawFile      io.ReadCloser
 	bz2Stream    io.Reader
+	gzStream     *gzip.Reader
 	tarfile      *tar.Reader
 	current      *tar.Header
 	currentRound uint64
@@ -145,6 +153,15 @@ func (tbf *tarBlockFile) _open() (err error) {
 	if strings.HasSuffix(tbf.path, ".bz2") {
 		tbf.bz2Stream = bzip2.NewReader(tbf.rawFile)
 		tbf.tarfile = tar.NewReader(tbf.bz2Stream)
+	} else if strings.HasSuffix(tbf.path, ".gz") {
+		tbf.gzStream, err = gzip.NewReader(tbf.rawFile)
+		if err != nil {
+			err = fmt.Errorf("%s: gzip.NewReader %v", tbf.path, err)
+			tbf.rawFile.Close()
+			tbf.rawFile = nil
+			return
+		}
+		tbf.tarfile = tar.NewReader(tbf.gzStream)
 	} else {
 		tbf.tarfile = tar.NewReader(tbf.rawFile)
 	}
@@ -164,6 +181,7 @@ func (tbf *tarBlockFile) _close() (err error) {
 		logging.Base().Infof("close %p %s, %v", tbf, tbf.path, err)
 		tbf.rawFile = nil
 		tbf.bz2Stream = nil
+		tbf.gzStream = nil
 		tbf.tarfile = nil
 		tbf.current = nil
 		tbf.blocks = nil
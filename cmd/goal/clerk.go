@@ -18,6 +18,7 @@ package main
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -50,6 +51,8 @@ var (
 	closeToAddress  string
 	noProgramOutput bool
 	signProgram     bool
+	writeSourceMap  bool
+	analyzeProgram  bool
 	programSource   string
 	argB64Strings   []string
 	disassemble     bool
@@ -62,6 +65,13 @@ var (
 	rekeyToAddress  string
 	signerAddress   string
 	rawOutput       bool
+	lintMode        string
+	lintDisable     []string
+	lintMaxFee      uint64
+	dryrunTxnFile   string
+	captureDryrun   bool
+	captureRound    uint64
+	traceFilename   string
 )
 
 func init() {
@@ -72,6 +82,7 @@ func init() {
 	clerkCmd.AddCommand(groupCmd)
 	clerkCmd.AddCommand(splitCmd)
 	clerkCmd.AddCommand(compileCmd)
+	clerkCmd.AddCommand(lintCmd)
 	clerkCmd.AddCommand(dryrunCmd)
 	clerkCmd.AddCommand(dryrunRemoteCmd)
 
@@ -124,20 +135,31 @@ func init() {
 	compileCmd.Flags().BoolVarP(&disassemble, "disassemble", "D", false, "disassemble a compiled program")
 	compileCmd.Flags().BoolVarP(&noProgramOutput, "no-out", "n", false, "don't write contract program binary")
 	compileCmd.Flags().BoolVarP(&signProgram, "sign", "s", false, "sign program, output is a binary signed LogicSig record")
+	compileCmd.Flags().BoolVarP(&writeSourceMap, "map", "m", false, "write the source map of the program to the output filename with a .map extension")
+	compileCmd.Flags().BoolVar(&analyzeProgram, "analyze", false, "report the worst case opcode cost and stack depth of the program and each of its subroutines")
 	compileCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename to write program bytes or signed LogicSig to")
 	compileCmd.Flags().StringVarP(&account, "account", "a", "", "Account address to sign the program (If not specified, uses default account)")
 
+	lintCmd.Flags().StringVar(&lintMode, "mode", "", "lint the program as a \"signature\" or an \"application\" (default decides by the opcodes used)")
+	lintCmd.Flags().StringSliceVar(&lintDisable, "disable", nil, "rules not to check: "+lintRuleNames())
+	lintCmd.Flags().Uint64Var(&lintMaxFee, "max-fee", 0, "warn if a logic signature allows a Fee larger than this, in microAlgos")
+
 	dryrunCmd.Flags().StringVarP(&txFilename, "txfile", "t", "", "transaction or transaction-group to test")
 	dryrunCmd.Flags().StringVarP(&protoVersion, "proto", "P", "", "consensus protocol version id string")
 	dryrunCmd.Flags().BoolVar(&dumpForDryrun, "dryrun-dump", false, "Dump in dryrun format acceptable by dryrun REST api instead of running")
 	dryrunCmd.Flags().Var(&dumpForDryrunFormat, "dryrun-dump-format", "Dryrun dump format: "+dumpForDryrunFormat.AllowedString())
 	dryrunCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename for writing dryrun state object")
+	dryrunCmd.Flags().StringVar(&traceFilename, "trace", "", "Filename for writing a JSON lines trace of every program run")
 	dryrunCmd.MarkFlagRequired("txfile")
 
 	dryrunRemoteCmd.Flags().StringVarP(&txFilename, "dryrun-state", "D", "", "dryrun request object to run")
+	dryrunRemoteCmd.Flags().StringVarP(&dryrunTxnFile, "txfile", "t", "", "transaction or transaction-group to run against the ledger state algod captures for it, instead of a dryrun request object")
+	dryrunRemoteCmd.Flags().BoolVar(&captureDryrun, "capture", false, "save the dryrun request captured for --txfile to --outfile instead of running it")
+	dryrunRemoteCmd.Flags().Uint64Var(&captureRound, "round", 0, "capture the ledger state of this round, running the group as if in the next round (default latest)")
+	dryrunRemoteCmd.Flags().StringVarP(&outFilename, "outfile", "o", "", "Filename for writing the captured dryrun request")
 	dryrunRemoteCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print more info")
 	dryrunRemoteCmd.Flags().BoolVarP(&rawOutput, "raw", "r", false, "output raw response from algod")
-	dryrunRemoteCmd.MarkFlagRequired("dryrun-state")
+	dryrunRemoteCmd.Flags().StringVar(&traceFilename, "trace", "", "Filename for writing a JSON lines trace of every program run")
 
 }
 
@@ -648,7 +670,10 @@ var inspectCmd = &cobra.Command{
 			dec := protocol.NewDecoderBytes(data)
 			count := 0
 			for {
-				var txn transactions.SignedTxn
+				// A SignedTxnWithAD decodes plain signed transactions
+				// too, and lets us show the logs of application calls
+				// that carry their apply data.
+				var txn transactions.SignedTxnWithAD
 				err = dec.Decode(&txn)
 				if err == io.EOF {
 					break
@@ -656,11 +681,18 @@ var inspectCmd = &cobra.Command{
 				if err != nil {
 					reportErrorf(txDecodeError, txFilename, err)
 				}
-				sti, err := inspectTxn(txn)
+				sti, err := inspectTxn(txn.SignedTxn)
 				if err != nil {
 					reportErrorf(txDecodeError, txFilename, err)
 				}
 				fmt.Printf("%s[%d]\n%s\n\n", txFilename, count, string(protocol.EncodeJSON(sti)))
+				if logs := txn.ApplyData.EvalDelta.Logs; len(logs) > 0 {
+					fmt.Printf("%s[%d] logs:\n", txFilename, count)
+					for _, l := range logs {
+						fmt.Printf("%s\n", heuristicFormatStr(l))
+					}
+					fmt.Printf("\n")
+				}
 				count++
 			}
 		}
@@ -921,11 +953,18 @@ func mustReadFile(fname string) []byte {
 }
 
 func assembleFile(fname string) (program []byte) {
+	ops, _ := assembleFileWithSource(fname)
+	return ops.Program
+}
+
+// assembleFileWithSource assembles fname, reporting any error, and returns
+// the assembled program along with its source text
+func assembleFileWithSource(fname string) (*logic.OpStream, string) {
 	text, err := readFile(fname)
 	if err != nil {
 		reportErrorf("%s: %s", fname, err)
 	}
-	ops, err := logic.AssembleString(string(text))
+	ops, err := logic.AssembleStringWithIncludes(fname, string(text), logic.ReadIncludeFile)
 	if err != nil {
 		ops.ReportProblems(fname)
 		reportErrorf("%s: %s", fname, err)
@@ -941,7 +980,46 @@ func assembleFile(fname string) (program []byte) {
 		}
 	}
 
-	return ops.Program
+	return ops, string(text)
+}
+
+// printCostAnalysis reports the worst case cost and stack depth of each
+// entry point of an assembled program, and whether the program's budget
+// can be exceeded
+func printCostAnalysis(fname string, ops *logic.OpStream) {
+	costs, err := logic.AnalyzeCost(ops.Program)
+	if err != nil {
+		reportErrorf("%s: %s", fname, err)
+	}
+	_, params := getProto(protoVersion)
+	budget := int(params.LogicSigMaxCost)
+	if ops.HasStatefulOps {
+		budget = params.MaxAppProgramCost
+	}
+	position := func(pc int) string {
+		source, line := ops.SourcePosition(pc)
+		if source == "" {
+			source = fname
+		}
+		return fmt.Sprintf("%s:%d", source, line+1)
+	}
+	for _, entry := range costs {
+		where := fname
+		if entry.Subroutine {
+			where = position(entry.PC) + ": subroutine"
+		}
+		cost := fmt.Sprintf("cost %d", entry.Cost)
+		if entry.CostUnbounded {
+			cost = "cost unbounded, loop at " + position(entry.LoopPC)
+		} else if !entry.Subroutine && entry.Cost > budget {
+			cost += fmt.Sprintf(" exceeds budget of %d", budget)
+		}
+		stack := fmt.Sprintf("max stack depth %d", entry.MaxStack)
+		if entry.StackUnbounded {
+			stack = "stack depth unbounded"
+		}
+		fmt.Printf("%s: %s, %s\n", where, cost, stack)
+	}
 }
 
 func disassembleFile(fname, outname string) {
@@ -990,7 +1068,8 @@ var compileCmd = &cobra.Command{
 				disassembleFile(fname, outFilename)
 				continue
 			}
-			program := assembleFile(fname)
+			ops, source := assembleFileWithSource(fname)
+			program := ops.Program
 			outblob := program
 			outname := outFilename
 			if outname == "" {
@@ -1000,6 +1079,24 @@ var compileCmd = &cobra.Command{
 					outname = fmt.Sprintf("%s.tok", fname)
 				}
 			}
+			if writeSourceMap {
+				if outname == stdoutFilenameValue {
+					reportErrorf(tealMapStdout, fname)
+				}
+				mapname := outname + ".map"
+				sm := ops.SourceMap(filepath.Base(fname), source)
+				data, err := json.Marshal(sm)
+				if err != nil {
+					reportErrorf("%s: %s", mapname, err)
+				}
+				err = writeFile(mapname, data, 0666)
+				if err != nil {
+					reportErrorf("%s: %s", mapname, err)
+				}
+			}
+			if analyzeProgram {
+				printCostAnalysis(fname, ops)
+			}
 			if signProgram {
 				dataDir := ensureSingleDataDir()
 				accountList := makeAccountsList(dataDir)
@@ -1038,6 +1135,45 @@ var compileCmd = &cobra.Command{
 	},
 }
 
+func lintRuleNames() string {
+	names := make([]string, len(logic.LintRules))
+	for i, rule := range logic.LintRules {
+		names[i] = string(rule)
+	}
+	return strings.Join(names, ", ")
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [input file 1] [input file 2]...",
+	Short: "Check a contract program for common mistakes",
+	Long:  "Compiles TEAL contract programs and reports paths that approve a transaction without checking fields such as RekeyTo, CloseRemainderTo and Fee, along with unreachable code.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := logic.LintConfig{Mode: lintMode, MaxFee: lintMaxFee}
+		for _, rule := range lintDisable {
+			config.Disable = append(config.Disable, logic.LintRule(rule))
+		}
+		found := 0
+		for _, fname := range args {
+			ops, _ := assembleFileWithSource(fname)
+			warnings, err := logic.Lint(ops.Program, config)
+			if err != nil {
+				reportErrorf("%s: %s", fname, err)
+			}
+			for _, w := range warnings {
+				source, line := ops.SourcePosition(w.PC)
+				if source == "" {
+					source = fname
+				}
+				fmt.Printf("%s:%d: [%s] %s\n", source, line+1, w.Rule, w.Message)
+			}
+			found += len(warnings)
+		}
+		if found > 0 {
+			reportErrorf(tealLintWarnings, found)
+		}
+	},
+}
+
 var dryrunCmd = &cobra.Command{
 	Use:   "dryrun",
 	Short: "Test a program offline",
@@ -1080,6 +1216,16 @@ var dryrunCmd = &cobra.Command{
 		if timeStamp <= 0 {
 			timeStamp = time.Now().Unix()
 		}
+		var trace *logic.TraceWriter
+		if traceFilename != "" {
+			f, err := os.Create(traceFilename)
+			if err != nil {
+				reportErrorf(fileWriteError, traceFilename, err)
+			}
+			defer f.Close()
+			trace = logic.MakeTraceWriter(f)
+		}
+		pastSideEffects := logic.MakePastSideEffects(len(txgroup))
 		for i, txn := range txgroup {
 			if txn.Lsig.Blank() {
 				continue
@@ -1094,11 +1240,15 @@ var dryrunCmd = &cobra.Command{
 			}
 			sb := strings.Builder{}
 			ep = logic.EvalParams{
-				Txn:        &txn,
-				GroupIndex: i,
-				Proto:      &params,
-				Trace:      &sb,
-				TxnGroup:   txgroup,
+				Txn:             &txn,
+				GroupIndex:      i,
+				Proto:           &params,
+				Trace:           &sb,
+				TxnGroup:        txgroup,
+				PastSideEffects: pastSideEffects,
+			}
+			if trace != nil {
+				ep.Debugger = trace
 			}
 			pass, err := logic.Eval(txn.Lsig.Logic, ep)
 			// TODO: optionally include `inspect` output here?
@@ -1119,19 +1269,67 @@ var dryrunCmd = &cobra.Command{
 var dryrunRemoteCmd = &cobra.Command{
 	Use:   "dryrun-remote",
 	Short: "Test a program with algod's dryrun REST endpoint",
-	Long:  "Test a TEAL program with algod's dryrun REST endpoint under various conditions and verbosity.",
+	Long:  "Test a TEAL program with algod's dryrun REST endpoint under various conditions and verbosity. Either run a dryrun request object, or have algod capture the ledger state a transaction group needs into one, which can be saved with --capture and replayed later with tealdbg.",
 	Run: func(cmd *cobra.Command, args []string) {
-		data, err := readFile(txFilename)
-		if err != nil {
-			reportErrorf(fileReadError, txFilename, err)
+		if (txFilename == "") == (dryrunTxnFile == "") {
+			reportErrorf("dryrun-remote: exactly one of --dryrun-state or --txfile must be given")
+		}
+		if captureDryrun && (dryrunTxnFile == "" || outFilename == "") {
+			reportErrorf("dryrun-remote: --capture needs --txfile and --outfile")
 		}
 
 		dataDir := ensureSingleDataDir()
 		client := ensureFullClient(dataDir)
-		resp, err := client.Dryrun(data)
+
+		var data []byte
+		var err error
+		if dryrunTxnFile != "" {
+			txgroup, err := readFile(dryrunTxnFile)
+			if err != nil {
+				reportErrorf(fileReadError, dryrunTxnFile, err)
+			}
+			data, err = client.DryrunSnapshot(txgroup, captureRound)
+			if err != nil {
+				reportErrorf("dryrun-remote: %s", err.Error())
+			}
+			if captureDryrun {
+				err = writeFile(outFilename, data, 0600)
+				if err != nil {
+					reportErrorf(fileWriteError, outFilename, err)
+				}
+				return
+			}
+		} else {
+			data, err = readFile(txFilename)
+			if err != nil {
+				reportErrorf(fileReadError, txFilename, err)
+			}
+		}
+
+		var resp generatedV2.DryrunResponse
+		if traceFilename != "" {
+			resp, err = client.DryrunWithTrace(data)
+		} else {
+			resp, err = client.Dryrun(data)
+		}
 		if err != nil {
 			reportErrorf("dryrun-remote: %s", err.Error())
 		}
+		if traceFilename != "" {
+			var traces []byte
+			for _, txnResult := range resp.Txns {
+				if txnResult.LogicSigJsonTrace != nil {
+					traces = append(traces, *txnResult.LogicSigJsonTrace...)
+				}
+				if txnResult.AppCallJsonTrace != nil {
+					traces = append(traces, *txnResult.AppCallJsonTrace...)
+				}
+			}
+			err = writeFile(traceFilename, traces, 0600)
+			if err != nil {
+				reportErrorf(fileWriteError, traceFilename, err)
+			}
+		}
 		if rawOutput {
 			fmt.Fprintf(os.Stdout, string(protocol.EncodeJSON(&resp)))
 			return
@@ -1167,6 +1365,12 @@ var dryrunRemoteCmd = &cobra.Command{
 				for _, msg := range msgs {
 					fmt.Fprintf(os.Stdout, "%s\n", msg)
 				}
+				if txnResult.Logs != nil && len(*txnResult.Logs) > 0 {
+					fmt.Fprintf(os.Stdout, "tx[%d] logs:\n", i)
+					for _, l := range *txnResult.Logs {
+						fmt.Fprintf(os.Stdout, "%s\n", heuristicFormatStr(string(l)))
+					}
+				}
 				if verbose && len(trace) > 0 {
 					fmt.Fprintf(os.Stdout, "tx[%d] trace:\n", i)
 					for _, item := range trace {
@@ -66,6 +66,7 @@ const (
 	catchupStoppedOnUnsupported       = "Last supported block (%d) is committed. The next block consensus protocol is not supported. Catchup service is stopped."
 	infoNodeCatchpointCatchupStatus   = "Last committed block: %d\nSync Time: %s\nCatchpoint: %s"
 	infoNodeCatchpointCatchupAccounts = "Catchpoint total accounts: %d\nCatchpoint accounts processed: %d\nCatchpoint accounts verified: %d"
+	infoNodeCatchpointCatchupChunks   = "Catchpoint file total chunks: %d\nCatchpoint file chunks downloaded: %d"
 	infoNodeCatchpointCatchupBlocks   = "Catchpoint total blocks: %d\nCatchpoint downloaded blocks: %d"
 	nodeLastCatchpoint                = "Last Catchpoint: %s"
 	errorNodeCreationIPFailure        = "Parsing passed IP %v failed: need a valid IPv4 or IPv6 address with a specified port number"
@@ -160,6 +161,8 @@ const (
 
 	tealLogicSigSize = "%s: logicsig program size too large: %d > %d"
 	tealAppSize      = "%s: app program size too large: %d > %d"
+	tealMapStdout    = "%s: --map needs an output file, use --outfile"
+	tealLintWarnings = "%d lint warnings"
 
 	// Wallet
 	infoRecoveryPrompt           = "Please type your recovery mnemonic below, and hit return when you are done: "
@@ -422,6 +422,10 @@ func makeStatusString(stat generatedV2.NodeStatusResponse) string {
 			catchupTime,
 			*stat.Catchpoint)
 
+		if stat.CatchpointTotalChunks != nil && (*stat.CatchpointTotalChunks > 0) && stat.CatchpointAcquiredChunks != nil {
+			statusString = statusString + "\n" + fmt.Sprintf(infoNodeCatchpointCatchupChunks, *stat.CatchpointTotalChunks,
+				*stat.CatchpointAcquiredChunks)
+		}
 		if stat.CatchpointTotalAccounts != nil && (*stat.CatchpointTotalAccounts > 0) && stat.CatchpointProcessedAccounts != nil {
 			statusString = statusString + "\n" + fmt.Sprintf(infoNodeCatchpointCatchupAccounts, *stat.CatchpointTotalAccounts,
 				*stat.CatchpointProcessedAccounts, *stat.CatchpointVerifiedAccounts)
@@ -105,6 +105,21 @@ func assetParamsFieldsMarkdown(out io.Writer) {
 	fieldTableMarkdown(out, logic.AssetParamsFieldNames, logic.AssetParamsFieldTypes, logic.AssetParamsFieldDocs)
 }
 
+func appParamsFieldsMarkdown(out io.Writer) {
+	fmt.Fprintf(out, "\n`app_params_get` Fields:\n\n")
+	fieldTableMarkdown(out, logic.AppParamsFieldNames, logic.AppParamsFieldTypes, logic.AppParamsFieldDocs)
+}
+
+func ecdsaCurvesMarkdown(out io.Writer) {
+	fmt.Fprintf(out, "\n`ECDSA` Curves:\n\n")
+	fmt.Fprintf(out, "| Index | Name | Notes |\n")
+	fmt.Fprintf(out, "| --- | --- | --- |\n")
+	for i, name := range logic.EcdsaCurveNames {
+		fmt.Fprintf(out, "| %d | %s | %s |\n", i, markdownTableEscape(name), logic.EcdsaCurveDocs[name])
+	}
+	out.Write([]byte("\n"))
+}
+
 func immediateMarkdown(op *logic.OpSpec) string {
 	markdown := ""
 	for _, imm := range op.Details.Immediates {
@@ -179,6 +194,10 @@ func opToMarkdown(out io.Writer, op *logic.OpSpec) (err error) {
 		assetHoldingFieldsMarkdown(out)
 	} else if op.Name == "asset_params_get" {
 		assetParamsFieldsMarkdown(out)
+	} else if op.Name == "app_params_get" {
+		appParamsFieldsMarkdown(out)
+	} else if strings.HasPrefix(op.Name, "ecdsa_") {
+		ecdsaCurvesMarkdown(out)
 	}
 	ode := logic.OpDocExtra(op.Name)
 	if ode != "" {
@@ -240,6 +259,12 @@ func argEnum(name string) []string {
 	if name == "asset_params_get" {
 		return logic.AssetParamsFieldNames
 	}
+	if name == "app_params_get" {
+		return logic.AppParamsFieldNames
+	}
+	if strings.HasPrefix(name, "ecdsa_") {
+		return logic.EcdsaCurveNames
+	}
 	return nil
 }
 
@@ -281,6 +306,9 @@ func argEnumTypes(name string) string {
 	if name == "asset_params_get" {
 		return typeString(logic.AssetParamsFieldTypes)
 	}
+	if name == "app_params_get" {
+		return typeString(logic.AppParamsFieldTypes)
+	}
 
 	return ""
 }
@@ -344,6 +372,10 @@ func main() {
 	fieldTableMarkdown(assetparams, logic.AssetParamsFieldNames, logic.AssetParamsFieldTypes, logic.AssetParamsFieldDocs)
 	assetparams.Close()
 
+	appparams, _ := os.Create("app_params_fields.md")
+	fieldTableMarkdown(appparams, logic.AppParamsFieldNames, logic.AppParamsFieldTypes, logic.AppParamsFieldDocs)
+	appparams.Close()
+
 	langspecjs, _ := os.Create("langspec.json")
 	enc := json.NewEncoder(langspecjs)
 	enc.Encode(buildLanguageSpec(opGroups))
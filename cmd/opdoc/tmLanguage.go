@@ -126,7 +126,9 @@ func buildSyntaxHighlight() *tmLanguage {
 	allNamedFields = append(allNamedFields, logic.GlobalFieldNames...)
 	allNamedFields = append(allNamedFields, logic.AssetHoldingFieldNames...)
 	allNamedFields = append(allNamedFields, logic.AssetParamsFieldNames...)
+	allNamedFields = append(allNamedFields, logic.AppParamsFieldNames...)
 	allNamedFields = append(allNamedFields, logic.OnCompletionNames...)
+	allNamedFields = append(allNamedFields, logic.EcdsaCurveNames...)
 
 	literals.Patterns = append(literals.Patterns, pattern{
 		Name:  "variable.parameter.teal",
@@ -167,7 +169,7 @@ func buildSyntaxHighlight() *tmLanguage {
 				Name:  "keyword.other.teal",
 				Match: fmt.Sprintf("^(%s)\\b", strings.Join(loading, "|")),
 			})
-		case "State Access":
+		case "State Access", "Inner Transactions", "Box Access":
 			keywords.Patterns = append(keywords.Patterns, pattern{
 				Name:  "keyword.other.unit.teal",
 				Match: fmt.Sprintf("^(%s)\\b", strings.Join(names, "|")),
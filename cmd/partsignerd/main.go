@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/daemon/partsigner"
+	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/db"
+	"github.com/algorand/go-algorand/util/tokens"
+)
+
+const protectionDBFilename = "partsigner.sqlite"
+
+var (
+	dataDir       string
+	listenAddress string
+)
+
+func init() {
+	partsignerdCmd.Flags().StringVarP(&dataDir, "data-dir", "d", "", "partsignerd data directory, holding the participation keys.")
+	partsignerdCmd.Flags().StringVarP(&listenAddress, "listen", "l", "127.0.0.1:7834", "Address to serve the nodes on.")
+	partsignerdCmd.MarkFlagRequired("data-dir")
+}
+
+var partsignerdCmd = &cobra.Command{
+	Use:   "partsignerd",
+	Short: "Participation signer daemon",
+	Long: `The participation signer daemon holds participation keys on behalf of
+algod nodes, which reach it through their ParticipationSignerAddress setting,
+and produces the VRF proofs and vote signatures they need to take part in
+agreement. It keeps a record of the votes it signs, and refuses to sign two
+different votes for the same round, period and step.
+
+The participation keys (*.partkey) are loaded from the data directory, where
+the partsigner.token API token is generated, to be copied to the data
+directory of the nodes. This is a blocking command.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := run(dataDir, listenAddress)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+func run(dataDir, listenAddress string) error {
+	log := logging.NewLogger()
+	log.SetLevel(logging.Info)
+
+	apiToken, _, err := tokens.ValidateOrGenerateAPIToken(dataDir, tokens.PartSignerTokenFilename)
+	if err != nil {
+		return fmt.Errorf("cannot get API token: %v", err)
+	}
+
+	keys, err := loadParticipationKeys(dataDir)
+	if err != nil {
+		return err
+	}
+	for _, part := range keys {
+		defer part.Close()
+		log.Infof("loaded participation keys of %s (%d-%d)", part.Address(), part.FirstValid, part.LastValid)
+	}
+
+	protectionDB, err := db.MakeAccessor(filepath.Join(dataDir, protectionDBFilename), false, false)
+	if err != nil {
+		return fmt.Errorf("cannot open %s: %v", protectionDBFilename, err)
+	}
+	defer protectionDB.Close()
+
+	handler, err := partsigner.MakeServer(log, apiToken, keys, protectionDB)
+	if err != nil {
+		return err
+	}
+	server := http.Server{Addr: listenAddress, Handler: handler}
+
+	kill := make(chan os.Signal, 1)
+	signal.Notify(kill, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-kill
+		server.Shutdown(context.Background())
+	}()
+
+	log.Infof("serving %d participation keys on %s", len(keys), listenAddress)
+	err = server.ListenAndServe()
+	if err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// loadParticipationKeys loads the participation keys found in dir.
+func loadParticipationKeys(dir string) (keys []account.PersistedParticipation, err error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read directory %s: %v", dir, err)
+	}
+
+	for _, info := range files {
+		if !config.IsPartKeyFilename(info.Name()) {
+			continue
+		}
+
+		handle, err := db.MakeErasableAccessor(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot open %s: %v", info.Name(), err)
+		}
+		part, err := account.RestoreParticipation(handle)
+		if err != nil {
+			handle.Close()
+			return nil, fmt.Errorf("cannot load participation keys from %s: %v", info.Name(), err)
+		}
+		keys = append(keys, part)
+	}
+	return keys, nil
+}
+
+func main() {
+	if err := partsignerdCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -169,6 +169,7 @@ func (s *cdtSession) websocketHandler(w http.ResponseWriter, r *http.Request) {
 		state.Update(cdtStateUpdate{
 			dbgState.Stack, dbgState.Scratch,
 			0, 0, "",
+			dbgState.Cost, dbgState.Budget,
 			s.debugger.GetStates(nil),
 		})
 
@@ -247,6 +248,7 @@ func (s *cdtSession) websocketHandler(w http.ResponseWriter, r *http.Request) {
 				state.Update(cdtStateUpdate{
 					dbgState.Stack, dbgState.Scratch,
 					dbgState.PC, dbgState.Line, dbgState.Error,
+					dbgState.Cost, dbgState.Budget,
 					appState,
 				})
 				dbgStateMu.Unlock()
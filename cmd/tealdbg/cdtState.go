@@ -48,6 +48,8 @@ type cdtState struct {
 	pc      atomicInt
 	line    atomicInt
 	err     atomicString
+	cost    atomicInt
+	budget  atomicInt
 	AppState
 
 	// debugger states
@@ -63,6 +65,8 @@ type cdtStateUpdate struct {
 	pc      int
 	line    int
 	err     string
+	cost    int
+	budget  int
 
 	AppState
 }
@@ -104,6 +108,8 @@ func (s *cdtState) Update(state cdtStateUpdate) {
 	s.pc.Store(state.pc)
 	s.line.Store(state.line)
 	s.err.Store(state.err)
+	s.cost.Store(state.cost)
+	s.budget.Store(state.budget)
 	s.stack = state.stack
 	s.scratch = state.scratch
 	s.AppState = state.AppState
@@ -364,7 +370,9 @@ func prepareTxn(txn *transactions.Transaction, groupIndex int) []fieldDesc {
 			field == int(logic.Accounts) ||
 			field == int(logic.ApplicationArgs) ||
 			field == int(logic.Assets) ||
-			field == int(logic.Applications) {
+			field == int(logic.Applications) ||
+			field == int(logic.NumLogs) ||
+			field == int(logic.LastLog) {
 			continue
 		}
 		var value string
@@ -672,8 +680,20 @@ func makeLocalScope(s *cdtState, preview bool) (desc []cdt.RuntimePropertyDescri
 		Value: strconv.Itoa(s.pc.Load()),
 		Type:  "number",
 	})
+	cost := makePrimitive(fieldDesc{
+		Name:  "cost",
+		Value: strconv.Itoa(s.cost.Load()),
+		Type:  "number",
+	})
+	budget := makePrimitive(fieldDesc{
+		Name:  "budget",
+		Value: strconv.Itoa(s.budget.Load()),
+		Type:  "number",
+	})
 	desc = []cdt.RuntimePropertyDescriptor{
 		pc,
+		cost,
+		budget,
 		txn,
 		gtxn,
 		stack,
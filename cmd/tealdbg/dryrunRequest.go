@@ -64,8 +64,12 @@ func convertAccounts(accounts []generatedV2.Account) (records []basics.BalanceRe
 	return
 }
 
+// balanceRecordsFromDdr merges DryrunRequest.Accounts and DryrunRequest.Apps
+// into balance records, in the order the addresses first appear so that
+// replaying the same request always sets up the same ledger
 func balanceRecordsFromDdr(ddr *v2.DryrunRequest) (records []basics.BalanceRecord, err error) {
 	accounts := make(map[basics.Address]basics.AccountData)
+	var order []basics.Address
 	for _, a := range ddr.Accounts {
 		var addr basics.Address
 		addr, err = basics.UnmarshalChecksumAddress(a.Address)
@@ -77,6 +81,9 @@ func balanceRecordsFromDdr(ddr *v2.DryrunRequest) (records []basics.BalanceRecor
 		if err != nil {
 			return
 		}
+		if _, ok := accounts[addr]; !ok {
+			order = append(order, addr)
+		}
 		accounts[addr] = ad
 	}
 	for _, a := range ddr.Apps {
@@ -92,7 +99,10 @@ func balanceRecordsFromDdr(ddr *v2.DryrunRequest) (records []basics.BalanceRecor
 			return
 		}
 		appIdx := basics.AppIndex(a.Id)
-		ad := accounts[addr]
+		ad, ok := accounts[addr]
+		if !ok {
+			order = append(order, addr)
+		}
 		if ad.AppParams == nil {
 			ad.AppParams = make(map[basics.AppIndex]basics.AppParams, 1)
 			ad.AppParams[appIdx] = params
@@ -108,8 +118,8 @@ func balanceRecordsFromDdr(ddr *v2.DryrunRequest) (records []basics.BalanceRecor
 		accounts[addr] = ad
 	}
 
-	for addr, ad := range accounts {
-		records = append(records, basics.BalanceRecord{Addr: addr, AccountData: ad})
+	for _, addr := range order {
+		records = append(records, basics.BalanceRecord{Addr: addr, AccountData: accounts[addr]})
 	}
 	return
 }
@@ -17,6 +17,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -211,6 +212,7 @@ type evaluation struct {
 	name            string
 	groupIndex      int
 	pastSideEffects []logic.EvalSideEffects
+	pooledBudget    *uint64
 	mode            modeType
 	aidx            basics.AppIndex
 	ba              apply.Balances
@@ -248,6 +250,32 @@ func MakeLocalRunner(debugger *Debugger) *LocalRunner {
 	return r
 }
 
+// sourceFromMap returns the source and the pc to source line mapping
+// recorded in an assembler source map
+func sourceFromMap(data []byte) (source string, offsetToLine map[int]int, err error) {
+	var sm logic.SourceMap
+	err = json.Unmarshal(data, &sm)
+	if err != nil {
+		return
+	}
+	if len(sm.SourcesContent) == 0 {
+		err = fmt.Errorf("source map has no source content")
+		return
+	}
+	locations, err := sm.Locations()
+	if err != nil {
+		return
+	}
+	offsetToLine = make(map[int]int, len(locations))
+	for pc, loc := range locations {
+		// only the program's own source is shown, not the files it includes
+		if loc.Source == 0 {
+			offsetToLine[pc] = loc.Line
+		}
+	}
+	return sm.SourcesContent[0], offsetToLine, nil
+}
+
 func determineEvalMode(program []byte, modeIn string) (mode modeType, err error) {
 	switch modeIn {
 	case "signature":
@@ -276,14 +304,15 @@ func determineEvalMode(program []byte, modeIn string) (mode modeType, err error)
 // - Sources from command line file names.
 // - Programs mentioned in transaction group txnGroup.
 // - if DryrunRequest present and no sources or transaction group set in command line then:
-//   1. DryrunRequest.Sources are expanded to DryrunRequest.Apps or DryrunRequest.Txns.
-//   2. DryrunRequest.Apps are expanded into DryrunRequest.Txns.
-//   3. txnGroup is set to DryrunRequest.Txns
+//  1. DryrunRequest.Sources are expanded to DryrunRequest.Apps or DryrunRequest.Txns.
+//  2. DryrunRequest.Apps are expanded into DryrunRequest.Txns.
+//  3. txnGroup is set to DryrunRequest.Txns
+//
 // Application search by id:
-//  - Balance records from CLI or DryrunRequest.Accounts
-//  - If no balance records set in CLI then DryrunRequest.Accounts and DryrunRequest.Apps are used.
-//    In this case Accounts data is used as a base for balance records creation,
-//    and Apps supply updates to AppParams field.
+//   - Balance records from CLI or DryrunRequest.Accounts
+//   - If no balance records set in CLI then DryrunRequest.Accounts and DryrunRequest.Apps are used.
+//     In this case Accounts data is used as a base for balance records creation,
+//     and Apps supply updates to AppParams field.
 func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
 	ddr, err := ddrFromParams(dp)
 	if err != nil {
@@ -332,12 +361,23 @@ func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
 		balances[record.Addr] = record.AccountData
 	}
 
-	if dp.Round == 0 && ddr.Round != 0 {
-		dp.Round = ddr.Round
+	// a captured dryrun request carries the round and timestamp it was
+	// captured for, so that global Round and global LatestTimestamp replay
+	// the same values unless overridden from the command line
+	if ddr.Round != 0 {
+		if dp.Round == 0 {
+			dp.Round = ddr.Round
+		} else if dp.Round != ddr.Round {
+			log.Printf("Using round %d instead of the dryrun request round %d", dp.Round, ddr.Round)
+		}
 	}
 
-	if dp.LatestTimestamp == 0 && ddr.LatestTimestamp != 0 {
-		dp.LatestTimestamp = int64(ddr.LatestTimestamp)
+	if ddr.LatestTimestamp != 0 {
+		if dp.LatestTimestamp == 0 {
+			dp.LatestTimestamp = ddr.LatestTimestamp
+		} else if dp.LatestTimestamp != ddr.LatestTimestamp {
+			log.Printf("Using latest timestamp %d instead of the dryrun request timestamp %d", dp.LatestTimestamp, ddr.LatestTimestamp)
+		}
 	}
 
 	if dp.PastSideEffects == nil {
@@ -386,9 +426,17 @@ func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
 					r.runs[i].offsetToLine = ops.OffsetToLine
 					r.runs[i].source = source
 				}
+			} else if i < len(dp.SourceMapBlobs) && len(dp.SourceMapBlobs[i]) > 0 && !dp.DisableSourceMap {
+				// compiled program with a source map from the assembler
+				r.runs[i].source, r.runs[i].offsetToLine, err = sourceFromMap(dp.SourceMapBlobs[i])
+				if err != nil {
+					return fmt.Errorf("invalid source map for %s: %w", dp.ProgramNames[i], err)
+				}
 			}
 			r.runs[i].groupIndex = dp.GroupIndex
 			r.runs[i].pastSideEffects = dp.PastSideEffects
+			// each program is debugged on its own, so each gets a fresh pool
+			r.runs[i].pooledBudget = logic.MakePooledBudget(&r.proto, r.txnGroup)
 			r.runs[i].name = dp.ProgramNames[i]
 
 			var mode modeType
@@ -426,6 +474,8 @@ func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
 
 	r.runs = nil
 	// otherwise, if no program(s) set, check transactions for TEAL programs
+	// and let their application calls share the group's pooled budget
+	pooledBudget := logic.MakePooledBudget(&r.proto, r.txnGroup)
 	for gi, stxn := range r.txnGroup {
 		// make a new ledger per possible execution since it requires a current group index
 		if len(stxn.Lsig.Logic) > 0 {
@@ -454,6 +504,7 @@ func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
 						program:         stxn.Txn.ApprovalProgram,
 						groupIndex:      gi,
 						pastSideEffects: dp.PastSideEffects,
+						pooledBudget:    pooledBudget,
 						mode:            modeStateful,
 						aidx:            appIdx,
 						ba:              b,
@@ -490,6 +541,7 @@ func (r *LocalRunner) Setup(dp *DebugParams) (err error) {
 								program:         program,
 								groupIndex:      gi,
 								pastSideEffects: dp.PastSideEffects,
+								pooledBudget:    pooledBudget,
 								mode:            modeStateful,
 								aidx:            appIdx,
 								ba:              b,
@@ -528,12 +580,14 @@ func (r *LocalRunner) RunAll() error {
 		r.debugger.SaveProgram(run.name, run.program, run.source, run.offsetToLine, run.states)
 
 		ep := logic.EvalParams{
-			Proto:           &r.proto,
-			Debugger:        r.debugger,
-			Txn:             &r.txnGroup[groupIndex],
-			TxnGroup:        r.txnGroup,
-			GroupIndex:      run.groupIndex,
-			PastSideEffects: run.pastSideEffects,
+			Proto:                   &r.proto,
+			Debugger:                r.debugger,
+			Txn:                     &r.txnGroup[groupIndex],
+			TxnGroup:                r.txnGroup,
+			GroupIndex:              run.groupIndex,
+			PastSideEffects:         run.pastSideEffects,
+			Specials:                &transactions.SpecialAddresses{},
+			PooledApplicationBudget: run.pooledBudget,
 		}
 
 		run.result.pass, run.result.err = run.eval(ep)
@@ -548,6 +602,31 @@ func (r *LocalRunner) RunAll() error {
 	return nil
 }
 
+// RunTrace runs all the programs without a debugger, writing a JSON lines
+// trace of each of them to w. Results are kept in the runs.
+func (r *LocalRunner) RunTrace(w io.Writer) error {
+	if len(r.runs) < 1 {
+		return fmt.Errorf("no program to trace")
+	}
+
+	trace := logic.MakeTraceWriter(w)
+	for i := range r.runs {
+		run := &r.runs[i]
+		ep := logic.EvalParams{
+			Proto:                   &r.proto,
+			Debugger:                trace,
+			Txn:                     &r.txnGroup[run.groupIndex],
+			TxnGroup:                r.txnGroup,
+			GroupIndex:              run.groupIndex,
+			PastSideEffects:         run.pastSideEffects,
+			Specials:                &transactions.SpecialAddresses{},
+			PooledApplicationBudget: run.pooledBudget,
+		}
+		run.result.pass, run.result.err = run.eval(ep)
+	}
+	return nil
+}
+
 // Run starts the first program in list
 func (r *LocalRunner) Run() (bool, error) {
 	if len(r.runs) < 1 {
@@ -557,11 +636,13 @@ func (r *LocalRunner) Run() (bool, error) {
 	run := r.runs[0]
 
 	ep := logic.EvalParams{
-		Proto:           &r.proto,
-		Txn:             &r.txnGroup[groupIndex],
-		TxnGroup:        r.txnGroup,
-		GroupIndex:      run.groupIndex,
-		PastSideEffects: run.pastSideEffects,
+		Proto:                   &r.proto,
+		Txn:                     &r.txnGroup[groupIndex],
+		TxnGroup:                r.txnGroup,
+		GroupIndex:              run.groupIndex,
+		PastSideEffects:         run.pastSideEffects,
+		Specials:                &transactions.SpecialAddresses{},
+		PooledApplicationBudget: run.pooledBudget,
 	}
 
 	// Workaround for Go's nil/empty interfaces nil check after nil assignment, i.e.
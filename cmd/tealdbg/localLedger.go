@@ -303,3 +303,7 @@ func (l *localLedger) GetCreatorForRound(rnd basics.Round, cidx basics.Creatable
 	}
 	return basics.Address{}, false, fmt.Errorf("unknown creatable type %d", ctype)
 }
+
+func (l *localLedger) LookupBox(rnd basics.Round, key string) (string, bool, error) {
+	return "", false, nil
+}
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	v2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2"
+	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/logic"
@@ -598,6 +599,49 @@ func TestDebugFromPrograms(t *testing.T) {
 	a.Empty(l.runs[1].aidx)
 }
 
+func TestDebugFromProgramWithSourceMap(t *testing.T) {
+	a := require.New(t)
+
+	source := `#pragma version 2
+int 1
+// comment
+int 1
+==
+`
+	ops, err := logic.AssembleString(source)
+	a.NoError(err)
+	sm, err := json.Marshal(ops.SourceMap("test.teal", source))
+	a.NoError(err)
+
+	l := LocalRunner{}
+	dp := DebugParams{
+		ProgramNames:   []string{"test"},
+		ProgramBlobs:   [][]byte{ops.Program},
+		SourceMapBlobs: [][]byte{sm},
+		TxnBlob:        []byte(txnSample),
+		RunMode:        "signature",
+	}
+
+	err = l.Setup(&dp)
+	a.NoError(err)
+	a.Equal(1, len(l.runs))
+	a.Equal(ops.Program, l.runs[0].program)
+	a.Equal(source, l.runs[0].source)
+	a.Equal(ops.OffsetToLine, l.runs[0].offsetToLine)
+
+	dp.DisableSourceMap = true
+	err = l.Setup(&dp)
+	a.NoError(err)
+	a.Empty(l.runs[0].source)
+	a.Nil(l.runs[0].offsetToLine)
+
+	dp.DisableSourceMap = false
+	dp.SourceMapBlobs = [][]byte{[]byte(`{"version":3,"sources":["test.teal"],"names":[],"mappings":"AAAA"}`)}
+	err = l.Setup(&dp)
+	a.Error(err)
+	a.Contains(err.Error(), "source map has no source content")
+}
+
 func TestRunMode(t *testing.T) {
 	a := require.New(t)
 
@@ -1053,3 +1097,134 @@ func TestLocalBalanceAdapterIndexer(t *testing.T) {
 	ba := l.runs[0].ba
 	checkBalanceAdapter(a, ba, sender, payTxn.Txn.Receiver, assetIdx, appIdx)
 }
+
+func TestDebugFromSnapshot(t *testing.T) {
+	a := require.New(t)
+
+	sender, err := basics.UnmarshalChecksumAddress("47YPQTIGQEO7T4Y4RWDYWEKV6RTR2UNBQXBABEEGM72ESWDQNCQ52OPASU")
+	a.NoError(err)
+	creator, err := basics.UnmarshalChecksumAddress("PNWOET7LLOWMBMLE4KOCELCX6X3D3Q4H2Q4QJASYIEOF7YIPPQBG3YQ5YI")
+	a.NoError(err)
+
+	ops, err := logic.AssembleString(`#pragma version 2
+global Round
+int 101
+==
+global LatestTimestamp
+int 5555
+==
+&&
+byte "k"
+app_global_get
+int 7
+==
+&&
+`)
+	a.NoError(err)
+
+	// a dryrun request as captured by algod
+	appIdx := basics.AppIndex(100)
+	params := basics.AppParams{
+		ApprovalProgram:   ops.Program,
+		ClearStateProgram: ops.Program,
+		GlobalState:       basics.TealKeyValue{"k": {Type: basics.TealUintType, Uint: 7}},
+		StateSchemas: basics.StateSchemas{
+			GlobalStateSchema: basics.StateSchema{NumUint: 1},
+		},
+	}
+	appTxn := transactions.SignedTxn{
+		Txn: transactions.Transaction{
+			Type:   protocol.ApplicationCallTx,
+			Header: transactions.Header{Sender: sender},
+			ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+				ApplicationID: appIdx,
+			},
+		},
+	}
+	var accounts []generated.Account
+	for _, addr := range []basics.Address{sender, appIdx.Address(), creator} {
+		ad := basics.AccountData{MicroAlgos: basics.MicroAlgos{Raw: 1000000}}
+		acct, err := v2.AccountDataToAccount(addr.String(), &ad, nil, 100, ad.MicroAlgos)
+		a.NoError(err)
+		accounts = append(accounts, acct)
+	}
+	gdr := generated.DryrunRequest{
+		ProtocolVersion: string(protocol.ConsensusCurrentVersion),
+		Round:           101,
+		LatestTimestamp: 5555,
+		Accounts:        accounts,
+		Apps:            []generated.Application{v2.AppParamsToApplication(creator.String(), appIdx, &params)},
+		Txns:            []json.RawMessage{protocol.EncodeJSON(&appTxn)},
+	}
+	ddrBlob := protocol.EncodeJSON(&gdr)
+
+	// balance records keep the order of the request
+	ddr, err := ddrFromParams(&DebugParams{DdrBlob: ddrBlob})
+	a.NoError(err)
+	for i := 0; i < 10; i++ {
+		records, err := balanceRecordsFromDdr(&ddr)
+		a.NoError(err)
+		a.Len(records, 3)
+		a.Equal(sender, records[0].Addr)
+		a.Equal(appIdx.Address(), records[1].Addr)
+		a.Equal(creator, records[2].Addr)
+		a.Contains(records[2].AppParams, appIdx)
+	}
+
+	// global Round and LatestTimestamp replay the captured values
+	dp := DebugParams{DdrBlob: ddrBlob}
+	local := MakeLocalRunner(nil)
+	err = local.Setup(&dp)
+	a.NoError(err)
+	a.Equal(uint64(101), dp.Round)
+	a.Equal(int64(5555), dp.LatestTimestamp)
+	pass, err := local.Run()
+	a.NoError(err)
+	a.True(pass)
+
+	// unless overridden from the command line
+	dp = DebugParams{DdrBlob: ddrBlob, Round: 222}
+	err = local.Setup(&dp)
+	a.NoError(err)
+	pass, err = local.Run()
+	a.NoError(err)
+	a.False(pass)
+}
+
+func TestLocalTrace(t *testing.T) {
+	a := require.New(t)
+
+	dp := DebugParams{
+		ProgramNames: []string{"pass", "reject"},
+		ProgramBlobs: [][]byte{[]byte("int 1"), []byte("int 0")},
+		Proto:        string(protocol.ConsensusCurrentVersion),
+		RunMode:      "signature",
+	}
+	local := MakeLocalRunner(nil)
+	err := local.Setup(&dp)
+	a.NoError(err)
+
+	var trace strings.Builder
+	err = local.RunTrace(&trace)
+	a.NoError(err)
+	a.Len(local.runs, 2)
+	a.True(local.runs[0].result.pass)
+	a.NoError(local.runs[0].result.err)
+	a.False(local.runs[1].result.pass)
+
+	var events []logic.TraceEvent
+	for _, line := range strings.Split(strings.TrimSpace(trace.String()), "\n") {
+		var event logic.TraceEvent
+		a.NoError(json.Unmarshal([]byte(line), &event), line)
+		events = append(events, event)
+	}
+	var names []string
+	for _, event := range events {
+		names = append(names, event.Event)
+	}
+	a.Equal([]string{
+		logic.TraceRegister, logic.TraceStep, logic.TraceStep, logic.TraceComplete,
+		logic.TraceRegister, logic.TraceStep, logic.TraceStep, logic.TraceComplete,
+	}, names)
+	a.Equal([]logic.TraceValue{{Type: "uint"}}, events[7].Stack)
+}
@@ -143,6 +143,8 @@ var verbose bool
 var painless bool
 var appID uint64
 var listenForDrReq bool
+var sourceMapFiles []string
+var traceFile string
 
 func init() {
 	rootCmd.PersistentFlags().VarP(&frontend, "frontend", "f", "Frontend to use: "+frontend.AllowedString())
@@ -168,6 +170,8 @@ func init() {
 	debugCmd.Flags().StringVarP(&indexerURL, "indexer-url", "i", "", "URL for indexer to fetch Balance records from to evaluate stateful TEAL")
 	debugCmd.Flags().StringVarP(&indexerToken, "indexer-token", "", "", "API token for indexer to fetch Balance records from to evaluate stateful TEAL")
 	debugCmd.Flags().BoolVarP(&listenForDrReq, "listen-dr-req", "q", false, "Listen for upcoming debugging dryrun request objects instead of taking program(s) from command line")
+	debugCmd.Flags().StringSliceVar(&sourceMapFiles, "map", nil, "Source map(s) from the assembler for the compiled program(s), in the same order as the program(s)")
+	debugCmd.Flags().StringVar(&traceFile, "trace", "", "Run without a frontend, writing a JSON lines trace of every step to the file")
 
 	rootCmd.AddCommand(debugCmd)
 	rootCmd.AddCommand(remoteCmd)
@@ -195,6 +199,10 @@ func debugLocal(args []string) {
 		log.Fatalln("Can not combine listening for Dryrun Requests and program(s), or transaction(s), or dryrun-req object")
 	}
 
+	if listenForDrReq && len(traceFile) != 0 {
+		log.Fatalln("Can not combine listening for Dryrun Requests and writing a trace")
+	}
+
 	if !listenForDrReq {
 		// program can be set either directly
 		// or with SignedTxn.Lsig.Logic,
@@ -235,6 +243,21 @@ func debugLocal(args []string) {
 		}
 	}
 
+	if len(sourceMapFiles) > len(args) {
+		log.Fatalln("Error: more source maps than programs")
+	}
+	var sourceMapBlobs [][]byte
+	if len(sourceMapFiles) > 0 {
+		sourceMapBlobs = make([][]byte, len(sourceMapFiles))
+		for i, file := range sourceMapFiles {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				log.Fatalf("Error source map reading %s: %s", file, err)
+			}
+			sourceMapBlobs[i] = data
+		}
+	}
+
 	var err error
 	var txnBlob []byte
 	if len(txnFile) > 0 {
@@ -263,6 +286,7 @@ func debugLocal(args []string) {
 	dp := DebugParams{
 		ProgramNames:     programNames,
 		ProgramBlobs:     programBlobs,
+		SourceMapBlobs:   sourceMapBlobs,
 		Proto:            proto,
 		TxnBlob:          txnBlob,
 		GroupIndex:       groupIndex,
@@ -279,6 +303,11 @@ func debugLocal(args []string) {
 		ListenForDrReq:   listenForDrReq,
 	}
 
+	if len(traceFile) != 0 {
+		traceLocal(&dp, traceFile)
+		return
+	}
+
 	ds := makeDebugServer(iface, port, &frontend, &dp)
 
 	err = ds.startDebug()
@@ -286,3 +315,34 @@ func debugLocal(args []string) {
 		log.Fatalf("Debug error: %s", err.Error())
 	}
 }
+
+// traceLocal runs the programs without a frontend, writing their trace to
+// file and printing how each of them ended
+func traceLocal(dp *DebugParams, file string) {
+	local := MakeLocalRunner(nil)
+	err := local.Setup(dp)
+	if err != nil {
+		log.Fatalf("Debug error: %s", err.Error())
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		log.Fatalf("Error trace writing %s: %s", file, err)
+	}
+	defer f.Close()
+
+	err = local.RunTrace(f)
+	if err != nil {
+		log.Fatalf("Debug error: %s", err.Error())
+	}
+	for _, run := range local.runs {
+		switch {
+		case run.result.err != nil:
+			fmt.Printf("%s: ERROR: %s\n", run.name, run.result.err.Error())
+		case run.result.pass:
+			fmt.Printf("%s: PASS\n", run.name)
+		default:
+			fmt.Printf("%s: REJECT\n", run.name)
+		}
+	}
+}
@@ -70,6 +70,7 @@ type spinoffMsg struct {
 type DebugParams struct {
 	ProgramNames     []string
 	ProgramBlobs     [][]byte
+	SourceMapBlobs   [][]byte
 	Proto            string
 	TxnBlob          []byte
 	GroupIndex       int
@@ -124,6 +124,11 @@ func (ccw *Worker) signBlock(hdr bookkeeping.BlockHeader) {
 	var sigs []sigFromAddr
 	var sigkeys []crypto.OneTimeSignatureVerifier
 	for _, key := range keys {
+		if key.Remote != nil {
+			// Remote signers only sign votes.
+			continue
+		}
+
 		if key.FirstValid <= sigKeyRound && sigKeyRound <= key.LastValid {
 			keyDilution := key.KeyDilution
 			if keyDilution == 0 {
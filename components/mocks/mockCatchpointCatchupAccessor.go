@@ -47,6 +47,21 @@ func (m *MockCatchpointCatchupAccessor) SetLabel(ctx context.Context, label stri
 	return nil
 }
 
+// GetDownloadChunks returns the download chunks of the catchpoint file being downloaded, or nil if there is no such download
+func (m *MockCatchpointCatchupAccessor) GetDownloadChunks(ctx context.Context) (chunks *ledger.CatchpointDownloadChunks, err error) {
+	return nil, nil
+}
+
+// SetDownloadChunks stores the download chunks of the catchpoint file being downloaded
+func (m *MockCatchpointCatchupAccessor) SetDownloadChunks(ctx context.Context, chunks *ledger.CatchpointDownloadChunks) (err error) {
+	return nil
+}
+
+// GetDownloadFilePath returns the path of the file the catchpoint file is being downloaded into
+func (m *MockCatchpointCatchupAccessor) GetDownloadFilePath() string {
+	return ""
+}
+
 // ResetStagingBalances resets the current staging balances, preparing for a new set of balances to be added
 func (m *MockCatchpointCatchupAccessor) ResetStagingBalances(ctx context.Context, newCatchup bool) (err error) {
 	return nil
@@ -63,7 +63,7 @@ type Local struct {
 	// Version tracks the current version of the defaults so we can migrate old -> new
 	// This is specifically important whenever we decide to change the default value
 	// for an existing parameter. This field tag must be updated any time we add a new version.
-	Version uint32 `version[0]:"0" version[1]:"1" version[2]:"2" version[3]:"3" version[4]:"4" version[5]:"5" version[6]:"6" version[7]:"7" version[8]:"8" version[9]:"9" version[10]:"10" version[11]:"11" version[12]:"12" version[13]:"13" version[14]:"14" version[15]:"15" version[16]:"16"`
+	Version uint32 `version[0]:"0" version[1]:"1" version[2]:"2" version[3]:"3" version[4]:"4" version[5]:"5" version[6]:"6" version[7]:"7" version[8]:"8" version[9]:"9" version[10]:"10" version[11]:"11" version[12]:"12" version[13]:"13" version[14]:"14" version[15]:"15" version[16]:"16" version[17]:"17"`
 
 	// environmental (may be overridden)
 	// When enabled, stores blocks indefinitally, otherwise, only the most recents blocks
@@ -418,6 +418,58 @@ type Local struct {
 	// features like catchpoint catchup would be rendered completly non-operational, and many of the node inner
 	// working would be completly dis-functional.
 	DisableNetworking bool `version[16]:"false"`
+
+	// EnableAccountHistory makes an archival node keep the state of every account, asset and application as of
+	// every round, starting from the round at which the history was enabled, so that their state at past rounds
+	// can be queried. It has no effect on non-archival nodes. Enabling it on an existing node builds the history
+	// from the current state of the accounts, which may take a while on large ledgers.
+	EnableAccountHistory bool `version[17]:"false"`
+
+	// ParticipationSignerAddress is the host:port of a participation signer daemon holding participation keys on
+	// behalf of this node. When set, the node also votes with the keys of the signer, which produces their VRF proofs
+	// and vote signatures, so that the keys don't have to be stored on this host. The API token of the signer is read
+	// from the partsigner.token file of the data directory.
+	ParticipationSignerAddress string `version[17]:""`
+
+	// BlockStorageBackend selects where the ledger keeps its blocks: "sqlite" for the SQLite blocks database, or
+	// "kv" for an embedded key-value store, which is kept in memory and appended to a log file on every write.
+	// The key-value store holds at most 2GB of blocks, past which the ledger fails to write new blocks, so it is
+	// meant for nodes which bound the blocks they keep with BlockRetentionPolicy, and the ledger refuses to open
+	// it on archival nodes. Switching the backend doesn't migrate the existing blocks, so it should only be changed
+	// on a new data directory.
+	BlockStorageBackend string `version[17]:"sqlite"`
+
+	// AccountStorageBackend selects where the ledger keeps the state of the accounts: "sqlite" for the SQLite tracker
+	// database, or "kv" for an embedded key-value store, which is kept in memory and appended to a log file on every
+	// write. The merkle trie of the accounts and the catchpoints are only maintained in the SQLite tracker database,
+	// so the ledger refuses to open the key-value store on nodes which track catchpoints, and the catchpoint catchup
+	// can't be used with it. Switching the backend doesn't migrate the existing accounts, so it should only be
+	// changed on a new data directory.
+	AccountStorageBackend string `version[17]:"sqlite"`
+
+	// CatchupLedgerDownloadPeers is the number of relays the catchpoint file is downloaded from in parallel during a
+	// catchpoint catchup. The file is downloaded in chunks, which are kept across restarts of the node, so that the
+	// download resumes where it stopped. Setting it to 0 downloads the whole file from a single relay.
+	CatchupLedgerDownloadPeers int `version[17]:"4"`
+
+	// BlockRetentionPolicy selects which blocks a non-archival node keeps, on top of the blocks the protocol
+	// requires it to keep: "" keeps only the blocks required by the protocol, "rounds" keeps the blocks of the last
+	// BlockRetentionPeriod rounds, "days" keeps the blocks of the last BlockRetentionPeriod days, and "all" keeps
+	// every block. Archival nodes always keep every block.
+	BlockRetentionPolicy string `version[17]:""`
+
+	// BlockRetentionPeriod is the number of rounds or days of blocks kept by the "rounds" and "days" retention policies.
+	BlockRetentionPeriod uint64 `version[17]:"0"`
+
+	// BlockArchiveDir, when set, is the directory the blocks pruned by a non-archival node are exported to. The blocks
+	// are written into gzip compressed tar files of up to 1000 rounds each, named <first>_<last>.tar.gz, which
+	// catchupsrv can serve and an archival node can import with algod -importblocks.
+	BlockArchiveDir string `version[17]:""`
+
+	// BlockVacuumRounds, when non-zero, has a non-archival node vacuum its blocks storage every time it pruned that
+	// many rounds of blocks, to give the space of the pruned blocks back to the file system. New blocks can't be
+	// written while the storage is being vacuumed, so this is disabled by default.
+	BlockVacuumRounds uint64 `version[17]:"0"`
 }
 
 // Filenames of config files within the configdir (e.g. ~/.algorand)
@@ -267,6 +267,31 @@ type ConsensusParams struct {
 	// maximum cost of application approval program or clear state program
 	MaxAppProgramCost int
 
+	// pool the MaxAppProgramCost of every application call in a group,
+	// so that a single program may spend budget left unused by the others
+	EnableAppCostPooling bool
+
+	// maximum number of inner transactions that a single application
+	// call may issue. zero means applications may not issue inner
+	// transactions at all
+	MaxInnerTransactions int
+
+	// maximum number of applications that may be on the call stack at
+	// once, counting the top-level application call. applications may
+	// only issue inner application calls when this is more than one
+	MaxAppCallDepth int
+
+	// maximum number of log calls that a single application call may
+	// make, and the maximum total size in bytes of the logged values
+	MaxLogCalls int
+	MaxLogSize  int
+
+	// maximum number of return data entries that a single program may
+	// leave for later transactions in its group, and the maximum total
+	// size in bytes of those entries
+	MaxReturnDataEntries int
+	MaxReturnDataSize    int
+
 	// maximum length of a key used in an application's global or local
 	// key/value store
 	MaxAppKeyLen int
@@ -314,6 +339,15 @@ type ConsensusParams struct {
 	// GlobalStateSchema (and therefore allowed in GlobalState)
 	MaxGlobalSchemaEntries uint64
 
+	// maximum size in bytes of a single application box. Box storage is
+	// disabled when zero
+	MaxBoxSize uint64
+
+	// flat MinBalance requirement for each box an application creates,
+	// and additional MinBalance requirement per byte of box name and value
+	BoxFlatMinBalance uint64
+	BoxByteMinBalance uint64
+
 	// maximum total minimum balance requirement for an account, used
 	// to limit the maximum size of a single balance record
 	MaximumMinimumBalance uint64
@@ -381,6 +415,11 @@ type ConsensusParams struct {
 	// 5. checking that in the case of going online the VoteFirst is less or equal to the LastValid+1.
 	// 6. checking that in the case of going online the VoteFirst is less or equal to the next network round.
 	EnableKeyregCoherencyCheck bool
+
+	// EnableResourceHashes hashes each asset holding and application local state of an account into the
+	// balances merkle trie as an entry of its own, rather than as part of the account data. This changes
+	// the catchpoint labels, and the catchpoint file version.
+	EnableResourceHashes bool
 }
 
 // PaysetCommitType enumerates possible ways for the block header to commit to
@@ -416,6 +455,22 @@ var MaxVoteThreshold int
 // an eval delta, used for decoding purposes.
 var MaxEvalDeltaAccounts int
 
+// MaxInnerTransactions is the largest number of inner transactions that may
+// appear in an eval delta, used for decoding purposes.
+var MaxInnerTransactions int
+
+// MaxLogCalls is the largest number of log entries that may appear in an
+// eval delta, used for decoding purposes.
+var MaxLogCalls int
+
+// MaxReturnDataEntries is the largest number of return data entries that may
+// appear in an eval delta, used for decoding purposes.
+var MaxReturnDataEntries int
+
+// MaxBoxSize is the largest size of an application box, used for decoding
+// purposes.
+var MaxBoxSize int
+
 // MaxStateDeltaKeys is the largest number of key/value pairs that may appear
 // in a StateDelta, used for decoding purposes.
 var MaxStateDeltaKeys int
@@ -470,6 +525,10 @@ func checkSetAllocBounds(p ConsensusParams) {
 	// executed TEAL instructions should be fine (order of ~1000)
 	checkSetMax(p.MaxAppProgramLen, &MaxStateDeltaKeys)
 	checkSetMax(p.MaxAppProgramLen, &MaxEvalDeltaAccounts)
+	checkSetMax(p.MaxInnerTransactions, &MaxInnerTransactions)
+	checkSetMax(p.MaxLogCalls, &MaxLogCalls)
+	checkSetMax(p.MaxReturnDataEntries, &MaxReturnDataEntries)
+	checkSetMax(int(p.MaxBoxSize), &MaxBoxSize)
 	checkSetMax(p.MaxAppProgramLen, &MaxAppProgramLen)
 	checkSetMax(int(p.LogicSigMaxSize), &MaxLogicSigMaxSize)
 	checkSetMax(p.MaxTxnNoteBytes, &MaxTxnNoteBytes)
@@ -984,6 +1043,32 @@ func initConsensusProtocols() {
 	vFuture.CompactCertWeightThreshold = (1 << 32) * 30 / 100
 	vFuture.CompactCertSecKQ = 128
 
+	// Enable TEAL 5 / AVM 0.5, which allows applications to issue inner transactions
+	vFuture.LogicSigVersion = 5
+	vFuture.MaxInnerTransactions = 16
+
+	// Allow applications to emit up to 32 log entries totalling 1KB per call
+	vFuture.MaxLogCalls = 32
+	vFuture.MaxLogSize = 1024
+
+	// Allow programs to leave up to 16 return data entries totalling 1KB for later transactions
+	vFuture.MaxReturnDataEntries = 16
+	vFuture.MaxReturnDataSize = 1024
+
+	// Enable application box storage
+	vFuture.MaxBoxSize = 32768
+	vFuture.BoxFlatMinBalance = 2500
+	vFuture.BoxByteMinBalance = 400
+
+	// Pool the opcode budget of the application calls in a group
+	vFuture.EnableAppCostPooling = true
+
+	// Allow applications to call other applications, up to 8 deep
+	vFuture.MaxAppCallDepth = 8
+
+	// Hash the asset holdings and application local states into the balances trie apart from the accounts
+	vFuture.EnableResourceHashes = true
+
 	Consensus[protocol.ConsensusFuture] = vFuture
 }
 
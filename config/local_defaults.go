@@ -20,13 +20,19 @@
 package config
 
 var defaultLocal = Local{
-	Version:                                 16,
+	Version:                                 17,
+	AccountStorageBackend:                   "sqlite",
 	AccountUpdatesStatsInterval:             5000000000,
 	AccountsRebuildSynchronousMode:          1,
 	AnnounceParticipationKey:                true,
 	Archival:                                false,
 	BaseLoggerDebugLevel:                    4,
+	BlockArchiveDir:                         "",
+	BlockRetentionPeriod:                    0,
+	BlockRetentionPolicy:                    "",
 	BlockServiceCustomFallbackEndpoints:     "",
+	BlockStorageBackend:                     "sqlite",
+	BlockVacuumRounds:                       0,
 	BroadcastConnectionsLimit:               -1,
 	CadaverSizeTarget:                       1073741824,
 	CatchpointFileHistoryLength:             365,
@@ -37,6 +43,7 @@ var defaultLocal = Local{
 	CatchupFailurePeerRefreshRate:           10,
 	CatchupGossipBlockFetchTimeoutSec:       4,
 	CatchupHTTPBlockFetchTimeoutSec:         4,
+	CatchupLedgerDownloadPeers:              4,
 	CatchupLedgerDownloadRetryAttempts:      50,
 	CatchupParallelBlocks:                   16,
 	ConnectionsRateLimitingCount:            60,
@@ -47,6 +54,7 @@ var defaultLocal = Local{
 	DisableLocalhostConnectionRateLimit:     true,
 	DisableNetworking:                       false,
 	DisableOutgoingConnectionThrottling:     false,
+	EnableAccountHistory:                    false,
 	EnableAccountUpdatesStats:               false,
 	EnableAgreementReporting:                false,
 	EnableAgreementTimeMetrics:              false,
@@ -89,6 +97,7 @@ var defaultLocal = Local{
 	OutgoingMessageFilterBucketCount:        3,
 	OutgoingMessageFilterBucketSize:         128,
 	ParticipationKeysRefreshInterval:        60000000000,
+	ParticipationSignerAddress:              "",
 	PeerConnectionsUpdateInterval:           3600,
 	PeerPingPeriodSeconds:                   0,
 	PriorityPeers:                           map[string]bool{},
@@ -59,9 +59,16 @@ const (
 
 // rawRequestPaths is a set of paths where the body should not be urlencoded
 var rawRequestPaths = map[string]bool{
-	"/v1/transactions": true,
-	"/v2/teal/dryrun":  true,
-	"/v2/teal/compile": true,
+	"/v1/transactions":         true,
+	"/v2/teal/dryrun":          true,
+	"/v2/teal/dryrun/snapshot": true,
+	"/v2/teal/compile":         true,
+}
+
+// rawRequestWithParams is a raw request body sent along with query parameters
+type rawRequestWithParams struct {
+	body   []byte
+	params interface{}
 }
 
 // unauthorizedRequestError is generated when we receive 401 error from the server. This error includes the inner error
@@ -152,6 +159,14 @@ func (client RestClient) submitForm(response interface{}, path string, request i
 
 	if request != nil {
 		if rawRequestPaths[path] {
+			if withParams, ok := request.(rawRequestWithParams); ok {
+				v, err := query.Values(withParams.params)
+				if err != nil {
+					return err
+				}
+				queryURL.RawQuery = v.Encode()
+				request = withParams.body
+			}
 			reqBytes, ok := request.([]byte)
 			if !ok {
 				return fmt.Errorf("couldn't decode raw request as bytes")
@@ -580,6 +595,33 @@ func (client RestClient) RawDryrun(data []byte) (response []byte, err error) {
 	return
 }
 
+type dryrunParams struct {
+	JSONTrace bool `url:"json-trace,omitempty"`
+}
+
+// RawDryrunWithTrace gets the raw DryrunResponse along with a JSON lines
+// trace of every program it ran
+func (client RestClient) RawDryrunWithTrace(data []byte) (response []byte, err error) {
+	var blob Blob
+	err = client.submitForm(&blob, "/v2/teal/dryrun", rawRequestWithParams{data, dryrunParams{JSONTrace: true}}, "POST", false /* encodeJSON */, false /* decodeJSON */)
+	response = blob
+	return
+}
+
+type dryrunSnapshotParams struct {
+	Round uint64 `url:"round,omitempty"`
+}
+
+// RawDryrunSnapshot gets a dryrun request holding the ledger state that a
+// msgpack encoded transaction group needs, read at round, or at the latest
+// round if round is 0
+func (client RestClient) RawDryrunSnapshot(txgroup []byte, round uint64) (response []byte, err error) {
+	var blob Blob
+	err = client.submitForm(&blob, "/v2/teal/dryrun/snapshot", rawRequestWithParams{txgroup, dryrunSnapshotParams{round}}, "POST", false /* encodeJSON */, false /* decodeJSON */)
+	response = blob
+	return
+}
+
 // Proof gets a Merkle proof for a transaction in a block.
 func (client RestClient) Proof(txid string, round uint64) (response generatedV2.ProofResponse, err error) {
 	txid = stripTransaction(txid)
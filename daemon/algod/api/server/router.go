@@ -74,6 +74,7 @@ import (
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated/private"
+	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/node"
 	"github.com/algorand/go-algorand/util/tokens"
@@ -140,10 +141,13 @@ func NewRouter(logger logging.Logger, node *node.AlgorandFullNode, shutdown <-ch
 	registerHandlers(e, apiV1Tag, routes.V1Routes, ctx, apiAuthenticator)
 
 	// Registering v2 routes
+	blockStream := v2.MakeBlockStream(v2.DefaultBlockStreamRetainedRounds)
+	node.Ledger().RegisterBlockListeners([]ledger.BlockListener{blockStream})
 	v2Handler := v2.Handlers{
-		Node:     node,
-		Log:      logger,
-		Shutdown: shutdown,
+		Node:        node,
+		Log:         logger,
+		Shutdown:    shutdown,
+		BlockStream: blockStream,
 	}
 	generated.RegisterHandlers(e, &v2Handler, apiAuthenticator)
 	private.RegisterHandlers(e, &v2Handler, adminAuthenticator)
@@ -126,6 +126,8 @@ func AccountDataToAccount(
 		AppsLocalState:              &appsLocalState,
 		AppsTotalSchema:             &totalAppSchema,
 		AppsTotalExtraPages:         numOrNil(totalExtraPages),
+		TotalBoxes:                  numOrNil(record.TotalBoxes),
+		TotalBoxBytes:               numOrNil(record.TotalBoxBytes),
 	}, nil
 }
 
@@ -341,6 +343,13 @@ func AccountToAccountData(a *generated.Account) (basics.AccountData, error) {
 		TotalExtraAppPages: totalExtraPages,
 	}
 
+	if a.TotalBoxes != nil {
+		ad.TotalBoxes = *a.TotalBoxes
+	}
+	if a.TotalBoxBytes != nil {
+		ad.TotalBoxBytes = *a.TotalBoxBytes
+	}
+
 	if a.AuthAddr != nil {
 		authAddr, err := basics.UnmarshalChecksumAddress(*a.AuthAddr)
 		if err != nil {
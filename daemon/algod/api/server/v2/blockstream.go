@@ -0,0 +1,277 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/algorand/go-codec/codec"
+	"github.com/algorand/go-deadlock"
+	"github.com/algorand/websocket"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// DefaultBlockStreamRetainedRounds is the number of recently committed rounds
+// a BlockStream keeps by default. It bounds both how far back a consumer may
+// resume from, and how far behind the latest round a consumer may fall
+// before it is disconnected.
+const DefaultBlockStreamRetainedRounds = 64
+
+// blockStreamWriteTimeout bounds the time a single message may take to be
+// written to a consumer before the consumer is considered stuck.
+const blockStreamWriteTimeout = 30 * time.Second
+
+// blockStreamReadLimit is the largest message a consumer may send us.
+// Consumers are not expected to send anything but control frames.
+const blockStreamReadLimit = 1024
+
+// BlockDelta is the message sent over a block stream: a committed block,
+// along with the changes the ledger computed while evaluating it. Parts of
+// the state delta that can be derived from the block itself, such as the
+// transaction leases, are omitted.
+type BlockDelta struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Block bookkeeping.Block `codec:"block"`
+
+	// Accounts holds the new state of every account modified in the block
+	Accounts []basics.BalanceRecord `codec:"accts"`
+
+	// Creatables lists the assets and applications created or deleted in the block
+	Creatables []CreatableDelta `codec:"ctbls"`
+
+	// Boxes lists the application boxes modified in the block
+	Boxes []BoxDelta `codec:"boxes"`
+}
+
+// CreatableDelta records the creation or deletion of an asset or application
+type CreatableDelta struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Index   basics.CreatableIndex `codec:"idx"`
+	Type    basics.CreatableType  `codec:"type"`
+	Created bool                  `codec:"created"`
+	Creator basics.Address        `codec:"creator"`
+}
+
+// BoxDelta records the new content of an application box, or its deletion
+type BoxDelta struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	App     basics.AppIndex `codec:"app"`
+	Name    []byte          `codec:"name"`
+	Value   []byte          `codec:"value"`
+	Deleted bool            `codec:"deleted"`
+}
+
+// makeBlockDelta flattens a StateDelta into its streamed representation
+func makeBlockDelta(block bookkeeping.Block, delta ledgercore.StateDelta) *BlockDelta {
+	bd := &BlockDelta{
+		Block:    block,
+		Accounts: make([]basics.BalanceRecord, delta.Accts.Len()),
+	}
+	for i := range bd.Accounts {
+		addr, data := delta.Accts.GetByIdx(i)
+		bd.Accounts[i] = basics.BalanceRecord{Addr: addr, AccountData: data}
+	}
+
+	for cidx, mc := range delta.Creatables {
+		bd.Creatables = append(bd.Creatables, CreatableDelta{
+			Index:   cidx,
+			Type:    mc.Ctype,
+			Created: mc.Created,
+			Creator: mc.Creator,
+		})
+	}
+	sort.Slice(bd.Creatables, func(i, j int) bool { return bd.Creatables[i].Index < bd.Creatables[j].Index })
+
+	keys := make([]string, 0, len(delta.Boxes))
+	for key := range delta.Boxes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		app, name, err := ledgercore.SplitBoxKey(key)
+		if err != nil {
+			logging.Base().Warnf("makeBlockDelta: round %d: %v", block.Round(), err)
+			continue
+		}
+		bd.Boxes = append(bd.Boxes, BoxDelta{App: app, Name: []byte(name)})
+		if value := delta.Boxes[key].Value; value != nil {
+			bd.Boxes[len(bd.Boxes)-1].Value = []byte(*value)
+		} else {
+			bd.Boxes[len(bd.Boxes)-1].Deleted = true
+		}
+	}
+	return bd
+}
+
+// BlockStream implements ledger.BlockListener, retaining the blocks and
+// state deltas of the most recent rounds so that they can be streamed to
+// consumers. Every consumer keeps its own position in the retained rounds,
+// so a slow consumer never holds back the ledger or other consumers; a
+// consumer that falls further behind than the retained rounds is
+// disconnected, and may resume from where it left off once it catches up
+// with the retention window again.
+type BlockStream struct {
+	mu      deadlock.Mutex
+	retain  int
+	entries []*BlockDelta
+	// changed is closed, and replaced, whenever a new round is added
+	changed chan struct{}
+}
+
+// MakeBlockStream creates a BlockStream retaining the given number of rounds
+func MakeBlockStream(retain int) *BlockStream {
+	if retain <= 0 {
+		retain = DefaultBlockStreamRetainedRounds
+	}
+	return &BlockStream{
+		retain:  retain,
+		changed: make(chan struct{}),
+	}
+}
+
+// OnNewBlock implements the ledger.BlockListener interface
+func (bs *BlockStream) OnNewBlock(block bookkeeping.Block, delta ledgercore.StateDelta) {
+	bd := makeBlockDelta(block, delta)
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if n := len(bs.entries); n > 0 && bs.entries[n-1].Block.Round()+1 != block.Round() {
+		// The ledger skipped ahead (e.g. after a catchpoint catchup), so the
+		// retained rounds can no longer be streamed contiguously.
+		bs.entries = nil
+	}
+	if len(bs.entries) == bs.retain {
+		copy(bs.entries, bs.entries[1:])
+		bs.entries[len(bs.entries)-1] = nil
+		bs.entries = bs.entries[:len(bs.entries)-1]
+	}
+	bs.entries = append(bs.entries, bd)
+
+	close(bs.changed)
+	bs.changed = make(chan struct{})
+}
+
+// get returns the retained entry for round rnd. If rnd has not been added
+// yet, it returns a channel that is closed once a new round is added.
+func (bs *BlockStream) get(rnd basics.Round) (*BlockDelta, <-chan struct{}, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if len(bs.entries) > 0 {
+		first := bs.entries[0].Block.Round()
+		if rnd < first {
+			return nil, nil, fmt.Errorf("round %d is no longer retained, the earliest streamable round is %d", rnd, first)
+		}
+		if idx := uint64(rnd - first); idx < uint64(len(bs.entries)) {
+			return bs.entries[idx], nil, nil
+		}
+	}
+	return nil, bs.changed, nil
+}
+
+// checkAvailable verifies that a stream starting at round rnd can be served,
+// given that latest is the latest round committed to the ledger.
+func (bs *BlockStream) checkAvailable(rnd basics.Round, latest basics.Round) error {
+	if rnd > latest {
+		return nil
+	}
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if len(bs.entries) == 0 || rnd < bs.entries[0].Block.Round() {
+		return fmt.Errorf("round %d is no longer retained for streaming", rnd)
+	}
+	return nil
+}
+
+var blockStreamUpgrader = websocket.Upgrader{
+	// Requests are authenticated by API token, as any other, so there is
+	// no reason to restrict the origin of the request beyond what the
+	// CORS middleware already allows.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// serve streams the retained rounds, starting at round next, to the
+// consumer on conn until the consumer goes away, falls behind, or shutdown
+// is closed.
+func (bs *BlockStream) serve(conn *websocket.Conn, handle codec.Handle, next basics.Round, shutdown <-chan struct{}) error {
+	messageType := websocket.TextMessage
+	if handle == protocol.CodecHandle {
+		messageType = websocket.BinaryMessage
+	}
+
+	// Consume control messages, so that we notice when the consumer closes
+	// the connection.
+	conn.SetReadLimit(blockStreamReadLimit)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	closeWith := func(code int, text string) {
+		msg := websocket.FormatCloseMessage(code, text)
+		conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(blockStreamWriteTimeout))
+	}
+
+	for {
+		bd, wait, err := bs.get(next)
+		if err != nil {
+			closeWith(websocket.CloseTryAgainLater, err.Error())
+			return err
+		}
+		if bd == nil {
+			select {
+			case <-wait:
+				continue
+			case <-closed:
+				return nil
+			case <-shutdown:
+				closeWith(websocket.CloseGoingAway, "server shutting down")
+				return nil
+			}
+		}
+
+		data, err := encode(handle, bd)
+		if err != nil {
+			closeWith(websocket.CloseInternalServerErr, errFailedToEncodeResponse)
+			return err
+		}
+		// Drop the consumer if it does not take the message in time, which
+		// makes the blocked write fail.
+		stuck := time.AfterFunc(blockStreamWriteTimeout, func() { conn.CloseWithoutFlush() })
+		err = conn.WriteMessage(messageType, data)
+		stuck.Stop()
+		if err != nil {
+			return err
+		}
+		next++
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package v2
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algorand/go-codec/codec"
+	"github.com/algorand/websocket"
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func addStreamRound(bs *BlockStream, rnd basics.Round) {
+	hdr := bookkeeping.BlockHeader{Round: rnd}
+	delta := ledgercore.MakeStateDelta(&hdr, 0, 1, 0)
+	delta.Accts.Upsert(basics.Address{byte(rnd)}, basics.AccountData{MicroAlgos: basics.MicroAlgos{Raw: uint64(rnd)}})
+	bs.OnNewBlock(bookkeeping.Block{BlockHeader: hdr}, delta)
+}
+
+func TestBlockStreamRetention(t *testing.T) {
+	bs := MakeBlockStream(3)
+	for rnd := basics.Round(1); rnd <= 5; rnd++ {
+		addStreamRound(bs, rnd)
+	}
+
+	_, _, err := bs.get(2)
+	require.Error(t, err)
+	require.Error(t, bs.checkAvailable(2, 5))
+	require.NoError(t, bs.checkAvailable(3, 5))
+	require.NoError(t, bs.checkAvailable(6, 5))
+	require.NoError(t, bs.checkAvailable(10, 5))
+
+	bd, _, err := bs.get(3)
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(3), bd.Block.Round())
+	require.Len(t, bd.Accounts, 1)
+	require.Equal(t, uint64(3), bd.Accounts[0].MicroAlgos.Raw)
+
+	bd, wait, err := bs.get(6)
+	require.NoError(t, err)
+	require.Nil(t, bd)
+	select {
+	case <-wait:
+		require.Fail(t, "round 6 signaled before it was added")
+	default:
+	}
+	addStreamRound(bs, 6)
+	<-wait
+	bd, _, err = bs.get(6)
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(6), bd.Block.Round())
+
+	// skipping ahead drops everything retained so far
+	addStreamRound(bs, 10)
+	_, _, err = bs.get(6)
+	require.Error(t, err)
+	require.Error(t, bs.checkAvailable(9, 10))
+	bd, _, err = bs.get(10)
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(10), bd.Block.Round())
+}
+
+func TestBlockDeltaEncoding(t *testing.T) {
+	hdr := bookkeeping.BlockHeader{Round: 7}
+	delta := ledgercore.MakeStateDelta(&hdr, 0, 1, 0)
+	creator := basics.Address{1}
+	delta.Accts.Upsert(creator, basics.AccountData{MicroAlgos: basics.MicroAlgos{Raw: 100}})
+	delta.Creatables[12] = ledgercore.ModifiedCreatable{Ctype: basics.AppCreatable, Created: true, Creator: creator}
+	delta.Creatables[3] = ledgercore.ModifiedCreatable{Ctype: basics.AssetCreatable, Created: false, Creator: creator}
+	value := "content"
+	delta.Boxes[ledgercore.MakeBoxKey(12, "b")] = ledgercore.ModifiedBox{Value: &value}
+	delta.Boxes[ledgercore.MakeBoxKey(12, "a")] = ledgercore.ModifiedBox{}
+
+	bd := makeBlockDelta(bookkeeping.Block{BlockHeader: hdr}, delta)
+	require.Equal(t, []CreatableDelta{
+		{Index: 3, Type: basics.AssetCreatable, Created: false, Creator: creator},
+		{Index: 12, Type: basics.AppCreatable, Created: true, Creator: creator},
+	}, bd.Creatables)
+	require.Equal(t, []BoxDelta{
+		{App: 12, Name: []byte("a"), Deleted: true},
+		{App: 12, Name: []byte("b"), Value: []byte("content")},
+	}, bd.Boxes)
+
+	for _, handle := range []codec.Handle{protocol.JSONHandle, protocol.CodecHandle} {
+		data, err := encode(handle, bd)
+		require.NoError(t, err)
+		var decoded BlockDelta
+		require.NoError(t, decode(handle, data, &decoded))
+		require.Equal(t, bd.Block.Round(), decoded.Block.Round())
+		require.Equal(t, bd.Accounts, decoded.Accounts)
+		require.Equal(t, bd.Creatables, decoded.Creatables)
+		require.Equal(t, bd.Boxes, decoded.Boxes)
+	}
+}
+
+func TestBlockStreamServe(t *testing.T) {
+	bs := MakeBlockStream(3)
+	for rnd := basics.Round(1); rnd <= 5; rnd++ {
+		addStreamRound(bs, rnd)
+	}
+
+	shutdown := make(chan struct{})
+	defer close(shutdown)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handle, _, err := getCodecHandle(strOrNil(r.URL.Query().Get("format")))
+		require.NoError(t, err)
+		start, err := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
+		require.NoError(t, err)
+		conn, err := blockStreamUpgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		bs.serve(conn, handle, basics.Round(start), shutdown)
+	}))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	start := basics.Round(3)
+	for _, format := range []string{"json", "msgpack"} {
+		conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("%s?format=%s&start=%d", url, format, start), nil)
+		require.NoError(t, err)
+		conn.SetReadLimit(1 << 20)
+		handle, _, err := getCodecHandle(&format)
+		require.NoError(t, err)
+
+		latest := start + 2
+		for rnd := start; rnd <= latest+1; rnd++ {
+			if rnd == latest+1 {
+				// new rounds are streamed as soon as they are committed
+				addStreamRound(bs, rnd)
+			}
+			conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+			messageType, data, err := conn.ReadMessage()
+			require.NoError(t, err)
+			if format == "json" {
+				require.Equal(t, websocket.TextMessage, messageType)
+			} else {
+				require.Equal(t, websocket.BinaryMessage, messageType)
+			}
+			var bd BlockDelta
+			require.NoError(t, decode(handle, data, &bd))
+			require.Equal(t, rnd, bd.Block.Round())
+			require.Equal(t, uint64(rnd), bd.Accounts[0].MicroAlgos.Raw)
+		}
+		conn.Close()
+		start++
+	}
+
+	// consumers that are behind the retained rounds are told to try again later
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?start=1", nil)
+	require.NoError(t, err)
+	conn.SetReadLimit(1 << 20)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, _, err = conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseTryAgainLater), "%v", err)
+}
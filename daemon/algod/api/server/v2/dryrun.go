@@ -17,7 +17,9 @@
 package v2
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/ledger/apply"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 
 	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/protocol"
@@ -56,6 +59,24 @@ type DryrunRequest struct {
 	LatestTimestamp int64 `codec:"latest-timestamp"`
 
 	Sources []generated.DryrunSource `codec:"sources"`
+
+	// Boxes are the contents of the application boxes the programs may read.
+	Boxes []generated.DryrunBox `codec:"boxes"`
+
+	// sourceMaps of the programs compiled from Sources, used to point
+	// errors at the source line that caused them
+	sourceMaps map[dryrunSourceKey]logic.SourceMap
+
+	// jsonTrace asks for a JSON lines trace of every program run
+	jsonTrace bool
+}
+
+// dryrunSourceKey identifies the program a DryrunSource was compiled into:
+// a logic sig by its transaction, an application program by its app id
+type dryrunSourceKey struct {
+	fieldName string
+	txnIndex  uint64
+	appIndex  uint64
 }
 
 // DryrunRequestFromGenerated converts generated.DryrunRequest to DryrunRequest field by fields
@@ -78,6 +99,9 @@ func DryrunRequestFromGenerated(gdr *generated.DryrunRequest) (dr DryrunRequest,
 	dr.Round = gdr.Round
 	dr.LatestTimestamp = int64(gdr.LatestTimestamp)
 	dr.Sources = gdr.Sources
+	if gdr.Boxes != nil {
+		dr.Boxes = *gdr.Boxes
+	}
 	return
 }
 
@@ -89,10 +113,16 @@ func (dr *DryrunRequest) ExpandSources() error {
 		if err != nil {
 			return fmt.Errorf("Dryrun Source[%d]: %v", i, err)
 		}
+		if dr.sourceMaps == nil {
+			dr.sourceMaps = make(map[dryrunSourceKey]logic.SourceMap)
+		}
+		sm := ops.SourceMap(fmt.Sprintf("Source[%d]", i), "")
 		switch s.FieldName {
 		case "lsig":
 			dr.Txns[s.TxnIndex].Lsig.Logic = ops.Program
+			dr.sourceMaps[dryrunSourceKey{fieldName: s.FieldName, txnIndex: s.TxnIndex}] = sm
 		case "approv", "clearp":
+			dr.sourceMaps[dryrunSourceKey{fieldName: s.FieldName, appIndex: s.AppIndex}] = sm
 			for ai, app := range dr.Apps {
 				if app.Id == s.AppIndex {
 					switch s.FieldName {
@@ -110,11 +140,50 @@ func (dr *DryrunRequest) ExpandSources() error {
 	return nil
 }
 
+// sourceError describes an evaluation error, naming the source line of the
+// failing instruction when the program was compiled from Sources
+func (dr *DryrunRequest) sourceError(err error, key dryrunSourceKey, history []generated.DryrunState) string {
+	sm, ok := dr.sourceMaps[key]
+	if !ok || len(history) == 0 {
+		return err.Error()
+	}
+	locations, lerr := sm.Locations()
+	if lerr != nil {
+		return err.Error()
+	}
+	loc, ok := locations[int(history[len(history)-1].Pc)]
+	if !ok {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s at %s line %d", err.Error(), sm.Sources[loc.Source], loc.Line+1)
+}
+
 type dryrunDebugReceiver struct {
 	disassembly   string
 	lines         []string
 	history       []generated.DryrunState
 	scratchActive []bool
+
+	// trace receives every step as well when a JSON lines trace is requested
+	trace    *logic.TraceWriter
+	traceBuf bytes.Buffer
+}
+
+func makeDryrunDebugReceiver(jsonTrace bool) *dryrunDebugReceiver {
+	ddr := &dryrunDebugReceiver{}
+	if jsonTrace {
+		ddr.trace = logic.MakeTraceWriter(&ddr.traceBuf)
+	}
+	return ddr
+}
+
+// jsonTrace returns the JSON lines trace of the program, or nil if none was requested
+func (ddr *dryrunDebugReceiver) jsonTrace() *string {
+	if ddr.trace == nil {
+		return nil
+	}
+	trace := ddr.traceBuf.String()
+	return &trace
 }
 
 func (ddr *dryrunDebugReceiver) updateScratch() {
@@ -198,20 +267,34 @@ func (ddr *dryrunDebugReceiver) stateToState(state *logic.DebugState) generated.
 func (ddr *dryrunDebugReceiver) Register(state *logic.DebugState) error {
 	ddr.disassembly = state.Disassembly
 	ddr.lines = strings.Split(state.Disassembly, "\n")
+	if ddr.trace != nil {
+		return ddr.trace.Register(state)
+	}
 	return nil
 }
 
-// Update is fired on every step (DebuggerHook interface)
-func (ddr *dryrunDebugReceiver) Update(state *logic.DebugState) error {
+func (ddr *dryrunDebugReceiver) record(state *logic.DebugState) {
 	st := ddr.stateToState(state)
 	ddr.history = append(ddr.history, st)
 	ddr.updateScratch()
+}
+
+// Update is fired on every step (DebuggerHook interface)
+func (ddr *dryrunDebugReceiver) Update(state *logic.DebugState) error {
+	ddr.record(state)
+	if ddr.trace != nil {
+		return ddr.trace.Update(state)
+	}
 	return nil
 }
 
 // Complete is called when the program exits (DebuggerHook interface)
 func (ddr *dryrunDebugReceiver) Complete(state *logic.DebugState) error {
-	return ddr.Update(state)
+	ddr.record(state)
+	if ddr.trace != nil {
+		return ddr.trace.Complete(state)
+	}
+	return nil
 }
 
 type dryrunLedger struct {
@@ -226,6 +309,8 @@ type dryrunLedger struct {
 	accountsIn map[basics.Address]int
 	// index into dr.Apps[]
 	accountApps map[basics.Address]int
+	// box contents from dr.Boxes[], by box key
+	boxes map[string]string
 }
 
 func (dl *dryrunLedger) init() error {
@@ -249,6 +334,10 @@ func (dl *dryrunLedger) init() error {
 		}
 		dl.accountApps[addr] = i
 	}
+	dl.boxes = make(map[string]string, len(dl.dr.Boxes))
+	for _, box := range dl.dr.Boxes {
+		dl.boxes[ledgercore.MakeBoxKey(basics.AppIndex(box.AppIndex), string(box.Name))] = string(box.Value)
+	}
 	return nil
 }
 
@@ -335,6 +424,11 @@ func (dl *dryrunLedger) GetCreatorForRound(rnd basics.Round, cidx basics.Creatab
 	return basics.Address{}, false, fmt.Errorf("unknown creatable type %d", ctype)
 }
 
+func (dl *dryrunLedger) LookupBox(rnd basics.Round, key string) (string, bool, error) {
+	value, ok := dl.boxes[key]
+	return value, ok, nil
+}
+
 func (dl *dryrunLedger) getAppParams(addr basics.Address, aidx basics.AppIndex) (params basics.AppParams, err error) {
 	idx, ok := dl.accountApps[addr]
 	if !ok {
@@ -398,31 +492,43 @@ func doDryrunRequest(dr *DryrunRequest, response *generated.DryrunResponse) {
 	}
 	proto := config.Consensus[protocol.ConsensusVersion(dr.ProtocolVersion)]
 
+	pooledBudget := logic.MakePooledBudget(&proto, dr.Txns)
 	response.Txns = make([]generated.DryrunTxnResult, len(dr.Txns))
+	// logic signatures and applications are evaluated separately on
+	// chain, so each sees only the side effects of its own kind
+	lsigSideEffects := logic.MakePastSideEffects(len(dr.Txns))
+	appSideEffects := logic.MakePastSideEffects(len(dr.Txns))
 	for ti, stxn := range dr.Txns {
-		pse := logic.MakePastSideEffects(len(dr.Txns))
 		ep := logic.EvalParams{
-			Txn:             &stxn,
-			Proto:           &proto,
-			TxnGroup:        dr.Txns,
-			GroupIndex:      ti,
-			PastSideEffects: pse,
+			Txn:                     &stxn,
+			Proto:                   &proto,
+			TxnGroup:                dr.Txns,
+			GroupIndex:              ti,
+			PastSideEffects:         appSideEffects,
+			Specials:                &transactions.SpecialAddresses{},
+			PooledApplicationBudget: pooledBudget,
 		}
 		var result generated.DryrunTxnResult
 		if len(stxn.Lsig.Logic) > 0 {
-			var debug dryrunDebugReceiver
-			ep.Debugger = &debug
-			pass, err := logic.Eval(stxn.Lsig.Logic, ep)
+			debug := makeDryrunDebugReceiver(dr.jsonTrace)
+			ep.Debugger = debug
+			lsigEp := ep
+			lsigEp.PastSideEffects = lsigSideEffects
+			pass, err := logic.Eval(stxn.Lsig.Logic, lsigEp)
 			var messages []string
 			result.Disassembly = debug.lines
 			result.LogicSigTrace = &debug.history
+			result.LogicSigJsonTrace = debug.jsonTrace()
+			result.LogicSigReturnData = convertToLogs(lsigSideEffects[ti].ReturnData())
+			result.LogicSigCostAnalysis = convertToCostAnalysis(stxn.Lsig.Logic)
 			if pass {
 				messages = append(messages, "PASS")
 			} else {
 				messages = append(messages, "REJECT")
 			}
 			if err != nil {
-				messages = append(messages, err.Error())
+				key := dryrunSourceKey{fieldName: "lsig", txnIndex: uint64(ti)}
+				messages = append(messages, dr.sourceError(err, key, debug.history))
 			}
 			result.LogicSigMessages = &messages
 		}
@@ -492,20 +598,24 @@ func doDryrunRequest(dr *DryrunRequest, response *generated.DryrunResponse) {
 				messages = make([]string, 1)
 				messages[0] = fmt.Sprintf("uploaded state did not include app id %d referenced in txn[%d]", appIdx, ti)
 			} else {
-				var debug dryrunDebugReceiver
-				ep.Debugger = &debug
+				debug := makeDryrunDebugReceiver(dr.jsonTrace)
+				ep.Debugger = debug
 				var program []byte
+				key := dryrunSourceKey{appIndex: uint64(appIdx)}
 				messages = make([]string, 1)
 				if stxn.Txn.OnCompletion == transactions.ClearStateOC {
 					program = app.ClearStateProgram
+					key.fieldName = "clearp"
 					messages[0] = "ClearStateProgram"
 				} else {
 					program = app.ApprovalProgram
+					key.fieldName = "approv"
 					messages[0] = "ApprovalProgram"
 				}
 				pass, delta, err := ba.StatefulEval(ep, appIdx, program)
 				result.Disassembly = debug.lines
 				result.AppCallTrace = &debug.history
+				result.AppCallJsonTrace = debug.jsonTrace()
 				result.GlobalDelta = StateDeltaToStateDelta(delta.GlobalDelta)
 				if len(delta.LocalDeltas) > 0 {
 					localDeltas := make([]generated.AccountStateDelta, len(delta.LocalDeltas))
@@ -521,13 +631,18 @@ func doDryrunRequest(dr *DryrunRequest, response *generated.DryrunResponse) {
 					}
 					result.LocalDeltas = &localDeltas
 				}
+				// logs are reported even if the program rejected or failed, to aid debugging
+				result.Logs = convertToLogs(ep.PastSideEffects[ti].Logs())
+				result.AppCallReturnData = convertToLogs(ep.PastSideEffects[ti].ReturnData())
+				result.OpcodeCost = numOrNil(uint64(ep.PastSideEffects[ti].Cost()))
+				result.AppCallCostAnalysis = convertToCostAnalysis(program)
 				if pass {
 					messages = append(messages, "PASS")
 				} else {
 					messages = append(messages, "REJECT")
 				}
 				if err != nil {
-					messages = append(messages, err.Error())
+					messages = append(messages, dr.sourceError(err, key, debug.history))
 				}
 			}
 			result.AppCallMessages = &messages
@@ -536,6 +651,166 @@ func doDryrunRequest(dr *DryrunRequest, response *generated.DryrunResponse) {
 	}
 }
 
+// snapshotLedger is the part of the ledger a dryrun snapshot is read from
+type snapshotLedger interface {
+	BlockHdr(rnd basics.Round) (bookkeeping.BlockHeader, error)
+	Lookup(rnd basics.Round, addr basics.Address) (basics.AccountData, error)
+	LookupWithoutRewards(rnd basics.Round, addr basics.Address) (basics.AccountData, basics.Round, error)
+	GetCreatorForRound(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error)
+	ListBoxes(appIdx basics.AppIndex, maxResults uint64) ([]string, error)
+	LookupBox(rnd basics.Round, key string) (string, bool, error)
+}
+
+// dryrunSnapshotMaxBoxes is the most boxes of a single application that a
+// dryrun snapshot includes
+const dryrunSnapshotMaxBoxes = 1000
+
+// makeDryrunSnapshot builds a self-contained DryrunRequest for running
+// txgroup as if it were in the round after rnd. It includes the state of
+// rnd for every account, application and asset the group refers to, and
+// the round and timestamp that programs read with global. The boxes of
+// the applications are included too; their names are listed from the latest
+// round, so a box deleted after rnd is missing from the snapshot.
+func makeDryrunSnapshot(l snapshotLedger, txgroup []transactions.SignedTxn, rnd basics.Round) (generated.DryrunRequest, error) {
+	hdr, err := l.BlockHdr(rnd)
+	if err != nil {
+		return generated.DryrunRequest{}, err
+	}
+	dr := generated.DryrunRequest{
+		ProtocolVersion: string(hdr.CurrentProtocol),
+		Round:           uint64(rnd + 1),
+		LatestTimestamp: uint64(hdr.TimeStamp),
+		Accounts:        make([]generated.Account, 0),
+		Apps:            make([]generated.Application, 0),
+		Sources:         make([]generated.DryrunSource, 0),
+		Txns:            make([]json.RawMessage, len(txgroup)),
+	}
+
+	var addrs []basics.Address
+	seenAddrs := make(map[basics.Address]bool)
+	addAddr := func(addr basics.Address) {
+		if !addr.IsZero() && !seenAddrs[addr] {
+			seenAddrs[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	var apps []basics.AppIndex
+	var assets []basics.AssetIndex
+	seenApps := make(map[basics.AppIndex]bool)
+	seenAssets := make(map[basics.AssetIndex]bool)
+
+	for i := range txgroup {
+		txn := &txgroup[i].Txn
+		dr.Txns[i] = protocol.EncodeJSON(&txgroup[i])
+
+		for _, addr := range []basics.Address{
+			txn.Sender, txn.Receiver, txn.CloseRemainderTo,
+			txn.AssetSender, txn.AssetReceiver, txn.AssetCloseTo, txn.FreezeAccount,
+		} {
+			addAddr(addr)
+		}
+		for _, addr := range txn.Accounts {
+			addAddr(addr)
+		}
+		for _, app := range append([]basics.AppIndex{txn.ApplicationID}, txn.ForeignApps...) {
+			if app != 0 && !seenApps[app] {
+				seenApps[app] = true
+				apps = append(apps, app)
+			}
+		}
+		for _, asset := range append([]basics.AssetIndex{txn.XferAsset, txn.ConfigAsset, txn.FreezeAsset}, txn.ForeignAssets...) {
+			if asset != 0 && !seenAssets[asset] {
+				seenAssets[asset] = true
+				assets = append(assets, asset)
+			}
+		}
+	}
+
+	// applications are reported with their creator, so only their own
+	// accounts are added to the accounts
+	var boxes []generated.DryrunBox
+	for _, app := range apps {
+		creator, ok, err := l.GetCreatorForRound(rnd, basics.CreatableIndex(app), basics.AppCreatable)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		if !ok {
+			// a deleted application, or one the group creates
+			continue
+		}
+		record, _, err := l.LookupWithoutRewards(rnd, creator)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		params, ok := record.AppParams[app]
+		if !ok {
+			continue
+		}
+		dr.Apps = append(dr.Apps, AppParamsToApplication(creator.String(), app, &params))
+		addAddr(app.Address())
+
+		names, err := l.ListBoxes(app, dryrunSnapshotMaxBoxes+1)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		if len(names) > dryrunSnapshotMaxBoxes {
+			return generated.DryrunRequest{}, fmt.Errorf("application %d has more than %d boxes", app, dryrunSnapshotMaxBoxes)
+		}
+		for _, name := range names {
+			value, ok, err := l.LookupBox(rnd, ledgercore.MakeBoxKey(app, name))
+			if err != nil {
+				return generated.DryrunRequest{}, err
+			}
+			if !ok {
+				// created after rnd
+				continue
+			}
+			boxes = append(boxes, generated.DryrunBox{AppIndex: uint64(app), Name: []byte(name), Value: []byte(value)})
+		}
+	}
+	if len(boxes) > 0 {
+		dr.Boxes = &boxes
+	}
+
+	// asset params are read from the account of their creator
+	for _, asset := range assets {
+		creator, ok, err := l.GetCreatorForRound(rnd, basics.CreatableIndex(asset), basics.AssetCreatable)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		if ok {
+			addAddr(creator)
+		}
+	}
+
+	for _, addr := range addrs {
+		record, err := l.Lookup(rnd, addr)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		without, _, err := l.LookupWithoutRewards(rnd, addr)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		assetsCreators := make(map[basics.AssetIndex]string, len(record.Assets))
+		for aidx := range record.Assets {
+			creator, ok, err := l.GetCreatorForRound(rnd, basics.CreatableIndex(aidx), basics.AssetCreatable)
+			if err == nil && ok {
+				assetsCreators[aidx] = creator.String()
+			} else {
+				// the asset may have been deleted
+				assetsCreators[aidx] = ""
+			}
+		}
+		account, err := AccountDataToAccount(addr.String(), &record, assetsCreators, rnd, without.MicroAlgos)
+		if err != nil {
+			return generated.DryrunRequest{}, err
+		}
+		dr.Accounts = append(dr.Accounts, account)
+	}
+	return dr, nil
+}
+
 // StateDeltaToStateDelta converts basics.StateDelta to generated.StateDelta
 func StateDeltaToStateDelta(sd basics.StateDelta) *generated.StateDelta {
 	if len(sd) == 0 {
@@ -20,6 +20,8 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -31,8 +33,10 @@ import (
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/protocol"
 )
 
@@ -176,6 +180,116 @@ func TestDryrunLogicSigSource(t *testing.T) {
 	}
 }
 
+func TestDryrunSourceErrorLine(t *testing.T) {
+	t.Parallel()
+
+	var dr DryrunRequest
+	var response generated.DryrunResponse
+
+	dr.ProtocolVersion = string(dryrunProtoVersion)
+
+	dr.Txns = []transactions.SignedTxn{
+		{},
+		{
+			Txn: transactions.Transaction{
+				Type: protocol.ApplicationCallTx,
+				ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+					ApplicationID: 1,
+				},
+			},
+		},
+	}
+	dr.Apps = []generated.Application{{Id: 1}}
+	dr.Sources = []generated.DryrunSource{
+		{
+			Source:    "#pragma version 2\nint 1\n// comment\nerr",
+			FieldName: "lsig",
+			TxnIndex:  0,
+		},
+		{
+			Source:    "#pragma version 2\nint 1\nint 0\n/\nreturn",
+			FieldName: "approv",
+			AppIndex:  1,
+		},
+	}
+	doDryrunRequest(&dr, &response)
+	require.Empty(t, response.Error)
+	require.Equal(t, 2, len(response.Txns))
+
+	messages := *response.Txns[0].LogicSigMessages
+	require.Equal(t, 2, len(messages))
+	require.Equal(t, "REJECT", messages[0])
+	require.True(t, strings.HasSuffix(messages[1], " at Source[0] line 4"), messages[1])
+
+	messages = *response.Txns[1].AppCallMessages
+	require.Equal(t, 3, len(messages))
+	require.Equal(t, "REJECT", messages[1])
+	require.True(t, strings.HasSuffix(messages[2], " at Source[1] line 4"), messages[2])
+	if t.Failed() {
+		logResponse(t, &response)
+	}
+}
+
+func TestDryrunCostAnalysis(t *testing.T) {
+	t.Parallel()
+
+	var dr DryrunRequest
+	var response generated.DryrunResponse
+
+	dr.ProtocolVersion = string(dryrunProtoVersion)
+
+	dr.Txns = []transactions.SignedTxn{
+		{},
+		{
+			Txn: transactions.Transaction{
+				Type: protocol.ApplicationCallTx,
+				ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+					ApplicationID: 1,
+				},
+			},
+		},
+	}
+	dr.Apps = []generated.Application{{Id: 1}}
+	dr.Sources = []generated.DryrunSource{
+		{
+			Source:    "#pragma version 2\nint 1\nint 2\n+",
+			FieldName: "lsig",
+			TxnIndex:  0,
+		},
+		{
+			Source:    "#pragma version 4\nint 1\ncallsub sub\nreturn\nsub:\nloop:\nb loop",
+			FieldName: "approv",
+			AppIndex:  1,
+		},
+	}
+	doDryrunRequest(&dr, &response)
+	require.Empty(t, response.Error)
+	require.Equal(t, 2, len(response.Txns))
+
+	lsig := *response.Txns[0].LogicSigCostAnalysis
+	require.Len(t, lsig, 1)
+	// intcblock, two intc and +
+	require.Equal(t, uint64(4), *lsig[0].Cost)
+	require.Equal(t, uint64(2), *lsig[0].MaxStackDepth)
+	require.Nil(t, lsig[0].LoopPc)
+	require.Nil(t, response.Txns[0].AppCallCostAnalysis)
+
+	app := *response.Txns[1].AppCallCostAnalysis
+	require.Len(t, app, 2)
+	require.False(t, app[0].Subroutine)
+	require.True(t, app[1].Subroutine)
+	for _, entry := range app {
+		require.Nil(t, entry.Cost)
+		require.Equal(t, app[1].Pc, *entry.LoopPc)
+	}
+	// the loop leaves the stack alone, so only the main program's int counts
+	require.Equal(t, uint64(1), *app[0].MaxStackDepth)
+	require.Equal(t, uint64(0), *app[1].MaxStackDepth)
+	if t.Failed() {
+		logResponse(t, &response)
+	}
+}
+
 const globalTestSource = `#pragma version 2
 // This program approves all transactions whose first arg is "hello"
 // Then, accounts can write "foo": "bar" to the GlobalState by
@@ -346,9 +460,13 @@ func init() {
 
 	// legder requires proto string and proto params set
 	var proto config.ConsensusParams
-	proto.LogicSigVersion = 4
+	proto.LogicSigVersion = 5
 	proto.LogicSigMaxCost = 20000
 	proto.MaxAppProgramCost = 700
+	proto.MaxLogCalls = 32
+	proto.MaxLogSize = 1024
+	proto.MaxReturnDataEntries = 16
+	proto.MaxReturnDataSize = 1024
 	proto.MaxAppKeyLen = 64
 	proto.MaxAppBytesValueLen = 64
 	proto.MaxAppSumKeyValueLens = 128
@@ -1091,3 +1209,418 @@ int 1`)
 		logResponse(t, &response)
 	}
 }
+
+func TestDryrunLogs(t *testing.T) {
+	t.Parallel()
+
+	ops, err := logic.AssembleString(`#pragma version 5
+byte "A"
+log
+byte "B"
+log
+txn NumAppArgs
+`)
+	require.NoError(t, err)
+	approval := ops.Program
+	ops, err = logic.AssembleString("int 1")
+	require.NoError(t, err)
+	clst := ops.Program
+
+	var appIdx basics.AppIndex = 1
+	creator := randomAddress()
+	dr := DryrunRequest{
+		Txns: []transactions.SignedTxn{
+			{
+				Txn: transactions.Transaction{
+					Type: protocol.ApplicationCallTx,
+					ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+						ApplicationID:   appIdx,
+						ApplicationArgs: [][]byte{[]byte("pass")},
+					},
+				},
+			},
+			{
+				Txn: transactions.Transaction{
+					Type: protocol.ApplicationCallTx,
+					ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+						ApplicationID: appIdx,
+					},
+				},
+			},
+		},
+		Apps: []generated.Application{
+			{
+				Id: uint64(appIdx),
+				Params: generated.ApplicationParams{
+					Creator:           creator.String(),
+					ApprovalProgram:   approval,
+					ClearStateProgram: clst,
+				},
+			},
+		},
+	}
+	dr.ProtocolVersion = string(dryrunProtoVersion)
+
+	var response generated.DryrunResponse
+	doDryrunRequest(&dr, &response)
+	checkAppCallPass(t, &response)
+	if t.Failed() {
+		logResponse(t, &response)
+	}
+	require.Len(t, response.Txns, 2)
+	for _, result := range response.Txns {
+		require.NotNil(t, result.Logs)
+		require.Equal(t, [][]byte{[]byte("A"), []byte("B")}, *result.Logs)
+	}
+	messages := *response.Txns[1].AppCallMessages
+	require.Equal(t, "REJECT", messages[len(messages)-1])
+}
+
+func TestDryrunJSONTrace(t *testing.T) {
+	t.Parallel()
+
+	ops, err := logic.AssembleString(`#pragma version 5
+byte "A"
+log
+int 1`)
+	require.NoError(t, err)
+	approval := ops.Program
+	ops, err = logic.AssembleString("#pragma version 5\nint 1")
+	require.NoError(t, err)
+	clst := ops.Program
+
+	var appIdx basics.AppIndex = 1
+	dr := DryrunRequest{
+		Txns: []transactions.SignedTxn{
+			{
+				Lsig: transactions.LogicSig{
+					Logic: clst,
+				},
+				Txn: transactions.Transaction{
+					Type: protocol.ApplicationCallTx,
+					ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+						ApplicationID: appIdx,
+					},
+				},
+			},
+		},
+		Apps: []generated.Application{
+			{
+				Id: uint64(appIdx),
+				Params: generated.ApplicationParams{
+					Creator:           randomAddress().String(),
+					ApprovalProgram:   approval,
+					ClearStateProgram: clst,
+				},
+			},
+		},
+	}
+	dr.ProtocolVersion = string(dryrunProtoVersion)
+
+	var response generated.DryrunResponse
+	doDryrunRequest(&dr, &response)
+	checkAppCallPass(t, &response)
+	require.Nil(t, response.Txns[0].LogicSigJsonTrace)
+	require.Nil(t, response.Txns[0].AppCallJsonTrace)
+
+	dr.jsonTrace = true
+	response = generated.DryrunResponse{}
+	doDryrunRequest(&dr, &response)
+	checkAppCallPass(t, &response)
+	checkLogicSigPass(t, &response)
+
+	readTrace := func(trace *string) []logic.TraceEvent {
+		require.NotNil(t, trace)
+		var events []logic.TraceEvent
+		for _, line := range strings.Split(strings.TrimSpace(*trace), "\n") {
+			var event logic.TraceEvent
+			require.NoError(t, json.Unmarshal([]byte(line), &event), line)
+			events = append(events, event)
+		}
+		return events
+	}
+	events := readTrace(response.Txns[0].LogicSigJsonTrace)
+	require.Equal(t, logic.TraceRegister, events[0].Event)
+	require.Equal(t, logic.TraceComplete, events[len(events)-1].Event)
+
+	events = readTrace(response.Txns[0].AppCallJsonTrace)
+	require.Equal(t, logic.TraceRegister, events[0].Event)
+	var opcodes []string
+	var logs [][]byte
+	for _, event := range events[1 : len(events)-1] {
+		require.Equal(t, logic.TraceStep, event.Event)
+		opcodes = append(opcodes, event.Op)
+		logs = append(logs, event.Logs...)
+	}
+	require.Equal(t, []string{"pushbytes", "log", "pushint"}, opcodes)
+	require.Equal(t, [][]byte{[]byte("A")}, logs)
+	complete := events[len(events)-1]
+	require.Equal(t, logic.TraceComplete, complete.Event)
+	require.Equal(t, []logic.TraceValue{{Type: "uint", Uint: 1}}, complete.Stack)
+}
+
+func TestDryrunReturnData(t *testing.T) {
+	t.Parallel()
+
+	ops, err := logic.AssembleString(`#pragma version 5
+txn GroupIndex
+bnz read
+byte "from app 0"
+retdata_put
+int 1
+return
+read:
+gretdata 0 0
+assert
+byte "from app 0"
+==
+`)
+	require.NoError(t, err)
+	approval := ops.Program
+	ops, err = logic.AssembleString("int 1")
+	require.NoError(t, err)
+	clst := ops.Program
+	ops, err = logic.AssembleString(`#pragma version 5
+txn GroupIndex
+bnz read
+byte "from lsig 0"
+retdata_put
+int 1
+return
+read:
+gretdata 0 0
+assert
+byte "from lsig 0"
+==
+`)
+	require.NoError(t, err)
+	lsig := ops.Program
+
+	var appIdx basics.AppIndex = 1
+	creator := randomAddress()
+	call := transactions.SignedTxn{
+		Lsig: transactions.LogicSig{Logic: lsig},
+		Txn: transactions.Transaction{
+			Type: protocol.ApplicationCallTx,
+			ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+				ApplicationID: appIdx,
+			},
+		},
+	}
+	dr := DryrunRequest{
+		Txns: []transactions.SignedTxn{call, call},
+		Apps: []generated.Application{
+			{
+				Id: uint64(appIdx),
+				Params: generated.ApplicationParams{
+					Creator:           creator.String(),
+					ApprovalProgram:   approval,
+					ClearStateProgram: clst,
+				},
+			},
+		},
+	}
+	dr.ProtocolVersion = string(dryrunProtoVersion)
+
+	var response generated.DryrunResponse
+	doDryrunRequest(&dr, &response)
+	checkLogicSigPass(t, &response)
+	checkAppCallPass(t, &response)
+	if t.Failed() {
+		logResponse(t, &response)
+	}
+	require.Len(t, response.Txns, 2)
+	require.Equal(t, [][]byte{[]byte("from lsig 0")}, *response.Txns[0].LogicSigReturnData)
+	require.Equal(t, [][]byte{[]byte("from app 0")}, *response.Txns[0].AppCallReturnData)
+	require.Nil(t, response.Txns[1].LogicSigReturnData)
+	require.Nil(t, response.Txns[1].AppCallReturnData)
+}
+
+// snapshotTestLedger serves account data at a single round
+type snapshotTestLedger struct {
+	hdr      bookkeeping.BlockHeader
+	accounts map[basics.Address]basics.AccountData
+	boxes    map[string]string
+}
+
+func (l *snapshotTestLedger) BlockHdr(rnd basics.Round) (bookkeeping.BlockHeader, error) {
+	return l.hdr, nil
+}
+
+func (l *snapshotTestLedger) Lookup(rnd basics.Round, addr basics.Address) (basics.AccountData, error) {
+	return l.accounts[addr], nil
+}
+
+func (l *snapshotTestLedger) LookupWithoutRewards(rnd basics.Round, addr basics.Address) (basics.AccountData, basics.Round, error) {
+	return l.accounts[addr], rnd, nil
+}
+
+func (l *snapshotTestLedger) GetCreatorForRound(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error) {
+	for addr, ad := range l.accounts {
+		if _, ok := ad.AppParams[basics.AppIndex(cidx)]; ok && ctype == basics.AppCreatable {
+			return addr, true, nil
+		}
+		if _, ok := ad.AssetParams[basics.AssetIndex(cidx)]; ok && ctype == basics.AssetCreatable {
+			return addr, true, nil
+		}
+	}
+	return basics.Address{}, false, nil
+}
+
+func (l *snapshotTestLedger) ListBoxes(appIdx basics.AppIndex, maxResults uint64) ([]string, error) {
+	prefix := ledgercore.BoxKeyAppPrefix(appIdx)
+	var names []string
+	for key := range l.boxes {
+		if strings.HasPrefix(key, prefix) {
+			names = append(names, key[len(prefix):])
+		}
+	}
+	sort.Strings(names)
+	if maxResults > 0 && uint64(len(names)) > maxResults {
+		names = names[:maxResults]
+	}
+	return names, nil
+}
+
+func (l *snapshotTestLedger) LookupBox(rnd basics.Round, key string) (string, bool, error) {
+	value, ok := l.boxes[key]
+	return value, ok, nil
+}
+
+func TestDryrunSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ops, err := logic.AssembleString(`#pragma version 5
+global Round
+int 101
+==
+assert
+global LatestTimestamp
+int 5555
+==
+assert
+byte "k"
+app_global_get
+int 7
+==
+assert
+txna Accounts 1
+int 9
+asset_holding_get AssetBalance
+assert
+int 3
+==
+assert
+int 9
+asset_params_get AssetTotal
+assert
+int 100
+==
+assert
+byte "box"
+box_get
+assert
+byte "contents"
+==
+`)
+	require.NoError(t, err)
+	approval := ops.Program
+	ops, err = logic.AssembleString("int 1")
+	require.NoError(t, err)
+	clst := ops.Program
+
+	var appIdx basics.AppIndex = 5
+	var assetIdx basics.AssetIndex = 9
+	sender := randomAddress()
+	holder := randomAddress()
+	creator := randomAddress()
+	l := &snapshotTestLedger{
+		hdr: bookkeeping.BlockHeader{
+			Round:        100,
+			TimeStamp:    5555,
+			UpgradeState: bookkeeping.UpgradeState{CurrentProtocol: dryrunProtoVersion},
+		},
+		accounts: map[basics.Address]basics.AccountData{
+			sender: {MicroAlgos: basics.MicroAlgos{Raw: 1000000}},
+			holder: {
+				MicroAlgos: basics.MicroAlgos{Raw: 1000000},
+				Assets:     map[basics.AssetIndex]basics.AssetHolding{assetIdx: {Amount: 3}},
+			},
+			creator: {
+				MicroAlgos: basics.MicroAlgos{Raw: 1000000},
+				AppParams: map[basics.AppIndex]basics.AppParams{appIdx: {
+					ApprovalProgram:   approval,
+					ClearStateProgram: clst,
+					GlobalState:       basics.TealKeyValue{"k": {Type: basics.TealUintType, Uint: 7}},
+					StateSchemas: basics.StateSchemas{
+						GlobalStateSchema: basics.StateSchema{NumUint: 1},
+					},
+				}},
+				AssetParams: map[basics.AssetIndex]basics.AssetParams{assetIdx: {Total: 100}},
+			},
+		},
+		boxes: map[string]string{
+			ledgercore.MakeBoxKey(appIdx, "box"):   "contents",
+			ledgercore.MakeBoxKey(appIdx+1, "box"): "other app",
+		},
+	}
+	call := transactions.SignedTxn{
+		Txn: transactions.Transaction{
+			Type:   protocol.ApplicationCallTx,
+			Header: transactions.Header{Sender: sender},
+			ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+				ApplicationID: appIdx,
+				Accounts:      []basics.Address{holder},
+				ForeignApps:   []basics.AppIndex{77}, // does not exist
+				ForeignAssets: []basics.AssetIndex{assetIdx},
+			},
+		},
+	}
+
+	gdr, err := makeDryrunSnapshot(l, []transactions.SignedTxn{call}, 100)
+	require.NoError(t, err)
+	require.Equal(t, string(dryrunProtoVersion), gdr.ProtocolVersion)
+	require.Equal(t, uint64(101), gdr.Round)
+	require.Equal(t, uint64(5555), gdr.LatestTimestamp)
+	require.Len(t, gdr.Apps, 1)
+	require.Equal(t, uint64(appIdx), gdr.Apps[0].Id)
+	require.Equal(t, creator.String(), gdr.Apps[0].Params.Creator)
+	var addrs []string
+	for _, acct := range gdr.Accounts {
+		addrs = append(addrs, acct.Address)
+	}
+	require.Equal(t, []string{sender.String(), holder.String(), appIdx.Address().String(), creator.String()}, addrs)
+	require.NotNil(t, gdr.Boxes)
+	require.Equal(t, []generated.DryrunBox{{AppIndex: uint64(appIdx), Name: []byte("box"), Value: []byte("contents")}}, *gdr.Boxes)
+
+	// the snapshot replays through the dryrun endpoint without the ledger
+	var decoded generated.DryrunRequest
+	require.NoError(t, json.Unmarshal(protocol.EncodeJSON(&gdr), &decoded))
+	dr, err := DryrunRequestFromGenerated(&decoded)
+	require.NoError(t, err)
+	require.Equal(t, []transactions.SignedTxn{call}, dr.Txns)
+
+	var response generated.DryrunResponse
+	doDryrunRequest(&dr, &response)
+	checkAppCallPass(t, &response)
+	if t.Failed() {
+		logResponse(t, &response)
+	}
+
+	// the program reads the box from the snapshot, so it fails without it
+	dr.Boxes = nil
+	response = generated.DryrunResponse{}
+	doDryrunRequest(&dr, &response)
+	require.NotNil(t, response.Txns[0].AppCallMessages)
+	messages := *response.Txns[0].AppCallMessages
+	require.Contains(t, messages, "REJECT")
+	require.Contains(t, messages[len(messages)-1], "assert failed")
+
+	// applications with too many boxes aren't snapshotted
+	for i := 0; i < dryrunSnapshotMaxBoxes; i++ {
+		l.boxes[ledgercore.MakeBoxKey(appIdx, fmt.Sprintf("box%d", i))] = ""
+	}
+	_, err = makeDryrunSnapshot(l, []transactions.SignedTxn{call}, 100)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has more than 1000 boxes")
+}
@@ -19,6 +19,11 @@ package v2
 var (
 	errAppDoesNotExist                         = "application does not exist"
 	errAssetDoesNotExist                       = "asset does not exist"
+	errBoxDoesNotExist                         = "box not found"
+	errBlockStreamNotAvailable                 = "block streaming is not available"
+	errRoundNotRetainedForStreaming            = "requested round is no longer retained for streaming"
+	errRequestedRoundInFuture                  = "requested round %d is later than the latest round %d"
+	errRequestedRoundNotAvailable              = "the state of the requested round is not available on this node"
 	errFailedLookingUpLedger                   = "failed to retrieve information from the ledger"
 	errFailedLookingUpTransactionPool          = "failed to retrieve information from the transaction pool"
 	errFailedRetrievingNodeStatus              = "failed retrieving node status"
@@ -28,6 +33,11 @@ var (
 	errFailedToParseTransaction                = "failed to parse transaction"
 	errFailedToParseBlock                      = "failed to parse block"
 	errFailedToParseCert                       = "failed to parse cert"
+	errFailedToParseBoxName                    = "failed to parse box name"
+	errFailedToParseNotePrefix                 = "failed to parse the note prefix"
+	errFailedSearchingTransactions             = "failed to search for transactions"
+	errFailedSimulatingTransactions            = "failed to simulate transactions"
+	errIndexerNotActive                        = "transaction search is not available, as the indexer is not active on this node"
 	errFailedToEncodeResponse                  = "failed to encode response"
 	errInternalFailure                         = "internal failure"
 	errNoTxnSpecified                          = "no transaction ID was specified"
@@ -235,135 +235,199 @@ func RegisterHandlers(router interface {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x9/XfbtrLgv4LVe+fkY0XJ+eq98Tk9b32TtNfbNM2Jfbu7L862EDmSUJMAS4C21Kz/",
-	"9z0zAEiQBCXZ8ctuz7s/JRZBYDDfmBkMP09SVZRKgjR6cvx5UvKKF2Cgor94mqpamkRk+FcGOq1EaYSS",
-	"k2P/jGlTCbmaTCcCfy25WU+mE8kLaMfg+9NJBb/XooJscmyqGqYTna6h4Dix2ZY4uplpk6xU4qY4sVOc",
-	"vp7c7HjAs6wCrYdQ/iTzLRMyzesMmKm41DzFR5pdC7NmZi00cy8zIZmSwNSSmXVnMFsKyDM985v8vYZq",
-	"G+zSLT6+pZsWxKRSOQzhfKWKhZDgoYIGqIYgzCiWwZIGrblhuALC6gcaxTTwKl2zpar2gGqBCOEFWReT",
-	"448TDTKDiqiVgrii/y4rgD8gMbxagZl8msY2tzRQJUYUka2dOuxXoOvcaEZjaY8rcQWS4Vsz9mOtDVsA",
-	"45J9+O4Ve/bs2UvcSMGNgcwx2eiu2tXDPdnXJ8eTjBvwj4e8xvOVqrjMkmb8h+9e0fpnboOHjuJaQ1xY",
-	"TvAJO309tgH/YoSFhDSwIjp0uB/fiAhF+/MClqqCA2liB98rUcL1/59SJeUmXZdKSBOhC6OnzD6O6rDg",
-	"9V06rAGgM75ETFU46cej5OWnz0+mT45u/uXjSfLv7s8Xz24O3P6rZt49GIgOTOuqApluk1UFnKRlzeUQ",
-	"Hx8cP+i1qvOMrfkVEZ8XpOrduwzftarziuc18olIK3WSr5Rm3LFRBkte54b5hVktc1RTOJvjdiY0Kyt1",
-	"JTLIpqh9r9ciXbOUazsFjWPXIs+RB2sN2RivxXe3Q5huQpQgXHfCB23o/19ktPvagwnYkDZI0lxpSIza",
-	"Y568xeEyY6FBaW2Vvp2xYudrYLQ4PrDGlnAnkafzfMsM0TVjXDPOvGmaMrFkW1WzayJOLi7pfbcbxFrB",
-	"EGlEnI4dReEdQ98AGRHkLZTKgUtCnpe7IcrkUqzqCjS7XoNZO5tXgS6V1MDU4jdIDZL9v5/99I6piv0I",
-	"WvMVvOfpJQOZqmycxm7RmAX/TSskeKFXJU8v4+Y6F4WIgPwj34iiLpisiwVUSC9vH4xiFZi6kmMA2Rn3",
-	"8FnBN8NFz6tapkTcdtmOo4asJHSZ8+2MnS5ZwTffHk0dOJrxPGclyEzIFTMbOeqk4dr7wUsqVcvsAB/G",
-	"IMECq6lLSMVSQMaaWXZA4pbZB4+Qt4On9awCcPwko+A0q+wBR8ImwjMouviElXwFAcvM2D+c5qKnRl2C",
-	"bBQcW2zpUVnBlVC1bl4agZGW3u1eS2UgKStYigiPnTl0oPawY5x6LZyDkyppuJCQoeYloJUBq4lGYQoW",
-	"3H2YGZroBdfwzfMxA94+PZD6S9Wn+k6KH0RtGpRYkYzYRXzqBDbuNnXeP+DwF66txSqxPw8IKVbnaEqW",
-	"Iicz8xvSz6Oh1qQEOojwhkeLleSmruD4Qj7Gv1jCzgyXGa8y/KWwP/1Y50aciRX+lNuf3qqVSM/EagSZ",
-	"DazR0xS9Vth/cL64Ojab6KHhrVKXdRluKO2cShdbdvp6jMh2ztsy5klzlA1PFecbf9K47Rtm0xByBMhR",
-	"3JUcB17CtgKElqdL+mezJH7iy+oP/Kcs8xhOkYGdoaWggAsWfHC/4U8o8mDPBDiLSDkidU7m8/hzANC/",
-	"VrCcHE/+Zd5GSub2qZ67eXHFm+nkpJ3n/ldq37T76x1k2sdMSEsdGjq1Z8L7hwdnjUJCjmoPhr/lKr28",
-	"EwxlpUqojLB0XOA8Q0mh6dkaeAYVy7jhs/ZQZf2sEX6nF/9O79EpCaqIifuJ/sNzho9RCrnx7hu6rkKj",
-	"E6eCQFOGHp+1I3YlHECeqGKFdfIYOme3gvJVu7hV0I1G/ejQ8qk/W4Q6b6xfyegNvwncentqPFmo6m78",
-	"0mMEydqzMOM4a+P94s67lKWhdZk4/ET8aTugN1Ebfhyq1RBD/eljuOpg4czw/wAsaJz1PrDQnei+saCK",
-	"UuRwD/K65no93AQ6OM+esrO/n7x48vSXpy++QQtdVmpV8YIttgY0e+jsCtNmm8Oj4c5Iwde5ic/+zXN/",
-	"gurOuxdDBHAz9yESdQ6oGSzGmI0XIHSvq21Vy3tAIVSVqiI+L7GOUanKkyuotFCR8MV7N4K5EaiHrN/d",
-	"+91Cy665Zrg2HcdqmUE1i2Eez1lk0g0Uep+hsFOfb2SLGzchryq+HVDA7jeyO7fuITTpIt9795qVUCVm",
-	"I1kGi3oV2ii2rFTBOMvoRVKI71QGZ4abWt+DFmgna4FBQoQg8IWqDeNMqgwFGgfH9cNILJOCKBT7MaHK",
-	"MWtrfxaA3nHK69XaMHQrVYy07YsJTy1RErIVeuTo15zZ7Si7nI2T5RXwbMsWAJKphTtfuZMfbZJTWMb4",
-	"jIvTTi1YzZmgA1dZqRS0hixx6aW9oPlxlspmB54IcAK4WYVpxZa8uiOwRhme7wGUxsTAbdwJdygdQn3Y",
-	"8rsI2F88JCOv8IxpuQB9F5TuHAyMofBAnFxBRYez/1D6+UXuSr66HEmdOAt8LgoUXya5VBpSJTMdnSzn",
-	"2iT7xBYHddwE3EEgKTFJpYlHAgRvuTb2iC5kRi6jVTe0Dr1DS4wDPGpRcOafvTEZzp2inpS61o1l0XVZ",
-	"qspAFtuDhM2Otd7BpllLLYO5G/NlFKs17Jt5DEvB/A5ZdicWQdy4GFETwxpujsLxaAe2UVR2gGgRsQuQ",
-	"Mz8qwG4YPh4BBM8XzZvEOEL3OKeJWU8n2qiyRPkzSS2b98bQdGZHn5h/tGOHzMVNq9czBbi68TA5yK8t",
-	"Zm3iYM3Rt6OZWcEv0TaRp2ZjCUOYURgTLWQKyS7OR7E8w1GhCOwR0hEn2aUmg9V6wtHj3yjTjTLBHiqM",
-	"bXjEY39vI+DnbXToHpyW12C4yHXjmDRh9nYVisj3qyXQi6wgBWnyLfLqUlSFTWqROdP+N+v2ZG4Vm75p",
-	"xU9mrIJrXmV+xPC0FGwmETKDTVy78k5sJIMNE3Ggl83KwrDUp5xkOMEsKug2iZfmSgu5Smx2cJ9Ra5J6",
-	"DzSrpXAG7BoqB9cSKmd2jc+OJUb5DNouOHahwgVn7oIEfDW+rAXOUkvHkqj0AAWxEGmluM2NIlJ7G2QV",
-	"FByhoyydM/vja+5C9iv73KdqfYg85N34vJ5fRzVMw6LXayIWqto+EkOux6MtaBjbyCpXC54n6PBDkkFu",
-	"9obe8CABr2kk2muVDl/vgnxx8THPLi4+sbc4ls4WwC5hO6eMNUvXXK6gTSOE8mJPDbCBtA5NSw+NBx0E",
-	"Xay0C333KDidlErlSXPk7ac9Buamj/dLkV5CxlBfkYg5K/igSyFchD1EFtdNYuh6vfUuZFmChOzRjLET",
-	"yaAozdbFV3oeT29x+cDsWn9Dq2Y15ai5ZLTJ2YWMhzZshvsLZcpPs1uSbMnXFy5lJ9m9kNnIEXHi15Sg",
-	"wemi8rkzOnpGbwamb2DRA6ayUBwSQ/ie6qB4h8oio+NIa910vSgEFUMFw6aoOX1+enjCF2bG2DnpDjxg",
-	"abiCiudU6aF94FhoVgg8qOs6TQGy4wuZdCBJVeEWftj+16qli/ro6Bmwo0f9d7RBd9WdJa0M9N/9lh1N",
-	"7SNCF/uWXUwuJoOZKijUFWT2PBbytX1r77T/pZn3Qv40UMys4Ft7kvOyyHS9XIpUWKTnCvX6SvW8Tqno",
-	"CVQIHqCZ1UyYKZkywih565YurQBOot7TfcR8IrOin46mFLWdz0p2eUcz2PAUd8lJyWytR9Dw2dAJMqpM",
-	"wgmiIegdK7okgO7o8TvK3VCf2wDEbvjOeyGIDjoCdp3t990HyIhCcIj4n7BSIdWFqz/yRSq50GYApAtH",
-	"UAaoYciI0Zmx/6VqlnKS37I20JztVEUHJjpI4wpkY/2azlNrMQQ5FGAjRPTk8eP+xh8/djQXmi3h2hft",
-	"4cA+Oh4/tkKgtPliCeix5uY04kBRYB6taaTQes31erY3SE/zHhSbD6Y+fe0XJGHSmkwMbrxSankPuxXZ",
-	"JuqzwCa2U0c5Crc90Kzk21H3ukQAI9VaUF3mFMtXyx5HMqf/1qLEKdvKkq2BTlXq/374b8cfT5J/58kf",
-	"R8nL/zr/9Pn5zaPHgx+f3nz77f/p/vTs5ttH//avMedFG7GI533+zvUaIXWaYyNPpc3coudJAbutiwOo",
-	"5deGu8diSEyP+WBLhzDd+xhBBLoSRGziubO6LPPtPRgZOxGrwJ0xdCc8qu1TtQyLUh3n6a02UAwzDPbV",
-	"X0ZOPx98dGLApUrmQkJSKAnb6D0MIeFHehj1DUktjbxMBmLs3X70pgN/D6zuOocQ80vxS9QO1ND7pkT2",
-	"Hojfn7eXXArLcelkA3nJOEtzQaFzJbWp6tRcSE7BuZ7r3WMLH3IcD9e+8kPi8eFI+NZNdSG5Rhw2Ibto",
-	"0nEJkWD8dwA+aqvr1Qp0zxVnS4AL6UYJSYEWWotOMoklWAkVZYdndiR6n0ueU3T5D6gUW9Sma+6patB6",
-	"0zbThcswtbyQ3LAcuDbsRyHPNzSdP1V7npFgrlV12WBhJCoAErTQSVyRfm+fkj5121873UpXOOxjr2++",
-	"tgHwsMdq2hzkp6+dK3z6mvydNsc1gP2rJT4KIZMok+ERtRCSSqN7vMUeotfmGehRmy1zVL+QZiORka54",
-	"LjJu7sYOfRU3kEUrHT2u6RCiF8f2e/0UO2KvVFLy9JLqTyYrYdb1YpaqYu6PAPOVao4D84xDoSQ9y+a8",
-	"FHNdQjq/erLHHfsCfcUi6upmOnFaR997pZubOLah/ppNBsn/bRR78P2bczZ3lNIPbIGrnTqoTIyc2tz9",
-	"yk4AATdvL2jZCl88QL+GpZACnx9fyIwbPl9wLVI9rzVUf+M5lynMVoodMzfla244xZ160fSxO5QUE3TQ",
-	"lPUiFym7DE1xK5pjwdiLi4/IIBcXnwb55qHhdEvFA9y0QHItzFrVJnEZifHYVRvfo5ltLHjXqlPm5rYc",
-	"6TIebv6RoHtZ6iSIwsa3X5Y5bj9gQ83oJapXZNqoyitB1Iwujob0fadcxr3i1/7WSK1Bs18LXn4U0nxi",
-	"iYv5nJQlhXgpxvqr0zXIk9sSDo/TtiC2k8XO9rRx61DBxlQ8KfkKdHT7BnhJ1CdDXVAULc8ZvdaJN/tq",
-	"LZqq3cDOuGIAx61raWlzZ/Ytn0CJb4EeEQlpDGqnNh5+V3rhVH9XOTLZnckVzBGlUm3WCcp2dFcaWdxT",
-	"prnYtUKd7PPfWqwkCoG7A7cAlq4hvYSMkn8UH592XvclFs7CedUhtL22Zktm6W4FhUIWwOoy484H4HLb",
-	"L3LXYIyv7P8Al7A9V+3VjNtUtd9MJy6hlSDPjAkqcWpgjJBZQ7H1SbEe8V1+k5JOZclsXsdWI3u2OG74",
-	"wr8zLsjWQt6DEMeYokHDDn4veRVBhGX+ERTcYaM43xexfjSLxCsjUlHa/R+Wl3rfeQcn2WdcouZELftW",
-	"Y6DUo0rMDk4WXMcNCOATpAfKUL+aya9ko4o2Uc2o9YFj3EUOQUZVO8nmFTldftv2LvcYaHEugUq2Vt2D",
-	"0cVI6D6sXWmAuGoLAijkc4ih3ZuQRS7yNTuim3oRuG4OV3w0CzZ65+g0KMQJrrI2N4q8YusLw7S5XWa7",
-	"SvibR/66kb9jNJne6r7QdOJqQ2PkUJK8jAxyWHGX9KGqU19wYEF7oAMCIRw/LZe5kMCSWE0P11qlwtYB",
-	"tLrcrQHohD5mzAZ42MEzxNg4AJui5TQxe6dC2ZSr2wApQVB4nfu5Kc4e/A37o81tew/n3u51Q4e6oxWi",
-	"aXv9zpJxGIWaTqIqaeyE0BnF7JAFDI5UMRZF1TSMywyjPxpyIHOcdDRrchmL1qFXAcSGZ/614NjAHool",
-	"GvlHQdKkgpXQBtpzM0qrDwR93djFlTKQLEWlTUJH9uj2cNB3mpzB73BoXP10UMVsfwCRxbUPLXsJ2yQT",
-	"eR2ntlv3h9e47Lvm/KTrxSVsycgAT9dsQf0s0Ap1lscxO5a2dW07N/zWbvgtv7f9HsZLOBQXrpQyvTX+",
-	"JFzV0ye7hCnCgDHmGFJtFKU71EtQiTPULcGZzNYLUW3RbFfUYCBMt65mGtW8dqboXgJHd+cubNGbrWsL",
-	"2kEM71iMyAAvS5Ftemd4O+tI2o4c+Fs46tbjj6SiJs1kezAQnNdjZbwV+JiDJWlgM21jj0Gp437M9Ass",
-	"A4UQLiW0b0s1RBSyNlWi7cPVOfD8B9j+jGNpO5Ob6eTLjvwxXLsZ9+D6fUPeKJ4plm2PgJ0I3i1Rzsuy",
-	"Ulc8T1xgZIw1K3XlWJOG+zjKV1Z18eP3+ZuTt+8d+FS5CbxyBYu7dkXjyj/NrvBEHKtaPA8iI+St+rOz",
-	"dcQC4jd3icNgii8y7fhyqMUcc1nxagNlgSi64MoynlLbGypxMT27xR2xPSib0F57IraRvW40j19xkfuj",
-	"qId2f1HsnbRCp6r2S6OCYYntvaqbgXTHpaPlrj06KVxrRxOUwvb50UzJfmERupB0wiVWLfgWOcgGp4fK",
-	"SdZFguKX6Fyk8bCFXGhkDmljvjiY0eARZxRnrMVICkHWIpgLh+kDsmU9IIM1osikkNIO3C2Ua9BYS/F7",
-	"DUxkIA0+qlyhYUdQUS597fzQnMbr9N3ErlS/mf5LfAycasy7ICB2OxhhhDlyS8QfOP1Gm9A4/hAEBm+R",
-	"qApXHJjEHUkmxx+Om222f92NFIf9FIf6DxnD9t7Z38zRhy3WFtCRNaLNGUetxcm4paD7F4fbiNYkELih",
-	"MbA1sTzXKjJNLa+5tL3W8D2LQ/e2BhszwLeuVUWXFjVEs/RCJ8tK/QHxk+wSCRWpfXSoJHeR3p5FLoP1",
-	"lWgTlWm7aHr8hnCMsvaYJxc8ZN1E4oiEE5cHoXMq5vYBLi4tW9u+cJ30dVw4wpKTuZ2/FQ4H86BMJ+fX",
-	"Cx5rkYIOFcJ00iZpOqE4o5h/2VNBN3cYHO8F+Z5mrLA3/Uqo2gLl4a3yOzpHfy6WzyAVBc/jXlJG2O9e",
-	"AcvEStjmerWGoHubm8h2JbVc5Drg2TRYi5rTJTuaBv0hHTUycSW0WORAI57YEQuuwd40C2+fucIoA9Ks",
-	"NQ1/esDwdS2zCjKz1haxWrHGgbWXinzsewHmGkCyIxr35CV7SFF/La7gEWLR+SKT4ycvqSzF/nEUM3au",
-	"i+YuvZKRYvkfTrHE+ZjSHnYONFJu1ln01qltfTyuwnZIk331EFmikU7r7Zelgku+gng2t9gDk32XqElB",
-	"wx5eZGb7dmpTqS0TJr4+GI76aaQ0DdWfBcPdUSlQgIxiWhXIT21rNruon842AXXtkjxc/iGlWEp/16h3",
-	"YP66AWJry2O7pkTYO15AF61Txu3lbLou5S71O4U4G+lrA9VVfJFqhMDebrp32UOpZFKg7GSP2qLHgP+i",
-	"bV2U4Xm8fMPrrn71zu6pD3W1cJZkFLF1B7E80El3RnFdxffJa1zqHx/eOsNQqCrWo6XVhs5IVGAqAVdR",
-	"ie0X7zWeSWMuPOZjDsrfapFnP7clt712aBWX6Toae13gi7+0/RsbtFusR6++rrmUkEens7L8i5f5iFb6",
-	"TR26TiHkgWP7bc7sdnubawHvgumB8gsieoXJcYEQq90axKZoJV+pjNE6bZOFlhGGdxODlk+/16BN7B4l",
-	"PbD1XnTGRn/FdhxiIDOy9jNm7x0iLJ2bY2RlRVHn9hYSZCuoXPCnLnPFsynDec7fnLxldlXt7tDTfTfq",
-	"eLSyd1g7u+idrYKOLLe51DtWHnb4PLvrVXDX2lBLBm14UcYqf3HEuR9A5cVhvInMT4idGXttLb/2dsUu",
-	"0t7dZs1yTtcQT+B/jOHpmkxqxwCNs/zhrbo8V+qgZW3T/bNpqmKvIxvlu3XZZl1TptDvuRbatt2GK+gW",
-	"GzeV986l88XH3e1VtZSWU+L2acfNkLug3QNnk4o+JBWFrIf4W5oZreoqhdt2Ljujt6J3G/tt0Aa9au0t",
-	"p6ZXpP+cQsqlkiKlm4VBo+8GZNfC+5B47QGXMPvHZS/iTkIjwhVtvtaULTgsjrZj84rQIW4YMAqeIlEt",
-	"d9g/DfWKxoPgCox2mg2yqW+w585xQmpwTXKom3ugJ/E43s9dRtMqbZuMW7IRlR6OuCvf4TNyVYQrF7oU",
-	"ki6NO7S5yiR70qIOwwaPd8KwlQLt9tO9Kqg/4jszui6XwebTzHckpjlsCBm3bfMlw6lOfPbEZStw7Csc",
-	"yyhc3P7cKXO0i56UpVs0etOvoXCsReAogiNR8MSHIQPkNvOHs+1gt51pT7KnyGhwRUkTKMkODxhjpPXE",
-	"GzzUWo6yN9htuUH0eoqQETDeCgltv+yIgUijJoEIQ/I68p5OK26sC3iQTjsHnlOmJKbQtHGhoy+dqkdg",
-	"Qgnt0a8xTsa2UeSI4mgGtI4bl9umTTdyd+BMvKLvAzhEDts+klflnKiMCsp6jSBjigMVt2+h2jUAQzEY",
-	"+kT2dVNxKzm3sURjhfiZ0HgcKRZ5pITmdfMwaIZKtXqLLf0bu/g/vgOXWLtzoxp68db+5e6mMTnSPtFi",
-	"dUeqtO/fI1l6MhDSKMb9b1CthHeXBj0crOJprhZR+YDyranpUNEUxXd5lhRd9NDWdhnefWgd7xc8JdU4",
-	"UkT0ob01y632tbHBsVKidLTyjRtX1mo429W9yTb5jc1g85C2ubD9UE80MDCWe7SpR3w8ePswv2HghdHc",
-	"OxHqk9pDgH7wFTOs5MIFvlsRGWLW1dYNqx0PqbppCdzfhKtYo0liO7ljgdlBsjfEUkSww9KAPex52UGp",
-	"vYnS8yRVBfeM2sCE3hK1w6KHQ7dH+yCOqTUM93kwATq4HcH9IYhv9cIQuePibBaHiHO8oB9fJ31iEeKv",
-	"nAy1yVfTBp3e5G7dGNV/Hose2BPySKCqh9Na5Nk+4nbCju2Vbgqs/bL45nknevc1L5X/YhPyQ3Fz92tv",
-	"Y/j7RCDERPbaWTxYKggoHhBLdK9FIofUgy2tK2G2VLvjPU3xS7Qm+nuQrkO7++BFkwF1CTj7rSUXml41",
-	"o9vP43yvbMv6At1fcgUNNSd6s+FFmYOTi28fLP4Cz/76PDt69uQvi78evThK4fmLl0dH/OVz/uTlsyfw",
-	"9K8vnh/Bk+U3LxdPs6fPny6eP33+zYuX6bPnTxbPv3n5lwf+2zQW0Pa7L/+TOi8kJ+9Pk3MEtsUJL8UP",
-	"sLV3rZGN/S1unpIkQsFFPjn2P/03L2GzVBXB5zTdrxMX6Z+sjSn18Xx+fX09C1+Zr6hdZmJUna7nfp1h",
-	"L6j3p02A1ib8iaI29oasQER1rHBCzz68OTtnJ+9PZy3DTI4nR7Oj2RNqllKC5KWYHE+e0U8kPWui+9wx",
-	"2+T48810Ml8Dz83a/VGAqUTqH+lrvlpBNXPX2fGnq6dzH9+Zf3ZJ7ptdz7pVBu7aSvBCcO9x/rnTbzUL",
-	"56VbgfPPvgIjeGT7ic8/U/ho9PcuGJ/NRmQ3c9/3yL3h+vLOP7eNsm+sdOQQO/n7Bn7tcGrMR98P0fZX",
-	"FAifVxS621e9oe5phlTFt141TcPDzyR//E/6UdFPvW8sPT06+k/2tZjnt9zxTn+2c/6L9Jr4G8+Yzy3R",
-	"2k++3tqnkm6ZoEJjVmHfTCcvvubuTyWyPM8ZjQyqQYak/4e8lOpa+pFoXeui4NXWi7HuKAX/KQDS4Xyl",
-	"qWFoJa7wUP+JOtLGknkjyoU+y3Nr5ULfGvqncvlayuXP8RGmp7cU8D//jv+pTv9s6vTMqrvD1alz5Wz5",
-	"wty2z2s9PH9jc3iNsevNjulkd9RhDylOKuH6kSuBsNNGrsQ26WaV2ZiIb6/kC6uCdvtdnf3BTdq5ff0D",
-	"bPU+BX6+Bvarmz4R2a9U/knJhylTFfuV53nwG7XJ8W77LK7v22uSe7+y2gpoDKwlgC9GpaJT13UYDdkl",
-	"+Au1FgedBOUwp98241vC6Je2bc+yUIM5FnxydHQUKwbqw+ziNxZiKv69VkkOV5APST0GRO9e7a7v0o5+",
-	"uWd4HTo8d0e4zn/GvbkhPfqZ3u4d39tA91rJB4Zdc+E+fhD01LGfciqE8V+wtkVCroCwsRHxrx4nOOXu",
-	"j6J/qfH+83URvtmh7PS6Npm6luOKi24X8dyV51LBbBNuMIr5CRpNNWP+k6T51n9Tm3EqV1K16X7q3rfK",
-	"6DVLb5o5rYSkBUjKaRVbh86DKk/36ZyhEjxzkL2zXxrq6b3oF38tjHG5jwn9l/LS0NHYSSvfWqXz9xxZ",
-	"Ht1V+yW1hDA0DGkY4PncFar0frXp5ODHbkP0yK/z5mpX9GE/UBN76uIoflAbIQ0jjkSpJtb48RMinGqI",
-	"HRHbANrxfE4p3LXSZj5BhdMNroUPPzU4/uwp73F98+nm/wYAAP//UWHRAXWKAAA=",
+	"H4sIAAAAAAACA+19C3PcNtLgX+FpvyrbuaEkv7JrV6W+U+w8fOskLktJ7s7yxZwhZsQVh5zlQ9Ikp/9+",
+	"/QIIkABnJM066/pctVuxhng0Go1Gv/HH3qxcrspCFU299/yPvVVSJUvVqIr+Smazsi2aOEvxr1TVsypb",
+	"NVlZ7D3X36K6qbJisTfZy/DXVdKcwb8LGKRrg/0ne5X6Z5tVCoZqqlZN9urZmVomOHCzXmFrM9JVvChj",
+	"GeKIh3j1cu965EOSppWq6yGUPxX5OsqKWd6mKmqqpKiTGX6qo8usOYuas6yOpDM0iwARUTmHn53G0TxT",
+	"eVrv60X+s1XV2lqlTB5e0nUHYlyVuRrC+aJcTjOYXKBSBiizIVFTRqmaU6OzpIlwBoRVN4TPtUqq2Vk0",
+	"L6sNoDIQNryqaJd7z9/t1apIVUW7NVPZBf1zXin1u4qbpFqoZu/9xLe4OUAYN9nSs7RXgn2YuM0bQPec",
+	"VgNrXMAERYS99qMf2rqJprDuInr77Yvo8ePHz3Ahy6RpVCpEFlxVN7u9Ju4O39OkUfrzkNaSfFHCXqex",
+	"aQ8A0PzHssBtWyWrVZ7NEly398gcdd8joNvAYtxBPESVFY1a0M4456Hr5zks/Y9JXSv/uT7CLyPg6Y7b",
+	"A4Y9PCB1P08VIFVtST7ceKf0Y8//pxIQ7NDsbFUCHj37EtHXiD972a3VfYzdGgCc9ivEVIWDvjuMn73/",
+	"4+Hk4eH1X94dxf9H/nz6+HrL5b8w427AgLfhrK0qVczW8aJSCR3ss6QY4uOt0EN9VrZ5Gp0lF7T5yZJu",
+	"JekbYV/m8hdJ3iKdZLOqPAJIgBEJGQFXTWCoSE8ctUWOHBVHE2qPYIBVVV5kqUoneFFcnmWwF7Ok5iGo",
+	"HTDvPEcabGuVhmjNv7qRw3RtowThuhU+aEH/vsjo1rUBE+qKuEE8y8sajmS54SbVlyNQXWTffd21Wt/s",
+	"Xo1OYIE0OX5guYBwVyBN5yBsNLSvMB38HulbFNA0j9ZlG13S5uTZOfWX1SDWlhEijTbHufLx8IbQN0CG",
+	"B3nTEpYLeEXk6XM3RFkxzxYtLBdQoAAYvp7hb5AMYaXl9B9q1uC2/8/jn36Myir6ATCTLNSbZHYewQaW",
+	"aXiPZVKfsPGPusQNX9aLFQzklyzybJl5QP4hucqW7TKCkaYALuyXvh8AZ5Vq2qoIAcQjbqCzZXI1nPSk",
+	"aosZbW43rSNTIill9SpP1vvRq3kEg3x1OBFwgBzgQKxAvoKlRc1VEZQnce7N4AEdt0W6hbjV4IZZt2a9",
+	"UrMMKDeNzCgjkMg0m+DJipvB0wmBFjh6kCA4ZpYN4BTqykMzeHTxCxywhbJIZj/6WTgXfW3Kc5AqNIOL",
+	"pmv6tKrURVa2tekUgJGmHtcEihKkCRhvnnlo7FjQgdyD2wh7XYqAMyuLJgFulSLnJaBhOOZEQZisCcf1",
+	"ruEVPQWu/uWT0AXefd1y96Fnb9dHd3yr3aZGMR9Jz72IX+XA+sUmp/8Weqo9d50tYv55sJHZ4gSvknmW",
+	"0zXzD9w/jYa2JibgIEJfPDBkkQDHUM9Piy/wrygG6QjQnlQp/rLkn36AgTKYBH/K+afX5SKbwU8BZBpY",
+	"vYofdVvyf3A8PzturrxKw+uyPG9X9oJmjgINh+jVy9Am85g3Jcwjo3XbWsXJldY0btoDoNAbGQAyiLtV",
+	"gg3P1bpSCG0ym9N/ruZET8m8+n2PlUMfTpGA5aIl+4XYNd7Kb/gTHnnFOoGlHR7Q9Qm/dQD9B5xxGPsv",
+	"B51R54C/1gcyLs4IU1oK4e5n6nry+sJacFbw7lDTCeuEu4cHR/VCQoJqD4av83J2fisY4MpYqarJeB+n",
+	"OM7wpNDw0ZlKUrj/QK9M9juliuWsAL1Tx++pH2lJMJPH3EX/SPIIP+MpBGlFxDcUXUGCg/+Vlk0sRYmP",
+	"7xGeCRuQJFpGSxbyIhTObgTli25yZtCGo74TtLzvj+bZnW9Yroyoh14E7VB5tXMagTF9MMDPA/oor1S9",
+	"C/rAcYjbNGpZbwHfS4GspP0X9CVVBcxngGQaexsk4wKRw9Wk8oCOZ5/diaWgH03L6nZHs3fmiqgzO0QJ",
+	"jmoUDSQyF0nUtF3FQooe1YUb9AbqjNLDG8zGU394H8YcLMA9/C/AQo2j7gIL7kC7xgJQZZarHZD+WVKf",
+	"DReBsuTjR9Hx90dPHz767dHTL5EkoeMCtGEQIBqg0ftyhcPK1rl6MFwZ3aUgGPlH//KJVlbdcX3j1GVb",
+	"zQD61XAoVoLZS8DNImzX27XrIRZdtBMWDMDbHNYThZydtyFiew+C+rJag2K6g31RVVVWHp2FVtaUszKP",
+	"L0CUzUqP+emNtIikBd4jrDf1fmdoo8sEWA7MTep0i06Hfd82oJ68NZPkoU+uig43o2yS1+tZncy7zZ64",
+	"yNfaWQ2KfhXDIFGqpu3CvkOieVUuQblLqSNdaK+zW8p77vZdJlUBaNseXzjvr9xpI67M4FsTao6srduJ",
+	"H+HcAfts2noH/LMbrMM4UpuNZ7gSWrhhQD9OkRViYz9nDRjcydJHBsrGZtbNGQtJU4Uq3CxpF2dNhLpP",
+	"6aPfrmOczBibMQk0dcA+YQxL3IqnY2NuXoH0t4aJQWArp2IEEPMELTIh22GjeZPw9Q4so7j64YLGxWa4",
+	"uJUzByNmjlwpAG5aXhZ5mSDnRVMsnDPWee8OPeznDG4EAF+czRsXoNvxQWxGdpnWQfCbWYDhR/OkuiWw",
+	"Tdkk+QZAqY0PXCOxi91nCPV204+RX39ymwjRyq05AqoHyFBy1agQCm+EkzHa6wMlFOgjPziXFqllxYTV",
+	"m6y553ygna8VkCEMXKk8WZP3Hw3vea7yTSBr8v2Xkpw5I7ekOBDo/A5VERZP4CMuukiKslbARdPaO1ie",
+	"1E28iU9iI0eixRVYh9vHGmnggNnwNXxjw11WpKRI8n7TPNSHpggDHJRTcORftIgyHHuGF1NRw72i5ZW6",
+	"Xa1At1Cpbw1o7Q3P9SN81XMh3ZqxjVAEx6it1aaRQ1iyxhdk8UoYQUBNbDk2lu3h4shJhxfv2otKB4gO",
+	"EWOAHOtWFnZtp1IAELQ6mJ5EOPCLSznGkwXCeVOuVnj+mrgtTL8Qmo659VHzc9d2SFzo+tNXUVoqnL3R",
+	"MAnkl4xZdieC+B4JHCD+n6MwQEoFWxiHMONhjGtg4ioeo3w8lsfYyj4CGw5pQJ+TgAVrtt7h6NGvl+iC",
+	"RLBhF0ILDiiXb9gvdtLZjHcgJb5UICTltZEEjfOtm4X8dP1wL9RN0HNbNPkaaXWeVUt2ddMNXOvfWM5M",
+	"ZRZ26nbHD/5fKRCbU91iqNg7sT6gA135uWviWEyhGXqTfUDPzcwZHDLtiHasOvveg86uffQjA35ijhnY",
+	"dKkZVz/crW2RyQV2qSqBaw7qFUsKjfaZw2Wv/epjcIyhQky2t0ECdvVPy8DxbtW+0Ar6gAdxiRETCUdM",
+	"IFJ7C4QdXyYIHfnu5doPzzmG7Bf8XQdwaMeZTbv+cTW9BjmMIVHgy+QTxmujh0Sb6tEKo4BfBxayyMsp",
+	"SHCoYak4VXmz0diKmpt6SS2v0SVUsK7swfzp6busuTo9fR+9wlautz2r67bTIexDwrqZulKz1r5Pergz",
+	"OrIHP8kl+eFwe23MUEwJXpc3Mt0f00gWb/sVxjl6OdS5gT+XsyEuBzjJU0TJa2xLmq2KztX6gIJ6QDpO",
+	"ioXqPK13wMsW7iR3K4erWfg3NV/wAhY7gbNzR68b5YSy/d/7//kcQ9iS+PfD+Nl/P3j/x5PrB18Mfnx0",
+	"/dVX/8/96fH1Vw/+8z+8JqneIldwx8fGdNZ3fw8EjP5JO89m57BGvKGIqYrcc889kzhJdB+ZWm0CBC7P",
+	"1lppgHsYKOzBfhQdFZFarpq1GH97Mm5v8uJeMzb/Fc2athSrBByUFrl/WvjtrhzpdEcuqocZ550cpXzH",
+	"qXiQ8YmAK92EQdyaIQxkOIuoGIptzG7fUTxs4uxylpIC2skzdTtdZhQUazWb4F2p45SGRrSsAco6odsC",
+	"rQBac8aIv1o7EIFulhnawup2NlMqfX5axA4kwERk4vvdP/kiOm0PDx+r6PBBv0/doIIiBg8+A/2+X0WH",
+	"E/5E6IK/T/dO9wYjwcVcXmjV36Zr7rVx2P9mxj0tfhpcxaABrFl312cR0DCfZ7OMkZ6XeJMvyp6eUZT0",
+	"BagQwFMoWAH2mwkJL4RR0s94X7oDuOeVl3dhVvWMipoZCk/I7XR0iks7NTBq+BesMiEms2YZ0NDZUOwF",
+	"vSG2B/D6x0ZmFGdw7VwCtzx3Q37OBqlx+E56JilXLunIdX+ztjZAhheCbY7/EUyJu55JHKoOVsyzuhkA",
+	"KQYoigQwBOm5dPaj/122cNLp/K7gOjbaPBwKVJHJdIIz0AWt5xTZvMOQyoHC2YxJX774or/wL76QPYeB",
+	"5upSB29jwz46vviCD0FZN3c+AT3SvHrlEZnJa4i3qSc3CN14+xt9rDTuVq4Ta+hXL42bEQ9TXdMVgwuv",
+	"ynK+g9Vm6ZVXZgHlyrNS2TmyCd9Da+Q6qFCtEEBP1K6qznPyCcLwLkVGwv/OshUO+XFFOrhmpn6n9Pfw",
+	"K0IqnOOqeFVwBA+KrWSiXYvlp5x/bLh7JIabqTFvLWkbonvj25AMRQnabKK542zZ5nC2d0B242ZzVmi0",
+	"gUFbzinfB67mVbSEo4iG8YkEESvUe2BiFHabm+ovvgtgnmR5W6lwuAUJgCqBVVpgcWT/FL8gmhG+Uqye",
+	"CYqhBcWrg6acFDMVXWRlTsiq96NfUYCCozRBeYD7mGDpSrKc9m9qQbe1/LLWWLXAhctaMIfmkQW6UAOH",
+	"GcOuBe64g3trL/MPWfE19/5Fd/Zeu1dFLMveemhNk67Z0Ov6n+zRBsUinQ4R9qtlpO7tKBChWrFJGxUh",
+	"fSgmvhwL+0Q6lld3ene9k+5IBNG9zTnmxbPAbx9lXk/N2NKXCFrq8/UOpEYeCGhWzES14+Gq+SvAZGUb",
+	"yVVSr2vY5qFXnrv+FiDtt9rAPKDUssizAg4ukMnamwsMX3+gj15lj+SMQGeS+EJ9+wZ4B/4eWO482+zq",
+	"XfFLu20dkTcm92kHm98ftxeQYedZkalC5SsgzlmekfcTJgeZcdacFgn5V3q6dI8sUFWqyVg+V94cav39",
+	"W6W0LwxaUo4aWqgpaoEMAjGjCQanCDC4Sk4LR1c10nEBp5USe0Dcwf8syPwsJoSBsHxa/KrDaUl3RFG2",
+	"zfMJa3PGfwA69TRLU50DYIu2pwVMpD/k5SUsCNdggcpOPYRDXWQzUbC8dmD2sYX9ky90E79D1OOvlKFg",
+	"uwiXxkflvZ68m2RtTd0ucL96uh30OtUbuGnf9rklKt9zzK6CbfldVWU0bRtX26HkGTYmcHAK4bScw0Iw",
+	"NxI9v3BNgXiHw2mLpD5hhWouy+rcYCFgBgetv87q2C9HfsdfSZyU5Z+JaEkXDn/ubpaPK/9q2H2pHQI5",
+	"6CJsCYB/oLrXxaEMYP9onn68Kb1EhsKPlrh6tBXdR6VVE9CDLqJFdh34wFWBhASyUYZ537cih/6F4HIu",
+	"3+Hk49IjI2dnep5cvfj3PpPjoowxap+k171F1py1032Qnw60SeQAGph/p4mCy4i+pQfJKjtAE/PBxcMN",
+	"6ukd2H3k4fbuHXVMKbc70DVk18bEZJQgDbEZ2xz698nPCAw2cixicGZzSi7X0QCU/qvtp0BK65BaTK50",
+	"yi8cQvJzLUS4ShZZwWIaqwbLsurVLkGGt6RqAGzIRlxNdMqi+Eooi9GUE5G8RyuxeEHpxigd+HwcPevX",
+	"VtL4C+0zHDWw+WWlLmrgplavExcxgBTymgpWiK7keqt3njUiA/vA6s9pYnP037AF97775iQ6EA5Q3+OE",
+	"Qh7aygTzWEel9I5jqMdDxQUxOKMSDdUvMa89w+/PTwv0WB5Mkzqb1QdtrSpRyvYXZfQ8kiFfQhvy7/QU",
+	"9lB5HUtFj1btFNCInkgfRYXc3Ken75DxoDuwH+c3lGdlKn/oAE0QI8WDeBVLrEfYR9T50Whk9rKPzTqJ",
+	"ZGzmdBJLIuMHwhlWqzq2XLr+5QP54fItMqwj6kROZgwyqvRti1ew+Ktwf38sRadHd5Rk6bfok/mwTFbv",
+	"AJD3USy+laPVivzF5LD9IJca0iQAvb3RpAOxG8yna9PCWc8BxlMlMaZc+13BjUpWtPskES5JeQUxjbo5",
+	"TmGdskFDdQsY9d9ZcNw4d5EWd8y9dGiKfwn0ibaQ2uCt1zmtb7tfONT3ZY5Eduvtssbw7lLbnMV4tr2r",
+	"qpHE9c6YQhpsKBIlBF0zeAik5ghmp58pdCdTWBX5oSdOdx28KqKUZh1ZzWVCOEWRctnJ5YDlQ1ZpIsJm",
+	"Uqz7ScWwvkYrS28VsJ6TskuFv0kWMSpMHCoUI82EDipRqiXkILHax1aHG/U2XzQ/uphWq4gjZjiERJPF",
+	"c0MXuk/4ILPktYND7CMKg4YRegcMeBDBxB9AwS0WiuPdifS90RoJ3GqzbMXr385I/Mbpg4Nsuly81wmm",
+	"s7m3xoCpe5kYN44xg827HQq/4H7gGerHieuZ2HsnYiVVxRPCnebKilWr5WSjTGuhimtnhUDzUwmoq92t",
+	"rsFwMWKLD2cSdAkijAm1JNfKNhftRiM4UpGOhs7cEIcM583VRRKMNgnWeHhlhThbpYNMBQfN2PqHYWKq",
+	"eXDBQV3pQZd30DUdAJyb1GdArw+lOfm2oyxIykhhqYtEgisogcr1tNyrrQ1COH6az9F6GcW+aGk48+Us",
+	"YxdCx8tlDoVC6BdRxHbXaOsRfGRsgS2GNhg4An7yxibSmwBZqIzs/Ykem/zZ1t/KrxlxDk15FXPqqFek",
+	"mV5NEdfejBpKZPURxb3avkvgL5gEpWkON6fgvgD1G5jC8GhwLIf8VVYTzqhfiG27QG1h6uiKYYrEv1Ey",
+	"H7LTjq9MugowTNlDjXCy5+XSIaXJaRVxk6kaWC98G4TcemgTHdqpa6AfklBi57KJz31+BRS0FJ3MY93N",
+	"0qSi+xmm5a8fWBbpSi3Q/tbZrJCBaSPsx7UbXmBhIdD5MacAzWXe5WGjb2uSj7/Fpn6O7KAq4hJ1WcC0",
+	"TdMCduI0y1v/bsu8f3+J0/5oiL5up3SiYCdVAlNPyYiCF7MzPbYZmZqTKEYX/JoX/DrZ2Xq3oyVsihNX",
+	"Zdn05vhEqKrHT8YOk4cAfcQx3LUgSkfYixXpPFrYlR3tFLu9P2ZIGRymG4fOBzkvj+RdiyX7j66CMyw4",
+	"icKqSDjMoA6cAbg0svSqZ9bgUQMRQ6TT3EB3YSXIEwWzZwbbgAHLhOHLGcNyiLkVYm+JEZwHMMir2YyZ",
+	"fjaPxRDsqbJaF3EeIgpJm4SBTbjCJPy/q/Uv2JaWs3c92bubFcSHaxlxA67fmO314pn8SKwVO0bNG6Ic",
+	"PlYlICcWW1GINKGRkCY116alj8zq/BaJk2+OXr8R8ClNSCWVJISMrYrarT6ZVaG06UuYOLGMRSTAa7mU",
+	"BTFr8005K9u+pDOaHFkOuZgQFx+vznZoHUWxN8397uyN1iMxc/ISR8ydamWsnZ2RgI2droEzuUiyXGvn",
+	"GtrNGVi34gpOCtddDaV2CtNO2c3gdPtPR0ddG3iSPddIHc4ll5rFumn9mGYUIUnpJ1JFr9xUib1+yJyg",
+	"H+mMcQ0A+C05xbRG4ijYDI6NI2ocEEZxxDYLeFWKNrPGwmb1FupbD0hrDi8yyco2grtpKf7Htsj+2cLF",
+	"lmJ8OnyqJMfBOah4LnWi5vA69SeFysCSF2qGv4uMgUOFpAsCYlzAsI3unpRkrXDqhRpvAf5g2Upv4Luz",
+	"ZxxciSN+N6EPoWaOtDlzjed2Sf8h/0PC4PKvm98T0JacMwY0MIf3fYDgbXEUviko2Xf7O6K7Eghc+zLg",
+	"dJwkr0vPMG1xmRRc7hv7MQ6lN8bqaR/iZVlRhYzab1XK6nhelb8rvyY7x43ypF0IKklcpN5bxKR2Vpnu",
+	"IQeNXxuOIGmHJDnrY+T6VgMnnKjc8iZQHpm2+UEjGpBLkzuRIv7DYccNHPD43eEQmAcBhXlyOU18VTpR",
+	"oEKYjjq/lWOdxPI30lnvQm3SJ4X2LBeYaZtxWQmAocuNGtaMuqVw9GmRfAoksoQpvMhPZ0ODZZotMq7v",
+	"DltgFRCXgfhhDKYiKcLOnsEONbAhhxPriQLZjTS7yOoMJC1q8ZBboE+FyxrYpQ4kKBEjR85qav5oi+Zn",
+	"gFI4ftCFEQtoNQIsJ21rd8BUNZdYOueQ2j18Ft0nR0idXagHiEWRRfaeP3xGEWD8x6HvspOHHMb4SkqM",
+	"RQe+++mYPEE8Bl5SMuq+t8QJPxQUZmEjp4m7bnOWqKVwvc1naZkUyUL5HdzLDTBxX9pNMhr28FKk/HQE",
+	"TFauJfVkOL9qEuRPgbBQZH8MhqTHYo4cPTlRLpGeuurgPKkejt+hkIq9Gi79kbxOK53m3FOYP66BmO9y",
+	"36rJN/gjfHbROkHHD4W0W+XhhCHuB+p9qurCP0kV2GB9b0pfDAkt4iWenfRBF3Bs0V/QEeR3t4R8P+ND",
+	"bytq4ShxELGtg9jE4km3RnFb+deZtDjVz29fy8VAcYrD5PGOG8olUSkYWl14T2w/OtBIJua60Jj3CShY",
+	"xXlYcVtKHBtrpoT+eTS0EFLxA651KkNNIrecrOdcDX0l2mY3tNnjFz08/dEff3/zBH09LqHaTzxpAFVW",
+	"QWkv0lLz3fKTJRF82hZ1X7tFdzX6brca7yraLE9/6fIbeiW24QjNzrzG9il2/K17M8LAw8fMm1BxlhSF",
+	"yr3DMfP+TTN5zzX0j3LbeYARbdm2X/Wbl9tbXAe4C6YGSk+I6M0afJvQwaob320CtzBWPKJ5uhJu3ckf",
+	"1sGg+tGe8GDPM2duzQjp42SjehKDti15lBVWUbxg0SO/uSXDFCGeIi7nc6/pA2fib74MajRi21nUOyk6",
+	"svOqRMPkBQe7XkSEipTo6tRezmw+aX3FsGlh0cOi9MRDSPdZYLBZu9IVPTlyQ2oqe/wCoaJittOL9gXG",
+	"nyqsyoGqxi2u5wAnDNwc+3e4Or7e3XXRIWiy+erQJa//2YLs6yu+Qx84eJmso7g5vDUAYUp62n7ExWoQ",
+	"5U4EP+lHktabRrlKsToKm+15sycRjoP+hIhnraXUHhVJoXLbCy585LCjcAb6XdPFdbzoLoIvAzE89OaE",
+	"eaJPDkcXueUeD2UKry/IGINmaSzFvHXsZHdifSW9KFmGakvCrixXvowubHGiG1DamO3LINXG3r/96CVr",
+	"lbXWWSQjp+P+ZjqRY+n6wX80TQJQp3JUt7hdt69kry/A2nqRyzxuZKrDcpUtgFuK2XMte8n9v8xqflUQ",
+	"CyY5F6jJqJQ91Ell7vJgGwqmZb/uM5IffRu0a+D4qtTuDi9kPcTfkEfyqwk3Lex/TL0CtQPcwQZPcXHx",
+	"DvMUjn4tFjSSsoCzgwVzfMxTXijcxhe4RW2hPtPtcv6Jh3gOl/dtAhMSJ1gMvlagWbUgLnD/8lfcVKYO",
+	"/rOhp/AGF+1Evz8hNkIQDFVlSiM4SWp4I/fjYjZczTckI4r0D6jC3+I3UoMzic49zwqqhSZok0BgtuLR",
+	"A2oNlwqPFlj915fjXb/DPvtUBQYgfr+vH1yjMdg9ictmX/xwqCPtmRdPOLZ9gW0jckV2PztZBTwp9JVJ",
+	"w6+VeFUPrDMRQvCYGGAh14xvjzZCbqMhNXTjI6Fh9RGgCrUiSWFAGIGKit9ItZdSqqFFHMrmTTvOCg8Y",
+	"rzEW2ehGngti5r0SaGPovAb6QXsMJtyap6EjnrzwPoYGh4XdEncdql+RBFFCa9RzhLexe0clwDhMg05H",
+	"xBQdfSiQui1x5wU9fyqIHL6KQnKfiHkpxW/33knxMQ5k3PGsBJaZFEm+rjNvhBFMMIuwVaRb+SQnAW1i",
+	"8r01rAgZefqhE5qP6nYKHLgBTNZbi1TfgMa0foOC7wuAIyBF8nLwysFiZz6GTS8P4R5SnbaZGl0HeUIq",
+	"FsZ1AFU3uJeNGCCk9JF7qW6shGq6szAe07kOVE9iaR1VhFzNG18RX41/Ahw6YOPfVm3zb1T61SzY7NdN",
+	"xJlQ8mSa1WgvXU5zT4zvS/PResWK8isAhfhfX1HE8Aok8ucWZZs5zIc63liN2lg1OJvFmHVzh6NNg3SZ",
+	"OH/Wse7WcptzPVjELc50B8HtDnXX/9anureMf+cT3a12p0daF8L+N1lluUJVh45XwIRJDfhkhe8YqU/h",
+	"PPOG2Vks5E3bdKG83ZFl1mzRpcpkujySk4pvn9W0Upfd2zk4BXoTaPwtYtpsfuoTd3qneFgZzgQyOHgR",
+	"iWV2DtLMqiE+ovX1LpaTgOYkP+IyCufiUIXaZ8j2zf86qbB6TH9TUNbCh6w7xFiD2xmUUkBUczd6FgF3",
+	"YD86mnLABjoK87Jc4UMmpvhTjbEdU9Q1QwZx7BJvIS9rGmAHBWlIOH87lSiWBHnaSuHrEVJhIc+WWAfZ",
+	"vE1L0KBbexwgfK+etiSmLfEUIMVxOJWO0UL7hwCgTziaqnVJFRPxiTeJzJMkQgu3HrzhCEDKl9agzlL2",
+	"b6tqaNRRjgtq21Q/Rx4U6sPl10vMznsuUNU9LGFTiIxtRMlKsbEH+Lns3BaRZqxkdLN7jx6qcHZZlkEZ",
+	"aFbyTNUUujpK/cox3TQm3989S/qa8oRkmLKe40bx8HuoE1JDA8lAb7s6fQlruhzjE0oJmgUz2JJGMnZh",
+	"lWNPfoQzUTmemHNOCQq/gz8UQ8whxPh50Hs7G83A4kVjjyJUB6cPAfq7znwBtpdJAFsnSQ4xKzlyYXfK",
+	"qGhnNri/CMk8C7pH7Fc1Pe5NfCYoX0fLDNjEucTb8V1ItNJFsbuL8RszxFhH8r8cWptHUBgeg0JGW4zH",
+	"8DPOUKlb6y+LK4DysfQRko+hrfwM7VZgVm3ugbEwUUSKHx2lZptcXtKIuJSYRcYOvK+KrbdsvcQYcdVd",
+	"Xe43sXxatuuWpWSVw82B0kGvSvDN8gelk1+g04C4yf4T94WMjeV/xyvt3WEOx0g5nIGzES3vORe0de5G",
+	"G60cHNo5+s2mJPU4tjfV6jdgTqx0S40cF1U+IgrXK/ZL4W0zK3Ugl5eAhhSyI9GenKySlNCT3Dc/QRUM",
+	"YBhELejZOXIBZS63LrXjXdpJNEMoTOGW6b3bmQkGd5tHJ7MTszYIFefORcilkXq+lrJSO74QLSPzDS/E",
+	"YcrZtsujddA9jyHfg3VuvQEObgO43wbxnTQ3RO5YOZBthDB/hRnsTlIgI0TXQBqeuY8mw/E6ZQyZ17fr",
+	"v4T86+xDDkSN9XCKAWabNteJAeyK2VKU229TWMFHN7doCDgdanjcpODjTexw/U0gxHjW6kxuTWVF920R",
+	"2CfdPGF89PgWNM6aNWVOal9M9pu3IgUWD66Ik5+pBF/bMvknkv7AZUolMHhhWre1Nlx8V+JDU+VyieYW",
+	"knMbepXmm6sEn5+Wc/HVvelf1eO/PUkPHz/86/Rvh08PZ+rJ02eHh8mzJ8nDZ48fqkd/e/rkUD2cf/ls",
+	"+ih99OTR9MmjJ18+fTZ7/OTh9MmXz/56j+LaAGQGVMdBPd/7X1RzOj568yo+ocqt3dasMmAqXPwTyViX",
+	"FYX7hxyYyyTLoZn89D/0CcNCvN3w+tc9ibPeO2uaVf384ODy8nLf7nKwoJcx46ZsZ2cHep7hI0BvXpkg",
+	"K46Kox3l6BR6b2KvI4Uj+vb2m+OTCPrtdwQD3w73D/cfsrFQFbBU+Okx/USn54z2/UCIDf4NDQ8AdTlZ",
+	"XfCPJUZ5z/Sn+jJZAKvZl/qq+NPFowMdAXHwh0hT12Pf3BwvKRpkdbAK8UEn+2nV9HrLZgdTilPctqmq",
+	"rcZU/g6aSV6dNSW/on4AZ1tR9r778x8Uz3Ed+t1d9R/NFc6t39eRHvLi78Ef3RPc13wY8YF2z7GUh+K6",
+	"5vQAHCheFeVySRFdnUSS1e4j84aY8K2kvSPs9cI8R27VCnn+bqj+0kCRHolOHJJTdyCcmTqeB8qisstX",
+	"GI7utO/4+jvg0u//eDh5eHj9F+Tb8ufTx9dbxvO86F4zPzZMecuG7ykvhSxWdE4eHR7e4YmFo8J+Wp02",
+	"yRjEPDZkef05qNLLVvUGigwyNmRk9IYPvOf85IYrHhWfHSOhp9by1wkwMQlHpbkffry5XxVUUgj5Z8T3",
+	"AzR5+jFX/wrNKmgNpZZW6p+nxnhxXpSXhW5J1mG4Wau1Psa1wxQi2Wy6MhL0YL0DYssu0Mn1nl4+9SmZ",
+	"AeYCitMtmMsx9vrMXD4Wc6FN2gVzcQfaMXN5dMMD/umv+DM7/dTY6TGzu+3ZqYhynPFwwK86dRKeLs83",
+	"rFnnCs8hniyaVXSfnGmFunwgWRM8rKf+oYn/Rmshp9fw8wLamCqz7g949lsZ1Cm1CZpSvYmBo4nwgwwP",
+	"EvQHyvWnaMAJOo0/JHlu/UZl4rWWsO/n952RNszsBwfUBxa+HCOVB8gpLa/b4kWGBRUZj5WkEVkC+zDI",
+	"vnv1CMY0YMNpq9Yd3PwWjM3BhAQfHh4e+szUfZjFXMQQk4v3soxzdaHy4VaHgOgVURxgbGT6E/cFH7v2",
+	"pa3me6iOXjyeqq4cpg8yTj1zCjreBLqXJT58fplk4v22rNJY2IOSJAGGORofOaZJssXNHeEDqihjHNIH",
+	"S+civ+vl/em9Vns9wuzqs7ZJgYOGGReVkgJmzLUYqDqCsW5gUosMYDjVfvSTBAfka/MgTkL5QxiM4byA",
+	"o0tF9x7lNo8ZLLKCJqBTTrOwXymxUvprBVuXepjgsUD2Iww55Hs++hEY/efed+jvSktDQWN0r0xp8QUn",
+	"vrrr/U5RVGfLvHZn4imij8d17ZXDMgOJ7J4GwiPGsSDv9Uxp+6d1WxNV4LtmCvd/lrT4uD1pLd6iPaZj",
+	"nMz4JMVsyPFPaFUEp1byXhY9Tpdjuh7WklOYsYSL7go10CJ7xdFnRj/yZK174ILGxWa4uJUzh1gVs1yF",
+	"wEWSl5RcqlqOLyRnO4Ee9nMGVzm+TDH6fHC3APOkgQmQC+0yrYPgN7NgkZ55Ut0SWK7IPg5ovzqIAVee",
+	"iTQP7A2h3m76MfIblKW3iDCha0+exeMLcYV2xBAKb4STMdrrAyUU6CM/fGWgI7WskFjBDAvWWx9o52vM",
+	"vqQ3KPKEa38jN85zKokwCrIm338pyZkzckuKA+0Rr46gZoqZuLjoIilKua780ZwoTm3ikyTU2eqz4rpQ",
+	"+nB/zOcfWf4LuRlxZP+7prwGU8jfpPKCylViLqO3kBG+3Bec60f4qudCuh0+nMqS+KaRQ1iyxjfJvY2p",
+	"aZE0lnBND/0NF0diNUVahB8m1EB0iBgD5Fi3srB7ab2hHQAkqztEmwcWXMqxKorVTbla4flr4rYw/UJo",
+	"OubWR83PXdshcdmyXor5mVhFTNoL5JfmaUfoiw/TCBwYH01v0mCQDOuVQ5jxMMZ1hs93jz7QDs2OsZV9",
+	"BDYc0oDxiI5/73lQ53D06NdLdEEi2LALoQUHLVmfmjWpryrdzGLUTTPoN2Y++k71nvm2hFtWqjqZ/ADV",
+	"TjQZ840fk5ZiuRW1wN7LakgwBo4NO9SPXoGCfcYraG6eN41kHKvIRm3Oz71aPyIkhwpJYagL4VTfltXX",
+	"OMs2NiB5ibVkFR3u0yy39HAj4XvsPZokw9aesaKJOzatf9ZdPusun3WXz7rLZ93ls+7yWXf5rLt81l0+",
+	"ed3lz/HCR3Gs+bQuFI4Viwt6EfMCGSThOPrsrafpH3+86Y9VdZHNVHSioG+VVFm+jn4uTN21HeuClpKg",
+	"U5xAQcJDrZN42UMv7/ZpLbFRSX4gZbtGQgSoq10CjPKEUOLIE6p5f9VMdIkHt96kSRriHAdkPNjg8aPo",
+	"+Pujpw8f/fbo6ZfkeaSXFp2293V57rpZ5+qBRIUbFx/W8yioNltCBU9IUEq07kn1ARct1ohG9kzCFb1B",
+	"/Q01f4nublSkkDypOlnrUU4x9+OFIGeDbkrVDrC0Kwz2AUf7MHFUYsHbMln1MqhRLkoirPLhxgN8mCd5",
+	"rT6EPMo8Hgy3hU+ZGMXXZbruET9u2wHtoEv2XUZEViSVp36Wx9nbpw1YAdbQk4JwA7X7eqe+bn8B/SGd",
+	"bSKxQBF5b4DPGJmHy7DhhvlLvGj/uKGTvq5/7Uu4sK9NKd0sAG/lolecQIF7FEm24Z96l0UEkRy5jm//",
+	"+RfXk8MnG7i04SjUEWUvYUz/XlFqt7xn9JZ4zzlxiQkegbTFapH4VBTT4lWMjRYKhDHe4ngKbCh2eJh7",
+	"F3FJt/BV9M2VmrV4dO2M+Pv1A3mAmUpT2uZIb9Ff6/UBReN1jwZ+7OuFiwNtDF/G53bkfuXLol8SispV",
+	"CTqeU1UZ5ip09vEhFJ05yzmgajXpsmx7afgTyQOecLWSSYRF4heoyHMZw6jOy6aeSLorf4Q/sY6RFOiG",
+	"o8xKf+j2sgpe3f76uv0RcqtUX3O1NjPWsl6spMTi7YYbDYXCKEtKUecoS6zUg9aV5Qr+pU3YqHZQRje9",
+	"Qy512ztWT5LBbm9QU1FzWLdh66LIti7s1H3SvzOeKI1bKiKnXBLZn/TaL9y7eQu6spSbkiR5vd4SuoGC",
+	"ucNd1dsuUd3Gjg9Li2EQTyHLXtnK//LB2//Vb9U3EoTov6TY/9Z4ecf+xsu1srhb73Y9qItkVZ+VzSaN",
+	"z51d6rHpa17Tsa45aJ5OIlukXZy/q8oBG4DPyzXlc5IStFl5Ymfu1/r5z6zikvXy4hw/E8SP/uBv+A5D",
+	"d4dV/DZdVwwe+ww4EFnJbMmhpvL3PAzXu+TnsxhcLrOOGlo2pwIUil4xsm2U5qk5W72+lSixG0niWG/t",
+	"BoniLS57sFP6Maiqe+kPEdBtoKCCf5qXeV5eUvwzLztQq7/S7yYH47XHg7S3lgKuYtFY76zOYlkcfGJX",
+	"q3eDc0AOpGRFNSulsBvhb+ea7p0Ej6P+GTVF9fCtrbYwleg6SviTFcAfkhz3C1B+JAlvNu4/+WuLklVs",
+	"erF4iTxa6FgIP33F0TojDqvx3C1RoVRaO+/1bL7mkkss2+Pccrm4+e5izNRWkYzVSUAhPtHKSSGYBYJa",
+	"lBS70PXaUCVqQT1Kaqn3z+U++4WN8LjRcPj5rTpX65NyEr3Iy1q9xboQKBCflEgn3yr151wlr3VS68gF",
+	"8jrrFWIlO+YHU8n3Q8Tv+X6wCPaDe0Xgu30kMYijldVO54ka9C7Woatjye61jvpVgdE77/YMEHuOZmdJ",
+	"9eGMrte6ShyfRmTzuFkIg7pa5TglK2B+mLCg7DR3wdq+oDKZITHHC1jg3hA2LB2oK4u6ZZMTvIlxA4Bm",
+	"4NqtFiSVJAVd512ZtSNdZs2LzuQq7ieX3ewu/ggGZdyLf7k9WQoObq9+2nUdN6meZvCtLbRUuPCzefaz",
+	"Ihm+Zel6GpyZscvqxtZZK3O1yz12y+7o59+9H/s1eXxfpYZNoJEuc6g/d7Wy7NpTdFeZqlPv3iPLord8",
+	"5RrrSik9PziglwrOQE44IHbrllmyP7436W/6QVOTBnf9/vr/AzMsCCsr5QAA",
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
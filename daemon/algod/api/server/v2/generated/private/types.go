@@ -75,6 +75,12 @@ type Account struct {
 	// *  Online  - indicates that the associated account used as part of the delegation pool.
 	// *   NotParticipating - indicates that the associated account is neither a delegator nor a delegate.
 	Status string `json:"status"`
+
+	// \[tbxb\] The total number of bytes used by this account's application's box keys and values.
+	TotalBoxBytes *uint64 `json:"total-box-bytes,omitempty"`
+
+	// \[tbx\] The number of existing boxes created by this account's application.
+	TotalBoxes *uint64 `json:"total-boxes,omitempty"`
 }
 
 // AccountParticipation defines model for AccountParticipation.
@@ -233,6 +239,23 @@ type AssetParams struct {
 	Url *string `json:"url,omitempty"`
 }
 
+// Box defines model for Box.
+type Box struct {
+
+	// \[name\] box name, base64 encoded
+	Name []byte `json:"name"`
+
+	// \[value\] box value, base64 encoded.
+	Value []byte `json:"value"`
+}
+
+// BoxDescriptor defines model for BoxDescriptor.
+type BoxDescriptor struct {
+
+	// Base64 encoded box name
+	Name []byte `json:"name"`
+}
+
 // BuildVersion defines model for BuildVersion.
 type BuildVersion struct {
 	Branch      string `json:"branch"`
@@ -243,11 +266,40 @@ type BuildVersion struct {
 	Minor       uint64 `json:"minor"`
 }
 
+// ConfirmedTransaction defines model for ConfirmedTransaction.
+type ConfirmedTransaction struct {
+
+	// The round in which the transaction was confirmed.
+	ConfirmedRound uint64 `json:"confirmed-round"`
+
+	// The offset of the transaction within the block.
+	IntraRoundOffset uint64 `json:"intra-round-offset"`
+
+	// The raw signed transaction with apply data.
+	Txn map[string]interface{} `json:"txn"`
+}
+
+// DryrunBox defines model for DryrunBox.
+type DryrunBox struct {
+
+	// Application the box belongs to.
+	AppIndex uint64 `json:"app-index"`
+
+	// Box name, base64 encoded.
+	Name []byte `json:"name"`
+
+	// Box value, base64 encoded.
+	Value []byte `json:"value"`
+}
+
 // DryrunRequest defines model for DryrunRequest.
 type DryrunRequest struct {
 	Accounts []Account     `json:"accounts"`
 	Apps     []Application `json:"apps"`
 
+	// Boxes are the contents of the application boxes the programs may read.
+	Boxes *[]DryrunBox `json:"boxes,omitempty"`
+
 	// LatestTimestamp is available to some TEAL scripts. Defaults to the latest confirmed timestamp this algod is attached to.
 	LatestTimestamp uint64 `json:"latest-timestamp"`
 
@@ -287,17 +339,58 @@ type DryrunState struct {
 
 // DryrunTxnResult defines model for DryrunTxnResult.
 type DryrunTxnResult struct {
-	AppCallMessages *[]string      `json:"app-call-messages,omitempty"`
-	AppCallTrace    *[]DryrunState `json:"app-call-trace,omitempty"`
+
+	// Static cost analysis of the application program, for the program and each of its subroutines.
+	AppCallCostAnalysis *[]EntryPointCost `json:"app-call-cost-analysis,omitempty"`
+
+	// JSON lines trace of the application program, when requested with json-trace.
+	AppCallJsonTrace *string   `json:"app-call-json-trace,omitempty"`
+	AppCallMessages  *[]string `json:"app-call-messages,omitempty"`
+
+	// The return data left by the application program with retdata_put.
+	AppCallReturnData *[][]byte      `json:"app-call-return-data,omitempty"`
+	AppCallTrace      *[]DryrunState `json:"app-call-trace,omitempty"`
 
 	// Disassembled program line by line.
 	Disassembly []string `json:"disassembly"`
 
 	// Application state delta.
-	GlobalDelta      *StateDelta          `json:"global-delta,omitempty"`
-	LocalDeltas      *[]AccountStateDelta `json:"local-deltas,omitempty"`
-	LogicSigMessages *[]string            `json:"logic-sig-messages,omitempty"`
-	LogicSigTrace    *[]DryrunState       `json:"logic-sig-trace,omitempty"`
+	GlobalDelta *StateDelta          `json:"global-delta,omitempty"`
+	LocalDeltas *[]AccountStateDelta `json:"local-deltas,omitempty"`
+
+	// Static cost analysis of the logic signature, for the program and each of its subroutines.
+	LogicSigCostAnalysis *[]EntryPointCost `json:"logic-sig-cost-analysis,omitempty"`
+
+	// JSON lines trace of the logic signature, when requested with json-trace.
+	LogicSigJsonTrace *string   `json:"logic-sig-json-trace,omitempty"`
+	LogicSigMessages  *[]string `json:"logic-sig-messages,omitempty"`
+
+	// The return data left by the logic signature with retdata_put.
+	LogicSigReturnData *[][]byte      `json:"logic-sig-return-data,omitempty"`
+	LogicSigTrace      *[]DryrunState `json:"logic-sig-trace,omitempty"`
+	Logs               *[][]byte      `json:"logs,omitempty"`
+
+	// The opcode cost of the application program. When the protocol pools the budget of the application calls in a group, this is the amount the program drew from the pooled budget.
+	OpcodeCost *uint64 `json:"opcode-cost,omitempty"`
+}
+
+// EntryPointCost defines model for EntryPointCost.
+type EntryPointCost struct {
+
+	// Largest opcode cost of any path from the entry point, including the subroutines it calls. Absent if a loop makes the cost unbounded.
+	Cost *uint64 `json:"cost,omitempty"`
+
+	// Program counter of the branch or callsub that can repeat without limit, when the cost is unbounded.
+	LoopPc *uint64 `json:"loop-pc,omitempty"`
+
+	// Most values the stack can hold beyond what it held at the entry point. Absent if a loop can grow the stack without limit.
+	MaxStackDepth *uint64 `json:"max-stack-depth,omitempty"`
+
+	// Program counter of the first instruction of the entry point.
+	Pc uint64 `json:"pc"`
+
+	// Set for the subroutines of the program, reached by callsub.
+	Subroutine bool `json:"subroutine"`
 }
 
 // ErrorResponse defines model for ErrorResponse.
@@ -327,6 +420,46 @@ type EvalDeltaKeyValue struct {
 	Value EvalDelta `json:"value"`
 }
 
+// LintWarning defines model for LintWarning.
+type LintWarning struct {
+
+	// source line of the instruction the warning is about
+	Line uint64 `json:"line"`
+
+	// description of the problem
+	Message string `json:"message"`
+
+	// program counter of the instruction the warning is about
+	Pc uint64 `json:"pc"`
+
+	// name of the lint rule
+	Rule string `json:"rule"`
+}
+
+// MinBalanceViolation defines model for MinBalanceViolation.
+type MinBalanceViolation struct {
+	Address string `json:"address"`
+
+	// The balance of the account, in microalgos.
+	Balance uint64 `json:"balance"`
+
+	// The minimum balance of the account, in microalgos.
+	MinBalance uint64 `json:"min-balance"`
+
+	// The index within the group of the first transaction after which the account was below its minimum balance.
+	TxnIndex uint64 `json:"txn-index"`
+}
+
+// SimulateTransactionResult defines model for SimulateTransactionResult.
+type SimulateTransactionResult struct {
+
+	// The opcode cost of the application program run by an application call transaction.
+	OpcodeCost *uint64 `json:"opcode-cost,omitempty"`
+
+	// The transaction with the apply data it was evaluated to.
+	Txn map[string]interface{} `json:"txn"`
+}
+
 // StateDelta defines model for StateDelta.
 type StateDelta []EvalDeltaKeyValue
 
@@ -374,6 +507,9 @@ type AddressRole string
 // AfterTime defines model for after-time.
 type AfterTime time.Time
 
+// ApplicationId defines model for application-id.
+type ApplicationId uint64
+
 // AssetId defines model for asset-id.
 type AssetId uint64
 
@@ -447,6 +583,14 @@ type BlockResponse struct {
 	Cert *map[string]interface{} `json:"cert,omitempty"`
 }
 
+// BoxResponse defines model for BoxResponse.
+type BoxResponse Box
+
+// BoxesResponse defines model for BoxesResponse.
+type BoxesResponse struct {
+	Boxes []BoxDescriptor `json:"boxes"`
+}
+
 // CatchpointAbortResponse defines model for CatchpointAbortResponse.
 type CatchpointAbortResponse struct {
 
@@ -469,6 +613,9 @@ type CompileResponse struct {
 
 	// base64 encoded program bytes
 	Result string `json:"result"`
+
+	// JSON of the source map
+	Sourcemap *map[string]interface{} `json:"sourcemap,omitempty"`
 }
 
 // DryrunResponse defines model for DryrunResponse.
@@ -480,6 +627,11 @@ type DryrunResponse struct {
 	Txns            []DryrunTxnResult `json:"txns"`
 }
 
+// LintResponse defines model for LintResponse.
+type LintResponse struct {
+	Warnings []LintWarning `json:"warnings"`
+}
+
 // NodeStatusResponse defines model for NodeStatusResponse.
 type NodeStatusResponse struct {
 
@@ -489,6 +641,9 @@ type NodeStatusResponse struct {
 	// The number of blocks that have already been obtained by the node as part of the catchup
 	CatchpointAcquiredBlocks *uint64 `json:"catchpoint-acquired-blocks,omitempty"`
 
+	// The number of chunks of the catchpoint file that have already been downloaded and verified by the node as part of the catchup
+	CatchpointAcquiredChunks *uint64 `json:"catchpoint-acquired-chunks,omitempty"`
+
 	// The number of accounts from the current catchpoint that have been processed so far as part of the catchup
 	CatchpointProcessedAccounts *uint64 `json:"catchpoint-processed-accounts,omitempty"`
 
@@ -498,6 +653,9 @@ type NodeStatusResponse struct {
 	// The total number of blocks that are required to complete the current catchpoint catchup
 	CatchpointTotalBlocks *uint64 `json:"catchpoint-total-blocks,omitempty"`
 
+	// The total number of chunks the catchpoint file is downloaded in, when it's downloaded from several relays in parallel
+	CatchpointTotalChunks *uint64 `json:"catchpoint-total-chunks,omitempty"`
+
 	// The number of accounts from the current catchpoint that have been verified so far as part of the catchup
 	CatchpointVerifiedAccounts *uint64 `json:"catchpoint-verified-accounts,omitempty"`
 
@@ -553,9 +711,15 @@ type PendingTransactionResponse struct {
 	// Application state delta.
 	GlobalStateDelta *StateDelta `json:"global-state-delta,omitempty"`
 
+	// \[itx\] Inner transactions issued by the application being executed by this transaction.
+	InnerTxns *[]map[string]interface{} `json:"inner-txns,omitempty"`
+
 	// \[ld\] Local state key/value changes for the application being executed by this transaction.
 	LocalStateDelta *[]AccountStateDelta `json:"local-state-delta,omitempty"`
 
+	// \[lg\] Logs for the application being executed by this transaction.
+	Logs *[][]byte `json:"logs,omitempty"`
+
 	// Indicates that the transaction was kicked out of this node's transaction pool (and specifies why that happened).  An empty string indicates the transaction wasn't kicked out of this node's txpool due to an error.
 	PoolError string `json:"pool-error"`
 
@@ -599,6 +763,24 @@ type ProofResponse struct {
 	Stibhash []byte `json:"stibhash"`
 }
 
+// SimulateResponse defines model for SimulateResponse.
+type SimulateResponse struct {
+
+	// The state of the accounts the group modified, after evaluating it.
+	Accounts []Account `json:"accounts"`
+
+	// The reason the group would be rejected, other than minimum balance violations. When set, no other results are given.
+	FailureMessage *string `json:"failure-message,omitempty"`
+
+	// The round whose state the group was evaluated against.
+	LastRound            uint64                      `json:"last-round"`
+	MinBalanceViolations []MinBalanceViolation       `json:"min-balance-violations"`
+	TxnResults           []SimulateTransactionResult `json:"txn-results"`
+
+	// Whether the group would be accepted into a block.
+	WouldSucceed bool `json:"would-succeed"`
+}
+
 // SupplyResponse defines model for SupplyResponse.
 type SupplyResponse struct {
 
@@ -639,6 +821,17 @@ type TransactionParametersResponse struct {
 	MinFee uint64 `json:"min-fee"`
 }
 
+// TransactionSearchResponse defines model for TransactionSearchResponse.
+type TransactionSearchResponse struct {
+
+	// The latest round the node has indexed. Transactions of later rounds are not returned yet.
+	CurrentRound uint64 `json:"current-round"`
+
+	// Used for pagination. When more transactions may match the search, provide this token with the next parameter to get them.
+	NextToken    *string                `json:"next-token,omitempty"`
+	Transactions []ConfirmedTransaction `json:"transactions"`
+}
+
 // VersionsResponse defines model for VersionsResponse.
 type VersionsResponse Version
 
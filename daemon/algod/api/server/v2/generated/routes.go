@@ -25,10 +25,19 @@ type ServerInterface interface {
 	GetPendingTransactionsByAddress(ctx echo.Context, address string, params GetPendingTransactionsByAddressParams) error
 	// Get application information.
 	// (GET /v2/applications/{application-id})
-	GetApplicationByID(ctx echo.Context, applicationId uint64) error
+	GetApplicationByID(ctx echo.Context, applicationId uint64, params GetApplicationByIDParams) error
+	// Get box information for a given application.
+	// (GET /v2/applications/{application-id}/box)
+	GetApplicationBoxByName(ctx echo.Context, applicationId uint64, params GetApplicationBoxByNameParams) error
+	// Get all box names for a given application.
+	// (GET /v2/applications/{application-id}/boxes)
+	GetApplicationBoxes(ctx echo.Context, applicationId uint64, params GetApplicationBoxesParams) error
 	// Get asset information.
 	// (GET /v2/assets/{asset-id})
-	GetAssetByID(ctx echo.Context, assetId uint64) error
+	GetAssetByID(ctx echo.Context, assetId uint64, params GetAssetByIDParams) error
+	// Stream committed blocks and their state deltas.
+	// (GET /v2/blocks/stream)
+	StreamBlocks(ctx echo.Context, params StreamBlocksParams) error
 	// Get the block for the given round.
 	// (GET /v2/blocks/{round})
 	GetBlock(ctx echo.Context, round uint64, params GetBlockParams) error
@@ -46,10 +55,19 @@ type ServerInterface interface {
 	WaitForBlock(ctx echo.Context, round uint64) error
 	// Compile TEAL source code to binary, produce its hash
 	// (POST /v2/teal/compile)
-	TealCompile(ctx echo.Context) error
+	TealCompile(ctx echo.Context, params TealCompileParams) error
 	// Provide debugging information for a transaction (or group).
 	// (POST /v2/teal/dryrun)
-	TealDryrun(ctx echo.Context) error
+	TealDryrun(ctx echo.Context, params TealDryrunParams) error
+	// Capture the ledger state a transaction group needs for a dryrun
+	// (POST /v2/teal/dryrun/snapshot)
+	TealDryrunSnapshot(ctx echo.Context, params TealDryrunSnapshotParams) error
+	// Check TEAL source code for common security mistakes
+	// (POST /v2/teal/lint)
+	TealLint(ctx echo.Context, params TealLintParams) error
+	// Search for transactions.
+	// (GET /v2/transactions)
+	SearchForTransactions(ctx echo.Context, params SearchForTransactionsParams) error
 	// Broadcasts a raw transaction to the network.
 	// (POST /v2/transactions)
 	RawTransaction(ctx echo.Context) error
@@ -62,6 +80,9 @@ type ServerInterface interface {
 	// Get a specific pending transaction.
 	// (GET /v2/transactions/pending/{txid})
 	PendingTransactionInformation(ctx echo.Context, txid string, params PendingTransactionInformationParams) error
+	// Simulates a raw transaction or transaction group.
+	// (POST /v2/transactions/simulate)
+	SimulateTransaction(ctx echo.Context, params SimulateTransactionParams) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -75,6 +96,7 @@ func (w *ServerInterfaceWrapper) AccountInformation(ctx echo.Context) error {
 	validQueryParams := map[string]bool{
 		"pretty": true,
 		"format": true,
+		"round":  true,
 	}
 
 	// Check for unknown query parameters.
@@ -107,6 +129,16 @@ func (w *ServerInterfaceWrapper) AccountInformation(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
 	}
 
+	// ------------- Optional query parameter "round" -------------
+	if paramValue := ctx.QueryParam("round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "round", ctx.QueryParams(), &params.Round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.AccountInformation(ctx, address, params)
 	return err
@@ -171,6 +203,7 @@ func (w *ServerInterfaceWrapper) GetApplicationByID(ctx echo.Context) error {
 
 	validQueryParams := map[string]bool{
 		"pretty": true,
+		"round":  true,
 	}
 
 	// Check for unknown query parameters.
@@ -191,8 +224,108 @@ func (w *ServerInterfaceWrapper) GetApplicationByID(ctx echo.Context) error {
 
 	ctx.Set("api_key.Scopes", []string{""})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApplicationByIDParams
+	// ------------- Optional query parameter "round" -------------
+	if paramValue := ctx.QueryParam("round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "round", ctx.QueryParams(), &params.Round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetApplicationByID(ctx, applicationId)
+	err = w.Handler.GetApplicationByID(ctx, applicationId, params)
+	return err
+}
+
+// GetApplicationBoxByName converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApplicationBoxByName(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"name":   true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "application-id" -------------
+	var applicationId uint64
+
+	err = runtime.BindStyledParameter("simple", false, "application-id", ctx.Param("application-id"), &applicationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
+	}
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApplicationBoxByNameParams
+	// ------------- Required query parameter "name" -------------
+	if paramValue := ctx.QueryParam("name"); paramValue != "" {
+
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Query argument name is required, but not found"))
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "name", ctx.QueryParams(), &params.Name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetApplicationBoxByName(ctx, applicationId, params)
+	return err
+}
+
+// GetApplicationBoxes converts echo context to params.
+func (w *ServerInterfaceWrapper) GetApplicationBoxes(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"max":    true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+	// ------------- Path parameter "application-id" -------------
+	var applicationId uint64
+
+	err = runtime.BindStyledParameter("simple", false, "application-id", ctx.Param("application-id"), &applicationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
+	}
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetApplicationBoxesParams
+	// ------------- Optional query parameter "max" -------------
+	if paramValue := ctx.QueryParam("max"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "max", ctx.QueryParams(), &params.Max)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetApplicationBoxes(ctx, applicationId, params)
 	return err
 }
 
@@ -201,6 +334,7 @@ func (w *ServerInterfaceWrapper) GetAssetByID(ctx echo.Context) error {
 
 	validQueryParams := map[string]bool{
 		"pretty": true,
+		"round":  true,
 	}
 
 	// Check for unknown query parameters.
@@ -221,8 +355,67 @@ func (w *ServerInterfaceWrapper) GetAssetByID(ctx echo.Context) error {
 
 	ctx.Set("api_key.Scopes", []string{""})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAssetByIDParams
+	// ------------- Optional query parameter "round" -------------
+	if paramValue := ctx.QueryParam("round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "round", ctx.QueryParams(), &params.Round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetAssetByID(ctx, assetId, params)
+	return err
+}
+
+// StreamBlocks converts echo context to params.
+func (w *ServerInterfaceWrapper) StreamBlocks(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"format": true,
+		"round":  true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params StreamBlocksParams
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := ctx.QueryParam("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// ------------- Optional query parameter "round" -------------
+	if paramValue := ctx.QueryParam("round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "round", ctx.QueryParams(), &params.Round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetAssetByID(ctx, assetId)
+	err = w.Handler.StreamBlocks(ctx, params)
 	return err
 }
 
@@ -400,7 +593,8 @@ func (w *ServerInterfaceWrapper) WaitForBlock(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) TealCompile(ctx echo.Context) error {
 
 	validQueryParams := map[string]bool{
-		"pretty": true,
+		"pretty":    true,
+		"sourcemap": true,
 	}
 
 	// Check for unknown query parameters.
@@ -414,16 +608,103 @@ func (w *ServerInterfaceWrapper) TealCompile(ctx echo.Context) error {
 
 	ctx.Set("api_key.Scopes", []string{""})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TealCompileParams
+	// ------------- Optional query parameter "sourcemap" -------------
+	if paramValue := ctx.QueryParam("sourcemap"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "sourcemap", ctx.QueryParams(), &params.Sourcemap)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sourcemap: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.TealCompile(ctx)
+	err = w.Handler.TealCompile(ctx, params)
 	return err
 }
 
 // TealDryrun converts echo context to params.
 func (w *ServerInterfaceWrapper) TealDryrun(ctx echo.Context) error {
 
+	validQueryParams := map[string]bool{
+		"pretty":     true,
+		"json-trace": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TealDryrunParams
+	// ------------- Optional query parameter "json-trace" -------------
+	if paramValue := ctx.QueryParam("json-trace"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "json-trace", ctx.QueryParams(), &params.JsonTrace)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter json-trace: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.TealDryrun(ctx, params)
+	return err
+}
+
+// TealDryrunSnapshot converts echo context to params.
+func (w *ServerInterfaceWrapper) TealDryrunSnapshot(ctx echo.Context) error {
+
 	validQueryParams := map[string]bool{
 		"pretty": true,
+		"round":  true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TealDryrunSnapshotParams
+	// ------------- Optional query parameter "round" -------------
+	if paramValue := ctx.QueryParam("round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "round", ctx.QueryParams(), &params.Round)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter round: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.TealDryrunSnapshot(ctx, params)
+	return err
+}
+
+// TealLint converts echo context to params.
+func (w *ServerInterfaceWrapper) TealLint(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty":  true,
+		"mode":    true,
+		"disable": true,
+		"max-fee": true,
 	}
 
 	// Check for unknown query parameters.
@@ -437,8 +718,197 @@ func (w *ServerInterfaceWrapper) TealDryrun(ctx echo.Context) error {
 
 	ctx.Set("api_key.Scopes", []string{""})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params TealLintParams
+	// ------------- Optional query parameter "mode" -------------
+	if paramValue := ctx.QueryParam("mode"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", ctx.QueryParams(), &params.Mode)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter mode: %s", err))
+	}
+
+	// ------------- Optional query parameter "disable" -------------
+	if paramValue := ctx.QueryParam("disable"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", false, false, "disable", ctx.QueryParams(), &params.Disable)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter disable: %s", err))
+	}
+
+	// ------------- Optional query parameter "max-fee" -------------
+	if paramValue := ctx.QueryParam("max-fee"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "max-fee", ctx.QueryParams(), &params.MaxFee)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max-fee: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.TealDryrun(ctx)
+	err = w.Handler.TealLint(ctx, params)
+	return err
+}
+
+// SearchForTransactions converts echo context to params.
+func (w *ServerInterfaceWrapper) SearchForTransactions(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty":                true,
+		"address":               true,
+		"tx-type":               true,
+		"asset-id":              true,
+		"application-id":        true,
+		"note-prefix":           true,
+		"currency-greater-than": true,
+		"currency-less-than":    true,
+		"min-round":             true,
+		"max-round":             true,
+		"limit":                 true,
+		"next":                  true,
+		"format":                true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchForTransactionsParams
+	// ------------- Optional query parameter "address" -------------
+	if paramValue := ctx.QueryParam("address"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "address", ctx.QueryParams(), &params.Address)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter address: %s", err))
+	}
+
+	// ------------- Optional query parameter "tx-type" -------------
+	if paramValue := ctx.QueryParam("tx-type"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "tx-type", ctx.QueryParams(), &params.TxType)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter tx-type: %s", err))
+	}
+
+	// ------------- Optional query parameter "asset-id" -------------
+	if paramValue := ctx.QueryParam("asset-id"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "asset-id", ctx.QueryParams(), &params.AssetId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter asset-id: %s", err))
+	}
+
+	// ------------- Optional query parameter "application-id" -------------
+	if paramValue := ctx.QueryParam("application-id"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "application-id", ctx.QueryParams(), &params.ApplicationId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter application-id: %s", err))
+	}
+
+	// ------------- Optional query parameter "note-prefix" -------------
+	if paramValue := ctx.QueryParam("note-prefix"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "note-prefix", ctx.QueryParams(), &params.NotePrefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter note-prefix: %s", err))
+	}
+
+	// ------------- Optional query parameter "currency-greater-than" -------------
+	if paramValue := ctx.QueryParam("currency-greater-than"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "currency-greater-than", ctx.QueryParams(), &params.CurrencyGreaterThan)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter currency-greater-than: %s", err))
+	}
+
+	// ------------- Optional query parameter "currency-less-than" -------------
+	if paramValue := ctx.QueryParam("currency-less-than"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "currency-less-than", ctx.QueryParams(), &params.CurrencyLessThan)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter currency-less-than: %s", err))
+	}
+
+	// ------------- Optional query parameter "min-round" -------------
+	if paramValue := ctx.QueryParam("min-round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "min-round", ctx.QueryParams(), &params.MinRound)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter min-round: %s", err))
+	}
+
+	// ------------- Optional query parameter "max-round" -------------
+	if paramValue := ctx.QueryParam("max-round"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "max-round", ctx.QueryParams(), &params.MaxRound)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter max-round: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := ctx.QueryParam("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "next" -------------
+	if paramValue := ctx.QueryParam("next"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "next", ctx.QueryParams(), &params.Next)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter next: %s", err))
+	}
+
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := ctx.QueryParam("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SearchForTransactions(ctx, params)
 	return err
 }
 
@@ -578,6 +1048,42 @@ func (w *ServerInterfaceWrapper) PendingTransactionInformation(ctx echo.Context)
 	return err
 }
 
+// SimulateTransaction converts echo context to params.
+func (w *ServerInterfaceWrapper) SimulateTransaction(ctx echo.Context) error {
+
+	validQueryParams := map[string]bool{
+		"pretty": true,
+		"format": true,
+	}
+
+	// Check for unknown query parameters.
+	for name, _ := range ctx.QueryParams() {
+		if _, ok := validQueryParams[name]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unknown parameter detected: %s", name))
+		}
+	}
+
+	var err error
+
+	ctx.Set("api_key.Scopes", []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SimulateTransactionParams
+	// ------------- Optional query parameter "format" -------------
+	if paramValue := ctx.QueryParam("format"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "format", ctx.QueryParams(), &params.Format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter format: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.SimulateTransaction(ctx, params)
+	return err
+}
+
 // RegisterHandlers adds each server route to the EchoRouter.
 func RegisterHandlers(router interface {
 	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
@@ -598,7 +1104,10 @@ func RegisterHandlers(router interface {
 	router.GET("/v2/accounts/:address", wrapper.AccountInformation, m...)
 	router.GET("/v2/accounts/:address/transactions/pending", wrapper.GetPendingTransactionsByAddress, m...)
 	router.GET("/v2/applications/:application-id", wrapper.GetApplicationByID, m...)
+	router.GET("/v2/applications/:application-id/box", wrapper.GetApplicationBoxByName, m...)
+	router.GET("/v2/applications/:application-id/boxes", wrapper.GetApplicationBoxes, m...)
 	router.GET("/v2/assets/:asset-id", wrapper.GetAssetByID, m...)
+	router.GET("/v2/blocks/stream", wrapper.StreamBlocks, m...)
 	router.GET("/v2/blocks/:round", wrapper.GetBlock, m...)
 	router.GET("/v2/blocks/:round/transactions/:txid/proof", wrapper.GetProof, m...)
 	router.GET("/v2/ledger/supply", wrapper.GetSupply, m...)
@@ -606,189 +1115,272 @@ func RegisterHandlers(router interface {
 	router.GET("/v2/status/wait-for-block-after/:round", wrapper.WaitForBlock, m...)
 	router.POST("/v2/teal/compile", wrapper.TealCompile, m...)
 	router.POST("/v2/teal/dryrun", wrapper.TealDryrun, m...)
+	router.POST("/v2/teal/dryrun/snapshot", wrapper.TealDryrunSnapshot, m...)
+	router.POST("/v2/teal/lint", wrapper.TealLint, m...)
+	router.GET("/v2/transactions", wrapper.SearchForTransactions, m...)
 	router.POST("/v2/transactions", wrapper.RawTransaction, m...)
 	router.GET("/v2/transactions/params", wrapper.TransactionParams, m...)
 	router.GET("/v2/transactions/pending", wrapper.GetPendingTransactions, m...)
 	router.GET("/v2/transactions/pending/:txid", wrapper.PendingTransactionInformation, m...)
+	router.POST("/v2/transactions/simulate", wrapper.SimulateTransaction, m...)
 
 }
 
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+x9a3PbuJLoX8FqtyrJrGg5z3Piqqm9njgz43uSTCr2nD1349xZiGxJOCYBDgDa0uT6",
-	"v99CAyBBEpTkR16z+pRYxKPR6Be6G42Po1QUpeDAtRodfByVVNICNEj8i6apqLhOWGb+ykClkpWaCT46",
-	"8N+I0pLx+Wg8YubXkurFaDzitICmjek/Hkn4vWISstGBlhWMRypdQEHNwHpVmtb1SMtkLhI3xKEd4vho",
-	"dLXmA80yCUr1ofyF5yvCeJpXGRAtKVc0NZ8UuWR6QfSCKeI6E8aJ4EDEjOhFqzGZMcgztecX+XsFchWs",
-	"0k0+vKSrBsREihz6cL4QxZRx8FBBDVS9IUQLksEMGy2oJmYGA6tvqAVRQGW6IDMhN4BqgQjhBV4Vo4P3",
-	"IwU8A4m7lQK7wP/OJMAfkGgq56BHH8axxc00yESzIrK0Y4d9CarKtSLYFtc4ZxfAiem1R15XSpMpEMrJ",
-	"ux9fkMePHz83Cymo1pA5IhtcVTN7uCbbfXQwyqgG/7lPazSfC0l5ltTt3/34Auc/cQvcthVVCuLMcmi+",
-	"kOOjoQX4jhESYlzDHPehRf2mR4Qpmp+nMBMSttwT2/hONyWc/4vuSkp1uigF4zqyLwS/Evs5KsOC7utk",
-	"WA1Aq31pMCXNoO/3k+cfPj4cP9y/+tf3h8l/uT+fPr7acvkv6nE3YCDaMK2kBJ6ukrkEityyoLyPj3eO",
-	"HtRCVHlGFvQCN58WKOpdX2L6WtF5QfPK0AlLpTjM50IR6sgogxmtck38xKTiuRFTZjRH7YQpUkpxwTLI",
-	"xkb6Xi5YuiApVXYIbEcuWZ4bGqwUZEO0Fl/dGma6ClFi4LoRPnBBXy8ymnVtwAQsURokaS4UJFpsUE9e",
-	"41CekVChNLpKXU9ZkdMFEJzcfLDKFnHHDU3n+Ypo3NeMUEUo8appTNiMrERFLnFzcnaO/d1qDNYKYpCG",
-	"m9PSo4Z5h9DXQ0YEeVMhcqAckef5ro8yPmPzSoIilwvQC6fzJKhScAVETP8JqTbb/r9PfnlDhCSvQSk6",
-	"h7c0PSfAU5EN77GbNKbB/6mE2fBCzUuansfVdc4KFgH5NV2yoioIr4opSLNfXj9oQSToSvIhgOyIG+is",
-	"oMv+pKey4ilubjNty1AzpMRUmdPVHjmekYIuv98fO3AUoXlOSuAZ43Oil3zQSDNzbwYvkaLi2RY2jDYb",
-	"FmhNVULKZgwyUo+yBhI3zSZ4GL8ePI1lFYDjBxkEp55lAzgclhGaMaxrvpCSziEgmT3yq5Nc+FWLc+C1",
-	"gCPTFX4qJVwwUam60wCMOPV685oLDUkpYcYiNHbi0GGkh23jxGvhDJxUcE0Zh8xIXgRaaLCSaBCmYML1",
-	"h5m+ip5SBc+eDCnw5uuWuz8T3V1fu+Nb7TY2SixLRvSi+eoYNm42tfpvcfgL51ZsntifexvJ5qdGlcxY",
-	"jmrmn2b/PBoqhUKghQiveBSbc6orCQdn/DvzF0nIiaY8ozIzvxT2p9dVrtkJm5ufcvvTKzFn6QmbDyCz",
-	"hjV6msJuhf3HjBcXx3oZPTS8EuK8KsMFpa1T6XRFjo+GNtmOeV3CPKyPsuGp4nTpTxrX7aGX9UYOADmI",
-	"u5KahuewkmCgpekM/1nOkJ7oTP5h/inLPIZTQ8BO0aJTwDkL3rnfzE+G5cGeCcwoLKUGqRNUnwcfA4D+",
-	"TcJsdDD610njKZnYr2rixjUzXo1Hh804dz9T09Our3OQaT4Txu3uYNOxPRPePTxm1CgkaKh2YPghF+n5",
-	"jWAopShBamb3cWrG6XMKDk8WQDOQJKOa7jWHKmtnDdA7dvwZ++EpCWRExf2C/6E5MZ8NF1LtzTdjujJl",
-	"jDgROJoyY/FZPWJnMg3QEhWksEYeMcbZtaB80UxuBXQtUd87tHzojhbZnZfWriTYwy/CLL05NR5OhbwZ",
-	"vXQIgZPmLEyoGbW2fs3K2zuLTasycfiJ2NO2QWegxv3YF6shhrrDx3DVwsKJpp8AC8qMehdYaA9011gQ",
-	"RclyuAN+XVC16C/CGDiPH5GTnw+fPnz026Onz4yGLqWYS1qQ6UqDIvedXiFKr3J40F8ZCvgq1/HRnz3x",
-	"J6j2uBsxhADXY2/DUadgJIPFGLH+AgPdkVzJit8BCkFKISM2L5KOFqnIkwuQiomI++Kta0FcCyOHrN3d",
-	"+d1CSy6pImZuPI5VPAO5F8O8OWehStdQqE2Kwg59uuQNbtyAVEq66u2AXW9kdW7ebfakjXxv3StSgkz0",
-	"kpMMptU81FFkJkVBKMmwIwrENyKDE011pe5ACjSDNcCYjQhBoFNRaUIJF5lhaNM4Lh8GfJnoREHfjw5F",
-	"jl5Y/TMFYx2ntJovNDFmpYhtbdMxoandlAR1hRo4+tVndtvKTmf9ZLkEmq3IFIATMXXnK3fyw0VSdMto",
-	"H3Fx0qkBqz4TtOAqpUhBKcgSF17aCJpvZ3dZr8ETAo4A17MQJciMyhsCq4Wm+QZAsU0M3NqccIfSPtTb",
-	"Tb9uA7uTh9tIpTljWiowtovh7hw0DKFwS5xcgMTD2SfdPz/JTbevKgdCJ04Dn7LCsC/hlAsFqeCZig6W",
-	"U6WTTWxrGrXMBLOCgFNinIoDDzgIXlGl7RGd8QxNRitucB7sg1MMAzyoUczIf/fKpD92auQkV5WqNYuq",
-	"ylJIDVlsDRyWa+Z6A8t6LjELxq7VlxakUrBp5CEsBeM7ZNmVWARR7XxEtQ+rvzh0xxs9sIqisgVEg4h1",
-	"gJz4VgF2Q/fxACDmfFH3RMJhqkM5tc96PFJalKXhP51UvO43hKYT2/pQ/9q07RMX1Y1czwSY2bWHyUF+",
-	"aTFrAwcLamw7HJkU9NzoJrTUrC+hD7NhxkQxnkKyjvINW56YViELbGDSASPZhSaD2TrM0aHfKNENEsGG",
-	"XRha8IDF/tZ6wE8b79AdGC1HoCnLVW2Y1G72Zhb0yHezJYwVKSEFrvOVodUZk4UNaqE6U/43a/ZkbhYb",
-	"vmnYj2dEwiWVmW/RPy0Fi0kYz2AZl6605RvJYElYHOhZPTPTJPUhJx4OsBdldBvES3OhGJ8nNjq4SanV",
-	"Qb17ilScOQV2CdLBNQPp1K720bFECx9BWwfHOlQ458xNkGC6xqe1wNndUrEgKn4wjFiwVApqY6MGqZ0F",
-	"EgkFNdBhlM6p/eE51yH7hf3uQ7XeRR7SbnxcT6+DEqYm0csFbpYRtV0khlRvjragYGgh81xMaZ4Ygx+S",
-	"DHK90fVmDhJwhC2NvhZpv3sb5LOz93l2dvaBvDJt8WwB5BxWE4xYk3RB+RyaMELIL/bUAEtIq1C1dNC4",
-	"1UHQ+Urb0LePguNRKUSe1Efebtijp266eD9n6TlkxMgrZDGnBe+1d8hMQu4bEld1YOhysfImZFkCh+zB",
-	"HiGHnEBR6pXzr3Qsns7k/J5eN/8SZ80qjFFTTnCRe2c87tqwEe5b8pQfZj0n2ZSvW05lB1k/kV7yAXai",
-	"lxigMcNF+XOtd/QEewaqr6fRA6KyUGzjQ/gJ86Boa5dZhseRRrupalowTIYKmo2N5PTx6f4Jn+k9Qk5R",
-	"dpgDloILkDTHTA/lHcdMkYKZg7qq0hQgOzjjSQuSVBRu4vvNf61YOqv29x8D2X/Q7aO0MVfdWdLyQLfv",
-	"92R/bD8husj35Gx0NuqNJKEQF5DZ81hI17bXxmH/pR73jP/SE8ykoCt7kvO8SFQ1m7GUWaTnwsj1uehY",
-	"nVzgF5AGPDBqVhGmx6jKEKNordt9aRhwFLWe7sLnExnV2OlGlRpp56OSbdpRBJY0NaukKGRW1iKo6axv",
-	"BGlRJuEAURf0mhldEEC15PgN+a4vz60DYj18px0XRAsdAbnubbbde8iIQrAN+x+SUphdZy7/yCep5Ezp",
-	"HpDOHYERoJogI0pnj/wfUZGUIv+WlYb6bCckHpjwIG1mQB3r53SWWoMhyKEA6yHCL9991134d9+5PWeK",
-	"zODSJ+2Zhl10fPedZQKh9K05oEOay+OIAYWOeaNNI4nWC6oWexud9DjuVr75YOjjIz8hMpNSqGLMwqUQ",
-	"sztYLcuWUZsFlrGVup1Dd9s9RUq6GjSvSwNgJFsL5HmOvnwx61AkcfJvwUozZJNZstLQykr9v/f/4+D9",
-	"YfJfNPljP3n+75MPH59cPfiu9+Ojq++//3/tnx5fff/gP/4tZrwozabxuM/PVC0MpE5yLPkxt5FbY3mi",
-	"w27l/ABi9rnh7pCY2UyP+WBJ2xDd29iGMGNK4GYjzZ1UZZmv7kDJ2IGIBHfGUC33qLJfxSxMSnWUp1ZK",
-	"Q9GPMNiuvw2cft5570SPSgXPGYekEBxW0XsYjMNr/Bi1DVEsDXRGBTHUt+u9acHfAas9zzabeVv84m4H",
-	"YuhtnSJ7B5vfHbcTXArTcfFkA3lJKElzhq5zwZWWVarPOEXnXMf07pCFdzkOu2tf+CZx/3DEfeuGOuNU",
-	"GRzWLrto0HEGEWf8jwDea6uq+RxUxxQnM4Az7loxjo4WnAtPMondsBIkRof3bEtjfc5ojt7lP0AKMq10",
-	"W91j1qC1pm2ky0xDxOyMU01yoEqT14yfLnE4f6r2NMNBXwp5XmNhwCsAHBRTSVyQ/mS/ojx1y1842YpX",
-	"OOxnL28+twLwsMdy2hzkx0fOFD4+QnuniXH1YP9sgY+C8SRKZOaIWjCOqdEd2iL3jdXmCehBEy1zu37G",
-	"9ZIbQrqgOcuovhk5dEVcjxctd3SoprURHT+2X+uH2BF7LpKSpueYfzKaM72opnupKCb+CDCZi/o4MMko",
-	"FILjt2xCSzZRJaSTi4cbzLFbyCsSEVdX45GTOurOM93cwLEFdeesI0j+by3IvZ9enpKJ2yl1zya42qGD",
-	"zMTIqc3dr2w5EMzi7QUtm+FrDtBHMGOcme8HZzyjmk6mVLFUTSoF8geaU57C3lyQA+KGPKKaot+p400f",
-	"ukOJPkEHTVlNc5aS81AVN6w55Iw9O3tvCOTs7EMv3txXnG6quIMbJ0gumV6ISicuIjHsu2r8eziy9QWv",
-	"m3VM3NiWIl3Ew40/4HQvS5UEXtj48ssyN8sPyFAR7IT5ikRpIb0QNJLR+dHM/r4RLuIu6aW/NVIpUOS/",
-	"C1q+Z1x/IInz+RyWJbp40cf6307WGJpclbC9n7YBsRksdrbHhVuDCpZa0qSkc1DR5WugJe4+KuoCvWh5",
-	"TrBby9/ss7VwqGYBa/2KARzXzqXFxZ3YXj6AEl8CfsItxDZGOjX+8JvulxnqZ5EbIrvxdgVjRHep0ovE",
-	"8HZ0VcqQuN+Z+mLX3MhkH/9WbM4NE7g7cFMg6QLSc8gw+If+8XGru0+xcBrOiw6m7LU1mzKLdyvQFTIF",
-	"UpUZdTYA5atukrsCrX1m/zs4h9WpaK5mXCer/Wo8cgGtxNDMEKMipQbKyBBryLY+KNbZfBffxKBTWRIb",
-	"17HZyJ4sDmq68H2GGdlqyDtg4hhR1GhYQ+8llRFEWOIfQMENFmrGuxXpR6NIVGqWstKuf7u41NtWHzPI",
-	"JuUSVSdi1tUaPaEeFWK2cTKlKq5AwHwx+2F4qJvN5GeyXkUbqCZY+sAR7jSHIKKqHGdTiUaXX7a9yz0E",
-	"WpxKQPJGq3sw2hgJzYeFSw1gF01CALp8tlG0GwOyhop8zg5rh16YmTeHCzoYBRu8c3QcJOIEV1nrG0Ve",
-	"sHWZYVzfLrNVJfzNI3/dyN8xGo2vdV9oPHK5obHtEBytjAxymFMX9MGsU59wYEG7p4INMnD8MpvljANJ",
-	"Yjk9VCmRMpsH0MhyNwcYI/Q7QqyDh2w9QoyMA7DRW44Dkzci5E0+vw6QHBi616kfG/3swd+w2dvclPdw",
-	"5u1GM7QvOxomGjfX7+w29r1Q41FUJA2dEFqtiG0yhd6RKkaiRjT1/TJ974+CHFAdJy3JmpzHvHXGqgAk",
-	"wxPfLTg2kPtsZpT8gyBoImHOlIbm3Gy41TuCPq/v4kJoSGZMKp3gkT26PNPoR4XG4I+maVz8tFBFbH0A",
-	"lsWlD057DqskY3kV320379+OzLRv6vOTqqbnsEIlAzRdkCnWszBaqDW9abNmapvXtnbBr+yCX9E7W+92",
-	"tGSamomlELozxzdCVR15so6ZIgQYI47+rg2idI14CTJx+rIlOJPZfCHMLdpb5zXoMdO1s5kGJa8dKbqW",
-	"wNBduwqb9Gbz2oJyEP07FgM8QMuSZcvOGd6OOhC2QwP+Goa6tfgjoahRPdgGDATn9VgarwTvc7BbGuhM",
-	"W9ijl+q4GTPdBMtAIIRTMeXLUvURZUgbM9E24eoUaP43WP3dtMXljK7Go9sd+WO4diNuwPXbenujeEZf",
-	"tj0Ctjx410Q5LUspLmieOMfIEGlKceFIE5t7P8pnFnXx4/fpy8NXbx34mLkJVLqExXWrwnblN7MqcyKO",
-	"ZS2eBp4RtFb92dkaYsHm13eJQ2eKTzJt2XJGijnisuzVOMoCVnTOlVk8pLbRVeJ8enaJa3x7UNauveZE",
-	"bD17bW8evaAs90dRD+3mpNgbSYVWVu1tvYJhiu2dipsed8e5o6GuDTIpnGtNEZTC1vlRRPBuYpExIfGE",
-	"i6Ra0JWhIOuc7gsnXhWJYb9E5SyNuy34VBni4NbnaxoTbDxgjJoRKzYQQuAVC8YyzdQW0bIOkMEcUWSi",
-	"S2kN7qbCFWisOPu9AsIy4Np8ki7RsMWohi997nxfncbz9N3ALlW/Hv42NoYZasi6QCDWGxihhzlyS8Qf",
-	"OP1Ca9e4+SFwDF4jUBXO2FOJa4JMjj4cNdto/6LtKQ7rKfblnyEMW3tnczFH77ZYWEAH5ogWZxzUFofD",
-	"mgLvX2yvIxqVgOCGysDmxNJcicgwFb+k3NZaM/0sDl1vBdZnYHpdComXFhVEo/RMJTMp/oD4SXZmNiqS",
-	"++hQieYi9t6LXAbrCtHaK9NU0fT4DeEYJO0hSy74SNqBxAEORyoPXOeYzO0dXJRbsrZ14Vrh6zhzhCkn",
-	"Ezt+wxwO5l6aTk4vpzRWIsUYVAamwyZI03LFaUF8Z78Lqr7D4GgviPfUbZm96VeCbBKU+7fKb2gcfVsk",
-	"n0HKCprHraQMsd++ApaxObPF9SoFQfU2N5CtSmqpyFXAs2GwBjXHM7I/DupDut3I2AVTbJoDtnhoW0yp",
-	"AnvTLLx95hKjNHC9UNj80RbNFxXPJGR6oSxilSC1AWsvFXnf9xT0JQAn+9ju4XNyH73+il3AA4NFZ4uM",
-	"Dh4+x7QU+8d+TNm5Kprr5EqGguU/nWCJ0zGGPewYRkm5Ufeit05t6eNhEbaGm2zXbXgJWzqpt5mXCsrp",
-	"HOLR3GIDTLYv7iY6DTt44Zmt26m0FCvCdHx+0NTIp4HUNCP+LBjujkphGEgLokRh6KkpzWYn9cPZIqCu",
-	"XJKHy3/EEEvp7xp1Dsyf10FsdXls1RgIe0MLaKN1TKi9nI3XpdylficQ9wbq2oC8iE8iBzbY603Xl9zn",
-	"gieF4Z3sQZP0GNBftKyL0DSPp2942dXN3lk/9LamlhklGURs1UIsDWTSjVFcyfg6aWWm+vXdK6cYCiFj",
-	"NVoaaeiUhAQtGVxEObabvFdbJrW68JiPGSg/VCzP/t6k3HbKoUnK00XU9zo1HX9r6jfWaLdYj159XVDO",
-	"IY8OZ3n5N8/zEan0T7HtPAXjW7btljmzy+0srgG8DaYHyk9o0Mt0biYIsdrOQayTVvK5yAjO0xRZaAih",
-	"fzcxKPn0ewVKx+5R4geb74VnbGOv2IpDBHiG2n6P2HuHBpbWzTHUsqyocnsLCbI5SOf8qcpc0GxMzDin",
-	"Lw9fETurcnfo8b4bVjya2zusrVV0zlZBRZbrXOodSg/bfpz1+Spm1UpjSQalaVHGMn9Ni1PfANOLQ38T",
-	"qp8QO3vkyGp+5fWKnaS5u03q6ZysQZow/9GapgtUqS0FNEzy25fq8lSpgpK1dfXPuqiKvY6sha/WZYt1",
-	"jYkwds8lU7bsNlxAO9m4zrx3Jp1PPm4vT1acW0qJ66c1N0NugnYPnA0qepdUFLIO4q+pZpSoZArXrVx2",
-	"gr2idxu7ZdB6tWrtLae6VqR/TiGlXHCW4s3CoNB3DbIr4b2Nv3aLS5jd47JnccehEeaKFl+r0xYcFgfL",
-	"sXlB6BDXdxgFX82mWuqwf2qsFW0OgnPQykk2yMa+wJ47xzGuwBXJwWrugZw0x/Fu7DIaVmnKZFyTjDD1",
-	"cMBc+dF8Q1OFuXShc8bx0rhDm8tMsictrDCszfGOaTIXoNx62lcF1XvTZw+vy2Ww/LDnKxLjGNaFbJZt",
-	"4yX9oQ599MRFK0zbF6YtQXdx83MrzdFOeliWbtLoTb96h2MlAgcRHPGCJ94NGSC3Hj8cbQ25rQ17oj41",
-	"hAYXGDSBEvVwjzAGSk+8NIdaS1H2BrtNN4heT2E8AsYrxqGplx1REGlUJeDGIL8O9FOppNqagFvJtFOg",
-	"OUZKYgJNaec6uu1QnQ1GlOAa/RzD29gUihwQHHWDxnCjfFWX6TbUHRgTL/B9AIfIftlHtKqcEZVhQlmn",
-	"EGRMcBjB7UuothVAnw36NpHtriW1nHMdTTSUiJ8xZY4jxTSPpNAc1R+DYqiYqzdd4b+xi//DK3CBtRsX",
-	"qsGO17Yv1xeNyc3eJ4rNb7grTf873JYOD4R7FKP+l0ashHeXejUcrOCprxZh+oDwpanxUFEnxbdpFgVd",
-	"9NDWVBlef2gdrhc8RtE4kET0rrk1S630tb7BoVSidDDzjWqX1qopWVe9yRb5jY1g45C2uLB9qCfqGBiK",
-	"PdrQo/nc672d3dCzwnDstQj1Qe0+QH/zGTOkpMw5vhsW6WPW5db1sx23ybppNri7CJexhoPEVnLDBLOt",
-	"eK+PpQhjh6kBG8jzvIVSexOlY0kKCXeM2kCFXhO1/aSHbZeH60CKqRT017n1BrRwO4D7bRDfyIU+cofZ",
-	"WU+3Yed4Qr/pjvLEIsRfOelLk88mDVq1yd28sV3/+5D3wJ6QBxxVHZxWLM82bW7L7dhc6UbH2m/TZ09a",
-	"3rvPean8NxuQ77Obu197HcXf3QRETGStrcmDqQKH4ha+RNct4jnEGmxpJZleYe6OtzTZb9Gc6J+Auwrt",
-	"7sGLOgLqAnD2rSXnmp7XrZvncX4StmR9YcxfNAU1Fid6uaRFmYPji+/vTf8Cj//6JNt//PAv07/uP91P",
-	"4cnT5/v79PkT+vD544fw6K9Pn+zDw9mz59NH2aMnj6ZPHj159vR5+vjJw+mTZ8//cs+/TWMBbd59+QdW",
-	"XkgO3x4npwbYBie0ZH+Dlb1rbcjY3+KmKXIiFJTlowP/0//yHLaXiiJ4TtP9OnKe/tFC61IdTCaXl5d7",
-	"YZfJHMtlJlpU6WLi5+nXgnp7XDtobcAfd9T63gwp4KY6UjjEb+9enpySw7fHew3BjA5G+3v7ew+xWEoJ",
-	"nJZsdDB6jD8h9yxw3yeO2EYHH6/Go8kCaK4X7o8CtGSp/6Qu6XwOcs9dZzc/XTyaeP/O5KMLcl+ZUeex",
-	"rCZf4q72L/ZveY+tw8KcWeqSdsFFIuXuF43J1ObvEFdVkWfoAbS5GUa01cg6zoLHe4NXYsatt4fff0PP",
-	"6cXqrcWuy8ceSK4z3IcfyAreEPXvhj7961Uk0PSh8+jRo/39T/DQ0bg1isfLDV9MenKHILZPULcGtDtc",
-	"Tyq8prmhG6gfwRzhgh5+sws65niXxIgtYsXy1Xj09BveoWNuGIfmBFsGKSR9UfgrP+fikvuWRiVXRUHl",
-	"ChVucIk9NK2uBkVuO3nL3QYclsMQVP4LLhC3HNvTlaezMVF1ofdSMmEMB3wyNoNUAkU1LyTGg5oagu6a",
-	"JNjK9q8P/4He49eH/7DFOaPPaQbT20K1bSH+E+hIjcsfVs2TcGsl+pcSk+Ov9gXSb0fn3VbV7CqlfrOV",
-	"UrcQ2rvd3dXB/Wbr4H7bJumyTrylhAuecCyocAEkcGvtbNSv2kZ9uv/4m13NCcgLlgI5haIUkkqWr8iv",
-	"vM4Iup0JXsucigc5WmvlT+/Vh8aKDsz3oLjT5GPrUZlss/OkdRs8a9X+p/FHeYO6Ny4bdNxccaU8s5kc",
-	"Plarxv6qJ3rr7J1qux/j3kXQvZiRHoRaflgdH21jl7fWFNxAi9nmLXxd76nvT+qxuPGDyZ9SA/Tg+IFm",
-	"xKeMfmLZvJ0wfbL/5PNBEO7CG6HJj5hk9olF+if1E8TJKhA2WEBt8tFfVttCwLiLoG3R0n1lOyZUDIeO",
-	"Xc6+K1VdP3pj5IkVhPYubl9qmBm2lRf9u6oxSdHcz/taZMS1HjHfyYWdXLixXOgSVCMR7Iurk4+YYBuK",
-	"gx5L4mMJf6JASVC5T4rCl44RZAY6Xbgn6jux7IhY8YnJwzJl3bXCW8uXTnQdt6hHHvaZCxevxetu13n6",
-	"/2cbPr0aj1KQEeL7xWeBmc9shqXx6qRzf3tWcDSL3YvC7p2p+sYdU8QQqBbE5XoRs4vXgvJFM3k/to5o",
-	"uZk3aYfg2yC4J9ReunfxLXu5RXzrjo9AW5KEvEFzCBnc51z/Gd0en1Ijf+oFvREcCCyZwoqelhZ34cba",
-	"XKifhqrfiwir/g+YDu2g40e9ZNnVpH48asioeOveOFprVDSamvHgWezQvULLEqhUN1bSm8Nhp50Zj4/C",
-	"EpSiTnUitHlCKgKKwcs1I4n/vk0Y8c8brdu9c7Z75+xm75x91iNzk5BjRZWPE8mO1Pii52n9Rc7TbwRP",
-	"UNsC197ya6Hly52t8VpLqxa8v6TMhX1hTUg0EkI5oPa2Uq8wGEpoCRVM6RwmY6dsU6rTRVVOPuJ/MBn0",
-	"qkm7tDfyJ9bNtk7f2hflRneaQLF7BfAbeAXwy7vwbmWOdlYroayT0DBaj/TfcIuvvt0vSd3OTHbN1aLS",
-	"mbgM8pibVw4GOcm2uFNOeiMysOO2c/n7RWCofXVaeSA6DFTLiHjRM4/Npp299s6Ue3I/pdV8oW3Vo2hJ",
-	"tbpjQlNL+Ik9DsQnbJImbCv/2P0FEJpLoNmKTAE4EVOz6GZfcZGddxqcJIwXkWngKqVIQSnIkrCsyDrQ",
-	"6qxy9AfqNXhCwBHgehaiBJlReUNgrUhYD2i3+lENbu31cVzfh3q76ddtYHfycBuphObpQS0wqyYH9wxV",
-	"BIVb4gRNVfaJ989PctPtq0qsXBF5j9R+PWUFXnPjlAsFqeCZig6GxfQ3sS2+IxmsRYEtIuc55XO+V2mr",
-	"/w/dCDMjxx9itWuoX/2oa8pYSwuyaNUzWK6Z6w0s67nELPbSqy1xuGnkISwF49dVZnTtkaA68EiY4SKL",
-	"w6f/qTO8ImWHQyAaRKwD5MS3CrAbHvsHAGGqQXT9GkubcoLyg0qLsjT8p5OK1/2G0HRiWx/qX5u2feJy",
-	"ieAo1zMBKjSzHeSXFrO2gNSCKuLgIAU9dxb63OVj92E2zJgoxlP3PsXQq02sgBPTKmSBDUzaNfJC9u88",
-	"cNpijg79RolukAg27MLQgmNm5VdhBF73lNf1H3xCt2fbrA7Mq8astH9PLinTyUxIqzETLJ0aiaC2Z/9P",
-	"yrQr2OvOwFo4t6UrvmoFihsnKJ+mwmRW96KW4yOz+/38CTPVj0JuFbBtfKtaELMwUnHN/HU7fHnR25hf",
-	"X/RzZz3vrOed9byznnfW88563lnPO+v5U1vPXyYDkySJl9P+ek3scg0ZfZMW/jd0f+VzXjhpjP7a5MdD",
-	"gjHRDR+vzczQQPOJK1qKIXShBlO8wwKoqZmOcVLmFF9lWGp/0RgfZHj2xCcK1KX8bA0kI2tMg8ePyMnP",
-	"h08fPvrt0dNnRvrYt0Bbbe/7AvJKr3J44DLY6gInPpUNOMWagZjJRv3pJ/VZDtaan7EcCD4J/hKbH8EF",
-	"5MaUt7FOYg4j/ePRKdD8hUOOlUqg9A8iW3UIx6x/gqhok0wTMGecykgZzj6h9JCsBZbidXVleyeoqzvN",
-	"mYjnCfQ3bNNeDbwXECXvdfSyMS/AVVB3Y28TIzN76tFJXAnPLyqyCULkyKwRT19NJn33GS3HONjWWBWO",
-	"/77VrHeP+CjjIduODU1mVQr4IqujuGViGs2BJ04sJFORrfwTWq4icEvK2lKtw0L25RLSyvASQuLY4L56",
-	"4B6/xpLToasnWio/ePkBcLzmwcbPLTht1dG1cvPm1NF+w+DWOZPd4fpSI0i6uC8kmUtRlQ/sY018hUfi",
-	"oqR85d1gxlbERxDwrXPM875bSV0XgO7J2e1r+IfnFby03/3dooVcUuUL+Ge2gn+8imG3zvxmjDdVlDdV",
-	"vbPrjVZ8H6jv3t9Ev8su0bF2/ZUgE73kkbrLnSrLu8tV/yNUwlspLpg5OEclbD8LqxEIexs1gwxEFqqG",
-	"TqkNrxva8vQdvQwLd2wrU5eJMzxvbZUuwD6K6q20SF0Soy+loFlKFd4fcU9jfGKLVS+PI34HBBPrS/Uz",
-	"fY0C3/z+EY67lT3ZzvR2E2IBGGULaX5Z67LJNj1013Va2Ni5Av4sroAfPPMpQomkl13mDJ6r2UJM0Uu9",
-	"5FEpNWke841mvAUMUb/+eYexu97w7RBe8MymDUFAXhJK0pxhgEJwpWWV6jNO0QUaPm/aD+95x+6wKfXC",
-	"N4l74SNOcjfUGaf4IF3tGI2aVDOIPd4C4C02Vc3noHRHEs8AzrhrxXjz+F3BUikSm/dp1LWR6Hu2ZUFX",
-	"ZEZz9OH/AVKQqTlFhDVL0KGoNMtzF0800xAxO+NUkxyM0H/NjEFnhvM+pzpG7p5J8lgYeE3dVpQdeLHx",
-	"J/sVLy245Xu/Ebq37GefDT3+MnWfow8xO8iPj1w9seMjLBHTRBJ7sH+28FLBeBIlMqPxXUS+S1vkvnv9",
-	"EwnoQROTdLt+xo0xrQVBQd88+389cuiGAXq8aLmjQzWtjehEC/xaP8Tuss5FYo6M+MzEaM70oppi5WV/",
-	"x3UyF/V910lGoRAcv2UTWrKJKiGdXDzcYB/cQl6RiLjaae4/jxO/+zx0vfHGiO3t/YBevoPyrV93zdaN",
-	"KUq7Cqm7Cqm7Gpq7Cqm73d1VSN3VD93VD/2fWj90b62F6GpubKzo17ppnNlX7SWkduZagIfNWrX/+mFJ",
-	"pvcIOcUn66nRAXABkuYkpcoaRtxmyhVsvtBEVWkKkB2c8aQFiX073Ux8v/mvPeaeVfv7j4HsP+j2sX6L",
-	"QPL2+6Kpip/sA4bfk7PR2ag3koRCXICrBIbNswpjxbbXxmH/pR73F9nbuoKurHNlQcsSjFpT1WzGUmZR",
-	"ngtzGJiLTn4fF/gFpAHOFpogTNuiq4hPzIt02TnU3TaPGd19/X6Nh28OO+SyK2ryKQzsI9CU5aq+nRA5",
-	"T+HJpktZl1Q1rFtLFV/OAJT/zQWs3Sw5O4cwBxezDy6pzHyL6MOzTZld/7By37XUrj+awdKbBF2gZ/XM",
-	"TNuKoebA2XsKsO/ZslU801yYM2tiH3jalNmOL0aZfvcUek0to6G9inDNQLrce/Rm5UJBokVTqXkYjnWo",
-	"cCUXb4IENVikxgJnd0vFnjbED0YkoleYolMYkdpZoBEq1EAn8RqSzf0fnnMdsl/Y7+61rdor2PHBR8b1",
-	"9DqYZlyT6CUqF5R6XSSGVD8jrkLCgCPaPh5sEzlu/IRwp3vvdcY8Ozv7QF7ZStn4tOg5rCb2Ubt0Qfkc",
-	"VI2jkF/s1SGb3hPkl3fQeHfPFhvtlQw8OH7czznv4v2cpeeQESOvkMVcKnzkMEHu12V/Zwwl+crfI7Hq",
-	"8MEeIYecQFHqFbEStuPz7kzO7+l18y9DBd7WjJH0xRTYBchb8pQfZj0nKTAMd8up7CDrJ9JLPsBO9DJy",
-	"tN62DmTkJN051wZEZaG4CwfFTjvutONOO+6040477rTjn1479pxSO7fN53DbfHHHzZ+oBvau3PVXtqAw",
-	"mbX1nsUtvNn1q90xa9z5qZtX8cNX5tHLWL8v//7D1QfzTV54B2TzaPrBZIJWxUIoPRldjT92HlQPPxpR",
-	"Sud2BOfgKyW7wGr1H67+fwAAAP//rYSwcWn0AAA=",
+	"H4sIAAAAAAACA+19CXPbRproX8Fqt8rHEpR8ZSZ+ldonH0m8YycuS0lmX+SXgESTxAgEOGhAIpPn//6+",
+	"o7vRALpBUKLPUdVMxSKAPr7++ruPPw+m+XKVZyIr5cHjPw9WUREtRSkK+iuaTvMqK8Mkxr9iIadFsiqT",
+	"PDt4rJ8FsiySbH4wOkjw11VULuDfGQxSv4Pfjw4K8c8qKQQMVRaVGB3I6UIsIxy43KzwbTPSOpznoRri",
+	"mId48ezgXc+DKI4LIWV3lT9m6SZIsmlaxSIoiyiT0RQfyeAyKRdBuUhkoD6G1wIARJDP4OfGy8EsEWks",
+	"x3qT/6xEsbF2qSb3b+ldvcSwyFPRXefTfDlJYHK1KmEWZQ4kKPMgFjN6aRGVAc6Aa9UvwmMpomK6CGZ5",
+	"sWWpvAh7vSKrlgePfz2QIotFQac1FckF/XNWCPGHCMuomIvy4O3ItbkZrDAsk6Vjay8U9GHiKi0B3DPa",
+	"DexxDhNkAX41Dl5VsgwmsO8sePPt0+DBgwdf40aWUVmKWCGZd1f17Pae+HN4Hkel0I+7uBal8xzOOg7N",
+	"+7AAmv9EbXDoW9FqlSbTCPftvDLH9fMA8NazmeYgDqRKslLM6WQa96H+znFZ2g8jKYX7Xh/jk57l6Q+H",
+	"Lwy/cCyp/nkiAKhiIPrwy3vFH3v+j4pAcELTxSoHODrOJaCnAT92klvr8z5yaxbQeH+FkCpw0F+Pwq/f",
+	"/nlvdO/o3b//ehz+H/XnowfvBm7/qRl3CwScL06rohDZdBPOCxHRxV5EWRcebxQ+yEVepXGwiC7o8KMl",
+	"cSX1bYDfMpW/iNIK8SSZFvkxrAQIkUIjoKoRDBXoiYMqS5Gi4mgK2wMYYFXkF0ks4hEyistFAmcxjSQP",
+	"Qe8B8U5TxMFKitiHa+7d9VymdzZIcF1Xggdt6NMFRr2vLZAQa6IG4TTNJVzJfAsn1cwRsC6weV/NVuVu",
+	"fDU4hQ3S5PiA5QKCXYY4nYKwUdK5wnTwe6C5KIBpFmzyKrikw0mTc/pe7QahtgwQaHQ4DZaPl9cHvg4w",
+	"HMCb5LBdgCsCT9+7LsiyWTKvYLsAAgGLYfYMf4NkCDvNJ/8Q0xKP/b9PfvwhyIvgFUAmmovX0fQ8gAPM",
+	"Y/8Zq0ldwsY/ZI4HvpTzFQzklizSZJk4lvwqWifLahnASBNYLpyX5g8As0KUVZH5FsQjbsGzZbTuTnpa",
+	"VNmUDreetiFTIiolcpVGm3HwYhbAIN8cjdRyAB3gQqxAvoKtBeU688qTOPf25QEeV1k8QNwq8cAsrilX",
+	"YpoA5saBGaVnJWqabetJst3WUwuB1nL0IN7lmFm2LCcTawfO4NXFJ3DB5sJCmXHwk6Jc9LTMz0Gq0AQu",
+	"mGzo0aoQF0leSfORZ400db8mkOUgTcB4s8SBYycKHEg9+B1FXpdKwJnmWRkBtYqR8tKiYTimRN41WRP2",
+	"611dFj0Bqv7VQx8Dr58OPH34snXqvSc+6LTppZCvpIMv4lN1Yd1iU+P7AXqqPbdM5iH/3DnIZH6KrGSW",
+	"pMRm/oHnp8FQSSICDUBoxgNDZhFQDPH4LLuLfwUhSEcA9qiI8Zcl//QKBkpgEvwp5Z9e5vNkCj95gGnW",
+	"6lT86LMl/wfHc5Pjcu1UGl7m+Xm1sjc0bSjQcIlePPMdMo+5K2IeG63b1ipO11rT2PULWIU+SM8ivbBb",
+	"RfjiudgUAlcbTWf0n/WM8CmaFX8csHLogikisGK0ZL9Qdo036jf8Ca+8YJ3A0g4PiX3Cb/WC/gPuOIz9",
+	"74e1UeeQn8pDNS7OCFNaCuH+Z6q/5P35teAk49OhV0esE+5/PTiqcyUkqLbW8CTNp+dXWgOwjJUoyoTP",
+	"cYLjdG8KDR8sRBQD/wO9MhrXShXLWR58pw+/p+9IS4KZHOYu+keUBvgYbyFIK0p8Q9EVJDj4X27ZxGKU",
+	"+JiP8Ez4AkmiebBkIS9A4WynVT6tJ2cCbSjqrwosb9ujOU7nOcuVAX2hN0EnlK/3jiMwpmsN8HMHP/K1",
+	"kPvADxyHqE0plnLA+p6pleV0/gp8UVEA8ekAmcYeAmTcIFI4SSoP6Hj23R1ZCvrxJC+udjVbdy4LarND",
+	"EOGoRtFAJGsCiV6tVqFCRYfqwi+0BqqN0l0OZsOpPbwLYg0oAB9+D1CQOOo+oNAcaN9QAKxMUrEH1F9E",
+	"ctHdBMqSD+4HJ98fP7p3/7f7j75ClIQP56ANgwBRAo7eViwcdrZJxZ3uzoiXgmDkHv2rh1pZbY7rGkfm",
+	"VTGF1a+6Q7ESzF4Cfi3A91qn9q4LxSbYCQpmwUMu66lAys7HELC9B5f6rNiAYrqHcxFFkRcOnYV2VubT",
+	"PA0vQJRNcof56bV6I1BvIB9hvan1O682uIyA5MDcpE5X6HQYu44B9eTBRJKHPl1nNWx6ySTv17E7Ne+Q",
+	"M2kCX2tnEhT9IoRBglhMqrnNQ4JZkS9BuYvpQ2JoL5MrynvN47uMigzANhxeOO8v/NFWWJnBByNqiqSt",
+	"Pokf4N4B+SwruQf6WQ9WQxyxzYYzsIQKOAzoxzGSQnzZTVk9Bney9JGBsrSJdblgIWkiUIWbRtV8UQao",
+	"++Qu/K0/DKMpQzMkgUZ67BPGsMRv8XRszE0LkP42MDEIbPlEGQGUeYI2GZHtsNS0SdH1ellGcXWvC17O",
+	"tq+L32rMwYCZIVXyLDfOL7M0j5DyoikW7hnrvNdfPZznFDgCLF85m7duQL/HF7HsOWXaB63fzAIEP5hF",
+	"xRUXW+ZllG5ZKL3jWq6R2JXdp7vqYdP3oV97chsJ0cqtKQKqB0hQUlEKHwh3gkkf7rUXpTDQhX5wLy1U",
+	"S7IRqzdJeavxgE5eCkBDGLgQabQh7z8a3tNUpNuWrNH3vaKcuSNXxDgQ6NwOVSUsnsJD3HQWZbkUQEVj",
+	"6RwsjWQZbqOT+FJDosUdWJfbRRppYI/Z8CU8Y8NdksWkSPJ50zz0DU3hX7BXTsGRf9YiSnfsKTKmTAJf",
+	"0fKKrFYr0C1E7NoDWnv9c/0AT/VciLdmbCMUwTWqpNg2sg9K1vgKWLwTBhBgE1uOjWW7uzly0iHj3ThB",
+	"2VhEDYi+hZzotyzo2k4lz0LQ6mC+JMSBX5qYYzxZIJyX+WqF968Mq8x85wPTCb99XP5Uv9tFLnT9aVYU",
+	"5wJnL/Wa1MovGbLsTgTxPVDrAPH/HIUBUirYwthdM17GUAIRF2Ef5uO1PMG37Cuw5ZJ69DkVsGDN1roc",
+	"Lfx1Ip0XCbacgm/DHuXyNfvFTmub8R6kxGcChKRUGknQON/qWchP1w73Qt0EPbdZmW4QV2dJsWRXN3Fg",
+	"qX9jOTNWs7BTt75+8P9CgNgc6ze6in0j1gd0oLWbukYNiym8ht5k16JnZuYELpl2RDesOmPnRWfXPvqR",
+	"AT4hxwxsY2rG1Q+8tcoSxcAuRaHWNQP1iiWFUvvMgdlrv3rfOvpAoUy2VwECfuqelhfHpyVdoRX0AC/i",
+	"EiMmIo6YQKC2NggnvoxwdeS7V2zfP2cfsJ/ycx3AoR1nNu66x9X46qUwBkWBLpNPGNlGC4g21qMVRgC9",
+	"9mxknuYTkOBQwxJhLNJyq7EVNTfxjN58hy6hjHVlB+TPzn5NyvXZ2dvgBb7V9LYnUla1DmFfEtbNxFpM",
+	"K5uftGBndGQHfKJL8sPh8dqQoZgSZJc7me5PaCSLtv0C4xw/6+rcQJ/zaReWHZikMYLkJb5Lmq0IzsXm",
+	"kIJ6QDqOsrmoPa3XgMsAd1LzKLu7mbsPNZ3zBuZ7WWftjt6UohHK9n9v/9djDGGLwj+Owq//8/Dtnw/f",
+	"3bnb+fH+u2+++X/Nnx68++bOf/2H0yTV2uQKeHxoTGdt93dHwGjftPNkeg57RA5FRFXJPbeadxInCW4j",
+	"UZMmQOBysdFKA/BhwLA74yA4zgKxXJUbZfxtybitybNbZd/8a5o1rihWCSgobXJ8lrntrhzpdE0qqofp",
+	"p50cpXzNqXiQ/omAKu1CIK5MEDoynIVUvIohZrfvKB42apxyEpMCWsszsposEwqKtV4bIa/UcUpdI1pS",
+	"AmadErdAK4DWnDHiT2oHIuDNMkFbmKymUyHix2dZ2FgJEBE18e36n8yIzqqjowciOLrT/kaWqKAogwff",
+	"gfa33wRHI35E4IK/zw7ODjojAWPOL7Tqb+M1f7V12H8z455lP3ZYMWgAG9bd9V0EMMxmyTRhoKc5cvJ5",
+	"3tIzspyeABbC8gQKVgD9ckTCC0GU9DM+l/oCHjjl5X2YVR2jomaGwhNSOx2d0sQdCYQa/gW7jIjIbFgG",
+	"NHjWFXtBbwjtAZz+sZ4ZlTNYNpjAFe9dl56zQap/factk1RTLqnRdbxdW+sAw7mCIdf/GKbEU09UHKoO",
+	"VkwTWXYWqQxQFAlgENLBdMbB/+QV3HS6vytgx0abh0uBKjKZTnAGYtB6TiWb1xASKWA4mzHpyd277Y3f",
+	"vavOHAaaiUsdvI0vtsFx9y5fglyW174BLdRcv3CIzOQ1RG7qyA1CN954q4+Vxh3kOrGGfvHMuBnxMklJ",
+	"LAY3XuT5bA+7TeK1U2YB5cqxU3VyZBO+hdbIjVehWuECHVG7ojhPyScIwzcxMlD0b5GscMgPK9IBm5m4",
+	"ndLfw6+4UkU51tmLjCN4UGwlE+1GWX7y2YdedwvF8DA15K0tDUG6164DSVCUoMMmnDtJllUKd3sPaNdv",
+	"NmeFRhsYtOWc8n2ANa+CJVxFNIyPVBCxQL0HJkZht9xVf3ExgFmUpFUh/OEWJACKCHZpLYsj+yf4BMGM",
+	"68uV1TNCMTSjeHXQlKNsKoKLJE8JWHIc/IICFFylEcoD/I0Jli5UltN4Vwu6reXnUkPVWi4wawU5NI/M",
+	"0YXqucwYdq3WHdbrHuxlfpVkT/jrn/XHTra7zkK17cFDa5xsmg2drv/RAR1QqKTTLsB+sYzUrRMFJBQr",
+	"NmmjIqQvxciVY2HfyIbltTl9c7+j+kp4wT3kHvPmWeC3rzLvRzK0NBNBS3262YPUyAMBziozkWx4uCQ/",
+	"hTVZ2UaKlciNhGPueuX50988qP1GG5g7mJpnaZLBxQU02ThzgeHpK3roVPZIzvB8TBKf79u2Ab6x/tay",
+	"mvMMOdXrwpdO27oir03u0x4Ovz1uKyDDzrMiU4VIV4Cc0zQh7ydMDjLjtDzLIvKvtHTpFlqgqiTJWD4T",
+	"zhxq/fxbIbQvDN6kHDW0UFPUAhkEQgYTDE4RYMBKzrKGrmqk4wxuKyX2gLiD/5mT+VmZEDrC8ln2iw6n",
+	"Jd0RRdkqTUeszRn/AejUkySOdQ6ALdqeZTCRfpDml7Ah3IO1VHbq4TrERTJVCpbTDsw+Nr9/8ql+xe0Q",
+	"dfgr1VBwXARL46NysifnIVlHI6s5nldLt4OvzvQBbju3Mb+JyvcMs6vgWP4QRR5MqrKp7VDyDBsTODiF",
+	"YJrPYCOYG4meX2BTIN7hcNoiqW9YJsrLvDg3UPCYwUHrl4kM3XLkd/yUxEm1/YUSLYnh8OOas3xY+Vev",
+	"3ZXaoVYOughbAuAfqO7VcSidtX8wTz9ySieSofCjJa4WbgW3UWnVCHSnjmhRpw50YJ0hIoFslGDe95XQ",
+	"oc0QmpTLdTn5urTQqHEyLU+u3vxbl8lxnocYtU/S68E8KRfVZAzy06E2iRzCC+bfcSSAGdGz+DBaJYdo",
+	"Yj68uLdFPb0GuQ8c1L7Jo04o5XYPuoY6tT4xGSVIg2zGNof+ffIzAoENGhYxuLMpJZfraABK/9X2U0Cl",
+	"jU8tJlc65Rd2V/KTVEi4iuZJxmIaqwbLvGjVLkGCt6RqAGzIRliNdMqi8pVQFqMpJ6LyHq3E4jmlG6N0",
+	"4PJxtKxfg6Txp9pn2Gtgc8tKddTArlav0yZgACjkNVVQIbxS7E3uPWtEDexaVntOE5uj/4YjuPXd89Pg",
+	"UFEAeYsTCnloKxPMYR1VpXcahnq8VFwQgzMq0VD9DPPaE3z++CxDj+XhJJLJVB5WUhRKKRvP8+BxoIZ8",
+	"Bu+Qf6elsPvK61gqerCqJgBG9ES6MMrn5j47+xUJD7oD23F+XXlWTeUOHaAJQsR4EK9CFevh9xHVfjQa",
+	"mb3sfbOOAjU2UzoVS6LG94QzrFYytFy67u0D+uH2LTSUAX1ETmYMMio0t0UWrPxVeL4/5EqnR3eUytKv",
+	"0Cfz+zJa/QoLeRuEyrdyvFqRv5gctr8rpoY4CYsebjSpl1gP5tK1aeOs5wDhKaIQU67druBSRCs6fZII",
+	"l6S8gphGnzWcwjplg4aqN9Drv7PWsXPuIm3uhL/SoSnuLdAjOkJ6B7le7bS+6nnhUN/nKSLZlY/LGsN5",
+	"SlW5CPFuO3clEcX1yZhCGmwoUkoIumbwEqiaI5idvhDoTqawKvJDjxqf6+BVJUpp0pFILhPCKYqUy04u",
+	"BywfsoojJWxG2aadVAz7K7Wy9EYA6TnN61T4XbKIUWHiUKEQccZ3UQlTLSEHkdW+tjrcqHX4SvMjxrRa",
+	"BRwxwyEkGi0eG7zQ3/gvMktee7jELqQwYOjBd4CAAxCM/B4QXGGjON61UN8ZrREBV5smK97/MCPx68Y3",
+	"OMg25uJkJ5jO1uQaHaLuJGL8cogZbM7jEPgEzwPvUDtOXM/E3jslVlJVPIW4k1RYsWpS3WyUaS1Qce0s",
+	"39LcWALqas3V9TKaELHFh4UKugQRxoRakmtlCKPdagRHLNLR0EkzxCHBeVNxEXmjTbw1Hl5YIc5W6SBT",
+	"wUETtvZlGJlqHlxwUFd60OUddE0HWM4u9RnQ60NpTq7jyDOSMmLY6jxSwRWUQNX0tNyS1gHhOn6czdB6",
+	"GYSuaGm48/k0YRdCTcvVHAKF0LtBwHbXYPAILjS2lq0MbTBwAPTktY2kuywyEwnZ+yM9Nvmzrb+FWzPi",
+	"HJp8HXLqqFOkmawnCGtnRg0lsrqQ4pa0eQn8BZOgNM3h5hTc58F+syb/evRyLIf8OpEEM/rOR7abixpg",
+	"6qiLYSqJf6tk3iWnNV0Z1RVgGLO7GuHowEmlfUpT462AX5mIjvXCdUBIrbs20a6dWgL+kIQSNphNeO7y",
+	"K6CgJehmnujPLE0quJ1gWv7mjmWRLsQc7W+1zQoJmDbCfli74QUWFgKdH3MK0Fzm3B6+9K0k+fhbfNVN",
+	"kRugCrhEXeIxbdO0AJ0wTtLKfdpq3r89w2l/MEgvqwndKDhJEcHUEzKiIGNuTI/v9EzNSRS9G37JG34Z",
+	"7W2/w3AJX8WJizwvW3N8JljVoid9l8mBgC7k6J6aF6Q95MWKdO4t7MqOdordHvcZUjqXaefQeS/l5ZGc",
+	"e7Fk/95dcIYFJ1FYFQm7GdSeOwBMI4nXLbMGj+qJGCKdZgfdhZUgRxTMgRlsCwQsE4YrZwzLIaZWiL0l",
+	"RnAeQCevZjtk2tk8FkGwp0qkLuLcBRSiNgkD22CFSfh/E5uf8V3azsG70cH1rCAuWKsRt8D6tTleJ5zJ",
+	"j8RaccOouSPI4WGRA3BCZSvyoSa8pFCTXtempQ9M6twWidPnxy9fq+VTmpCICpUQ0rcrem/12ewKpU1X",
+	"wsSpZSwiAV7LpSyIWYdvylnZ9iWd0dSQ5ZCKKeTi61XbDq2rqOxNM7c7e6v1SJk5eYs95k6xMtbO2kjA",
+	"xs6mgTO6iJJUa+d6tdszsK5EFRopXNc1lNopTHslN53b7b4dNXZtoUn2XD11OJdcahbrprVjmlGEJKWf",
+	"UBW9chOh7PVd4gTfkc4YSliA25KTTSQiR8ZmcHw5oJc9wiiOWCUer0pWJdZY+JocoL61FmnN4QQmWdl6",
+	"YDfJlf+xypJ/VsDYYoxPh0eFynFoXFS8lzpRs8tO3UmhamCVF2qGv46MgUP5pAtaRL+AYRvdHSnJWuHU",
+	"GzXeAvzBspXu4LuzZ+ywxB6/m8IPhc0cabNoGs/tkv5d+oeIweVft/cT0JacBS/UM4ezP4CXWxz7OQUl",
+	"+w7nETVLoOXazIDTcaJU5o5hquwyyrjcN37HMFRfY6ye9iFe5gVVyJBuq1Iiw1mR/yHcmuwMD8qRdqFA",
+	"SeIifT0gJrW2ytSNHDR87XV4UdsnyVkPg6Zv1XPDCcstbwLlkWmbH7xEA3Jp8kakiPty2HEDhzx+fTnU",
+	"mjsBhWl0OYlcVTpRoMI1Hdd+q4Z1EsvfqI/1KUiTPqlwz3KBmXcTLisBa6hzo7o1o64oHH1eKB8Diixh",
+	"Cifw42nXYBkn84Tru8MRWAXE1UDcGIOxSBVhZ89gDRo4kKOR1aJAnUacXCQyAUmL3rjHb6BPhcsa2KUO",
+	"VFAiRo4sJL1+f8DrCwApXD/4hAELYDUCLCdta3fARJSXWDrniN6793VwmxwhMrkQdxCKShY5eHzva4oA",
+	"4z+OXMxONXLooysxERYd+O7GY/IE8RjIpNSoY2eJE24U5CdhPbeJPx1yl+hNRfW236VllEVz4XZwL7es",
+	"ib+l0ySjYQsuWcytI2CyfKNST7rzizJC+uQJC0Xyx8tQ6bGYI0ctJ/Il4lNdHZwn1cNxHwpVsVevSz8k",
+	"r9NKpzm3FOYPayBmXu7aNfkGf4DHTbCO0PFDIe1WeThFEMeeep+iuHBPUngOWPNN9S2GhGbhEu9OfKcO",
+	"OLbwz+sIcrtbfL6f/qGHilo4SugFbNUAbGTRpCuDuCrc+4wqnOqnNy8VY6A4xW7yeE0NFZMoBAwtLpw3",
+	"th0daCQTwy405F0CClZx7lbcViWOjTVThf45NDQfUPEB7nWihhoFzXKyjnvV9ZVom13XZo9P9PD0R3v8",
+	"8fYJ2npcRLWfeFIPqKyC0k6gxea55SeLAng0FHRPmkV3NfiuthvnLqokjX+u8xtaJbbhCk0XTmP7BD/8",
+	"re4ZYdbD18yZULGIskykzuGYeP+mibyDDf0jHzoPEKKB77arfvN2W5urF95cpl6UnhDBm5TYm7AB1WZ8",
+	"twncwljxgOapS7jVN79bB4PqRzvCgx1tzpo1I9Q3jWxUR2LQ0JJHSWYVxfMWPXKbWxJMEeIpwnw2c5o+",
+	"cCZ+5sqgRiO2nUW9l6Ije69K1E1eaEDXCQhfkRJdndpJmc0jra8YMq1IdLcoPdEQ0n3mGGxWrXRFT47c",
+	"UDWVHX4BX1Ex2+lF5wLjTwRW5UBV4wrs2UMJPZxjfA3W8WR/7KIG0Gg769Alr/9ZgezrKr5DDzh4mayj",
+	"eDh8NLDCmPS0ccDFahDkjQh+0o9UWm8cpCLG6ihstufDHgU4DvoTAp5VqlJ7VCSFym3PufBRgxz5M9Cv",
+	"my6u40X3EXzpieGhnhOmRZ+6HHXkVvN6CFN4fU7GGDRLYynmwbGT9Y11lfSiZBmqLQmnsly5MrrwjVP9",
+	"AqWN2b4MUm3s8xsHz1irlFpnURk5NfU30yk5ltgP/qMsI1h1rK7qAO46vJK9ZoDS6shlmhuZ6rBcZQvW",
+	"rYrZcy17lft/mUjuKogFkxoM1GRUqjPUSWXN7cExZIzLbt2nJz/6KmDXi2NWqd0dzpW1AL8jjeSuCbsW",
+	"9j+hrzy1A5qDdVpxcfEO0wpHd4sFjSTP4O5gwRwX8VQdCof4AgfUFmoT3Trnn2iI43I5exOYkDgFRW+3",
+	"Ak2qFeA8/Jef4qEydvCfJbXC6zDake4/oWyEIBiKwpRGaCSpIUdux8VsYc07ohFF+ntU4W/xGanBiYrO",
+	"PU8yqoWmwKYCgdmKRw3USi4VHsyx+q8rx1v+it+MqQoMrPjtWDdcozHYPYnbZl98d6hj7ZlXnnB89ym+",
+	"G5Arsv65kVXAk8K3alJ/txKn6oF1JnwA7hMDLOCa8e3RetCtN6SGOD4iGlYfAawQK5IUOojhqaj4XFV7",
+	"yVU1tIBD2Zxpx0nmWMZLjEU2upGDQUydLIEOhu6r5zt4H4MJB9M0dMSTF95F0OCysFviukO1K5IgSGiP",
+	"eg7/MdZ9VDyEw7xQ64iYoqMvBWK3Je48pfanCpDdrigk9ykxL6b47VafFBfhQMIdTnMgmVEWpRuZOCOM",
+	"YIJpgG8F+i2X5KSWNjL53nqtuDLy9MNHaD6S1QQocAmQlINFquegMW1eo+D7FNbhkSJ5O8hysNiZi2BT",
+	"5yE8Q6rTNhW9+yBPSMHCuA6gqgd3khGzCFX6qMlUt1ZCNZ+zMB7SvfZUT2JpHVWEVMxKVxFfDX9aOHyA",
+	"L/+2qspPqPSr2bA5r13EGV/yZJxItJcuJ6kjxveZeWh1saL8CgAh/tdVFNG/AxX5c4WyzRzmQx/urEZt",
+	"rRqcTEPMurnG1aZB6kycj3Wt671c5V53NnGFO12v4GqXuv7+yre6tY1P+UbXu93rldaFsD+RXeYrVHXo",
+	"enlMmPQC3yw/j1H1KRpt3jA7i4W8SRXPhfNzJJmSLbpUmUyXR2qk4tt3NS7EZd07B6dAbwKNPyCmzaan",
+	"LnGndYu7leFMIEMDLkpimZ6DNLMqiY5ofb2O5aRFc5IfURmBc3GognQZsl3zv4wKrB7TPhSUtbCRdQ0Y",
+	"a3A7g1IVENXUjdoi4AmMg+MJB2ygozDN8xU2MjHFnyTGdkxQ1/QZxPGTcIC8rHGAHRSkIeH81URFsURI",
+	"01YCu0eoCgtpssQ6yKY3La0G3dr9C8J+9XQkIR2JowApjsOpdAwWOj9cAPqEg4nY5FQxEVu8qcg8lURo",
+	"wdYBNxwBUPnSGrSxlfFVVQ0NOspxQW2b6ueohkLtdbn1EnPyDgYq6sYSNoaosY0oWQg29gA9Vyc3INKM",
+	"lYx6dufVQxXOLsvSKQPNSp6pmkKsI9ddjonTmHz/5l3SbMoRkmHKevYbxf39UEekhnqSgd7Udfoi1nQ5",
+	"xseXEjT1ZrBFpcrYhV32tfzwZ6JyPDHnnNIq3A5+XwwxhxDj487Xw2w0HYsXjd0LUB2c3l3Q33TmC5C9",
+	"RAWw1ZJkF7IqR87vTukV7cwBtzehMs+87hG7q6bDvYltgtJNsEyATJyreDvmhYQrdRR7czNuY4Yy1pH8",
+	"ry6tTSMoDI+XQkZbjMdwE05fqVvrL4sqgPKxdCGSi6Ct3ATtSsssqtSxxsxEEQluOkqvbXN5qZeISimz",
+	"SN+Fd1WxdZatVzFGXHVXl/uNLJ+W7bplKVmkwDlQOmhVCd4tf1B95Bbo9EKayf6jZoeMreV/+yvtXWOO",
+	"hpGyOwNnI1recy5o2+CNNlg5OLR29JtDiWQ/tLfV6jfLHFnplho4TVC5kMhfr9gthVflNNeBXE4E6mLI",
+	"nkR7crKqpISW5L69BZU3gKETtaBn58gFlLmadakb3qW9RDP4whSumN47zEzQ4W0OncxOzNoiVJw3GCGX",
+	"Rmr5WvJC7JkhWkbmHRliN+Vs6PZoH8TnMeS7s8/BB9CArQf2QwBfS3Nd4PaVAxkihLkrzODnJAUyQHQN",
+	"pO6d+2AyHO9TjaHmdZ36zz7/OvuQPVFjLZhigNm2w23EANbFbCnK7bcJ7OCDm1v0CjgdqnvdVMHHXexw",
+	"7UMgwDj22pjcmsqK7hsQ2Kc+c4TxUfMteDkpN5Q5qX0xyW/OihRYPLggSr4QEXbbMvknKv2By5SqwOC5",
+	"ebuS2nDxXY6NpvLlEs0tJOeW1JXm+TrC9tPqXnxza/IX8eCvD+OjB/f+Mvnr0aOjqXj46Oujo+jrh9G9",
+	"rx/cE/f/+ujhkbg3++rryf34/sP7k4f3H3716Ovpg4f3Jg+/+vovtyiuDZbMC9VxUI8P/k41p8Pj1y/C",
+	"U6rcWh/NKgGiwsU/EY11WVHgP+TAXEZJCq+pn/63vmFYiLceXv96oOKsDxZluZKPDw8vLy/H9ieHc+qM",
+	"GZZ5NV0c6nm6TYBevzBBVhwVRyfK0SnUb+KgRoVjevbm+clpAN+Na4SBZ0fjo/E9NhaKDLYKPz2gn+j2",
+	"LOjcDxWywb/hxUMAXUpWF/xjiVHeU/1IXkZzIDVjVV8Vf7q4f6gjIA7/VNLUOxx17gqs1L3NTAROt+yo",
+	"MiOSjKJ7mTV6iHANoVEtlXM7vSymGBm2OyJpM8DCXkC6/seLmlDpBFCuiPH4V0dd9Vkyr4pW62NjxFCV",
+	"H2GtZPiHQ3rFmsdrNBxZcSiEkP+sRLGpEUaRMruUg64RpqJVlnK+arp2axWoR2Np1G+lmfGcLUw1Am9N",
+	"iUCFE/ZKarqKtBII5ds/H/313cGAhbxh34EtreuyG6ZIel3dltRHuKhG7bNKOI+DN1yXOU9j3X4F31mi",
+	"xMtt8LC/lM7KorC6nArimXApVCGK6SLBCAUsBi0taVXnkCQonGDUEH7hPS0Tq2NA1FEx3lISC2EGXar7",
+	"R0d7K1BswhbZWWlG0ShyhYFwqId7XGLTBHjthbaH6xDIV1GKVwgPvq7e8PDo3me7oRcZFTVCCh4wh6IN",
+	"PXxvG+q2naEr+UNeBsf6CuEaHn3GWPICbVVoYqY3rXxKR+H27DzLLzP9JpncQVwBKoDyj1Xk1pZ033k5",
+	"YDOTWZXG87NFYXXgswqMNiLxUI/n0UeBpCbt+NOqSHKU48hAEwtMtSKpKy8ogLXu5Wc8IPTPV8d/p3A3",
+	"+C83ydSsluJ7HNNzw9gmT4VlO3pNPtkcGx7Ty2A/Ga51aoDk6QWJGaKcjExAW0brb3wgW7Ns5uIi8Fk/",
+	"Dxl9PiLIddndTcfSz7Zj6QCifXO6N/1oP9t+tJ+3WLw2VSiiABPiMyq4fIF2V2Nl/OLk5C9LRn109OCz",
+	"3c2JKC6SqQhOBXxbREUCpOCnLLIViquL4IbmAD2ok8p66U+b8FhStCW+W80fQISv/wqTeLstq1EaFYve",
+	"l7Vk2KiaahWrN1FdqjTCqK73SImzlE6ic/9Guu4hGU+5wCifx6hTFXHsEtItz9eTzYtnQ+Tyxp6scmwu",
+	"2bwBr14RfbvEaxuQbH+mVQfyxpJ0DUuSnbjq4PVufH3fXLGzjidwkjoz+T3zq0/QEGOfAppjviWcec9s",
+	"7r3aTtxoNZAAH0646kAfEW5SrBfPiC7WdV4skqyrA5giBcad2ks28/WTzQ+cRffJ0s5TVcHMXavFRY3U",
+	"o62T7k3t7/UAY6q6gxZgYYkbWvSRaBFC/4ugQZMmGqlSIyxCNIJRBtMkvgG7UCVtMjRVUqTuk6fyCmjU",
+	"AbRIyE+ZDqESarXKUSSIrKi8f21E1cX/qJwZnAf+9E3HGm0GuLJd9bpUqxXOoo9+UNBSs0LX1ogQGnuI",
+	"WeaJAWunus4NibwR164lrtmXbgChpI6BQCJVKeIBGrMq893UlfnHfi2ZxTmuyEjFDzc6/GOaoILMmr2P",
+	"huIMQxXgbiVyF9msqy/vS+mleW/U3T2pu9w/0nFn28h2QzNvaOa1aGYboWr6SDUCYVUlELGllzb+tJoX",
+	"UWzSNrNMdZHDpp7BpZhIGATzcC9MSgRWo9qogsMUDIDzoEiFcc5IJOfsUTF31wp6Z6rBZdmUBwYTJhO4",
+	"/Cpsn7J4YBj4qKA/VPxAnbauqM1z6sBGcyMp5uE5op2XgiQMEx+yOYyuknNGnCtF5YlmSOuIzWgndhRM",
+	"kgxgaz2y/Nqq8SmTstSGQMGEzxSohSPCooJFvvxfMCY2/YMfCk5XnVH2cpnn8K8CXl8kXG0rTqSCPtJG",
+	"+I0Lr6nfuuBQXeHSNL/Uwa7YooyyhZPS9ELtMqQTwocnhBxfUCjiqUnlYdBgzTLaabdQmRt2qmZc40z3",
+	"yF7uMaFteS3gjgC/4xJwlMdmLpxpEMmIrvBX+a+1mYVOkXJDdG2yG37yQfnJaZcysXIZYA1QganHGCWv",
+	"SowyQqoI/Y/Mcd6ns++DeeeYlrV5kdTdgZLC5j2ywxr/pAOz9YaO7E437Asjk4r+Yd0HleQIzEiUhp22",
+	"kmoc+oemf37lo6+7xL7tInRE3aqntBeVOLJLZWP68HvO48Di3DBTd/QfdREBfIwZBYhmuj6kbqJC6g1r",
+	"kyTmqHIUqvECiSnUrkETdzzFnVb5tJ68a9IhsFwtjuoGwNcBcIf8PddeEYLYB+LU7zviwmL8QQjKVKYj",
+	"fXR5xJvA+E9rQz+galD3bSdcvAm0N5q0KatvatpYhi+f6NAMt/+zXKOPBqhnPusTKl7TC1uEigFdBwC2",
+	"IirklZn0MNeuPeOLZ3Yn8tzkXHLNqnzmWQrCZccY+v8cEkD/5capt5tBO0pbUEFfV58Iu0fELWy/vXG3",
+	"CuJ65oyqLU+aKM5TwUfairUFXorUXS6S1YdvCAWka+JuhfU9/IorNcW6X2RPzGW+EEUyo35uBkk/Yk96",
+	"PEwNeWtLQwSJ164DsXubfGjtv06HY1Kl3a9Fi2p8VNNA+VFMA8BuQ+K2aIpUkl8DLB/PCEBV0ewaf6b+",
+	"InnFqxUo6SQk2HRAjgexV+ENom0QFfbuedFYMdsp1sSuVod/0j8oK/1dnf/NduRD9sf18dsTfmOvqUM8",
+	"JlYe1AU+7EIIykcIG32FtYswU97kCsgN4ES3RJf69Le+tgxOGg5KUJKJcAno4Kih8CM9fUUPndV2KB3B",
+	"8zElhvi+bTf6aay/tazmPENI3XXhO/40rJHXEkdbuwVYmPTL2o9S35ZCzJHcFCG2wU2myYrjarD7ZKNE",
+	"gnpdLqoyhiVYv1CFg96bxG/s9Sb9AEIYj9ssKtLtBRiRV1gVYuheIEMj3OWjNDTr99gjk2BVL/L2R9V8",
+	"UXLzS2dnXfNhGE0Z8UNWB9wTWrFIbJak6RbRBYjLKfbUwYbvAvO/lI1YnSttMpKmmzGhAlNCd2s5x7rg",
+	"5Wz7uvitxhyq9Ad65DzLRZxRfbMojhWFq2Qvq4fznGIYRRzaHZb6NmCKc5gqtr5Tpn3Q+s0s2EkXnXBX",
+	"WywTtP6Ftlt4muUam5WiWd1VD5u+D/3ak9tIiMEXmnhTO+ocy+GUwgfCnWDSh3vtRSkMdKEfOkVrVEsy",
+	"VdA3KW81HtDJS3RKR1i4JQVtB6GKunWaUt/C3iVr9H2vKGfuyBUxrlpRV5/u0p7yU2yXhZvOoiyX6DOO",
+	"pbvkciTLcBudJDeytRcpuHmzvtzOMvE4sEdyeQnPVFOpLCZTqqzd1aw24BT+BXt7beHIP5tKYJ2x0fEu",
+	"Mgl8xfTbYtFWxM5uw2LdM9cP8FTPhXhrxjayM7cW3zayD0rW+KYDV+2sxuLWxgSEwzk2d5mkKZdDdIKy",
+	"sYgaEH0LOdFvWdC17SyehSSyBjQjDrlEbcyx2n7LMl+t8P6VYZWZ73xgOuG3j8uf6ne7yKWCJIgVxdhE",
+	"ydJr1MovddQG8rAF9jTmkbGIuVKJ5qr0Q3fNeBlDCURchH2Yj9fyBN+yr8CWS9qWqu3r37hnrcvRwl8n",
+	"0nmRYMsp+DbskuM/Cal7V7W6bbDZzc5cT9P5bouU31RqLOG2Fur578PLKCnRN8UcP6QStQ7/dav1QITB",
+	"/axqswUCo7rIaKyK3DJ1UeNYnTClnUTPS9CRR4gK3aginOrbvBjkLq8t27Ac3FgA/DTRVdfw8hkJ/9Pz",
+	"Pd/oLje6y43ucqO73OguN7rLje5yo7vc6C5fmO7ycWKngzDUdFrXUXJVUQoOPkv96l8ndvm6uqClJOg+",
+	"JKAg4aXujUoqRZQeqt7aFD7ibODBeZB2n25q5oESRxqhNAc3zOSKtyo66M4e3IiAylzACw/uByffHz+6",
+	"d/+3+4++QlJEQRjNd2+rspywrU0q7qjoTVNlXIdxqow+FpQirXtOdYSPyp5H4QrTPoPn9PozkJ1SVKTY",
+	"z48l2xzKKTZoeKqAs0U3pZaEKmr0dxzt91FDJVZwW0arVpszzjbCMKNmwsnvsyiV4ndfkBGPB8O5sknq",
+	"3mhvmbICoXiSx5sW8uOxHdIJNtG+jnGhrCZHYEo3n6GNG7ADbHSvurZ31O53ew1zcof2dPFsG4q55JjC",
+	"0ymnD839vdLxwNx9WBVW1HjS1vXfuSKybbZJUDALHuIrR/zWZxSolkAflZcFtCJ15Wq6/QkmB3U+MxSF",
+	"PkTZSxGmTytL9Ip8Rh+J854TlRjhFYirqaDCIAoX1yG+NBcgjPERhxMgQ2GDhjV5Efdd97Oi52sxrfDq",
+	"2m3rbss7yIxob+uyYY6klu9zSg7tmNaovDSNhxkrH4e9cAffrQn+qdS1SBSzaPdtpp7SChyPKZmTqQoH",
+	"siJa6jxZatQkVqM6z7bVK2+kmnWNuKXoKJguomyOivy0QMk8kGleypFKkeKH8Cc2G+ZigJi4z0q/j3tZ",
+	"Xamvzr6ufoUY6Cb78LrR5u3hunTWCle7DeIY9ZG7Q8DCdrpoXVmu4F/ahI1qB7Vdww90hkxN6kky2C8H",
+	"5eg7V0Mgrer7rQSvtTHA0oUbzZn17wwn6rXGFwFb7mSxKNydqdaZ3LER9uk6q7lYb90a3q9jd2reIdxT",
+	"H7uKGTd2fNhaCIMw6WnWcELrWRQwjbtJuf2X56pwcy4SNNc4mVQ31LWmHeOtzLWwqFuLux7KLFrJRV5u",
+	"0/ias6um6ZrNazzWeczUy9qkxXOpBmYRdetMOACsEVPmj0lK0GblkV2ox87CpfJSXDSWC1aowjr4GyZK",
+	"1zyM9NoRORlh0uWKvulQILKS2ZKDxCbTMQ/DZWlRDtDLBUKGGwR6lcyoSyRWdFfeC2WjJFW7rV5fSZTY",
+	"jyRxoo92i0TxBrfdOSlSQnDXDE0SpCq796kChU6X1JUQ1LbbBRMaZX6uWRJhiBSwDpXGem11FtNgQJMz",
+	"6l3nHpADKVqBRCasWiWUmbFfTfdagsdx+47SBdD1RqrMtIuvMeEjK4B1Ysixyqxtpz581myLygba+GLR",
+	"ElVcMPpQzYw+jOJo3ZEGqXHwliATItZxJ7FWjLawuegSe+s2uFyq3HzXMWZqq0jC6iSAEAsccG9DLBuB",
+	"WpTqSKmbqqNKVIF6FElVHJ3K7nS6D+N1o+Hw8RtxLjan+Sh4muZSvMHmjSgQn+aIJ98K8XFYyUv2xfcy",
+	"kJfs8m3ZMX/HNiURnvjvVBgpC363EPb3JouIxTQhiUE5WlntlI1RK9lTR5Pda900S7OIg4ZmN6jQxUvd",
+	"yp1vI5J5PCxcg1ivUpySFTD3muJE0t21lzW02yp6pDapTiE96K7tlwj0/wTbcoOynUwDs08sgZhf4gEA",
+	"zgDbLeZ1Jb1E1r3Qj3UvdE9Z0nAmxDV48QcwKONZvHd78mVE4WTD1U9Eml/4o62qpxl8sIUW6dmNefZG",
+	"kezhssSeOnemj1ntbJ1tdYJyBo6eCKzDyS7HRpsT0/3EzjG3PJcYjICcby1Qe7I/5agttHzWX1OvwEZ5",
+	"A9Vty8yjau4NKhHK8xZ2adBW5Tva1bd5cdpsUNXLH3+0Ssw0gaEmxopsyvsEO0GLrSMlf5aINPZS7LrL",
+	"oD8kGZiI69NyHapW6l32uYrwRRBMCjFHDjqd0X/WMyorG82KPxRfHdbrlxR3KmLr3ENdktbLdhBV57lG",
+	"TBqQU8PdP//ZU7zTv452RfGhq6m/c6yp9bBzX7itNDrGQeIRs2StUFrHciijYo36QLkEY4UPKfCNkAfr",
+	"QQx37SJ2avIuep52jQmSZDoJwm0aq/DZTLW2BtleRKUtj6gWFnYKbqRUBEUKVXjhdBNUWYo3JFIVjuFo",
+	"UAZeseEsHtVEYAqLsyoSU2AYABDkRy+k9CShWmGIKzzYtRRy39Zp7Z/uvnGKq2z6hSJrhdp8VJKwb2xh",
+	"qls6BpYlKg7LK3QmdaDW9ZcwEYCsor0GkGv716BfONi5bwDmC1hxsno1decAz5RpskzKg91r6lD04QpL",
+	"ktWzjYOfFArQU6paYTBF61ZAEC6SvJLmIx/1gCG28ZN/zVo6KuLZG/fYsncOkW3gDNPIpNMwNUDR1Mg7",
+	"G1/9HYpp5Pok3QoBUpUNBTwBfYud2xSftKTLYa8AiwUvyalLd4YEnZFGHiZajE9GWlIYqCQfruVEhoCl",
+	"24vXEhsHqVNPtRjX26PUXUeiDt+8flvYGwT4khGgz00vGShswWOo3Phr/6XCd18orRCr/R9fv+CwWzEm",
+	"nNJm6qbS+Sa6tJH/47vAHA2o0SZX5FE8xcwT+CMT5WVenL9n61y5fuEgv7RMvK8OLRqj0cZb66vRuDvS",
+	"CSxsqCM3sdO3lNxK4RP2od2kAnwxZcyf6MuH9oMiumxfTmbXdCfHO3vS7AKlxO79BZ6sC/Ga39xrsnRn",
+	"+GbOtNV8iFMQRbrC9hlpQgmKsAigQdPyLIsoBcra2LibT60Tu/zhbk/1K+4sPEeSnBoKFkASn0mMcspL",
+	"6IHpzIleHRVVJ6v5nLsGNOyEQpxl6q0kC6oMw2tgLvL6hGxrQOM3UvQxv4mSHzYVQUT5QxRAyjEOoCEC",
+	"oRFKlmhRYLsGTgOjwkbQtIFE/1WCQXc4nE4zMUUJGO/qfhROMRbrwMpEhu4I/u/4KdXoVNvXqSIUksKP",
+	"dfG/D11UVK89ib0rB+7AjTfgH9gLvLbjddb+wdJL0eLiRDLk+KoEQhu3gtuolWgEulOnUatTP8sw4BEQ",
+	"iQg9uvevgg5teb5zF/l2tLCmcRCtbEG917eu0u3zPESVL5rj73NQb6rJGKj7oTZ8HsIL5t9xJJZAqvDv",
+	"+DBaJYdoXzq8uLdFPrgGvQoc5OqGc38hnPu7ZtM8dtSpg2els3X2Hr4sMpR5/Q0MVUFIpD2U/cTvN8j8",
+	"KJAma3lVJDl6Ccm+HAuMtpQkU6PDbYQRI9mUwrZVsrHgNO1Xx3+nFqnw36DTDtU1J7AgV8PD1/zqLm62",
+	"U7Ok2grbYGIYZ5LIVRptdBfXb3wLXGdXb9n6L2QWbUX/d89M8Tyy0uB5dDVHGYg1/As9tNJy35IKhf14",
+	"uoJZma/Ctimp2wTTP6OCNx9wKx6nt52HKgzeZ4fSRWr713faqgLSAIeSClb5IM7YAYZzBVezed6c7md7",
+	"uo7w31WOdzoBMrexqLdmB80YDZbUKGrBruJlg5l2EPxPXlEMsWrEaOgb0DAKmdMMB0V2M2fCMnsNIZGK",
+	"peC6QPTk7t32xu/eVWcOA83EpXYh44ttcNy9O/4i+8Z8WT1W3qfo9r538z4lwcjcSLgtJlSq/3Z2rmWv",
+	"hKhazGztdN0orE/tmqO6cakh4PZrjZ7Y3RzbpBwHmFGDMarIA1TNKoxakLrxFlzvZYJV6GQ1nQoRPz7L",
+	"wsZK6j59t1uNNoOz6ujogQiO7rS/YbuFRXm735KoSo8oHRD+Pjs4O+iMVIDmd6GLbtHrcUXxqPzV1mH/",
+	"zYz7Y9E5OrTCkHFlgfUVka3JajZLpgmDnJKconnequ9j94lUfVWo+S1nTiWSw0B0wrBqruASurv8/YXV",
+	"Y3pbVnILXW56+LwPAfsZdgJNpSkH6dCnSLNpYxam2Vo9hxVV0d07hAnKVFnGapY0ORd2fhul0l9GRazf",
+	"6Apvjcg59Jq5TUvWa+wN1yJBe9EzM3OiEv6o6qI9gNuYyJFS0zRHnTXkEKxtle1M5BRIOmg15YtG8iqt",
+	"awZXh8sFkjULszRCLIbKkZd96+gDhWq+fRUgSG9PJl4cn5ZDQn3DD0wuQMQBaAjU1gaRqHAeCtV95SRN",
+	"/5x9wH7Kz3U8nLYKtmzwjnE1vvZFWzCKXhJz4UiFFhBtrMfSL9QQxGOIpuTPkLPvqe3rNokBy7cK6qFM",
+	"1tp82v28ueSzs1/T+OzsbfAS31VpUOdic0hhgbqQgoGRfV+4VivXqrDqy7XAOCi04piPs7n6tsaD3Cs0",
+	"NQE6jvF2zbk23M8TTNIIkF7pjFKPMhHcRhSXJgz2crHRdSSZHd4B8QF0PxC6yk3AFLZl825Nnt0q++Zf",
+	"2wy8yRkdpX+oF3pxzTulh+m/SYCa8bWn4kH6J0Inn/s6RZcO1Xpo21OHJt3Say2k4lXsw0Bxwx1vuOMN",
+	"d7zhjjfc8YY7fvHcsWOUujHbfAizzUc33HxBLd9vurt/Yhuyg1kx1vlbkiiuZ81WHGvqlMbddmpV8K6n",
+	"CPFzrl9IuZLd0iHRHONrym5tFTslY2SVcmLXJFfT4CHUB5TfQGFZI1OvQ5FQYq9AGU903YU6Y0N3O8Bo",
+	"ijat4CwLTMrLmIM4UozV9psh373W10/c/vkJ1msyOPZ+49P7m2o0kNP01agrbC3zWCES51Pqsp2MejvK",
+	"sS7pdQZ6KmBNuGRs8Eg7IpK5XfjrktJbsWqUQLSiSkYK56LMhA2CkhBlUxFcJDlXr5Qq8QjUshEyP/7G",
+	"JG0Wqm7azgGQtoKDOlm70BxebwU51AyZOIy98ZBq3WG97sGpSq+S7Al//bP+2BlDsMaoa9r24KEdRMFX",
+	"3nJ0QAcUKlHM0dLLIhGtEwUkFCsOTUWpvxOSWpdjbUqsjUjL5vTN/Y7qK+EF9z4S9G4u3s3Fu7l41794",
+	"HfbKm2e1sstZ61rFN4lPN+HTHyRoRl9QV95TM8WScXTH7CdON6CmC2R74tJJJINHq+S3c4H/fotyroQN",
+	"avG8KlIYaFGWq8eHh2TxBApZHlK9lPqZbD3E+xfNeQS1llWRXKCY+u7tu/8PXMxV3SldAQA=",
 }
 
 // GetSwagger returns the Swagger specification corresponding to the generated code
@@ -19,6 +19,7 @@ package v2
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -37,9 +38,11 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/node/indexer"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/rpcs"
 )
@@ -52,6 +55,9 @@ type Handlers struct {
 	Node     NodeInterface
 	Log      logging.Logger
 	Shutdown <-chan struct{}
+
+	// BlockStream, if set, serves the block streaming endpoint
+	BlockStream *BlockStream
 }
 
 // NodeInterface represents node fns used by the handlers.
@@ -64,9 +70,11 @@ type NodeInterface interface {
 	GetPendingTransaction(txID transactions.Txid) (res node.TxnWithStatus, found bool)
 	GetPendingTxnsFromPool() ([]transactions.SignedTxn, error)
 	SuggestedFee() basics.MicroAlgos
+	CongestionFee() basics.MicroAlgos
 	StartCatchup(catchpoint string) error
 	AbortCatchup(catchpoint string) error
 	Config() config.Local
+	Indexer() (*indexer.Indexer, error)
 }
 
 // RegisterParticipationKeys registers participation keys.
@@ -97,10 +105,13 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string, params
 	}
 
 	myLedger := v2.Node.Ledger()
-	lastRound := myLedger.Latest()
+	lastRound, err := queryRound(myLedger, params.Round)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
 	record, err := myLedger.Lookup(lastRound, addr)
 	if err != nil {
-		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+		return v2.lookupError(ctx, err)
 	}
 
 	if handle == protocol.CodecHandle {
@@ -113,7 +124,7 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string, params
 
 	recordWithoutPendingRewards, _, err := myLedger.LookupWithoutRewards(lastRound, addr)
 	if err != nil {
-		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+		return v2.lookupError(ctx, err)
 	}
 	amountWithoutPendingRewards := recordWithoutPendingRewards.MicroAlgos
 
@@ -122,7 +133,7 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string, params
 		//assets = make(map[uint64]v1.AssetHolding)
 		for curid := range record.Assets {
 			var creator string
-			creatorAddr, ok, err := myLedger.GetCreator(basics.CreatableIndex(curid), basics.AssetCreatable)
+			creatorAddr, ok, err := myLedger.GetCreatorForRound(lastRound, basics.CreatableIndex(curid), basics.AssetCreatable)
 			if err == nil && ok {
 				creator = creatorAddr.String()
 			} else {
@@ -143,6 +154,29 @@ func (v2 *Handlers) AccountInformation(ctx echo.Context, address string, params
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// queryRound returns the round at which the ledger state should be queried:
+// the requested round, if any, or the latest round otherwise.
+func queryRound(ledger *data.Ledger, round *uint64) (basics.Round, error) {
+	latest := ledger.Latest()
+	if round == nil {
+		return latest, nil
+	}
+	if basics.Round(*round) > latest {
+		return 0, fmt.Errorf(errRequestedRoundInFuture, *round, latest)
+	}
+	return basics.Round(*round), nil
+}
+
+// lookupError reports a failed ledger state lookup, distinguishing lookups
+// at rounds whose state is not available on this node.
+func (v2 *Handlers) lookupError(ctx echo.Context, err error) error {
+	var roundErr *ledger.RoundOffsetError
+	if errors.As(err, &roundErr) {
+		return notFound(ctx, err, errRequestedRoundNotAvailable, v2.Log)
+	}
+	return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+}
+
 // GetBlock gets the block for the given round.
 // (GET /v2/blocks/{round})
 func (v2 *Handlers) GetBlock(ctx echo.Context, round uint64, params generated.GetBlockParams) error {
@@ -284,6 +318,8 @@ func (v2 *Handlers) GetStatus(ctx echo.Context) error {
 		CatchpointVerifiedAccounts:  &stat.CatchpointCatchupVerifiedAccounts,
 		CatchpointTotalBlocks:       &stat.CatchpointCatchupTotalBlocks,
 		CatchpointAcquiredBlocks:    &stat.CatchpointCatchupAcquiredBlocks,
+		CatchpointTotalChunks:       &stat.CatchpointCatchupTotalChunks,
+		CatchpointAcquiredChunks:    &stat.CatchpointCatchupAcquiredChunks,
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -332,6 +368,51 @@ func (v2 *Handlers) WaitForBlock(ctx echo.Context, round uint64) error {
 	return v2.GetStatus(ctx)
 }
 
+// StreamBlocks streams committed blocks, along with their state deltas, over a websocket.
+// (GET /v2/blocks/stream)
+func (v2 *Handlers) StreamBlocks(ctx echo.Context, params generated.StreamBlocksParams) error {
+	if v2.BlockStream == nil {
+		return serviceUnavailable(ctx, fmt.Errorf("StreamBlocks: no block stream configured"), errBlockStreamNotAvailable, v2.Log)
+	}
+	handle, _, err := getCodecHandle(params.Format)
+	if err != nil {
+		return badRequest(ctx, err, errFailedParsingFormatOption, v2.Log)
+	}
+
+	stat, err := v2.Node.Status()
+	if err != nil {
+		return internalError(ctx, err, errFailedRetrievingNodeStatus, v2.Log)
+	}
+	if stat.Catchpoint != "" {
+		// node is currently catching up to the requested catchpoint.
+		return serviceUnavailable(ctx, fmt.Errorf("StreamBlocks failed as the node was catchpoint catchuping"), errOperationNotAvailableDuringCatchup, v2.Log)
+	}
+
+	latest := v2.Node.Ledger().Latest()
+	next := latest + 1
+	if params.Round != nil {
+		next = basics.Round(*params.Round)
+	}
+	err = v2.BlockStream.checkAvailable(next, latest)
+	if err != nil {
+		return notFound(ctx, err, errRoundNotRetainedForStreaming, v2.Log)
+	}
+
+	conn, err := blockStreamUpgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		// the upgrader has already replied with an appropriate error
+		v2.Log.Infof("StreamBlocks: failed to upgrade connection: %v", err)
+		return nil
+	}
+	defer conn.Close()
+
+	err = v2.BlockStream.serve(conn, handle, next, v2.Shutdown)
+	if err != nil {
+		v2.Log.Infof("StreamBlocks: stream to %s ended: %v", ctx.Request().RemoteAddr, err)
+	}
+	return nil
+}
+
 // RawTransaction broadcasts a raw transaction to the network.
 // (POST /v2/transactions)
 func (v2 *Handlers) RawTransaction(ctx echo.Context) error {
@@ -345,8 +426,25 @@ func (v2 *Handlers) RawTransaction(ctx echo.Context) error {
 	}
 	proto := config.Consensus[stat.LastVersion]
 
+	txgroup, err := decodeTxGroup(ctx.Request().Body, proto.MaxTxGroupSize)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	err = v2.Node.BroadcastSignedTxGroup(txgroup)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	// For backwards compatibility, return txid of first tx in group
+	txid := txgroup[0].ID()
+	return ctx.JSON(http.StatusOK, generated.PostTransactionsResponse{TxId: txid.String()})
+}
+
+// decodeTxGroup decodes the msgpack encoded transaction group of a request body.
+func decodeTxGroup(body io.Reader, maxGroupSize int) ([]transactions.SignedTxn, error) {
 	var txgroup []transactions.SignedTxn
-	dec := protocol.NewDecoder(ctx.Request().Body)
+	dec := protocol.NewDecoder(body)
 	for {
 		var st transactions.SignedTxn
 		err := dec.Decode(&st)
@@ -354,34 +452,217 @@ func (v2 *Handlers) RawTransaction(ctx echo.Context) error {
 			break
 		}
 		if err != nil {
-			return badRequest(ctx, err, err.Error(), v2.Log)
+			return nil, err
 		}
 		txgroup = append(txgroup, st)
 
-		if len(txgroup) > proto.MaxTxGroupSize {
-			err := fmt.Errorf("max group size is %d", proto.MaxTxGroupSize)
-			return badRequest(ctx, err, err.Error(), v2.Log)
+		if len(txgroup) > maxGroupSize {
+			return nil, fmt.Errorf("max group size is %d", maxGroupSize)
 		}
 	}
 
 	if len(txgroup) == 0 {
-		err := errors.New("empty txgroup")
+		return nil, errors.New("empty txgroup")
+	}
+	return txgroup, nil
+}
+
+// SimulateTransaction evaluates a transaction group against the latest round, without submitting it.
+// (POST /v2/transactions/simulate)
+func (v2 *Handlers) SimulateTransaction(ctx echo.Context, params generated.SimulateTransactionParams) error {
+	stat, err := v2.Node.Status()
+	if err != nil {
+		return internalError(ctx, err, errFailedRetrievingNodeStatus, v2.Log)
+	}
+	if stat.Catchpoint != "" {
+		// node is currently catching up to the requested catchpoint.
+		return serviceUnavailable(ctx, fmt.Errorf("SimulateTransaction failed as the node was catchpoint catchuping"), errOperationNotAvailableDuringCatchup, v2.Log)
+	}
+	proto := config.Consensus[stat.LastVersion]
+
+	handle, contentType, err := getCodecHandle(params.Format)
+	if err != nil {
+		return badRequest(ctx, err, errFailedParsingFormatOption, v2.Log)
+	}
+
+	txgroup, err := decodeTxGroup(ctx.Request().Body, proto.MaxTxGroupSize)
+	if err != nil {
 		return badRequest(ctx, err, err.Error(), v2.Log)
 	}
 
-	err = v2.Node.BroadcastSignedTxGroup(txgroup)
+	result, err := v2.Node.Ledger().Simulate(txgroup)
+	if err != nil {
+		return internalError(ctx, err, errFailedSimulatingTransactions, v2.Log)
+	}
+
+	// Encoding wasn't working well without embedding "real" objects.
+	type simulateTransactionResult struct {
+		Txn        transactions.SignedTxnWithAD `json:"txn"`
+		OpcodeCost *uint64                      `json:"opcode-cost,omitempty"`
+	}
+	response := struct {
+		LastRound            uint64                          `json:"last-round"`
+		WouldSucceed         bool                            `json:"would-succeed"`
+		FailureMessage       *string                         `json:"failure-message,omitempty"`
+		TxnResults           []simulateTransactionResult     `json:"txn-results"`
+		Accounts             []generated.Account             `json:"accounts"`
+		MinBalanceViolations []generated.MinBalanceViolation `json:"min-balance-violations"`
+	}{
+		LastRound:            uint64(result.Round),
+		WouldSucceed:         result.WouldSucceed(),
+		FailureMessage:       strOrNil(result.FailureMessage),
+		TxnResults:           make([]simulateTransactionResult, len(result.Txns)),
+		Accounts:             make([]generated.Account, 0, result.Delta.Accts.Len()),
+		MinBalanceViolations: make([]generated.MinBalanceViolation, len(result.MinBalanceViolations)),
+	}
+	for i, txn := range result.Txns {
+		response.TxnResults[i].Txn = txn.SignedTxnWithAD
+		if txn.Txn.Type == protocol.ApplicationCallTx {
+			response.TxnResults[i].OpcodeCost = numOrNil(uint64(txn.OpcodeCost))
+		}
+	}
+	for i := 0; i < result.Delta.Accts.Len(); i++ {
+		addr, record := result.Delta.Accts.GetByIdx(i)
+		account, err := AccountDataToAccount(addr.String(), &record, nil, result.Round+1, record.MicroAlgos)
+		if err != nil {
+			return internalError(ctx, err, errInternalFailure, v2.Log)
+		}
+		response.Accounts = append(response.Accounts, account)
+	}
+	for i, violation := range result.MinBalanceViolations {
+		response.MinBalanceViolations[i] = generated.MinBalanceViolation{
+			TxnIndex:   uint64(violation.GroupIndex),
+			Address:    violation.Address.String(),
+			Balance:    violation.Balance.Raw,
+			MinBalance: violation.MinBalance.Raw,
+		}
+	}
+
+	data, err := encode(handle, response)
+	if err != nil {
+		return internalError(ctx, err, errFailedToEncodeResponse, v2.Log)
+	}
+	return ctx.Blob(http.StatusOK, contentType, data)
+}
+
+// SearchForTransactions searches for transactions in the blocks the node has indexed.
+// (GET /v2/transactions)
+func (v2 *Handlers) SearchForTransactions(ctx echo.Context, params generated.SearchForTransactionsParams) error {
+	idx, err := v2.Node.Indexer()
+	if err != nil {
+		return serviceUnavailable(ctx, err, errIndexerNotActive, v2.Log)
+	}
+
+	handle, contentType, err := getCodecHandle(params.Format)
+	if err != nil {
+		return badRequest(ctx, err, errFailedParsingFormatOption, v2.Log)
+	}
+
+	search := indexer.SearchParams{
+		AmountGreaterThan: params.CurrencyGreaterThan,
+		AmountLessThan:    params.CurrencyLessThan,
+	}
+	if params.Address != nil {
+		addr, err := basics.UnmarshalChecksumAddress(*params.Address)
+		if err != nil {
+			return badRequest(ctx, err, errFailedToParseAddress, v2.Log)
+		}
+		search.Address = addr.String()
+	}
+	if params.TxType != nil {
+		search.Type = protocol.TxType(*params.TxType)
+	}
+	if params.AssetId != nil {
+		search.AssetID = basics.AssetIndex(*params.AssetId)
+	}
+	if params.ApplicationId != nil {
+		search.ApplicationID = basics.AppIndex(*params.ApplicationId)
+	}
+	if params.NotePrefix != nil {
+		search.NotePrefix, err = base64.StdEncoding.DecodeString(*params.NotePrefix)
+		if err != nil {
+			return badRequest(ctx, err, errFailedToParseNotePrefix, v2.Log)
+		}
+	}
+	if params.MinRound != nil {
+		search.MinRound = basics.Round(*params.MinRound)
+	}
+	if params.MaxRound != nil {
+		search.MaxRound = basics.Round(*params.MaxRound)
+	}
+	if params.Limit != nil {
+		search.Limit = *params.Limit
+	}
+	if params.Next != nil {
+		search.Next = *params.Next
+	}
+
+	// the indexed round is read first, so that it covers all the results
+	currentRound, err := idx.LastBlock()
 	if err != nil {
+		return internalError(ctx, err, errFailedSearchingTransactions, v2.Log)
+	}
+	txns, nextToken, err := idx.SearchTransactions(search)
+	if errors.Is(err, indexer.ErrInvalidNextToken) {
 		return badRequest(ctx, err, err.Error(), v2.Log)
 	}
+	if err != nil {
+		return internalError(ctx, err, errFailedSearchingTransactions, v2.Log)
+	}
 
-	// For backwards compatibility, return txid of first tx in group
-	txid := txgroup[0].ID()
-	return ctx.JSON(http.StatusOK, generated.PostTransactionsResponse{TxId: txid.String()})
+	// Encoding wasn't working well without embedding "real" objects.
+	type confirmedTransaction struct {
+		ConfirmedRound   uint64                       `json:"confirmed-round"`
+		IntraRoundOffset uint64                       `json:"intra-round-offset"`
+		Txn              transactions.SignedTxnWithAD `json:"txn"`
+	}
+	response := struct {
+		CurrentRound uint64                 `json:"current-round"`
+		NextToken    *string                `json:"next-token,omitempty"`
+		Transactions []confirmedTransaction `json:"transactions"`
+	}{
+		CurrentRound: uint64(currentRound),
+		NextToken:    strOrNil(nextToken),
+		Transactions: make([]confirmedTransaction, 0, len(txns)),
+	}
+
+	ledger := v2.Node.Ledger()
+	var payset []transactions.SignedTxnWithAD
+	var paysetRound basics.Round
+	for _, txn := range txns {
+		rnd := basics.Round(txn.Round)
+		if payset == nil || rnd != paysetRound {
+			block, err := ledger.Block(rnd)
+			if err != nil {
+				return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+			}
+			payset, err = block.DecodePaysetFlat()
+			if err != nil {
+				return internalError(ctx, err, errFailedToParseBlock, v2.Log)
+			}
+			paysetRound = rnd
+		}
+		if int(txn.Intra) >= len(payset) || payset[txn.Intra].ID().String() != txn.TXID {
+			err = fmt.Errorf("transaction %s is not at offset %d of round %d", txn.TXID, txn.Intra, rnd)
+			return internalError(ctx, err, errFailedSearchingTransactions, v2.Log)
+		}
+		response.Transactions = append(response.Transactions, confirmedTransaction{
+			ConfirmedRound:   uint64(rnd),
+			IntraRoundOffset: uint64(txn.Intra),
+			Txn:              payset[txn.Intra],
+		})
+	}
+
+	data, err := encode(handle, response)
+	if err != nil {
+		return internalError(ctx, err, errFailedToEncodeResponse, v2.Log)
+	}
+	return ctx.Blob(http.StatusOK, contentType, data)
 }
 
 // TealDryrun takes transactions and additional simulated ledger state and returns debugging information.
 // (POST /v2/teal/dryrun)
-func (v2 *Handlers) TealDryrun(ctx echo.Context) error {
+func (v2 *Handlers) TealDryrun(ctx echo.Context, params generated.TealDryrunParams) error {
 	if !v2.Node.Config().EnableDeveloperAPI {
 		return ctx.String(http.StatusNotFound, "/teal/dryrun was not enabled in the configuration file by setting the EnableDeveloperAPI to true")
 	}
@@ -439,11 +720,41 @@ func (v2 *Handlers) TealDryrun(ctx echo.Context) error {
 		dr.LatestTimestamp = hdr.TimeStamp
 	}
 
+	dr.jsonTrace = params.JsonTrace != nil && *params.JsonTrace
 	doDryrunRequest(&dr, &response)
 	response.ProtocolVersion = string(protocolVersion)
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// TealDryrunSnapshot builds a dryrun request for a transaction group from the ledger state of a round.
+// (POST /v2/teal/dryrun/snapshot)
+func (v2 *Handlers) TealDryrunSnapshot(ctx echo.Context, params generated.TealDryrunSnapshotParams) error {
+	if !v2.Node.Config().EnableDeveloperAPI {
+		return ctx.String(http.StatusNotFound, "/teal/dryrun/snapshot was not enabled in the configuration file by setting the EnableDeveloperAPI to true")
+	}
+	myLedger := v2.Node.Ledger()
+	rnd, err := queryRound(myLedger, params.Round)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+	hdr, err := myLedger.BlockHdr(rnd)
+	if err != nil {
+		return v2.lookupError(ctx, err)
+	}
+	proto := config.Consensus[hdr.CurrentProtocol]
+
+	txgroup, err := decodeTxGroup(ctx.Request().Body, proto.MaxTxGroupSize)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	response, err := makeDryrunSnapshot(myLedger, txgroup, rnd)
+	if err != nil {
+		return v2.lookupError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // TransactionParams returns the suggested parameters for constructing a new transaction.
 // (GET /v2/transactions/params)
 func (v2 *Handlers) TransactionParams(ctx echo.Context) error {
@@ -461,6 +772,7 @@ func (v2 *Handlers) TransactionParams(ctx echo.Context) error {
 
 	response := generated.TransactionParametersResponse{
 		ConsensusVersion: string(stat.LastVersion),
+		CongestionFee:    v2.Node.CongestionFee().Raw,
 		Fee:              v2.Node.SuggestedFee().Raw,
 		GenesisHash:      gh[:],
 		GenesisId:        v2.Node.GenesisID(),
@@ -508,7 +820,9 @@ func (v2 *Handlers) PendingTransactionInformation(ctx echo.Context, txid string,
 		ClosingAmount      *uint64                        `codec:"closing-amount,omitempty"`
 		ConfirmedRound     *uint64                        `codec:"confirmed-round,omitempty"`
 		GlobalStateDelta   *generated.StateDelta          `codec:"global-state-delta,omitempty"`
+		InnerTxns          []transactions.SignedTxnWithAD `codec:"inner-txns,omitempty"`
 		LocalStateDelta    *[]generated.AccountStateDelta `codec:"local-state-delta,omitempty"`
+		Logs               *[][]byte                      `codec:"logs,omitempty"`
 		PoolError          string                         `codec:"pool-error"`
 		ReceiverRewards    *uint64                        `codec:"receiver-rewards,omitempty"`
 		SenderRewards      *uint64                        `codec:"sender-rewards,omitempty"`
@@ -536,6 +850,8 @@ func (v2 *Handlers) PendingTransactionInformation(ctx echo.Context, txid string,
 		response.ApplicationIndex = computeAppIndexFromTxn(txn, v2.Node.Ledger())
 
 		response.LocalStateDelta, response.GlobalStateDelta = convertToDeltas(txn)
+		response.InnerTxns = txn.ApplyData.EvalDelta.InnerTxns
+		response.Logs = convertToLogs(txn.ApplyData.EvalDelta.Logs)
 	}
 
 	data, err := encode(handle, response)
@@ -673,21 +989,24 @@ func (v2 *Handlers) GetPendingTransactions(ctx echo.Context, params generated.Ge
 
 // GetApplicationByID returns application information by app idx.
 // (GET /v2/applications/{application-id})
-func (v2 *Handlers) GetApplicationByID(ctx echo.Context, applicationID uint64) error {
+func (v2 *Handlers) GetApplicationByID(ctx echo.Context, applicationID uint64, params generated.GetApplicationByIDParams) error {
 	appIdx := basics.AppIndex(applicationID)
 	ledger := v2.Node.Ledger()
-	creator, ok, err := ledger.GetCreator(basics.CreatableIndex(appIdx), basics.AppCreatable)
+	lastRound, err := queryRound(ledger, params.Round)
 	if err != nil {
-		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+	creator, ok, err := ledger.GetCreatorForRound(lastRound, basics.CreatableIndex(appIdx), basics.AppCreatable)
+	if err != nil {
+		return v2.lookupError(ctx, err)
 	}
 	if !ok {
 		return notFound(ctx, errors.New(errAppDoesNotExist), errAppDoesNotExist, v2.Log)
 	}
 
-	lastRound := ledger.Latest()
 	record, _, err := ledger.LookupWithoutRewards(lastRound, creator)
 	if err != nil {
-		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+		return v2.lookupError(ctx, err)
 	}
 
 	appParams, ok := record.AppParams[appIdx]
@@ -699,23 +1018,82 @@ func (v2 *Handlers) GetApplicationByID(ctx echo.Context, applicationID uint64) e
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// GetApplicationBoxes returns the box names of an application
+// (GET /v2/applications/{application-id}/boxes)
+func (v2 *Handlers) GetApplicationBoxes(ctx echo.Context, applicationID uint64, params generated.GetApplicationBoxesParams) error {
+	appIdx := basics.AppIndex(applicationID)
+	ledger := v2.Node.Ledger()
+	_, ok, err := ledger.GetCreator(basics.CreatableIndex(appIdx), basics.AppCreatable)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+	if !ok {
+		return notFound(ctx, errors.New(errAppDoesNotExist), errAppDoesNotExist, v2.Log)
+	}
+
+	var max uint64
+	if params.Max != nil {
+		max = *params.Max
+	}
+	names, err := ledger.ListBoxes(appIdx, max)
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+
+	response := generated.BoxesResponse{
+		Boxes: make([]generated.BoxDescriptor, len(names)),
+	}
+	for i, name := range names {
+		response.Boxes[i].Name = []byte(name)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetApplicationBoxByName returns the value of an application's box
+// (GET /v2/applications/{application-id}/box)
+func (v2 *Handlers) GetApplicationBoxByName(ctx echo.Context, applicationID uint64, params generated.GetApplicationBoxByNameParams) error {
+	appIdx := basics.AppIndex(applicationID)
+	name, err := base64.StdEncoding.DecodeString(params.Name)
+	if err != nil {
+		return badRequest(ctx, err, errFailedToParseBoxName, v2.Log)
+	}
+
+	ledger := v2.Node.Ledger()
+	value, ok, err := ledger.LookupBox(ledger.Latest(), ledgercore.MakeBoxKey(appIdx, string(name)))
+	if err != nil {
+		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+	}
+	if !ok {
+		return notFound(ctx, errors.New(errBoxDoesNotExist), errBoxDoesNotExist, v2.Log)
+	}
+
+	response := generated.BoxResponse{
+		Name:  name,
+		Value: []byte(value),
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
 // GetAssetByID returns application information by app idx.
 // (GET /v2/assets/{asset-id})
-func (v2 *Handlers) GetAssetByID(ctx echo.Context, assetID uint64) error {
+func (v2 *Handlers) GetAssetByID(ctx echo.Context, assetID uint64, params generated.GetAssetByIDParams) error {
 	assetIdx := basics.AssetIndex(assetID)
 	ledger := v2.Node.Ledger()
-	creator, ok, err := ledger.GetCreator(basics.CreatableIndex(assetIdx), basics.AssetCreatable)
+	lastRound, err := queryRound(ledger, params.Round)
 	if err != nil {
-		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+	creator, ok, err := ledger.GetCreatorForRound(lastRound, basics.CreatableIndex(assetIdx), basics.AssetCreatable)
+	if err != nil {
+		return v2.lookupError(ctx, err)
 	}
 	if !ok {
 		return notFound(ctx, errors.New(errAssetDoesNotExist), errAssetDoesNotExist, v2.Log)
 	}
 
-	lastRound := ledger.Latest()
 	record, err := ledger.Lookup(lastRound, creator)
 	if err != nil {
-		return internalError(ctx, err, errFailedLookingUpLedger, v2.Log)
+		return v2.lookupError(ctx, err)
 	}
 
 	assetParams, ok := record.AssetParams[assetIdx]
@@ -748,7 +1126,7 @@ func (v2 *Handlers) AbortCatchup(ctx echo.Context, catchpoint string) error {
 
 // TealCompile compiles TEAL code to binary, return both binary and hash
 // (POST /v2/teal/compile)
-func (v2 *Handlers) TealCompile(ctx echo.Context) error {
+func (v2 *Handlers) TealCompile(ctx echo.Context, params generated.TealCompileParams) error {
 	// return early if teal compile is not allowed in node config
 	if !v2.Node.Config().EnableDeveloperAPI {
 		return ctx.String(http.StatusNotFound, "/teal/compile was not enabled in the configuration file by setting the EnableDeveloperAPI to true")
@@ -767,5 +1145,61 @@ func (v2 *Handlers) TealCompile(ctx echo.Context) error {
 		Hash:   addr.String(),
 		Result: base64.StdEncoding.EncodeToString(ops.Program),
 	}
+	if params.Sourcemap != nil && *params.Sourcemap {
+		// round trip through json so the map is returned as a plain object
+		var sourcemap map[string]interface{}
+		encoded, err := json.Marshal(ops.SourceMap("", source))
+		if err == nil {
+			err = json.Unmarshal(encoded, &sourcemap)
+		}
+		if err != nil {
+			return internalError(ctx, err, "failed to encode source map", v2.Log)
+		}
+		response.Sourcemap = &sourcemap
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// TealLint compiles TEAL code and checks it for common security mistakes
+// (POST /v2/teal/lint)
+func (v2 *Handlers) TealLint(ctx echo.Context, params generated.TealLintParams) error {
+	// return early if teal lint is not allowed in node config
+	if !v2.Node.Config().EnableDeveloperAPI {
+		return ctx.String(http.StatusNotFound, "/teal/lint was not enabled in the configuration file by setting the EnableDeveloperAPI to true")
+	}
+	buf := new(bytes.Buffer)
+	ctx.Request().Body = http.MaxBytesReader(nil, ctx.Request().Body, maxTealSourceBytes)
+	buf.ReadFrom(ctx.Request().Body)
+	ops, err := logic.AssembleString(buf.String())
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	var config logic.LintConfig
+	if params.Mode != nil {
+		config.Mode = *params.Mode
+	}
+	if params.Disable != nil {
+		for _, rule := range *params.Disable {
+			config.Disable = append(config.Disable, logic.LintRule(rule))
+		}
+	}
+	if params.MaxFee != nil {
+		config.MaxFee = *params.MaxFee
+	}
+	warnings, err := logic.Lint(ops.Program, config)
+	if err != nil {
+		return badRequest(ctx, err, err.Error(), v2.Log)
+	}
+
+	response := generated.LintResponse{Warnings: make([]generated.LintWarning, len(warnings))}
+	for i, w := range warnings {
+		response.Warnings[i] = generated.LintWarning{
+			Rule:    string(w.Rule),
+			Pc:      uint64(w.PC),
+			Line:    uint64(ops.OffsetToLine[w.PC] + 1),
+			Message: w.Message,
+		}
+	}
 	return ctx.JSON(http.StatusOK, response)
 }
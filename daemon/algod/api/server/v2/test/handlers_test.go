@@ -18,6 +18,7 @@ package test
 
 import (
 	"bytes"
+	"encoding/base64"
 	"errors"
 	"io"
 	"net/http"
@@ -27,15 +28,19 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/crypto"
 	v2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	generatedV2 "github.com/algorand/go-algorand/daemon/algod/api/server/v2/generated"
 	"github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/node"
+	"github.com/algorand/go-algorand/node/indexer"
 	"github.com/algorand/go-algorand/protocol"
 )
 
@@ -66,13 +71,13 @@ func TestSimpleMockBuilding(t *testing.T) {
 	require.Equal(t, t.Name(), handler.Node.GenesisID())
 }
 
-func accountInformationTest(t *testing.T, address string, expectedCode int) {
+func accountInformationTest(t *testing.T, address string, round *uint64, expectedCode int) {
 	handler, c, rec, _, _, releasefunc := setupTestForMethodGet(t)
 	defer releasefunc()
-	err := handler.AccountInformation(c, address, generatedV2.AccountInformationParams{})
+	err := handler.AccountInformation(c, address, generatedV2.AccountInformationParams{Round: round})
 	require.NoError(t, err)
 	require.Equal(t, expectedCode, rec.Code)
-	if address == poolAddr.String() {
+	if address == poolAddr.String() && expectedCode == 200 {
 		expectedResponse := poolAddrResponseGolden
 		actualResponse := generatedV2.AccountResponse{}
 		err = protocol.DecodeJSON(rec.Body.Bytes(), &actualResponse)
@@ -84,8 +89,12 @@ func accountInformationTest(t *testing.T, address string, expectedCode int) {
 func TestAccountInformation(t *testing.T) {
 	t.Parallel()
 
-	accountInformationTest(t, poolAddr.String(), 200)
-	accountInformationTest(t, "bad account", 400)
+	latest := uint64(0)
+	future := uint64(100)
+	accountInformationTest(t, poolAddr.String(), nil, 200)
+	accountInformationTest(t, poolAddr.String(), &latest, 200)
+	accountInformationTest(t, poolAddr.String(), &future, 400)
+	accountInformationTest(t, "bad account", nil, 400)
 }
 
 func getBlockTest(t *testing.T, blockNum uint64, format string, expectedCode int) {
@@ -138,6 +147,8 @@ func TestGetStatus(t *testing.T) {
 		CatchpointVerifiedAccounts:  &stat.CatchpointCatchupVerifiedAccounts,
 		CatchpointTotalBlocks:       &stat.CatchpointCatchupTotalBlocks,
 		CatchpointAcquiredBlocks:    &stat.CatchpointCatchupAcquiredBlocks,
+		CatchpointTotalChunks:       &stat.CatchpointCatchupTotalChunks,
+		CatchpointAcquiredChunks:    &stat.CatchpointCatchupAcquiredChunks,
 	}
 	actualResult := generatedV2.NodeStatusResponse{}
 	err = protocol.DecodeJSON(rec.Body.Bytes(), &actualResult)
@@ -358,7 +369,7 @@ func TestAbortCatchup(t *testing.T) {
 	abortCatchupTest(t, badCatchPoint, 400)
 }
 
-func tealCompileTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDeveloperAPI bool) {
+func tealCompileTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDeveloperAPI bool, params generatedV2.TealCompileParams) (response generatedV2.CompileResponse) {
 	numAccounts := 1
 	numTransactions := 1
 	offlineAccounts := true
@@ -376,22 +387,99 @@ func tealCompileTest(t *testing.T, bytesToUse []byte, expectedCode int, enableDe
 	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bytesToUse))
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	err := handler.TealCompile(c)
+	err := handler.TealCompile(c, params)
 	require.NoError(t, err)
 	require.Equal(t, expectedCode, rec.Code)
+	if rec.Code == 200 {
+		data := rec.Body.Bytes()
+		err = protocol.DecodeJSON(data, &response)
+		require.NoError(t, err, string(data))
+	}
+	return
 }
 
 func TestTealCompile(t *testing.T) {
 	t.Parallel()
 
-	tealCompileTest(t, nil, 200, true) // nil program should work
+	params := generatedV2.TealCompileParams{}
+	tealCompileTest(t, nil, 200, true, params) // nil program should work
 	goodProgram := `int 1`
 	goodProgramBytes := []byte(goodProgram)
-	tealCompileTest(t, goodProgramBytes, 200, true)
-	tealCompileTest(t, goodProgramBytes, 404, false)
+	response := tealCompileTest(t, goodProgramBytes, 200, true, params)
+	require.Nil(t, response.Sourcemap)
+	tealCompileTest(t, goodProgramBytes, 404, false, params)
 	badProgram := "bad program"
 	badProgramBytes := []byte(badProgram)
-	tealCompileTest(t, badProgramBytes, 400, true)
+	tealCompileTest(t, badProgramBytes, 400, true, params)
+
+	sourcemap := true
+	params.Sourcemap = &sourcemap
+	response = tealCompileTest(t, goodProgramBytes, 200, true, params)
+	require.NotNil(t, response.Sourcemap)
+
+	var sm logic.SourceMap
+	data := protocol.EncodeJSON(*response.Sourcemap)
+	require.NoError(t, protocol.DecodeJSON(data, &sm))
+	require.Equal(t, []string{goodProgram}, sm.SourcesContent)
+	locations, err := sm.Locations()
+	require.NoError(t, err)
+	// version and intcblock, then int 1 as the last op
+	program, err := base64.StdEncoding.DecodeString(response.Result)
+	require.NoError(t, err)
+	require.Equal(t, map[int]logic.SourceLocation{len(program) - 1: {}}, locations)
+}
+
+func tealLintTest(t *testing.T, source string, expectedCode int, enableDeveloperAPI bool, params generatedV2.TealLintParams) (response generatedV2.LintResponse) {
+	numAccounts := 1
+	numTransactions := 1
+	offlineAccounts := true
+	mockLedger, _, _, _, releasefunc := testingenv(t, numAccounts, numTransactions, offlineAccounts)
+	defer releasefunc()
+	dummyShutdownChan := make(chan struct{})
+	mockNode := makeMockNode(mockLedger, t.Name(), nil)
+	mockNode.config.EnableDeveloperAPI = enableDeveloperAPI
+	handler := v2.Handlers{
+		Node:     &mockNode,
+		Log:      logging.Base(),
+		Shutdown: dummyShutdownChan,
+	}
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(source)))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	err := handler.TealLint(c, params)
+	require.NoError(t, err)
+	require.Equal(t, expectedCode, rec.Code)
+	if rec.Code == 200 {
+		data := rec.Body.Bytes()
+		err = protocol.DecodeJSON(data, &response)
+		require.NoError(t, err, string(data))
+	}
+	return
+}
+
+func TestTealLint(t *testing.T) {
+	t.Parallel()
+
+	params := generatedV2.TealLintParams{}
+	source := "#pragma version 4\nint 1\nreturn\nint 0"
+	tealLintTest(t, source, 404, false, params)
+	tealLintTest(t, "bad program", 400, true, params)
+
+	response := tealLintTest(t, source, 200, true, params)
+	require.Len(t, response.Warnings, 6)
+	require.Equal(t, generatedV2.LintWarning{Rule: "rekey-to", Pc: 3, Line: 3, Message: "approves without checking RekeyTo"}, response.Warnings[0])
+	require.Equal(t, generatedV2.LintWarning{Rule: "unreachable", Pc: 4, Line: 4, Message: "unreachable code"}, response.Warnings[5])
+
+	disable := []string{"rekey-to", "close-remainder-to", "asset-close-to", "fee", "group-size"}
+	params.Disable = &disable
+	response = tealLintTest(t, source, 200, true, params)
+	require.Len(t, response.Warnings, 1)
+	require.Equal(t, "unreachable", response.Warnings[0].Rule)
+
+	mode := "stateless"
+	params.Mode = &mode
+	tealLintTest(t, source, 400, true, params)
 }
 
 func tealDryrunTest(
@@ -425,7 +513,7 @@ func tealDryrunTest(
 	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(data))
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
-	err := handler.TealDryrun(c)
+	err := handler.TealDryrun(c, generatedV2.TealDryrunParams{})
 	require.NoError(t, err)
 	require.Equal(t, expCode, rec.Code)
 	if rec.Code == 200 {
@@ -516,3 +604,204 @@ func TestTealDryrun(t *testing.T) {
 	tealDryrunTest(t, &gdr, "msgp", 200, "REJECT", true)
 	tealDryrunTest(t, &gdr, "json", 404, "", false)
 }
+
+func TestTealDryrunSnapshot(t *testing.T) {
+	t.Parallel()
+
+	mockLedger, _, _, stxns, releasefunc := testingenv(t, 5, 5, false)
+	defer releasefunc()
+	mockNode := makeMockNode(mockLedger, t.Name(), nil)
+	handler := v2.Handlers{Node: &mockNode, Log: logging.Base(), Shutdown: make(chan struct{})}
+	snapshot := func(body []byte, params generatedV2.TealDryrunSnapshotParams) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)), rec)
+		require.NoError(t, handler.TealDryrunSnapshot(c, params))
+		return rec
+	}
+
+	var stxn transactions.SignedTxn
+	for _, stxn = range stxns {
+		if !stxn.Txn.Sender.IsZero() {
+			break
+		}
+	}
+	body := protocol.Encode(&stxn)
+	require.Equal(t, http.StatusNotFound, snapshot(body, generatedV2.TealDryrunSnapshotParams{}).Code)
+
+	mockNode.config.EnableDeveloperAPI = true
+	latest := mockLedger.Latest()
+	hdr, err := mockLedger.BlockHdr(latest)
+	require.NoError(t, err)
+
+	rec := snapshot(body, generatedV2.TealDryrunSnapshotParams{})
+	require.Equal(t, http.StatusOK, rec.Code)
+	var gdr generatedV2.DryrunRequest
+	require.NoError(t, protocol.DecodeJSON(rec.Body.Bytes(), &gdr))
+	require.Equal(t, uint64(latest+1), gdr.Round)
+	require.Equal(t, uint64(hdr.TimeStamp), gdr.LatestTimestamp)
+	require.Equal(t, string(hdr.CurrentProtocol), gdr.ProtocolVersion)
+	require.Len(t, gdr.Txns, 1)
+	addrs := []string{stxn.Txn.Sender.String()}
+	if stxn.Txn.Receiver != stxn.Txn.Sender {
+		addrs = append(addrs, stxn.Txn.Receiver.String())
+	}
+	require.Len(t, gdr.Accounts, len(addrs))
+	for i, addr := range addrs {
+		require.Equal(t, addr, gdr.Accounts[i].Address)
+	}
+
+	record, err := mockLedger.Lookup(latest, stxn.Txn.Sender)
+	require.NoError(t, err)
+	require.Equal(t, record.MicroAlgos.Raw, gdr.Accounts[0].Amount)
+
+	// the snapshot can be sent to the dryrun endpoint as is
+	dr, err := v2.DryrunRequestFromGenerated(&gdr)
+	require.NoError(t, err)
+	require.Equal(t, []transactions.SignedTxn{stxn}, dr.Txns)
+
+	future := uint64(latest + 1)
+	require.Equal(t, http.StatusBadRequest, snapshot(body, generatedV2.TealDryrunSnapshotParams{Round: &future}).Code)
+	require.Equal(t, http.StatusBadRequest, snapshot(nil, generatedV2.TealDryrunSnapshotParams{}).Code)
+}
+
+func TestSearchForTransactions(t *testing.T) {
+	t.Parallel()
+
+	mockLedger, _, _, stxns, releasefunc := testingenv(t, 5, 10, false)
+	defer releasefunc()
+	prev, err := mockLedger.BlockHdr(mockLedger.Latest())
+	require.NoError(t, err)
+	blk := bookkeeping.MakeBlock(prev)
+	eval, err := mockLedger.StartEvaluator(blk.BlockHeader, 0)
+	require.NoError(t, err)
+	var txids []string
+	for _, stxn := range stxns {
+		if stxn.Txn.Sender.IsZero() {
+			continue
+		}
+		require.NoError(t, eval.Transaction(stxn, transactions.ApplyData{}))
+		txids = append(txids, stxn.ID().String())
+	}
+	require.NotEmpty(t, txids)
+	vb, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	require.NoError(t, mockLedger.AddValidatedBlock(*vb, agreement.Certificate{}))
+
+	idx, err := indexer.MakeIndexer(t.TempDir(), mockLedger, false)
+	require.NoError(t, err)
+	defer idx.Shutdown()
+	require.NoError(t, idx.NewBlock(vb.Block()))
+
+	mockNode := makeMockNode(mockLedger, t.Name(), nil)
+	handler := v2.Handlers{Node: mockNode, Log: logging.Base(), Shutdown: make(chan struct{})}
+	search := func(params generatedV2.SearchForTransactionsParams) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		require.NoError(t, handler.SearchForTransactions(c, params))
+		return rec
+	}
+
+	// the indexer is not active
+	require.Equal(t, http.StatusServiceUnavailable, search(generatedV2.SearchForTransactionsParams{}).Code)
+
+	mockNode.indexer = idx
+	handler.Node = mockNode
+
+	// page through all the transactions
+	var found []string
+	limit := uint64(3)
+	params := generatedV2.SearchForTransactionsParams{Limit: &limit}
+	for {
+		rec := search(params)
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response struct {
+			CurrentRound uint64  `json:"current-round"`
+			NextToken    *string `json:"next-token"`
+			Transactions []struct {
+				ConfirmedRound   uint64                       `json:"confirmed-round"`
+				IntraRoundOffset uint64                       `json:"intra-round-offset"`
+				Txn              transactions.SignedTxnWithAD `json:"txn"`
+			} `json:"transactions"`
+		}
+		require.NoError(t, protocol.DecodeJSON(rec.Body.Bytes(), &response))
+		require.Equal(t, uint64(1), response.CurrentRound)
+		for _, txn := range response.Transactions {
+			require.Equal(t, uint64(1), txn.ConfirmedRound)
+			require.Equal(t, uint64(len(found)), txn.IntraRoundOffset)
+			found = append(found, txn.Txn.ID().String())
+		}
+		if response.NextToken == nil {
+			break
+		}
+		params.Next = response.NextToken
+	}
+	require.Equal(t, txids, found)
+
+	badToken := "token"
+	require.Equal(t, http.StatusBadRequest, search(generatedV2.SearchForTransactionsParams{Next: &badToken}).Code)
+	badNote := "!"
+	require.Equal(t, http.StatusBadRequest, search(generatedV2.SearchForTransactionsParams{NotePrefix: &badNote}).Code)
+	badAddress := "address"
+	require.Equal(t, http.StatusBadRequest, search(generatedV2.SearchForTransactionsParams{Address: &badAddress}).Code)
+}
+
+func TestSimulateTransaction(t *testing.T) {
+	t.Parallel()
+
+	mockLedger, _, _, stxns, releasefunc := testingenv(t, 5, 5, false)
+	defer releasefunc()
+	mockNode := makeMockNode(mockLedger, t.Name(), nil)
+	handler := v2.Handlers{Node: mockNode, Log: logging.Base(), Shutdown: make(chan struct{})}
+	simulate := func(body []byte) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)), rec)
+		require.NoError(t, handler.SimulateTransaction(c, generatedV2.SimulateTransactionParams{}))
+		return rec
+	}
+
+	var stxn transactions.SignedTxn
+	for _, stxn = range stxns {
+		if !stxn.Txn.Sender.IsZero() {
+			break
+		}
+	}
+	// signatures are not verified
+	stxn.Sig = crypto.Signature{}
+	latest := mockLedger.Latest()
+
+	rec := simulate(protocol.Encode(&stxn))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		LastRound      uint64  `json:"last-round"`
+		WouldSucceed   bool    `json:"would-succeed"`
+		FailureMessage *string `json:"failure-message"`
+		TxnResults     []struct {
+			Txn        transactions.SignedTxnWithAD `json:"txn"`
+			OpcodeCost *uint64                      `json:"opcode-cost"`
+		} `json:"txn-results"`
+		Accounts             []generatedV2.Account             `json:"accounts"`
+		MinBalanceViolations []generatedV2.MinBalanceViolation `json:"min-balance-violations"`
+	}
+	require.NoError(t, protocol.DecodeJSON(rec.Body.Bytes(), &response))
+	require.True(t, response.WouldSucceed, "%v", response.FailureMessage)
+	require.Equal(t, uint64(latest), response.LastRound)
+	require.Len(t, response.TxnResults, 1)
+	require.Equal(t, stxn.ID(), response.TxnResults[0].Txn.ID())
+	require.Nil(t, response.TxnResults[0].OpcodeCost)
+	require.NotEmpty(t, response.Accounts)
+	require.Empty(t, response.MinBalanceViolations)
+
+	// the simulation does not change the ledger
+	require.Equal(t, latest, mockLedger.Latest())
+
+	// transactions that can't be evaluated report why
+	stxn.Txn.FirstValid = latest + 1000
+	stxn.Txn.LastValid = latest + 1001
+	rec = simulate(protocol.Encode(&stxn))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, protocol.DecodeJSON(rec.Body.Bytes(), &response))
+	require.False(t, response.WouldSucceed)
+	require.NotNil(t, response.FailureMessage)
+
+	require.Equal(t, http.StatusBadRequest, simulate(nil).Code)
+}
@@ -53,6 +53,8 @@ var cannedStatusReportGolden = node.StatusReport{
 	CatchpointCatchupVerifiedAccounts:  0,
 	CatchpointCatchupTotalAccounts:     0,
 	CatchpointCatchupTotalBlocks:       0,
+	CatchpointCatchupAcquiredChunks:    0,
+	CatchpointCatchupTotalChunks:       0,
 	LastCatchpoint:                     "",
 }
 
@@ -84,6 +86,7 @@ type mockNode struct {
 	genesisID string
 	config    config.Local
 	err       error
+	indexer   *indexer.Indexer
 }
 
 func makeMockNode(ledger *data.Ledger, genesisID string, nodeError error) mockNode {
@@ -128,6 +131,10 @@ func (m mockNode) SuggestedFee() basics.MicroAlgos {
 	return basics.MicroAlgos{Raw: 1}
 }
 
+func (m mockNode) CongestionFee() basics.MicroAlgos {
+	return basics.MicroAlgos{Raw: 1}
+}
+
 // unused by handlers:
 func (m mockNode) Config() config.Local {
 	return m.config
@@ -163,7 +170,10 @@ func (m mockNode) Uint64() uint64 {
 }
 
 func (m mockNode) Indexer() (*indexer.Indexer, error) {
-	return nil, fmt.Errorf("indexer not implemented")
+	if m.indexer == nil {
+		return nil, fmt.Errorf("indexer not implemented")
+	}
+	return m.indexer, nil
 }
 
 func (m mockNode) GetTransactionByID(txid transactions.Txid, rnd basics.Round) (node.TxnWithStatus, error) {
@@ -255,6 +265,8 @@ func testingenv(t testing.TB, numAccounts, numTxs int, offlineAccounts bool) (*d
 	}
 
 	genesis[poolAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: 100000 * uint64(proto.RewardsRateRefreshInterval)})
+	// the rewards pool needs its minimum balance for blocks to be evaluated
+	genesis[sinkAddr] = basics.MakeAccountData(basics.NotParticipating, basics.MicroAlgos{Raw: proto.MinBalance})
 
 	bootstrap := data.MakeGenesisBalances(genesis, poolAddr, sinkAddr)
 
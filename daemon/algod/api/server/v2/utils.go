@@ -29,6 +29,7 @@ import (
 	"github.com/algorand/go-algorand/data"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/node"
 	"github.com/algorand/go-algorand/protocol"
@@ -263,3 +264,44 @@ func convertToDeltas(txn node.TxnWithStatus) (*[]generated.AccountStateDelta, *g
 
 	return localStateDelta, stateDeltaToStateDelta(txn.ApplyData.EvalDelta.GlobalDelta)
 }
+
+// convertToLogs converts the values logged by an application to their API
+// representation, returning nil if nothing was logged
+func convertToLogs(logs []string) *[][]byte {
+	if len(logs) == 0 {
+		return nil
+	}
+	res := make([][]byte, len(logs))
+	for i, l := range logs {
+		res[i] = []byte(l)
+	}
+	return &res
+}
+
+// convertToCostAnalysis reports the static cost analysis of a program,
+// returning nil if the program can not be analyzed
+func convertToCostAnalysis(program []byte) *[]generated.EntryPointCost {
+	costs, err := logic.AnalyzeCost(program)
+	if err != nil {
+		return nil
+	}
+	res := make([]generated.EntryPointCost, len(costs))
+	for i, entry := range costs {
+		res[i] = generated.EntryPointCost{
+			Pc:         uint64(entry.PC),
+			Subroutine: entry.Subroutine,
+		}
+		if entry.CostUnbounded {
+			loop := uint64(entry.LoopPC)
+			res[i].LoopPc = &loop
+		} else {
+			cost := uint64(entry.Cost)
+			res[i].Cost = &cost
+		}
+		if !entry.StackUnbounded {
+			depth := uint64(entry.MaxStack)
+			res[i].MaxStackDepth = &depth
+		}
+	}
+	return &res
+}
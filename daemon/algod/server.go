@@ -35,7 +35,9 @@ import (
 	"github.com/algorand/go-algorand/config"
 	apiServer "github.com/algorand/go-algorand/daemon/algod/api/server"
 	"github.com/algorand/go-algorand/daemon/algod/api/server/lib"
+	"github.com/algorand/go-algorand/data"
 	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/logging/telemetryspec"
 	"github.com/algorand/go-algorand/node"
@@ -135,6 +137,38 @@ func (s *Server) Initialize(cfg config.Local, phonebookAddresses []string, genes
 	return nil
 }
 
+// ImportBlockArchives adds the blocks of the block archive files of the given directory, as exported by the
+// non-archival nodes pruning their blocks, to the ledger of the node. It's meant to be called before the node
+// is started, and returns once the imported blocks were written.
+func (s *Server) ImportBlockArchives(dir string) error {
+	files, err := ledger.BlockArchiveFiles(dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no block archive files found in %s", dir)
+	}
+	l := s.node.Ledger()
+	for _, fileName := range files {
+		imported, err := importBlockArchive(l, fileName)
+		if err != nil {
+			return fmt.Errorf("unable to import the block archive %s: %v", fileName, err)
+		}
+		s.log.Infof("Imported %d blocks from the block archive %s", imported, fileName)
+	}
+	l.WaitForCommit(l.Latest())
+	return nil
+}
+
+func importBlockArchive(l *data.Ledger, fileName string) (int, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return l.ImportBlockArchive(file)
+}
+
 // helper handles startup of tcp listener
 func makeListener(addr string) (net.Listener, error) {
 	var listener net.Listener
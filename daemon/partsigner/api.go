@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package partsigner implements a participation signer daemon, which holds
+// participation keys away from algod and produces the VRF proofs and vote
+// signatures algod needs to take part in agreement, along with the client
+// algod uses to reach it.
+package partsigner
+
+import (
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+const (
+	// TokenHeader is the HTTP header used for the pre-shared auth token
+	TokenHeader = "X-Algo-Partsigner-Token"
+
+	keysPath          = "/v1/keys"
+	proveVRFPath      = "/v1/vrf"
+	signVotePath      = "/v1/vote"
+	deleteOldKeysPath = "/v1/keys/delete-old"
+)
+
+// keyInfo describes a participation key held by the signer.
+type keyInfo struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Parent      basics.Address                  `codec:"parent"`
+	SelectionPK crypto.VrfPubkey                `codec:"selkey"`
+	VotePK      crypto.OneTimeSignatureVerifier `codec:"votekey"`
+	FirstValid  basics.Round                    `codec:"first"`
+	LastValid   basics.Round                    `codec:"last"`
+	KeyDilution uint64                          `codec:"kd"`
+}
+
+type keysResponse struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Keys []keyInfo `codec:"keys"`
+}
+
+type proveVRFRequest struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	VotePK  crypto.OneTimeSignatureVerifier `codec:"votekey"`
+	HashID  protocol.HashID                 `codec:"hid"`
+	Message []byte                          `codec:"msg"`
+}
+
+type proveVRFResponse struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Proof crypto.VrfProof `codec:"proof"`
+}
+
+// signVoteRequest carries the encoding of the vote to sign, whose round,
+// period and step the signer reads to protect against double-signing. The
+// signer signs with the ephemeral key of the round it read.
+type signVoteRequest struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	VotePK crypto.OneTimeSignatureVerifier `codec:"votekey"`
+	Vote   []byte                          `codec:"vote"`
+}
+
+type signVoteResponse struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Sig crypto.OneTimeSignature `codec:"sig"`
+}
+
+type deleteOldKeysRequest struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	VotePK      crypto.OneTimeSignatureVerifier `codec:"votekey"`
+	Round       basics.Round                    `codec:"rnd"`
+	KeyDilution uint64                          `codec:"kd"`
+}
+
+type errorResponse struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Error string `codec:"error"`
+}
+
+// hashedMessage is a message sent to the signer, along with the domain
+// separation prefix it is hashed with.
+type hashedMessage struct {
+	hashID protocol.HashID
+	data   []byte
+}
+
+// ToBeHashed implements the crypto.Hashable interface.
+func (m hashedMessage) ToBeHashed() (protocol.HashID, []byte) {
+	return m.hashID, m.data
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package partsigner
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// requestTimeout bounds the time a request to the signer may take. Votes
+// are only useful for a few seconds, so there is no point in waiting longer.
+const requestTimeout = 5 * time.Second
+
+// Client is the client algod uses to reach a participation signer daemon.
+type Client struct {
+	httpClient http.Client
+	apiToken   string
+	address    string
+}
+
+// MakeClient instantiates a Client for the signer listening on address.
+func MakeClient(address string, apiToken string) *Client {
+	return &Client{
+		httpClient: http.Client{Timeout: requestTimeout},
+		apiToken:   apiToken,
+		address:    address,
+	}
+}
+
+// Participations returns the participation keys held by the signer. Their VRF
+// proofs and vote signatures are produced by the signer.
+func (c *Client) Participations() ([]account.Participation, error) {
+	var resp keysResponse
+	err := c.do(http.MethodGet, keysPath, nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]account.Participation, 0, len(resp.Keys))
+	for _, key := range resp.Keys {
+		signer := remoteKey{client: c, votePK: key.VotePK}
+		parts = append(parts, account.MakeRemoteParticipation(key.Parent, key.SelectionPK, key.VotePK, key.FirstValid, key.LastValid, key.KeyDilution, signer))
+	}
+	return parts, nil
+}
+
+func (c *Client) do(method string, path string, req interface{}, resp interface{}) error {
+	var body []byte
+	if req != nil {
+		body = protocol.EncodeReflect(req)
+	}
+
+	hreq, err := http.NewRequest(method, fmt.Sprintf("http://%s%s", c.address, path), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set(TokenHeader, c.apiToken)
+	hreq.Header.Set("Content-Type", "application/msgpack")
+
+	hresp, err := c.httpClient.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer hresp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(hresp.Body)
+	if err != nil {
+		return err
+	}
+
+	if hresp.StatusCode != http.StatusOK {
+		var errResp errorResponse
+		if protocol.DecodeReflect(respBody, &errResp) != nil || errResp.Error == "" {
+			return fmt.Errorf("participation signer returned %s", hresp.Status)
+		}
+		return fmt.Errorf("participation signer returned %s: %s", hresp.Status, errResp.Error)
+	}
+	return protocol.DecodeReflect(respBody, resp)
+}
+
+// remoteKey is a participation key held by a signer.
+type remoteKey struct {
+	client *Client
+	votePK crypto.OneTimeSignatureVerifier
+}
+
+// ProveVRF implements account.ParticipationSigner.
+func (k remoteKey) ProveVRF(message crypto.Hashable) (crypto.VrfProof, error) {
+	hashID, data := message.ToBeHashed()
+	req := proveVRFRequest{VotePK: k.votePK, HashID: hashID, Message: data}
+	var resp proveVRFResponse
+	err := k.client.do(http.MethodPost, proveVRFPath, req, &resp)
+	return resp.Proof, err
+}
+
+// SignVote implements account.ParticipationSigner. The signer ignores id,
+// and signs with the ephemeral key of the round of the vote.
+func (k remoteKey) SignVote(id crypto.OneTimeSignatureIdentifier, vote crypto.Hashable) (crypto.OneTimeSignature, error) {
+	hashID, data := vote.ToBeHashed()
+	if hashID != protocol.Vote {
+		return crypto.OneTimeSignature{}, fmt.Errorf("cannot have a %s message signed as a vote", hashID)
+	}
+
+	req := signVoteRequest{VotePK: k.votePK, Vote: data}
+	var resp signVoteResponse
+	err := k.client.do(http.MethodPost, signVotePath, req, &resp)
+	return resp.Sig, err
+}
+
+// DeleteOldKeys implements account.RemoteSigner.
+func (k remoteKey) DeleteOldKeys(current basics.Round, keyDilution uint64) error {
+	req := deleteOldKeysRequest{VotePK: k.votePK, Round: current, KeyDilution: keyDilution}
+	var resp struct{}
+	return k.client.do(http.MethodPost, deleteOldKeysPath, req, &resp)
+}
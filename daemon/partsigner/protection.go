@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package partsigner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+var errDoubleSign = errors.New("refusing to sign a different vote for an already signed round, period and step")
+var errStaleVote = errors.New("refusing to sign a vote for a round older than the last signed one")
+
+var protectionSchema = []string{
+	`CREATE TABLE IF NOT EXISTS signedvotes (
+		votekey blob NOT NULL,
+		round integer NOT NULL,
+		period integer NOT NULL,
+		step integer NOT NULL,
+		digest blob NOT NULL,
+		PRIMARY KEY (votekey, round, period, step))`,
+}
+
+// votePosition is the round, period and step a vote is cast for. Honest
+// participants cast at most one vote per position.
+type votePosition struct {
+	round  basics.Round
+	period uint64
+	step   uint64
+}
+
+// readVotePosition reads the position of an encoded vote. The vote is
+// decoded loosely, so that the signer does not depend on its other fields.
+func readVotePosition(vote []byte) (pos votePosition, err error) {
+	var fields map[string]interface{}
+	err = protocol.DecodeReflect(vote, &fields)
+	if err != nil {
+		return votePosition{}, fmt.Errorf("could not decode vote: %v", err)
+	}
+
+	var rnd uint64
+	for _, f := range []struct {
+		name string
+		val  *uint64
+	}{{"rnd", &rnd}, {"per", &pos.period}, {"step", &pos.step}} {
+		switch v := fields[f.name].(type) {
+		case nil:
+			// zero values are omitted from the encoding
+		case uint64:
+			*f.val = v
+		case int64:
+			if v < 0 {
+				return votePosition{}, fmt.Errorf("vote has negative %s %d", f.name, v)
+			}
+			*f.val = uint64(v)
+		default:
+			return votePosition{}, fmt.Errorf("vote has malformed %s %v", f.name, v)
+		}
+	}
+	pos.round = basics.Round(rnd)
+	return pos, nil
+}
+
+// protection records the votes signed with every participation key, so that
+// the signer never signs two different votes for the same position, nor a
+// vote for a round older than the last one it signed for. This keeps several
+// nodes sharing a signer from equivocating. Only the votes of the last signed
+// round are kept.
+type protection struct {
+	store db.Accessor
+}
+
+func makeProtection(store db.Accessor) (*protection, error) {
+	err := store.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		for _, stmt := range protectionSchema {
+			_, err := tx.Exec(stmt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("makeProtection: could not install database: %v", err)
+	}
+	return &protection{store: store}, nil
+}
+
+// record checks whether the vote with the given digest may be signed at pos
+// with the participation key votePK, and records it if so. Signing the same
+// vote again is allowed.
+func (p *protection) record(votePK crypto.OneTimeSignatureVerifier, pos votePosition, digest crypto.Digest) error {
+	return p.store.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var last sql.NullInt64
+		err := tx.QueryRow("SELECT MAX(round) FROM signedvotes WHERE votekey=?", votePK[:]).Scan(&last)
+		if err != nil {
+			return err
+		}
+		if last.Valid && pos.round < basics.Round(last.Int64) {
+			return errStaleVote
+		}
+
+		var signed []byte
+		err = tx.QueryRow("SELECT digest FROM signedvotes WHERE votekey=? AND round=? AND period=? AND step=?",
+			votePK[:], pos.round, pos.period, pos.step).Scan(&signed)
+		switch err {
+		case nil:
+			if string(signed) != string(digest[:]) {
+				return errDoubleSign
+			}
+			return nil
+		case sql.ErrNoRows:
+		default:
+			return err
+		}
+
+		_, err = tx.Exec("INSERT INTO signedvotes (votekey, round, period, step, digest) VALUES (?, ?, ?, ?, ?)",
+			votePK[:], pos.round, pos.period, pos.step, digest[:])
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("DELETE FROM signedvotes WHERE votekey=? AND round<?", votePK[:], pos.round)
+		return err
+	})
+}
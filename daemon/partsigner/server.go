@@ -0,0 +1,241 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package partsigner
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+	"github.com/algorand/go-algorand/util/tokens"
+)
+
+// maxRequestSize bounds the size of the requests the server reads.
+const maxRequestSize = 64 * 1024
+
+// A Server holds participation keys, and serves the VRF proofs and vote
+// signatures made with them to the algod nodes which know its API token.
+type Server struct {
+	log      logging.Logger
+	apiToken []byte
+
+	// mu serializes signing, so that the protection checks and records
+	// every vote before the next one gets signed.
+	mu         deadlock.Mutex
+	keys       map[crypto.OneTimeSignatureVerifier]account.PersistedParticipation
+	protection *protection
+}
+
+// MakeServer creates a Server for the given participation keys, which keeps
+// the record of the votes it signs in protectionDB.
+func MakeServer(log logging.Logger, apiToken string, keys []account.PersistedParticipation, protectionDB db.Accessor) (*Server, error) {
+	err := tokens.ValidateAPIToken(apiToken)
+	if err != nil {
+		return nil, fmt.Errorf("MakeServer: invalid API token: %v", err)
+	}
+
+	p, err := makeProtection(protectionDB)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		log:        log,
+		apiToken:   []byte(apiToken),
+		keys:       make(map[crypto.OneTimeSignatureVerifier]account.PersistedParticipation),
+		protection: p,
+	}
+	for _, part := range keys {
+		s.keys[part.Voting.OneTimeSignatureVerifier] = part
+	}
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Check the token in constant time
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), s.apiToken) != 1 {
+		s.respondError(w, http.StatusUnauthorized, fmt.Errorf("invalid API token"))
+		return
+	}
+
+	switch {
+	case r.URL.Path == keysPath && r.Method == http.MethodGet:
+		s.listKeys(w)
+	case r.URL.Path == proveVRFPath && r.Method == http.MethodPost:
+		s.proveVRF(w, r)
+	case r.URL.Path == signVotePath && r.Method == http.MethodPost:
+		s.signVote(w, r)
+	case r.URL.Path == deleteOldKeysPath && r.Method == http.MethodPost:
+		s.deleteOldKeys(w, r)
+	default:
+		s.respondError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s %s", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) listKeys(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var resp keysResponse
+	for _, part := range s.keys {
+		resp.Keys = append(resp.Keys, keyInfo{
+			Parent:      part.Parent,
+			SelectionPK: part.VRF.PK,
+			VotePK:      part.Voting.OneTimeSignatureVerifier,
+			FirstValid:  part.FirstValid,
+			LastValid:   part.LastValid,
+			KeyDilution: part.KeyDilution,
+		})
+	}
+	s.respond(w, resp)
+}
+
+func (s *Server) proveVRF(w http.ResponseWriter, r *http.Request) {
+	var req proveVRFRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	part, ok := s.lookup(w, req.VotePK)
+	if !ok {
+		return
+	}
+
+	proof, err := part.ProveVRF(hashedMessage{hashID: req.HashID, data: req.Message})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(w, proveVRFResponse{Proof: proof})
+}
+
+func (s *Server) signVote(w http.ResponseWriter, r *http.Request) {
+	var req signVoteRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	part, ok := s.lookup(w, req.VotePK)
+	if !ok {
+		return
+	}
+
+	pos, err := readVotePosition(req.Vote)
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if pos.round < part.FirstValid || pos.round > part.LastValid {
+		s.respondError(w, http.StatusBadRequest, fmt.Errorf("round %d is outside of the validity range %d-%d of the key", pos.round, part.FirstValid, part.LastValid))
+		return
+	}
+
+	vote := hashedMessage{hashID: protocol.Vote, data: req.Vote}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err = s.protection.record(req.VotePK, pos, crypto.HashObj(vote))
+	if err == errDoubleSign || err == errStaleVote {
+		s.log.Warnf("partsigner: vote by %v for round %d, period %d, step %d: %v", part.Parent, pos.round, pos.period, pos.step, err)
+		s.respondError(w, http.StatusConflict, err)
+		return
+	}
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, fmt.Errorf("could not record vote: %v", err))
+		return
+	}
+
+	// the ephemeral key is the one of the round the protection recorded,
+	// whatever key the client would expect.
+	keyDilution := part.KeyDilution
+	if keyDilution == 0 {
+		keyDilution = config.Consensus[protocol.ConsensusCurrentVersion].DefaultKeyDilution
+	}
+	sig, err := part.SignVote(basics.OneTimeIDForRound(pos.round, keyDilution), vote)
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(w, signVoteResponse{Sig: sig})
+}
+
+func (s *Server) deleteOldKeys(w http.ResponseWriter, r *http.Request) {
+	var req deleteOldKeysRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	part, ok := s.lookup(w, req.VotePK)
+	if !ok {
+		return
+	}
+
+	// the key dilution of the request only applies to keys without one.
+	err := <-part.DeleteOldKeys(req.Round, config.ConsensusParams{DefaultKeyDilution: req.KeyDilution})
+	if err != nil {
+		s.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.respond(w, struct{}{})
+}
+
+func (s *Server) lookup(w http.ResponseWriter, votePK crypto.OneTimeSignatureVerifier) (account.PersistedParticipation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	part, ok := s.keys[votePK]
+	if !ok {
+		s.respondError(w, http.StatusNotFound, fmt.Errorf("no participation key with voting key %v", votePK))
+	}
+	return part, ok
+}
+
+func (s *Server) decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err == nil {
+		err = protocol.DecodeReflect(body, req)
+	}
+	if err != nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Errorf("could not decode request: %v", err))
+		return false
+	}
+	return true
+}
+
+func (s *Server) respond(w http.ResponseWriter, resp interface{}) {
+	w.Header().Set("Content-Type", "application/msgpack")
+	w.WriteHeader(http.StatusOK)
+	w.Write(protocol.EncodeReflect(resp))
+}
+
+func (s *Server) respondError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/msgpack")
+	w.WriteHeader(status)
+	w.Write(protocol.EncodeReflect(errorResponse{Error: err.Error()}))
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package partsigner
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/account"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// testVote has the fields of the votes agreement has signed.
+type testVote struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Sender basics.Address `codec:"snd"`
+	Round  basics.Round   `codec:"rnd"`
+	Period uint64         `codec:"per"`
+	Step   uint64         `codec:"step"`
+	Note   []byte         `codec:"note"`
+}
+
+func (v testVote) ToBeHashed() (protocol.HashID, []byte) {
+	return protocol.Vote, protocol.EncodeReflect(v)
+}
+
+func makeTestSigner(t *testing.T) (*httptest.Server, account.PersistedParticipation) {
+	var parent basics.Address
+	crypto.RandBytes(parent[:])
+
+	partDB, err := db.MakeAccessor(t.Name()+"_part", false, true)
+	require.NoError(t, err)
+	part, err := account.FillDBWithParticipationKeys(partDB, parent, 0, 1000, 100)
+	require.NoError(t, err)
+	t.Cleanup(part.Close)
+
+	protectionDB, err := db.MakeAccessor(t.Name()+"_protection", false, true)
+	require.NoError(t, err)
+	t.Cleanup(protectionDB.Close)
+
+	apiToken := strings.Repeat("a", 64)
+	server, err := MakeServer(logging.TestingLog(t), apiToken, []account.PersistedParticipation{part}, protectionDB)
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return ts, part
+}
+
+func TestRemoteSigning(t *testing.T) {
+	ts, local := makeTestSigner(t)
+
+	parts, err := MakeClient(strings.TrimPrefix(ts.URL, "http://"), strings.Repeat("a", 64)).Participations()
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	part := parts[0]
+	require.Equal(t, local.Parent, part.Parent)
+	require.Equal(t, local.VRF.PK, part.VRF.PK)
+	require.Equal(t, local.Voting.OneTimeSignatureVerifier, part.Voting.OneTimeSignatureVerifier)
+	require.Equal(t, local.FirstValid, part.FirstValid)
+	require.Equal(t, local.LastValid, part.LastValid)
+	require.Equal(t, local.KeyDilution, part.KeyDilution)
+
+	seed := hashedMessage{hashID: protocol.Seed, data: []byte("seed")}
+	proof, err := part.ProveVRF(seed)
+	require.NoError(t, err)
+	ok, _ := part.VRF.PK.Verify(proof, seed)
+	require.True(t, ok)
+
+	vote := testVote{Sender: part.Parent, Round: 10, Period: 1, Step: 2}
+	id := basics.OneTimeIDForRound(vote.Round, part.KeyDilution)
+	sig, err := part.SignVote(id, vote)
+	require.NoError(t, err)
+	require.True(t, part.Voting.OneTimeSignatureVerifier.Verify(id, vote, sig))
+
+	// the same vote may be signed again, and the signer signs it with the
+	// key of its round whatever identifier the client asks for.
+	sig, err = part.SignVote(basics.OneTimeIDForRound(vote.Round+500, part.KeyDilution), vote)
+	require.NoError(t, err)
+	require.True(t, part.Voting.OneTimeSignatureVerifier.Verify(id, vote, sig))
+
+	// but not a different one for the same round, period and step.
+	equivocation := vote
+	equivocation.Note = []byte("equivocation")
+	_, err = part.SignVote(id, equivocation)
+	require.Error(t, err)
+
+	// a vote for another step of the round may be signed.
+	next := vote
+	next.Step = 3
+	_, err = part.SignVote(id, next)
+	require.NoError(t, err)
+
+	// but not a vote for an older round, once a later one was signed.
+	later := testVote{Sender: part.Parent, Round: 11}
+	_, err = part.SignVote(basics.OneTimeIDForRound(later.Round, part.KeyDilution), later)
+	require.NoError(t, err)
+	_, err = part.SignVote(id, vote)
+	require.Error(t, err)
+
+	// once the old keys are deleted, the signer cannot sign with them anymore.
+	ps := account.PersistedParticipation{Participation: part}
+	require.NoError(t, <-ps.DeleteOldKeys(300, config.Consensus[protocol.ConsensusCurrentVersion]))
+	old := testVote{Sender: part.Parent, Round: 250}
+	_, err = part.SignVote(basics.OneTimeIDForRound(old.Round, part.KeyDilution), old)
+	require.Error(t, err)
+}
@@ -54,6 +54,10 @@ type Participation struct {
 	LastValid  basics.Round
 
 	KeyDilution uint64
+
+	// Remote, if set, produces the VRF proofs and vote signatures of this
+	// Participation, whose VRF and Voting then only hold public keys.
+	Remote RemoteSigner
 }
 
 // PersistedParticipation encapsulates the static state of the participation
@@ -134,6 +138,15 @@ func (part PersistedParticipation) DeleteOldKeys(current basics.Round, proto con
 		keyDilution = proto.DefaultKeyDilution
 	}
 
+	if part.Remote != nil {
+		errorCh := make(chan error, 1)
+		go func() {
+			errorCh <- part.Remote.DeleteOldKeys(current, keyDilution)
+			close(errorCh)
+		}()
+		return errorCh
+	}
+
 	part.Voting.DeleteBeforeFineGrained(basics.OneTimeIDForRound(current, keyDilution), keyDilution)
 
 	errorCh := make(chan error, 1)
@@ -230,7 +243,11 @@ func Migrate(partDB db.Accessor) error {
 	})
 }
 
-// Close closes the underlying database handle.
+// Close closes the underlying database handle. Remote participations
+// have none.
 func (part PersistedParticipation) Close() {
+	if part.Remote != nil {
+		return
+	}
 	part.Store.Close()
 }
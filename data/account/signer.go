@@ -0,0 +1,92 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package account
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// A ParticipationSigner produces the VRF proofs and the vote signatures of a
+// participation key. Agreement uses it rather than the participation secrets,
+// so that the secrets may be held outside of the node.
+type ParticipationSigner interface {
+	// ProveVRF returns a VRF proof of message under the selection key.
+	ProveVRF(message crypto.Hashable) (crypto.VrfProof, error)
+
+	// SignVote signs a vote with the ephemeral voting key identified by id.
+	SignVote(id crypto.OneTimeSignatureIdentifier, vote crypto.Hashable) (crypto.OneTimeSignature, error)
+}
+
+// A RemoteSigner is a ParticipationSigner for secrets held by another
+// process, typically a signer daemon running on another host.
+type RemoteSigner interface {
+	ParticipationSigner
+
+	// DeleteOldKeys asks the signer to securely delete the ephemeral voting
+	// keys for rounds strictly older than current.
+	DeleteOldKeys(current basics.Round, keyDilution uint64) error
+}
+
+// ProveVRF implements ParticipationSigner, using the remote signer of the
+// participation if it has one.
+func (part Participation) ProveVRF(message crypto.Hashable) (crypto.VrfProof, error) {
+	if part.Remote != nil {
+		return part.Remote.ProveVRF(message)
+	}
+
+	proof, ok := part.VRF.SK.Prove(message)
+	if !ok {
+		return crypto.VrfProof{}, fmt.Errorf("could not make VRF proof -- participation key may be corrupt")
+	}
+	return proof, nil
+}
+
+// SignVote implements ParticipationSigner, using the remote signer of the
+// participation if it has one.
+func (part Participation) SignVote(id crypto.OneTimeSignatureIdentifier, vote crypto.Hashable) (crypto.OneTimeSignature, error) {
+	if part.Remote != nil {
+		return part.Remote.SignVote(id, vote)
+	}
+
+	sig := part.Voting.Sign(id, vote)
+	if (sig == crypto.OneTimeSignature{}) {
+		return crypto.OneTimeSignature{}, fmt.Errorf("got back empty signature for vote")
+	}
+	return sig, nil
+}
+
+// MakeRemoteParticipation returns a Participation whose VRF proofs and vote
+// signatures are produced by signer. It only holds the public keys of the
+// participation.
+func MakeRemoteParticipation(parent basics.Address, selectionPK crypto.VrfPubkey, votePK crypto.OneTimeSignatureVerifier, firstValid, lastValid basics.Round, keyDilution uint64, signer RemoteSigner) Participation {
+	return Participation{
+		Parent: parent,
+		VRF:    &crypto.VRFSecrets{PK: selectionPK},
+		Voting: &crypto.OneTimeSignatureSecrets{
+			OneTimeSignatureSecretsPersistent: crypto.OneTimeSignatureSecretsPersistent{
+				OneTimeSignatureVerifier: votePK,
+			},
+		},
+		FirstValid:  firstValid,
+		LastValid:   lastValid,
+		KeyDilution: keyDilution,
+		Remote:      signer,
+	}
+}
@@ -107,14 +107,6 @@ import (
 //      |-----> Msgsize
 //      |-----> MsgIsZero
 //
-// EvalDelta
-//     |-----> (*) MarshalMsg
-//     |-----> (*) CanMarshalMsg
-//     |-----> (*) UnmarshalMsg
-//     |-----> (*) CanUnmarshalMsg
-//     |-----> (*) Msgsize
-//     |-----> (*) MsgIsZero
-//
 // Round
 //   |-----> MarshalMsg
 //   |-----> CanMarshalMsg
@@ -200,8 +192,8 @@ import (
 func (z *AccountData) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0009Len := uint32(16)
-	var zb0009Mask uint32 /* 17 bits */
+	zb0009Len := uint32(18)
+	var zb0009Mask uint32 /* 19 bits */
 	if (*z).MicroAlgos.MsgIsZero() {
 		zb0009Len--
 		zb0009Mask |= 0x2
@@ -242,30 +234,38 @@ func (z *AccountData) MarshalMsg(b []byte) (o []byte) {
 		zb0009Len--
 		zb0009Mask |= 0x400
 	}
-	if (*z).TotalExtraAppPages == 0 {
+	if (*z).TotalBoxes == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x800
 	}
-	if ((*z).TotalAppSchema.NumUint == 0) && ((*z).TotalAppSchema.NumByteSlice == 0) {
+	if (*z).TotalBoxBytes == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x1000
 	}
-	if (*z).VoteID.MsgIsZero() {
+	if (*z).TotalExtraAppPages == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x2000
 	}
-	if (*z).VoteFirstValid == 0 {
+	if ((*z).TotalAppSchema.NumUint == 0) && ((*z).TotalAppSchema.NumByteSlice == 0) {
 		zb0009Len--
 		zb0009Mask |= 0x4000
 	}
-	if (*z).VoteKeyDilution == 0 {
+	if (*z).VoteID.MsgIsZero() {
 		zb0009Len--
 		zb0009Mask |= 0x8000
 	}
-	if (*z).VoteLastValid == 0 {
+	if (*z).VoteFirstValid == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x10000
 	}
+	if (*z).VoteKeyDilution == 0 {
+		zb0009Len--
+		zb0009Mask |= 0x20000
+	}
+	if (*z).VoteLastValid == 0 {
+		zb0009Len--
+		zb0009Mask |= 0x40000
+	}
 	// variable map header, size zb0009Len
 	o = msgp.AppendMapHeader(o, zb0009Len)
 	if zb0009Len != 0 {
@@ -404,11 +404,21 @@ func (z *AccountData) MarshalMsg(b []byte) (o []byte) {
 			o = (*z).AuthAddr.MarshalMsg(o)
 		}
 		if (zb0009Mask & 0x800) == 0 { // if not empty
+			// string "tbx"
+			o = append(o, 0xa3, 0x74, 0x62, 0x78)
+			o = msgp.AppendUint64(o, (*z).TotalBoxes)
+		}
+		if (zb0009Mask & 0x1000) == 0 { // if not empty
+			// string "tbxb"
+			o = append(o, 0xa4, 0x74, 0x62, 0x78, 0x62)
+			o = msgp.AppendUint64(o, (*z).TotalBoxBytes)
+		}
+		if (zb0009Mask & 0x2000) == 0 { // if not empty
 			// string "teap"
 			o = append(o, 0xa4, 0x74, 0x65, 0x61, 0x70)
 			o = msgp.AppendUint32(o, (*z).TotalExtraAppPages)
 		}
-		if (zb0009Mask & 0x1000) == 0 { // if not empty
+		if (zb0009Mask & 0x4000) == 0 { // if not empty
 			// string "tsch"
 			o = append(o, 0xa4, 0x74, 0x73, 0x63, 0x68)
 			// omitempty: check for empty values
@@ -435,22 +445,22 @@ func (z *AccountData) MarshalMsg(b []byte) (o []byte) {
 				o = msgp.AppendUint64(o, (*z).TotalAppSchema.NumUint)
 			}
 		}
-		if (zb0009Mask & 0x2000) == 0 { // if not empty
+		if (zb0009Mask & 0x8000) == 0 { // if not empty
 			// string "vote"
 			o = append(o, 0xa4, 0x76, 0x6f, 0x74, 0x65)
 			o = (*z).VoteID.MarshalMsg(o)
 		}
-		if (zb0009Mask & 0x4000) == 0 { // if not empty
+		if (zb0009Mask & 0x10000) == 0 { // if not empty
 			// string "voteFst"
 			o = append(o, 0xa7, 0x76, 0x6f, 0x74, 0x65, 0x46, 0x73, 0x74)
 			o = msgp.AppendUint64(o, uint64((*z).VoteFirstValid))
 		}
-		if (zb0009Mask & 0x8000) == 0 { // if not empty
+		if (zb0009Mask & 0x20000) == 0 { // if not empty
 			// string "voteKD"
 			o = append(o, 0xa6, 0x76, 0x6f, 0x74, 0x65, 0x4b, 0x44)
 			o = msgp.AppendUint64(o, (*z).VoteKeyDilution)
 		}
-		if (zb0009Mask & 0x10000) == 0 { // if not empty
+		if (zb0009Mask & 0x40000) == 0 { // if not empty
 			// string "voteLst"
 			o = append(o, 0xa7, 0x76, 0x6f, 0x74, 0x65, 0x4c, 0x73, 0x74)
 			o = msgp.AppendUint64(o, uint64((*z).VoteLastValid))
@@ -857,6 +867,22 @@ func (z *AccountData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		}
+		if zb0009 > 0 {
+			zb0009--
+			(*z).TotalBoxes, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalBoxes")
+				return
+			}
+		}
+		if zb0009 > 0 {
+			zb0009--
+			(*z).TotalBoxBytes, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalBoxBytes")
+				return
+			}
+		}
 		if zb0009 > 0 {
 			err = msgp.ErrTooManyArrayFields(zb0009)
 			if err != nil {
@@ -1228,6 +1254,18 @@ func (z *AccountData) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "TotalExtraAppPages")
 					return
 				}
+			case "tbx":
+				(*z).TotalBoxes, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalBoxes")
+					return
+				}
+			case "tbxb":
+				(*z).TotalBoxBytes, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalBoxBytes")
+					return
+				}
 			default:
 				err = msgp.ErrNoField(string(field))
 				if err != nil {
@@ -1280,13 +1318,13 @@ func (z *AccountData) Msgsize() (s int) {
 			s += 0 + zb0007.Msgsize() + zb0008.Msgsize()
 		}
 	}
-	s += 5 + 1 + 4 + msgp.Uint64Size + 4 + msgp.Uint64Size + 5 + msgp.Uint32Size
+	s += 5 + 1 + 4 + msgp.Uint64Size + 4 + msgp.Uint64Size + 5 + msgp.Uint32Size + 4 + msgp.Uint64Size + 5 + msgp.Uint64Size
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *AccountData) MsgIsZero() bool {
-	return ((*z).Status == 0) && ((*z).MicroAlgos.MsgIsZero()) && ((*z).RewardsBase == 0) && ((*z).RewardedMicroAlgos.MsgIsZero()) && ((*z).VoteID.MsgIsZero()) && ((*z).SelectionID.MsgIsZero()) && ((*z).VoteFirstValid == 0) && ((*z).VoteLastValid == 0) && ((*z).VoteKeyDilution == 0) && (len((*z).AssetParams) == 0) && (len((*z).Assets) == 0) && ((*z).AuthAddr.MsgIsZero()) && (len((*z).AppLocalStates) == 0) && (len((*z).AppParams) == 0) && (((*z).TotalAppSchema.NumUint == 0) && ((*z).TotalAppSchema.NumByteSlice == 0)) && ((*z).TotalExtraAppPages == 0)
+	return ((*z).Status == 0) && ((*z).MicroAlgos.MsgIsZero()) && ((*z).RewardsBase == 0) && ((*z).RewardedMicroAlgos.MsgIsZero()) && ((*z).VoteID.MsgIsZero()) && ((*z).SelectionID.MsgIsZero()) && ((*z).VoteFirstValid == 0) && ((*z).VoteLastValid == 0) && ((*z).VoteKeyDilution == 0) && (len((*z).AssetParams) == 0) && (len((*z).Assets) == 0) && ((*z).AuthAddr.MsgIsZero()) && (len((*z).AppLocalStates) == 0) && (len((*z).AppParams) == 0) && (((*z).TotalAppSchema.NumUint == 0) && ((*z).TotalAppSchema.NumByteSlice == 0)) && ((*z).TotalExtraAppPages == 0) && ((*z).TotalBoxes == 0) && ((*z).TotalBoxBytes == 0)
 }
 
 // MarshalMsg implements msgp.Marshaler
@@ -2875,8 +2913,8 @@ func (z *AssetParams) MsgIsZero() bool {
 func (z *BalanceRecord) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0009Len := uint32(17)
-	var zb0009Mask uint32 /* 19 bits */
+	zb0009Len := uint32(19)
+	var zb0009Mask uint32 /* 21 bits */
 	if (*z).Addr.MsgIsZero() {
 		zb0009Len--
 		zb0009Mask |= 0x4
@@ -2921,30 +2959,38 @@ func (z *BalanceRecord) MarshalMsg(b []byte) (o []byte) {
 		zb0009Len--
 		zb0009Mask |= 0x1000
 	}
-	if (*z).AccountData.TotalExtraAppPages == 0 {
+	if (*z).AccountData.TotalBoxes == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x2000
 	}
-	if ((*z).AccountData.TotalAppSchema.NumUint == 0) && ((*z).AccountData.TotalAppSchema.NumByteSlice == 0) {
+	if (*z).AccountData.TotalBoxBytes == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x4000
 	}
-	if (*z).AccountData.VoteID.MsgIsZero() {
+	if (*z).AccountData.TotalExtraAppPages == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x8000
 	}
-	if (*z).AccountData.VoteFirstValid == 0 {
+	if ((*z).AccountData.TotalAppSchema.NumUint == 0) && ((*z).AccountData.TotalAppSchema.NumByteSlice == 0) {
 		zb0009Len--
 		zb0009Mask |= 0x10000
 	}
-	if (*z).AccountData.VoteKeyDilution == 0 {
+	if (*z).AccountData.VoteID.MsgIsZero() {
 		zb0009Len--
 		zb0009Mask |= 0x20000
 	}
-	if (*z).AccountData.VoteLastValid == 0 {
+	if (*z).AccountData.VoteFirstValid == 0 {
 		zb0009Len--
 		zb0009Mask |= 0x40000
 	}
+	if (*z).AccountData.VoteKeyDilution == 0 {
+		zb0009Len--
+		zb0009Mask |= 0x80000
+	}
+	if (*z).AccountData.VoteLastValid == 0 {
+		zb0009Len--
+		zb0009Mask |= 0x100000
+	}
 	// variable map header, size zb0009Len
 	o = msgp.AppendMapHeader(o, zb0009Len)
 	if zb0009Len != 0 {
@@ -3088,11 +3134,21 @@ func (z *BalanceRecord) MarshalMsg(b []byte) (o []byte) {
 			o = (*z).AccountData.AuthAddr.MarshalMsg(o)
 		}
 		if (zb0009Mask & 0x2000) == 0 { // if not empty
+			// string "tbx"
+			o = append(o, 0xa3, 0x74, 0x62, 0x78)
+			o = msgp.AppendUint64(o, (*z).AccountData.TotalBoxes)
+		}
+		if (zb0009Mask & 0x4000) == 0 { // if not empty
+			// string "tbxb"
+			o = append(o, 0xa4, 0x74, 0x62, 0x78, 0x62)
+			o = msgp.AppendUint64(o, (*z).AccountData.TotalBoxBytes)
+		}
+		if (zb0009Mask & 0x8000) == 0 { // if not empty
 			// string "teap"
 			o = append(o, 0xa4, 0x74, 0x65, 0x61, 0x70)
 			o = msgp.AppendUint32(o, (*z).AccountData.TotalExtraAppPages)
 		}
-		if (zb0009Mask & 0x4000) == 0 { // if not empty
+		if (zb0009Mask & 0x10000) == 0 { // if not empty
 			// string "tsch"
 			o = append(o, 0xa4, 0x74, 0x73, 0x63, 0x68)
 			// omitempty: check for empty values
@@ -3119,22 +3175,22 @@ func (z *BalanceRecord) MarshalMsg(b []byte) (o []byte) {
 				o = msgp.AppendUint64(o, (*z).AccountData.TotalAppSchema.NumUint)
 			}
 		}
-		if (zb0009Mask & 0x8000) == 0 { // if not empty
+		if (zb0009Mask & 0x20000) == 0 { // if not empty
 			// string "vote"
 			o = append(o, 0xa4, 0x76, 0x6f, 0x74, 0x65)
 			o = (*z).AccountData.VoteID.MarshalMsg(o)
 		}
-		if (zb0009Mask & 0x10000) == 0 { // if not empty
+		if (zb0009Mask & 0x40000) == 0 { // if not empty
 			// string "voteFst"
 			o = append(o, 0xa7, 0x76, 0x6f, 0x74, 0x65, 0x46, 0x73, 0x74)
 			o = msgp.AppendUint64(o, uint64((*z).AccountData.VoteFirstValid))
 		}
-		if (zb0009Mask & 0x20000) == 0 { // if not empty
+		if (zb0009Mask & 0x80000) == 0 { // if not empty
 			// string "voteKD"
 			o = append(o, 0xa6, 0x76, 0x6f, 0x74, 0x65, 0x4b, 0x44)
 			o = msgp.AppendUint64(o, (*z).AccountData.VoteKeyDilution)
 		}
-		if (zb0009Mask & 0x40000) == 0 { // if not empty
+		if (zb0009Mask & 0x100000) == 0 { // if not empty
 			// string "voteLst"
 			o = append(o, 0xa7, 0x76, 0x6f, 0x74, 0x65, 0x4c, 0x73, 0x74)
 			o = msgp.AppendUint64(o, uint64((*z).AccountData.VoteLastValid))
@@ -3549,6 +3605,22 @@ func (z *BalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		}
+		if zb0009 > 0 {
+			zb0009--
+			(*z).AccountData.TotalBoxes, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalBoxes")
+				return
+			}
+		}
+		if zb0009 > 0 {
+			zb0009--
+			(*z).AccountData.TotalBoxBytes, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalBoxBytes")
+				return
+			}
+		}
 		if zb0009 > 0 {
 			err = msgp.ErrTooManyArrayFields(zb0009)
 			if err != nil {
@@ -3926,6 +3998,18 @@ func (z *BalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "TotalExtraAppPages")
 					return
 				}
+			case "tbx":
+				(*z).AccountData.TotalBoxes, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalBoxes")
+					return
+				}
+			case "tbxb":
+				(*z).AccountData.TotalBoxBytes, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalBoxBytes")
+					return
+				}
 			default:
 				err = msgp.ErrNoField(string(field))
 				if err != nil {
@@ -3978,13 +4062,13 @@ func (z *BalanceRecord) Msgsize() (s int) {
 			s += 0 + zb0007.Msgsize() + zb0008.Msgsize()
 		}
 	}
-	s += 5 + 1 + 4 + msgp.Uint64Size + 4 + msgp.Uint64Size + 5 + msgp.Uint32Size
+	s += 5 + 1 + 4 + msgp.Uint64Size + 4 + msgp.Uint64Size + 5 + msgp.Uint32Size + 4 + msgp.Uint64Size + 5 + msgp.Uint64Size
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *BalanceRecord) MsgIsZero() bool {
-	return ((*z).Addr.MsgIsZero()) && ((*z).AccountData.Status == 0) && ((*z).AccountData.MicroAlgos.MsgIsZero()) && ((*z).AccountData.RewardsBase == 0) && ((*z).AccountData.RewardedMicroAlgos.MsgIsZero()) && ((*z).AccountData.VoteID.MsgIsZero()) && ((*z).AccountData.SelectionID.MsgIsZero()) && ((*z).AccountData.VoteFirstValid == 0) && ((*z).AccountData.VoteLastValid == 0) && ((*z).AccountData.VoteKeyDilution == 0) && (len((*z).AccountData.AssetParams) == 0) && (len((*z).AccountData.Assets) == 0) && ((*z).AccountData.AuthAddr.MsgIsZero()) && (len((*z).AccountData.AppLocalStates) == 0) && (len((*z).AccountData.AppParams) == 0) && (((*z).AccountData.TotalAppSchema.NumUint == 0) && ((*z).AccountData.TotalAppSchema.NumByteSlice == 0)) && ((*z).AccountData.TotalExtraAppPages == 0)
+	return ((*z).Addr.MsgIsZero()) && ((*z).AccountData.Status == 0) && ((*z).AccountData.MicroAlgos.MsgIsZero()) && ((*z).AccountData.RewardsBase == 0) && ((*z).AccountData.RewardedMicroAlgos.MsgIsZero()) && ((*z).AccountData.VoteID.MsgIsZero()) && ((*z).AccountData.SelectionID.MsgIsZero()) && ((*z).AccountData.VoteFirstValid == 0) && ((*z).AccountData.VoteLastValid == 0) && ((*z).AccountData.VoteKeyDilution == 0) && (len((*z).AccountData.AssetParams) == 0) && (len((*z).AccountData.Assets) == 0) && ((*z).AccountData.AuthAddr.MsgIsZero()) && (len((*z).AccountData.AppLocalStates) == 0) && (len((*z).AccountData.AppParams) == 0) && (((*z).AccountData.TotalAppSchema.NumUint == 0) && ((*z).AccountData.TotalAppSchema.NumByteSlice == 0)) && ((*z).AccountData.TotalExtraAppPages == 0) && ((*z).AccountData.TotalBoxes == 0) && ((*z).AccountData.TotalBoxBytes == 0)
 }
 
 // MarshalMsg implements msgp.Marshaler
@@ -4125,370 +4209,6 @@ func (z DeltaAction) MsgIsZero() bool {
 	return z == 0
 }
 
-// MarshalMsg implements msgp.Marshaler
-func (z *EvalDelta) MarshalMsg(b []byte) (o []byte) {
-	o = msgp.Require(b, z.Msgsize())
-	// omitempty: check for empty values
-	zb0007Len := uint32(2)
-	var zb0007Mask uint8 /* 3 bits */
-	if len((*z).GlobalDelta) == 0 {
-		zb0007Len--
-		zb0007Mask |= 0x2
-	}
-	if len((*z).LocalDeltas) == 0 {
-		zb0007Len--
-		zb0007Mask |= 0x4
-	}
-	// variable map header, size zb0007Len
-	o = append(o, 0x80|uint8(zb0007Len))
-	if zb0007Len != 0 {
-		if (zb0007Mask & 0x2) == 0 { // if not empty
-			// string "gd"
-			o = append(o, 0xa2, 0x67, 0x64)
-			if (*z).GlobalDelta == nil {
-				o = msgp.AppendNil(o)
-			} else {
-				o = msgp.AppendMapHeader(o, uint32(len((*z).GlobalDelta)))
-			}
-			zb0001_keys := make([]string, 0, len((*z).GlobalDelta))
-			for zb0001 := range (*z).GlobalDelta {
-				zb0001_keys = append(zb0001_keys, zb0001)
-			}
-			sort.Sort(SortString(zb0001_keys))
-			for _, zb0001 := range zb0001_keys {
-				zb0002 := (*z).GlobalDelta[zb0001]
-				_ = zb0002
-				o = msgp.AppendString(o, zb0001)
-				o = zb0002.MarshalMsg(o)
-			}
-		}
-		if (zb0007Mask & 0x4) == 0 { // if not empty
-			// string "ld"
-			o = append(o, 0xa2, 0x6c, 0x64)
-			if (*z).LocalDeltas == nil {
-				o = msgp.AppendNil(o)
-			} else {
-				o = msgp.AppendMapHeader(o, uint32(len((*z).LocalDeltas)))
-			}
-			zb0003_keys := make([]uint64, 0, len((*z).LocalDeltas))
-			for zb0003 := range (*z).LocalDeltas {
-				zb0003_keys = append(zb0003_keys, zb0003)
-			}
-			sort.Sort(SortUint64(zb0003_keys))
-			for _, zb0003 := range zb0003_keys {
-				zb0004 := (*z).LocalDeltas[zb0003]
-				_ = zb0004
-				o = msgp.AppendUint64(o, zb0003)
-				if zb0004 == nil {
-					o = msgp.AppendNil(o)
-				} else {
-					o = msgp.AppendMapHeader(o, uint32(len(zb0004)))
-				}
-				zb0005_keys := make([]string, 0, len(zb0004))
-				for zb0005 := range zb0004 {
-					zb0005_keys = append(zb0005_keys, zb0005)
-				}
-				sort.Sort(SortString(zb0005_keys))
-				for _, zb0005 := range zb0005_keys {
-					zb0006 := zb0004[zb0005]
-					_ = zb0006
-					o = msgp.AppendString(o, zb0005)
-					o = zb0006.MarshalMsg(o)
-				}
-			}
-		}
-	}
-	return
-}
-
-func (_ *EvalDelta) CanMarshalMsg(z interface{}) bool {
-	_, ok := (z).(*EvalDelta)
-	return ok
-}
-
-// UnmarshalMsg implements msgp.Unmarshaler
-func (z *EvalDelta) UnmarshalMsg(bts []byte) (o []byte, err error) {
-	var field []byte
-	_ = field
-	var zb0007 int
-	var zb0008 bool
-	zb0007, zb0008, bts, err = msgp.ReadMapHeaderBytes(bts)
-	if _, ok := err.(msgp.TypeError); ok {
-		zb0007, zb0008, bts, err = msgp.ReadArrayHeaderBytes(bts)
-		if err != nil {
-			err = msgp.WrapError(err)
-			return
-		}
-		if zb0007 > 0 {
-			zb0007--
-			var zb0009 int
-			var zb0010 bool
-			zb0009, zb0010, bts, err = msgp.ReadMapHeaderBytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "struct-from-array", "GlobalDelta")
-				return
-			}
-			if zb0009 > config.MaxStateDeltaKeys {
-				err = msgp.ErrOverflow(uint64(zb0009), uint64(config.MaxStateDeltaKeys))
-				err = msgp.WrapError(err, "struct-from-array", "GlobalDelta")
-				return
-			}
-			if zb0010 {
-				(*z).GlobalDelta = nil
-			} else if (*z).GlobalDelta == nil {
-				(*z).GlobalDelta = make(StateDelta, zb0009)
-			}
-			for zb0009 > 0 {
-				var zb0001 string
-				var zb0002 ValueDelta
-				zb0009--
-				zb0001, bts, err = msgp.ReadStringBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "struct-from-array", "GlobalDelta")
-					return
-				}
-				bts, err = zb0002.UnmarshalMsg(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "struct-from-array", "GlobalDelta", zb0001)
-					return
-				}
-				(*z).GlobalDelta[zb0001] = zb0002
-			}
-		}
-		if zb0007 > 0 {
-			zb0007--
-			var zb0011 int
-			var zb0012 bool
-			zb0011, zb0012, bts, err = msgp.ReadMapHeaderBytes(bts)
-			if err != nil {
-				err = msgp.WrapError(err, "struct-from-array", "LocalDeltas")
-				return
-			}
-			if zb0011 > config.MaxEvalDeltaAccounts {
-				err = msgp.ErrOverflow(uint64(zb0011), uint64(config.MaxEvalDeltaAccounts))
-				err = msgp.WrapError(err, "struct-from-array", "LocalDeltas")
-				return
-			}
-			if zb0012 {
-				(*z).LocalDeltas = nil
-			} else if (*z).LocalDeltas == nil {
-				(*z).LocalDeltas = make(map[uint64]StateDelta, zb0011)
-			}
-			for zb0011 > 0 {
-				var zb0003 uint64
-				var zb0004 StateDelta
-				zb0011--
-				zb0003, bts, err = msgp.ReadUint64Bytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "struct-from-array", "LocalDeltas")
-					return
-				}
-				var zb0013 int
-				var zb0014 bool
-				zb0013, zb0014, bts, err = msgp.ReadMapHeaderBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "struct-from-array", "LocalDeltas", zb0003)
-					return
-				}
-				if zb0013 > config.MaxStateDeltaKeys {
-					err = msgp.ErrOverflow(uint64(zb0013), uint64(config.MaxStateDeltaKeys))
-					err = msgp.WrapError(err, "struct-from-array", "LocalDeltas", zb0003)
-					return
-				}
-				if zb0014 {
-					zb0004 = nil
-				} else if zb0004 == nil {
-					zb0004 = make(StateDelta, zb0013)
-				}
-				for zb0013 > 0 {
-					var zb0005 string
-					var zb0006 ValueDelta
-					zb0013--
-					zb0005, bts, err = msgp.ReadStringBytes(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "struct-from-array", "LocalDeltas", zb0003)
-						return
-					}
-					bts, err = zb0006.UnmarshalMsg(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "struct-from-array", "LocalDeltas", zb0003, zb0005)
-						return
-					}
-					zb0004[zb0005] = zb0006
-				}
-				(*z).LocalDeltas[zb0003] = zb0004
-			}
-		}
-		if zb0007 > 0 {
-			err = msgp.ErrTooManyArrayFields(zb0007)
-			if err != nil {
-				err = msgp.WrapError(err, "struct-from-array")
-				return
-			}
-		}
-	} else {
-		if err != nil {
-			err = msgp.WrapError(err)
-			return
-		}
-		if zb0008 {
-			(*z) = EvalDelta{}
-		}
-		for zb0007 > 0 {
-			zb0007--
-			field, bts, err = msgp.ReadMapKeyZC(bts)
-			if err != nil {
-				err = msgp.WrapError(err)
-				return
-			}
-			switch string(field) {
-			case "gd":
-				var zb0015 int
-				var zb0016 bool
-				zb0015, zb0016, bts, err = msgp.ReadMapHeaderBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "GlobalDelta")
-					return
-				}
-				if zb0015 > config.MaxStateDeltaKeys {
-					err = msgp.ErrOverflow(uint64(zb0015), uint64(config.MaxStateDeltaKeys))
-					err = msgp.WrapError(err, "GlobalDelta")
-					return
-				}
-				if zb0016 {
-					(*z).GlobalDelta = nil
-				} else if (*z).GlobalDelta == nil {
-					(*z).GlobalDelta = make(StateDelta, zb0015)
-				}
-				for zb0015 > 0 {
-					var zb0001 string
-					var zb0002 ValueDelta
-					zb0015--
-					zb0001, bts, err = msgp.ReadStringBytes(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "GlobalDelta")
-						return
-					}
-					bts, err = zb0002.UnmarshalMsg(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "GlobalDelta", zb0001)
-						return
-					}
-					(*z).GlobalDelta[zb0001] = zb0002
-				}
-			case "ld":
-				var zb0017 int
-				var zb0018 bool
-				zb0017, zb0018, bts, err = msgp.ReadMapHeaderBytes(bts)
-				if err != nil {
-					err = msgp.WrapError(err, "LocalDeltas")
-					return
-				}
-				if zb0017 > config.MaxEvalDeltaAccounts {
-					err = msgp.ErrOverflow(uint64(zb0017), uint64(config.MaxEvalDeltaAccounts))
-					err = msgp.WrapError(err, "LocalDeltas")
-					return
-				}
-				if zb0018 {
-					(*z).LocalDeltas = nil
-				} else if (*z).LocalDeltas == nil {
-					(*z).LocalDeltas = make(map[uint64]StateDelta, zb0017)
-				}
-				for zb0017 > 0 {
-					var zb0003 uint64
-					var zb0004 StateDelta
-					zb0017--
-					zb0003, bts, err = msgp.ReadUint64Bytes(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "LocalDeltas")
-						return
-					}
-					var zb0019 int
-					var zb0020 bool
-					zb0019, zb0020, bts, err = msgp.ReadMapHeaderBytes(bts)
-					if err != nil {
-						err = msgp.WrapError(err, "LocalDeltas", zb0003)
-						return
-					}
-					if zb0019 > config.MaxStateDeltaKeys {
-						err = msgp.ErrOverflow(uint64(zb0019), uint64(config.MaxStateDeltaKeys))
-						err = msgp.WrapError(err, "LocalDeltas", zb0003)
-						return
-					}
-					if zb0020 {
-						zb0004 = nil
-					} else if zb0004 == nil {
-						zb0004 = make(StateDelta, zb0019)
-					}
-					for zb0019 > 0 {
-						var zb0005 string
-						var zb0006 ValueDelta
-						zb0019--
-						zb0005, bts, err = msgp.ReadStringBytes(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "LocalDeltas", zb0003)
-							return
-						}
-						bts, err = zb0006.UnmarshalMsg(bts)
-						if err != nil {
-							err = msgp.WrapError(err, "LocalDeltas", zb0003, zb0005)
-							return
-						}
-						zb0004[zb0005] = zb0006
-					}
-					(*z).LocalDeltas[zb0003] = zb0004
-				}
-			default:
-				err = msgp.ErrNoField(string(field))
-				if err != nil {
-					err = msgp.WrapError(err)
-					return
-				}
-			}
-		}
-	}
-	o = bts
-	return
-}
-
-func (_ *EvalDelta) CanUnmarshalMsg(z interface{}) bool {
-	_, ok := (z).(*EvalDelta)
-	return ok
-}
-
-// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *EvalDelta) Msgsize() (s int) {
-	s = 1 + 3 + msgp.MapHeaderSize
-	if (*z).GlobalDelta != nil {
-		for zb0001, zb0002 := range (*z).GlobalDelta {
-			_ = zb0001
-			_ = zb0002
-			s += 0 + msgp.StringPrefixSize + len(zb0001) + zb0002.Msgsize()
-		}
-	}
-	s += 3 + msgp.MapHeaderSize
-	if (*z).LocalDeltas != nil {
-		for zb0003, zb0004 := range (*z).LocalDeltas {
-			_ = zb0003
-			_ = zb0004
-			s += 0 + msgp.Uint64Size + msgp.MapHeaderSize
-			if zb0004 != nil {
-				for zb0005, zb0006 := range zb0004 {
-					_ = zb0005
-					_ = zb0006
-					s += 0 + msgp.StringPrefixSize + len(zb0005) + zb0006.Msgsize()
-				}
-			}
-		}
-	}
-	return
-}
-
-// MsgIsZero returns whether this is a zero value
-func (z *EvalDelta) MsgIsZero() bool {
-	return (len((*z).GlobalDelta) == 0) && (len((*z).LocalDeltas) == 0)
-}
-
 // MarshalMsg implements msgp.Marshaler
 func (z Round) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
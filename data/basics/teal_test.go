@@ -136,80 +136,3 @@ func TestStateDeltaEqual(t *testing.T) {
 	d2 = StateDelta{"test": {Action: SetBytesAction, Bytes: "val1"}}
 	a.False(d1.Equal(d2))
 }
-
-func TestEvalDeltaEqual(t *testing.T) {
-	a := require.New(t)
-
-	d1 := EvalDelta{}
-	d2 := EvalDelta{}
-	a.True(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		GlobalDelta: nil,
-		LocalDeltas: nil,
-	}
-	a.True(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		GlobalDelta: StateDelta{},
-		LocalDeltas: map[uint64]StateDelta{},
-	}
-	a.True(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		GlobalDelta: StateDelta{"test": {Action: SetUintAction, Uint: 0}},
-	}
-	a.False(d1.Equal(d2))
-
-	d1 = EvalDelta{
-		GlobalDelta: StateDelta{"test": {Action: SetUintAction, Uint: 0}},
-	}
-	a.True(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			0: {"test": {Action: SetUintAction, Uint: 0}},
-		},
-	}
-	a.False(d1.Equal(d2))
-
-	d1 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			0: {"test": {Action: SetUintAction, Uint: 1}},
-		},
-	}
-	a.False(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			0: {"test": {Action: SetUintAction, Uint: 1}},
-		},
-	}
-	a.True(d1.Equal(d2))
-
-	d1 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			0: {"test": {Action: SetBytesAction, Bytes: "val"}},
-		},
-	}
-	d2 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			0: {"test": {Action: SetBytesAction, Bytes: "val"}},
-		},
-	}
-	a.True(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			0: {"test": {Action: SetBytesAction, Bytes: "val1"}},
-		},
-	}
-	a.False(d1.Equal(d2))
-
-	d2 = EvalDelta{
-		LocalDeltas: map[uint64]StateDelta{
-			1: {"test": {Action: SetBytesAction, Bytes: "val"}},
-		},
-	}
-	a.False(d1.Equal(d2))
-}
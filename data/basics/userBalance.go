@@ -17,6 +17,7 @@
 package basics
 
 import (
+	"encoding/binary"
 	"fmt"
 	"reflect"
 
@@ -207,6 +208,12 @@ type AccountData struct {
 	// TotalExtraAppPages stores the extra length in pages (MaxAppProgramLen bytes per page)
 	// requested for app program by this account
 	TotalExtraAppPages uint32 `codec:"teap"`
+
+	// TotalBoxes and TotalBoxBytes track the number of boxes, and the
+	// combined size of their names and values, owned by the application
+	// whose address this account is. They are used to compute MinBalance.
+	TotalBoxes    uint64 `codec:"tbx"`
+	TotalBoxBytes uint64 `codec:"tbxb"`
 }
 
 // AppLocalState stores the LocalState associated with an application. It also
@@ -291,6 +298,20 @@ type AssetIndex uint64
 // AppParams
 type AppIndex uint64
 
+// ToBeHashed implements crypto.Hashable
+func (app AppIndex) ToBeHashed() (protocol.HashID, []byte) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(app))
+	return protocol.AppIndex, buf
+}
+
+// Address returns the address of the account controlled by the application.
+// Nobody holds the secret key of this address, so funds held by it can only
+// be moved by inner transactions issued by the application itself.
+func (app AppIndex) Address() Address {
+	return Address(crypto.HashObj(app))
+}
+
 // CreatableIndex represents either an AssetIndex or AppIndex, which come from
 // the same namespace of indices as each other (both assets and apps are
 // "creatables")
@@ -449,6 +470,12 @@ func (u AccountData) MinBalance(proto *config.ConsensusParams) (res MicroAlgos)
 	extraAppProgramLenCost := MulSaturate(proto.AppFlatParamsMinBalance, uint64(u.TotalExtraAppPages))
 	min = AddSaturate(min, extraAppProgramLenCost)
 
+	// MinBalance for each box, and for the bytes stored in boxes
+	boxCost := MulSaturate(proto.BoxFlatMinBalance, u.TotalBoxes)
+	min = AddSaturate(min, boxCost)
+	boxByteCost := MulSaturate(proto.BoxByteMinBalance, u.TotalBoxBytes)
+	min = AddSaturate(min, boxByteCost)
+
 	res.Raw = min
 	return res
 }
@@ -223,3 +223,20 @@ func TestEncodedAccountAllocationBounds(t *testing.T) {
 		}
 	}
 }
+
+func TestAppIndexHashing(t *testing.T) {
+	i := AppIndex(12)
+	prefix, buf := i.ToBeHashed()
+	require.Equal(t, protocol.HashID("appID"), prefix)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0c}, buf)
+
+	i = AppIndex(12 << 16)
+	prefix, buf = i.ToBeHashed()
+	require.Equal(t, protocol.HashID("appID"), prefix)
+	require.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x0c, 0x00, 0x00}, buf)
+
+	// the address is derived from the hash, and differs between apps
+	require.Equal(t, Address(crypto.Hash(append([]byte("appID"), buf...))), i.Address())
+	require.NotEqual(t, AppIndex(12).Address(), i.Address())
+	require.NotEqual(t, Address{}, i.Address())
+}
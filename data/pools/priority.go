@@ -0,0 +1,310 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package pools
+
+import (
+	"container/heap"
+	"math/bits"
+	"sort"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+)
+
+// txGroupPriority is the fee per byte a transaction group pays, which
+// determines the order in which the pool proposes groups, and which
+// groups it evicts when it is full. It is kept as a fraction, so that
+// groups paying close fees compare exactly.
+type txGroupPriority struct {
+	fee    uint64
+	length uint64
+}
+
+// makeTxGroupPriority returns the priority of a transaction group, which is
+// its total fee over its total encoded length. Fees are pooled across the
+// group, so the group is prioritized as a whole.
+func makeTxGroupPriority(txgroup []transactions.SignedTxn) (p txGroupPriority) {
+	for _, t := range txgroup {
+		p.fee = basics.AddSaturate(p.fee, t.Txn.Fee.Raw)
+		p.length += uint64(t.GetEncodedLength())
+	}
+	if p.length == 0 {
+		p.length = 1
+	}
+	return
+}
+
+// less returns whether p pays less per byte than other.
+func (p txGroupPriority) less(other txGroupPriority) bool {
+	hi1, lo1 := bits.Mul64(p.fee, other.length)
+	hi2, lo2 := bits.Mul64(other.fee, p.length)
+	return hi1 < hi2 || (hi1 == hi2 && lo1 < lo2)
+}
+
+// feePerByte returns the fee per byte of the priority, rounded down.
+func (p txGroupPriority) feePerByte() uint64 {
+	return p.fee / p.length
+}
+
+// capPriorities lowers the priority of every group to that of the earlier
+// groups it may depend on, that is, the earlier groups which involve one of
+// its senders. This way, a group is never ordered ahead of, nor kept in the
+// pool longer than, a group whose changes it may need.
+func capPriorities(txgroups [][]transactions.SignedTxn, priorities []txGroupPriority) {
+	lowest := make(map[basics.Address]txGroupPriority)
+	for i, txgroup := range txgroups {
+		for _, t := range txgroup {
+			if p, ok := lowest[t.Txn.Sender]; ok && p.less(priorities[i]) {
+				priorities[i] = p
+			}
+		}
+		for _, t := range txgroup {
+			for _, addr := range t.Txn.RelevantAddrs(transactions.SpecialAddresses{}) {
+				if addr.IsZero() {
+					continue
+				}
+				if p, ok := lowest[addr]; !ok || priorities[i].less(p) {
+					lowest[addr] = priorities[i]
+				}
+			}
+		}
+	}
+}
+
+// sortByPriority reorders the given groups, and their priorities, from the
+// highest to the lowest priority. The priorities are capped first, so that
+// a group never moves ahead of an earlier group it may depend on.
+func sortByPriority(txgroups [][]transactions.SignedTxn, priorities []txGroupPriority) {
+	capPriorities(txgroups, priorities)
+	sort.Stable(byPriority{txgroups: txgroups, priorities: priorities})
+}
+
+// queuedTxGroup is a pending transaction group held in an evictionQueue.
+type queuedTxGroup struct {
+	txgroup []transactions.SignedTxn
+	// priority is the group's own priority, and capped its priority
+	// lowered to that of the earlier groups it may depend on.
+	priority txGroupPriority
+	capped   txGroupPriority
+	// seq orders the groups by the time they were queued.
+	seq uint64
+	// index is the position of the group in the heap, or -1 once the
+	// group has been removed from the queue.
+	index int
+}
+
+// evictionQueue keeps the pending transaction groups in a min-heap of their
+// capped priorities, so that a full pool finds the groups to evict without
+// sorting all of them. As with capPriorities, a group's priority is capped
+// by those of the earlier groups involving one of its senders. Among groups
+// of the same capped priority, the latest queued one comes first, so that a
+// group is never evicted ahead of the later groups which may depend on it.
+type evictionQueue struct {
+	heap    txGroupHeap
+	nextSeq uint64
+	// byAddr holds the queued groups involving each address, and lowest
+	// the lowest capped priority among them.
+	byAddr map[basics.Address]map[*queuedTxGroup]struct{}
+	lowest map[basics.Address]txGroupPriority
+}
+
+func makeEvictionQueue() evictionQueue {
+	return evictionQueue{
+		byAddr: make(map[basics.Address]map[*queuedTxGroup]struct{}),
+		lowest: make(map[basics.Address]txGroupPriority),
+	}
+}
+
+// groupAddrs returns the addresses a transaction group involves.
+func groupAddrs(txgroup []transactions.SignedTxn) (addrs []basics.Address) {
+	for _, t := range txgroup {
+		for _, addr := range t.Txn.RelevantAddrs(transactions.SpecialAddresses{}) {
+			if !addr.IsZero() {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return
+}
+
+// cappedPriority returns the priority p of txgroup capped by the priorities
+// of the queued groups involving one of its senders.
+func (q *evictionQueue) cappedPriority(txgroup []transactions.SignedTxn, p txGroupPriority) txGroupPriority {
+	for _, t := range txgroup {
+		if lowest, ok := q.lowest[t.Txn.Sender]; ok && lowest.less(p) {
+			p = lowest
+		}
+	}
+	return p
+}
+
+// push queues txgroup, whose own priority is p, after the queued groups.
+func (q *evictionQueue) push(txgroup []transactions.SignedTxn, p txGroupPriority) *queuedTxGroup {
+	qg := &queuedTxGroup{
+		txgroup:  txgroup,
+		priority: p,
+		capped:   q.cappedPriority(txgroup, p),
+		seq:      q.nextSeq,
+	}
+	q.nextSeq++
+	heap.Push(&q.heap, qg)
+	for _, addr := range groupAddrs(txgroup) {
+		groups, ok := q.byAddr[addr]
+		if !ok {
+			groups = make(map[*queuedTxGroup]struct{})
+			q.byAddr[addr] = groups
+		}
+		groups[qg] = struct{}{}
+		if lowest, ok := q.lowest[addr]; !ok || qg.capped.less(lowest) {
+			q.lowest[addr] = qg.capped
+		}
+	}
+	return qg
+}
+
+// remove takes qg out of the queue.
+func (q *evictionQueue) remove(qg *queuedTxGroup) {
+	heap.Remove(&q.heap, qg.index)
+	for _, addr := range groupAddrs(qg.txgroup) {
+		groups := q.byAddr[addr]
+		delete(groups, qg)
+		if len(groups) == 0 {
+			delete(q.byAddr, addr)
+			delete(q.lowest, addr)
+			continue
+		}
+		if q.lowest[addr].less(qg.capped) {
+			continue
+		}
+		first := true
+		var lowest txGroupPriority
+		for g := range groups {
+			if first || g.capped.less(lowest) {
+				lowest = g.capped
+				first = false
+			}
+		}
+		q.lowest[addr] = lowest
+	}
+}
+
+// lowestPriority returns the lowest priority of the queued groups.
+func (q *evictionQueue) lowestPriority() (txGroupPriority, bool) {
+	if len(q.heap) == 0 {
+		return txGroupPriority{}, false
+	}
+	return q.heap[0].capped, true
+}
+
+// fits returns whether txgroup, whose own priority is p, could be queued
+// while keeping the number of transactions queued, which is count, within
+// limit: that is, whether the groups it would need to evict all have a
+// lower capped priority than its own. The queue is not modified.
+func (q *evictionQueue) fits(txgroup []transactions.SignedTxn, p txGroupPriority, count int, limit int) bool {
+	needed := count + len(txgroup) - limit
+	if needed <= 0 {
+		return true
+	}
+	capped := q.cappedPriority(txgroup, p)
+
+	// The groups of a lower priority form a subtree of the heap at its
+	// root, so only those, and their children, are visited.
+	stack := []int{0}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if i >= len(q.heap) || !q.heap[i].capped.less(capped) {
+			continue
+		}
+		needed -= len(q.heap[i].txgroup)
+		if needed <= 0 {
+			return true
+		}
+		stack = append(stack, 2*i+1, 2*i+2)
+	}
+	return false
+}
+
+// evict removes the lowest priority groups until the number of
+// transactions queued, which is count, is within limit, and returns them.
+// It stops short of evicting keep.
+func (q *evictionQueue) evict(count int, limit int, keep *queuedTxGroup) (evicted []*queuedTxGroup) {
+	for count > limit && len(q.heap) > 0 && q.heap[0] != keep {
+		qg := q.heap[0]
+		q.remove(qg)
+		count -= len(qg.txgroup)
+		evicted = append(evicted, qg)
+	}
+	return
+}
+
+// txGroupHeap implements heap.Interface for an evictionQueue.
+type txGroupHeap []*queuedTxGroup
+
+func (h txGroupHeap) Len() int {
+	return len(h)
+}
+
+func (h txGroupHeap) Less(i, j int) bool {
+	if h[i].capped.less(h[j].capped) {
+		return true
+	}
+	if h[j].capped.less(h[i].capped) {
+		return false
+	}
+	return h[i].seq > h[j].seq
+}
+
+func (h txGroupHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *txGroupHeap) Push(x interface{}) {
+	qg := x.(*queuedTxGroup)
+	qg.index = len(*h)
+	*h = append(*h, qg)
+}
+
+func (h *txGroupHeap) Pop() interface{} {
+	old := *h
+	qg := old[len(old)-1]
+	old[len(old)-1] = nil
+	qg.index = -1
+	*h = old[:len(old)-1]
+	return qg
+}
+
+// byPriority sorts transaction groups from the highest to the lowest priority.
+type byPriority struct {
+	txgroups   [][]transactions.SignedTxn
+	priorities []txGroupPriority
+}
+
+func (bp byPriority) Len() int {
+	return len(bp.txgroups)
+}
+
+func (bp byPriority) Less(i, j int) bool {
+	return bp.priorities[j].less(bp.priorities[i])
+}
+
+func (bp byPriority) Swap(i, j int) {
+	bp.txgroups[i], bp.txgroups[j] = bp.txgroups[j], bp.txgroups[i]
+	bp.priorities[i], bp.priorities[j] = bp.priorities[j], bp.priorities[i]
+}
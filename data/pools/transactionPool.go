@@ -43,10 +43,13 @@ import (
 // groups slated for proposal.  TransactionPool.Remember adds a
 // properly-signed and well-formed transaction group to this queue
 // only if its fees are sufficiently high and its state changes are
-// consistent with the prior transactions in the queue.
+// consistent with the prior transactions in the queue.  When the pool
+// is full, a new group evicts the groups paying the lowest fee per
+// byte, provided that it pays more than they do.
 //
 // TransactionPool.AssembleBlock constructs a valid block for
-// proposal given a deadline.
+// proposal given a deadline.  Blocks are assembled from the pending
+// groups paying the highest fee per byte first.
 type TransactionPool struct {
 	// feePerByte is stored at the beginning of this struct to ensure it has a 64 bit aligned address. This is needed as it's being used
 	// with atomic operations which require 64 bit alignment on arm.
@@ -74,9 +77,13 @@ type TransactionPool struct {
 	assemblyRound   basics.Round
 	assemblyResults poolAsmResults
 
-	// pendingMu protects pendingTxGroups and pendingTxids
+	// pendingMu protects pendingTxGroups, pendingTxQueued, pendingQueue
+	// and pendingTxids.  pendingTxQueued holds the entry of each of the
+	// pendingTxGroups in pendingQueue.
 	pendingMu       deadlock.RWMutex
 	pendingTxGroups [][]transactions.SignedTxn
+	pendingTxQueued []*queuedTxGroup
+	pendingQueue    evictionQueue
 	pendingTxids    map[transactions.Txid]transactions.SignedTxn
 
 	// Calls to remember() add transactions to rememberedTxGroups and
@@ -84,8 +91,9 @@ type TransactionPool struct {
 	// pendingTxGroups and pendingTxids.  This allows us to batch the
 	// changes in OnNewBlock() without preventing a concurrent call
 	// to PendingTxGroups() or Verified().
-	rememberedTxGroups [][]transactions.SignedTxn
-	rememberedTxids    map[transactions.Txid]transactions.SignedTxn
+	rememberedTxGroups     [][]transactions.SignedTxn
+	rememberedTxPriorities []txGroupPriority
+	rememberedTxids        map[transactions.Txid]transactions.SignedTxn
 
 	log logging.Logger
 }
@@ -97,6 +105,7 @@ func MakeTransactionPool(ledger *ledger.Ledger, cfg config.Local, log logging.Lo
 	}
 	pool := TransactionPool{
 		pendingTxids:         make(map[transactions.Txid]transactions.SignedTxn),
+		pendingQueue:         makeEvictionQueue(),
 		rememberedTxids:      make(map[transactions.Txid]transactions.SignedTxn),
 		expiredTxCount:       make(map[basics.Round]int),
 		ledger:               ledger,
@@ -152,12 +161,19 @@ const (
 // i.e. typically it means that we're trying to make a proposal for an older round than what the ledger is currently pointing at.
 var ErrStaleBlockAssemblyRequest = fmt.Errorf("AssembleBlock: requested block assembly specified a round that is older than current transaction pool round")
 
+// errEvictedByHigherFee is the status of transactions evicted from a full pool
+// to make room for transactions paying a higher fee per byte.
+var errEvictedByHigherFee = fmt.Errorf("TransactionPool: evicted by transactions paying a higher fee per byte")
+
 // Reset resets the content of the transaction pool
 func (pool *TransactionPool) Reset() {
 	pool.pendingTxids = make(map[transactions.Txid]transactions.SignedTxn)
 	pool.pendingTxGroups = nil
+	pool.pendingTxQueued = nil
+	pool.pendingQueue = makeEvictionQueue()
 	pool.rememberedTxids = make(map[transactions.Txid]transactions.SignedTxn)
 	pool.rememberedTxGroups = nil
+	pool.rememberedTxPriorities = nil
 	pool.expiredTxCount = make(map[basics.Round]int)
 	pool.numPendingWholeBlocks = 0
 	pool.pendingBlockEvaluator = nil
@@ -219,6 +235,8 @@ func (pool *TransactionPool) rememberCommit(flush bool) {
 
 	if flush {
 		pool.pendingTxGroups = pool.rememberedTxGroups
+		pool.pendingTxQueued = nil
+		pool.pendingQueue = makeEvictionQueue()
 		pool.pendingTxids = pool.rememberedTxids
 		pool.ledger.VerifiedTransactionCache().UpdatePinned(pool.pendingTxids)
 	} else {
@@ -228,11 +246,56 @@ func (pool *TransactionPool) rememberCommit(flush bool) {
 			pool.pendingTxids[txid] = txn
 		}
 	}
+	for i, txgroup := range pool.rememberedTxGroups {
+		pool.pendingTxQueued = append(pool.pendingTxQueued, pool.pendingQueue.push(txgroup, pool.rememberedTxPriorities[i]))
+	}
 
 	pool.rememberedTxGroups = nil
+	pool.rememberedTxPriorities = nil
 	pool.rememberedTxids = make(map[transactions.Txid]transactions.SignedTxn)
 }
 
+// evictLowestPriority drops the pending groups paying the lowest fee per
+// byte until the pending transactions fit in the pool again, sparing the
+// last group, which was just remembered.  The evicted groups were already
+// fed to the pending block evaluator, so it gets recomputed from the
+// remaining groups.  The caller is assumed to be holding pool.mu.
+func (pool *TransactionPool) evictLowestPriority() {
+	pool.pendingMu.Lock()
+	if len(pool.pendingTxids) <= pool.txPoolMaxSize || len(pool.pendingTxQueued) == 0 {
+		pool.pendingMu.Unlock()
+		return
+	}
+
+	keep := pool.pendingTxQueued[len(pool.pendingTxQueued)-1]
+	evicted := pool.pendingQueue.evict(len(pool.pendingTxids), pool.txPoolMaxSize, keep)
+	if len(evicted) == 0 {
+		pool.pendingMu.Unlock()
+		return
+	}
+
+	// PendingTxGroups() hands out the current slice, so build new ones
+	// rather than removing the evicted groups in place.
+	txgroups := make([][]transactions.SignedTxn, 0, len(pool.pendingTxGroups)-len(evicted))
+	queued := make([]*queuedTxGroup, 0, len(pool.pendingTxQueued)-len(evicted))
+	for i, qg := range pool.pendingTxQueued {
+		if qg.index >= 0 {
+			txgroups = append(txgroups, pool.pendingTxGroups[i])
+			queued = append(queued, qg)
+			continue
+		}
+		for _, tx := range qg.txgroup {
+			delete(pool.pendingTxids, tx.ID())
+			pool.statusCache.put(tx, errEvictedByHigherFee.Error())
+		}
+	}
+	pool.pendingTxGroups = txgroups
+	pool.pendingTxQueued = queued
+	pool.pendingMu.Unlock()
+
+	pool.recomputeBlockEvaluator(make(map[transactions.Txid]basics.Round), 0)
+}
+
 // PendingCount returns the number of transactions currently pending in the pool.
 func (pool *TransactionPool) PendingCount() int {
 	pool.pendingMu.RLock()
@@ -251,12 +314,20 @@ func (pool *TransactionPool) pendingCountNoLock() int {
 }
 
 // checkPendingQueueSize tests to see if we can grow the pending group transaction list
-// by adding txCount more transactions. The limits comes from the total number of transactions
+// by adding txgroup. The limits comes from the total number of transactions
 // and not from the total number of transaction groups.
-// As long as we haven't surpassed the size limit, we should be good to go.
-func (pool *TransactionPool) checkPendingQueueSize(txCount int) error {
-	pendingSize := pool.pendingTxIDsCount()
-	if pendingSize+txCount > pool.txPoolMaxSize {
+// As long as we haven't surpassed the size limit, we should be good to go. Past
+// the limit, txgroup is only accepted if it pays a higher fee per byte than the
+// pending groups it would need to evict.
+func (pool *TransactionPool) checkPendingQueueSize(txgroup []transactions.SignedTxn) error {
+	pool.pendingMu.RLock()
+	defer pool.pendingMu.RUnlock()
+
+	if len(pool.pendingTxids)+len(txgroup) <= pool.txPoolMaxSize {
+		return nil
+	}
+
+	if !pool.pendingQueue.fits(txgroup, makeTxGroupPriority(txgroup), len(pool.pendingTxids), pool.txPoolMaxSize) {
 		return fmt.Errorf("TransactionPool.checkPendingQueueSize: transaction pool have reached capacity")
 	}
 	return nil
@@ -268,6 +339,27 @@ func (pool *TransactionPool) FeePerByte() uint64 {
 	return atomic.LoadUint64(&pool.feePerByte)
 }
 
+// CongestionFeePerByte returns the current minimum microalgos per byte a
+// transaction group needs to pay in order to get into the pool.  This is
+// FeePerByte, unless the pool is full, in which case the group also needs
+// to pay more per byte than the lowest priority group it would evict.
+func (pool *TransactionPool) CongestionFeePerByte() uint64 {
+	feePerByte := pool.FeePerByte()
+
+	pool.pendingMu.RLock()
+	defer pool.pendingMu.RUnlock()
+
+	lowest, ok := pool.pendingQueue.lowestPriority()
+	if len(pool.pendingTxids) < pool.txPoolMaxSize || !ok {
+		return feePerByte
+	}
+
+	if lowest.feePerByte()+1 > feePerByte {
+		feePerByte = lowest.feePerByte() + 1
+	}
+	return feePerByte
+}
+
 // computeFeePerByte computes and returns the current minimum microalgos per byte a transaction
 // needs to pay in order to get into the pool. It also updates the atomic counter that holds
 // the current fee per byte
@@ -337,7 +429,7 @@ func (pool *TransactionPool) checkSufficientFee(txgroup []transactions.SignedTxn
 // Test performs basic duplicate detection and well-formedness checks
 // on a transaction group without storing the group.
 func (pool *TransactionPool) Test(txgroup []transactions.SignedTxn) error {
-	if err := pool.checkPendingQueueSize(len(txgroup)); err != nil {
+	if err := pool.checkPendingQueueSize(txgroup); err != nil {
 		return err
 	}
 
@@ -409,6 +501,7 @@ func (pool *TransactionPool) ingest(txgroup []transactions.SignedTxn, params poo
 	}
 
 	pool.rememberedTxGroups = append(pool.rememberedTxGroups, txgroup)
+	pool.rememberedTxPriorities = append(pool.rememberedTxPriorities, makeTxGroupPriority(txgroup))
 	for _, t := range txgroup {
 		pool.rememberedTxids[t.ID()] = t
 	}
@@ -424,7 +517,7 @@ func (pool *TransactionPool) RememberOne(t transactions.SignedTxn) error {
 // Remember stores the provided transaction group.
 // Precondition: Only Remember() properly-signed and well-formed transactions (i.e., ensure t.WellFormed())
 func (pool *TransactionPool) Remember(txgroup []transactions.SignedTxn) error {
-	if err := pool.checkPendingQueueSize(len(txgroup)); err != nil {
+	if err := pool.checkPendingQueueSize(txgroup); err != nil {
 		return err
 	}
 
@@ -437,6 +530,7 @@ func (pool *TransactionPool) Remember(txgroup []transactions.SignedTxn) error {
 	}
 
 	pool.rememberCommit(false)
+	pool.evictLowestPriority()
 	return nil
 }
 
@@ -647,11 +741,19 @@ func (pool *TransactionPool) recomputeBlockEvaluator(committedTxIds map[transact
 		return
 	}
 
-	// Grab the transactions to be played through the new block evaluator
+	// Grab the transactions to be played through the new block evaluator,
+	// from the highest to the lowest fee per byte.  They are copied since
+	// PendingTxGroups() hands out the pending slice itself.
 	pool.pendingMu.RLock()
-	txgroups := pool.pendingTxGroups
+	txgroups := make([][]transactions.SignedTxn, len(pool.pendingTxGroups))
+	copy(txgroups, pool.pendingTxGroups)
+	priorities := make([]txGroupPriority, len(pool.pendingTxQueued))
+	for i, qg := range pool.pendingTxQueued {
+		priorities[i] = qg.priority
+	}
 	pendingCount := pool.pendingCountNoLock()
 	pool.pendingMu.RUnlock()
+	sortByPriority(txgroups, priorities)
 
 	pool.assemblyMu.Lock()
 	pool.assemblyResults = poolAsmResults{
@@ -677,7 +779,7 @@ func (pool *TransactionPool) recomputeBlockEvaluator(committedTxIds map[transact
 
 	firstTxnGrpTime := time.Now()
 
-	// Feed the transactions in order
+	// Feed the transactions in priority order
 	for _, txgroup := range txgroups {
 		if len(txgroup) == 0 {
 			asmStats.InvalidCount++
@@ -21,6 +21,7 @@ import (
 	"math/rand"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -1240,3 +1241,190 @@ func TestTxPoolSizeLimits(t *testing.T) {
 		}
 	}
 }
+
+func TestTxPoolEvictsLowestFee(t *testing.T) {
+	numOfAccounts := 4
+	// Generate accounts
+	secrets := make([]*crypto.SignatureSecrets, numOfAccounts)
+	addresses := make([]basics.Address, numOfAccounts)
+
+	for i := 0; i < numOfAccounts; i++ {
+		secret := keypair()
+		addr := basics.Address(secret.SignatureVerifier)
+		secrets[i] = secret
+		addresses[i] = addr
+	}
+
+	mockLedger := makeMockLedger(t, initAccFixed(addresses, 1<<32))
+	cfg := config.GetDefaultLocal()
+	cfg.TxPoolSize = 2
+	cfg.EnableProcessBlockStats = false
+	transactionPool := MakeTransactionPool(mockLedger, cfg, logging.Base())
+
+	makeTx := func(sender int, fee uint64) transactions.SignedTxn {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addresses[sender],
+				Fee:         basics.MicroAlgos{Raw: fee},
+				FirstValid:  0,
+				LastValid:   basics.Round(proto.MaxTxnLife),
+				GenesisHash: mockLedger.GenesisHash(),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addresses[numOfAccounts-1],
+				Amount:   basics.MicroAlgos{Raw: 1},
+			},
+		}
+		return tx.Sign(secrets[sender])
+	}
+
+	cheap := makeTx(0, proto.MinTxnFee)
+	dear := makeTx(1, proto.MinTxnFee*2)
+	require.NoError(t, transactionPool.RememberOne(cheap))
+	require.NoError(t, transactionPool.RememberOne(dear))
+	require.Equal(t, transactionPool.FeePerByte(), uint64(0))
+	require.Greater(t, transactionPool.CongestionFeePerByte(), proto.MinTxnFee/uint64(cheap.GetEncodedLength()))
+
+	// a transaction paying no more than the cheapest one cannot get in.
+	require.Error(t, transactionPool.RememberOne(makeTx(2, proto.MinTxnFee)))
+	require.Len(t, transactionPool.PendingTxGroups(), 2)
+
+	// a transaction paying more evicts the cheapest one.
+	dearest := makeTx(2, proto.MinTxnFee*3)
+	require.NoError(t, transactionPool.RememberOne(dearest))
+	// the pool is recomputed without it, from the highest fee per byte
+	require.Equal(t, [][]transactions.SignedTxn{{dearest}, {dear}}, transactionPool.PendingTxGroups())
+
+	_, txErr, found := transactionPool.Lookup(cheap.ID())
+	require.True(t, found)
+	require.Equal(t, errEvictedByHigherFee.Error(), txErr)
+
+	// the evicted transaction is no longer proposed either
+	require.NotNil(t, transactionPool.assemblyResults.blk)
+	payset := transactionPool.assemblyResults.blk.Block().Payset
+	require.Len(t, payset, 2)
+	for _, txib := range payset {
+		require.NotEqual(t, cheap.ID(), txib.SignedTxn.ID())
+	}
+}
+
+func TestEvictionQueue(t *testing.T) {
+	addresses := make([]basics.Address, 5)
+	for i := range addresses {
+		crypto.RandBytes(addresses[i][:])
+	}
+	makeGroup := func(sender int, fee uint64) []transactions.SignedTxn {
+		txn := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender: addresses[sender],
+				Fee:    basics.MicroAlgos{Raw: fee},
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addresses[len(addresses)-1],
+			},
+		}
+		return []transactions.SignedTxn{{Txn: txn}}
+	}
+
+	q := makeEvictionQueue()
+	push := func(txgroup []transactions.SignedTxn) *queuedTxGroup {
+		return q.push(txgroup, makeTxGroupPriority(txgroup))
+	}
+	a := push(makeGroup(0, 1000))
+	b := push(makeGroup(1, 5000))
+	c := push(makeGroup(0, 9000)) // may depend on a
+	d := push(makeGroup(2, 3000))
+	require.Equal(t, a.priority, c.capped)
+
+	// a group which pays no more than the lowest one doesn't fit
+	require.True(t, q.fits(makeGroup(3, 500), makeTxGroupPriority(makeGroup(3, 500)), 4, 5))
+	require.False(t, q.fits(makeGroup(3, 500), makeTxGroupPriority(makeGroup(3, 500)), 4, 4))
+	require.True(t, q.fits(makeGroup(3, 2000), makeTxGroupPriority(makeGroup(3, 2000)), 4, 3))
+	require.False(t, q.fits(makeGroup(3, 2000), makeTxGroupPriority(makeGroup(3, 2000)), 4, 1))
+	require.True(t, q.fits(makeGroup(3, 4000), makeTxGroupPriority(makeGroup(3, 4000)), 4, 2))
+	// nor does one from a sender with a lower priority group pending
+	require.False(t, q.fits(makeGroup(0, 4000), makeTxGroupPriority(makeGroup(0, 4000)), 4, 3))
+
+	// a group is evicted before the earlier group it may depend on
+	require.Equal(t, []*queuedTxGroup{c, a}, q.evict(4, 2, nil))
+	require.Equal(t, -1, a.index)
+	require.Equal(t, -1, c.index)
+	lowest, ok := q.lowestPriority()
+	require.True(t, ok)
+	require.Equal(t, d.capped, lowest)
+
+	// the evicted groups no longer cap the priority of later ones
+	e := push(makeGroup(0, 9000))
+	require.Equal(t, e.priority, e.capped)
+
+	require.Equal(t, []*queuedTxGroup{d}, q.evict(3, 2, e))
+	require.Empty(t, q.evict(2, 0, b))
+	require.Equal(t, []*queuedTxGroup{b, e}, q.evict(2, 0, nil))
+	_, ok = q.lowestPriority()
+	require.False(t, ok)
+	require.Empty(t, q.byAddr)
+	require.Empty(t, q.lowest)
+}
+
+func TestTxPoolPriorityOrder(t *testing.T) {
+	numOfAccounts := 4
+	// Generate accounts
+	secrets := make([]*crypto.SignatureSecrets, numOfAccounts)
+	addresses := make([]basics.Address, numOfAccounts)
+
+	for i := 0; i < numOfAccounts; i++ {
+		secret := keypair()
+		addr := basics.Address(secret.SignatureVerifier)
+		secrets[i] = secret
+		addresses[i] = addr
+	}
+
+	mockLedger := makeMockLedger(t, initAccFixed(addresses, 1<<32))
+	cfg := config.GetDefaultLocal()
+	cfg.TxPoolSize = testPoolSize
+	cfg.EnableProcessBlockStats = false
+	transactionPool := MakeTransactionPool(mockLedger, cfg, logging.Base())
+
+	makeTx := func(sender int, fee uint64, note byte) transactions.SignedTxn {
+		tx := transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addresses[sender],
+				Fee:         basics.MicroAlgos{Raw: fee},
+				FirstValid:  0,
+				LastValid:   basics.Round(proto.MaxTxnLife),
+				Note:        []byte{note},
+				GenesisHash: mockLedger.GenesisHash(),
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addresses[numOfAccounts-1],
+				Amount:   basics.MicroAlgos{Raw: 1},
+			},
+		}
+		return tx.Sign(secrets[sender])
+	}
+
+	low := makeTx(0, proto.MinTxnFee, 0)
+	// high follows low from the same sender, so it cannot move ahead of it.
+	high := makeTx(0, proto.MinTxnFee*3, 1)
+	mid := makeTx(1, proto.MinTxnFee*2, 2)
+	highest := makeTx(2, proto.MinTxnFee*4, 3)
+	for _, tx := range []transactions.SignedTxn{low, high, mid, highest} {
+		require.NoError(t, transactionPool.RememberOne(tx))
+	}
+
+	transactionPool.mu.Lock()
+	transactionPool.recomputeBlockEvaluator(make(map[transactions.Txid]basics.Round), 0)
+	transactionPool.mu.Unlock()
+	require.Equal(t, [][]transactions.SignedTxn{{highest}, {mid}, {low}, {high}}, transactionPool.PendingTxGroups())
+
+	blk, err := transactionPool.AssembleBlock(1, time.Now().Add(config.ProposalAssemblyTime))
+	require.NoError(t, err)
+	payset := blk.Block().Payset
+	require.Len(t, payset, 4)
+	for i, tx := range []transactions.SignedTxn{highest, mid, low, high} {
+		require.Equal(t, tx.Txn.Note, payset[i].Txn.Note)
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// programInst is an instruction of a decoded program
+type programInst struct {
+	pc   int
+	next int
+	spec *OpSpec
+}
+
+// decodedProgram is a program split into instructions, with the leaders of
+// its basic blocks and the entry points of its subroutines marked
+type decodedProgram struct {
+	program     []byte
+	version     uint64
+	insts       []programInst
+	index       map[int]int // pc to position in insts
+	leaders     map[int]bool
+	subroutines map[int]bool
+}
+
+// decodeProgram decodes a program using the same checks as Check, so that
+// the analyses built on it can trust the immediates of every instruction
+func decodeProgram(program []byte, mode runMode) (*decodedProgram, error) {
+	version, vlen := binary.Uvarint(program)
+	if vlen <= 0 {
+		return nil, errors.New("invalid version")
+	}
+	if version > EvalMaxVersion {
+		return nil, fmt.Errorf("program version %d greater than max supported version %d", version, EvalMaxVersion)
+	}
+
+	d := &decodedProgram{
+		program:     program,
+		version:     version,
+		index:       make(map[int]int),
+		leaders:     map[int]bool{vlen: true},
+		subroutines: make(map[int]bool),
+	}
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	var cx evalContext
+	cx.version = version
+	cx.pc = vlen
+	cx.EvalParams = EvalParams{Proto: &proto, runModeFlags: mode}
+	cx.program = program
+	cx.branchTargets = make(map[int]bool)
+	cx.instructionStarts = make(map[int]bool)
+
+	for cx.pc < len(cx.program) {
+		pc := cx.pc
+		_, err := cx.checkStep()
+		if err != nil {
+			return nil, fmt.Errorf("pc=%3d %w", cx.pc, err)
+		}
+		inst := programInst{pc: pc, next: cx.pc, spec: &opsByOpcode[version][program[pc]]}
+		d.index[pc] = len(d.insts)
+		d.insts = append(d.insts, inst)
+
+		switch inst.spec.Name {
+		case "callsub":
+			d.subroutines[d.target(inst)] = true
+			fallthrough
+		case "bnz", "bz", "b":
+			d.leaders[d.target(inst)] = true
+			d.leaders[inst.next] = true
+		case "return", "err", "retsub":
+			d.leaders[inst.next] = true
+		}
+	}
+	return d, nil
+}
+
+// target returns the destination of a branch or callsub. The program has
+// been checked, so the offset is known to be good.
+func (d *decodedProgram) target(inst programInst) int {
+	offset := int16(uint16(d.program[inst.pc+1])<<8 | uint16(d.program[inst.pc+2]))
+	return inst.pc + 3 + int(offset)
+}
+
+// successors returns the pcs that can run after inst within the same
+// subroutine. A callsub continues after the call; a pc at the end of the
+// program means the program ends there.
+func (d *decodedProgram) successors(inst programInst) []int {
+	switch inst.spec.Name {
+	case "b":
+		return []int{d.target(inst)}
+	case "bz", "bnz":
+		return []int{inst.next, d.target(inst)}
+	case "return", "err", "retsub":
+		return nil
+	}
+	return []int{inst.next}
+}
+
+// EntryPointCost is the worst case of running a program from one of its
+// entry points: the start of the program, or a subroutine.
+type EntryPointCost struct {
+	// PC is the first instruction of the entry point
+	PC int
+
+	// Subroutine is set for entry points reached by callsub
+	Subroutine bool
+
+	// Cost is the largest opcode cost of any path from PC, including the
+	// subroutines it calls. It is only meaningful if CostUnbounded is not set.
+	Cost int
+
+	// CostUnbounded is set if a loop or recursion can repeat without limit.
+	// LoopPC is then the branch or callsub that repeats.
+	CostUnbounded bool
+	LoopPC        int
+
+	// MaxStack is the most values the stack can hold beyond what it held at
+	// PC. It is only meaningful if StackUnbounded is not set.
+	MaxStack int
+
+	// StackUnbounded is set if a loop or recursion can keep growing the
+	// stack until it exceeds MaxStackDepth.
+	StackUnbounded bool
+}
+
+// AnalyzeCost computes the worst case opcode cost and stack depth of a
+// program, using the opcode costs of its version. The program itself is
+// returned first, followed by its subroutines in the order they appear.
+func AnalyzeCost(program []byte) ([]EntryPointCost, error) {
+	d, err := decodeProgram(program, modeAny)
+	if err != nil {
+		return nil, err
+	}
+	a := costAnalyzer{
+		decodedProgram: d,
+		done:           make(map[int]*entryAnalysis),
+		active:         make(map[int]bool),
+	}
+
+	entries := []int{len(program)}
+	if len(d.insts) > 0 {
+		entries[0] = d.insts[0].pc
+	}
+	subroutines := make([]int, 0, len(d.subroutines))
+	for pc := range d.subroutines {
+		subroutines = append(subroutines, pc)
+	}
+	sort.Ints(subroutines)
+	entries = append(entries, subroutines...)
+
+	costs := make([]EntryPointCost, len(entries))
+	for i, pc := range entries {
+		costs[i] = a.entry(pc).EntryPointCost
+		costs[i].Subroutine = i > 0
+	}
+	return costs, nil
+}
+
+// entryAnalysis adds what a caller needs to know about a subroutine
+type entryAnalysis struct {
+	EntryPointCost
+
+	// returns is set if the subroutine can reach a retsub, and net is then
+	// the largest change it makes to the stack by doing so
+	returns bool
+	net     int
+}
+
+type costAnalyzer struct {
+	*decodedProgram
+	done   map[int]*entryAnalysis
+	active map[int]bool
+}
+
+// entry analyzes the entry point at pc, or returns nil if it is already
+// being analyzed because of a recursive call
+func (a *costAnalyzer) entry(pc int) *entryAnalysis {
+	if e, ok := a.done[pc]; ok {
+		return e
+	}
+	if a.active[pc] {
+		return nil
+	}
+	a.active[pc] = true
+	e := &entryAnalysis{EntryPointCost: EntryPointCost{PC: pc}}
+	a.cost(e)
+	a.stack(e)
+	delete(a.active, pc)
+	a.done[pc] = e
+	return e
+}
+
+func (e *entryAnalysis) loop(pc int) {
+	if !e.CostUnbounded {
+		e.CostUnbounded = true
+		e.LoopPC = pc
+	}
+}
+
+// cost finds the most expensive path from the entry point. A path that
+// comes back to an instruction it has already run is a loop, and makes
+// the cost unbounded.
+func (a *costAnalyzer) cost(e *entryAnalysis) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[int]int)
+	costs := make(map[int]int)
+
+	var visit func(pc int) int
+	visit = func(pc int) int {
+		if pc >= len(a.program) {
+			return 0
+		}
+		if state[pc] == visited {
+			return costs[pc]
+		}
+		state[pc] = visiting
+		inst := a.insts[a.index[pc]]
+		cost := inst.spec.Details.Cost
+		if inst.spec.Name == "callsub" {
+			callee := a.entry(a.target(inst))
+			switch {
+			case callee == nil:
+				e.loop(inst.pc)
+			case callee.CostUnbounded:
+				e.loop(callee.LoopPC)
+			default:
+				cost += callee.Cost
+			}
+			if callee != nil && !callee.returns {
+				state[pc] = visited
+				costs[pc] = cost
+				return cost
+			}
+		}
+		most := 0
+		for _, next := range a.successors(inst) {
+			if state[next] == visiting {
+				e.loop(inst.pc)
+				continue
+			}
+			if c := visit(next); c > most {
+				most = c
+			}
+		}
+		state[pc] = visited
+		costs[pc] = cost + most
+		return costs[pc]
+	}
+	e.Cost = visit(e.PC)
+}
+
+// stack finds the deepest the stack can get from the entry point, by
+// pushing the greatest depth seen at each instruction forward until
+// nothing changes. A loop that grows the stack never settles, so it is
+// stopped once the depth passes MaxStackDepth.
+func (a *costAnalyzer) stack(e *entryAnalysis) {
+	depths := map[int]int{e.PC: 0}
+	work := []int{e.PC}
+	for len(work) > 0 && !e.StackUnbounded {
+		pc := work[len(work)-1]
+		work = work[:len(work)-1]
+		if pc >= len(a.program) {
+			continue
+		}
+		inst := a.insts[a.index[pc]]
+		depth := depths[pc]
+		after := depth - len(inst.spec.Args) + len(inst.spec.Returns)
+		peak := after
+		continues := true
+		switch inst.spec.Name {
+		case "callsub":
+			callee := a.entry(a.target(inst))
+			if callee == nil || callee.StackUnbounded {
+				e.StackUnbounded = true
+				continue
+			}
+			peak = depth + callee.MaxStack
+			after = depth + callee.net
+			continues = callee.returns
+		case "retsub":
+			if !e.returns || depth > e.net {
+				e.net = depth
+			}
+			e.returns = true
+		}
+		if depth > peak {
+			peak = depth
+		}
+		if peak > e.MaxStack {
+			e.MaxStack = peak
+		}
+		if e.MaxStack > MaxStackDepth {
+			e.StackUnbounded = true
+			continue
+		}
+		if !continues {
+			continue
+		}
+		for _, next := range a.successors(inst) {
+			if old, seen := depths[next]; !seen || after > old {
+				depths[next] = after
+				work = append(work, next)
+			}
+		}
+	}
+}
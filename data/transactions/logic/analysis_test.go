@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func analyzeCost(t *testing.T, source string, version uint64) []EntryPointCost {
+	t.Helper()
+	ops := testProg(t, source, version)
+	costs, err := AnalyzeCost(ops.Program)
+	require.NoError(t, err)
+	return costs
+}
+
+func TestAnalyzeCostStraightLine(t *testing.T) {
+	t.Parallel()
+
+	costs := analyzeCost(t, "int 1; int 2; +; pop; byte 0x01; sha256; len", AssemblerMaxVersion)
+	require.Len(t, costs, 1)
+	require.Equal(t, 1+1+1+1+1+35+1, costs[0].Cost)
+	require.Equal(t, 2, costs[0].MaxStack)
+	require.False(t, costs[0].CostUnbounded)
+	require.False(t, costs[0].StackUnbounded)
+
+	// the cost table of the program's version is used, and v1 has no pushbytes
+	costs = analyzeCost(t, "byte 0x01; sha256; len", 1)
+	require.Equal(t, 1+1+7+1, costs[0].Cost)
+}
+
+func TestAnalyzeCostBranches(t *testing.T) {
+	t.Parallel()
+
+	// the more expensive side of a branch is the worst case
+	costs := analyzeCost(t, `txn Fee
+bz cheap
+byte 0x01; keccak256; keccak256; pop
+int 1; return
+cheap:
+int 1; int 2; int 3; pop; pop`, AssemblerMaxVersion)
+	// intcblock, then the expensive side
+	require.Equal(t, 1+1+1+1+130+130+1+1+1, costs[0].Cost)
+	require.Equal(t, 3, costs[0].MaxStack)
+}
+
+func TestAnalyzeCostSubroutines(t *testing.T) {
+	t.Parallel()
+
+	ops := testProg(t, `int 1
+callsub double
+callsub double
+return
+double:
+dup; +
+retsub`, AssemblerMaxVersion)
+	costs, err := AnalyzeCost(ops.Program)
+	require.NoError(t, err)
+	require.Len(t, costs, 2)
+
+	main, double := costs[0], costs[1]
+	require.False(t, main.Subroutine)
+	require.True(t, double.Subroutine)
+	require.Equal(t, 1+1+1, double.Cost)
+	require.Equal(t, 1, double.MaxStack)
+	require.Equal(t, 1+2*(1+double.Cost)+1, main.Cost)
+	require.Equal(t, 2, main.MaxStack)
+	require.Equal(t, ops.OffsetToLine[double.PC], 5)
+}
+
+func TestAnalyzeCostLoops(t *testing.T) {
+	t.Parallel()
+
+	ops := testProg(t, `int 10
+loop:
+int 1; -
+dup
+bnz loop
+pop; int 1`, AssemblerMaxVersion)
+	costs, err := AnalyzeCost(ops.Program)
+	require.NoError(t, err)
+	require.True(t, costs[0].CostUnbounded)
+	require.Equal(t, 5, ops.OffsetToLine[costs[0].LoopPC])
+	// the loop does not grow the stack
+	require.False(t, costs[0].StackUnbounded)
+	require.Equal(t, 2, costs[0].MaxStack)
+
+	costs = analyzeCost(t, "loop: int 1; b loop", AssemblerMaxVersion)
+	require.True(t, costs[0].CostUnbounded)
+	require.True(t, costs[0].StackUnbounded)
+
+	costs = analyzeCost(t, "int 1; callsub rec; return; rec: dup; bz done; int 1; -; callsub rec; done: retsub", AssemblerMaxVersion)
+	require.Len(t, costs, 2)
+	require.True(t, costs[0].CostUnbounded)
+	require.True(t, costs[1].CostUnbounded)
+	require.Equal(t, costs[1].LoopPC, costs[0].LoopPC)
+}
+
+func TestAnalyzeCostErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := AnalyzeCost(nil)
+	require.EqualError(t, err, "invalid version")
+	_, err = AnalyzeCost([]byte{0x04, 0xff})
+	require.Error(t, err)
+}
@@ -45,6 +45,7 @@ type Writer interface {
 }
 
 type labelReference struct {
+	sourceFile int
 	sourceLine int
 
 	// position of the opcode start that refers to the label
@@ -232,18 +233,58 @@ type OpStream struct {
 	// Keep a stack of the types of what we would push and pop to typecheck a program
 	typeStack []StackType
 
-	// current sourceLine during assembly
-	sourceLine int
+	// current source file and sourceLine during assembly, and the column
+	// of the instruction being assembled on it. programLine is the line of
+	// the program itself being assembled, which is the #include directive
+	// or the macro use when assembling an included file or a macro body.
+	sourceFile   int
+	sourceLine   int
+	sourceColumn int
+	programLine  int
+
+	// Files names the source files of the program. The first is the
+	// program itself, the rest were read by #include.
+	Files []string
+
+	// text of each of Files, and the files being included, innermost
+	// last, to detect include cycles
+	fileTexts []string
+	including []int
+	include   IncludeFunc
+
+	// #define and #slot names, with the fields they expand to, and the
+	// scratch slots that have been named
+	defines map[string][]string
+	slots   map[uint64]string
+
+	// #macro definitions, the macros being expanded, to detect recursion,
+	// and how many expansions there have been, to keep labels unique
+	macros     map[string]*macro
+	expanding  map[string]bool
+	expansions int
 
 	// map label string to position within pending buffer
 	labels map[string]int
 
+	// map label string to where it was defined in the source
+	labelSources map[string]sourcePosition
+
 	// track references in order to patch in jump offsets
 	labelReferences []labelReference
 
-	// map opcode offsets to source line
+	// map opcode offsets to source line of the program. The instructions
+	// of included files and macro bodies map to the line of the #include
+	// directive or of the macro use, see SourcePosition for their own line.
 	OffsetToLine map[int]int
 
+	// map opcode offsets to their position in Files
+	offsetToSource map[int]sourcePosition
+
+	// the source text that first produced each int and byte constant,
+	// used to name constant block entries in source maps
+	intcNames  map[uint64]string
+	bytecNames map[string]string
+
 	HasStatefulOps bool
 }
 
@@ -257,27 +298,58 @@ func (ops *OpStream) GetVersion() uint64 {
 
 // createLabel inserts a label reference to point to the next
 // instruction, reporting an error for a duplicate.
-func (ops *OpStream) createLabel(label string) {
+func (ops *OpStream) createLabel(label string, column int) {
 	if ops.labels == nil {
 		ops.labels = make(map[string]int)
+		ops.labelSources = make(map[string]sourcePosition)
 	}
 	if _, ok := ops.labels[label]; ok {
 		ops.errorf("duplicate label %#v", label)
 	}
+	if _, ok := ops.defines[label]; ok {
+		ops.errorf("label %#v has the name of a #define", label)
+	}
 	ops.labels[label] = ops.pending.Len()
+	ops.labelSources[label] = sourcePosition{ops.sourceFile, ops.sourceLine - 1, column}
 }
 
-// RecordSourceLine adds an entry to pc to line mapping
+// RecordSourceLine adds an entry to pc to line mapping. Included files and
+// macro bodies are mapped to the line of the program that uses them, see
+// SourcePosition for their own lines.
 func (ops *OpStream) RecordSourceLine() {
 	if ops.OffsetToLine == nil {
 		ops.OffsetToLine = make(map[int]int)
+		ops.offsetToSource = make(map[int]sourcePosition)
+	}
+	ops.OffsetToLine[ops.pending.Len()] = ops.programLine - 1
+	ops.offsetToSource[ops.pending.Len()] = sourcePosition{ops.sourceFile, ops.sourceLine - 1, ops.sourceColumn}
+}
+
+// nameIntConstant records name as the source text of the int constant
+// val, unless an earlier pseudo-op already named it
+func (ops *OpStream) nameIntConstant(val uint64, name string) {
+	if ops.intcNames == nil {
+		ops.intcNames = make(map[uint64]string)
+	}
+	if _, ok := ops.intcNames[val]; !ok {
+		ops.intcNames[val] = name
+	}
+}
+
+// nameByteConstant records name as the source text of the byte constant
+// val, unless an earlier pseudo-op already named it
+func (ops *OpStream) nameByteConstant(val []byte, name string) {
+	if ops.bytecNames == nil {
+		ops.bytecNames = make(map[string]string)
+	}
+	if _, ok := ops.bytecNames[string(val)]; !ok {
+		ops.bytecNames[string(val)] = name
 	}
-	ops.OffsetToLine[ops.pending.Len()] = ops.sourceLine - 1
 }
 
 // ReferToLabel records an opcode label refence to resolve later
 func (ops *OpStream) ReferToLabel(pc int, label string) {
-	ops.labelReferences = append(ops.labelReferences, labelReference{ops.sourceLine, pc, label})
+	ops.labelReferences = append(ops.labelReferences, labelReference{ops.sourceFile, ops.sourceLine, pc, label})
 }
 
 // returns allows opcodes like `txn` to be specific about their return
@@ -407,18 +479,21 @@ func assembleInt(ops *OpStream, spec *OpSpec, args []string) error {
 	// check friendly TypeEnum constants
 	te, isTypeEnum := txnTypeConstToUint64[args[0]]
 	if isTypeEnum {
+		ops.nameIntConstant(te, args[0])
 		ops.Uint(te)
 		return nil
 	}
 	// check raw transaction type strings
 	tt, isTypeStr := txnTypeIndexes[args[0]]
 	if isTypeStr {
+		ops.nameIntConstant(tt, args[0])
 		ops.Uint(tt)
 		return nil
 	}
 	// check OnCompetion constants
 	oc, isOCStr := onCompletionConstToUint64[args[0]]
 	if isOCStr {
+		ops.nameIntConstant(oc, args[0])
 		ops.Uint(oc)
 		return nil
 	}
@@ -426,6 +501,7 @@ func assembleInt(ops *OpStream, spec *OpSpec, args []string) error {
 	if err != nil {
 		return ops.error(err)
 	}
+	ops.nameIntConstant(val, args[0])
 	ops.Uint(val)
 	return nil
 }
@@ -639,6 +715,7 @@ func assembleByte(ops *OpStream, spec *OpSpec, args []string) error {
 	if err != nil {
 		return ops.error(err)
 	}
+	ops.nameByteConstant(val, strings.Join(args, " "))
 	ops.ByteLiteral(val)
 	return nil
 }
@@ -704,6 +781,7 @@ func assembleAddr(ops *OpStream, spec *OpSpec, args []string) error {
 	if err != nil {
 		return ops.error(err)
 	}
+	ops.nameByteConstant(addr[:], "addr "+args[0])
 	ops.ByteLiteral(addr[:])
 	return nil
 }
@@ -959,6 +1037,47 @@ func assembleGtxnsa(ops *OpStream, spec *OpSpec, args []string) error {
 	return nil
 }
 
+func assembleItxn(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return ops.error("itxn expects one argument")
+	}
+	fs, ok := txnFieldSpecByName[args[0]]
+	if !ok {
+		return ops.errorf("itxn unknown field: %#v", args[0])
+	}
+	_, ok = txnaFieldSpecByField[fs.field]
+	if ok {
+		return ops.errorf("found array field %#v in itxn op", args[0])
+	}
+	if fs.version > ops.Version {
+		return ops.errorf("field %#v available in version %d. Missed #pragma version?", args[0], fs.version)
+	}
+	ops.pending.WriteByte(spec.Opcode)
+	ops.pending.WriteByte(uint8(fs.field))
+	ops.returns(TxnFieldTypes[fs.field])
+	return nil
+}
+
+func assembleItxnField(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return ops.error("itxn_field expects one argument")
+	}
+	fs, ok := txnFieldSpecByName[args[0]]
+	if !ok {
+		return ops.errorf("itxn_field unknown field: %#v", args[0])
+	}
+	version, ok := innerTxnFieldSpecByField[fs.field]
+	if !ok {
+		return ops.errorf("itxn_field %#v is not allowed", args[0])
+	}
+	if version > ops.Version {
+		return ops.errorf("itxn_field %#v available in version %d. Missed #pragma version?", args[0], version)
+	}
+	ops.pending.WriteByte(spec.Opcode)
+	ops.pending.WriteByte(uint8(fs.field))
+	return nil
+}
+
 func assembleGlobal(ops *OpStream, spec *OpSpec, args []string) error {
 	if len(args) != 1 {
 		return ops.error("global expects one argument")
@@ -994,6 +1113,19 @@ func assembleAssetHolding(ops *OpStream, spec *OpSpec, args []string) error {
 	return nil
 }
 
+func assembleEcdsa(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return ops.errorf("%s expects one argument", spec.Name)
+	}
+	val, ok := ecdsaCurves[args[0]]
+	if !ok {
+		return ops.errorf("%s unknown curve: %#v", spec.Name, args[0])
+	}
+	ops.pending.WriteByte(spec.Opcode)
+	ops.pending.WriteByte(uint8(val))
+	return nil
+}
+
 func assembleAssetParams(ops *OpStream, spec *OpSpec, args []string) error {
 	if len(args) != 1 {
 		return ops.error("asset_params_get expects one argument")
@@ -1008,6 +1140,20 @@ func assembleAssetParams(ops *OpStream, spec *OpSpec, args []string) error {
 	return nil
 }
 
+func assembleAppParams(ops *OpStream, spec *OpSpec, args []string) error {
+	if len(args) != 1 {
+		return ops.error("app_params_get expects one argument")
+	}
+	val, ok := appParamsFields[args[0]]
+	if !ok {
+		return ops.errorf("app_params_get unknown arg: %#v", args[0])
+	}
+	ops.pending.WriteByte(spec.Opcode)
+	ops.pending.WriteByte(uint8(val))
+	ops.returns(AppParamsFieldTypes[val], StackUint64)
+	return nil
+}
+
 type assembleFunc func(*OpStream, *OpSpec, []string) error
 
 // Basic assembly. Any extra bytes of opcode are encoded as byte immediates.
@@ -1039,11 +1185,16 @@ var keywords = map[string]OpSpec{
 }
 
 type lineError struct {
+	// File is set when the error is in an included file
+	File string
 	Line int
 	Err  error
 }
 
 func (le *lineError) Error() string {
+	if le.File != "" {
+		return fmt.Sprintf("%s:%d: %s", le.File, le.Line, le.Err.Error())
+	}
 	return fmt.Sprintf("%d: %s", le.Line, le.Err.Error())
 }
 
@@ -1187,67 +1338,17 @@ func (ops *OpStream) assemble(fin io.Reader) error {
 		return ops.errorf("Can not assemble version %d", ops.Version)
 	}
 	scanner := bufio.NewScanner(fin)
-	ops.sourceLine = 0
+	var lines []string
 	for scanner.Scan() {
-		ops.sourceLine++
-		line := scanner.Text()
-		if len(line) == 0 {
-			ops.trace("%d: 0 line\n", ops.sourceLine)
-			continue
-		}
-		if strings.HasPrefix(line, "//") {
-			ops.trace("%d: // line\n", ops.sourceLine)
-			continue
-		}
-		if strings.HasPrefix(line, "#pragma") {
-			ops.trace("%d: #pragma line\n", ops.sourceLine)
-			ops.pragma(line)
-			continue
-		}
-		fields := fieldsFromLine(line)
-		if len(fields) == 0 {
-			ops.trace("%d: no fields\n", ops.sourceLine)
-			continue
-		}
-		// we're about to begin processing opcodes, so fix the Version
-		if ops.Version == assemblerNoVersion {
-			ops.Version = AssemblerDefaultVersion
-		}
-		opstring := fields[0]
-
-		if opstring[len(opstring)-1] == ':' {
-			ops.createLabel(opstring[:len(opstring)-1])
-			fields = fields[1:]
-			if len(fields) == 0 {
-				// There was a label, not need to ops.trace this
-				continue
-			}
-			opstring = fields[0]
-		}
-
-		spec, ok := OpsByName[ops.Version][opstring]
-		if !ok {
-			spec, ok = keywords[opstring]
-		}
-		if ok {
-			ops.trace("%3d: %s\t", ops.sourceLine, opstring)
-			ops.RecordSourceLine()
-			if spec.Modes == runModeApplication {
-				ops.HasStatefulOps = true
-			}
-			ops.checkArgs(spec)
-			spec.asm(ops, &spec, fields[1:])
-			ops.trace("\n")
-			continue
-		}
-		// unknown opcode, let's report a good error if version problem
-		spec, ok = OpsByName[AssemblerMaxVersion][opstring]
-		if ok {
-			ops.errorf("%s opcode was introduced in TEAL v%d", opstring, spec.Version)
-		} else {
-			ops.errorf("unknown opcode: %s", opstring)
-		}
+		lines = append(lines, scanner.Text())
+	}
+	if len(ops.Files) == 0 {
+		ops.Files = []string{""}
 	}
+	ops.fileTexts = []string{strings.Join(lines, "\n")}
+	ops.including = []int{0}
+	ops.assembleSource(0, lines)
+	ops.sourceFile = 0
 
 	// backward compatibility: do not allow jumps behind last instruction in TEAL v1
 	if ops.Version <= 1 {
@@ -1277,6 +1378,91 @@ func (ops *OpStream) assemble(fin io.Reader) error {
 	return nil
 }
 
+// assembleLine assembles one line of source. local maps the parameters
+// and labels of a macro being expanded to the fields they stand for.
+func (ops *OpStream) assembleLine(line string, local map[string][]string) {
+	if len(line) == 0 {
+		ops.trace("%d: 0 line\n", ops.sourceLine)
+		return
+	}
+	if strings.HasPrefix(line, "//") {
+		ops.trace("%d: // line\n", ops.sourceLine)
+		return
+	}
+	if strings.HasPrefix(line, "#pragma") {
+		ops.trace("%d: #pragma line\n", ops.sourceLine)
+		ops.pragma(line)
+		return
+	}
+	if strings.HasPrefix(strings.TrimLeft(line, " \t"), "#") {
+		ops.trace("%d: directive line\n", ops.sourceLine)
+		ops.directive(line, local)
+		return
+	}
+	fields := fieldsFromLine(line)
+	if len(fields) == 0 {
+		ops.trace("%d: no fields\n", ops.sourceLine)
+		return
+	}
+	// we're about to begin processing opcodes, so fix the Version
+	if ops.Version == assemblerNoVersion {
+		ops.Version = AssemblerDefaultVersion
+	}
+	opstring := fields[0]
+	column := strings.Index(line, opstring)
+
+	if opstring[len(opstring)-1] == ':' {
+		label := opstring[:len(opstring)-1]
+		if unique, ok := local[label]; ok {
+			label = unique[0]
+		}
+		ops.createLabel(label, column)
+		fields = fields[1:]
+		if len(fields) == 0 {
+			// There was a label, not need to ops.trace this
+			return
+		}
+		afterLabel := column + len(opstring)
+		opstring = fields[0]
+		column = afterLabel + strings.Index(line[afterLabel:], opstring)
+	}
+	ops.sourceColumn = column
+
+	fields = ops.expand(fields, local)
+	if len(fields) == 0 {
+		return
+	}
+	opstring = fields[0]
+
+	if m, ok := ops.macros[opstring]; ok {
+		ops.expandMacro(m, fields[1:])
+		return
+	}
+
+	spec, ok := OpsByName[ops.Version][opstring]
+	if !ok {
+		spec, ok = keywords[opstring]
+	}
+	if ok {
+		ops.trace("%3d: %s\t", ops.sourceLine, opstring)
+		ops.RecordSourceLine()
+		if spec.Modes == runModeApplication {
+			ops.HasStatefulOps = true
+		}
+		ops.checkArgs(spec)
+		spec.asm(ops, &spec, fields[1:])
+		ops.trace("\n")
+		return
+	}
+	// unknown opcode, let's report a good error if version problem
+	spec, ok = OpsByName[AssemblerMaxVersion][opstring]
+	if ok {
+		ops.errorf("%s opcode was introduced in TEAL v%d", opstring, spec.Version)
+	} else {
+		ops.errorf("unknown opcode: %s", opstring)
+	}
+}
+
 func (ops *OpStream) pragma(line string) error {
 	fields := strings.Split(line, " ")
 	if fields[0] != "#pragma" {
@@ -1321,11 +1507,11 @@ func (ops *OpStream) pragma(line string) error {
 }
 
 func (ops *OpStream) resolveLabels() {
-	saved := ops.sourceLine
+	saved, savedFile := ops.sourceLine, ops.sourceFile
 	raw := ops.pending.Bytes()
 	reported := make(map[string]bool)
 	for _, lr := range ops.labelReferences {
-		ops.sourceLine = lr.sourceLine
+		ops.sourceFile, ops.sourceLine = lr.sourceFile, lr.sourceLine
 		dest, ok := ops.labels[lr.label]
 		if !ok {
 			if !reported[lr.label] {
@@ -1349,7 +1535,7 @@ func (ops *OpStream) resolveLabels() {
 		raw[lr.position+2] = uint8(jump & 0x0ff)
 	}
 	ops.pending = *bytes.NewBuffer(raw)
-	ops.sourceLine = saved
+	ops.sourceLine, ops.sourceFile = saved, savedFile
 }
 
 // AssemblerDefaultVersion what version of code do we emit by default
@@ -1586,6 +1772,16 @@ func (ops *OpStream) optimizeConstants(refs []constReference, constBlock []inter
 			}
 		}
 		ops.OffsetToLine = fixedOffsetsToLine
+
+		fixedOffsetsToSource := make(map[int]sourcePosition, len(ops.offsetToSource))
+		for pos, source := range ops.offsetToSource {
+			if pos > position {
+				fixedOffsetsToSource[pos+positionDelta] = source
+			} else {
+				fixedOffsetsToSource[pos] = source
+			}
+		}
+		ops.offsetToSource = fixedOffsetsToSource
 	}
 
 	ops.pending = *bytes.NewBuffer(raw)
@@ -1648,6 +1844,17 @@ func (ops *OpStream) prependCBlocks() []byte {
 	}
 	ops.OffsetToLine = newOffsetToLine
 
+	newOffsetToSource := make(map[int]sourcePosition, len(ops.offsetToSource))
+	for o, p := range ops.offsetToSource {
+		newOffsetToSource[o+pbl] = p
+	}
+	ops.offsetToSource = newOffsetToSource
+
+	// and the labels, which are only needed for source maps from here on
+	for label := range ops.labels {
+		ops.labels[label] += pbl
+	}
+
 	return out
 }
 
@@ -1665,6 +1872,7 @@ func (ops *OpStream) lineError(line int, problem interface{}) error {
 	default:
 		le = &lineError{Line: line, Err: fmt.Errorf("%#v", p)}
 	}
+	le.File = ops.includedFileName()
 	ops.Errors = append(ops.Errors, le)
 	return le
 }
@@ -1687,6 +1895,7 @@ func (ops *OpStream) warn(problem interface{}) error {
 	default:
 		le = &lineError{Line: ops.sourceLine, Err: fmt.Errorf("%#v", p)}
 	}
+	le.File = ops.includedFileName()
 	warning := fmt.Errorf("warning: %w", le)
 	ops.Warnings = append(ops.Warnings, warning)
 	return warning
@@ -1730,6 +1939,16 @@ func AssembleStringWithVersion(text string, version uint64) (*OpStream, error) {
 	return &ops, err
 }
 
+// AssembleStringWithIncludes assembles a program like AssembleString, and
+// reads the files named by its #include directives with include. name is
+// the name of the program's own file, which includes are relative to.
+func AssembleStringWithIncludes(name, text string, include IncludeFunc) (*OpStream, error) {
+	sr := strings.NewReader(text)
+	ops := OpStream{Version: assemblerNoVersion, Files: []string{name}, include: include}
+	err := ops.assemble(sr)
+	return &ops, err
+}
+
 type disassembleState struct {
 	program []byte
 	pc      int
@@ -2179,6 +2398,20 @@ func disAssetHolding(dis *disassembleState, spec *OpSpec) (string, error) {
 	return fmt.Sprintf("asset_holding_get %s", AssetHoldingFieldNames[arg]), nil
 }
 
+func disEcdsa(dis *disassembleState, spec *OpSpec) (string, error) {
+	lastIdx := dis.pc + 1
+	if len(dis.program) <= lastIdx {
+		missing := lastIdx - len(dis.program) + 1
+		return "", fmt.Errorf("unexpected %s opcode end: missing %d bytes", spec.Name, missing)
+	}
+	dis.nextpc = dis.pc + 2
+	arg := dis.program[dis.pc+1]
+	if int(arg) >= len(EcdsaCurveNames) {
+		return "", fmt.Errorf("invalid curve arg index %d at pc=%d", arg, dis.pc)
+	}
+	return fmt.Sprintf("%s %s", spec.Name, EcdsaCurveNames[arg]), nil
+}
+
 func disAssetParams(dis *disassembleState, spec *OpSpec) (string, error) {
 	lastIdx := dis.pc + 1
 	if len(dis.program) <= lastIdx {
@@ -2193,6 +2426,20 @@ func disAssetParams(dis *disassembleState, spec *OpSpec) (string, error) {
 	return fmt.Sprintf("asset_params_get %s", AssetParamsFieldNames[arg]), nil
 }
 
+func disAppParams(dis *disassembleState, spec *OpSpec) (string, error) {
+	lastIdx := dis.pc + 1
+	if len(dis.program) <= lastIdx {
+		missing := lastIdx - len(dis.program) + 1
+		return "", fmt.Errorf("unexpected %s opcode end: missing %d bytes", spec.Name, missing)
+	}
+	dis.nextpc = dis.pc + 2
+	arg := dis.program[dis.pc+1]
+	if int(arg) >= len(AppParamsFieldNames) {
+		return "", fmt.Errorf("invalid app params arg index %d at pc=%d", arg, dis.pc)
+	}
+	return fmt.Sprintf("app_params_get %s", AppParamsFieldNames[arg]), nil
+}
+
 type disInfo struct {
 	pcOffset       []PCOffset
 	hasStatefulOps bool
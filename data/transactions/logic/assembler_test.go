@@ -19,6 +19,7 @@ package logic
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -291,11 +292,35 @@ gaid 0
 gaids
 `
 
+const v5Nonsense = `
+log
+itxn_begin
+itxn_field Sender
+itxn_submit
+itxn Sender
+box_create
+box_extract
+box_replace
+box_del
+box_len
+box_get
+box_put
+box_resize
+ecdsa_verify Secp256k1
+ecdsa_pk_decompress Secp256k1
+ecdsa_pk_recover Secp256k1
+app_params_get AppClearStateProgram
+gretdata 0 0
+gretdatas 0
+retdata_put
+`
+
 var nonsense = map[uint64]string{
 	1: v1Nonsense,
 	2: v1Nonsense + v2Nonsense,
 	3: v1Nonsense + v2Nonsense + v3Nonsense,
 	4: v1Nonsense + v2Nonsense + v3Nonsense + v4Nonsense,
+	5: v1Nonsense + v2Nonsense + v3Nonsense + v4Nonsense + v5Nonsense,
 }
 
 var compiled = map[uint64]string{
@@ -303,6 +328,7 @@ var compiled = map[uint64]string{
 	2: "022008b7a60cf8acd19181cf959a12f8acd19181cf951af8acd19181cf15f8acd191810f01020026050212340c68656c6c6f20776f726c6421208dae2087fbba51304eb02b91f656948397a7946390e8cb70fc9ea4d95f92251d024242047465737400320032013202320328292929292a0431003101310231043105310731083109310a310b310c310d310e310f3111311231133114311533000033000133000233000433000533000733000833000933000a33000b33000c33000d33000e33000f3300113300123300133300143300152d2e0102222324252104082209240a220b230c240d250e230f23102311231223132314181b1c2b171615400003290349483403350222231d4a484848482a50512a63222352410003420000432105602105612105270463484821052b62482b642b65484821052b2106662b21056721072b682b692107210570004848210771004848361c0037001a0031183119311b311d311e311f3120210721051e312131223123312431253126312731283129312a312b312c312d312e312f",
 	3: "032008b7a60cf8acd19181cf959a12f8acd19181cf951af8acd19181cf15f8acd191810f01020026050212340c68656c6c6f20776f726c6421208dae2087fbba51304eb02b91f656948397a7946390e8cb70fc9ea4d95f92251d024242047465737400320032013202320328292929292a0431003101310231043105310731083109310a310b310c310d310e310f3111311231133114311533000033000133000233000433000533000733000833000933000a33000b33000c33000d33000e33000f3300113300123300133300143300152d2e0102222324252104082209240a220b230c240d250e230f23102311231223132314181b1c2b171615400003290349483403350222231d4a484848482a50512a63222352410003420000432105602105612105270463484821052b62482b642b65484821052b2106662b21056721072b682b692107210570004848210771004848361c0037001a0031183119311b311d311e311f3120210721051e312131223123312431253126312731283129312a312b312c312d312e312f4478222105531421055427042106552105082106564c4d4b02210538212106391c0081e80780046a6f686e",
 	4: "042004010200b7a60c26040242420c68656c6c6f20776f726c6421208dae2087fbba51304eb02b91f656948397a7946390e8cb70fc9ea4d95f92251d047465737400320032013202320380021234292929292a0431003101310231043105310731083109310a310b310c310d310e310f3111311231133114311533000033000133000233000433000533000733000833000933000a33000b33000c33000d33000e33000f3300113300123300133300143300152d2e01022581f8acd19181cf959a1281f8acd19181cf951a81f8acd19181cf1581f8acd191810f082209240a220b230c240d250e230f23102311231223132314181b1c28171615400003290349483403350222231d4a484848482a50512a632223524100034200004322602261222b634848222862482864286548482228236628226724286828692422700048482471004848361c0037001a0031183119311b311d311e311f312024221e312131223123312431253126312731283129312a312b312c312d312e312f44782522531422542b2355220823564c4d4b0222382123391c0081e80780046a6f686e2281d00f24231f880003420001892223902291922394239593a0a1a2a3a4a5a6a7a8a9aaabacadae23af3a00003b003c003d",
+	5: "052004010200b7a60c26040242420c68656c6c6f20776f726c6421208dae2087fbba51304eb02b91f656948397a7946390e8cb70fc9ea4d95f92251d047465737400320032013202320380021234292929292a0431003101310231043105310731083109310a310b310c310d310e310f3111311231133114311533000033000133000233000433000533000733000833000933000a33000b33000c33000d33000e33000f3300113300123300133300143300152d2e01022581f8acd19181cf959a1281f8acd19181cf951a81f8acd19181cf1581f8acd191810f082209240a220b230c240d250e230f23102311231223132314181b1c28171615400003290349483403350222231d4a484848482a50512a632223524100034200004322602261222b634848222862482864286548482228236628226724286828692422700048482471004848361c0037001a0031183119311b311d311e311f312024221e312131223123312431253126312731283129312a312b312c312d312e312f44782522531422542b2355220823564c4d4b0222382123391c0081e80780046a6f686e2281d00f24231f880003420001892223902291922394239593a0a1a2a3a4a5a6a7a8a9aaabacadae23af3a00003b003c003db0b1b200b3b400b9babbbcbdbebfc005000600070072013e00003f00b5",
 }
 
 func pseudoOp(opcode string) bool {
@@ -1184,6 +1210,7 @@ global Round
 global LatestTimestamp
 global CurrentApplicationID
 global CreatorAddress
+global CurrentApplicationAddress
 txn Sender
 txn Fee
 bnz label1
@@ -1244,6 +1271,8 @@ txn LocalNumUint
 txn LocalNumByteSlice
 gtxn 12 Fee
 txn ExtraProgramPages
+itxn NumLogs
+itxn LastLog
 `, AssemblerMaxVersion)
 	for _, globalField := range GlobalFieldNames {
 		if !strings.Contains(text, globalField) {
@@ -1725,6 +1754,81 @@ err
 	require.Equal(t, 2, line)
 }
 
+func TestAssembleSourceMap(t *testing.T) {
+	t.Parallel()
+	source := `int 1
+int 1
+byte "abc"
+byte "abc"
+==
+pop
+  b done
+err
+done: int 1
+end:
+`
+	ops := testProg(t, source, AssemblerMaxVersion)
+	sm := ops.SourceMap("test.teal", source)
+	require.Equal(t, SourceMapVersion, sm.Version)
+	require.Equal(t, []string{"test.teal"}, sm.Sources)
+	require.Equal(t, []string{source}, sm.SourcesContent)
+	require.Equal(t, []string{"done", "end"}, sm.Names)
+	require.Equal(t, []string{"1"}, sm.IntcNames)
+	require.Equal(t, []string{`"abc"`}, sm.BytecNames)
+	// one line per pc, plus one for the label at the end of the program
+	require.Equal(t, len(ops.Program)+1, len(strings.Split(sm.Mappings, ";")))
+
+	// a map survives a round trip through json
+	encoded, err := json.Marshal(sm)
+	require.NoError(t, err)
+	var decoded SourceMap
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, sm, decoded)
+
+	locations, err := decoded.Locations()
+	require.NoError(t, err)
+	for pc, line := range ops.OffsetToLine {
+		require.Equal(t, line, locations[pc].Line, "pc %d", pc)
+	}
+	for pc, loc := range locations {
+		if len(loc.Labels) == 0 {
+			_, ok := ops.OffsetToLine[pc]
+			require.True(t, ok, "pc %d", pc)
+		}
+	}
+
+	bpc := len(ops.Program) - 5 // b done, err, intc_0
+	require.Equal(t, SourceLocation{Line: 6, Column: 2}, locations[bpc])
+	require.Equal(t, SourceLocation{Line: 8, Column: 6, Labels: []string{"done"}}, locations[bpc+4])
+	require.Equal(t, SourceLocation{Line: 9, Column: 0, Labels: []string{"end"}}, locations[len(ops.Program)])
+
+	sm.Version = 2
+	_, err = sm.Locations()
+	require.Error(t, err)
+}
+
+func TestSourceMapVLQ(t *testing.T) {
+	t.Parallel()
+	for _, v := range []int{0, 1, -1, 15, -15, 16, -16, 1000, -123456, 1 << 40} {
+		fields, err := vlqDecode(vlqEncode(v))
+		require.NoError(t, err)
+		require.Equal(t, []int{v}, fields)
+	}
+	require.Equal(t, "A", vlqEncode(0))
+	require.Equal(t, "C", vlqEncode(1))
+	require.Equal(t, "D", vlqEncode(-1))
+	require.Equal(t, "gB", vlqEncode(16))
+
+	fields, err := vlqDecode("AACAgB")
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 0, 1, 0, 16}, fields)
+
+	_, err = vlqDecode("g")
+	require.Error(t, err)
+	_, err = vlqDecode("A!")
+	require.Error(t, err)
+}
+
 func TestHasStatefulOps(t *testing.T) {
 	t.Parallel()
 	source := "int 1"
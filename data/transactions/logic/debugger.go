@@ -75,8 +75,14 @@ type DebugState struct {
 	Scratch []basics.TealValue `codec:"scratch"`
 	Error   string             `codec:"error"`
 
+	// opcode cost incurred so far, and the budget it is limited by. For
+	// application calls with cost pooling, the budget is what remained of
+	// the group's pooled budget when the program started.
+	Cost   int `codec:"cost"`
+	Budget int `codec:"budget"`
+
 	// global/local state changes are updated every step. Stateful TEAL only.
-	basics.EvalDelta
+	transactions.EvalDelta
 }
 
 // GetProgramID returns program or execution ID that is string representation of sha256 checksum.
@@ -216,6 +222,8 @@ func (cx *evalContext) refreshDebugState() *DebugState {
 
 	ds.Stack = stack
 	ds.Scratch = scratch
+	ds.Cost = cx.cost
+	ds.Budget = cx.budget()
 
 	if (cx.runModeFlags & runModeApplication) != 0 {
 		var err error
@@ -226,6 +234,8 @@ func (cx *evalContext) refreshDebugState() *DebugState {
 			vd := tv.ToValueDelta()
 			ds.EvalDelta.GlobalDelta = basics.StateDelta{"error": vd}
 		}
+		ds.EvalDelta.InnerTxns = cx.innerTxns
+		ds.EvalDelta.Logs = cx.logs
 	}
 
 	return ds
@@ -0,0 +1,451 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IncludeFunc reads the file named by an #include directive. from is the
+// name of the file that has the directive. It returns the name the file
+// is known by, which identifies it in Files and error messages, and its
+// text.
+type IncludeFunc func(name, from string) (resolved string, text string, err error)
+
+// ReadIncludeFile is an IncludeFunc for files on disk. Relative names are
+// relative to the directory of the including file.
+func ReadIncludeFile(name, from string) (string, string, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	return path, string(text), nil
+}
+
+// macro is a #macro definition. The labels defined in its body are
+// renamed in each expansion, so that a macro can be used more than once.
+type macro struct {
+	name   string
+	params []string
+	labels []string
+	body   []macroLine
+
+	// where the definition starts
+	file int
+	line int
+}
+
+// macroLine is a line of a macro body, and where it was written
+type macroLine struct {
+	text string
+	file int
+	line int
+}
+
+// SourcePosition returns the name of the file, from Files, that the
+// instruction at pc was assembled from, and its zero-based line in that
+// file. Unlike OffsetToLine, it points into included files and macro
+// bodies.
+func (ops *OpStream) SourcePosition(pc int) (file string, line int) {
+	pos, ok := ops.offsetToSource[pc]
+	if !ok || pos.file >= len(ops.Files) {
+		return "", 0
+	}
+	return ops.Files[pos.file], pos.line
+}
+
+// includedFileName is the name of the file being assembled, if it is an
+// included file rather than the program itself
+func (ops *OpStream) includedFileName() string {
+	if ops.sourceFile == 0 || ops.sourceFile >= len(ops.Files) {
+		return ""
+	}
+	return ops.Files[ops.sourceFile]
+}
+
+// directiveName returns the directive at the start of line, if any
+func directiveName(line string) string {
+	if !strings.HasPrefix(strings.TrimLeft(line, " \t"), "#") {
+		return ""
+	}
+	fields := fieldsFromLine(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// assembleSource assembles the lines of the program, or of a file it
+// includes, collecting #macro definitions as it goes
+func (ops *OpStream) assembleSource(file int, lines []string) {
+	var m *macro
+	for i, line := range lines {
+		ops.sourceFile = file
+		ops.sourceLine = i + 1
+		if file == 0 {
+			ops.programLine = i + 1
+		}
+		directive := directiveName(line)
+		if m != nil {
+			switch directive {
+			case "#endmacro":
+				ops.defineMacro(m)
+				m = nil
+			case "#macro":
+				ops.error("#macro can not be nested")
+			default:
+				m.body = append(m.body, macroLine{line, file, i + 1})
+			}
+			continue
+		}
+		if directive == "#macro" {
+			m = ops.startMacro(line)
+			continue
+		}
+		ops.assembleLine(line, nil)
+	}
+	if m != nil {
+		ops.sourceFile, ops.sourceLine = m.file, m.line
+		ops.errorf("#macro %s has no #endmacro", m.name)
+	}
+}
+
+// directive handles the lines starting with # other than #pragma and the
+// #macro definitions that assembleSource collects
+func (ops *OpStream) directive(line string, local map[string][]string) {
+	fields := fieldsFromLine(line)
+	switch fields[0] {
+	case "#define":
+		ops.define(fields[1:], local)
+	case "#slot":
+		ops.slot(fields[1:])
+	case "#include":
+		ops.includeFile(fields[1:])
+	case "#endmacro":
+		ops.error("#endmacro without #macro")
+	default:
+		ops.errorf("unknown directive: %s", fields[0])
+	}
+}
+
+// isFieldName tells whether name is the name of an immediate argument of
+// an opcode, such as a txn field, or of a named integer constant
+func isFieldName(name string) bool {
+	if _, ok := txnFieldSpecByName[name]; ok {
+		return true
+	}
+	if _, ok := globalFieldSpecByName[name]; ok {
+		return true
+	}
+	for _, fields := range []map[string]uint64{assetHoldingFields, assetParamsFields, appParamsFields, ecdsaCurves, txnTypeIndexes, txnTypeConstToUint64, onCompletionConstToUint64} {
+		if _, ok := fields[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkName reports an error if name can not be given to a #define,
+// #slot or #macro. The names of #define and #slot replace any field they
+// appear in, so they can not be field names or labels either.
+func (ops *OpStream) checkName(kind, name string) bool {
+	for i, c := range name {
+		if c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || (i > 0 && '0' <= c && c <= '9') {
+			continue
+		}
+		ops.errorf("%s name %#v is not valid", kind, name)
+		return false
+	}
+	if _, ok := OpsByName[AssemblerMaxVersion][name]; ok {
+		ops.errorf("%s %s would hide an opcode", kind, name)
+		return false
+	}
+	if _, ok := keywords[name]; ok {
+		ops.errorf("%s %s would hide an opcode", kind, name)
+		return false
+	}
+	if kind != "#macro" {
+		if isFieldName(name) {
+			ops.errorf("%s %s would hide a field", kind, name)
+			return false
+		}
+		if _, ok := ops.labels[name]; ok {
+			ops.errorf("%s %s would hide a label", kind, name)
+			return false
+		}
+	}
+	if _, ok := ops.defines[name]; ok {
+		ops.errorf("%s is already defined", name)
+		return false
+	}
+	if _, ok := ops.macros[name]; ok {
+		ops.errorf("%s is already defined", name)
+		return false
+	}
+	return true
+}
+
+// expand replaces fields that are macro parameters, macro labels or
+// #define names with the fields they stand for
+func (ops *OpStream) expand(fields []string, local map[string][]string) []string {
+	if len(local) == 0 && len(ops.defines) == 0 {
+		return fields
+	}
+	expanded := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if sub, ok := local[field]; ok {
+			expanded = append(expanded, sub...)
+		} else if sub, ok := ops.defines[field]; ok {
+			expanded = append(expanded, sub...)
+		} else {
+			expanded = append(expanded, field)
+		}
+	}
+	return expanded
+}
+
+// define handles `#define NAME value...`. The value is expanded when it is
+// defined, so it may use earlier definitions but not later ones.
+func (ops *OpStream) define(args []string, local map[string][]string) {
+	if len(args) < 2 {
+		ops.error("#define needs a name and a value")
+		return
+	}
+	name := args[0]
+	if !ops.checkName("#define", name) {
+		return
+	}
+	if ops.defines == nil {
+		ops.defines = make(map[string][]string)
+	}
+	ops.defines[name] = ops.expand(args[1:], local)
+}
+
+// slot handles `#slot NAME [N]`, naming scratch slot N, or the lowest slot
+// that has not been named yet
+func (ops *OpStream) slot(args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		ops.error("#slot needs a name and an optional slot number")
+		return
+	}
+	name := args[0]
+	if !ops.checkName("#slot", name) {
+		return
+	}
+	if ops.slots == nil {
+		ops.slots = make(map[uint64]string)
+	}
+	numSlots := uint64(len(scratchSpace{}))
+	var idx uint64
+	if len(args) == 2 {
+		var err error
+		idx, err = strconv.ParseUint(args[1], 0, 64)
+		if err != nil || idx >= numSlots {
+			ops.errorf("#slot %s: invalid scratch slot %#v", name, args[1])
+			return
+		}
+		if other, ok := ops.slots[idx]; ok {
+			ops.errorf("#slot %s: scratch slot %d is already named %s", name, idx, other)
+			return
+		}
+	} else {
+		for idx = 0; idx < numSlots; idx++ {
+			if _, ok := ops.slots[idx]; !ok {
+				break
+			}
+		}
+		if idx == numSlots {
+			ops.errorf("#slot %s: all scratch slots are named", name)
+			return
+		}
+	}
+	ops.slots[idx] = name
+	if ops.defines == nil {
+		ops.defines = make(map[string][]string)
+	}
+	ops.defines[name] = []string{strconv.FormatUint(idx, 10)}
+}
+
+// includeFile handles `#include "name"`, assembling the named file in place
+func (ops *OpStream) includeFile(args []string) {
+	if len(args) != 1 {
+		ops.error("#include needs a file name")
+		return
+	}
+	name := args[0]
+	if strings.HasPrefix(name, "\"") {
+		unquoted, err := parseStringLiteral(name)
+		if err != nil {
+			ops.errorf("#include %s: %v", name, err)
+			return
+		}
+		name = string(unquoted)
+	}
+	if ops.include == nil {
+		ops.errorf("#include %s: includes are not available here", name)
+		return
+	}
+	resolved, text, err := ops.include(name, ops.Files[ops.sourceFile])
+	if err != nil {
+		ops.errorf("#include %s: %v", name, err)
+		return
+	}
+
+	file := -1
+	for i, f := range ops.Files {
+		if f == resolved {
+			file = i
+			break
+		}
+	}
+	for i, f := range ops.including {
+		if f == file {
+			chain := make([]string, 0, len(ops.including)-i+1)
+			for _, f := range ops.including[i:] {
+				chain = append(chain, ops.Files[f])
+			}
+			chain = append(chain, resolved)
+			ops.errorf("#include cycle: %s", strings.Join(chain, " -> "))
+			return
+		}
+	}
+	if file == -1 {
+		file = len(ops.Files)
+		ops.Files = append(ops.Files, resolved)
+		ops.fileTexts = append(ops.fileTexts, text)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	savedFile, savedLine := ops.sourceFile, ops.sourceLine
+	ops.including = append(ops.including, file)
+	ops.assembleSource(file, lines)
+	ops.including = ops.including[:len(ops.including)-1]
+	ops.sourceFile, ops.sourceLine = savedFile, savedLine
+}
+
+// startMacro handles `#macro NAME [PARAM...]`, returning the macro whose
+// body lines follow. An invalid macro still collects its body, so that the
+// body is not assembled, but it is never defined.
+func (ops *OpStream) startMacro(line string) *macro {
+	fields := fieldsFromLine(line)
+	m := &macro{file: ops.sourceFile, line: ops.sourceLine}
+	if len(fields) < 2 {
+		ops.error("#macro needs a name")
+		return m
+	}
+	if !ops.checkName("#macro", fields[1]) {
+		return m
+	}
+	seen := make(map[string]bool)
+	for _, param := range fields[2:] {
+		if seen[param] {
+			ops.errorf("#macro %s: duplicate parameter %s", fields[1], param)
+			return m
+		}
+		seen[param] = true
+		_, isOp := OpsByName[AssemblerMaxVersion][param]
+		_, isKeyword := keywords[param]
+		if isOp || isKeyword {
+			ops.errorf("#macro %s: parameter %s would hide an opcode", fields[1], param)
+			return m
+		}
+		if isFieldName(param) {
+			ops.errorf("#macro %s: parameter %s would hide a field", fields[1], param)
+			return m
+		}
+	}
+	m.name = fields[1]
+	m.params = fields[2:]
+	return m
+}
+
+// defineMacro completes a macro definition at its #endmacro
+func (ops *OpStream) defineMacro(m *macro) {
+	if m.name == "" {
+		return
+	}
+	params := make(map[string]bool, len(m.params))
+	for _, param := range m.params {
+		params[param] = true
+	}
+	for _, bl := range m.body {
+		if strings.HasPrefix(bl.text, "#") {
+			continue
+		}
+		fields := fieldsFromLine(bl.text)
+		if len(fields) == 0 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+		label := fields[0][:len(fields[0])-1]
+		if params[label] {
+			ops.sourceFile, ops.sourceLine = bl.file, bl.line
+			ops.errorf("#macro %s: label %s has the name of a parameter", m.name, label)
+			return
+		}
+		m.labels = append(m.labels, label)
+	}
+	if ops.macros == nil {
+		ops.macros = make(map[string]*macro)
+	}
+	ops.macros[m.name] = m
+}
+
+// expandMacro assembles the body of m in place of a line that uses it
+func (ops *OpStream) expandMacro(m *macro, args []string) {
+	if len(args) != len(m.params) {
+		ops.errorf("macro %s expects %d arguments", m.name, len(m.params))
+		return
+	}
+	if ops.expanding[m.name] {
+		ops.errorf("macro %s expands itself", m.name)
+		return
+	}
+	if ops.expanding == nil {
+		ops.expanding = make(map[string]bool)
+	}
+	ops.expanding[m.name] = true
+	ops.expansions++
+
+	local := make(map[string][]string, len(m.params)+len(m.labels))
+	for _, label := range m.labels {
+		local[label] = []string{fmt.Sprintf("%s.%s.%d", m.name, label, ops.expansions)}
+	}
+	for i, param := range m.params {
+		local[param] = []string{args[i]}
+	}
+
+	savedFile, savedLine := ops.sourceFile, ops.sourceLine
+	for _, bl := range m.body {
+		ops.sourceFile, ops.sourceLine = bl.file, bl.line
+		ops.assembleLine(bl.text, local)
+	}
+	ops.sourceFile, ops.sourceLine = savedFile, savedLine
+	delete(ops.expanding, m.name)
+}
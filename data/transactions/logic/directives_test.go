@@ -0,0 +1,235 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssembleDefine(t *testing.T) {
+	t.Parallel()
+
+	ops := testProg(t, `#define FEE 1000
+#define MIN_FEE FEE
+#define FEE_FIELD txn Fee
+FEE_FIELD
+int MIN_FEE
+>=
+int FEE
+pop`, AssemblerMaxVersion)
+	expected := testProg(t, "txn Fee; int 1000; >=; int 1000; pop", AssemblerMaxVersion)
+	require.Equal(t, expected.Program, ops.Program)
+
+	testProg(t, "#define FEE 1; #define FEE 2", AssemblerMaxVersion, expect{2, "FEE is already defined"})
+	testProg(t, "#define FEE", AssemblerMaxVersion, expect{1, "#define needs a name and a value"})
+	testProg(t, "#define pop 1", AssemblerMaxVersion, expect{1, "#define pop would hide an opcode"})
+	testProg(t, "#define Fee 1", AssemblerMaxVersion, expect{1, "#define Fee would hide a field"})
+	testProg(t, "#define pay 1", AssemblerMaxVersion, expect{1, "#define pay would hide a field"})
+	testProg(t, "done:; #define done 1", AssemblerMaxVersion, expect{2, "#define done would hide a label"})
+	testProg(t, "#define done 1; done:", AssemblerMaxVersion, expect{2, "label \"done\" has the name of a #define"})
+	testProg(t, "#define 1a 1", AssemblerMaxVersion, expect{1, "#define name \"1a\" is not valid"})
+	testProg(t, "#defined A 1", AssemblerMaxVersion, expect{1, "unknown directive: #defined"})
+}
+
+func TestAssembleMacro(t *testing.T) {
+	t.Parallel()
+
+	source := `#macro clamp max
+  dup
+  int max
+  >
+  bz ok
+  pop
+  int max
+ok:
+#endmacro
+txn Fee
+clamp 1000
+txn FirstValid
+clamp 10
++
+`
+	ops := testProg(t, source, 4)
+	expected := testProg(t, `txn Fee; dup; int 1000; >; bz ok1; pop; int 1000; ok1:
+txn FirstValid; dup; int 10; >; bz ok2; pop; int 10; ok2:
++`, 4)
+	require.Equal(t, expected.Program, ops.Program)
+
+	// expanded instructions point at the macro use, and their source
+	// position at the macro body
+	lines := make(map[int]bool)
+	bodyLines := make(map[int]bool)
+	for pc, line := range ops.OffsetToLine {
+		lines[line] = true
+		_, bodyLine := ops.SourcePosition(pc)
+		bodyLines[bodyLine] = true
+	}
+	require.Equal(t, map[int]bool{9: true, 10: true, 11: true, 12: true, 13: true}, lines)
+	require.Equal(t, map[int]bool{1: true, 2: true, 3: true, 4: true, 5: true, 6: true, 9: true, 11: true, 13: true}, bodyLines)
+
+	// labels are unique to each expansion
+	require.Contains(t, ops.labels, "clamp.ok.1")
+	require.Contains(t, ops.labels, "clamp.ok.2")
+	require.NotContains(t, ops.labels, "ok")
+
+	// errors in the body are reported on the body's line
+	testProg(t, "#macro m; intt 1; #endmacro; m", AssemblerMaxVersion, expect{2, "unknown opcode: intt"})
+
+	testProg(t, "#macro m a; int a; #endmacro; m", AssemblerMaxVersion, expect{4, "macro m expects 1 arguments"})
+	testProg(t, "#macro m; m; #endmacro; m", AssemblerMaxVersion, expect{2, "macro m expands itself"})
+	testProg(t, "#macro m; int 1", AssemblerMaxVersion, expect{1, "#macro m has no #endmacro"})
+	testProg(t, "#macro m; #macro n; #endmacro", AssemblerMaxVersion, expect{2, "#macro can not be nested"})
+	testProg(t, "int 1; #endmacro", AssemblerMaxVersion, expect{2, "#endmacro without #macro"})
+	testProg(t, "#macro m a a; #endmacro", AssemblerMaxVersion, expect{1, "#macro m: duplicate parameter a"})
+	testProg(t, "#macro m int; #endmacro", AssemblerMaxVersion, expect{1, "#macro m: parameter int would hide an opcode"})
+	testProg(t, "#macro m Sender; #endmacro", AssemblerMaxVersion, expect{1, "#macro m: parameter Sender would hide a field"})
+	testProg(t, "#macro m a; a: int 1; #endmacro", AssemblerMaxVersion, expect{2, "#macro m: label a has the name of a parameter"})
+	testProg(t, "#define m 1; #macro m; #endmacro", AssemblerMaxVersion, expect{2, "m is already defined"})
+}
+
+func TestAssembleSlot(t *testing.T) {
+	t.Parallel()
+
+	ops := testProg(t, `#slot counter
+#slot total 10
+#slot other
+int 1
+store counter
+load counter
+store total
+load total
+store other`, AssemblerMaxVersion)
+	expected := testProg(t, "int 1; store 0; load 0; store 10; load 10; store 1", AssemblerMaxVersion)
+	require.Equal(t, expected.Program, ops.Program)
+
+	testProg(t, "#slot x 3; #slot y 3", AssemblerMaxVersion, expect{2, "#slot y: scratch slot 3 is already named x"})
+	testProg(t, "#slot a 256", AssemblerMaxVersion, expect{1, "#slot a: invalid scratch slot \"256\""})
+	testProg(t, "#slot a 1 2", AssemblerMaxVersion, expect{1, "#slot needs a name and an optional slot number"})
+	testProg(t, "#slot a; #define a 1", AssemblerMaxVersion, expect{2, "a is already defined"})
+	testProg(t, "#slot Amount", AssemblerMaxVersion, expect{1, "#slot Amount would hide a field"})
+
+	var source string
+	for i := 0; i < 256; i++ {
+		source += fmt.Sprintf("#slot s%d\n", i)
+	}
+	testProg(t, source+"#slot full", AssemblerMaxVersion, expect{257, "#slot full: all scratch slots are named"})
+}
+
+func testIncludes(files map[string]string) IncludeFunc {
+	return func(name, from string) (string, string, error) {
+		text, ok := files[name]
+		if !ok {
+			return "", "", fmt.Errorf("no such file")
+		}
+		return name, text, nil
+	}
+}
+
+func TestAssembleInclude(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"consts.teal": "#define FEE 1000\n#slot total",
+		"macros.teal": "#include \"consts.teal\"\n#macro check_fee\ntxn Fee\nint FEE\n<=\n#endmacro\n",
+		"body.teal":   "check_fee\nint 1\nstore total\n",
+		"bad.teal":    "int 1\n\nintt 2\n",
+		"a.teal":      "#include \"b.teal\"",
+		"b.teal":      "#include \"a.teal\"",
+	}
+	source := `#pragma version 4
+#include "macros.teal"
+#include "body.teal"
+assert
+`
+	ops, err := AssembleStringWithIncludes("main.teal", source, testIncludes(files))
+	require.NoError(t, err)
+	expected := testProg(t, "txn Fee; int 1000; <=; int 1; store 0; assert", 4)
+	require.Equal(t, expected.Program, ops.Program)
+	require.Equal(t, []string{"main.teal", "macros.teal", "consts.teal", "body.teal"}, ops.Files)
+
+	// the expanded macro is positioned in macros.teal, the rest in body.teal
+	// and the program, while the lines of the program point at the
+	// #include directives
+	positions := make(map[string]int)
+	lines := make(map[int]bool)
+	for pc, line := range ops.OffsetToLine {
+		file, sourceLine := ops.SourcePosition(pc)
+		positions[fmt.Sprintf("%s:%d", file, sourceLine)] = pc
+		lines[line] = true
+	}
+	require.Len(t, positions, 6)
+	require.Contains(t, positions, "macros.teal:2")
+	require.Contains(t, positions, "body.teal:2")
+	require.Contains(t, positions, "main.teal:3")
+	require.Equal(t, map[int]bool{2: true, 3: true}, lines)
+
+	sm := ops.SourceMap("main.teal", source)
+	require.Equal(t, ops.Files, sm.Sources)
+	require.Equal(t, []string{source, files["macros.teal"], files["consts.teal"], files["body.teal"]}, sm.SourcesContent)
+	locations, err := sm.Locations()
+	require.NoError(t, err)
+	for pc := range ops.OffsetToLine {
+		file, line := ops.SourcePosition(pc)
+		require.Equal(t, line, locations[pc].Line)
+		require.Equal(t, file, sm.Sources[locations[pc].Source])
+	}
+
+	// errors in included files name the file
+	ops, err = AssembleStringWithIncludes("main.teal", "int 1\n#include \"bad.teal\"\n", testIncludes(files))
+	require.Error(t, err)
+	require.Len(t, ops.Errors, 1)
+	require.Equal(t, "bad.teal:3: unknown opcode: intt", ops.Errors[0].Error())
+
+	ops, err = AssembleStringWithIncludes("main.teal", "#include \"a.teal\"", testIncludes(files))
+	require.Error(t, err)
+	require.Equal(t, "b.teal:1: #include cycle: a.teal -> b.teal -> a.teal", ops.Errors[0].Error())
+
+	ops, err = AssembleStringWithIncludes("main.teal", "int 1\n#include missing.teal", testIncludes(files))
+	require.Error(t, err)
+	require.Equal(t, "2: #include missing.teal: no such file", ops.Errors[0].Error())
+
+	testProg(t, "#include \"macros.teal\"", AssemblerMaxVersion, expect{1, "#include macros.teal: includes are not available here"})
+	testProg(t, "#include", AssemblerMaxVersion, expect{1, "#include needs a file name"})
+}
+
+func TestReadIncludeFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "teal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "lib"), 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "one.teal"), []byte("#include \"two.teal\"\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "lib", "two.teal"), []byte("int 1\n"), 0600))
+
+	main := filepath.Join(dir, "main.teal")
+	ops, err := AssembleStringWithIncludes(main, "#include \"lib/one.teal\"\n", ReadIncludeFile)
+	require.NoError(t, err)
+	require.Equal(t, []string{main, filepath.Join(dir, "lib", "one.teal"), filepath.Join(dir, "lib", "two.teal")}, ops.Files)
+	expected := testProg(t, "int 1", assemblerNoVersion)
+	require.Equal(t, expected.Program, ops.Program)
+
+	_, _, err = ReadIncludeFile("missing.teal", main)
+	require.Error(t, err)
+}
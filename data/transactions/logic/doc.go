@@ -24,107 +24,127 @@ import (
 
 // short description of every op
 var opDocByName = map[string]string{
-	"err":               "Error. Panic immediately. This is primarily a fencepost against accidental zero bytes getting compiled into programs.",
-	"sha256":            "SHA256 hash of value X, yields [32]byte",
-	"keccak256":         "Keccak256 hash of value X, yields [32]byte",
-	"sha512_256":        "SHA512_256 hash of value X, yields [32]byte",
-	"ed25519verify":     "for (data A, signature B, pubkey C) verify the signature of (\"ProgData\" || program_hash || data) against the pubkey => {0 or 1}",
-	"+":                 "A plus B. Panic on overflow.",
-	"-":                 "A minus B. Panic if B > A.",
-	"/":                 "A divided by B (truncated division). Panic if B == 0.",
-	"*":                 "A times B. Panic on overflow.",
-	"<":                 "A less than B => {0 or 1}",
-	">":                 "A greater than B => {0 or 1}",
-	"<=":                "A less than or equal to B => {0 or 1}",
-	">=":                "A greater than or equal to B => {0 or 1}",
-	"&&":                "A is not zero and B is not zero => {0 or 1}",
-	"||":                "A is not zero or B is not zero => {0 or 1}",
-	"==":                "A is equal to B => {0 or 1}",
-	"!=":                "A is not equal to B => {0 or 1}",
-	"!":                 "X == 0 yields 1; else 0",
-	"len":               "yields length of byte value X",
-	"itob":              "converts uint64 X to big endian bytes",
-	"btoi":              "converts bytes X as big endian to uint64",
-	"%":                 "A modulo B. Panic if B == 0.",
-	"|":                 "A bitwise-or B",
-	"&":                 "A bitwise-and B",
-	"^":                 "A bitwise-xor B",
-	"~":                 "bitwise invert value X",
-	"shl":               "A times 2^B, modulo 2^64",
-	"shr":               "A divided by 2^B",
-	"sqrt":              "The largest integer B such that B^2 <= X",
-	"bitlen":            "The highest set bit in X. If X is a byte-array, it is interpreted as a big-endian unsigned integer. bitlen of 0 is 0, bitlen of 8 is 4",
-	"exp":               "A raised to the Bth power. Panic if A == B == 0 and on overflow",
-	"expw":              "A raised to the Bth power as a 128-bit long result as low (top) and high uint64 values on the stack. Panic if A == B == 0 or if the results exceeds 2^128-1",
-	"mulw":              "A times B out to 128-bit long result as low (top) and high uint64 values on the stack",
-	"addw":              "A plus B out to 128-bit long result as sum (top) and carry-bit uint64 values on the stack",
-	"divmodw":           "Pop four uint64 values.  The deepest two are interpreted as a uint128 dividend (deepest value is high word), the top two are interpreted as a uint128 divisor.  Four uint64 values are pushed to the stack. The deepest two are the quotient (deeper value is the high uint64). The top two are the remainder, low bits on top.",
-	"intcblock":         "prepare block of uint64 constants for use by intc",
-	"intc":              "push Ith constant from intcblock to stack",
-	"intc_0":            "push constant 0 from intcblock to stack",
-	"intc_1":            "push constant 1 from intcblock to stack",
-	"intc_2":            "push constant 2 from intcblock to stack",
-	"intc_3":            "push constant 3 from intcblock to stack",
-	"pushint":           "push immediate UINT to the stack as an integer",
-	"bytecblock":        "prepare block of byte-array constants for use by bytec",
-	"bytec":             "push Ith constant from bytecblock to stack",
-	"bytec_0":           "push constant 0 from bytecblock to stack",
-	"bytec_1":           "push constant 1 from bytecblock to stack",
-	"bytec_2":           "push constant 2 from bytecblock to stack",
-	"bytec_3":           "push constant 3 from bytecblock to stack",
-	"pushbytes":         "push the following program bytes to the stack",
-	"bzero":             "push a byte-array of length X, containing all zero bytes",
-	"arg":               "push Nth LogicSig argument to stack",
-	"arg_0":             "push LogicSig argument 0 to stack",
-	"arg_1":             "push LogicSig argument 1 to stack",
-	"arg_2":             "push LogicSig argument 2 to stack",
-	"arg_3":             "push LogicSig argument 3 to stack",
-	"txn":               "push field F of current transaction to stack",
-	"gtxn":              "push field F of the Tth transaction in the current group",
-	"gtxns":             "push field F of the Xth transaction in the current group",
-	"txna":              "push Ith value of the array field F of the current transaction",
-	"gtxna":             "push Ith value of the array field F from the Tth transaction in the current group",
-	"gtxnsa":            "push Ith value of the array field F from the Xth transaction in the current group",
-	"global":            "push value from globals to stack",
-	"load":              "copy a value from scratch space to the stack",
-	"store":             "pop a value from the stack and store to scratch space",
-	"gload":             "push Ith scratch space index of the Tth transaction in the current group",
-	"gloads":            "push Ith scratch space index of the Xth transaction in the current group",
-	"gaid":              "push the ID of the asset or application created in the Tth transaction of the current group",
-	"gaids":             "push the ID of the asset or application created in the Xth transaction of the current group",
-	"bnz":               "branch to TARGET if value X is not zero",
-	"bz":                "branch to TARGET if value X is zero",
-	"b":                 "branch unconditionally to TARGET",
-	"return":            "use last value on stack as success value; end",
-	"pop":               "discard value X from stack",
-	"dup":               "duplicate last value on stack",
-	"dup2":              "duplicate two last values on stack: A, B -> A, B, A, B",
-	"dig":               "push the Nth value from the top of the stack. dig 0 is equivalent to dup",
-	"swap":              "swaps two last values on stack: A, B -> B, A",
-	"select":            "selects one of two values based on top-of-stack: A, B, C -> (if C != 0 then B else A)",
-	"concat":            "pop two byte-arrays A and B and join them, push the result",
-	"substring":         "pop a byte-array A. For immediate values in 0..255 S and E: extract a range of bytes from A starting at S up to but not including E, push the substring result. If E < S, or either is larger than the array length, the program fails",
-	"substring3":        "pop a byte-array A and two integers B and C. Extract a range of bytes from A starting at B up to but not including C, push the substring result. If C < B, or either is larger than the array length, the program fails",
-	"getbit":            "pop a target A (integer or byte-array), and index B. Push the Bth bit of A.",
-	"setbit":            "pop a target A, index B, and bit C. Set the Bth bit of A to C, and push the result",
-	"getbyte":           "pop a byte-array A and integer B. Extract the Bth byte of A and push it as an integer",
-	"setbyte":           "pop a byte-array A, integer B, and small integer C (between 0..255). Set the Bth byte of A to C, and push the result",
-	"balance":           "get balance for account A, in microalgos. The balance is observed after the effects of previous transactions in the group, and after the fee for the current transaction is deducted.",
-	"min_balance":       "get minimum required balance for account A, in microalgos. Required balance is affected by [ASA](https://developer.algorand.org/docs/features/asa/#assets-overview) and [App](https://developer.algorand.org/docs/features/asc1/stateful/#minimum-balance-requirement-for-a-smart-contract) usage. When creating or opting into an app, the minimum balance grows before the app code runs, therefore the increase is visible there. When deleting or closing out, the minimum balance decreases after the app executes.",
-	"app_opted_in":      "check if account A opted in for the application B => {0 or 1}",
-	"app_local_get":     "read from account A from local state of the current application key B => value",
-	"app_local_get_ex":  "read from account A from local state of the application B key C => [*... stack*, value, 0 or 1]",
-	"app_global_get":    "read key A from global state of a current application => value",
-	"app_global_get_ex": "read from application A global state key B => [*... stack*, value, 0 or 1]",
-	"app_local_put":     "write to account specified by A to local state of a current application key B with value C",
-	"app_global_put":    "write key A and value B to global state of the current application",
-	"app_local_del":     "delete from account A local state key B of the current application",
-	"app_global_del":    "delete key A from a global state of the current application",
-	"asset_holding_get": "read from account A and asset B holding field X (imm arg) => {0 or 1 (top), value}",
-	"asset_params_get":  "read from asset A params field X (imm arg) => {0 or 1 (top), value}",
-	"assert":            "immediately fail unless value X is a non-zero number",
-	"callsub":           "branch unconditionally to TARGET, saving the next instruction on the call stack",
-	"retsub":            "pop the top instruction from the call stack and branch to it",
+	"err":                 "Error. Panic immediately. This is primarily a fencepost against accidental zero bytes getting compiled into programs.",
+	"sha256":              "SHA256 hash of value X, yields [32]byte",
+	"keccak256":           "Keccak256 hash of value X, yields [32]byte",
+	"sha512_256":          "SHA512_256 hash of value X, yields [32]byte",
+	"ed25519verify":       "for (data A, signature B, pubkey C) verify the signature of (\"ProgData\" || program_hash || data) against the pubkey => {0 or 1}",
+	"ecdsa_verify":        "for (data A, signature B, C and pubkey D, E) verify the signature of the data against the pubkey => {0 or 1}",
+	"ecdsa_pk_decompress": "decompress pubkey A into components X, Y => [*... stack*, X, Y]",
+	"ecdsa_pk_recover":    "for (data A, recovery id B, signature C, D) recover a public key => [*... stack*, X, Y]",
+	"+":                   "A plus B. Panic on overflow.",
+	"-":                   "A minus B. Panic if B > A.",
+	"/":                   "A divided by B (truncated division). Panic if B == 0.",
+	"*":                   "A times B. Panic on overflow.",
+	"<":                   "A less than B => {0 or 1}",
+	">":                   "A greater than B => {0 or 1}",
+	"<=":                  "A less than or equal to B => {0 or 1}",
+	">=":                  "A greater than or equal to B => {0 or 1}",
+	"&&":                  "A is not zero and B is not zero => {0 or 1}",
+	"||":                  "A is not zero or B is not zero => {0 or 1}",
+	"==":                  "A is equal to B => {0 or 1}",
+	"!=":                  "A is not equal to B => {0 or 1}",
+	"!":                   "X == 0 yields 1; else 0",
+	"len":                 "yields length of byte value X",
+	"itob":                "converts uint64 X to big endian bytes",
+	"btoi":                "converts bytes X as big endian to uint64",
+	"%":                   "A modulo B. Panic if B == 0.",
+	"|":                   "A bitwise-or B",
+	"&":                   "A bitwise-and B",
+	"^":                   "A bitwise-xor B",
+	"~":                   "bitwise invert value X",
+	"shl":                 "A times 2^B, modulo 2^64",
+	"shr":                 "A divided by 2^B",
+	"sqrt":                "The largest integer B such that B^2 <= X",
+	"bitlen":              "The highest set bit in X. If X is a byte-array, it is interpreted as a big-endian unsigned integer. bitlen of 0 is 0, bitlen of 8 is 4",
+	"exp":                 "A raised to the Bth power. Panic if A == B == 0 and on overflow",
+	"expw":                "A raised to the Bth power as a 128-bit long result as low (top) and high uint64 values on the stack. Panic if A == B == 0 or if the results exceeds 2^128-1",
+	"mulw":                "A times B out to 128-bit long result as low (top) and high uint64 values on the stack",
+	"addw":                "A plus B out to 128-bit long result as sum (top) and carry-bit uint64 values on the stack",
+	"divmodw":             "Pop four uint64 values.  The deepest two are interpreted as a uint128 dividend (deepest value is high word), the top two are interpreted as a uint128 divisor.  Four uint64 values are pushed to the stack. The deepest two are the quotient (deeper value is the high uint64). The top two are the remainder, low bits on top.",
+	"intcblock":           "prepare block of uint64 constants for use by intc",
+	"intc":                "push Ith constant from intcblock to stack",
+	"intc_0":              "push constant 0 from intcblock to stack",
+	"intc_1":              "push constant 1 from intcblock to stack",
+	"intc_2":              "push constant 2 from intcblock to stack",
+	"intc_3":              "push constant 3 from intcblock to stack",
+	"pushint":             "push immediate UINT to the stack as an integer",
+	"bytecblock":          "prepare block of byte-array constants for use by bytec",
+	"bytec":               "push Ith constant from bytecblock to stack",
+	"bytec_0":             "push constant 0 from bytecblock to stack",
+	"bytec_1":             "push constant 1 from bytecblock to stack",
+	"bytec_2":             "push constant 2 from bytecblock to stack",
+	"bytec_3":             "push constant 3 from bytecblock to stack",
+	"pushbytes":           "push the following program bytes to the stack",
+	"bzero":               "push a byte-array of length X, containing all zero bytes",
+	"arg":                 "push Nth LogicSig argument to stack",
+	"arg_0":               "push LogicSig argument 0 to stack",
+	"arg_1":               "push LogicSig argument 1 to stack",
+	"arg_2":               "push LogicSig argument 2 to stack",
+	"arg_3":               "push LogicSig argument 3 to stack",
+	"txn":                 "push field F of current transaction to stack",
+	"gtxn":                "push field F of the Tth transaction in the current group",
+	"gtxns":               "push field F of the Xth transaction in the current group",
+	"txna":                "push Ith value of the array field F of the current transaction",
+	"gtxna":               "push Ith value of the array field F from the Tth transaction in the current group",
+	"gtxnsa":              "push Ith value of the array field F from the Xth transaction in the current group",
+	"global":              "push value from globals to stack",
+	"load":                "copy a value from scratch space to the stack",
+	"store":               "pop a value from the stack and store to scratch space",
+	"gload":               "push Ith scratch space index of the Tth transaction in the current group",
+	"gloads":              "push Ith scratch space index of the Xth transaction in the current group",
+	"gaid":                "push the ID of the asset or application created in the Tth transaction of the current group",
+	"gaids":               "push the ID of the asset or application created in the Xth transaction of the current group",
+	"gretdata":            "push Ith return data entry of the Tth transaction in the current group, and 1 if it exists or an empty byte-array and 0 if it does not",
+	"gretdatas":           "push Ith return data entry of the Xth transaction in the current group, and 1 if it exists or an empty byte-array and 0 if it does not",
+	"retdata_put":         "append bytes A to the return data of the current transaction",
+	"bnz":                 "branch to TARGET if value X is not zero",
+	"bz":                  "branch to TARGET if value X is zero",
+	"b":                   "branch unconditionally to TARGET",
+	"return":              "use last value on stack as success value; end",
+	"pop":                 "discard value X from stack",
+	"dup":                 "duplicate last value on stack",
+	"dup2":                "duplicate two last values on stack: A, B -> A, B, A, B",
+	"dig":                 "push the Nth value from the top of the stack. dig 0 is equivalent to dup",
+	"swap":                "swaps two last values on stack: A, B -> B, A",
+	"select":              "selects one of two values based on top-of-stack: A, B, C -> (if C != 0 then B else A)",
+	"concat":              "pop two byte-arrays A and B and join them, push the result",
+	"substring":           "pop a byte-array A. For immediate values in 0..255 S and E: extract a range of bytes from A starting at S up to but not including E, push the substring result. If E < S, or either is larger than the array length, the program fails",
+	"substring3":          "pop a byte-array A and two integers B and C. Extract a range of bytes from A starting at B up to but not including C, push the substring result. If C < B, or either is larger than the array length, the program fails",
+	"getbit":              "pop a target A (integer or byte-array), and index B. Push the Bth bit of A.",
+	"setbit":              "pop a target A, index B, and bit C. Set the Bth bit of A to C, and push the result",
+	"getbyte":             "pop a byte-array A and integer B. Extract the Bth byte of A and push it as an integer",
+	"setbyte":             "pop a byte-array A, integer B, and small integer C (between 0..255). Set the Bth byte of A to C, and push the result",
+	"balance":             "get balance for account A, in microalgos. The balance is observed after the effects of previous transactions in the group, and after the fee for the current transaction is deducted.",
+	"min_balance":         "get minimum required balance for account A, in microalgos. Required balance is affected by [ASA](https://developer.algorand.org/docs/features/asa/#assets-overview) and [App](https://developer.algorand.org/docs/features/asc1/stateful/#minimum-balance-requirement-for-a-smart-contract) usage. When creating or opting into an app, the minimum balance grows before the app code runs, therefore the increase is visible there. When deleting or closing out, the minimum balance decreases after the app executes.",
+	"app_opted_in":        "check if account A opted in for the application B => {0 or 1}",
+	"app_local_get":       "read from account A from local state of the current application key B => value",
+	"app_local_get_ex":    "read from account A from local state of the application B key C => [*... stack*, value, 0 or 1]",
+	"app_global_get":      "read key A from global state of a current application => value",
+	"app_global_get_ex":   "read from application A global state key B => [*... stack*, value, 0 or 1]",
+	"app_local_put":       "write to account specified by A to local state of a current application key B with value C",
+	"app_global_put":      "write key A and value B to global state of the current application",
+	"app_local_del":       "delete from account A local state key B of the current application",
+	"app_global_del":      "delete key A from a global state of the current application",
+	"asset_holding_get":   "read from account A and asset B holding field X (imm arg) => {0 or 1 (top), value}",
+	"asset_params_get":    "read from asset A params field X (imm arg) => {0 or 1 (top), value}",
+	"app_params_get":      "read from app A params field X (imm arg) => {0 or 1 (top), value}",
+	"log":                 "write bytes A to log state of the current application",
+	"itxn_begin":          "Begin preparation of a new inner transaction",
+	"itxn_field":          "Set field F of the current inner transaction to X",
+	"itxn_submit":         "Execute the current inner transaction. Panic on any failure.",
+	"itxn":                "push field F of the last inner transaction to stack",
+	"box_create":          "create a box named A, of length B. Fail if A is empty or B exceeds MaxBoxSize. Returns 0 if A already existed, else 1",
+	"box_extract":         "read C bytes from box A, starting at offset B. Fail if A does not exist, or the byte range is outside A's size.",
+	"box_replace":         "write byte-array C into box A, starting at offset B. Fail if A does not exist, or the byte range is outside A's size.",
+	"box_del":             "delete box named A if it exists. Return 1 if A existed, 0 otherwise",
+	"box_len":             "X is the length of box A if A exists, else 0. Y is 1 if A exists, else 0.",
+	"box_get":             "X is the contents of box A if A exists, else ''. Y is 1 if A exists, else 0.",
+	"box_put":             "replaces the contents of box A with byte-array B. Fails if A exists and len(B) != len(box A). Creates A if it does not exist",
+	"box_resize":          "change the size of box named A to be of length B, adding zero bytes to end or removing bytes from the end, as needed. Fail if the name A is empty, A is not an existing box, or B exceeds MaxBoxSize.",
+	"assert":              "immediately fail unless value X is a non-zero number",
+	"callsub":             "branch unconditionally to TARGET, saving the next instruction on the call stack",
+	"retsub":              "pop the top instruction from the call stack and branch to it",
 
 	"b+":  "A plus B, where A and B are byte-arrays interpreted as big-endian unsigned integers",
 	"b-":  "A minus B, where A and B are byte-arrays interpreted as big-endian unsigned integers. Panic on underflow.",
@@ -149,33 +169,41 @@ func OpDoc(opName string) string {
 }
 
 var opcodeImmediateNotes = map[string]string{
-	"intcblock":         "{varuint length} [{varuint value}, ...]",
-	"intc":              "{uint8 int constant index}",
-	"pushint":           "{varuint int}",
-	"bytecblock":        "{varuint length} [({varuint value length} bytes), ...]",
-	"bytec":             "{uint8 byte constant index}",
-	"pushbytes":         "{varuint length} {bytes}",
-	"arg":               "{uint8 arg index N}",
-	"txn":               "{uint8 transaction field index}",
-	"gtxn":              "{uint8 transaction group index} {uint8 transaction field index}",
-	"gtxns":             "{uint8 transaction field index}",
-	"txna":              "{uint8 transaction field index} {uint8 transaction field array index}",
-	"gtxna":             "{uint8 transaction group index} {uint8 transaction field index} {uint8 transaction field array index}",
-	"gtxnsa":            "{uint8 transaction field index} {uint8 transaction field array index}",
-	"global":            "{uint8 global field index}",
-	"bnz":               "{int16 branch offset, big endian}",
-	"bz":                "{int16 branch offset, big endian}",
-	"b":                 "{int16 branch offset, big endian}",
-	"callsub":           "{int16 branch offset, big endian}",
-	"load":              "{uint8 position in scratch space to load from}",
-	"store":             "{uint8 position in scratch space to store to}",
-	"gload":             "{uint8 transaction group index} {uint8 position in scratch space to load from}",
-	"gloads":            "{uint8 position in scratch space to load from}",
-	"gaid":              "{uint8 transaction group index}",
-	"substring":         "{uint8 start position} {uint8 end position}",
-	"dig":               "{uint8 depth}",
-	"asset_holding_get": "{uint8 asset holding field index}",
-	"asset_params_get":  "{uint8 asset params field index}",
+	"intcblock":           "{varuint length} [{varuint value}, ...]",
+	"intc":                "{uint8 int constant index}",
+	"pushint":             "{varuint int}",
+	"bytecblock":          "{varuint length} [({varuint value length} bytes), ...]",
+	"bytec":               "{uint8 byte constant index}",
+	"pushbytes":           "{varuint length} {bytes}",
+	"arg":                 "{uint8 arg index N}",
+	"txn":                 "{uint8 transaction field index}",
+	"gtxn":                "{uint8 transaction group index} {uint8 transaction field index}",
+	"gtxns":               "{uint8 transaction field index}",
+	"txna":                "{uint8 transaction field index} {uint8 transaction field array index}",
+	"gtxna":               "{uint8 transaction group index} {uint8 transaction field index} {uint8 transaction field array index}",
+	"gtxnsa":              "{uint8 transaction field index} {uint8 transaction field array index}",
+	"global":              "{uint8 global field index}",
+	"bnz":                 "{int16 branch offset, big endian}",
+	"bz":                  "{int16 branch offset, big endian}",
+	"b":                   "{int16 branch offset, big endian}",
+	"callsub":             "{int16 branch offset, big endian}",
+	"load":                "{uint8 position in scratch space to load from}",
+	"store":               "{uint8 position in scratch space to store to}",
+	"gload":               "{uint8 transaction group index} {uint8 position in scratch space to load from}",
+	"gloads":              "{uint8 position in scratch space to load from}",
+	"gaid":                "{uint8 transaction group index}",
+	"gretdata":            "{uint8 transaction group index} {uint8 return data index}",
+	"gretdatas":           "{uint8 return data index}",
+	"substring":           "{uint8 start position} {uint8 end position}",
+	"dig":                 "{uint8 depth}",
+	"asset_holding_get":   "{uint8 asset holding field index}",
+	"asset_params_get":    "{uint8 asset params field index}",
+	"app_params_get":      "{uint8 app params field index}",
+	"ecdsa_verify":        "{uint8 curve index}",
+	"ecdsa_pk_decompress": "{uint8 curve index}",
+	"ecdsa_pk_recover":    "{uint8 curve index}",
+	"itxn_field":          "{uint8 transaction field index}",
+	"itxn":                "{uint8 transaction field index}",
 }
 
 // OpImmediateNote returns a short string about immediate data which follows the op byte
@@ -185,43 +213,58 @@ func OpImmediateNote(opName string) string {
 
 // further documentation on the function of the opcode
 var opDocExtras = map[string]string{
-	"ed25519verify":     "The 32 byte public key is the last element on the stack, preceded by the 64 byte signature at the second-to-last element on the stack, preceded by the data which was signed at the third-to-last element on the stack.",
-	"bnz":               "The `bnz` instruction opcode 0x40 is followed by two immediate data bytes which are a high byte first and low byte second which together form a 16 bit offset which the instruction may branch to. For a bnz instruction at `pc`, if the last element of the stack is not zero then branch to instruction at `pc + 3 + N`, else proceed to next instruction at `pc + 3`. Branch targets must be aligned instructions. (e.g. Branching to the second byte of a 2 byte op will be rejected.) Starting at v4, the offset is treated as a signed 16 bit integer allowing for backward branches and looping. In prior version (v1 to v3), branch offsets are limited to forward branches only, 0-0x7fff.\n\nAt v2 it became allowed to branch to the end of the program exactly after the last instruction: bnz to byte N (with 0-indexing) was illegal for a TEAL program with N bytes before v2, and is legal after it. This change eliminates the need for a last instruction of no-op as a branch target at the end. (Branching beyond the end--in other words, to a byte larger than N--is still illegal and will cause the program to fail.)",
-	"bz":                "See `bnz` for details on how branches work. `bz` inverts the behavior of `bnz`.",
-	"b":                 "See `bnz` for details on how branches work. `b` always jumps to the offset.",
-	"callsub":           "The call stack is separate from the data stack. Only `callsub` and `retsub` manipulate it.",
-	"retsub":            "The call stack is separate from the data stack. Only `callsub` and `retsub` manipulate it.",
-	"intcblock":         "`intcblock` loads following program bytes into an array of integer constants in the evaluator. These integer constants can be referred to by `intc` and `intc_*` which will push the value onto the stack. Subsequent calls to `intcblock` reset and replace the integer constants available to the script.",
-	"bytecblock":        "`bytecblock` loads the following program bytes into an array of byte-array constants in the evaluator. These constants can be referred to by `bytec` and `bytec_*` which will push the value onto the stack. Subsequent calls to `bytecblock` reset and replace the bytes constants available to the script.",
-	"*":                 "Overflow is an error condition which halts execution and fails the transaction. Full precision is available from `mulw`.",
-	"+":                 "Overflow is an error condition which halts execution and fails the transaction. Full precision is available from `addw`.",
-	"/":                 "`divmodw` is available to divide the two-element values produced by `mulw` and `addw`.",
-	"bitlen":            "bitlen interprets arrays as big-endian integers, unlike setbit/getbit",
-	"txn":               "FirstValidTime causes the program to fail. The field is reserved for future use.",
-	"gtxn":              "for notes on transaction fields available, see `txn`. If this transaction is _i_ in the group, `gtxn i field` is equivalent to `txn field`.",
-	"gtxns":             "for notes on transaction fields available, see `txn`. If top of stack is _i_, `gtxns field` is equivalent to `gtxn _i_ field`. gtxns exists so that _i_ can be calculated, often based on the index of the current transaction.",
-	"gload":             "`gload` fails unless the requested transaction is an ApplicationCall and T < GroupIndex.",
-	"gloads":            "`gloads` fails unless the requested transaction is an ApplicationCall and X < GroupIndex.",
-	"gaid":              "`gaid` fails unless the requested transaction created an asset or application and T < GroupIndex.",
-	"gaids":             "`gaids` fails unless the requested transaction created an asset or application and X < GroupIndex.",
-	"btoi":              "`btoi` panics if the input is longer than 8 bytes.",
-	"concat":            "`concat` panics if the result would be greater than 4096 bytes.",
-	"pushbytes":         "pushbytes args are not added to the bytecblock during assembly processes",
-	"pushint":           "pushint args are not added to the intcblock during assembly processes",
-	"getbit":            "see explanation of bit ordering in setbit",
-	"setbit":            "When A is a uint64, index 0 is the least significant bit. Setting bit 3 to 1 on the integer 0 yields 8, or 2^3. When A is a byte array, index 0 is the leftmost bit of the leftmost byte. Setting bits 0 through 11 to 1 in a 4-byte-array of 0s yields the byte array 0xfff00000. Setting bit 3 to 1 on the 1-byte-array 0x00 yields the byte array 0x10.",
-	"balance":           "params: Before v4, Txn.Accounts offset. Since v4, Txn.Accounts offset or an account address that appears in Txn.Accounts or is Txn.Sender). Return: value.",
-	"min_balance":       "params: Before v4, Txn.Accounts offset. Since v4, Txn.Accounts offset or an account address that appears in Txn.Accounts or is Txn.Sender). Return: value.",
-	"app_opted_in":      "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), application id (or, since v4, a Txn.ForeignApps offset). Return: 1 if opted in and 0 otherwise.",
-	"app_local_get":     "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), state key. Return: value. The value is zero (of type uint64) if the key does not exist.",
-	"app_local_get_ex":  "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), application id (or, since v4, a Txn.ForeignApps offset), state key. Return: did_exist flag (top of the stack, 1 if exist and 0 otherwise), value. The value is zero (of type uint64) if the key does not exist.",
-	"app_global_get_ex": "params: Txn.ForeignApps offset (or, since v4, an application id that appears in Txn.ForeignApps or is the CurrentApplicationID), state key. Return: did_exist flag (top of the stack, 1 if exist and 0 otherwise), value. The value is zero (of type uint64) if the key does not exist.",
-	"app_global_get":    "params: state key. Return: value. The value is zero (of type uint64) if the key does not exist.",
-	"app_local_put":     "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), state key, value.",
-	"app_local_del":     "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), state key.\n\nDeleting a key which is already absent has no effect on the application local state. (In particular, it does _not_ cause the program to fail.)",
-	"app_global_del":    "params: state key.\n\nDeleting a key which is already absent has no effect on the application global state. (In particular, it does _not_ cause the program to fail.)",
-	"asset_holding_get": "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), asset id (or, since v4, a Txn.ForeignAssets offset). Return: did_exist flag (1 if exist and 0 otherwise), value.",
-	"asset_params_get":  "params: Before v4, Txn.ForeignAssets offset. Since v4, Txn.ForeignAssets offset or an asset id that appears in Txn.ForeignAssets. Return: did_exist flag (1 if exist and 0 otherwise), value.",
+	"ed25519verify":       "The 32 byte public key is the last element on the stack, preceded by the 64 byte signature at the second-to-last element on the stack, preceded by the data which was signed at the third-to-last element on the stack.",
+	"ecdsa_verify":        "The 32 byte Y-component of a public key is the last element on the stack, preceded by the X-component of the pubkey, preceded by the S and R components of a signature, preceded by the data, which is the fifth element on the stack. All values are big-endian encoded. The signed data must be 32 bytes long, and only signatures in lower-S form are accepted.",
+	"ecdsa_pk_decompress": "The 33 byte public key in compressed form is decompressed into its X and Y (top) components. All values are big-endian encoded.",
+	"ecdsa_pk_recover":    "S (top) and R elements of a signature, a recovery id and the data (bottom) are expected on the stack and used to derive a public key. All values are big-endian encoded. The signed data must be 32 bytes long.",
+	"bnz":                 "The `bnz` instruction opcode 0x40 is followed by two immediate data bytes which are a high byte first and low byte second which together form a 16 bit offset which the instruction may branch to. For a bnz instruction at `pc`, if the last element of the stack is not zero then branch to instruction at `pc + 3 + N`, else proceed to next instruction at `pc + 3`. Branch targets must be aligned instructions. (e.g. Branching to the second byte of a 2 byte op will be rejected.) Starting at v4, the offset is treated as a signed 16 bit integer allowing for backward branches and looping. In prior version (v1 to v3), branch offsets are limited to forward branches only, 0-0x7fff.\n\nAt v2 it became allowed to branch to the end of the program exactly after the last instruction: bnz to byte N (with 0-indexing) was illegal for a TEAL program with N bytes before v2, and is legal after it. This change eliminates the need for a last instruction of no-op as a branch target at the end. (Branching beyond the end--in other words, to a byte larger than N--is still illegal and will cause the program to fail.)",
+	"bz":                  "See `bnz` for details on how branches work. `bz` inverts the behavior of `bnz`.",
+	"b":                   "See `bnz` for details on how branches work. `b` always jumps to the offset.",
+	"callsub":             "The call stack is separate from the data stack. Only `callsub` and `retsub` manipulate it.",
+	"retsub":              "The call stack is separate from the data stack. Only `callsub` and `retsub` manipulate it.",
+	"intcblock":           "`intcblock` loads following program bytes into an array of integer constants in the evaluator. These integer constants can be referred to by `intc` and `intc_*` which will push the value onto the stack. Subsequent calls to `intcblock` reset and replace the integer constants available to the script.",
+	"bytecblock":          "`bytecblock` loads the following program bytes into an array of byte-array constants in the evaluator. These constants can be referred to by `bytec` and `bytec_*` which will push the value onto the stack. Subsequent calls to `bytecblock` reset and replace the bytes constants available to the script.",
+	"*":                   "Overflow is an error condition which halts execution and fails the transaction. Full precision is available from `mulw`.",
+	"+":                   "Overflow is an error condition which halts execution and fails the transaction. Full precision is available from `addw`.",
+	"/":                   "`divmodw` is available to divide the two-element values produced by `mulw` and `addw`.",
+	"bitlen":              "bitlen interprets arrays as big-endian integers, unlike setbit/getbit",
+	"txn":                 "FirstValidTime causes the program to fail. The field is reserved for future use.",
+	"gtxn":                "for notes on transaction fields available, see `txn`. If this transaction is _i_ in the group, `gtxn i field` is equivalent to `txn field`.",
+	"gtxns":               "for notes on transaction fields available, see `txn`. If top of stack is _i_, `gtxns field` is equivalent to `gtxn _i_ field`. gtxns exists so that _i_ can be calculated, often based on the index of the current transaction.",
+	"gload":               "`gload` fails unless the requested transaction is an ApplicationCall and T < GroupIndex.",
+	"gloads":              "`gloads` fails unless the requested transaction is an ApplicationCall and X < GroupIndex.",
+	"gaid":                "`gaid` fails unless the requested transaction created an asset or application and T < GroupIndex.",
+	"gaids":               "`gaids` fails unless the requested transaction created an asset or application and X < GroupIndex.",
+	"gretdata":            "`gretdata` fails unless T < GroupIndex. A logic signature only sees the return data of earlier logic signatures in the group, and an application only sees the return data of earlier application calls, because logic signatures are evaluated before, and separately from, applications. `gretdata` fails if a logic signature reads a transaction that isn't signed by a logic signature, or if an application reads a transaction that isn't an application call.",
+	"gretdatas":           "`gretdatas` fails unless X < GroupIndex. As with `gretdata`, logic signatures and applications each see only the return data of earlier programs of their own kind, and reading the other kind fails.",
+	"retdata_put":         "`retdata_put` can be called up to MaxReturnDataEntries times in a program, leaving up to a total of MaxReturnDataSize bytes. The return data of an application is recorded in the ApplyData of the application call. The return data of a logic signature is only available to the logic signatures that follow it, and isn't recorded.",
+	"btoi":                "`btoi` panics if the input is longer than 8 bytes.",
+	"concat":              "`concat` panics if the result would be greater than 4096 bytes.",
+	"pushbytes":           "pushbytes args are not added to the bytecblock during assembly processes",
+	"pushint":             "pushint args are not added to the intcblock during assembly processes",
+	"getbit":              "see explanation of bit ordering in setbit",
+	"setbit":              "When A is a uint64, index 0 is the least significant bit. Setting bit 3 to 1 on the integer 0 yields 8, or 2^3. When A is a byte array, index 0 is the leftmost bit of the leftmost byte. Setting bits 0 through 11 to 1 in a 4-byte-array of 0s yields the byte array 0xfff00000. Setting bit 3 to 1 on the 1-byte-array 0x00 yields the byte array 0x10.",
+	"balance":             "params: Before v4, Txn.Accounts offset. Since v4, Txn.Accounts offset or an account address that appears in Txn.Accounts or is Txn.Sender). Return: value.",
+	"min_balance":         "params: Before v4, Txn.Accounts offset. Since v4, Txn.Accounts offset or an account address that appears in Txn.Accounts or is Txn.Sender). Return: value.",
+	"app_opted_in":        "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), application id (or, since v4, a Txn.ForeignApps offset). Return: 1 if opted in and 0 otherwise.",
+	"app_local_get":       "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), state key. Return: value. The value is zero (of type uint64) if the key does not exist.",
+	"app_local_get_ex":    "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), application id (or, since v4, a Txn.ForeignApps offset), state key. Return: did_exist flag (top of the stack, 1 if exist and 0 otherwise), value. The value is zero (of type uint64) if the key does not exist.",
+	"app_global_get_ex":   "params: Txn.ForeignApps offset (or, since v4, an application id that appears in Txn.ForeignApps or is the CurrentApplicationID), state key. Return: did_exist flag (top of the stack, 1 if exist and 0 otherwise), value. The value is zero (of type uint64) if the key does not exist.",
+	"app_global_get":      "params: state key. Return: value. The value is zero (of type uint64) if the key does not exist.",
+	"app_local_put":       "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), state key, value.",
+	"app_local_del":       "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), state key.\n\nDeleting a key which is already absent has no effect on the application local state. (In particular, it does _not_ cause the program to fail.)",
+	"app_global_del":      "params: state key.\n\nDeleting a key which is already absent has no effect on the application global state. (In particular, it does _not_ cause the program to fail.)",
+	"asset_holding_get":   "params: Txn.Accounts offset (or, since v4, an account address that appears in Txn.Accounts or is Txn.Sender), asset id (or, since v4, a Txn.ForeignAssets offset). Return: did_exist flag (1 if exist and 0 otherwise), value.",
+	"asset_params_get":    "params: Before v4, Txn.ForeignAssets offset. Since v4, Txn.ForeignAssets offset or an asset id that appears in Txn.ForeignAssets. Return: did_exist flag (1 if exist and 0 otherwise), value.",
+	"app_params_get":      "params: Txn.ForeignApps offset or an app id that appears in Txn.ForeignApps or is the CurrentApplicationID. Return: did_exist flag (1 if exist and 0 otherwise), value.",
+	"log":                 "`log` can be called up to MaxLogCalls times in a program, and log up to a total of MaxLogSize bytes. Logged values are recorded in the ApplyData of the application call.",
+	"itxn_begin":          "`itxn_begin` initializes Sender to the application address, Fee to the minimum allowable, and FirstValid/LastValid to the values in the top-level transaction. It fails if a previous `itxn_begin` has not been followed by `itxn_submit`.",
+	"itxn_field":          "`itxn_field` fails if X is of the wrong type for F, including a byte array of the wrong size for use as an address when F is an address field. Only the Type, TypeEnum, Sender, Fee, Receiver, Amount, CloseRemainderTo, XferAsset, AssetAmount, AssetSender, AssetReceiver, AssetCloseTo, ApplicationID, OnCompletion, ApplicationArgs, Accounts, Assets and Applications fields may be set, and only `pay`, `axfer` and `appl` transactions may be created. Setting ApplicationArgs, Accounts, Assets or Applications appends X to the array. Addresses must be the application address, or an account that appears in Txn.Accounts or is Txn.Sender (or an offset into Txn.Accounts). Assets and applications must appear in Txn.ForeignAssets and Txn.ForeignApps.",
+	"itxn_submit":         "`itxn_submit` resets the current transaction so that it can not be resubmitted. A new `itxn_begin` is required to prepare another inner transaction. The Sender must be the application address or an account rekeyed to it. At most MaxInnerTransactions may be submitted by a single program. An `appl` transaction may only call an existing application with NoOp, OptIn or CloseOut. It fails if the called application is already executing, or if more than MaxAppCallDepth applications would be executing at once. The called application's opcode cost is charged to the caller, and it may spend only what remains of the caller's budget.",
+	"itxn":                "for notes on transaction fields available, see `txn`. `itxn` fails if no inner transaction has been submitted yet. NumLogs and LastLog report the logs of an inner `appl` transaction, and are only available from `itxn`.",
+	"box_create":          "Newly created boxes are filled with 0 bytes. `box_create` will fail if the referenced box already exists with a different size. Otherwise, existing boxes are unchanged by `box_create`. The application account must hold enough Algos to cover the box minimum balance, which grows with the number of boxes and their total size.",
+	"box_get":             "For boxes that exceed 4,096 bytes, consider `box_create`, `box_extract`, and `box_replace`",
+	"box_put":             "For boxes that exceed 4,096 bytes, consider `box_create`, `box_extract`, and `box_replace`",
 }
 
 // OpDocExtra returns extra documentation text about an op
@@ -231,12 +274,14 @@ func OpDocExtra(opName string) string {
 
 // OpGroups is groupings of ops for documentation purposes.
 var OpGroups = map[string][]string{
-	"Arithmetic":           {"sha256", "keccak256", "sha512_256", "ed25519verify", "+", "-", "/", "*", "<", ">", "<=", ">=", "&&", "||", "shl", "shr", "sqrt", "bitlen", "exp", "==", "!=", "!", "len", "itob", "btoi", "%", "|", "&", "^", "~", "mulw", "addw", "divmodw", "expw", "getbit", "setbit", "getbyte", "setbyte", "concat", "substring", "substring3"},
+	"Arithmetic":           {"sha256", "keccak256", "sha512_256", "ed25519verify", "ecdsa_verify", "ecdsa_pk_decompress", "ecdsa_pk_recover", "+", "-", "/", "*", "<", ">", "<=", ">=", "&&", "||", "shl", "shr", "sqrt", "bitlen", "exp", "==", "!=", "!", "len", "itob", "btoi", "%", "|", "&", "^", "~", "mulw", "addw", "divmodw", "expw", "getbit", "setbit", "getbyte", "setbyte", "concat", "substring", "substring3"},
 	"Byteslice Arithmetic": {"b+", "b-", "b/", "b*", "b<", "b>", "b<=", "b>=", "b==", "b!=", "b%"},
 	"Byteslice Logic":      {"b|", "b&", "b^", "b~"},
-	"Loading Values":       {"intcblock", "intc", "intc_0", "intc_1", "intc_2", "intc_3", "pushint", "bytecblock", "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3", "pushbytes", "bzero", "arg", "arg_0", "arg_1", "arg_2", "arg_3", "txn", "gtxn", "txna", "gtxna", "gtxns", "gtxnsa", "global", "load", "store", "gload", "gloads", "gaid", "gaids"},
+	"Loading Values":       {"intcblock", "intc", "intc_0", "intc_1", "intc_2", "intc_3", "pushint", "bytecblock", "bytec", "bytec_0", "bytec_1", "bytec_2", "bytec_3", "pushbytes", "bzero", "arg", "arg_0", "arg_1", "arg_2", "arg_3", "txn", "gtxn", "txna", "gtxna", "gtxns", "gtxnsa", "global", "load", "store", "gload", "gloads", "gaid", "gaids", "gretdata", "gretdatas"},
 	"Flow Control":         {"err", "bnz", "bz", "b", "return", "pop", "dup", "dup2", "dig", "swap", "select", "assert", "callsub", "retsub"},
-	"State Access":         {"balance", "min_balance", "app_opted_in", "app_local_get", "app_local_get_ex", "app_global_get", "app_global_get_ex", "app_local_put", "app_global_put", "app_local_del", "app_global_del", "asset_holding_get", "asset_params_get"},
+	"State Access":         {"balance", "min_balance", "app_opted_in", "app_local_get", "app_local_get_ex", "app_global_get", "app_global_get_ex", "app_local_put", "app_global_put", "app_local_del", "app_global_del", "asset_holding_get", "asset_params_get", "app_params_get", "log", "retdata_put"},
+	"Inner Transactions":   {"itxn_begin", "itxn_field", "itxn_submit", "itxn"},
+	"Box Access":           {"box_create", "box_extract", "box_replace", "box_del", "box_len", "box_get", "box_put", "box_resize"},
 }
 
 // OpCost indicates the cost of an operation over the range of
@@ -357,6 +402,8 @@ var txnFieldDocs = map[string]string{
 	"FreezeAssetAccount":       "32 byte address of the account whose asset slot is being frozen or un-frozen",
 	"FreezeAssetFrozen":        "The new frozen value, 0 or 1",
 	"ExtraProgramPages":        "Number of additional pages for each of the application's approval and clear state programs. An ExtraProgramPages of 1 means 2048 more total bytes, or 1024 for each program.",
+	"NumLogs":                  "Number of Logs (only with `itxn`)",
+	"LastLog":                  "The last message emitted. Empty bytes if none were emitted (only with `itxn`)",
 }
 
 // TxnFieldDocs are notes on fields available by `txn` and `gtxn` with extra versioning info if any
@@ -365,16 +412,17 @@ func TxnFieldDocs() map[string]string {
 }
 
 var globalFieldDocs = map[string]string{
-	"MinTxnFee":            "micro Algos",
-	"MinBalance":           "micro Algos",
-	"MaxTxnLife":           "rounds",
-	"ZeroAddress":          "32 byte address of all zero bytes",
-	"GroupSize":            "Number of transactions in this atomic transaction group. At least 1",
-	"LogicSigVersion":      "Maximum supported TEAL version",
-	"Round":                "Current round number",
-	"LatestTimestamp":      "Last confirmed block UNIX timestamp. Fails if negative",
-	"CurrentApplicationID": "ID of current application executing. Fails if no such application is executing",
-	"CreatorAddress":       "Address of the creator of the current application. Fails if no such application is executing",
+	"MinTxnFee":                 "micro Algos",
+	"MinBalance":                "micro Algos",
+	"MaxTxnLife":                "rounds",
+	"ZeroAddress":               "32 byte address of all zero bytes",
+	"GroupSize":                 "Number of transactions in this atomic transaction group. At least 1",
+	"LogicSigVersion":           "Maximum supported TEAL version",
+	"Round":                     "Current round number",
+	"LatestTimestamp":           "Last confirmed block UNIX timestamp. Fails if negative",
+	"CurrentApplicationID":      "ID of current application executing. Fails if no such application is executing",
+	"CreatorAddress":            "Address of the creator of the current application. Fails if no such application is executing",
+	"CurrentApplicationAddress": "Address that the current application controls. Fails if no such application is executing",
 }
 
 // GlobalFieldDocs are notes on fields available in `global` with extra versioning info if any
@@ -411,6 +459,24 @@ var AssetHoldingFieldDocs = map[string]string{
 	"AssetFrozen":  "Is the asset frozen or not",
 }
 
+// AppParamsFieldDocs are notes on fields available in `app_params_get`
+var AppParamsFieldDocs = map[string]string{
+	"AppApprovalProgram":    "Bytecode of Approval Program",
+	"AppClearStateProgram":  "Bytecode of Clear State Program",
+	"AppGlobalNumUint":      "Number of uint64 values allowed in Global State",
+	"AppGlobalNumByteSlice": "Number of byte array values allowed in Global State",
+	"AppLocalNumUint":       "Number of uint64 values allowed in Local State",
+	"AppLocalNumByteSlice":  "Number of byte array values allowed in Local State",
+	"AppExtraProgramPages":  "Number of Extra Program Pages of code space",
+	"AppCreator":            "Creator address",
+	"AppAddress":            "Address for which this application has authority",
+}
+
+// EcdsaCurveDocs are notes on curves available in `ecdsa_` opcodes
+var EcdsaCurveDocs = map[string]string{
+	"Secp256k1": "secp256k1 curve",
+}
+
 // AssetParamsFieldDocs are notes on fields available in `asset_params_get`
 var AssetParamsFieldDocs = map[string]string{
 	"AssetTotal":         "Total number of units of this asset",
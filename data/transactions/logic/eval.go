@@ -31,6 +31,8 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/algorand/go-algorand/config"
@@ -86,6 +88,13 @@ func (sv *stackValue) clone() stackValue {
 	return stackValue{Uint: sv.Uint}
 }
 
+func (sv *stackValue) uint() (uint64, error) {
+	if sv.Bytes != nil {
+		return 0, errors.New("not a uint64")
+	}
+	return sv.Uint, nil
+}
+
 func (sv *stackValue) String() string {
 	if sv.Bytes != nil {
 		return hex.EncodeToString(sv.Bytes)
@@ -144,6 +153,7 @@ type LedgerForLogic interface {
 
 	AssetHolding(addr basics.Address, assetIdx basics.AssetIndex) (basics.AssetHolding, error)
 	AssetParams(aidx basics.AssetIndex) (basics.AssetParams, error)
+	AppParams(aidx basics.AppIndex) (basics.AppParams, basics.Address, error)
 	ApplicationID() basics.AppIndex
 	CreatorAddress() basics.Address
 	OptedIn(addr basics.Address, appIdx basics.AppIndex) (bool, error)
@@ -157,12 +167,23 @@ type LedgerForLogic interface {
 	SetGlobal(key string, value basics.TealValue) error
 	DelGlobal(key string) error
 
-	GetDelta(txn *transactions.Transaction) (evalDelta basics.EvalDelta, err error)
+	GetBox(name string) (value string, exists bool, err error)
+	SetBox(name string, value string) error
+	DelBox(name string) (existed bool, err error)
+
+	GetDelta(txn *transactions.Transaction) (evalDelta transactions.EvalDelta, err error)
+
+	Authorizer(addr basics.Address) (basics.Address, error)
+	Perform(stxn *transactions.SignedTxn, ep *EvalParams) (transactions.ApplyData, error)
 }
 
 // EvalSideEffects contains data returned from evaluation
 type EvalSideEffects struct {
 	scratchSpace scratchSpace
+	innerTxns    []transactions.SignedTxnWithAD
+	logs         []string
+	returnData   []string
+	cost         int
 }
 
 // MakePastSideEffects allocates and initializes a slice of EvalSideEffects of length `size`
@@ -174,6 +195,16 @@ func MakePastSideEffects(size int) (pastSideEffects []EvalSideEffects) {
 	return
 }
 
+// setCost stores the opcode cost incurred by the program
+func (se *EvalSideEffects) setCost(cost int) {
+	se.cost = cost
+}
+
+// Cost returns the opcode cost incurred by the program
+func (se *EvalSideEffects) Cost() int {
+	return se.cost
+}
+
 // getScratchValue loads and clones a stackValue
 // The value is cloned so the original bytes are protected from changes
 func (se *EvalSideEffects) getScratchValue(scratchPos uint8) stackValue {
@@ -185,6 +216,39 @@ func (se *EvalSideEffects) setScratchSpace(scratch scratchSpace) {
 	se.scratchSpace = scratch
 }
 
+// setInnerTxns stores the inner transactions issued by the program
+func (se *EvalSideEffects) setInnerTxns(innerTxns []transactions.SignedTxnWithAD) {
+	se.innerTxns = innerTxns
+}
+
+// InnerTxns returns the inner transactions issued by the program, in the
+// order they were submitted
+func (se *EvalSideEffects) InnerTxns() []transactions.SignedTxnWithAD {
+	return se.innerTxns
+}
+
+// setLogs stores the values logged by the program
+func (se *EvalSideEffects) setLogs(logs []string) {
+	se.logs = logs
+}
+
+// Logs returns the values logged by the program, in the order they were
+// logged
+func (se *EvalSideEffects) Logs() []string {
+	return se.logs
+}
+
+// setReturnData stores the return data left by the program
+func (se *EvalSideEffects) setReturnData(returnData []string) {
+	se.returnData = returnData
+}
+
+// ReturnData returns the values the program left for later transactions
+// in its group with retdata_put, in the order they were put
+func (se *EvalSideEffects) ReturnData() []string {
+	return se.returnData
+}
+
 // EvalParams contains data that comes into condition evaluation.
 type EvalParams struct {
 	// the transaction being evaluated
@@ -205,6 +269,10 @@ type EvalParams struct {
 
 	Ledger LedgerForLogic
 
+	// Specials are the special addresses of the block being evaluated.
+	// They are required for applications to issue inner transactions.
+	Specials *transactions.SpecialAddresses
+
 	// optional debugger
 	Debugger DebuggerHook
 
@@ -213,8 +281,25 @@ type EvalParams struct {
 	// MinTealVersion is nil, we will compute it ourselves
 	MinTealVersion *uint64
 
+	// PooledApplicationBudget is the opcode budget remaining for the
+	// application calls of the group. It is shared by the EvalParams of
+	// every application call in the group, and is nil unless the protocol
+	// enables cost pooling.
+	PooledApplicationBudget *uint64
+
 	// determines eval mode: runModeSignature or runModeApplication
 	runModeFlags runMode
+
+	// appCallers are the applications that issued the inner application
+	// calls leading to this one, outermost first. It is empty for a
+	// top-level application call.
+	appCallers []basics.AppIndex
+
+	// innerAppCallBudget is the opcode budget of an inner application call,
+	// which is what remains of its caller's budget. It applies whether or
+	// not the protocol enables cost pooling, and is nil for a top-level
+	// application call.
+	innerAppCallBudget *uint64
 }
 
 type opEvalFunc func(cx *evalContext)
@@ -254,9 +339,44 @@ func (ep EvalParams) budget() int {
 	if ep.runModeFlags == runModeSignature {
 		return int(ep.Proto.LogicSigMaxCost)
 	}
+	if ep.innerAppCallBudget != nil {
+		return int(*ep.innerAppCallBudget)
+	}
+	if ep.Proto.EnableAppCostPooling && ep.PooledApplicationBudget != nil {
+		return int(*ep.PooledApplicationBudget)
+	}
 	return ep.Proto.MaxAppProgramCost
 }
 
+// MakePooledBudget returns the opcode budget shared by the application calls
+// in txgroup. Each application call contributes MaxAppProgramCost to the
+// pool. It returns nil if the protocol does not enable cost pooling.
+func MakePooledBudget(proto *config.ConsensusParams, txgroup []transactions.SignedTxn) *uint64 {
+	if !proto.EnableAppCostPooling {
+		return nil
+	}
+	var budget uint64
+	for _, stxn := range txgroup {
+		if stxn.Txn.Type == protocol.ApplicationCallTx {
+			budget += uint64(proto.MaxAppProgramCost)
+		}
+	}
+	return &budget
+}
+
+// spendPooledBudget draws the cost of the program from the group's pooled
+// budget, if there is one.
+func (cx *evalContext) spendPooledBudget() {
+	if !cx.Proto.EnableAppCostPooling || cx.PooledApplicationBudget == nil {
+		return
+	}
+	if uint64(cx.cost) > *cx.PooledApplicationBudget {
+		*cx.PooledApplicationBudget = 0
+		return
+	}
+	*cx.PooledApplicationBudget -= uint64(cx.cost)
+}
+
 func (ep EvalParams) log() logging.Logger {
 	if ep.Logger != nil {
 		return ep.Logger
@@ -295,6 +415,19 @@ type evalContext struct {
 	programHashCached crypto.Digest
 	txidCache         map[int]transactions.Txid
 
+	// the inner transaction being assembled by itxn_begin/itxn_field,
+	// and the inner transactions already submitted by itxn_submit
+	subtxn    *transactions.SignedTxn
+	innerTxns []transactions.SignedTxnWithAD
+
+	// values emitted by log, and their total size in bytes
+	logs    []string
+	logSize int
+
+	// values left by retdata_put, and their total size in bytes
+	returnData     []string
+	returnDataSize int
+
 	// Stores state & disassembly for the optional debugger
 	debugState DebugState
 }
@@ -355,9 +488,14 @@ func EvalStateful(program []byte, params EvalParams) (pass bool, err error) {
 	cx.EvalParams = params
 	cx.runModeFlags = runModeApplication
 	pass, err = eval(program, &cx)
+	cx.spendPooledBudget()
 
 	// set side effects
 	cx.PastSideEffects[cx.GroupIndex].setScratchSpace(cx.scratch)
+	cx.PastSideEffects[cx.GroupIndex].setInnerTxns(cx.innerTxns)
+	cx.PastSideEffects[cx.GroupIndex].setLogs(cx.logs)
+	cx.PastSideEffects[cx.GroupIndex].setReturnData(cx.returnData)
+	cx.PastSideEffects[cx.GroupIndex].setCost(cx.cost)
 	return
 }
 
@@ -367,7 +505,14 @@ func Eval(program []byte, params EvalParams) (pass bool, err error) {
 	var cx evalContext
 	cx.EvalParams = params
 	cx.runModeFlags = runModeSignature
-	return eval(program, &cx)
+	pass, err = eval(program, &cx)
+
+	// return data is the only side effect of a logic signature that
+	// later logic signatures in the group may observe
+	if cx.PastSideEffects != nil {
+		cx.PastSideEffects[cx.GroupIndex].setReturnData(cx.returnData)
+	}
+	return
 }
 
 // eval implementation
@@ -1755,6 +1900,40 @@ func (cx *evalContext) assetParamsEnumToValue(params *basics.AssetParams, field
 	return
 }
 
+func (cx *evalContext) appParamsEnumToValue(params *basics.AppParams, creator basics.Address, aidx basics.AppIndex, field uint64) (sv stackValue, err error) {
+	switch AppParamsField(field) {
+	case AppApprovalProgram:
+		sv.Bytes = nilToEmpty(params.ApprovalProgram)
+	case AppClearStateProgram:
+		sv.Bytes = nilToEmpty(params.ClearStateProgram)
+	case AppGlobalNumUint:
+		sv.Uint = params.GlobalStateSchema.NumUint
+	case AppGlobalNumByteSlice:
+		sv.Uint = params.GlobalStateSchema.NumByteSlice
+	case AppLocalNumUint:
+		sv.Uint = params.LocalStateSchema.NumUint
+	case AppLocalNumByteSlice:
+		sv.Uint = params.LocalStateSchema.NumByteSlice
+	case AppExtraProgramPages:
+		sv.Uint = uint64(params.ExtraProgramPages)
+	case AppCreator:
+		sv.Bytes = creator[:]
+	case AppAddress:
+		addr := aidx.Address()
+		sv.Bytes = addr[:]
+	default:
+		err = fmt.Errorf("invalid app params field %d", field)
+		return
+	}
+
+	appParamsField := AppParamsField(field)
+	appParamsFieldType := AppParamsFieldTypes[appParamsField]
+	if !typecheck(appParamsFieldType, sv.argType()) {
+		err = fmt.Errorf("%s expected field type is %s but got %s", appParamsField.String(), appParamsFieldType.String(), sv.argType().String())
+	}
+	return
+}
+
 // TxnFieldToTealValue is a thin wrapper for txnFieldToStack for external use
 func TxnFieldToTealValue(txn *transactions.Transaction, groupIndex int, field TxnField, arrayFieldIdx uint64) (basics.TealValue, error) {
 	cx := evalContext{EvalParams: EvalParams{GroupIndex: groupIndex}}
@@ -1927,6 +2106,9 @@ func (cx *evalContext) txnFieldToStack(txn *transactions.Transaction, field TxnF
 		sv.Uint = boolToUint(txn.AssetFrozen)
 	case ExtraProgramPages:
 		sv.Uint = uint64(txn.ExtraProgramPages)
+	case NumLogs, LastLog:
+		err = fmt.Errorf("%s is only available from itxn", field)
+		return
 	default:
 		err = fmt.Errorf("invalid txn field %d", field)
 		return
@@ -2198,6 +2380,14 @@ func (cx *evalContext) getCreatorAddress() ([]byte, error) {
 	return addr[:], nil
 }
 
+func (cx *evalContext) getApplicationAddress() ([]byte, error) {
+	if cx.Ledger == nil {
+		return nil, fmt.Errorf("ledger not available")
+	}
+	addr := cx.Ledger.ApplicationID().Address()
+	return addr[:], nil
+}
+
 var zeroAddress basics.Address
 
 func (cx *evalContext) globalFieldToStack(field GlobalField) (sv stackValue, err error) {
@@ -2222,6 +2412,8 @@ func (cx *evalContext) globalFieldToStack(field GlobalField) (sv stackValue, err
 		sv.Uint, err = cx.getApplicationID()
 	case CreatorAddress:
 		sv.Bytes, err = cx.getCreatorAddress()
+	case CurrentApplicationAddress:
+		sv.Bytes, err = cx.getApplicationAddress()
 	default:
 		err = fmt.Errorf("invalid global[%d]", field)
 	}
@@ -2306,6 +2498,163 @@ func opEd25519verify(cx *evalContext) {
 	cx.stack = cx.stack[:prev]
 }
 
+// checkEcdsaCurve checks the curve immediate of the current ecdsa_ opcode.
+// Only Secp256k1 is supported.
+func (cx *evalContext) checkEcdsaCurve() error {
+	curve := EcdsaCurve(cx.program[cx.pc+1])
+	if curve != Secp256k1 {
+		return fmt.Errorf("invalid curve %d", curve)
+	}
+	return nil
+}
+
+// ecdsaScalar parses a 32 byte big-endian signature component, which must be
+// in the range [1, N-1] where N is the order of the curve.
+func ecdsaScalar(b []byte) (secp256k1.ModNScalar, error) {
+	var s secp256k1.ModNScalar
+	if len(b) != 32 {
+		return s, errors.New("signature components must be 32 bytes long")
+	}
+	if overflow := s.SetByteSlice(b); overflow || s.IsZero() {
+		return s, errors.New("signature component out of range")
+	}
+	return s, nil
+}
+
+// ecdsaPubKey parses the 32 byte big-endian coordinates of a public key,
+// which must be a point on the curve.
+func ecdsaPubKey(x, y []byte) (*secp256k1.PublicKey, error) {
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("public key coordinates must be 32 bytes long")
+	}
+	uncompressed := make([]byte, 0, 65)
+	uncompressed = append(uncompressed, 0x04)
+	uncompressed = append(uncompressed, x...)
+	uncompressed = append(uncompressed, y...)
+	return secp256k1.ParsePubKey(uncompressed)
+}
+
+// ecdsaPubKeyCoordinates returns the 32 byte big-endian coordinates of a
+// public key
+func ecdsaPubKeyCoordinates(pk *secp256k1.PublicKey) (x []byte, y []byte) {
+	uncompressed := pk.SerializeUncompressed()
+	return uncompressed[1:33], uncompressed[33:65]
+}
+
+func opEcdsaVerify(cx *evalContext) {
+	if err := cx.checkEcdsaCurve(); err != nil {
+		cx.err = err
+		return
+	}
+
+	last := len(cx.stack) - 1 // index of PK y
+	prev := last - 1          // index of PK x
+	pprev := prev - 1         // index of signature s
+	fourth := pprev - 1       // index of signature r
+	fifth := fourth - 1       // index of data
+
+	msg := cx.stack[fifth].Bytes
+	if len(msg) != 32 {
+		cx.err = errors.New("the signed data must be 32 bytes long")
+		return
+	}
+	r, err := ecdsaScalar(cx.stack[fourth].Bytes)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	sigS, err := ecdsaScalar(cx.stack[pprev].Bytes)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	pubKey, err := ecdsaPubKey(cx.stack[prev].Bytes, cx.stack[last].Bytes)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	// Signatures with a high S value are malleable, and rejected just as
+	// Ethereum rejects them.
+	result := !sigS.IsOverHalfOrder() && ecdsa.NewSignature(&r, &sigS).Verify(msg, pubKey)
+
+	cx.stack[fifth].Uint = boolToUint(result)
+	cx.stack[fifth].Bytes = nil
+	cx.stack = cx.stack[:fourth]
+}
+
+func opEcdsaPkDecompress(cx *evalContext) {
+	if err := cx.checkEcdsaCurve(); err != nil {
+		cx.err = err
+		return
+	}
+
+	last := len(cx.stack) - 1 // index of compressed PK
+
+	compressed := cx.stack[last].Bytes
+	if len(compressed) != secp256k1.PubKeyBytesLenCompressed {
+		cx.err = fmt.Errorf("compressed public key must be %d bytes long", secp256k1.PubKeyBytesLenCompressed)
+		return
+	}
+	pubKey, err := secp256k1.ParsePubKey(compressed)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	x, y := ecdsaPubKeyCoordinates(pubKey)
+	cx.stack[last].Bytes = x
+	cx.stack = append(cx.stack, stackValue{Bytes: y})
+}
+
+func opEcdsaPkRecover(cx *evalContext) {
+	if err := cx.checkEcdsaCurve(); err != nil {
+		cx.err = err
+		return
+	}
+
+	last := len(cx.stack) - 1 // index of signature s
+	prev := last - 1          // index of signature r
+	pprev := prev - 1         // index of recovery id
+	fourth := pprev - 1       // index of data
+
+	msg := cx.stack[fourth].Bytes
+	if len(msg) != 32 {
+		cx.err = errors.New("the signed data must be 32 bytes long")
+		return
+	}
+	recid := cx.stack[pprev].Uint
+	if recid > 3 {
+		cx.err = fmt.Errorf("invalid recovery id %d", recid)
+		return
+	}
+	if _, err := ecdsaScalar(cx.stack[prev].Bytes); err != nil {
+		cx.err = err
+		return
+	}
+	if _, err := ecdsaScalar(cx.stack[last].Bytes); err != nil {
+		cx.err = err
+		return
+	}
+
+	// A compact signature is a header byte, 27 plus the recovery id for an
+	// uncompressed key, followed by r and s.
+	compact := make([]byte, 0, 65)
+	compact = append(compact, 27+byte(recid))
+	compact = append(compact, cx.stack[prev].Bytes...)
+	compact = append(compact, cx.stack[last].Bytes...)
+	pubKey, _, err := ecdsa.RecoverCompact(compact, msg)
+	if err != nil {
+		cx.err = fmt.Errorf("pubkey recovery failed: %w", err)
+		return
+	}
+
+	x, y := ecdsaPubKeyCoordinates(pubKey)
+	cx.stack[fourth].Bytes = x
+	cx.stack[pprev] = stackValue{Bytes: y}
+	cx.stack = cx.stack[:prev]
+}
+
 func opLoad(cx *evalContext) {
 	gindex := int(uint(cx.program[cx.pc+1]))
 	cx.stack = append(cx.stack, cx.scratch[gindex])
@@ -2365,6 +2714,95 @@ func opGloads(cx *evalContext) {
 	cx.stack[last] = scratchValue
 }
 
+func opRetdataPut(cx *evalContext) {
+	last := len(cx.stack) - 1
+
+	if len(cx.returnData) >= cx.Proto.MaxReturnDataEntries {
+		cx.err = fmt.Errorf("too many return data entries in program. up to %d is allowed", cx.Proto.MaxReturnDataEntries)
+		return
+	}
+	value := cx.stack[last].Bytes
+	if cx.returnDataSize+len(value) > cx.Proto.MaxReturnDataSize {
+		cx.err = fmt.Errorf("program return data too large. %d bytes > %d bytes limit", cx.returnDataSize+len(value), cx.Proto.MaxReturnDataSize)
+		return
+	}
+	cx.returnDataSize += len(value)
+	cx.returnData = append(cx.returnData, string(value))
+	cx.stack = cx.stack[:last]
+}
+
+func opGretdataImpl(cx *evalContext, groupIdx int, dataIdx int, opName string) (value stackValue, exists bool, err error) {
+	if groupIdx >= len(cx.TxnGroup) {
+		err = fmt.Errorf("%s lookup TxnGroup[%d] but it only has %d", opName, groupIdx, len(cx.TxnGroup))
+		return
+	} else if groupIdx == cx.GroupIndex {
+		err = fmt.Errorf("can't use %s on self", opName)
+		return
+	} else if groupIdx > cx.GroupIndex {
+		err = fmt.Errorf("%s can't get future return data from txn with index %d", opName, groupIdx)
+		return
+	} else if cx.PastSideEffects == nil {
+		err = fmt.Errorf("%s return data is not available", opName)
+		return
+	}
+	// logic signatures are evaluated before, and separately from,
+	// applications, so each only has the return data of its own kind.
+	if cx.runModeFlags == runModeSignature {
+		if len(cx.TxnGroup[groupIdx].Lsig.Logic) == 0 {
+			err = fmt.Errorf("%s can't read the return data of txn %d, which isn't signed by a logic signature", opName, groupIdx)
+			return
+		}
+	} else if cx.TxnGroup[groupIdx].Txn.Type != protocol.ApplicationCallTx {
+		err = fmt.Errorf("%s can't read the return data of txn %d, which isn't an application call", opName, groupIdx)
+		return
+	}
+
+	returnData := cx.PastSideEffects[groupIdx].ReturnData()
+	if dataIdx >= len(returnData) {
+		value.Bytes = []byte{}
+		return
+	}
+	value.Bytes = []byte(returnData[dataIdx])
+	exists = true
+	return
+}
+
+func opGretdata(cx *evalContext) {
+	groupIdx := int(uint(cx.program[cx.pc+1]))
+	dataIdx := int(uint(cx.program[cx.pc+2]))
+	value, exists, err := opGretdataImpl(cx, groupIdx, dataIdx, "gretdata")
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	var isOk stackValue
+	if exists {
+		isOk.Uint = 1
+	}
+
+	cx.stack = append(cx.stack, value, isOk)
+}
+
+func opGretdatas(cx *evalContext) {
+	last := len(cx.stack) - 1
+	groupIdx := int(cx.stack[last].Uint)
+	dataIdx := int(uint(cx.program[cx.pc+1]))
+	value, exists, err := opGretdataImpl(cx, groupIdx, dataIdx, "gretdatas")
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	var isOk stackValue
+	if exists {
+		isOk.Uint = 1
+	}
+
+	cx.stack[last] = value
+	cx.stack = append(cx.stack, isOk)
+}
+
 func opConcat(cx *evalContext) {
 	last := len(cx.stack) - 1
 	prev := last - 1
@@ -2987,3 +3425,601 @@ func opAssetParamsGet(cx *evalContext) {
 	cx.stack[last] = value
 	cx.stack = append(cx.stack, stackValue{Uint: exist})
 }
+
+func opAppParamsGet(cx *evalContext) {
+	last := len(cx.stack) - 1 // app
+
+	if cx.Ledger == nil {
+		cx.err = fmt.Errorf("ledger not available")
+		return
+	}
+
+	paramIdx := uint64(cx.program[cx.pc+1])
+
+	app, err := appReference(cx, cx.stack[last].Uint, true)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	var exist uint64 = 0
+	var value stackValue
+	if params, creator, err := cx.Ledger.AppParams(app); err == nil {
+		// params exist, read the value
+		exist = 1
+		value, err = cx.appParamsEnumToValue(&params, creator, app, paramIdx)
+		if err != nil {
+			cx.err = err
+			return
+		}
+	}
+
+	cx.stack[last] = value
+	cx.stack = append(cx.stack, stackValue{Uint: exist})
+}
+
+func opLog(cx *evalContext) {
+	last := len(cx.stack) - 1
+
+	if len(cx.logs) >= cx.Proto.MaxLogCalls {
+		cx.err = fmt.Errorf("too many log calls in program. up to %d is allowed", cx.Proto.MaxLogCalls)
+		return
+	}
+	msg := cx.stack[last].Bytes
+	if cx.logSize+len(msg) > cx.Proto.MaxLogSize {
+		cx.err = fmt.Errorf("program logs too large. %d bytes > %d bytes limit", cx.logSize+len(msg), cx.Proto.MaxLogSize)
+		return
+	}
+	cx.logSize += len(msg)
+	cx.logs = append(cx.logs, string(msg))
+	cx.stack = cx.stack[:last]
+}
+
+func opTxBegin(cx *evalContext) {
+	if cx.Ledger == nil {
+		cx.err = fmt.Errorf("ledger not available")
+		return
+	}
+	if cx.subtxn != nil {
+		cx.err = errors.New("itxn_begin without itxn_submit")
+		return
+	}
+
+	// Start with defaults that make the app account the sender of a
+	// minimum fee transaction, valid for as long as the top level one
+	cx.subtxn = &transactions.SignedTxn{}
+	cx.subtxn.Txn.Sender = cx.Ledger.ApplicationID().Address()
+	cx.subtxn.Txn.Fee = basics.MicroAlgos{Raw: cx.Proto.MinTxnFee}
+	cx.subtxn.Txn.FirstValid = cx.Txn.Txn.FirstValid
+	cx.subtxn.Txn.LastValid = cx.Txn.Txn.LastValid
+}
+
+// innerTxnAddress resolves an address for an inner transaction field. Like
+// the account arguments of other opcodes, it may be given as an index into
+// the Accounts array or as an address that appears there (or is the
+// Sender). The application's own address may always be used.
+func (cx *evalContext) innerTxnAddress(sv stackValue) (basics.Address, error) {
+	if sv.argType() == StackBytes {
+		var addr basics.Address
+		if len(sv.Bytes) != len(addr) {
+			return addr, fmt.Errorf("address is %d bytes, not %d", len(sv.Bytes), len(addr))
+		}
+		copy(addr[:], sv.Bytes)
+		if addr == cx.Ledger.ApplicationID().Address() {
+			return addr, nil
+		}
+	}
+	addr, _, err := accountReference(cx, sv)
+	return addr, err
+}
+
+func (cx *evalContext) stackIntoTxnField(sv stackValue, field TxnField, txn *transactions.Transaction) (err error) {
+	switch field {
+	case Type:
+		if sv.argType() != StackBytes {
+			return fmt.Errorf("Type arg not a byte array")
+		}
+		txType, ok := innerTxnTypes[string(sv.Bytes)]
+		if !ok {
+			return fmt.Errorf("%s is not a valid Type for itxn_field", sv.Bytes)
+		}
+		txn.Type = txType
+	case TypeEnum:
+		if sv.argType() != StackUint64 {
+			return fmt.Errorf("TypeEnum arg not a uint64")
+		}
+		var txType protocol.TxType
+		if sv.Uint < uint64(len(TxnTypeNames)) {
+			txType = innerTxnTypes[TxnTypeNames[sv.Uint]]
+		}
+		if txType == "" {
+			return fmt.Errorf("%d is not a valid TypeEnum for itxn_field", sv.Uint)
+		}
+		txn.Type = txType
+	case Sender:
+		txn.Sender, err = cx.innerTxnAddress(sv)
+	case Fee:
+		txn.Fee.Raw, err = sv.uint()
+	case Receiver:
+		txn.Receiver, err = cx.innerTxnAddress(sv)
+	case Amount:
+		txn.Amount.Raw, err = sv.uint()
+	case CloseRemainderTo:
+		txn.CloseRemainderTo, err = cx.innerTxnAddress(sv)
+	case XferAsset:
+		var ref uint64
+		ref, err = sv.uint()
+		if err == nil {
+			txn.XferAsset, err = asaReference(cx, ref, false)
+		}
+	case AssetAmount:
+		txn.AssetAmount, err = sv.uint()
+	case AssetSender:
+		txn.AssetSender, err = cx.innerTxnAddress(sv)
+	case AssetReceiver:
+		txn.AssetReceiver, err = cx.innerTxnAddress(sv)
+	case AssetCloseTo:
+		txn.AssetCloseTo, err = cx.innerTxnAddress(sv)
+	case ApplicationID:
+		var ref uint64
+		ref, err = sv.uint()
+		if err == nil {
+			txn.ApplicationID, err = appReference(cx, ref, false)
+		}
+	case OnCompletion:
+		var oc uint64
+		oc, err = sv.uint()
+		if err == nil {
+			txn.OnCompletion = transactions.OnCompletion(oc)
+		}
+	// The array fields append a value each time they are set
+	case ApplicationArgs:
+		if sv.argType() != StackBytes {
+			return fmt.Errorf("ApplicationArgs arg not a byte array")
+		}
+		txn.ApplicationArgs = append(txn.ApplicationArgs, append([]byte(nil), sv.Bytes...))
+	case Accounts:
+		var addr basics.Address
+		addr, err = cx.innerTxnAddress(sv)
+		if err == nil {
+			txn.Accounts = append(txn.Accounts, addr)
+		}
+	case Assets:
+		var ref uint64
+		var asset basics.AssetIndex
+		ref, err = sv.uint()
+		if err == nil {
+			asset, err = asaReference(cx, ref, false)
+		}
+		if err == nil {
+			txn.ForeignAssets = append(txn.ForeignAssets, asset)
+		}
+	case Applications:
+		var ref uint64
+		var app basics.AppIndex
+		ref, err = sv.uint()
+		if err == nil {
+			app, err = appReference(cx, ref, false)
+		}
+		if err == nil {
+			txn.ForeignApps = append(txn.ForeignApps, app)
+		}
+	default:
+		return fmt.Errorf("invalid itxn_field %s", field)
+	}
+	return
+}
+
+func opTxField(cx *evalContext) {
+	if cx.subtxn == nil {
+		cx.err = errors.New("itxn_field without itxn_begin")
+		return
+	}
+	last := len(cx.stack) - 1
+	field := TxnField(uint64(cx.program[cx.pc+1]))
+	version, ok := innerTxnFieldSpecByField[field]
+	if !ok || version > cx.version {
+		cx.err = fmt.Errorf("invalid itxn_field %d", field)
+		return
+	}
+	err := cx.stackIntoTxnField(cx.stack[last], field, &cx.subtxn.Txn)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = cx.stack[:last]
+}
+
+func opTxSubmit(cx *evalContext) {
+	if cx.Ledger == nil {
+		cx.err = fmt.Errorf("ledger not available")
+		return
+	}
+	if cx.subtxn == nil {
+		cx.err = errors.New("itxn_submit without itxn_begin")
+		return
+	}
+	if len(cx.innerTxns) >= cx.Proto.MaxInnerTransactions {
+		cx.err = fmt.Errorf("itxn_submit exceeds MaxInnerTransactions (%d)", cx.Proto.MaxInnerTransactions)
+		return
+	}
+	if cx.Specials == nil {
+		cx.err = errors.New("itxn_submit without special addresses")
+		return
+	}
+
+	// The app account may only send from accounts it controls: its own,
+	// or ones that have been rekeyed to it
+	appAddr := cx.Ledger.ApplicationID().Address()
+	authorizer, err := cx.Ledger.Authorizer(cx.subtxn.Txn.Sender)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	if authorizer != appAddr {
+		cx.err = fmt.Errorf("unauthorized inner transaction sender %s", cx.subtxn.Txn.Sender)
+		return
+	}
+	if authorizer != cx.subtxn.Txn.Sender {
+		cx.subtxn.AuthAddr = authorizer
+	}
+
+	if cx.subtxn.Txn.Fee.Raw < cx.Proto.MinTxnFee {
+		cx.err = fmt.Errorf("itxn_submit fee %d below minimum %d", cx.subtxn.Txn.Fee.Raw, cx.Proto.MinTxnFee)
+		return
+	}
+	err = cx.subtxn.Txn.WellFormed(*cx.Specials, *cx.Proto)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	// An inner application call draws its opcode budget from what remains
+	// of this program's, so that it fails before its effects are applied
+	// if it overruns it, and its cost is charged back to this program
+	var budget *uint64
+	appCall := cx.subtxn.Txn.Type == protocol.ApplicationCallTx
+	if appCall {
+		err = cx.checkInnerAppCall(&cx.subtxn.Txn)
+		if err != nil {
+			cx.err = err
+			return
+		}
+		remaining := uint64(cx.budget() - cx.cost)
+		budget = &remaining
+	}
+
+	ep := cx.innerEvalParams(cx.subtxn, budget)
+	ad, err := cx.Ledger.Perform(cx.subtxn, ep)
+	if err != nil {
+		cx.err = err
+		return
+	}
+
+	if appCall {
+		cx.cost += ep.PastSideEffects[0].Cost()
+		if cx.cost > cx.budget() {
+			cx.err = fmt.Errorf("pc=%3d dynamic cost budget of %d exceeded by inner application call", cx.pc, cx.budget())
+			return
+		}
+	}
+
+	cx.innerTxns = append(cx.innerTxns, transactions.SignedTxnWithAD{
+		SignedTxn: *cx.subtxn,
+		ApplyData: ad,
+	})
+	cx.subtxn = nil
+}
+
+// checkInnerAppCall enforces the rules for application calls issued by an
+// application. They may only call an existing application with NoOp, OptIn
+// or CloseOut, may not reenter an application that is already on the call
+// stack, and may not nest deeper than MaxAppCallDepth.
+func (cx *evalContext) checkInnerAppCall(txn *transactions.Transaction) error {
+	// the applications on the call stack, including this one
+	depth := len(cx.appCallers) + 1
+	if depth >= cx.Proto.MaxAppCallDepth {
+		return fmt.Errorf("appl depth (%d) exceeds MaxAppCallDepth (%d)", depth+1, cx.Proto.MaxAppCallDepth)
+	}
+	if txn.ApplicationID == 0 {
+		return errors.New("inner appl may not create an application")
+	}
+	switch txn.OnCompletion {
+	case transactions.NoOpOC, transactions.OptInOC, transactions.CloseOutOC:
+	default:
+		return fmt.Errorf("inner appl may not use OnCompletion %s", txn.OnCompletion)
+	}
+	if txn.ApplicationID == cx.Ledger.ApplicationID() {
+		return fmt.Errorf("app %d may not call itself", txn.ApplicationID)
+	}
+	for _, caller := range cx.appCallers {
+		if txn.ApplicationID == caller {
+			return fmt.Errorf("app %d is already on the call stack", txn.ApplicationID)
+		}
+	}
+	return nil
+}
+
+// innerEvalParams returns the EvalParams under which an inner transaction is
+// applied. The transaction forms a group of its own, and an application call
+// is evaluated with this application added to its callers.
+func (cx *evalContext) innerEvalParams(stxn *transactions.SignedTxn, budget *uint64) *EvalParams {
+	callers := make([]basics.AppIndex, len(cx.appCallers), len(cx.appCallers)+1)
+	copy(callers, cx.appCallers)
+	return &EvalParams{
+		Txn:                stxn,
+		Proto:              cx.Proto,
+		TxnGroup:           []transactions.SignedTxn{*stxn},
+		PastSideEffects:    MakePastSideEffects(1),
+		Logger:             cx.Logger,
+		Specials:           cx.Specials,
+		appCallers:         append(callers, cx.Ledger.ApplicationID()),
+		innerAppCallBudget: budget,
+	}
+}
+
+func opItxn(cx *evalContext) {
+	field := TxnField(uint64(cx.program[cx.pc+1]))
+	fs, ok := txnFieldSpecByField[field]
+	if !ok || fs.version > cx.version {
+		cx.err = fmt.Errorf("invalid itxn field %d", field)
+		return
+	}
+	_, ok = txnaFieldSpecByField[field]
+	if ok {
+		cx.err = fmt.Errorf("invalid itxn field %d", field)
+		return
+	}
+	if len(cx.innerTxns) == 0 {
+		cx.err = errors.New("no inner transaction available")
+		return
+	}
+
+	itxn := &cx.innerTxns[len(cx.innerTxns)-1]
+	var sv stackValue
+	var err error
+	switch field {
+	case TxID:
+		// inner transactions are not part of TxnGroup, so skip the cache
+		txid := itxn.Txn.ID()
+		sv.Bytes = txid[:]
+	case GroupIndex:
+		err = fmt.Errorf("itxn does not support %s", field)
+	case NumLogs:
+		sv.Uint = uint64(len(itxn.EvalDelta.Logs))
+	case LastLog:
+		if len(itxn.EvalDelta.Logs) == 0 {
+			sv.Bytes = []byte{}
+		} else {
+			sv.Bytes = []byte(itxn.EvalDelta.Logs[len(itxn.EvalDelta.Logs)-1])
+		}
+	default:
+		sv, err = cx.txnFieldToStack(&itxn.Txn, field, 0, 0)
+	}
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = append(cx.stack, sv)
+}
+
+// boxGet fetches the named box of the current application, failing if the
+// ledger is unavailable.
+func (cx *evalContext) boxGet(name string) (string, bool, error) {
+	if cx.Ledger == nil {
+		return "", false, fmt.Errorf("ledger not available")
+	}
+	if len(name) == 0 || len(name) > cx.Proto.MaxAppKeyLen {
+		return "", false, fmt.Errorf("invalid box name length %d", len(name))
+	}
+	return cx.Ledger.GetBox(name)
+}
+
+func (cx *evalContext) boxMustGet(name string) (string, error) {
+	value, exists, err := cx.boxGet(name)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("no such box %#x", name)
+	}
+	return value, nil
+}
+
+func (cx *evalContext) boxPut(name string, value string) error {
+	if uint64(len(value)) > cx.Proto.MaxBoxSize {
+		return fmt.Errorf("box size %d > %d", len(value), cx.Proto.MaxBoxSize)
+	}
+	return cx.Ledger.SetBox(name, value)
+}
+
+func opBoxCreate(cx *evalContext) {
+	last := len(cx.stack) - 1 // size
+	prev := last - 1          // name
+
+	name := string(cx.stack[prev].Bytes)
+	size := cx.stack[last].Uint
+
+	value, exists, err := cx.boxGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	if exists {
+		if uint64(len(value)) != size {
+			cx.err = fmt.Errorf("box size mismatch %d %d", len(value), size)
+			return
+		}
+		cx.stack[prev].Bytes = nil
+		cx.stack[prev].Uint = 0
+		cx.stack = cx.stack[:last]
+		return
+	}
+	if size > cx.Proto.MaxBoxSize {
+		cx.err = fmt.Errorf("box size %d > %d", size, cx.Proto.MaxBoxSize)
+		return
+	}
+	err = cx.boxPut(name, string(make([]byte, size)))
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack[prev].Bytes = nil
+	cx.stack[prev].Uint = 1
+	cx.stack = cx.stack[:last]
+}
+
+func opBoxExtract(cx *evalContext) {
+	last := len(cx.stack) - 1 // length
+	prev := last - 1          // start
+	pprev := prev - 1         // name
+
+	name := string(cx.stack[pprev].Bytes)
+	start := cx.stack[prev].Uint
+	length := cx.stack[last].Uint
+
+	value, err := cx.boxMustGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	end, overflow := basics.OAdd(start, length)
+	if overflow || end > uint64(len(value)) {
+		cx.err = fmt.Errorf("extract range beyond box")
+		return
+	}
+	if length > MaxStringSize {
+		cx.err = fmt.Errorf("box_extract attempted to create a too large string")
+		return
+	}
+	cx.stack[pprev].Bytes = []byte(value[start:end])
+	cx.stack = cx.stack[:prev]
+}
+
+func opBoxReplace(cx *evalContext) {
+	last := len(cx.stack) - 1 // replacement
+	prev := last - 1          // start
+	pprev := prev - 1         // name
+
+	name := string(cx.stack[pprev].Bytes)
+	start := cx.stack[prev].Uint
+	replacement := cx.stack[last].Bytes
+
+	value, err := cx.boxMustGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	end, overflow := basics.OAdd(start, uint64(len(replacement)))
+	if overflow || end > uint64(len(value)) {
+		cx.err = fmt.Errorf("replacement range beyond box")
+		return
+	}
+	updated := make([]byte, len(value))
+	copy(updated, value)
+	copy(updated[start:], replacement)
+	err = cx.boxPut(name, string(updated))
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = cx.stack[:pprev]
+}
+
+func opBoxDel(cx *evalContext) {
+	last := len(cx.stack) - 1 // name
+
+	name := string(cx.stack[last].Bytes)
+	if _, _, err := cx.boxGet(name); err != nil {
+		cx.err = err
+		return
+	}
+	existed, err := cx.Ledger.DelBox(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack[last].Bytes = nil
+	cx.stack[last].Uint = boolToUint(existed)
+}
+
+func opBoxLen(cx *evalContext) {
+	last := len(cx.stack) - 1 // name
+
+	name := string(cx.stack[last].Bytes)
+	value, exists, err := cx.boxGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack[last].Bytes = nil
+	cx.stack[last].Uint = uint64(len(value))
+	cx.stack = append(cx.stack, stackValue{Uint: boolToUint(exists)})
+}
+
+func opBoxGet(cx *evalContext) {
+	last := len(cx.stack) - 1 // name
+
+	name := string(cx.stack[last].Bytes)
+	value, exists, err := cx.boxGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	if len(value) > MaxStringSize {
+		cx.err = fmt.Errorf("box_get produced a too big (%d) byte-array", len(value))
+		return
+	}
+	cx.stack[last].Bytes = []byte(value)
+	cx.stack = append(cx.stack, stackValue{Uint: boolToUint(exists)})
+}
+
+func opBoxPut(cx *evalContext) {
+	last := len(cx.stack) - 1 // value
+	prev := last - 1          // name
+
+	name := string(cx.stack[prev].Bytes)
+	value := cx.stack[last].Bytes
+
+	current, exists, err := cx.boxGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	if exists && len(current) != len(value) {
+		cx.err = fmt.Errorf("box_put wrong size %d != %d", len(current), len(value))
+		return
+	}
+	err = cx.boxPut(name, string(value))
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = cx.stack[:prev]
+}
+
+func opBoxResize(cx *evalContext) {
+	last := len(cx.stack) - 1 // size
+	prev := last - 1          // name
+
+	name := string(cx.stack[prev].Bytes)
+	size := cx.stack[last].Uint
+
+	value, err := cx.boxMustGet(name)
+	if err != nil {
+		cx.err = err
+		return
+	}
+	if size > cx.Proto.MaxBoxSize {
+		cx.err = fmt.Errorf("box size %d > %d", size, cx.Proto.MaxBoxSize)
+		return
+	}
+	resized := make([]byte, size)
+	copy(resized, value)
+	err = cx.boxPut(name, string(resized))
+	if err != nil {
+		cx.err = err
+		return
+	}
+	cx.stack = cx.stack[:prev]
+}
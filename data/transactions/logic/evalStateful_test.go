@@ -33,6 +33,7 @@ import (
 
 type balanceRecord struct {
 	addr     basics.Address
+	auth     basics.Address
 	balance  uint64
 	locals   map[basics.AppIndex]basics.TealKeyValue
 	holdings map[uint64]basics.AssetHolding
@@ -54,6 +55,7 @@ type testLedger struct {
 	appID             basics.AppIndex
 	creatorAddr       basics.Address
 	mods              map[basics.AppIndex]map[string]basics.ValueDelta
+	boxes             map[basics.AppIndex]map[string]string
 }
 
 func makeSchemas(li uint64, lb uint64, gi uint64, gb uint64) basics.StateSchemas {
@@ -84,6 +86,7 @@ func makeTestLedger(balances map[basics.Address]uint64) *testLedger {
 	l.assets = make(map[basics.AssetIndex]basics.AssetParams)
 	l.trackedCreatables = make(map[int]basics.CreatableIndex)
 	l.mods = make(map[basics.AppIndex]map[string]basics.ValueDelta)
+	l.boxes = make(map[basics.AppIndex]map[string]string)
 	return l
 }
 
@@ -128,6 +131,15 @@ func (l *testLedger) setHolding(addr basics.Address, assetID uint64, amount uint
 	l.balances[addr] = br
 }
 
+func (l *testLedger) rekey(addr basics.Address, auth basics.Address) {
+	br, ok := l.balances[addr]
+	if !ok {
+		br = makeBalanceRecord(addr, 0)
+	}
+	br.auth = auth
+	l.balances[addr] = br
+}
+
 func (l *testLedger) Round() basics.Round {
 	return basics.Round(rand.Uint32() + 1)
 }
@@ -384,6 +396,13 @@ func (l *testLedger) AssetParams(assetID basics.AssetIndex) (basics.AssetParams,
 	return basics.AssetParams{}, fmt.Errorf("no such asset")
 }
 
+func (l *testLedger) AppParams(appID basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	if params, ok := l.applications[appID]; ok {
+		return params.AppParams, params.Creator, nil
+	}
+	return basics.AppParams{}, basics.Address{}, fmt.Errorf("no such app")
+}
+
 func (l *testLedger) ApplicationID() basics.AppIndex {
 	return l.appID
 }
@@ -406,7 +425,26 @@ func (l *testLedger) GlobalSchema() basics.StateSchema {
 	}
 }
 
-func (l *testLedger) GetDelta(txn *transactions.Transaction) (evalDelta basics.EvalDelta, err error) {
+func (l *testLedger) GetBox(name string) (string, bool, error) {
+	value, ok := l.boxes[l.appID][name]
+	return value, ok, nil
+}
+
+func (l *testLedger) SetBox(name string, value string) error {
+	if _, ok := l.boxes[l.appID]; !ok {
+		l.boxes[l.appID] = make(map[string]string)
+	}
+	l.boxes[l.appID][name] = value
+	return nil
+}
+
+func (l *testLedger) DelBox(name string) (bool, error) {
+	_, ok := l.boxes[l.appID][name]
+	delete(l.boxes[l.appID], name)
+	return ok, nil
+}
+
+func (l *testLedger) GetDelta(txn *transactions.Transaction) (evalDelta transactions.EvalDelta, err error) {
 	if tkv, ok := l.mods[l.appID]; ok {
 		evalDelta.GlobalDelta = tkv
 	}
@@ -428,6 +466,108 @@ func (l *testLedger) GetDelta(txn *transactions.Transaction) (evalDelta basics.E
 	return
 }
 
+func (l *testLedger) Authorizer(addr basics.Address) (basics.Address, error) {
+	br, ok := l.balances[addr]
+	if !ok || br.auth.IsZero() {
+		return addr, nil
+	}
+	return br.auth, nil
+}
+
+func (l *testLedger) move(from basics.Address, to basics.Address, amount uint64) error {
+	fbr, ok := l.balances[from]
+	if !ok {
+		fbr = makeBalanceRecord(from, 0)
+	}
+	if fbr.balance < amount {
+		return fmt.Errorf("insufficient balance")
+	}
+	fbr.balance -= amount
+	l.balances[from] = fbr
+
+	tbr, ok := l.balances[to]
+	if !ok {
+		tbr = makeBalanceRecord(to, 0)
+	}
+	tbr.balance += amount
+	l.balances[to] = tbr
+	return nil
+}
+
+func (l *testLedger) axfer(from basics.Address, to basics.Address, aid basics.AssetIndex, amount uint64) error {
+	fbr, ok := l.balances[from]
+	if !ok {
+		return fmt.Errorf("no such address")
+	}
+	fholding, ok := fbr.holdings[uint64(aid)]
+	if !ok {
+		return fmt.Errorf("sender not opted in")
+	}
+	if fholding.Amount < amount {
+		return fmt.Errorf("insufficient asset balance")
+	}
+	tbr, ok := l.balances[to]
+	if !ok {
+		return fmt.Errorf("no such address")
+	}
+	tholding, ok := tbr.holdings[uint64(aid)]
+	if !ok {
+		return fmt.Errorf("receiver not opted in")
+	}
+	fholding.Amount -= amount
+	fbr.holdings[uint64(aid)] = fholding
+	tholding.Amount += amount
+	tbr.holdings[uint64(aid)] = tholding
+	return nil
+}
+
+// call evaluates the approval program of appID as the application called by
+// an inner transaction
+func (l *testLedger) call(appID basics.AppIndex, ep *EvalParams) (transactions.ApplyData, error) {
+	params, ok := l.applications[appID]
+	if !ok {
+		return transactions.ApplyData{}, fmt.Errorf("no such app")
+	}
+
+	caller, creator := l.appID, l.creatorAddr
+	l.appID, l.creatorAddr = appID, params.Creator
+	defer func() {
+		l.appID, l.creatorAddr = caller, creator
+	}()
+
+	ep.Ledger = l
+	pass, err := EvalStateful(params.ApprovalProgram, *ep)
+	if err != nil {
+		return transactions.ApplyData{}, err
+	}
+	if !pass {
+		return transactions.ApplyData{}, fmt.Errorf("transaction rejected by ApprovalProgram")
+	}
+	var ad transactions.ApplyData
+	ad.EvalDelta.InnerTxns = ep.PastSideEffects[0].InnerTxns()
+	ad.EvalDelta.Logs = ep.PastSideEffects[0].Logs()
+	return ad, nil
+}
+
+func (l *testLedger) Perform(stxn *transactions.SignedTxn, ep *EvalParams) (transactions.ApplyData, error) {
+	txn := &stxn.Txn
+	err := l.move(txn.Sender, ep.Specials.FeeSink, txn.Fee.Raw)
+	if err != nil {
+		return transactions.ApplyData{}, err
+	}
+	switch txn.Type {
+	case protocol.PaymentTx:
+		err = l.move(txn.Sender, txn.Receiver, txn.Amount.Raw)
+	case protocol.AssetTransferTx:
+		err = l.axfer(txn.Sender, txn.AssetReceiver, txn.XferAsset, txn.AssetAmount)
+	case protocol.ApplicationCallTx:
+		return l.call(txn.ApplicationID, ep)
+	default:
+		err = fmt.Errorf("%s is not supported by testLedger", txn.Type)
+	}
+	return transactions.ApplyData{}, err
+}
+
 func TestEvalModes(t *testing.T) {
 	t.Parallel()
 	// ed25519verify and err are tested separately below
@@ -752,7 +892,7 @@ func TestBalance(t *testing.T) {
 	require.True(t, pass)
 }
 
-func testApp(t *testing.T, program string, ep EvalParams, problems ...string) basics.EvalDelta {
+func testApp(t *testing.T, program string, ep EvalParams, problems ...string) transactions.EvalDelta {
 	ops := testProg(t, program, ep.Proto.LogicSigVersion)
 	err := CheckStateful(ops.Program, ep)
 	require.NoError(t, err)
@@ -787,7 +927,7 @@ func testApp(t *testing.T, program string, ep EvalParams, problems ...string) ba
 		require.Empty(t, delta.LocalDeltas)
 		return delta
 	}
-	return basics.EvalDelta{}
+	return transactions.EvalDelta{}
 }
 
 func TestMinBalance(t *testing.T) {
@@ -2663,6 +2803,12 @@ func TestReturnTypes(t *testing.T) {
 	ep.Txn.Txn.ForeignAssets = []basics.AssetIndex{basics.AssetIndex(1), basics.AssetIndex(1)}
 	ep.GroupIndex = 1
 	ep.PastSideEffects = MakePastSideEffects(len(txgroup))
+	// gretdata in signature mode reads the return data of a logic signature
+	txgroup[0].Lsig.Logic = []byte{LogicVersion}
+	// inner transactions inherit the validity window of the sample txn
+	ep.Proto.MaxTxnLife = uint64(txn.Txn.LastValid - txn.Txn.FirstValid)
+	// ecdsa_pk_recover costs more than a single application call may spend
+	ep.Proto.MaxAppProgramCost = int(ep.Proto.LogicSigMaxCost)
 	txn.Lsig.Args = [][]byte{
 		[]byte("aoeu"),
 		[]byte("aoeu"),
@@ -2695,40 +2841,50 @@ func TestReturnTypes(t *testing.T) {
 	require.NoError(t, err)
 	algoValue := basics.TealValue{Type: basics.TealUintType, Uint: 0x77}
 	ledger.balances[txn.Txn.Receiver].locals[1][string(key)] = algoValue
+	ledger.balances[basics.AppIndex(1).Address()] = makeBalanceRecord(basics.AppIndex(1).Address(), 1000000)
 
 	ep.Ledger = ledger
 
 	specialCmd := map[string]string{
-		"txn":               "txn Sender",
-		"txna":              "txna ApplicationArgs 0",
-		"gtxn":              "gtxn 0 Sender",
-		"gtxna":             "gtxna 0 ApplicationArgs 0",
-		"global":            "global MinTxnFee",
-		"arg":               "arg 0",
-		"load":              "load 0",
-		"store":             "store 0",
-		"gload":             "gload 0 0",
-		"gloads":            "gloads 0",
-		"gaid":              "gaid 0",
-		"dig":               "dig 0",
-		"intc":              "intcblock 0; intc 0",
-		"intc_0":            "intcblock 0; intc_0",
-		"intc_1":            "intcblock 0 0; intc_1",
-		"intc_2":            "intcblock 0 0 0; intc_2",
-		"intc_3":            "intcblock 0 0 0 0; intc_3",
-		"bytec":             "bytecblock 0x32; bytec 0",
-		"bytec_0":           "bytecblock 0x32; bytec_0",
-		"bytec_1":           "bytecblock 0x32 0x33; bytec_1",
-		"bytec_2":           "bytecblock 0x32 0x33 0x34; bytec_2",
-		"bytec_3":           "bytecblock 0x32 0x33 0x34 0x35; bytec_3",
-		"substring":         "substring 0 2",
-		"ed25519verify":     "pop; pop; pop; int 1", // ignore
-		"asset_params_get":  "asset_params_get AssetTotal",
-		"asset_holding_get": "asset_holding_get AssetBalance",
-		"gtxns":             "gtxns Sender",
-		"gtxnsa":            "gtxnsa ApplicationArgs 0",
-		"pushint":           "pushint 7272",
-		"pushbytes":         `pushbytes "jojogoodgorilla"`,
+		"txn":                 "txn Sender",
+		"txna":                "txna ApplicationArgs 0",
+		"gtxn":                "gtxn 0 Sender",
+		"gtxna":               "gtxna 0 ApplicationArgs 0",
+		"global":              "global MinTxnFee",
+		"arg":                 "arg 0",
+		"load":                "load 0",
+		"store":               "store 0",
+		"gload":               "gload 0 0",
+		"gloads":              "gloads 0",
+		"gaid":                "gaid 0",
+		"gretdata":            "gretdata 0 0",
+		"gretdatas":           "pop; int 0; gretdatas 0",
+		"dig":                 "dig 0",
+		"intc":                "intcblock 0; intc 0",
+		"intc_0":              "intcblock 0; intc_0",
+		"intc_1":              "intcblock 0 0; intc_1",
+		"intc_2":              "intcblock 0 0 0; intc_2",
+		"intc_3":              "intcblock 0 0 0 0; intc_3",
+		"bytec":               "bytecblock 0x32; bytec 0",
+		"bytec_0":             "bytecblock 0x32; bytec_0",
+		"bytec_1":             "bytecblock 0x32 0x33; bytec_1",
+		"bytec_2":             "bytecblock 0x32 0x33 0x34; bytec_2",
+		"bytec_3":             "bytecblock 0x32 0x33 0x34 0x35; bytec_3",
+		"substring":           "substring 0 2",
+		"ed25519verify":       "pop; pop; pop; int 1",           // ignore
+		"ecdsa_verify":        "pop; pop; pop; pop; pop; int 1", // ignore
+		"ecdsa_pk_decompress": fmt.Sprintf("pop; byte %s; ecdsa_pk_decompress Secp256k1", ecdsaCompressed),
+		"ecdsa_pk_recover":    fmt.Sprintf("pop; pop; pop; pop; byte %s; int 0; byte %s; byte %s; ecdsa_pk_recover Secp256k1", ecdsaMsg, ecdsaR, ecdsaS),
+		"asset_params_get":    "asset_params_get AssetTotal",
+		"app_params_get":      "app_params_get AppGlobalNumUint",
+		"asset_holding_get":   "asset_holding_get AssetBalance",
+		"gtxns":               "gtxns Sender",
+		"gtxnsa":              "gtxnsa ApplicationArgs 0",
+		"pushint":             "pushint 7272",
+		"pushbytes":           `pushbytes "jojogoodgorilla"`,
+		"itxn_field":          "itxn_field Amount",
+		"itxn":                "itxn_begin; pushbytes \"pay\"; itxn_field Type; itxn_submit; itxn Sender",
+		"box_extract":         "pop; pop; pop; byte \"extract\"; dup; int 4; box_create; pop; int 1; int 2; box_extract",
 	}
 
 	byName := OpsByName[LogicVersion]
@@ -2763,7 +2919,7 @@ func TestReturnTypes(t *testing.T) {
 					fmt.Sprintf("%s expected to return %d values but stack has %d", spec.Name, len(spec.Returns), len(cx.stack)),
 				)
 				for i := 0; i < len(spec.Returns); i++ {
-					sp := len(cx.stack) - 1 - i
+					sp := len(cx.stack) - len(spec.Returns) + i
 					stackType := cx.stack[sp].argType()
 					retType := spec.Returns[i]
 					require.True(
@@ -2863,3 +3019,507 @@ int 42
 	require.NoError(t, err)
 	require.True(t, pass)
 }
+
+func makeInnerTxnTest() (EvalParams, *testLedger) {
+	var txn transactions.SignedTxn
+	copy(txn.Txn.Sender[:], []byte("aoeuiaoeuiaoeuiaoeuiaoeuiaoeui00"))
+	txn.Txn.Type = protocol.ApplicationCallTx
+	txn.Txn.ApplicationID = 888
+	txn.Txn.FirstValid = 10
+	txn.Txn.LastValid = 20
+	receiver := basics.Address{}
+	copy(receiver[:], []byte("aoeuiaoeuiaoeuiaoeuiaoeuiaoeui01"))
+	txn.Txn.Accounts = []basics.Address{receiver}
+	txn.Txn.ForeignAssets = []basics.AssetIndex{5}
+
+	ep := defaultEvalParams(nil, &txn)
+	ep.TxnGroup = []transactions.SignedTxn{txn}
+	ep.Proto.MaxTxnLife = 100
+	ep.Proto.Asset = true
+
+	ledger := makeTestLedger(map[basics.Address]uint64{
+		txn.Txn.Sender:                 1000000,
+		basics.AppIndex(888).Address(): 1000000,
+	})
+	ledger.newApp(txn.Txn.Sender, 888, makeSchemas(0, 0, 0, 0))
+	ep.Ledger = ledger
+	return ep, ledger
+}
+
+func TestInnerTxnPay(t *testing.T) {
+	t.Parallel()
+
+	ep, ledger := makeInnerTxnTest()
+	appAddr := basics.AppIndex(888).Address()
+	receiver := ep.Txn.Txn.Accounts[0]
+
+	pay := `
+itxn_begin
+byte "pay"
+itxn_field Type
+int 5000
+itxn_field Amount
+int 1
+itxn_field Receiver
+itxn_submit
+itxn Amount
+int 5000
+==
+itxn Receiver
+txn Accounts 1
+==
+&&
+itxn Sender
+global CurrentApplicationAddress
+==
+&&
+itxn Fee
+global MinTxnFee
+==
+&&
+`
+	testApp(t, pay, ep)
+	require.Equal(t, uint64(1000000-5000-1001), ledger.balances[appAddr].balance)
+	require.Equal(t, uint64(5000), ledger.balances[receiver].balance)
+
+	inner := ep.PastSideEffects[ep.GroupIndex].InnerTxns()
+	require.Len(t, inner, 1)
+	require.Equal(t, protocol.PaymentTx, inner[0].Txn.Type)
+	require.Equal(t, appAddr, inner[0].Txn.Sender)
+	require.Equal(t, receiver, inner[0].Txn.Receiver)
+	require.Equal(t, basics.Round(10), inner[0].Txn.FirstValid)
+	require.Equal(t, basics.Round(20), inner[0].Txn.LastValid)
+	require.True(t, inner[0].AuthAddr.IsZero())
+
+	// the app may pay from an account that has been rekeyed to it
+	sender := `
+itxn_begin
+int pay
+itxn_field TypeEnum
+txn Sender
+itxn_field Sender
+global CurrentApplicationAddress
+itxn_field Receiver
+int 1
+itxn_field Amount
+itxn_submit
+int 1
+`
+	testApp(t, sender, ep, "unauthorized inner transaction sender")
+	ledger.rekey(ep.Txn.Txn.Sender, appAddr)
+	testApp(t, sender, ep)
+	inner = ep.PastSideEffects[ep.GroupIndex].InnerTxns()
+	require.Len(t, inner, 1)
+	require.Equal(t, ep.Txn.Txn.Sender, inner[0].Txn.Sender)
+	require.Equal(t, appAddr, inner[0].AuthAddr)
+}
+
+func TestInnerTxnAxfer(t *testing.T) {
+	t.Parallel()
+
+	ep, ledger := makeInnerTxnTest()
+	appAddr := basics.AppIndex(888).Address()
+	receiver := ep.Txn.Txn.Accounts[0]
+	ledger.newAsset(appAddr, 5, basics.AssetParams{Total: 1000})
+	ledger.setHolding(receiver, 5, 0, false)
+
+	axfer := `
+itxn_begin
+int axfer
+itxn_field TypeEnum
+int 5
+itxn_field XferAsset
+int 10
+itxn_field AssetAmount
+txn Accounts 1
+itxn_field AssetReceiver
+itxn_submit
+itxn XferAsset
+int 5
+==
+`
+	testApp(t, axfer, ep)
+	require.Equal(t, uint64(990), ledger.balances[appAddr].holdings[5].Amount)
+	require.Equal(t, uint64(10), ledger.balances[receiver].holdings[5].Amount)
+
+	// assets must be available, like other asset references
+	testApp(t, "itxn_begin; int 6; itxn_field XferAsset; int 1", ep, "invalid Asset reference 6")
+}
+
+func TestInnerTxnErrors(t *testing.T) {
+	t.Parallel()
+
+	ep, _ := makeInnerTxnTest()
+
+	testApp(t, "int 1; itxn_field Amount; int 1", ep, "itxn_field without itxn_begin")
+	testApp(t, "itxn_submit; int 1", ep, "itxn_submit without itxn_begin")
+	testApp(t, "itxn_begin; itxn_begin; int 1", ep, "itxn_begin without itxn_submit")
+	testApp(t, "itxn Sender; int 1", ep, "no inner transaction available")
+
+	testApp(t, `itxn_begin; byte "keyreg"; itxn_field Type; int 1`, ep, "keyreg is not a valid Type")
+	testApp(t, "itxn_begin; int keyreg; itxn_field TypeEnum; int 1", ep, "2 is not a valid TypeEnum")
+	testApp(t, "itxn_begin; int 7; itxn_field TypeEnum; int 1", ep, "7 is not a valid TypeEnum")
+	testApp(t, "itxn_begin; int 1; itxn_field Type; int 1", ep, "Type arg not a byte array")
+	testApp(t, `itxn_begin; byte "pay"; itxn_field Amount; int 1`, ep, "not a uint64")
+
+	testApp(t, "itxn_begin; byte 0x01; itxn_field Receiver; int 1", ep, "address is 1 bytes")
+	testApp(t, "itxn_begin; int 2; itxn_field Receiver; int 1", ep, "invalid Account reference 2")
+	testApp(t, "itxn_begin; global ZeroAddress; itxn_field Receiver; int 1", ep, "invalid Account reference")
+
+	testApp(t, `itxn_begin; itxn_submit; int 1`, ep, "unknown tx type")
+	testApp(t, `itxn_begin; byte "pay"; itxn_field Type; int 1; itxn_field Fee; itxn_submit; int 1`, ep,
+		"fee 1 below minimum 1001")
+	testApp(t, `itxn_begin; byte "pay"; itxn_field Type; int 2000000; itxn_field Amount; itxn_submit; int 1`, ep,
+		"insufficient balance")
+
+	submit := `itxn_begin; byte "pay"; itxn_field Type; itxn_submit;`
+	testApp(t, strings.Repeat(submit, 4)+"int 1", ep)
+	testApp(t, strings.Repeat(submit, 5)+"int 1", ep, "exceeds MaxInnerTransactions (4)")
+
+	ep.Specials = nil
+	testApp(t, submit+"int 1", ep, "itxn_submit without special addresses")
+}
+
+func TestInnerTxnAssembly(t *testing.T) {
+	t.Parallel()
+
+	testProg(t, "itxn_begin", 4, expect{1, "itxn_begin opcode was introduced in TEAL v5"})
+	testProg(t, "int 1; itxn_field Amount", 4, expect{2, "itxn_field opcode was introduced in TEAL v5"})
+	testProg(t, "itxn Sender", 4, expect{1, "itxn opcode was introduced in TEAL v5"})
+
+	testLine(t, "itxn_field Note", AssemblerMaxVersion, "itxn_field \"Note\" is not allowed")
+	testLine(t, "itxn_field Nope", AssemblerMaxVersion, "itxn_field unknown field: \"Nope\"")
+	testLine(t, "itxn Accounts", AssemblerMaxVersion, "found array field \"Accounts\" in itxn op")
+	testLine(t, "itxn Sender 1", AssemblerMaxVersion, "itxn expects one argument")
+
+	ops := testProg(t, "itxn_begin; int 1; itxn_field Amount; itxn_submit; itxn Amount", AssemblerMaxVersion)
+	dis, err := Disassemble(ops.Program)
+	require.NoError(t, err)
+	require.Contains(t, dis, "itxn_field Amount\n")
+	require.Contains(t, dis, "itxn Amount\n")
+}
+
+func TestAppParams(t *testing.T) {
+	t.Parallel()
+
+	txn := makeSampleTxn()
+	txn.Txn.ApplicationID = 100
+	txn.Txn.ForeignApps = []basics.AppIndex{100, 200}
+	ep := defaultEvalParams(nil, &txn)
+	ledger := makeTestLedger(nil)
+	ledger.newApp(txn.Txn.Receiver, 100, makeSchemas(1, 2, 3, 4))
+	ep.Ledger = ledger
+
+	testApp(t, "int 0; app_params_get AppGlobalNumUint; assert; int 3; ==", ep)
+	testApp(t, "int 1; app_params_get AppLocalNumByteSlice; assert; int 2; ==", ep)
+	testApp(t, "int 100; app_params_get AppCreator; assert; txn Receiver; ==", ep)
+	testApp(t, "int 100; app_params_get AppAddress; assert; global CurrentApplicationAddress; ==", ep)
+	testApp(t, "int 100; app_params_get AppApprovalProgram; assert; len; !", ep)
+
+	// an available app that does not exist
+	testApp(t, "int 200; app_params_get AppCreator; !; assert; int 0; ==", ep)
+	testApp(t, "int 2; app_params_get AppCreator; !; assert; int 0; ==", ep)
+
+	// apps must be available, like other app references
+	testApp(t, "int 300; app_params_get AppCreator; int 1", ep, "invalid App reference 300")
+
+	testProg(t, "int 0; app_params_get AppCreator", 4, expect{2, "app_params_get opcode was introduced in TEAL v5"})
+	testLine(t, "app_params_get Nope", AssemblerMaxVersion, "app_params_get unknown arg: \"Nope\"")
+	testLine(t, "app_params_get", AssemblerMaxVersion, "app_params_get expects one argument")
+}
+
+// newCallee adds an application that inner transactions may call, leaving
+// the current application unchanged
+func (l *testLedger) newCallee(creator basics.Address, appID basics.AppIndex, approval []byte) {
+	l.applications[appID] = appParams{
+		Creator: creator,
+		AppParams: basics.AppParams{
+			ApprovalProgram: approval,
+			GlobalState:     make(basics.TealKeyValue),
+		},
+	}
+	l.balances[appID.Address()] = makeBalanceRecord(appID.Address(), 1000000)
+}
+
+func makeInnerAppCallTest(t *testing.T) (EvalParams, *testLedger) {
+	ep, ledger := makeInnerTxnTest()
+	ep.Txn.Txn.ForeignApps = []basics.AppIndex{222, 333}
+	ep.TxnGroup[0] = *ep.Txn
+	ep.Proto.Application = true
+	ep.Proto.MaxAppArgs = 16
+	ep.Proto.MaxAppTotalArgLen = 2048
+	ep.Proto.MaxAppTxnAccounts = 4
+	ep.Proto.MaxAppTxnForeignApps = 2
+	ep.Proto.MaxAppTxnForeignAssets = 2
+	ep.Proto.MaxAppTotalTxnReferences = 8
+
+	callee := testProg(t, `
+txna ApplicationArgs 0
+byte "arg"
+==
+assert
+byte "hello"
+log
+int 1
+`, AssemblerMaxVersion)
+	ledger.newCallee(ep.Txn.Txn.Sender, 222, callee.Program)
+	return ep, ledger
+}
+
+const innerCall = `
+itxn_begin
+int appl
+itxn_field TypeEnum
+int 222
+itxn_field ApplicationID
+byte "arg"
+itxn_field ApplicationArgs
+`
+
+func TestInnerAppCall(t *testing.T) {
+	t.Parallel()
+
+	ep, _ := makeInnerAppCallTest(t)
+
+	testApp(t, innerCall+`
+itxn_submit
+itxn NumLogs
+int 1
+==
+itxn LastLog
+byte "hello"
+==
+&&
+itxn ApplicationID
+int 222
+==
+&&
+`, ep)
+	inner := ep.PastSideEffects[ep.GroupIndex].InnerTxns()
+	require.Len(t, inner, 1)
+	require.Equal(t, protocol.ApplicationCallTx, inner[0].Txn.Type)
+	require.Equal(t, basics.AppIndex(888).Address(), inner[0].Txn.Sender)
+	require.Equal(t, [][]byte{[]byte("arg")}, inner[0].Txn.ApplicationArgs)
+	require.Equal(t, []string{"hello"}, inner[0].EvalDelta.Logs)
+
+	// the callee's failure is the caller's
+	testApp(t, `itxn_begin; int appl; itxn_field TypeEnum; int 222; itxn_field ApplicationID;
+byte "nope"; itxn_field ApplicationArgs; itxn_submit; int 1`, ep, "assert failed")
+	testApp(t, "itxn_begin; int appl; itxn_field TypeEnum; int 222; itxn_field ApplicationID; itxn_submit; int 1", ep,
+		"invalid ApplicationArgs index 0")
+
+	// logs are only available for inner transactions
+	testLogic(t, "txn LastLog; len", AssemblerMaxVersion, defaultEvalParams(nil, nil), "LastLog is only available from itxn")
+	testLogic(t, "txn NumLogs", AssemblerMaxVersion, defaultEvalParams(nil, nil), "NumLogs is only available from itxn")
+}
+
+func TestInnerAppCallCost(t *testing.T) {
+	t.Parallel()
+
+	ep, ledger := makeInnerAppCallTest(t)
+
+	// the callee's cost is charged to the caller
+	testApp(t, innerCall+"itxn_submit; int 1", ep)
+	// 9 ops in the caller, 7 in the callee
+	require.Equal(t, 9+7, ep.PastSideEffects[ep.GroupIndex].Cost())
+
+	// and it may only spend what remains of the caller's budget
+	expensive := testProg(t, strings.Repeat("int 1; pop; ", 340)+"int 1", AssemblerMaxVersion)
+	ledger.newCallee(ep.Txn.Txn.Sender, 333, expensive.Program)
+	testApp(t, "itxn_begin; int appl; itxn_field TypeEnum; int 333; itxn_field ApplicationID; itxn_submit; int 1", ep)
+	// the callee fails as soon as it overruns that budget, rather than once
+	// its effects were applied, whether or not the budget is pooled
+	overrun := strings.Repeat("int 1; pop; ", 10) +
+		"itxn_begin; int appl; itxn_field TypeEnum; int 333; itxn_field ApplicationID; itxn_submit; int 1"
+	testApp(t, overrun, ep, "exceeded, executing")
+	ep.PooledApplicationBudget = MakePooledBudget(ep.Proto, ep.TxnGroup)
+	testApp(t, overrun, ep, "exceeded, executing")
+	ep.Proto.EnableAppCostPooling = true
+	ep.PooledApplicationBudget = MakePooledBudget(ep.Proto, ep.TxnGroup)
+	testApp(t, overrun, ep, "exceeded, executing")
+}
+
+func TestInnerAppCallRules(t *testing.T) {
+	t.Parallel()
+
+	ep, ledger := makeInnerAppCallTest(t)
+
+	// apps must be available, like other app references
+	testApp(t, "itxn_begin; int 444; itxn_field ApplicationID; int 1", ep, "invalid App reference 444")
+
+	call := func(app int, oc string) string {
+		return fmt.Sprintf(`itxn_begin; int appl; itxn_field TypeEnum; int %d; itxn_field ApplicationID;
+int %s; itxn_field OnCompletion; itxn_submit; int 1`, app, oc)
+	}
+	testApp(t, "itxn_begin; int appl; itxn_field TypeEnum; itxn_submit; int 1", ep, "inner appl may not create an application")
+	testApp(t, call(222, "UpdateApplication"), ep, "may not use OnCompletion UpdateApplication")
+	testApp(t, call(222, "DeleteApplication"), ep, "may not use OnCompletion DeleteApplication")
+	testApp(t, call(222, "ClearState"), ep, "may not use OnCompletion ClearState")
+	testApp(t, call(888, "NoOp"), ep, "app 888 may not call itself")
+
+	// 333 calls back into 888, which is already executing
+	reenter := testProg(t, `
+itxn_begin
+int appl
+itxn_field TypeEnum
+int 888
+itxn_field ApplicationID
+itxn_submit
+int 1
+`, AssemblerMaxVersion)
+	ledger.newCallee(ep.Txn.Txn.Sender, 333, reenter.Program)
+	testApp(t, `itxn_begin; int appl; itxn_field TypeEnum; int 333; itxn_field ApplicationID;
+int 888; itxn_field Applications; itxn_submit; int 1`, ep, "app 888 is already on the call stack")
+
+	// 333 calls 222, which is within the depth limit of 3 but not of 2
+	deeper := testProg(t, innerCall+"itxn_submit; int 1", AssemblerMaxVersion)
+	ledger.newCallee(ep.Txn.Txn.Sender, 333, deeper.Program)
+	nested := `itxn_begin; int appl; itxn_field TypeEnum; int 333; itxn_field ApplicationID;
+int 222; itxn_field Applications; itxn_submit; int 1`
+	testApp(t, nested, ep)
+	ep.Proto.MaxAppCallDepth = 2
+	testApp(t, nested, ep, "appl depth (3) exceeds MaxAppCallDepth (2)")
+	ep.Proto.MaxAppCallDepth = 0
+	testApp(t, call(222, "NoOp"), ep, "appl depth (2) exceeds MaxAppCallDepth (0)")
+}
+
+func TestLog(t *testing.T) {
+	t.Parallel()
+
+	var txn transactions.SignedTxn
+	txn.Txn.Type = protocol.ApplicationCallTx
+	ledger := makeTestLedger(nil)
+	ledger.newApp(txn.Txn.Receiver, 0, makeSchemas(0, 0, 0, 0))
+	ep := defaultEvalParams(nil, &txn)
+	ep.Ledger = ledger
+
+	testApp(t, `byte "a logging message"; log; int 1`, ep)
+	logs := ep.PastSideEffects[ep.GroupIndex].Logs()
+	require.Equal(t, []string{"a logging message"}, logs)
+
+	testApp(t, `int 7; itob; log; byte "second"; log; int 1`, ep)
+	logs = ep.PastSideEffects[ep.GroupIndex].Logs()
+	require.Equal(t, []string{"\x00\x00\x00\x00\x00\x00\x00\x07", "second"}, logs)
+
+	// MaxLogCalls and MaxLogSize are enforced
+	maxCalls := strings.Repeat(`byte "x"; log; `, ep.Proto.MaxLogCalls)
+	testApp(t, maxCalls+"int 1", ep)
+	require.Len(t, ep.PastSideEffects[ep.GroupIndex].Logs(), ep.Proto.MaxLogCalls)
+	testApp(t, maxCalls+`byte "x"; log; int 1`, ep, "too many log calls")
+
+	testApp(t, fmt.Sprintf(`int %d; bzero; log; int 1`, ep.Proto.MaxLogSize), ep)
+	testApp(t, fmt.Sprintf(`int %d; bzero; log; int 1`, ep.Proto.MaxLogSize+1), ep, "program logs too large")
+	testApp(t, fmt.Sprintf(`int %d; bzero; log; byte "x"; log; int 1`, ep.Proto.MaxLogSize), ep,
+		"program logs too large")
+
+	testProg(t, `int 1; log; int 1`, LogicVersion, expect{2, "log arg 0 wanted type []byte got uint64"})
+
+	// log is only available to applications, from v5
+	testProg(t, `byte "x"; log`, 4, expect{2, "log opcode was introduced in TEAL v5"})
+	ops := testProg(t, `byte "x"; log; int 1`, LogicVersion)
+	sb := strings.Builder{}
+	ep = defaultEvalParams(&sb, &txn)
+	_, err := Eval(ops.Program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed in current mode")
+}
+
+func TestBoxes(t *testing.T) {
+	t.Parallel()
+
+	var txn transactions.SignedTxn
+	txn.Txn.Type = protocol.ApplicationCallTx
+	ledger := makeTestLedger(nil)
+	ledger.newApp(txn.Txn.Receiver, 0, makeSchemas(0, 0, 0, 0))
+	ep := defaultEvalParams(nil, &txn)
+	ep.Ledger = ledger
+
+	testApp(t, `byte "self"; int 4; box_create`, ep)
+	require.Equal(t, "\x00\x00\x00\x00", ledger.boxes[0]["self"])
+	// creating again with the same size is a no-op that returns 0
+	testApp(t, `byte "self"; int 4; box_create; !`, ep)
+	testApp(t, `byte "self"; int 5; box_create; !`, ep, "box size mismatch")
+	testApp(t, `byte ""; int 5; box_create`, ep, "invalid box name length")
+	testApp(t, fmt.Sprintf(`byte "big"; int %d; box_create`, ep.Proto.MaxBoxSize+1), ep, "box size")
+
+	testApp(t, `byte "self"; int 1; byte "ab"; box_replace;
+                byte "self"; int 0; int 4; box_extract; byte 0x00616200; ==`, ep)
+	testApp(t, `byte "self"; int 3; byte "ab"; box_replace; int 1`, ep, "replacement range beyond box")
+	testApp(t, `byte "self"; int 2; int 3; box_extract; len`, ep, "extract range beyond box")
+	testApp(t, `byte "none"; int 0; int 1; box_extract; len`, ep, "no such box")
+
+	testApp(t, `byte "self"; box_len; assert; int 4; ==`, ep)
+	testApp(t, `byte "none"; box_len; !; assert; !`, ep)
+	testApp(t, `byte "self"; box_get; assert; byte 0x00616200; ==`, ep)
+	testApp(t, `byte "none"; box_get; !; assert; len; !`, ep)
+
+	testApp(t, `byte "self"; byte "wxyz"; box_put; byte "self"; box_get; assert; byte "wxyz"; ==`, ep)
+	testApp(t, `byte "self"; byte "long"; box_put; byte "other"; byte "new box"; box_put;
+                byte "other"; box_len; assert; int 7; ==`, ep)
+	testApp(t, `byte "self"; byte "short"; box_put; int 1`, ep, "box_put wrong size")
+
+	testApp(t, `byte "self"; int 6; box_resize; byte "self"; box_get; assert; byte 0x6c6f6e670000; ==`, ep)
+	testApp(t, `byte "self"; int 2; box_resize; byte "self"; box_get; assert; byte "lo"; ==`, ep)
+	testApp(t, `byte "none"; int 2; box_resize; int 1`, ep, "no such box")
+
+	testApp(t, `byte "self"; box_del; byte "self"; box_del; !; &&`, ep)
+	testApp(t, `byte "self"; box_len; !; assert; !`, ep)
+	require.Len(t, ledger.boxes[0], 1)
+
+	// boxes are only available to applications, from v5
+	testProg(t, `byte "x"; int 1; box_create`, 4, expect{3, "box_create opcode was introduced in TEAL v5"})
+	testProg(t, `int 1; int 1; box_create`, LogicVersion, expect{3, "box_create arg 0 wanted type []byte got uint64"})
+	ops := testProg(t, `byte "x"; box_len; pop`, LogicVersion)
+	ep = defaultEvalParams(nil, &txn)
+	_, err := Eval(ops.Program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not allowed in current mode")
+}
+
+func TestPooledAppCost(t *testing.T) {
+	t.Parallel()
+
+	txn := makeSampleTxn()
+	txn.Txn.Type = protocol.ApplicationCallTx
+	txgroup := []transactions.SignedTxn{txn, txn}
+	ep := defaultEvalParams(nil, &txgroup[0])
+	ep.TxnGroup = txgroup
+	ep.Ledger = makeTestLedger(nil)
+
+	// 1 + 8*130 + 1 is over the budget of a single program
+	source := "byte 0x01" + strings.Repeat("; keccak256", 8) + "; len"
+	ops := testProg(t, source, AssemblerMaxVersion)
+
+	_, err := EvalStateful(ops.Program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dynamic cost budget of 700 exceeded")
+
+	// pooling is off unless the protocol enables it
+	ep.PooledApplicationBudget = MakePooledBudget(ep.Proto, txgroup)
+	require.Nil(t, ep.PooledApplicationBudget)
+
+	ep.Proto.EnableAppCostPooling = true
+	ep.PooledApplicationBudget = MakePooledBudget(ep.Proto, txgroup)
+	require.Equal(t, uint64(1400), *ep.PooledApplicationBudget)
+
+	pass, err := EvalStateful(ops.Program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+	require.Equal(t, 1042, ep.PastSideEffects[0].Cost())
+	require.Equal(t, uint64(1400-1042), *ep.PooledApplicationBudget)
+
+	// the second call in the group only has what is left of the pool
+	ep.Txn = &txgroup[1]
+	ep.GroupIndex = 1
+	_, err = EvalStateful(ops.Program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "dynamic cost budget of 358 exceeded")
+
+	cheap := testProg(t, "int 1", AssemblerMaxVersion)
+	pooled := uint64(1400)
+	ep.PooledApplicationBudget = &pooled
+	pass, err = EvalStateful(cheap.Program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+	require.Equal(t, uint64(1399), pooled)
+}
@@ -49,11 +49,18 @@ func defaultEvalProtoV1() config.ConsensusParams {
 
 func defaultEvalProtoWithVersion(version uint64) config.ConsensusParams {
 	return config.ConsensusParams{
-		LogicSigVersion:     version,
-		LogicSigMaxCost:     20000,
-		MaxAppProgramCost:   700,
-		MaxAppKeyLen:        64,
-		MaxAppBytesValueLen: 64,
+		LogicSigVersion:      version,
+		LogicSigMaxCost:      20000,
+		MaxAppProgramCost:    700,
+		MaxAppKeyLen:         64,
+		MaxAppBytesValueLen:  64,
+		MaxInnerTransactions: 4,
+		MaxAppCallDepth:      3,
+		MaxLogCalls:          32,
+		MaxLogSize:           1024,
+		MaxReturnDataEntries: 4,
+		MaxReturnDataSize:    64,
+		MaxBoxSize:           8192,
 		// These must be identical to keep an old backward compat test working
 		MinTxnFee:  1001,
 		MinBalance: 1001,
@@ -94,6 +101,7 @@ func defaultEvalParamsWithVersion(sb *strings.Builder, txn *transactions.SignedT
 	ep.Proto = &proto
 	ep.Txn = pt
 	ep.PastSideEffects = MakePastSideEffects(5)
+	ep.Specials = &transactions.SpecialAddresses{}
 	if sb != nil { // have to do this since go's nil semantics: https://golang.org/doc/faq#nil_error
 		ep.Trace = sb
 	}
@@ -910,6 +918,13 @@ const globalV4TestProgram = globalV3TestProgram + `
 // No new globals in v4
 `
 
+const globalV5TestProgram = globalV4TestProgram + `
+global CurrentApplicationAddress
+addr MW6NSXPOT4R6EQCK4VRNZOJSAKSPQ5TXYZQBCJXXYBK4ET4E7R74GVWL2Q
+==
+&&
+`
+
 func TestGlobal(t *testing.T) {
 	t.Parallel()
 	type desc struct {
@@ -933,6 +948,10 @@ func TestGlobal(t *testing.T) {
 			CreatorAddress, globalV4TestProgram,
 			EvalStateful, CheckStateful,
 		},
+		5: {
+			CurrentApplicationAddress, globalV5TestProgram,
+			EvalStateful, CheckStateful,
+		},
 	}
 	ledger := makeTestLedger(nil)
 	ledger.appID = 42
@@ -1403,7 +1422,8 @@ func TestTxn(t *testing.T) {
 	t.Parallel()
 	for _, txnField := range TxnFieldNames {
 		if !strings.Contains(testTxnProgramTextV4, txnField) {
-			if txnField != FirstValidTime.String() {
+			// NumLogs and LastLog are only available from itxn
+			if txnField != FirstValidTime.String() && txnField != NumLogs.String() && txnField != LastLog.String() {
 				t.Errorf("TestTxn missing field %v", txnField)
 			}
 		}
@@ -2316,6 +2336,92 @@ byte "txn 2"
 	}
 }
 
+func TestReturnData(t *testing.T) {
+	t.Parallel()
+
+	sources := []string{
+		`byte "first"; retdata_put; byte "second"; retdata_put; int 1`,
+		`int 1`,
+		`gretdata 0 0; assert; byte "first"; ==; assert
+		 int 0; gretdatas 1; assert; byte "second"; ==; assert
+		 gretdata 0 2; !; assert; len; !; assert
+		 gretdata 1 0; !; assert; len; !`,
+	}
+	opsList := make([]*OpStream, len(sources))
+	for j, source := range sources {
+		opsList[j] = testProg(t, source, AssemblerMaxVersion)
+	}
+
+	// Logic signatures and applications see return data the same way
+	for _, eval := range []func([]byte, EvalParams) (bool, error){Eval, EvalStateful} {
+		proto := defaultEvalProtoWithVersion(LogicVersion)
+		txgroup := make([]transactions.SignedTxn, len(sources))
+		for j := range txgroup {
+			txgroup[j].Txn.Type = protocol.ApplicationCallTx
+			txgroup[j].Lsig.Logic = opsList[j].Program
+		}
+		pastSideEffects := MakePastSideEffects(len(sources))
+		for j, ops := range opsList {
+			ep := EvalParams{
+				Proto:           &proto,
+				Txn:             &txgroup[j],
+				TxnGroup:        txgroup,
+				GroupIndex:      j,
+				PastSideEffects: pastSideEffects,
+			}
+			pass, err := eval(ops.Program, ep)
+			require.NoError(t, err)
+			require.True(t, pass)
+		}
+		require.Equal(t, []string{"first", "second"}, pastSideEffects[0].ReturnData())
+		require.Empty(t, pastSideEffects[1].ReturnData())
+	}
+
+	txn := transactions.SignedTxn{}
+	txgroup := []transactions.SignedTxn{txn, txn}
+	ep := defaultEvalParams(nil, &txgroup[1])
+	ep.TxnGroup = txgroup
+	ep.GroupIndex = 1
+	testLogic(t, `gretdata 1 0; pop`, LogicVersion, ep, "can't use gretdata on self")
+	testLogic(t, `int 2; gretdatas 0; pop`, LogicVersion, ep, "gretdatas lookup TxnGroup[2] but it only has 2")
+	ep.GroupIndex = 0
+	ep.Txn = &txgroup[0]
+	testLogic(t, `gretdata 1 0; pop`, LogicVersion, ep, "gretdata can't get future return data from txn with index 1")
+	ep.PastSideEffects = nil
+	ep.GroupIndex = 1
+	ep.Txn = &txgroup[1]
+	testLogic(t, `gretdata 0 0; pop`, LogicVersion, ep, "gretdata return data is not available")
+
+	// Logic signatures and applications can't read each other's return data
+	ep.PastSideEffects = MakePastSideEffects(len(txgroup))
+	txgroup[0].Lsig.Logic = nil
+	testLogic(t, `gretdata 0 0; pop; pop; int 1`, LogicVersion, ep,
+		"gretdata can't read the return data of txn 0, which isn't signed by a logic signature")
+	txgroup[0].Lsig.Logic = testProg(t, `int 1`, LogicVersion).Program
+	testLogic(t, `gretdata 0 0; pop; pop; int 1`, LogicVersion, ep)
+	ops := testProg(t, `int 0; gretdatas 0; pop; pop; int 1`, LogicVersion)
+	_, err := EvalStateful(ops.Program, ep)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "gretdatas can't read the return data of txn 0, which isn't an application call")
+	txgroup[0].Txn.Type = protocol.ApplicationCallTx
+	pass, err := EvalStateful(ops.Program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	// MaxReturnDataEntries and MaxReturnDataSize are enforced
+	ep = defaultEvalParams(nil, nil)
+	maxEntries := strings.Repeat(`byte "x"; retdata_put; `, ep.Proto.MaxReturnDataEntries)
+	testLogic(t, maxEntries+"int 1", LogicVersion, ep)
+	require.Len(t, ep.PastSideEffects[0].ReturnData(), ep.Proto.MaxReturnDataEntries)
+	testLogic(t, maxEntries+`byte "x"; retdata_put; int 1`, LogicVersion, ep, "too many return data entries")
+	testLogic(t, fmt.Sprintf(`int %d; bzero; retdata_put; int 1`, ep.Proto.MaxReturnDataSize), LogicVersion, ep)
+	testLogic(t, fmt.Sprintf(`int %d; bzero; retdata_put; byte "x"; retdata_put; int 1`, ep.Proto.MaxReturnDataSize),
+		LogicVersion, ep, "program return data too large")
+
+	testProg(t, `int 1; retdata_put; int 1`, LogicVersion, expect{2, "retdata_put arg 0 wanted type []byte got uint64"})
+	testProg(t, `byte "x"; retdata_put; int 1`, 4, expect{2, "retdata_put opcode was introduced in TEAL v5"})
+}
+
 const testCompareProgramText = `int 35
 int 16
 >
@@ -2936,10 +3042,11 @@ intc_1
 import random
 
 def foo():
-    for i in range(64):
-        print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
-    for i in range(63):
-        print('+')
+
+	for i in range(64):
+	    print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
+	for i in range(63):
+	    print('+')
 */
 const addBenchmarkSource = `int 20472989571761113
 int 80135167795737348
@@ -3074,10 +3181,11 @@ int 28939890412103745
 import random
 
 def foo():
-    print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
-    for i in range(63):
-        print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
-        print('+')
+
+	print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
+	for i in range(63):
+	    print('int {}'.format(random.randint(0,0x01ffffffffffffff)))
+	    print('+')
 */
 const addBenchmark2Source = `int 8371863094338737
 int 29595196041051360
@@ -4401,3 +4509,78 @@ func TestBytesConversions(t *testing.T) {
 	testAccepts(t, "byte 0x11; byte 0x10; b+; btoi; int 0x21; ==", 4)
 	testAccepts(t, "byte 0x0011; byte 0x10; b+; btoi; int 0x21; ==", 4)
 }
+
+// A secp256k1 signature of sha256("testdata"), as an Ethereum signer would
+// produce it.
+const (
+	ecdsaMsg        = "0x810ff2fb242a5dee4220f2cb0e6a519891fb67f2f828a6cab4ef8894633b1f50"
+	ecdsaR          = "0x81bd91eda49f328943ea5af7a9b3dcd055c35d341b9081903388d6f6ef8e1433"
+	ecdsaS          = "0x59fa3f4252e4a7f6b39fabc0498d7bdf2afd343e4a35cdfd3fe19b7a355d7196"
+	ecdsaHighS      = "0xa605c0bdad1b58094c60543fb672841f8fb1a8a86512d23e7ff0c3129ad8cfab"
+	ecdsaX          = "0xecde6e57b31d529ac9473773ba51aeb2185cc5cb24e45864cfe758405c82547b"
+	ecdsaY          = "0x5e6aca7f28d6fef67bcf8a34f04f3c4c07ebbf1a9aaa0a459b2bf4c01cff6ef1"
+	ecdsaCompressed = "0x03ecde6e57b31d529ac9473773ba51aeb2185cc5cb24e45864cfe758405c82547b"
+)
+
+func TestEcdsaVerify(t *testing.T) {
+	t.Parallel()
+
+	verify := func(msg, r, s, x, y string) string {
+		return fmt.Sprintf("byte %s; byte %s; byte %s; byte %s; byte %s; ecdsa_verify Secp256k1",
+			msg, r, s, x, y)
+	}
+	testAccepts(t, verify(ecdsaMsg, ecdsaR, ecdsaS, ecdsaX, ecdsaY), 5)
+
+	// wrong data, or a malleated signature, are rejected
+	testRejects(t, verify(ecdsaR, ecdsaR, ecdsaS, ecdsaX, ecdsaY), 5)
+	testRejects(t, verify(ecdsaMsg, ecdsaR, ecdsaHighS, ecdsaX, ecdsaY), 5)
+
+	// malformed inputs fail
+	testPanics(t, verify("0x01", ecdsaR, ecdsaS, ecdsaX, ecdsaY), 5)
+	testPanics(t, verify(ecdsaMsg, "0x01", ecdsaS, ecdsaX, ecdsaY), 5)
+	testPanics(t, verify(ecdsaMsg, ecdsaR, ecdsaS, ecdsaY, ecdsaX), 5)
+
+	ops := testProg(t, verify(ecdsaMsg, ecdsaR, ecdsaS, ecdsaX, ecdsaY), 5)
+	ops.Program[len(ops.Program)-1] = byte(invalidEcdsaCurve)
+	var txn transactions.SignedTxn
+	txn.Lsig.Logic = ops.Program
+	pass, err := Eval(ops.Program, defaultEvalParams(nil, &txn))
+	require.False(t, pass)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid curve")
+
+	testProg(t, "ecdsa_verify Secp256k1", 4, expect{1, "ecdsa_verify opcode was introduced in TEAL v5"})
+	testProg(t, "byte 0x00; ecdsa_pk_decompress Ed25519", 5, expect{2, "ecdsa_pk_decompress unknown curve..."})
+}
+
+func TestEcdsaPkDecompress(t *testing.T) {
+	t.Parallel()
+
+	testAccepts(t, fmt.Sprintf(`byte %s; ecdsa_pk_decompress Secp256k1
+byte %s; ==; assert; byte %s; ==`, ecdsaCompressed, ecdsaY, ecdsaX), 5)
+
+	testPanics(t, fmt.Sprintf("byte %s; ecdsa_pk_decompress Secp256k1; pop; pop; int 1", ecdsaX), 5)
+}
+
+func TestEcdsaPkRecover(t *testing.T) {
+	t.Parallel()
+
+	recover := func(msg string, recid int, r, s string) string {
+		return fmt.Sprintf("byte %s; int %d; byte %s; byte %s; ecdsa_pk_recover Secp256k1",
+			msg, recid, r, s)
+	}
+	testAccepts(t, recover(ecdsaMsg, 0, ecdsaR, ecdsaS)+
+		fmt.Sprintf("; byte %s; ==; assert; byte %s; ==", ecdsaY, ecdsaX), 5)
+
+	// the other recovery id yields a different key
+	testAccepts(t, recover(ecdsaMsg, 1, ecdsaR, ecdsaS)+
+		fmt.Sprintf("; byte %s; !=; assert; byte %s; !=", ecdsaY, ecdsaX), 5)
+
+	// recovered keys verify the signature
+	testAccepts(t, fmt.Sprintf("byte %s; byte %s; byte %s; ", ecdsaMsg, ecdsaR, ecdsaS)+
+		recover(ecdsaMsg, 0, ecdsaR, ecdsaS)+"; ecdsa_verify Secp256k1", 5)
+
+	testPanics(t, recover(ecdsaMsg, 4, ecdsaR, ecdsaS)+"; pop; pop; int 1", 5)
+	testPanics(t, recover("0x01", 0, ecdsaR, ecdsaS)+"; pop; pop; int 1", 5)
+	testPanics(t, recover(ecdsaMsg, 0, ecdsaR, "0x00")+"; pop; pop; int 1", 5)
+}
@@ -23,7 +23,7 @@ import (
 	"github.com/algorand/go-algorand/protocol"
 )
 
-//go:generate stringer -type=TxnField,GlobalField,AssetParamsField,AssetHoldingField,OnCompletionConstType -output=fields_string.go
+//go:generate stringer -type=TxnField,GlobalField,AssetParamsField,AssetHoldingField,AppParamsField,OnCompletionConstType,EcdsaCurve -output=fields_string.go
 
 // TxnField is an enum type for `txn` and `gtxn`
 type TxnField int
@@ -146,6 +146,11 @@ const (
 	// ExtraProgramPages AppParams.ExtraProgramPages
 	ExtraProgramPages
 
+	// NumLogs len(ApplyData.EvalDelta.Logs)
+	NumLogs
+	// LastLog ApplyData.EvalDelta.Logs[NumLogs-1]
+	LastLog
+
 	invalidTxnField // fence for some setup that loops from Sender..invalidTxnField
 )
 
@@ -232,6 +237,8 @@ var txnFieldSpecs = []txnFieldSpec{
 	{LocalNumUint, StackUint64, 3},
 	{LocalNumByteSlice, StackUint64, 3},
 	{ExtraProgramPages, StackUint64, 4},
+	{NumLogs, StackUint64, 5},
+	{LastLog, StackBytes, 5},
 }
 
 // TxnaFieldNames are arguments to the 'txna' opcode
@@ -253,6 +260,36 @@ var txnaFieldSpecByField = map[TxnField]txnFieldSpec{
 	Applications:    {Applications, StackUint64, 3},
 }
 
+// innerTxnFieldSpecByField are the fields that may be set by itxn_field,
+// along with the TEAL version in which they became settable
+var innerTxnFieldSpecByField = map[TxnField]uint64{
+	Sender:           5,
+	Fee:              5,
+	Receiver:         5,
+	Amount:           5,
+	CloseRemainderTo: 5,
+	Type:             5,
+	TypeEnum:         5,
+	XferAsset:        5,
+	AssetAmount:      5,
+	AssetSender:      5,
+	AssetReceiver:    5,
+	AssetCloseTo:     5,
+	ApplicationID:    5,
+	OnCompletion:     5,
+	ApplicationArgs:  5,
+	Accounts:         5,
+	Assets:           5,
+	Applications:     5,
+}
+
+// innerTxnTypes are the transaction types that may be issued by itxn_submit
+var innerTxnTypes = map[string]protocol.TxType{
+	string(protocol.PaymentTx):         protocol.PaymentTx,
+	string(protocol.AssetTransferTx):   protocol.AssetTransferTx,
+	string(protocol.ApplicationCallTx): protocol.ApplicationCallTx,
+}
+
 // TxnTypeNames is the values of Txn.Type in enum order
 var TxnTypeNames = []string{
 	string(protocol.UnknownTx),
@@ -327,6 +364,11 @@ const (
 	// CreatorAddress [32]byte
 	CreatorAddress
 
+	// v5
+
+	// CurrentApplicationAddress [32]byte
+	CurrentApplicationAddress
+
 	invalidGlobalField
 )
 
@@ -354,6 +396,7 @@ var globalFieldSpecs = []globalFieldSpec{
 	{LatestTimestamp, StackUint64, runModeApplication, 2},
 	{CurrentApplicationID, StackUint64, runModeApplication, 2},
 	{CreatorAddress, StackBytes, runModeApplication, 3},
+	{CurrentApplicationAddress, StackBytes, runModeApplication, 5},
 }
 
 // GlobalFieldSpecByField maps GlobalField to spec
@@ -455,6 +498,70 @@ var AssetParamsFieldTypes []StackType
 
 var assetParamsFields map[string]uint64
 
+// AppParamsField is an enum for `app_params_get` opcode
+type AppParamsField int
+
+const (
+	// AppApprovalProgram AppParams.ApprovalProgram
+	AppApprovalProgram AppParamsField = iota
+	// AppClearStateProgram AppParams.ClearStateProgram
+	AppClearStateProgram
+	// AppGlobalNumUint AppParams.StateSchemas.GlobalStateSchema.NumUint
+	AppGlobalNumUint
+	// AppGlobalNumByteSlice AppParams.StateSchemas.GlobalStateSchema.NumByteSlice
+	AppGlobalNumByteSlice
+	// AppLocalNumUint AppParams.StateSchemas.LocalStateSchema.NumUint
+	AppLocalNumUint
+	// AppLocalNumByteSlice AppParams.StateSchemas.LocalStateSchema.NumByteSlice
+	AppLocalNumByteSlice
+	// AppExtraProgramPages AppParams.ExtraProgramPages
+	AppExtraProgramPages
+	// AppCreator basics.Address
+	AppCreator
+	// AppAddress basics.Address
+	AppAddress
+	invalidAppParamsField
+)
+
+// AppParamsFieldNames are arguments to the 'app_params_get' opcode
+var AppParamsFieldNames []string
+
+type appParamsFieldType struct {
+	field AppParamsField
+	ftype StackType
+}
+
+var appParamsFieldTypeList = []appParamsFieldType{
+	{AppApprovalProgram, StackBytes},
+	{AppClearStateProgram, StackBytes},
+	{AppGlobalNumUint, StackUint64},
+	{AppGlobalNumByteSlice, StackUint64},
+	{AppLocalNumUint, StackUint64},
+	{AppLocalNumByteSlice, StackUint64},
+	{AppExtraProgramPages, StackUint64},
+	{AppCreator, StackBytes},
+	{AppAddress, StackBytes},
+}
+
+// AppParamsFieldTypes is StackUint64 StackBytes in parallel with AppParamsFieldNames
+var AppParamsFieldTypes []StackType
+
+var appParamsFields map[string]uint64
+
+// EcdsaCurve is an enum for `ecdsa_` opcodes
+type EcdsaCurve int
+
+const (
+	// Secp256k1 curve for bitcoin/ethereum
+	Secp256k1 EcdsaCurve = iota
+	invalidEcdsaCurve
+)
+
+// EcdsaCurveNames are arguments to the 'ecdsa_' opcodes
+var EcdsaCurveNames []string
+
+var ecdsaCurves map[string]uint64
+
 func init() {
 	TxnFieldNames = make([]string, int(invalidTxnField))
 	for fi := Sender; fi < invalidTxnField; fi++ {
@@ -515,6 +622,28 @@ func init() {
 		assetParamsFields[fn] = uint64(i)
 	}
 
+	AppParamsFieldNames = make([]string, int(invalidAppParamsField))
+	for i := AppApprovalProgram; i < invalidAppParamsField; i++ {
+		AppParamsFieldNames[int(i)] = i.String()
+	}
+	AppParamsFieldTypes = make([]StackType, len(AppParamsFieldNames))
+	for _, ft := range appParamsFieldTypeList {
+		AppParamsFieldTypes[int(ft.field)] = ft.ftype
+	}
+	appParamsFields = make(map[string]uint64)
+	for i, fn := range AppParamsFieldNames {
+		appParamsFields[fn] = uint64(i)
+	}
+
+	EcdsaCurveNames = make([]string, int(invalidEcdsaCurve))
+	for i := Secp256k1; i < invalidEcdsaCurve; i++ {
+		EcdsaCurveNames[int(i)] = i.String()
+	}
+	ecdsaCurves = make(map[string]uint64)
+	for i, cn := range EcdsaCurveNames {
+		ecdsaCurves[cn] = uint64(i)
+	}
+
 	txnTypeIndexes = make(map[string]uint64, len(TxnTypeNames))
 	for i, tt := range TxnTypeNames {
 		txnTypeIndexes[tt] = uint64(i)
@@ -0,0 +1,662 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// LintRule names a check made by Lint
+type LintRule string
+
+// The rules checked by Lint. Each approving exit of a program (a return of a
+// value that is not a constant 0, or the end of the program) is checked
+// against the conditions that must have been asserted on the way to it.
+const (
+	// LintRekeyTo warns when a logic signature can approve without checking RekeyTo
+	LintRekeyTo LintRule = "rekey-to"
+	// LintCloseRemainderTo warns when a logic signature can approve a payment without checking CloseRemainderTo
+	LintCloseRemainderTo LintRule = "close-remainder-to"
+	// LintAssetCloseTo warns when a logic signature can approve an asset transfer without checking AssetCloseTo
+	LintAssetCloseTo LintRule = "asset-close-to"
+	// LintFee warns when a logic signature can approve without bounding Fee
+	LintFee LintRule = "fee"
+	// LintGroupSize warns when a program can approve without checking the group size
+	LintGroupSize LintRule = "group-size"
+	// LintOnCompletion warns when an application can approve without checking OnCompletion
+	LintOnCompletion LintRule = "on-completion"
+	// LintUnreachable warns about instructions that can never be executed
+	LintUnreachable LintRule = "unreachable"
+)
+
+// LintRules lists all the rules known to Lint
+var LintRules = []LintRule{
+	LintRekeyTo,
+	LintCloseRemainderTo,
+	LintAssetCloseTo,
+	LintFee,
+	LintGroupSize,
+	LintOnCompletion,
+	LintUnreachable,
+}
+
+// LintConfig controls which rules Lint checks, and how
+type LintConfig struct {
+	// Mode is "signature" or "application". If empty, a program that uses
+	// application opcodes is linted as an application, and anything else as
+	// a logic signature.
+	Mode string
+
+	// Disable lists rules that should not be checked
+	Disable []LintRule
+
+	// MaxFee, if not zero, is the largest Fee bound that is not reported
+	MaxFee uint64
+}
+
+// LintWarning is a likely mistake in a program, found by Lint
+type LintWarning struct {
+	Rule LintRule
+	// PC is the offset of the instruction the warning is about
+	PC      int
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("pc=%d [%s] %s", w.PC, w.Rule, w.Message)
+}
+
+// Lint checks a compiled program for common security mistakes. It returns an
+// error only if the program can not be decoded; problems found in a valid
+// program are returned as warnings, ordered by PC.
+func Lint(program []byte, config LintConfig) ([]LintWarning, error) {
+	var mode runMode
+	switch config.Mode {
+	case "":
+		mode = modeAny
+	case "signature":
+		mode = runModeSignature
+	case "application":
+		mode = runModeApplication
+	default:
+		return nil, fmt.Errorf("unknown lint mode %#v", config.Mode)
+	}
+
+	l := linter{
+		config:    config,
+		reached:   make(map[int]bool),
+		exits:     make(map[int]lintFacts),
+		summaries: make(map[int]lintFacts),
+		disabled:  make(map[LintRule]bool),
+	}
+	known := make(map[LintRule]bool, len(LintRules))
+	for _, rule := range LintRules {
+		known[rule] = true
+	}
+	for _, rule := range config.Disable {
+		if !known[rule] {
+			return nil, fmt.Errorf("unknown lint rule %#v", rule)
+		}
+		l.disabled[rule] = true
+	}
+	d, err := decodeProgram(program, mode)
+	if err != nil {
+		return nil, err
+	}
+	l.decodedProgram = d
+	err = l.scan()
+	if err != nil {
+		return nil, err
+	}
+	if mode == modeAny {
+		mode = runModeSignature
+		for _, inst := range l.insts {
+			if inst.spec.Modes == runModeApplication {
+				mode = runModeApplication
+				break
+			}
+		}
+	}
+	l.mode = mode
+
+	if len(l.insts) > 0 {
+		l.analyze(l.insts[0].pc, lintFacts{})
+	}
+	return l.warnings(), nil
+}
+
+// lintFacts are the conditions known to hold at a point in the program. The
+// value of a fact depends on its key: an upper bound for Fee and GroupSize,
+// the exact value for TypeEnum and ApplicationID, and nothing for the
+// address fields and OnCompletion, which are only known to have been checked.
+type lintFacts map[string]uint64
+
+// lintBounded are the facts that record an upper bound
+var lintBounded = map[string]bool{"Fee": true, "GroupSize": true}
+
+// lintExact are the facts that record an exact value
+var lintExact = map[string]bool{"TypeEnum": true, "ApplicationID": true}
+
+func (f lintFacts) clone() lintFacts {
+	c := make(lintFacts, len(f))
+	for k, v := range f {
+		c[k] = v
+	}
+	return c
+}
+
+// union adds the facts in o, keeping the tighter of two bounds
+func (f lintFacts) union(o lintFacts) lintFacts {
+	u := f.clone()
+	for k, v := range o {
+		if old, ok := u[k]; ok && lintBounded[k] && old < v {
+			continue
+		}
+		u[k] = v
+	}
+	return u
+}
+
+// intersect keeps the facts known in both f and o, at the looser of two bounds
+func (f lintFacts) intersect(o lintFacts) lintFacts {
+	i := make(lintFacts)
+	for k, v := range f {
+		ov, ok := o[k]
+		if !ok {
+			continue
+		}
+		switch {
+		case v == ov:
+			i[k] = v
+		case lintBounded[k]:
+			if ov > v {
+				v = ov
+			}
+			i[k] = v
+		}
+	}
+	return i
+}
+
+func (f lintFacts) equal(o lintFacts) bool {
+	if len(f) != len(o) {
+		return false
+	}
+	for k, v := range f {
+		if ov, ok := o[k]; !ok || ov != v {
+			return false
+		}
+	}
+	return true
+}
+
+type lintValueKind int
+
+const (
+	lintUnknown lintValueKind = iota
+	lintInt
+	lintBytes
+	// lintField is a txn or global field, named by key
+	lintField
+	// lintCond is a truth value which, when true (false if negated), implies facts
+	lintCond
+)
+
+type lintValue struct {
+	kind    lintValueKind
+	uint    uint64
+	bytes   []byte
+	key     string
+	facts   lintFacts
+	negated bool
+}
+
+// cond returns the facts implied by v being true, and by v being false
+func (v lintValue) cond() (whenTrue lintFacts, whenFalse lintFacts) {
+	switch v.kind {
+	case lintCond:
+		if v.negated {
+			return nil, v.facts
+		}
+		return v.facts, nil
+	case lintField:
+		return nil, lintFacts{v.key: 0}
+	}
+	return nil, nil
+}
+
+type linter struct {
+	*decodedProgram
+	config LintConfig
+	mode   runMode
+
+	intc  []uint64
+	bytec [][]byte
+
+	usesGroup bool
+	reached   map[int]bool
+	exits     map[int]lintFacts // approving exits by pc, with the facts known there
+	summaries map[int]lintFacts // facts established by subroutines, by entry pc
+	disabled  map[LintRule]bool
+}
+
+// scan finds the constants and group accesses of the program
+func (l *linter) scan() (err error) {
+	for _, inst := range l.insts {
+		switch inst.spec.Name {
+		case "intcblock":
+			l.intc, _, err = parseIntcblock(l.program, inst.pc)
+		case "bytecblock":
+			l.bytec, _, err = parseBytecBlock(l.program, inst.pc)
+		case "gtxn", "gtxna", "gtxns", "gtxnsa", "gload", "gloads", "gaid", "gaids", "gretdata", "gretdatas":
+			l.usesGroup = true
+		}
+		if err != nil {
+			return fmt.Errorf("pc=%3d %w", inst.pc, err)
+		}
+	}
+	return nil
+}
+
+// analyze follows the control flow from entry until the facts at every
+// reachable block stop changing. It returns the facts known at every retsub
+// reached, and whether any was.
+func (l *linter) analyze(entry int, facts lintFacts) (lintFacts, bool) {
+	states := map[int]lintFacts{entry: facts}
+	work := []int{entry}
+	var ret lintFacts
+	returns := false
+	for len(work) > 0 {
+		pc := work[len(work)-1]
+		work = work[:len(work)-1]
+		edges, retFacts, retsub := l.runBlock(pc, states[pc].clone())
+		if retsub {
+			if returns {
+				ret = ret.intersect(retFacts)
+			} else {
+				ret = retFacts
+				returns = true
+			}
+		}
+		for _, e := range edges {
+			old, seen := states[e.target]
+			if !seen {
+				states[e.target] = e.facts
+				work = append(work, e.target)
+				continue
+			}
+			merged := old.intersect(e.facts)
+			if !merged.equal(old) {
+				states[e.target] = merged
+				work = append(work, e.target)
+			}
+		}
+	}
+	return ret, returns
+}
+
+type lintEdge struct {
+	target int
+	facts  lintFacts
+}
+
+// exit records an exit from the program at pc, with v on top of the stack
+func (l *linter) exit(pc int, facts lintFacts, v lintValue) {
+	if v.kind == lintInt && v.uint == 0 {
+		return
+	}
+	whenTrue, _ := v.cond()
+	facts = facts.union(whenTrue)
+	if old, ok := l.exits[pc]; ok {
+		facts = old.intersect(facts)
+	}
+	l.exits[pc] = facts
+}
+
+// summary returns the facts a subroutine establishes by the time it
+// returns. A recursive call is assumed to establish nothing.
+func (l *linter) summary(entry int) lintFacts {
+	if facts, ok := l.summaries[entry]; ok {
+		return facts
+	}
+	l.summaries[entry] = lintFacts{}
+	facts, _ := l.analyze(entry, lintFacts{})
+	if facts == nil {
+		facts = lintFacts{}
+	}
+	l.summaries[entry] = facts
+	return facts
+}
+
+// runBlock interprets the basic block starting at pc, tracking just enough
+// of the stack to see which conditions are checked. It returns the
+// successors of the block, or the facts known at the retsub ending it.
+func (l *linter) runBlock(pc int, facts lintFacts) (edges []lintEdge, retFacts lintFacts, retsub bool) {
+	var stack []lintValue
+	pop := func() lintValue {
+		if len(stack) == 0 {
+			return lintValue{}
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v
+	}
+	push := func(vs ...lintValue) {
+		stack = append(stack, vs...)
+	}
+	top := func() lintValue {
+		if len(stack) == 0 {
+			return lintValue{}
+		}
+		return stack[len(stack)-1]
+	}
+	// follow goes to target, which may be the end of the program
+	follow := func(inst programInst, target int, facts lintFacts) {
+		if target >= len(l.program) {
+			l.exit(inst.pc, facts, top())
+			return
+		}
+		edges = append(edges, lintEdge{target, facts})
+	}
+
+	for i := l.index[pc]; i < len(l.insts); i++ {
+		inst := l.insts[i]
+		l.reached[inst.pc] = true
+		imm := inst.pc + 1
+
+		switch inst.spec.Name {
+		case "intc_0", "intc_1", "intc_2", "intc_3", "intc":
+			n := int(inst.spec.Opcode - 0x22)
+			if inst.spec.Name == "intc" {
+				n = int(l.program[imm])
+			}
+			if n < len(l.intc) {
+				push(lintValue{kind: lintInt, uint: l.intc[n]})
+			} else {
+				push(lintValue{})
+			}
+		case "pushint":
+			val, _ := binary.Uvarint(l.program[imm:])
+			push(lintValue{kind: lintInt, uint: val})
+		case "bytec_0", "bytec_1", "bytec_2", "bytec_3", "bytec":
+			n := int(inst.spec.Opcode - 0x28)
+			if inst.spec.Name == "bytec" {
+				n = int(l.program[imm])
+			}
+			if n < len(l.bytec) {
+				push(lintValue{kind: lintBytes, bytes: l.bytec[n]})
+			} else {
+				push(lintValue{})
+			}
+		case "pushbytes":
+			length, n := binary.Uvarint(l.program[imm:])
+			start := imm + n
+			push(lintValue{kind: lintBytes, bytes: l.program[start : start+int(length)]})
+		case "txn":
+			field := TxnField(l.program[imm])
+			switch field {
+			case Fee, CloseRemainderTo, AssetCloseTo, RekeyTo, ApplicationID, OnCompletion, TypeEnum, Type:
+				push(lintValue{kind: lintField, key: field.String()})
+			default:
+				push(lintValue{})
+			}
+		case "global":
+			if GlobalField(l.program[imm]) == GroupSize {
+				push(lintValue{kind: lintField, key: GroupSize.String()})
+			} else {
+				push(lintValue{})
+			}
+		case "==", "!=", "<", "<=", ">", ">=":
+			b := pop()
+			a := pop()
+			push(lintCompare(inst.spec.Name, a, b))
+		case "&&":
+			b := pop()
+			a := pop()
+			at, _ := a.cond()
+			bt, _ := b.cond()
+			if at != nil || bt != nil {
+				push(lintValue{kind: lintCond, facts: lintFacts{}.union(at).union(bt)})
+			} else {
+				push(lintValue{})
+			}
+		case "!":
+			a := pop()
+			switch a.kind {
+			case lintCond:
+				a.negated = !a.negated
+				push(a)
+			case lintField:
+				push(lintValue{kind: lintCond, facts: lintFacts{a.key: 0}})
+			default:
+				push(lintValue{})
+			}
+		case "dup":
+			a := pop()
+			push(a, a)
+		case "dup2":
+			b := pop()
+			a := pop()
+			push(a, b, a, b)
+		case "swap":
+			b := pop()
+			a := pop()
+			push(b, a)
+		case "dig":
+			n := int(l.program[imm])
+			if n < len(stack) {
+				push(stack[len(stack)-1-n])
+			} else {
+				push(lintValue{})
+			}
+		case "pop":
+			pop()
+		case "assert":
+			whenTrue, _ := pop().cond()
+			facts = facts.union(whenTrue)
+		case "bnz", "bz":
+			whenTrue, whenFalse := pop().cond()
+			taken, fallen := facts.union(whenTrue), facts.union(whenFalse)
+			if inst.spec.Name == "bz" {
+				taken, fallen = fallen, taken
+			}
+			follow(inst, l.target(inst), taken)
+			follow(inst, inst.next, fallen)
+			return
+		case "b":
+			follow(inst, l.target(inst), facts)
+			return
+		case "callsub":
+			follow(inst, inst.next, facts.union(l.summary(l.target(inst))))
+			return
+		case "retsub":
+			return nil, facts, true
+		case "return":
+			l.exit(inst.pc, facts, pop())
+			return
+		case "err":
+			return
+		default:
+			for range inst.spec.Args {
+				pop()
+			}
+			for range inst.spec.Returns {
+				push(lintValue{})
+			}
+		}
+
+		if inst.next >= len(l.program) {
+			l.exit(inst.pc, facts, top())
+			return
+		}
+		if l.leaders[inst.next] {
+			edges = append(edges, lintEdge{inst.next, facts})
+			return
+		}
+	}
+	return
+}
+
+// lintCompare returns the value of a comparison. A comparison of a tracked
+// field becomes a condition carrying what it implies about the field.
+func lintCompare(op string, a, b lintValue) lintValue {
+	if a.kind != lintField {
+		if b.kind != lintField {
+			return lintValue{}
+		}
+		a, b = b, a
+		switch op {
+		case "<":
+			op = ">"
+		case "<=":
+			op = ">="
+		case ">":
+			op = "<"
+		case ">=":
+			op = "<="
+		}
+	}
+
+	key := a.key
+	if key == "Type" {
+		key = "TypeEnum"
+		if b.kind == lintBytes {
+			tt, ok := txnTypeIndexes[string(b.bytes)]
+			if !ok {
+				return lintValue{}
+			}
+			b = lintValue{kind: lintInt, uint: tt}
+		}
+	}
+
+	// A comparison that fails when the field is known is that comparison, negated
+	negated := false
+	switch op {
+	case "!=":
+		op, negated = "==", true
+	case ">":
+		op, negated = "<=", true
+	case ">=":
+		op, negated = "<", true
+	}
+
+	var value uint64
+	switch op {
+	case "==":
+		switch {
+		case lintExact[key]:
+			if b.kind != lintInt {
+				return lintValue{}
+			}
+			value = b.uint
+		case lintBounded[key]:
+			value = math.MaxUint64
+			if b.kind == lintInt {
+				value = b.uint
+			}
+		}
+	case "<=", "<":
+		if lintExact[key] {
+			return lintValue{}
+		}
+		if lintBounded[key] {
+			value = math.MaxUint64
+			if b.kind == lintInt {
+				value = b.uint
+				if op == "<" && value > 0 {
+					value--
+				}
+			}
+		}
+	}
+	return lintValue{kind: lintCond, facts: lintFacts{key: value}, negated: negated}
+}
+
+// warnings checks the recorded exits and reachability against the rules
+func (l *linter) warnings() []LintWarning {
+	var warnings []LintWarning
+	warn := func(rule LintRule, pc int, format string, args ...interface{}) {
+		if l.disabled[rule] {
+			return
+		}
+		warnings = append(warnings, LintWarning{Rule: rule, PC: pc, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for pc, facts := range l.exits {
+		_, known := facts["RekeyTo"]
+		typeEnum, typeKnown := facts["TypeEnum"]
+		if l.mode == runModeSignature {
+			if !known {
+				warn(LintRekeyTo, pc, "approves without checking RekeyTo")
+			}
+			if _, known := facts["CloseRemainderTo"]; !known && (!typeKnown || typeEnum == txnTypeIndexes[string(protocol.PaymentTx)]) {
+				warn(LintCloseRemainderTo, pc, "approves without checking CloseRemainderTo")
+			}
+			if _, known := facts["AssetCloseTo"]; !known && (!typeKnown || typeEnum == txnTypeIndexes[string(protocol.AssetTransferTx)]) {
+				warn(LintAssetCloseTo, pc, "approves without checking AssetCloseTo")
+			}
+			fee, known := facts["Fee"]
+			switch {
+			case !known:
+				warn(LintFee, pc, "approves without bounding Fee")
+			case fee == math.MaxUint64:
+				warn(LintFee, pc, "approves with Fee bounded only by a computed value")
+			case l.config.MaxFee != 0 && fee > l.config.MaxFee:
+				warn(LintFee, pc, "approves with Fee up to %d, more than %d", fee, l.config.MaxFee)
+			}
+			if _, known := facts["GroupSize"]; !known {
+				warn(LintGroupSize, pc, "approves without checking GroupSize")
+			}
+		} else {
+			if appID, known := facts["ApplicationID"]; !known || appID != 0 {
+				if _, known := facts["OnCompletion"]; !known {
+					warn(LintOnCompletion, pc, "approves without checking OnCompletion")
+				}
+			}
+			if _, known := facts["GroupSize"]; !known && l.usesGroup {
+				warn(LintGroupSize, pc, "approves without checking GroupSize, but reads other transactions in the group")
+			}
+		}
+	}
+
+	for i := 0; i < len(l.insts); i++ {
+		if l.reached[l.insts[i].pc] {
+			continue
+		}
+		warn(LintUnreachable, l.insts[i].pc, "unreachable code")
+		for i+1 < len(l.insts) && !l.reached[l.insts[i+1].pc] {
+			i++
+		}
+	}
+
+	order := make(map[LintRule]int, len(LintRules))
+	for i, rule := range LintRules {
+		order[rule] = i
+	}
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].PC != warnings[j].PC {
+			return warnings[i].PC < warnings[j].PC
+		}
+		return order[warnings[i].Rule] < order[warnings[j].Rule]
+	})
+	return warnings
+}
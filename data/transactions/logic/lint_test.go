@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// lintRules returns the rules warned about, in order
+func lintRules(t *testing.T, source string, config LintConfig) []LintRule {
+	t.Helper()
+	ops := testProg(t, source, AssemblerMaxVersion)
+	warnings, err := Lint(ops.Program, config)
+	require.NoError(t, err)
+	rules := []LintRule{}
+	for _, w := range warnings {
+		rules = append(rules, w.Rule)
+	}
+	return rules
+}
+
+func TestLintSignature(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []LintRule{LintRekeyTo, LintCloseRemainderTo, LintAssetCloseTo, LintFee, LintGroupSize},
+		lintRules(t, "int 1", LintConfig{}))
+
+	safe := `txn RekeyTo; global ZeroAddress; ==
+txn CloseRemainderTo; global ZeroAddress; ==; &&
+txn AssetCloseTo; global ZeroAddress; ==; &&
+txn Fee; int 1000; <=; &&
+global GroupSize; int 1; ==; &&`
+	require.Empty(t, lintRules(t, safe, LintConfig{}))
+	require.Equal(t, []LintRule{LintFee}, lintRules(t, safe, LintConfig{MaxFee: 500}))
+
+	// pinning the type means only the matching close field must be checked
+	asserted := `txn RekeyTo; global ZeroAddress; ==; assert
+txn Fee; int 1001; <; assert
+global GroupSize; int 2; <=; assert
+`
+	require.Empty(t, lintRules(t, asserted+"txn TypeEnum; int appl; ==; assert; int 1", LintConfig{}))
+	require.Equal(t, []LintRule{LintCloseRemainderTo},
+		lintRules(t, asserted+"txn Type; byte \"pay\"; ==; assert; int 1", LintConfig{}))
+
+	// checks made by failing early count only on the path they guard
+	branchy := `txn RekeyTo; global ZeroAddress; !=; bnz fail
+txn Fee; int 1000; >; bnz fail
+global GroupSize; int 1; ==; assert
+txn TypeEnum; int axfer; ==
+bnz xfer
+txn CloseRemainderTo; global ZeroAddress; ==; assert
+int 1; return
+xfer:
+int 1; return
+fail:
+int 0`
+	ops := testProg(t, branchy, AssemblerMaxVersion)
+	warnings, err := Lint(ops.Program, LintConfig{})
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	require.Equal(t, LintAssetCloseTo, warnings[0].Rule)
+	require.Equal(t, LintAssetCloseTo, warnings[1].Rule)
+	require.Less(t, warnings[0].PC, warnings[1].PC)
+	require.Empty(t, lintRules(t, branchy, LintConfig{Disable: []LintRule{LintAssetCloseTo}}))
+
+	// a subroutine's checks carry over to its callers
+	sub := `callsub check
+txn CloseRemainderTo; global ZeroAddress; ==
+txn AssetCloseTo; global ZeroAddress; ==; &&
+return
+check:
+txn RekeyTo; global ZeroAddress; ==; assert
+txn Fee; global MinTxnFee; ==; assert
+global GroupSize; int 1; ==; assert
+retsub`
+	require.Equal(t, []LintRule{LintFee}, lintRules(t, sub, LintConfig{}))
+}
+
+func TestLintApplication(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []LintRule{LintOnCompletion}, lintRules(t, `byte "k"; int 2; app_global_put; int 1`, LintConfig{}))
+	require.Empty(t, lintRules(t, "txn OnCompletion; int NoOp; ==", LintConfig{Mode: "application"}))
+	require.Empty(t, lintRules(t, "txn ApplicationID; bz create; txn OnCompletion; !; return; create: int 1", LintConfig{Mode: "application"}))
+	require.Equal(t, []LintRule{LintGroupSize},
+		lintRules(t, "txn OnCompletion; int NoOp; ==; assert; gtxn 1 Fee", LintConfig{Mode: "application"}))
+	require.Empty(t,
+		lintRules(t, "global GroupSize; int 2; ==; txn OnCompletion; int NoOp; ==; &&; assert; gtxn 1 Fee", LintConfig{Mode: "application"}))
+}
+
+func TestLintUnreachable(t *testing.T) {
+	t.Parallel()
+
+	config := LintConfig{Mode: "application"}
+	require.Equal(t, []LintRule{LintUnreachable}, lintRules(t, "int 0; return; int 1; int 2; +", config))
+	require.Equal(t, []LintRule{LintUnreachable}, lintRules(t, "b end; int 1; end: int 0", config))
+	require.Empty(t, lintRules(t, "int 0; bnz end; int 0; end: int 0", config))
+}
+
+func TestLintErrors(t *testing.T) {
+	t.Parallel()
+
+	ops := testProg(t, "int 1", AssemblerMaxVersion)
+	_, err := Lint(ops.Program, LintConfig{Mode: "stateful"})
+	require.EqualError(t, err, `unknown lint mode "stateful"`)
+	_, err = Lint(ops.Program, LintConfig{Disable: []LintRule{"rekey"}})
+	require.EqualError(t, err, `unknown lint rule "rekey"`)
+
+	ops = testProg(t, `byte "k"; int 2; app_global_put; int 1`, AssemblerMaxVersion)
+	_, err = Lint(ops.Program, LintConfig{Mode: "signature"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "app_global_put not allowed in current mode")
+
+	_, err = Lint(nil, LintConfig{})
+	require.EqualError(t, err, "invalid version")
+}
@@ -21,7 +21,7 @@ import (
 )
 
 // LogicVersion defines default assembler and max eval versions
-const LogicVersion = 4
+const LogicVersion = 5
 
 // rekeyingEnabledVersion is the version of TEAL where RekeyTo functionality
 // was enabled. This is important to remember so that old TEAL accounts cannot
@@ -42,6 +42,18 @@ const backBranchEnabledVersion = 4
 // using an index into arrays.
 const directRefEnabledVersion = 4
 
+// innerTxnsEnabledVersion is the first version of TEAL in which
+// applications may issue inner transactions
+const innerTxnsEnabledVersion = 5
+
+// boxesEnabledVersion is the first version of TEAL in which
+// applications may access their boxes
+const boxesEnabledVersion = 5
+
+// ecdsaEnabledVersion is the first version of TEAL with opcodes that
+// verify ECDSA signatures and recover or decompress ECDSA public keys
+const ecdsaEnabledVersion = 5
+
 // opDetails records details such as non-standard costs, immediate
 // arguments, or dynamic layout controlled by a check function.
 type opDetails struct {
@@ -58,6 +70,12 @@ func costly(cost int) opDetails {
 	return opDetails{cost, 1, nil, nil}
 }
 
+func costlyImm(cost int, name string, rest ...string) opDetails {
+	opd := immediates(name, rest...)
+	opd.Cost = cost
+	return opd
+}
+
 func immediates(name string, rest ...string) opDetails {
 	num := 1 + len(rest)
 	immediates := make([]immediate, num)
@@ -115,6 +133,8 @@ var oneAny = StackTypes{StackAny}
 var twoAny = StackTypes{StackAny, StackAny}
 var anyInt = StackTypes{StackAny, StackUint64}
 var anyIntInt = StackTypes{StackAny, StackUint64, StackUint64}
+var fiveBytes = StackTypes{StackBytes, StackBytes, StackBytes, StackBytes, StackBytes}
+var byteIntByteByte = StackTypes{StackBytes, StackUint64, StackBytes, StackBytes}
 
 // OpSpecs is the table of operations that can be assembled and evaluated.
 //
@@ -137,6 +157,9 @@ var OpSpecs = []OpSpec{
 	{0x03, "sha512_256", opSHA512_256, asmDefault, disDefault, oneBytes, oneBytes, 2, modeAny, costly(45)},
 
 	{0x04, "ed25519verify", opEd25519verify, asmDefault, disDefault, threeBytes, oneInt, 1, runModeSignature, costly(1900)},
+	{0x05, "ecdsa_verify", opEcdsaVerify, assembleEcdsa, disEcdsa, fiveBytes, oneInt, ecdsaEnabledVersion, modeAny, costlyImm(1700, "v")},
+	{0x06, "ecdsa_pk_decompress", opEcdsaPkDecompress, assembleEcdsa, disEcdsa, oneBytes, twoBytes, ecdsaEnabledVersion, modeAny, costlyImm(650, "v")},
+	{0x07, "ecdsa_pk_recover", opEcdsaPkRecover, assembleEcdsa, disEcdsa, byteIntByteByte, twoBytes, ecdsaEnabledVersion, modeAny, costlyImm(2000, "v")},
 	{0x08, "+", opPlus, asmDefault, disDefault, twoInts, oneInt, 1, modeAny, opDefault},
 	{0x09, "-", opMinus, asmDefault, disDefault, twoInts, oneInt, 1, modeAny, opDefault},
 	{0x0a, "/", opDiv, asmDefault, disDefault, twoInts, oneInt, 1, modeAny, opDefault},
@@ -200,6 +223,9 @@ var OpSpecs = []OpSpec{
 	// Access creatable IDs
 	{0x3c, "gaid", opGaid, asmDefault, disDefault, nil, oneInt, 4, runModeApplication, immediates("t")},
 	{0x3d, "gaids", opGaids, asmDefault, disDefault, oneInt, oneInt, 4, runModeApplication, opDefault},
+	// Group return data access
+	{0x3e, "gretdata", opGretdata, asmDefault, disDefault, nil, oneBytes.plus(oneInt), 5, modeAny, immediates("t", "i")},
+	{0x3f, "gretdatas", opGretdatas, asmDefault, disDefault, oneInt, oneBytes.plus(oneInt), 5, modeAny, immediates("i")},
 
 	{0x40, "bnz", opBnz, assembleBranch, disBranch, oneInt, nil, 1, modeAny, opBranch},
 	{0x41, "bz", opBz, assembleBranch, disBranch, oneInt, nil, 2, modeAny, opBranch},
@@ -243,6 +269,7 @@ var OpSpecs = []OpSpec{
 	{0x70, "asset_holding_get", opAssetHoldingGet, assembleAssetHolding, disAssetHolding, twoInts, oneAny.plus(oneInt), 2, runModeApplication, immediates("i")},
 	{0x70, "asset_holding_get", opAssetHoldingGet, assembleAssetHolding, disAssetHolding, oneAny.plus(oneInt), oneAny.plus(oneInt), directRefEnabledVersion, runModeApplication, immediates("i")},
 	{0x71, "asset_params_get", opAssetParamsGet, assembleAssetParams, disAssetParams, oneInt, oneAny.plus(oneInt), 2, runModeApplication, immediates("i")},
+	{0x72, "app_params_get", opAppParamsGet, assembleAppParams, disAppParams, oneInt, oneAny.plus(oneInt), 5, runModeApplication, immediates("i")},
 
 	{0x78, "min_balance", opMinBalance, asmDefault, disDefault, oneInt, oneInt, 3, runModeApplication, opDefault},
 	{0x78, "min_balance", opMinBalance, asmDefault, disDefault, oneAny, oneInt, directRefEnabledVersion, runModeApplication, opDefault},
@@ -281,6 +308,27 @@ var OpSpecs = []OpSpec{
 	{0xad, "b^", opBytesBitXor, asmDefault, disDefault, twoBytes, oneBytes, 4, modeAny, costly(6)},
 	{0xae, "b~", opBytesBitNot, asmDefault, disDefault, oneBytes, oneBytes, 4, modeAny, costly(4)},
 	{0xaf, "bzero", opBytesZero, asmDefault, disDefault, oneInt, oneBytes, 4, modeAny, opDefault},
+
+	{0xb0, "log", opLog, asmDefault, disDefault, oneBytes, nil, 5, runModeApplication, opDefault},
+
+	// Inner transactions
+	{0xb1, "itxn_begin", opTxBegin, asmDefault, disDefault, nil, nil, innerTxnsEnabledVersion, runModeApplication, opDefault},
+	{0xb2, "itxn_field", opTxField, assembleItxnField, disTxn, oneAny, nil, innerTxnsEnabledVersion, runModeApplication, immediates("f")},
+	{0xb3, "itxn_submit", opTxSubmit, asmDefault, disDefault, nil, nil, innerTxnsEnabledVersion, runModeApplication, opDefault},
+	{0xb4, "itxn", opItxn, assembleItxn, disTxn, nil, oneAny, innerTxnsEnabledVersion, runModeApplication, immediates("f")},
+
+	// Return data for later transactions in the group
+	{0xb5, "retdata_put", opRetdataPut, asmDefault, disDefault, oneBytes, nil, 5, modeAny, opDefault},
+
+	// Box storage
+	{0xb9, "box_create", opBoxCreate, asmDefault, disDefault, byteInt, oneInt, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xba, "box_extract", opBoxExtract, asmDefault, disDefault, byteIntInt, oneBytes, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xbb, "box_replace", opBoxReplace, asmDefault, disDefault, byteInt.plus(oneBytes), nil, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xbc, "box_del", opBoxDel, asmDefault, disDefault, oneBytes, oneInt, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xbd, "box_len", opBoxLen, asmDefault, disDefault, oneBytes, twoInts, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xbe, "box_get", opBoxGet, asmDefault, disDefault, oneBytes, byteInt, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xbf, "box_put", opBoxPut, asmDefault, disDefault, twoBytes, nil, boxesEnabledVersion, runModeApplication, opDefault},
+	{0xc0, "box_resize", opBoxResize, asmDefault, disDefault, byteInt, nil, boxesEnabledVersion, runModeApplication, opDefault},
 }
 
 type sortByOpcode []OpSpec
@@ -0,0 +1,283 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sourcePosition is a zero-based line and column in one of the
+// assembler's source files
+type sourcePosition struct {
+	file   int
+	line   int
+	column int
+}
+
+// SourceMapVersion is the version of the Source Map specification that
+// SourceMap follows.
+const SourceMapVersion = 3
+
+// SourceMap is debug information produced by the assembler, in Source Map
+// revision 3 format. Each line of the generated "file" is a program
+// counter, so the mapping for pc N is the N-th ';' separated group of
+// Mappings. A group holds one segment per label that points at the pc,
+// each carrying the label's index in Names, followed by a segment for the
+// instruction that starts at the pc, if any. The intcblock and bytecblock
+// values are described by the source text that introduced them.
+type SourceMap struct {
+	Version        int      `json:"version"`
+	File           string   `json:"file,omitempty"`
+	SourceRoot     string   `json:"sourceRoot,omitempty"`
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent,omitempty"`
+	Names          []string `json:"names"`
+	Mappings       string   `json:"mappings"`
+	IntcNames      []string `json:"x_algorand_intc,omitempty"`
+	BytecNames     []string `json:"x_algorand_bytec,omitempty"`
+}
+
+// SourceLocation is where in the source a program counter came from.
+// Source is an index into SourceMap.Sources. Line and Column are
+// zero-based, as in OpStream.OffsetToLine.
+type SourceLocation struct {
+	Source int
+	Line   int
+	Column int
+	Labels []string
+}
+
+// SourceMap returns the source map of an assembled program. name is the
+// name of the program's source file and is recorded, along with source
+// when it is not empty, so that the map alone is enough to debug the
+// program. Files read by #include follow it in Sources.
+func (ops *OpStream) SourceMap(name, source string) SourceMap {
+	sm := SourceMap{
+		Version: SourceMapVersion,
+		Sources: []string{name},
+		Names:   []string{},
+	}
+	if len(ops.Files) > 1 {
+		sm.Sources = append(sm.Sources, ops.Files[1:]...)
+	}
+	if source != "" {
+		sm.SourcesContent = []string{source}
+		if len(ops.fileTexts) > 1 {
+			sm.SourcesContent = append(sm.SourcesContent, ops.fileTexts[1:]...)
+		}
+	}
+
+	// labels sorted by pc, then by where they appear in the source
+	labelsAt := make(map[int][]string)
+	nameIndex := make(map[string]int)
+	labels := make([]string, 0, len(ops.labels))
+	for label := range ops.labels {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		li, lj := ops.labelSources[labels[i]], ops.labelSources[labels[j]]
+		if li.file != lj.file {
+			return li.file < lj.file
+		}
+		if li.line != lj.line {
+			return li.line < lj.line
+		}
+		if li.column != lj.column {
+			return li.column < lj.column
+		}
+		// the same label in different expansions of a macro
+		return ops.labels[labels[i]] < ops.labels[labels[j]]
+	})
+	for _, label := range labels {
+		pc := ops.labels[label]
+		labelsAt[pc] = append(labelsAt[pc], label)
+		nameIndex[label] = len(sm.Names)
+		sm.Names = append(sm.Names, label)
+	}
+
+	lines := len(ops.Program)
+	if len(labelsAt[lines]) > 0 {
+		// labels at the very end of the program are jump targets too
+		lines++
+	}
+
+	var mappings strings.Builder
+	var prev struct{ file, line, column, name int }
+	for pc := 0; pc < lines; pc++ {
+		if pc > 0 {
+			mappings.WriteByte(';')
+		}
+		segments := 0
+		writeSegment := func(pos sourcePosition, name int) {
+			if segments > 0 {
+				mappings.WriteByte(',')
+			}
+			segments++
+			// the generated column is always 0
+			mappings.WriteString(vlqEncode(0))
+			mappings.WriteString(vlqEncode(pos.file - prev.file))
+			mappings.WriteString(vlqEncode(pos.line - prev.line))
+			mappings.WriteString(vlqEncode(pos.column - prev.column))
+			prev.file, prev.line, prev.column = pos.file, pos.line, pos.column
+			if name >= 0 {
+				mappings.WriteString(vlqEncode(name - prev.name))
+				prev.name = name
+			}
+		}
+		for _, label := range labelsAt[pc] {
+			writeSegment(ops.labelSources[label], nameIndex[label])
+		}
+		if pos, ok := ops.offsetToSource[pc]; ok {
+			writeSegment(pos, -1)
+		}
+	}
+	sm.Mappings = mappings.String()
+
+	if len(ops.intc) > 0 {
+		sm.IntcNames = make([]string, len(ops.intc))
+		for i, val := range ops.intc {
+			if name, ok := ops.intcNames[val]; ok {
+				sm.IntcNames[i] = name
+			} else {
+				sm.IntcNames[i] = strconv.FormatUint(val, 10)
+			}
+		}
+	}
+	if len(ops.bytec) > 0 {
+		sm.BytecNames = make([]string, len(ops.bytec))
+		for i, val := range ops.bytec {
+			if name, ok := ops.bytecNames[string(val)]; ok {
+				sm.BytecNames[i] = name
+			} else {
+				sm.BytecNames[i] = guessByteFormat(val)
+			}
+		}
+	}
+	return sm
+}
+
+// Locations decodes the mappings of a source map into the source location
+// of each program counter that has one.
+func (sm SourceMap) Locations() (map[int]SourceLocation, error) {
+	if sm.Version != SourceMapVersion {
+		return nil, fmt.Errorf("unsupported source map version %d", sm.Version)
+	}
+	locations := make(map[int]SourceLocation)
+	var line, column, source, name int
+	for pc, group := range strings.Split(sm.Mappings, ";") {
+		if group == "" {
+			continue
+		}
+		var loc SourceLocation
+		for _, segment := range strings.Split(group, ",") {
+			fields, err := vlqDecode(segment)
+			if err != nil {
+				return nil, fmt.Errorf("pc %d: %w", pc, err)
+			}
+			switch len(fields) {
+			case 1:
+				// a generated position with no source
+				continue
+			case 4, 5:
+			default:
+				return nil, fmt.Errorf("pc %d: segment has %d fields", pc, len(fields))
+			}
+			source += fields[1]
+			if source < 0 || source >= len(sm.Sources) {
+				return nil, fmt.Errorf("pc %d: source %d is not in the source map", pc, source)
+			}
+			line += fields[2]
+			column += fields[3]
+			loc.Source, loc.Line, loc.Column = source, line, column
+			if len(fields) == 5 {
+				name += fields[4]
+				if name < 0 || name >= len(sm.Names) {
+					return nil, fmt.Errorf("pc %d: name %d is not in the source map", pc, name)
+				}
+				loc.Labels = append(loc.Labels, sm.Names[name])
+			}
+		}
+		locations[pc] = loc
+	}
+	return locations, nil
+}
+
+const vlqBase64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+const (
+	vlqShift        = 5
+	vlqContinuation = 1 << vlqShift
+	vlqMask         = vlqContinuation - 1
+)
+
+// vlqEncode encodes v as a base64 variable length quantity, with the sign
+// in the least significant bit.
+func vlqEncode(v int) string {
+	var u uint64
+	if v < 0 {
+		u = uint64(-v)<<1 | 1
+	} else {
+		u = uint64(v) << 1
+	}
+	var out []byte
+	for {
+		digit := u & vlqMask
+		u >>= vlqShift
+		if u > 0 {
+			digit |= vlqContinuation
+		}
+		out = append(out, vlqBase64[digit])
+		if u == 0 {
+			return string(out)
+		}
+	}
+}
+
+// vlqDecode decodes a segment of base64 variable length quantities
+func vlqDecode(segment string) ([]int, error) {
+	var fields []int
+	var u uint64
+	shift := uint(0)
+	for i := 0; i < len(segment); i++ {
+		digit := strings.IndexByte(vlqBase64, segment[i])
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid character %q in mappings", segment[i])
+		}
+		if shift > 60 {
+			return nil, errors.New("mappings value overflows")
+		}
+		u |= uint64(digit&vlqMask) << shift
+		if digit&vlqContinuation != 0 {
+			shift += vlqShift
+			continue
+		}
+		v := int(u >> 1)
+		if u&1 != 0 {
+			v = -v
+		}
+		fields = append(fields, v)
+		u, shift = 0, 0
+	}
+	if shift != 0 {
+		return nil, errors.New("mappings end in the middle of a value")
+	}
+	return fields, nil
+}
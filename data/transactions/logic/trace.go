@@ -0,0 +1,244 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// Events of a trace
+const (
+	// TraceRegister starts the trace of a program
+	TraceRegister = "register"
+	// TraceStep is the state before the instruction at PC runs
+	TraceStep = "step"
+	// TraceComplete is the state after the program exits
+	TraceComplete = "complete"
+)
+
+// TraceValue is a stack or scratch value in a trace
+type TraceValue struct {
+	// Type is "uint" or "bytes"
+	Type  string `json:"type"`
+	Uint  uint64 `json:"uint,omitempty"`
+	Bytes []byte `json:"bytes,omitempty"`
+}
+
+// TraceScratch is a scratch slot that was written
+type TraceScratch struct {
+	Slot  int        `json:"slot"`
+	Value TraceValue `json:"value"`
+}
+
+// TraceStateChange is a change to global or local application state. Local
+// changes name the account by its index in the Accounts of the transaction,
+// where 0 is the sender. Value is not set for deletes.
+type TraceStateChange struct {
+	Scope   string      `json:"scope"`
+	Account *uint64     `json:"account,omitempty"`
+	Key     []byte      `json:"key"`
+	Action  string      `json:"action"`
+	Value   *TraceValue `json:"value,omitempty"`
+}
+
+// TraceEvent is a line of a trace written by TraceWriter. Scratch, state
+// changes and logs only hold what changed since the previous line, so that
+// each line stays small.
+type TraceEvent struct {
+	Event string `json:"event"`
+
+	// set on register
+	ExecID     string `json:"execid,omitempty"`
+	GroupIndex int    `json:"group-index,omitempty"`
+	Budget     int    `json:"budget,omitempty"`
+
+	PC           int                `json:"pc,omitempty"`
+	Line         int                `json:"line,omitempty"`
+	Op           string             `json:"op,omitempty"`
+	Cost         int                `json:"cost,omitempty"`
+	Stack        []TraceValue       `json:"stack,omitempty"`
+	Scratch      []TraceScratch     `json:"scratch,omitempty"`
+	StateChanges []TraceStateChange `json:"state-changes,omitempty"`
+	Logs         [][]byte           `json:"logs,omitempty"`
+	Error        string             `json:"error,omitempty"`
+}
+
+// TraceWriter is a DebuggerHook that writes every step of the programs it
+// is given to an io.Writer as JSON lines, one TraceEvent per line. One
+// TraceWriter can trace several programs one after the other.
+type TraceWriter struct {
+	enc *json.Encoder
+
+	// state of the program being traced, to report what each step changed
+	lines   []string
+	scratch []basics.TealValue
+	global  basics.StateDelta
+	locals  map[uint64]basics.StateDelta
+	logs    int
+}
+
+// MakeTraceWriter creates a TraceWriter writing to w
+func MakeTraceWriter(w io.Writer) *TraceWriter {
+	return &TraceWriter{enc: json.NewEncoder(w)}
+}
+
+// Register is fired on program creation (DebuggerHook interface)
+func (tw *TraceWriter) Register(state *DebugState) error {
+	tw.lines = strings.Split(state.Disassembly, "\n")
+	tw.scratch = nil
+	tw.global = nil
+	tw.locals = nil
+	tw.logs = 0
+	return tw.enc.Encode(TraceEvent{
+		Event:      TraceRegister,
+		ExecID:     state.ExecID,
+		GroupIndex: state.GroupIndex,
+		Budget:     state.Budget,
+	})
+}
+
+// Update is fired on every step (DebuggerHook interface)
+func (tw *TraceWriter) Update(state *DebugState) error {
+	event := tw.event(TraceStep, state)
+	if state.Line >= 0 && state.Line < len(tw.lines) {
+		fields := strings.Fields(tw.lines[state.Line])
+		if len(fields) > 0 {
+			event.Op = fields[0]
+		}
+	}
+	return tw.enc.Encode(event)
+}
+
+// Complete is called when the program exits (DebuggerHook interface)
+func (tw *TraceWriter) Complete(state *DebugState) error {
+	event := tw.event(TraceComplete, state)
+	event.Error = state.Error
+	return tw.enc.Encode(event)
+}
+
+func (tw *TraceWriter) event(name string, state *DebugState) TraceEvent {
+	event := TraceEvent{
+		Event: name,
+		PC:    state.PC,
+		Line:  state.Line,
+		Cost:  state.Cost,
+		Stack: traceValues(state.Stack),
+	}
+
+	for i, tv := range state.Scratch {
+		// scratch slots start out as uint 0
+		last := basics.TealValue{Type: basics.TealUintType}
+		if i < len(tw.scratch) {
+			last = tw.scratch[i]
+		}
+		if tv != last {
+			event.Scratch = append(event.Scratch, TraceScratch{Slot: i, Value: traceValue(tv)})
+		}
+	}
+	tw.scratch = state.Scratch
+
+	event.StateChanges = append(event.StateChanges, stateChanges("global", nil, tw.global, state.GlobalDelta)...)
+	accounts := make([]uint64, 0, len(state.LocalDeltas))
+	for account := range state.LocalDeltas {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i] < accounts[j] })
+	for _, account := range accounts {
+		account := account
+		event.StateChanges = append(event.StateChanges, stateChanges("local", &account, tw.locals[account], state.LocalDeltas[account])...)
+	}
+	// a ledger may keep changing the deltas it returned, so keep copies
+	tw.global = copyStateDelta(state.GlobalDelta)
+	tw.locals = make(map[uint64]basics.StateDelta, len(state.LocalDeltas))
+	for account, delta := range state.LocalDeltas {
+		tw.locals[account] = copyStateDelta(delta)
+	}
+
+	if len(state.Logs) > tw.logs {
+		for _, log := range state.Logs[tw.logs:] {
+			event.Logs = append(event.Logs, []byte(log))
+		}
+		tw.logs = len(state.Logs)
+	}
+	return event
+}
+
+// traceValue converts a value of a DebugState, whose bytes are base64
+// encoded, into a TraceValue
+func traceValue(tv basics.TealValue) TraceValue {
+	if tv.Type == basics.TealBytesType {
+		data, err := base64.StdEncoding.DecodeString(tv.Bytes)
+		if err != nil {
+			data = []byte(tv.Bytes)
+		}
+		return TraceValue{Type: "bytes", Bytes: data}
+	}
+	return TraceValue{Type: "uint", Uint: tv.Uint}
+}
+
+func traceValues(values []basics.TealValue) []TraceValue {
+	if len(values) == 0 {
+		return nil
+	}
+	res := make([]TraceValue, len(values))
+	for i, tv := range values {
+		res[i] = traceValue(tv)
+	}
+	return res
+}
+
+func copyStateDelta(delta basics.StateDelta) basics.StateDelta {
+	res := make(basics.StateDelta, len(delta))
+	for key, vd := range delta {
+		res[key] = vd
+	}
+	return res
+}
+
+// stateChanges reports the keys of delta that differ from last, in key order
+func stateChanges(scope string, account *uint64, last basics.StateDelta, delta basics.StateDelta) []TraceStateChange {
+	keys := make([]string, 0, len(delta))
+	for key, vd := range delta {
+		if prev, ok := last[key]; !ok || prev != vd {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := make([]TraceStateChange, len(keys))
+	for i, key := range keys {
+		vd := delta[key]
+		changes[i] = TraceStateChange{Scope: scope, Account: account, Key: []byte(key)}
+		switch vd.Action {
+		case basics.SetBytesAction:
+			changes[i].Action = "set"
+			changes[i].Value = &TraceValue{Type: "bytes", Bytes: []byte(vd.Bytes)}
+		case basics.SetUintAction:
+			changes[i].Action = "set"
+			changes[i].Value = &TraceValue{Type: "uint", Uint: vd.Uint}
+		default:
+			changes[i].Action = "delete"
+		}
+	}
+	return changes
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package logic
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/stretchr/testify/require"
+)
+
+func readTrace(t *testing.T, trace *bytes.Buffer) []TraceEvent {
+	var events []TraceEvent
+	for _, line := range strings.Split(strings.TrimSpace(trace.String()), "\n") {
+		var event TraceEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event), line)
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestTraceWriter(t *testing.T) {
+	t.Parallel()
+
+	ops := testProg(t, `pushint 7; store 1; pushbytes 0x01ff; load 1; pop; pop; pushint 1`, 3)
+	var trace bytes.Buffer
+	ep := defaultEvalParams(nil, nil)
+	ep.Debugger = MakeTraceWriter(&trace)
+	pass, err := Eval(ops.Program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	events := readTrace(t, &trace)
+	require.Len(t, events, 9)
+	require.Equal(t, TraceRegister, events[0].Event)
+	require.Equal(t, GetProgramID(ops.Program), events[0].ExecID)
+
+	steps := events[1:8]
+	var names []string
+	for i, step := range steps {
+		require.Equal(t, TraceStep, step.Event)
+		require.Equal(t, i, step.Cost)
+		names = append(names, step.Op)
+	}
+	require.Equal(t, []string{"pushint", "store", "pushbytes", "load", "pop", "pop", "pushint"}, names)
+	require.Equal(t, 1, steps[0].PC)
+	require.Empty(t, steps[0].Stack)
+	require.Equal(t, []TraceValue{{Type: "uint", Uint: 7}}, steps[1].Stack)
+
+	// scratch is only reported when it changes
+	require.Empty(t, steps[1].Scratch)
+	require.Equal(t, []TraceScratch{{Slot: 1, Value: TraceValue{Type: "uint", Uint: 7}}}, steps[2].Scratch)
+	require.Empty(t, steps[3].Scratch)
+	require.Equal(t, []TraceValue{{Type: "bytes", Bytes: []byte{1, 0xff}}, {Type: "uint", Uint: 7}}, steps[4].Stack)
+
+	complete := events[8]
+	require.Equal(t, TraceComplete, complete.Event)
+	require.Equal(t, len(ops.Program), complete.PC)
+	require.Equal(t, 7, complete.Cost)
+	require.Equal(t, []TraceValue{{Type: "uint", Uint: 1}}, complete.Stack)
+	require.Empty(t, complete.Error)
+
+	// a failing program reports its error, and a writer can be reused
+	trace.Reset()
+	ops = testProg(t, `int 1; int 0; /`, 2)
+	pass, err = Eval(ops.Program, ep)
+	require.Error(t, err)
+	require.False(t, pass)
+	events = readTrace(t, &trace)
+	require.Equal(t, TraceRegister, events[0].Event)
+	require.Contains(t, events[len(events)-1].Error, "/ 0")
+}
+
+func TestTraceWriterState(t *testing.T) {
+	t.Parallel()
+
+	txn := makeSampleTxn()
+	txgroup := makeSampleTxnGroup(txn)
+	ledger := makeTestLedger(map[basics.Address]uint64{txn.Txn.Sender: 1})
+	ledger.newApp(txn.Txn.Sender, 100, makeSchemas(0, 1, 1, 0))
+	ep := defaultEvalParams(nil, &txn)
+	ep.TxnGroup = txgroup
+	ep.Ledger = ledger
+	ep.Txn.Txn.ApplicationID = 100
+
+	ops := testProg(t, `byte "g"; int 5; app_global_put; int 0; byte "l"; byte "v"; app_local_put; byte "hi"; log; int 1`, AssemblerMaxVersion)
+	var trace bytes.Buffer
+	ep.Debugger = MakeTraceWriter(&trace)
+	pass, err := EvalStateful(ops.Program, ep)
+	require.NoError(t, err)
+	require.True(t, pass)
+
+	var changes []TraceStateChange
+	var logs [][]byte
+	for _, event := range readTrace(t, &trace) {
+		changes = append(changes, event.StateChanges...)
+		logs = append(logs, event.Logs...)
+	}
+	sender := uint64(0)
+	require.Equal(t, []TraceStateChange{
+		{Scope: "global", Key: []byte("g"), Action: "set", Value: &TraceValue{Type: "uint", Uint: 5}},
+		{Scope: "local", Account: &sender, Key: []byte("l"), Action: "set", Value: &TraceValue{Type: "bytes", Bytes: []byte("v")}},
+	}, changes)
+	require.Equal(t, [][]byte{[]byte("hi")}, logs)
+}
@@ -3,6 +3,8 @@ package transactions
 // Code generated by github.com/algorand/msgp DO NOT EDIT.
 
 import (
+	"sort"
+
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/go-algorand/data/basics"
@@ -58,6 +60,14 @@ import (
 //           |-----> (*) Msgsize
 //           |-----> (*) MsgIsZero
 //
+// EvalDelta
+//     |-----> (*) MarshalMsg
+//     |-----> (*) CanMarshalMsg
+//     |-----> (*) UnmarshalMsg
+//     |-----> (*) CanUnmarshalMsg
+//     |-----> (*) Msgsize
+//     |-----> (*) MsgIsZero
+//
 // Header
 //    |-----> (*) MarshalMsg
 //    |-----> (*) CanMarshalMsg
@@ -1611,6 +1621,441 @@ func (z *CompactCertTxnFields) MsgIsZero() bool {
 	return ((*z).CertRound.MsgIsZero()) && ((*z).CertType.MsgIsZero()) && ((*z).Cert.MsgIsZero())
 }
 
+// MarshalMsg implements msgp.Marshaler
+func (z *EvalDelta) MarshalMsg(b []byte) (o []byte) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0006Len := uint32(5)
+	var zb0006Mask uint8 /* 6 bits */
+	if (*z).GlobalDelta.MsgIsZero() {
+		zb0006Len--
+		zb0006Mask |= 0x2
+	}
+	if len((*z).InnerTxns) == 0 {
+		zb0006Len--
+		zb0006Mask |= 0x4
+	}
+	if len((*z).LocalDeltas) == 0 {
+		zb0006Len--
+		zb0006Mask |= 0x8
+	}
+	if len((*z).Logs) == 0 {
+		zb0006Len--
+		zb0006Mask |= 0x10
+	}
+	if len((*z).ReturnData) == 0 {
+		zb0006Len--
+		zb0006Mask |= 0x20
+	}
+	// variable map header, size zb0006Len
+	o = append(o, 0x80|uint8(zb0006Len))
+	if zb0006Len != 0 {
+		if (zb0006Mask & 0x2) == 0 { // if not empty
+			// string "gd"
+			o = append(o, 0xa2, 0x67, 0x64)
+			o = (*z).GlobalDelta.MarshalMsg(o)
+		}
+		if (zb0006Mask & 0x4) == 0 { // if not empty
+			// string "itx"
+			o = append(o, 0xa3, 0x69, 0x74, 0x78)
+			if (*z).InnerTxns == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).InnerTxns)))
+			}
+			for zb0003 := range (*z).InnerTxns {
+				o = (*z).InnerTxns[zb0003].MarshalMsg(o)
+			}
+		}
+		if (zb0006Mask & 0x8) == 0 { // if not empty
+			// string "ld"
+			o = append(o, 0xa2, 0x6c, 0x64)
+			if (*z).LocalDeltas == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendMapHeader(o, uint32(len((*z).LocalDeltas)))
+			}
+			zb0001_keys := make([]uint64, 0, len((*z).LocalDeltas))
+			for zb0001 := range (*z).LocalDeltas {
+				zb0001_keys = append(zb0001_keys, zb0001)
+			}
+			sort.Sort(SortUint64(zb0001_keys))
+			for _, zb0001 := range zb0001_keys {
+				zb0002 := (*z).LocalDeltas[zb0001]
+				_ = zb0002
+				o = msgp.AppendUint64(o, zb0001)
+				o = zb0002.MarshalMsg(o)
+			}
+		}
+		if (zb0006Mask & 0x10) == 0 { // if not empty
+			// string "lg"
+			o = append(o, 0xa2, 0x6c, 0x67)
+			if (*z).Logs == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).Logs)))
+			}
+			for zb0004 := range (*z).Logs {
+				o = msgp.AppendString(o, (*z).Logs[zb0004])
+			}
+		}
+		if (zb0006Mask & 0x20) == 0 { // if not empty
+			// string "rd"
+			o = append(o, 0xa2, 0x72, 0x64)
+			if (*z).ReturnData == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).ReturnData)))
+			}
+			for zb0005 := range (*z).ReturnData {
+				o = msgp.AppendString(o, (*z).ReturnData[zb0005])
+			}
+		}
+	}
+	return
+}
+
+func (_ *EvalDelta) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*EvalDelta)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *EvalDelta) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0006 int
+	var zb0007 bool
+	zb0006, zb0007, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0006 > 0 {
+			zb0006--
+			bts, err = (*z).GlobalDelta.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "GlobalDelta")
+				return
+			}
+		}
+		if zb0006 > 0 {
+			zb0006--
+			var zb0008 int
+			var zb0009 bool
+			zb0008, zb0009, bts, err = msgp.ReadMapHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "LocalDeltas")
+				return
+			}
+			if zb0008 > config.MaxEvalDeltaAccounts {
+				err = msgp.ErrOverflow(uint64(zb0008), uint64(config.MaxEvalDeltaAccounts))
+				err = msgp.WrapError(err, "struct-from-array", "LocalDeltas")
+				return
+			}
+			if zb0009 {
+				(*z).LocalDeltas = nil
+			} else if (*z).LocalDeltas == nil {
+				(*z).LocalDeltas = make(map[uint64]basics.StateDelta, zb0008)
+			}
+			for zb0008 > 0 {
+				var zb0001 uint64
+				var zb0002 basics.StateDelta
+				zb0008--
+				zb0001, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "LocalDeltas")
+					return
+				}
+				bts, err = zb0002.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "LocalDeltas", zb0001)
+					return
+				}
+				(*z).LocalDeltas[zb0001] = zb0002
+			}
+		}
+		if zb0006 > 0 {
+			zb0006--
+			var zb0010 int
+			var zb0011 bool
+			zb0010, zb0011, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0010 > config.MaxInnerTransactions {
+				err = msgp.ErrOverflow(uint64(zb0010), uint64(config.MaxInnerTransactions))
+				err = msgp.WrapError(err, "struct-from-array", "InnerTxns")
+				return
+			}
+			if zb0011 {
+				(*z).InnerTxns = nil
+			} else if (*z).InnerTxns != nil && cap((*z).InnerTxns) >= zb0010 {
+				(*z).InnerTxns = ((*z).InnerTxns)[:zb0010]
+			} else {
+				(*z).InnerTxns = make([]SignedTxnWithAD, zb0010)
+			}
+			for zb0003 := range (*z).InnerTxns {
+				bts, err = (*z).InnerTxns[zb0003].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "InnerTxns", zb0003)
+					return
+				}
+			}
+		}
+		if zb0006 > 0 {
+			zb0006--
+			var zb0012 int
+			var zb0013 bool
+			zb0012, zb0013, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Logs")
+				return
+			}
+			if zb0012 > config.MaxLogCalls {
+				err = msgp.ErrOverflow(uint64(zb0012), uint64(config.MaxLogCalls))
+				err = msgp.WrapError(err, "struct-from-array", "Logs")
+				return
+			}
+			if zb0013 {
+				(*z).Logs = nil
+			} else if (*z).Logs != nil && cap((*z).Logs) >= zb0012 {
+				(*z).Logs = ((*z).Logs)[:zb0012]
+			} else {
+				(*z).Logs = make([]string, zb0012)
+			}
+			for zb0004 := range (*z).Logs {
+				(*z).Logs[zb0004], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "Logs", zb0004)
+					return
+				}
+			}
+		}
+		if zb0006 > 0 {
+			zb0006--
+			var zb0014 int
+			var zb0015 bool
+			zb0014, zb0015, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "ReturnData")
+				return
+			}
+			if zb0014 > config.MaxReturnDataEntries {
+				err = msgp.ErrOverflow(uint64(zb0014), uint64(config.MaxReturnDataEntries))
+				err = msgp.WrapError(err, "struct-from-array", "ReturnData")
+				return
+			}
+			if zb0015 {
+				(*z).ReturnData = nil
+			} else if (*z).ReturnData != nil && cap((*z).ReturnData) >= zb0014 {
+				(*z).ReturnData = ((*z).ReturnData)[:zb0014]
+			} else {
+				(*z).ReturnData = make([]string, zb0014)
+			}
+			for zb0005 := range (*z).ReturnData {
+				(*z).ReturnData[zb0005], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "ReturnData", zb0005)
+					return
+				}
+			}
+		}
+		if zb0006 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0006)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0007 {
+			(*z) = EvalDelta{}
+		}
+		for zb0006 > 0 {
+			zb0006--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "gd":
+				bts, err = (*z).GlobalDelta.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "GlobalDelta")
+					return
+				}
+			case "ld":
+				var zb0016 int
+				var zb0017 bool
+				zb0016, zb0017, bts, err = msgp.ReadMapHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "LocalDeltas")
+					return
+				}
+				if zb0016 > config.MaxEvalDeltaAccounts {
+					err = msgp.ErrOverflow(uint64(zb0016), uint64(config.MaxEvalDeltaAccounts))
+					err = msgp.WrapError(err, "LocalDeltas")
+					return
+				}
+				if zb0017 {
+					(*z).LocalDeltas = nil
+				} else if (*z).LocalDeltas == nil {
+					(*z).LocalDeltas = make(map[uint64]basics.StateDelta, zb0016)
+				}
+				for zb0016 > 0 {
+					var zb0001 uint64
+					var zb0002 basics.StateDelta
+					zb0016--
+					zb0001, bts, err = msgp.ReadUint64Bytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "LocalDeltas")
+						return
+					}
+					bts, err = zb0002.UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "LocalDeltas", zb0001)
+						return
+					}
+					(*z).LocalDeltas[zb0001] = zb0002
+				}
+			case "itx":
+				var zb0018 int
+				var zb0019 bool
+				zb0018, zb0019, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0018 > config.MaxInnerTransactions {
+					err = msgp.ErrOverflow(uint64(zb0018), uint64(config.MaxInnerTransactions))
+					err = msgp.WrapError(err, "InnerTxns")
+					return
+				}
+				if zb0019 {
+					(*z).InnerTxns = nil
+				} else if (*z).InnerTxns != nil && cap((*z).InnerTxns) >= zb0018 {
+					(*z).InnerTxns = ((*z).InnerTxns)[:zb0018]
+				} else {
+					(*z).InnerTxns = make([]SignedTxnWithAD, zb0018)
+				}
+				for zb0003 := range (*z).InnerTxns {
+					bts, err = (*z).InnerTxns[zb0003].UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "InnerTxns", zb0003)
+						return
+					}
+				}
+			case "lg":
+				var zb0020 int
+				var zb0021 bool
+				zb0020, zb0021, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Logs")
+					return
+				}
+				if zb0020 > config.MaxLogCalls {
+					err = msgp.ErrOverflow(uint64(zb0020), uint64(config.MaxLogCalls))
+					err = msgp.WrapError(err, "Logs")
+					return
+				}
+				if zb0021 {
+					(*z).Logs = nil
+				} else if (*z).Logs != nil && cap((*z).Logs) >= zb0020 {
+					(*z).Logs = ((*z).Logs)[:zb0020]
+				} else {
+					(*z).Logs = make([]string, zb0020)
+				}
+				for zb0004 := range (*z).Logs {
+					(*z).Logs[zb0004], bts, err = msgp.ReadStringBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "Logs", zb0004)
+						return
+					}
+				}
+			case "rd":
+				var zb0022 int
+				var zb0023 bool
+				zb0022, zb0023, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "ReturnData")
+					return
+				}
+				if zb0022 > config.MaxReturnDataEntries {
+					err = msgp.ErrOverflow(uint64(zb0022), uint64(config.MaxReturnDataEntries))
+					err = msgp.WrapError(err, "ReturnData")
+					return
+				}
+				if zb0023 {
+					(*z).ReturnData = nil
+				} else if (*z).ReturnData != nil && cap((*z).ReturnData) >= zb0022 {
+					(*z).ReturnData = ((*z).ReturnData)[:zb0022]
+				} else {
+					(*z).ReturnData = make([]string, zb0022)
+				}
+				for zb0005 := range (*z).ReturnData {
+					(*z).ReturnData[zb0005], bts, err = msgp.ReadStringBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "ReturnData", zb0005)
+						return
+					}
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *EvalDelta) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*EvalDelta)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *EvalDelta) Msgsize() (s int) {
+	s = 1 + 3 + (*z).GlobalDelta.Msgsize() + 3 + msgp.MapHeaderSize
+	if (*z).LocalDeltas != nil {
+		for zb0001, zb0002 := range (*z).LocalDeltas {
+			_ = zb0001
+			_ = zb0002
+			s += 0 + msgp.Uint64Size + zb0002.Msgsize()
+		}
+	}
+	s += 4 + msgp.ArrayHeaderSize
+	for zb0003 := range (*z).InnerTxns {
+		s += (*z).InnerTxns[zb0003].Msgsize()
+	}
+	s += 3 + msgp.ArrayHeaderSize
+	for zb0004 := range (*z).Logs {
+		s += msgp.StringPrefixSize + len((*z).Logs[zb0004])
+	}
+	s += 3 + msgp.ArrayHeaderSize
+	for zb0005 := range (*z).ReturnData {
+		s += msgp.StringPrefixSize + len((*z).ReturnData[zb0005])
+	}
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *EvalDelta) MsgIsZero() bool {
+	return ((*z).GlobalDelta.MsgIsZero()) && (len((*z).LocalDeltas) == 0) && (len((*z).InnerTxns) == 0) && (len((*z).Logs) == 0) && (len((*z).ReturnData) == 0)
+}
+
 // MarshalMsg implements msgp.Marshaler
 func (z *Header) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
@@ -365,6 +365,65 @@ func BenchmarkUnmarshalCompactCertTxnFields(b *testing.B) {
 	}
 }
 
+func TestMarshalUnmarshalEvalDelta(t *testing.T) {
+	v := EvalDelta{}
+	bts := v.MarshalMsg(nil)
+	left, err := v.UnmarshalMsg(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after UnmarshalMsg(): %q", len(left), left)
+	}
+
+	left, err = msgp.Skip(bts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) > 0 {
+		t.Errorf("%d bytes left over after Skip(): %q", len(left), left)
+	}
+}
+
+func TestRandomizedEncodingEvalDelta(t *testing.T) {
+	protocol.RunEncodingTest(t, &EvalDelta{})
+}
+
+func BenchmarkMarshalMsgEvalDelta(b *testing.B) {
+	v := EvalDelta{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.MarshalMsg(nil)
+	}
+}
+
+func BenchmarkAppendMsgEvalDelta(b *testing.B) {
+	v := EvalDelta{}
+	bts := make([]byte, 0, v.Msgsize())
+	bts = v.MarshalMsg(bts[0:0])
+	b.SetBytes(int64(len(bts)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bts = v.MarshalMsg(bts[0:0])
+	}
+}
+
+func BenchmarkUnmarshalEvalDelta(b *testing.B) {
+	v := EvalDelta{}
+	bts := v.MarshalMsg(nil)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bts)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := v.UnmarshalMsg(bts)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestMarshalUnmarshalHeader(t *testing.T) {
 	v := Header{}
 	bts := v.MarshalMsg(nil)
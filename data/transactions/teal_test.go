@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package transactions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func TestEvalDeltaEqual(t *testing.T) {
+	a := require.New(t)
+
+	d1 := EvalDelta{}
+	d2 := EvalDelta{}
+	a.True(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		GlobalDelta: nil,
+		LocalDeltas: nil,
+	}
+	a.True(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		GlobalDelta: basics.StateDelta{},
+		LocalDeltas: map[uint64]basics.StateDelta{},
+	}
+	a.True(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		GlobalDelta: basics.StateDelta{"test": {Action: basics.SetUintAction, Uint: 0}},
+	}
+	a.False(d1.Equal(d2))
+
+	d1 = EvalDelta{
+		GlobalDelta: basics.StateDelta{"test": {Action: basics.SetUintAction, Uint: 0}},
+	}
+	a.True(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			0: {"test": {Action: basics.SetUintAction, Uint: 0}},
+		},
+	}
+	a.False(d1.Equal(d2))
+
+	d1 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			0: {"test": {Action: basics.SetUintAction, Uint: 1}},
+		},
+	}
+	a.False(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			0: {"test": {Action: basics.SetUintAction, Uint: 1}},
+		},
+	}
+	a.True(d1.Equal(d2))
+
+	d1 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			0: {"test": {Action: basics.SetBytesAction, Bytes: "val"}},
+		},
+	}
+	d2 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			0: {"test": {Action: basics.SetBytesAction, Bytes: "val"}},
+		},
+	}
+	a.True(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			0: {"test": {Action: basics.SetBytesAction, Bytes: "val1"}},
+		},
+	}
+	a.False(d1.Equal(d2))
+
+	d2 = EvalDelta{
+		LocalDeltas: map[uint64]basics.StateDelta{
+			1: {"test": {Action: basics.SetBytesAction, Bytes: "val"}},
+		},
+	}
+	a.False(d1.Equal(d2))
+
+	d1 = EvalDelta{Logs: []string{"a", "b"}}
+	d2 = EvalDelta{Logs: []string{"a", "b"}}
+	a.True(d1.Equal(d2))
+
+	d2 = EvalDelta{Logs: []string{"b", "a"}}
+	a.False(d1.Equal(d2))
+
+	d2 = EvalDelta{Logs: []string{"a"}}
+	a.False(d1.Equal(d2))
+
+	d1 = EvalDelta{Logs: []string{}}
+	d2 = EvalDelta{}
+	a.True(d1.Equal(d2))
+}
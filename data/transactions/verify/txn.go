@@ -66,6 +66,10 @@ type GroupContext struct {
 	consensusParams  config.ConsensusParams
 	minTealVersion   uint64
 	signedGroupTxns  []transactions.SignedTxn
+
+	// pastSideEffects carries the return data of the logic signatures
+	// already evaluated in the group to the ones that follow them
+	pastSideEffects []logic.EvalSideEffects
 }
 
 // PrepareGroupContext prepares a verification group parameter object for a given transaction
@@ -87,6 +91,7 @@ func PrepareGroupContext(group []transactions.SignedTxn, contextHdr bookkeeping.
 		consensusParams:  consensusParams,
 		minTealVersion:   logic.ComputeMinTealVersion(group),
 		signedGroupTxns:  group,
+		pastSideEffects:  logic.MakePastSideEffects(len(group)),
 	}, nil
 }
 
@@ -118,37 +123,47 @@ func TxnGroup(stxs []transactions.SignedTxn, contextHdr bookkeeping.BlockHeader,
 		return nil, err
 	}
 
-	minFeeCount := uint64(0)
-	feesPaid := uint64(0)
 	for i, stxn := range stxs {
 		err = Txn(&stxn, i, groupCtx)
 		if err != nil {
 			err = fmt.Errorf("transaction %+v invalid : %w", stxn, err)
 			return
 		}
+	}
+	err = TxnGroupFees(stxs, groupCtx.consensusParams)
+	if err != nil {
+		return
+	}
+
+	if cache != nil {
+		cache.Add(stxs, groupCtx)
+	}
+	return
+}
+
+// TxnGroupFees checks that the fees paid by a transaction group cover the
+// minimum fee of each of its transactions.
+func TxnGroupFees(stxs []transactions.SignedTxn, proto config.ConsensusParams) error {
+	minFeeCount := uint64(0)
+	feesPaid := uint64(0)
+	for _, stxn := range stxs {
 		if stxn.Txn.Type != protocol.CompactCertTx {
 			minFeeCount++
 		}
 		feesPaid = basics.AddSaturate(feesPaid, stxn.Txn.Fee.Raw)
 	}
-	feeNeeded, overflow := basics.OMul(groupCtx.consensusParams.MinTxnFee, minFeeCount)
+	feeNeeded, overflow := basics.OMul(proto.MinTxnFee, minFeeCount)
 	if overflow {
-		err = fmt.Errorf("txgroup fee requirement overflow")
-		return
+		return fmt.Errorf("txgroup fee requirement overflow")
 	}
 	// feesPaid may have saturated. That's ok. Since we know
 	// feeNeeded did not overlfow, simple comparison tells us
 	// feesPaid was enough.
 	if feesPaid < feeNeeded {
-		err = fmt.Errorf("txgroup had %d in fees, which is less than the minimum %d * %d",
-			feesPaid, minFeeCount, groupCtx.consensusParams.MinTxnFee)
-		return
-	}
-
-	if cache != nil {
-		cache.Add(stxs, groupCtx)
+		return fmt.Errorf("txgroup had %d in fees, which is less than the minimum %d * %d",
+			feesPaid, minFeeCount, proto.MinTxnFee)
 	}
-	return
+	return nil
 }
 
 func stxnVerifyCore(s *transactions.SignedTxn, txnIdx int, groupCtx *GroupContext) error {
@@ -280,11 +295,12 @@ func logicSig(txn *transactions.SignedTxn, groupIndex int, groupCtx *GroupContex
 	}
 
 	ep := logic.EvalParams{
-		Txn:            txn,
-		Proto:          &groupCtx.consensusParams,
-		TxnGroup:       groupCtx.signedGroupTxns,
-		GroupIndex:     groupIndex,
-		MinTealVersion: &groupCtx.minTealVersion,
+		Txn:             txn,
+		Proto:           &groupCtx.consensusParams,
+		TxnGroup:        groupCtx.signedGroupTxns,
+		GroupIndex:      groupIndex,
+		MinTealVersion:  &groupCtx.minTealVersion,
+		PastSideEffects: groupCtx.pastSideEffects,
 	}
 	pass, err := logic.Eval(txn.Lsig.Logic, ep)
 	if err != nil {
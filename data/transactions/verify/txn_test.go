@@ -29,6 +29,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
 	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/execpool"
 )
@@ -251,6 +252,66 @@ func TestDecodeNil(t *testing.T) {
 	}
 }
 
+func TestTxnGroupLogicSigReturnData(t *testing.T) {
+	blkHdr := bookkeeping.BlockHeader{
+		Round:       50,
+		GenesisHash: crypto.Hash([]byte{1, 2, 3, 4, 5}),
+		UpgradeState: bookkeeping.UpgradeState{
+			CurrentProtocol: protocol.ConsensusFuture,
+		},
+		RewardsState: bookkeeping.RewardsState{
+			FeeSink:     feeSink,
+			RewardsPool: poolAddr,
+		},
+	}
+	proto := config.Consensus[protocol.ConsensusFuture]
+
+	makeGroup := func(expected string) []transactions.SignedTxn {
+		sources := []string{
+			`#pragma version 5
+byte "from lsig 0"
+retdata_put
+int 1`,
+			`#pragma version 5
+gretdata 0 0
+assert
+byte "` + expected + `"
+==`,
+		}
+		group := make([]transactions.SignedTxn, len(sources))
+		var txGroup transactions.TxGroup
+		for i, source := range sources {
+			ops, err := logic.AssembleString(source)
+			require.NoError(t, err)
+			program := logic.Program(ops.Program)
+			group[i].Lsig.Logic = ops.Program
+			group[i].Txn = transactions.Transaction{
+				Type: protocol.PaymentTx,
+				Header: transactions.Header{
+					Sender:      basics.Address(crypto.HashObj(&program)),
+					Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+					FirstValid:  45,
+					LastValid:   55,
+					GenesisHash: blkHdr.GenesisHash,
+				},
+			}
+			txGroup.TxGroupHashes = append(txGroup.TxGroupHashes, crypto.HashObj(group[i].Txn))
+		}
+		for i := range group {
+			group[i].Txn.Group = crypto.HashObj(txGroup)
+		}
+		return group
+	}
+
+	// the second logic signature reads what the first left for it
+	_, err := TxnGroup(makeGroup("from lsig 0"), blkHdr, nil)
+	require.NoError(t, err)
+
+	_, err = TxnGroup(makeGroup("something else"), blkHdr, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "rejected by logic")
+}
+
 func TestPaysetGroups(t *testing.T) {
 	_, signedTxn, secrets, addrs := generateTestObjects(10000, 20, 50)
 	blkHdr := bookkeeping.BlockHeader{
@@ -26,6 +26,7 @@ import (
 	"github.com/mattn/go-sqlite3"
 
 	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/protocol"
@@ -37,7 +38,10 @@ import (
 type accountsDbQueries struct {
 	listCreatablesStmt          *sql.Stmt
 	lookupStmt                  *sql.Stmt
+	lookupResourcesStmt         *sql.Stmt
 	lookupCreatorStmt           *sql.Stmt
+	lookupBoxStmt               *sql.Stmt
+	listBoxesStmt               *sql.Stmt
 	deleteStoredCatchpoint      *sql.Stmt
 	insertStoredCatchpoint      *sql.Stmt
 	selectOldestCatchpointFiles *sql.Stmt
@@ -80,8 +84,25 @@ var accountsSchema = []string{
 		id string primary key,
 		intval integer,
 		strval text)`,
+	boxesSchema,
+	resourcesSchema,
 }
 
+// boxesSchema creates the key/value table holding the application boxes.
+var boxesSchema = `CREATE TABLE IF NOT EXISTS kvstore (
+		key blob primary key,
+		value blob)`
+
+// resourcesSchema creates the table holding the asset holdings and the application local states of the accounts,
+// one row per creatable. These are stored apart from the rest of the account data in accountbase so that
+// modifying a single holding doesn't require rewriting the whole account.
+var resourcesSchema = `CREATE TABLE IF NOT EXISTS resources (
+		address blob,
+		aidx integer,
+		rtype integer,
+		data blob,
+		PRIMARY KEY (address, aidx, rtype))`
+
 // TODO: Post applications, rename assetcreators -> creatables and rename
 // 'asset' column -> 'creatable'
 var creatablesMigration = []string{
@@ -109,12 +130,14 @@ var accountsResetExprs = []string{
 	`DROP TABLE IF EXISTS storedcatchpoints`,
 	`DROP TABLE IF EXISTS catchpointstate`,
 	`DROP TABLE IF EXISTS accounthashes`,
+	`DROP TABLE IF EXISTS kvstore`,
+	`DROP TABLE IF EXISTS resources`,
 }
 
 // accountDBVersion is the database version that this binary would know how to support and how to upgrade to.
 // details about the content of each of the versions can be found in the upgrade functions upgradeDatabaseSchemaXXXX
 // and their descriptions.
-var accountDBVersion = int32(5)
+var accountDBVersion = int32(7)
 
 // persistedAccountData is used for representing a single account stored on the disk. In addition to the
 // basics.AccountData, it also stores complete referencing information used to maintain the base accounts
@@ -155,6 +178,9 @@ type accountDelta struct {
 	old     persistedAccountData
 	new     basics.AccountData
 	ndeltas int
+	// resources are the changes to the asset holdings and application local states of the account, as computed by
+	// makeResourceDeltas once the old data is known.
+	resources []resourceDelta
 }
 
 // catchpointState is used to store catchpoint related variables into the catchpointstate table.
@@ -176,6 +202,9 @@ const (
 	// catchpointStateCatchupBalancesRound is the balance round that is associated with the current running catchpoint catchup. Typically it would be
 	// equal to catchpointStateCatchupBlockRound - 320.
 	catchpointStateCatchupBalancesRound = catchpointState("catchpointCatchupBalancesRound")
+	// catchpointStateCatchupDownloadChunks is the encoded description of the download chunks of the catchpoint file the current running catchpoint
+	// catchup is downloading. It allows the download to be resumed after a restart.
+	catchpointStateCatchupDownloadChunks = catchpointState("catchpointCatchupDownloadChunks")
 )
 
 // normalizedAccountBalance is a staging area for a catchpoint file account information before it's being added to the catchpoint staging tables.
@@ -256,6 +285,11 @@ func (a *compactAccountDeltas) accountsLoadOld(tx *sql.Tx) (err error) {
 		return
 	}
 	defer selectStmt.Close()
+	selectResourcesStmt, err := tx.Prepare("SELECT aidx, rtype, data FROM resources WHERE address=?")
+	if err != nil {
+		return
+	}
+	defer selectResourcesStmt.Close()
 	defer func() {
 		a.misses = nil
 	}()
@@ -272,6 +306,10 @@ func (a *compactAccountDeltas) accountsLoadOld(tx *sql.Tx) (err error) {
 				if err != nil {
 					return err
 				}
+				err = loadAccountResources(selectResourcesStmt, addr, &persistedAcctData.accountData)
+				if err != nil {
+					return err
+				}
 				a.updateOld(idx, *persistedAcctData)
 			} else {
 				// to retain backward compatibility, we will treat this condition as if we don't have the account.
@@ -353,6 +391,167 @@ func (a *compactAccountDeltas) updateOld(idx int, old persistedAccountData) {
 	a.deltas[idx].old = old
 }
 
+// makeResourceDeltas sets the resource changes of every account, so that they are computed once per commit and shared
+// by the writes to the resources table and the updates of the merkle trie. The old data of the accounts has to be loaded
+// by then.
+func (a *compactAccountDeltas) makeResourceDeltas() {
+	for i := range a.deltas {
+		a.deltas[i].resources = makeResourceDeltas(a.deltas[i].old.accountData, a.deltas[i].new)
+	}
+}
+
+// resourceKey identifies one of the asset holdings or application local states of an account in the resources table.
+type resourceKey struct {
+	aidx  basics.CreatableIndex
+	rtype basics.CreatableType
+}
+
+// resourceDelta is the change made to a single asset holding or application local state of an account. The old and new
+// fields hold the encoded resource before and after the change, and are nil when the resource didn't exist.
+type resourceDelta struct {
+	resourceKey
+	old []byte
+	new []byte
+}
+
+// accountBaseData returns the part of the account data that is stored in the accountbase table, that is, the account
+// data without its asset holdings and application local states.
+func accountBaseData(ad basics.AccountData) basics.AccountData {
+	ad.Assets = nil
+	ad.AppLocalStates = nil
+	return ad
+}
+
+// encodeAccountResources returns the encoded asset holdings and application local states of the given account data,
+// as they are stored in the resources table.
+func encodeAccountResources(ad basics.AccountData) map[resourceKey][]byte {
+	if len(ad.Assets) == 0 && len(ad.AppLocalStates) == 0 {
+		return nil
+	}
+	resources := make(map[resourceKey][]byte, len(ad.Assets)+len(ad.AppLocalStates))
+	for aidx, holding := range ad.Assets {
+		holding := holding
+		resources[resourceKey{aidx: basics.CreatableIndex(aidx), rtype: basics.AssetCreatable}] = protocol.Encode(&holding)
+	}
+	for aidx, localState := range ad.AppLocalStates {
+		localState := localState
+		resources[resourceKey{aidx: basics.CreatableIndex(aidx), rtype: basics.AppCreatable}] = protocol.Encode(&localState)
+	}
+	return resources
+}
+
+// mergeAccountResource decodes a single row of the resources table into the matching asset holding or application local
+// state of the given account data.
+func mergeAccountResource(ad *basics.AccountData, aidx basics.CreatableIndex, rtype basics.CreatableType, data []byte) error {
+	switch rtype {
+	case basics.AssetCreatable:
+		var holding basics.AssetHolding
+		err := protocol.Decode(data, &holding)
+		if err != nil {
+			return err
+		}
+		if ad.Assets == nil {
+			ad.Assets = make(map[basics.AssetIndex]basics.AssetHolding)
+		}
+		ad.Assets[basics.AssetIndex(aidx)] = holding
+	case basics.AppCreatable:
+		var localState basics.AppLocalState
+		err := protocol.Decode(data, &localState)
+		if err != nil {
+			return err
+		}
+		if ad.AppLocalStates == nil {
+			ad.AppLocalStates = make(map[basics.AppIndex]basics.AppLocalState)
+		}
+		ad.AppLocalStates[basics.AppIndex(aidx)] = localState
+	default:
+		return fmt.Errorf("unknown resource type %d for creatable %d", rtype, aidx)
+	}
+	return nil
+}
+
+// makeResourceDeltas compares the asset holdings and application local states of the old and new account data, and
+// returns the ones that were added, modified or deleted. Only these are encoded, so that an account with many of them
+// doesn't have all of them encoded whenever it changes.
+func makeResourceDeltas(old basics.AccountData, new basics.AccountData) (deltas []resourceDelta) {
+	for aidx, holding := range new.Assets {
+		oldHolding, has := old.Assets[aidx]
+		if has && oldHolding == holding {
+			continue
+		}
+		holding := holding
+		delta := resourceDelta{resourceKey: resourceKey{aidx: basics.CreatableIndex(aidx), rtype: basics.AssetCreatable}, new: protocol.Encode(&holding)}
+		if has {
+			delta.old = protocol.Encode(&oldHolding)
+		}
+		deltas = append(deltas, delta)
+	}
+	for aidx, holding := range old.Assets {
+		if _, has := new.Assets[aidx]; !has {
+			holding := holding
+			deltas = append(deltas, resourceDelta{resourceKey: resourceKey{aidx: basics.CreatableIndex(aidx), rtype: basics.AssetCreatable}, old: protocol.Encode(&holding)})
+		}
+	}
+	for aidx, localState := range new.AppLocalStates {
+		oldLocalState, has := old.AppLocalStates[aidx]
+		if has && appLocalStateEqual(oldLocalState, localState) {
+			continue
+		}
+		localState := localState
+		delta := resourceDelta{resourceKey: resourceKey{aidx: basics.CreatableIndex(aidx), rtype: basics.AppCreatable}, new: protocol.Encode(&localState)}
+		if has {
+			delta.old = protocol.Encode(&oldLocalState)
+		}
+		deltas = append(deltas, delta)
+	}
+	for aidx, localState := range old.AppLocalStates {
+		if _, has := new.AppLocalStates[aidx]; !has {
+			localState := localState
+			deltas = append(deltas, resourceDelta{resourceKey: resourceKey{aidx: basics.CreatableIndex(aidx), rtype: basics.AppCreatable}, old: protocol.Encode(&localState)})
+		}
+	}
+	return
+}
+
+// appLocalStateEqual tells whether the two application local states have the same encoding
+func appLocalStateEqual(a basics.AppLocalState, b basics.AppLocalState) bool {
+	if a.Schema != b.Schema || len(a.KeyValue) != len(b.KeyValue) {
+		return false
+	}
+	for key, value := range a.KeyValue {
+		if other, has := b.KeyValue[key]; !has || other != value {
+			return false
+		}
+	}
+	return true
+}
+
+// loadAccountResources reads the asset holdings and application local states of the given address using selectStmt,
+// which is expected to select the aidx, rtype and data of the matching rows of a resources table, and merges them
+// into the given account data.
+func loadAccountResources(selectStmt *sql.Stmt, addr basics.Address, ad *basics.AccountData) error {
+	rows, err := selectStmt.Query(addr[:])
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var aidx basics.CreatableIndex
+	var rtype basics.CreatableType
+	var buf []byte
+	for rows.Next() {
+		err = rows.Scan(&aidx, &rtype, &buf)
+		if err != nil {
+			return err
+		}
+		err = mergeAccountResource(ad, aidx, rtype, buf)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 // writeCatchpointStagingBalances inserts all the account balances in the provided array into the catchpoint balance staging table catchpointbalances.
 func writeCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, bals []normalizedAccountBalance) error {
 	insertAcctStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointbalances(address, normalizedonlinebalance, data) VALUES(?, ?, ?)")
@@ -400,6 +599,93 @@ func writeCatchpointStagingHashes(ctx context.Context, tx *sql.Tx, bals []normal
 	return nil
 }
 
+// writeCatchpointStagingBoxes writes the given boxes into the catchpointkvstore staging table, and their hashes
+// into the catchpointpendinghashes table.
+func writeCatchpointStagingBoxes(ctx context.Context, tx *sql.Tx, boxes []encodedBoxRecord) error {
+	insertBoxStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointkvstore(key, value) VALUES(?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertBoxStmt.Close()
+
+	insertHashStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointpendinghashes(data) VALUES(?)")
+	if err != nil {
+		return err
+	}
+	defer insertHashStmt.Close()
+
+	for _, box := range boxes {
+		_, err = insertBoxStmt.ExecContext(ctx, box.Key, box.Value)
+		if err != nil {
+			return err
+		}
+		_, err = insertHashStmt.ExecContext(ctx, boxHashBuilder(string(box.Key), string(box.Value)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCatchpointStagingResources writes the given account resources into the catchpointresources staging table and,
+// when resourceHashes is set, their hashes into the catchpointpendinghashes table.
+func writeCatchpointStagingResources(ctx context.Context, tx *sql.Tx, resources []encodedResourceRecord, resourceHashes bool) error {
+	insertResourceStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointresources(address, aidx, rtype, data) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertResourceStmt.Close()
+
+	insertHashStmt, err := tx.PrepareContext(ctx, "INSERT INTO catchpointpendinghashes(data) VALUES(?)")
+	if err != nil {
+		return err
+	}
+	defer insertHashStmt.Close()
+
+	for _, resource := range resources {
+		_, err = insertResourceStmt.ExecContext(ctx, resource.Address[:], resource.Aidx, resource.Rtype, []byte(resource.Data))
+		if err != nil {
+			return err
+		}
+		if !resourceHashes {
+			continue
+		}
+		_, err = insertHashStmt.ExecContext(ctx, resourceHashBuilder(resource.Address, resource.Aidx, resource.Rtype, resource.Data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashCatchpointStagingAccountsResources recalculates the hashes of the given accounts so that they cover their asset
+// holdings and application local states, as found in the catchpointresources staging table.
+func hashCatchpointStagingAccountsResources(ctx context.Context, tx *sql.Tx, bals []normalizedAccountBalance) error {
+	selectResourcesStmt, err := tx.PrepareContext(ctx, "SELECT aidx, rtype, data FROM catchpointresources WHERE address=?")
+	if err != nil {
+		return err
+	}
+	defer selectResourcesStmt.Close()
+
+	for i := range bals {
+		// decode the account data again, rather than adding the resources to the maps shared with the other writers.
+		var accountData basics.AccountData
+		err = protocol.Decode(bals[i].encodedAccountData, &accountData)
+		if err != nil {
+			return err
+		}
+		err = loadAccountResources(selectResourcesStmt, bals[i].address, &accountData)
+		if err != nil {
+			return err
+		}
+		if len(accountData.Assets) == 0 && len(accountData.AppLocalStates) == 0 {
+			continue
+		}
+		bals[i].accountHash = accountHashBuilder(bals[i].address, accountData, protocol.Encode(&accountData))
+	}
+	return nil
+}
+
 // createCatchpointStagingHashesIndex creates an index on catchpointpendinghashes to allow faster scanning according to the hash order
 func createCatchpointStagingHashesIndex(ctx context.Context, tx *sql.Tx) (err error) {
 	_, err = tx.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS catchpointpendinghashesidx ON catchpointpendinghashes(data)")
@@ -443,6 +729,8 @@ func resetCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, newCatchup
 	s := []string{
 		"DROP TABLE IF EXISTS catchpointbalances",
 		"DROP TABLE IF EXISTS catchpointassetcreators",
+		"DROP TABLE IF EXISTS catchpointkvstore",
+		"DROP TABLE IF EXISTS catchpointresources",
 		"DROP TABLE IF EXISTS catchpointaccounthashes",
 		"DROP TABLE IF EXISTS catchpointpendinghashes",
 		"DELETE FROM accounttotals where id='catchpointStaging'",
@@ -459,6 +747,8 @@ func resetCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, newCatchup
 
 		s = append(s,
 			"CREATE TABLE IF NOT EXISTS catchpointassetcreators (asset integer primary key, creator blob, ctype integer)",
+			"CREATE TABLE IF NOT EXISTS catchpointkvstore (key blob primary key, value blob)",
+			"CREATE TABLE IF NOT EXISTS catchpointresources (address blob, aidx integer, rtype integer, data blob, PRIMARY KEY (address, aidx, rtype))",
 			"CREATE TABLE IF NOT EXISTS catchpointbalances (address blob primary key, data blob, normalizedonlinebalance integer)",
 			"CREATE TABLE IF NOT EXISTS catchpointpendinghashes (data blob)",
 			"CREATE TABLE IF NOT EXISTS catchpointaccounthashes (id integer primary key, data blob)",
@@ -482,15 +772,24 @@ func applyCatchpointStagingBalances(ctx context.Context, tx *sql.Tx, balancesRou
 	stmts := []string{
 		"ALTER TABLE accountbase RENAME TO accountbase_old",
 		"ALTER TABLE assetcreators RENAME TO assetcreators_old",
+		"ALTER TABLE kvstore RENAME TO kvstore_old",
+		"ALTER TABLE resources RENAME TO resources_old",
 		"ALTER TABLE accounthashes RENAME TO accounthashes_old",
 
 		"ALTER TABLE catchpointbalances RENAME TO accountbase",
 		"ALTER TABLE catchpointassetcreators RENAME TO assetcreators",
+		"ALTER TABLE catchpointkvstore RENAME TO kvstore",
+		"ALTER TABLE catchpointresources RENAME TO resources",
 		"ALTER TABLE catchpointaccounthashes RENAME TO accounthashes",
 
 		"DROP TABLE IF EXISTS accountbase_old",
 		"DROP TABLE IF EXISTS assetcreators_old",
+		"DROP TABLE IF EXISTS kvstore_old",
+		"DROP TABLE IF EXISTS resources_old",
 		"DROP TABLE IF EXISTS accounthashes_old",
+
+		// the external trackers are rebuilt from the new balances once the ledger is reloaded.
+		"DROP TABLE IF EXISTS externaltrackers",
 	}
 
 	for _, stmt := range stmts {
@@ -550,11 +849,19 @@ func accountsInit(tx *sql.Tx, initAccounts map[basics.Address]basics.AccountData
 		var totals ledgercore.AccountTotals
 
 		for addr, data := range initAccounts {
+			baseData := accountBaseData(data)
 			_, err = tx.Exec("INSERT INTO accountbase (address, data) VALUES (?, ?)",
-				addr[:], protocol.Encode(&data))
+				addr[:], protocol.Encode(&baseData))
 			if err != nil {
 				return true, err
 			}
+			for key, resourceData := range encodeAccountResources(data) {
+				_, err = tx.Exec("INSERT INTO resources (address, aidx, rtype, data) VALUES (?, ?, ?, ?)",
+					addr[:], key.aidx, key.rtype, resourceData)
+				if err != nil {
+					return true, err
+				}
+			}
 
 			totals.AddAccount(proto, data, &ot)
 		}
@@ -741,11 +1048,26 @@ func accountsDbInit(r db.Queryable, w db.Queryable) (*accountsDbQueries, error)
 		return nil, err
 	}
 
+	qs.lookupResourcesStmt, err = r.Prepare("SELECT rnd, aidx, rtype, data FROM acctrounds LEFT JOIN resources ON address=? WHERE id='acctbase'")
+	if err != nil {
+		return nil, err
+	}
+
 	qs.lookupCreatorStmt, err = r.Prepare("SELECT rnd, creator FROM acctrounds LEFT JOIN assetcreators ON asset = ? AND ctype = ? WHERE id='acctbase'")
 	if err != nil {
 		return nil, err
 	}
 
+	qs.lookupBoxStmt, err = r.Prepare("SELECT rnd, key, value FROM acctrounds LEFT JOIN kvstore ON key = ? WHERE id='acctbase'")
+	if err != nil {
+		return nil, err
+	}
+
+	qs.listBoxesStmt, err = r.Prepare("SELECT rnd, key FROM acctrounds LEFT JOIN kvstore ON key >= ? AND key < ? WHERE id='acctbase' ORDER BY key LIMIT ?")
+	if err != nil {
+		return nil, err
+	}
+
 	qs.deleteStoredCatchpoint, err = w.Prepare("DELETE FROM storedcatchpoints WHERE round=?")
 	if err != nil {
 		return nil, err
@@ -845,36 +1167,130 @@ func (qs *accountsDbQueries) lookupCreator(cidx basics.CreatableIndex, ctype bas
 	return
 }
 
+// lookupBox looks up the value of the box stored under the given key, along with the current database round.
+func (qs *accountsDbQueries) lookupBox(key string) (value string, ok bool, dbRound basics.Round, err error) {
+	err = db.Retry(func() error {
+		var keyBuf, valueBuf []byte
+		err := qs.lookupBoxStmt.QueryRow([]byte(key)).Scan(&dbRound, &keyBuf, &valueBuf)
+
+		// this shouldn't happen unless we can't figure the round number.
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("lookupBox was unable to retrieve round number")
+		}
+
+		// Some other database error
+		if err != nil {
+			return err
+		}
+
+		// box names are never empty, so a non-empty key means that the box exists
+		if len(keyBuf) > 0 {
+			ok = true
+			value = string(valueBuf)
+		}
+		return nil
+	})
+	return
+}
+
+// listBoxes lists, in ascending order, up to maxResults keys of boxes belonging to the given application.
+func (qs *accountsDbQueries) listBoxes(appIdx basics.AppIndex, maxResults uint64) (keys []string, dbRound basics.Round, err error) {
+	err = db.Retry(func() error {
+		keys = nil
+		rows, err := qs.listBoxesStmt.Query([]byte(ledgercore.BoxKeyAppPrefix(appIdx)), []byte(ledgercore.BoxKeyAppPrefix(appIdx+1)), maxResults)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var keyBuf []byte
+		for rows.Next() {
+			err = rows.Scan(&dbRound, &keyBuf)
+			if err != nil {
+				return err
+			}
+			if len(keyBuf) == 0 {
+				// we received an entry without any key. This would happen only on the first entry when the application has no boxes.
+				break
+			}
+			keys = append(keys, string(keyBuf))
+		}
+		return nil
+	})
+	return
+}
+
 // lookup looks up for a the account data given it's address. It returns the persistedAccountData, which includes the current database round and the matching
 // account data, if such was found. If no matching account data could be found for the given address, an empty account data would
 // be retrieved.
 func (qs *accountsDbQueries) lookup(addr basics.Address) (data persistedAccountData, err error) {
 	err = db.Retry(func() error {
-		var buf []byte
-		var rowid sql.NullInt64
-		err := qs.lookupStmt.QueryRow(addr[:]).Scan(&rowid, &data.round, &buf)
-		if err == nil {
+		for {
+			data = persistedAccountData{}
+			var buf []byte
+			var rowid sql.NullInt64
+			err := qs.lookupStmt.QueryRow(addr[:]).Scan(&rowid, &data.round, &buf)
+			if err == sql.ErrNoRows {
+				// this should never happen; it indicates that we don't have a current round in the acctrounds table.
+				return fmt.Errorf("unable to query account data for address %v : %w", addr, err)
+			}
+			if err != nil {
+				return err
+			}
 			data.addr = addr
-			if len(buf) > 0 && rowid.Valid {
-				data.rowid = rowid.Int64
-				return protocol.Decode(buf, &data.accountData)
+			if len(buf) == 0 || !rowid.Valid {
+				// we don't have that account, just return the database round.
+				return nil
+			}
+			data.rowid = rowid.Int64
+			err = protocol.Decode(buf, &data.accountData)
+			if err != nil {
+				return err
 			}
-			// we don't have that account, just return the database round.
-			return nil
-		}
 
-		// this should never happen; it indicates that we don't have a current round in the acctrounds table.
-		if err == sql.ErrNoRows {
-			// Return the zero value of data
-			return fmt.Errorf("unable to query account data for address %v : %w", addr, err)
+			consistent, err := qs.lookupResources(addr, data.round, &data.accountData)
+			if err != nil || consistent {
+				return err
+			}
+			// the accounts were updated in between the two queries, so look the account up again.
 		}
-
-		return err
 	})
 
 	return
 }
 
+// lookupResources merges the asset holdings and application local states of the given address into the given account
+// data. Since these are read apart from the account data, it returns false if they weren't read at the given round.
+func (qs *accountsDbQueries) lookupResources(addr basics.Address, round basics.Round, ad *basics.AccountData) (bool, error) {
+	rows, err := qs.lookupResourcesStmt.Query(addr[:])
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rnd basics.Round
+		var aidx, rtype sql.NullInt64
+		var buf []byte
+		err = rows.Scan(&rnd, &aidx, &rtype, &buf)
+		if err != nil {
+			return false, err
+		}
+		if rnd != round {
+			return false, nil
+		}
+		if !aidx.Valid {
+			// the account has no resources.
+			continue
+		}
+		err = mergeAccountResource(ad, basics.CreatableIndex(aidx.Int64), basics.CreatableType(rtype.Int64), buf)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, rows.Err()
+}
+
 func (qs *accountsDbQueries) storeCatchpoint(ctx context.Context, round basics.Round, fileName string, catchpoint string, fileSize int64) (err error) {
 	err = db.Retry(func() (err error) {
 		_, err = qs.deleteStoredCatchpoint.ExecContext(ctx, round)
@@ -982,7 +1398,10 @@ func (qs *accountsDbQueries) close() {
 	preparedQueries := []**sql.Stmt{
 		&qs.listCreatablesStmt,
 		&qs.lookupStmt,
+		&qs.lookupResourcesStmt,
 		&qs.lookupCreatorStmt,
+		&qs.lookupBoxStmt,
+		&qs.listBoxesStmt,
 		&qs.deleteStoredCatchpoint,
 		&qs.insertStoredCatchpoint,
 		&qs.selectOldestCatchpointFiles,
@@ -1072,7 +1491,8 @@ func accountsPutTotals(tx *sql.Tx, totals ledgercore.AccountTotals, catchpointSt
 	return err
 }
 
-// accountsNewRound updates the accountbase and assetcreators tables by applying the provided deltas to the accounts / creatables.
+// accountsNewRound updates the accountbase, resources and assetcreators tables by applying the provided deltas to the accounts / creatables.
+// Only the resource changes set by compactAccountDeltas.makeResourceDeltas are written to the resources table.
 // The function returns a persistedAccountData for the modified accounts which can be stored in the base cache.
 func accountsNewRound(tx *sql.Tx, updates compactAccountDeltas, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, proto config.ConsensusParams, lastUpdateRound basics.Round) (updatedAccounts []persistedAccountData, err error) {
 
@@ -1095,12 +1515,28 @@ func accountsNewRound(tx *sql.Tx, updates compactAccountDeltas, creatables map[b
 		return
 	}
 	defer updateStmt.Close()
+
+	var upsertResourceStmt, deleteResourceStmt *sql.Stmt
+	upsertResourceStmt, err = tx.Prepare("INSERT OR REPLACE INTO resources (address, aidx, rtype, data) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return
+	}
+	defer upsertResourceStmt.Close()
+
+	deleteResourceStmt, err = tx.Prepare("DELETE FROM resources WHERE address=? AND aidx=? AND rtype=?")
+	if err != nil {
+		return
+	}
+	defer deleteResourceStmt.Close()
+
 	var result sql.Result
 	var rowsAffected int64
 	updatedAccounts = make([]persistedAccountData, updates.len())
 	updatedAccountIdx := 0
 	for i := 0; i < updates.len(); i++ {
 		addr, data := updates.getByIdx(i)
+		// the asset holdings and application local states are kept in the resources table.
+		baseData := accountBaseData(data.new)
 		if data.old.rowid == 0 {
 			// zero rowid means we don't have a previous value.
 			if data.new.IsZero() {
@@ -1108,7 +1544,7 @@ func accountsNewRound(tx *sql.Tx, updates compactAccountDeltas, creatables map[b
 			} else {
 				// create a new entry.
 				normBalance := data.new.NormalizedOnlineBalance(proto)
-				result, err = insertStmt.Exec(addr[:], normBalance, protocol.Encode(&data.new))
+				result, err = insertStmt.Exec(addr[:], normBalance, protocol.Encode(&baseData))
 				if err == nil {
 					updatedAccounts[updatedAccountIdx].rowid, err = result.LastInsertId()
 					updatedAccounts[updatedAccountIdx].accountData = data.new
@@ -1130,7 +1566,7 @@ func accountsNewRound(tx *sql.Tx, updates compactAccountDeltas, creatables map[b
 				}
 			} else {
 				normBalance := data.new.NormalizedOnlineBalance(proto)
-				result, err = updateStmt.Exec(normBalance, protocol.Encode(&data.new), data.old.rowid)
+				result, err = updateStmt.Exec(normBalance, protocol.Encode(&baseData), data.old.rowid)
 				if err == nil {
 					// rowid doesn't change on update.
 					updatedAccounts[updatedAccountIdx].rowid = data.old.rowid
@@ -1147,6 +1583,18 @@ func accountsNewRound(tx *sql.Tx, updates compactAccountDeltas, creatables map[b
 			return
 		}
 
+		// write only the resources that were changed.
+		for _, rdelta := range data.resources {
+			if rdelta.new != nil {
+				_, err = upsertResourceStmt.Exec(addr[:], rdelta.aidx, rdelta.rtype, rdelta.new)
+			} else {
+				_, err = deleteResourceStmt.Exec(addr[:], rdelta.aidx, rdelta.rtype)
+			}
+			if err != nil {
+				return
+			}
+		}
+
 		// set the returned persisted account states so that we could store that as the baseAccounts in commitRound
 		updatedAccounts[updatedAccountIdx].round = lastUpdateRound
 		updatedAccounts[updatedAccountIdx].addr = addr
@@ -1181,50 +1629,314 @@ func accountsNewRound(tx *sql.Tx, updates compactAccountDeltas, creatables map[b
 	return
 }
 
-// totalsNewRounds updates the accountsTotals by applying series of round changes
-func totalsNewRounds(tx *sql.Tx, updates []ledgercore.AccountDeltas, compactUpdates compactAccountDeltas, accountTotals []ledgercore.AccountTotals, proto config.ConsensusParams) (err error) {
-	var ot basics.OverflowTracker
-	totals, err := accountsTotals(tx, false)
-	if err != nil {
+// boxesNewRound writes the given box changes to the kvstore table
+func boxesNewRound(tx *sql.Tx, boxes map[string]ledgercore.ModifiedBox) (err error) {
+	if len(boxes) == 0 {
 		return
 	}
 
-	// copy the updates base account map, since we don't want to modify the input map.
-	accounts := make(map[basics.Address]basics.AccountData, compactUpdates.len())
-	for i := 0; i < compactUpdates.len(); i++ {
-		addr, acctData := compactUpdates.getByIdx(i)
-		accounts[addr] = acctData.old.accountData
-	}
-
-	for i := 0; i < len(updates); i++ {
-		totals.ApplyRewards(accountTotals[i].RewardsLevel, &ot)
-
-		for j := 0; j < updates[i].Len(); j++ {
-			addr, data := updates[i].GetByIdx(j)
-
-			if oldAccountData, has := accounts[addr]; has {
-				totals.DelAccount(proto, oldAccountData, &ot)
-			} else {
-				err = fmt.Errorf("missing old account data")
-				return
-			}
-
-			totals.AddAccount(proto, data, &ot)
-			accounts[addr] = data
-		}
-	}
-
-	if ot.Overflowed {
-		err = fmt.Errorf("overflow computing totals")
+	upsertStmt, err := tx.Prepare("INSERT OR REPLACE INTO kvstore (key, value) VALUES (?, ?)")
+	if err != nil {
 		return
 	}
+	defer upsertStmt.Close()
 
-	err = accountsPutTotals(tx, totals, false)
+	deleteStmt, err := tx.Prepare("DELETE FROM kvstore WHERE key=?")
 	if err != nil {
 		return
 	}
+	defer deleteStmt.Close()
 
-	return
+	for key, bdelta := range boxes {
+		if bdelta.Value != nil {
+			_, err = upsertStmt.Exec([]byte(key), []byte(*bdelta.Value))
+		} else {
+			_, err = deleteStmt.Exec([]byte(key))
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// boxesLoadOld loads the current database values of the given box keys. Boxes that
+// don't exist in the database are omitted from the returned map.
+func boxesLoadOld(tx *sql.Tx, boxes map[string]ledgercore.ModifiedBox) (old map[string]string, err error) {
+	old = make(map[string]string, len(boxes))
+	if len(boxes) == 0 {
+		return
+	}
+
+	selectStmt, err := tx.Prepare("SELECT value FROM kvstore WHERE key=?")
+	if err != nil {
+		return
+	}
+	defer selectStmt.Close()
+
+	var valueBuf []byte
+	for key := range boxes {
+		err = selectStmt.QueryRow([]byte(key)).Scan(&valueBuf)
+		switch err {
+		case nil:
+			old[key] = string(valueBuf)
+		case sql.ErrNoRows:
+			err = nil
+		default:
+			return
+		}
+	}
+	return
+}
+
+// addBoxesToTrie adds the hashes of all the boxes stored in the kvstore table to the given trie, returning
+// the number of boxes added.
+func addBoxesToTrie(ctx context.Context, tx *sql.Tx, trie *merkletrie.Trie) (count int, err error) {
+	rows, err := tx.QueryContext(ctx, "SELECT key, value FROM kvstore")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var keyBuf, valueBuf []byte
+	for rows.Next() {
+		err = rows.Scan(&keyBuf, &valueBuf)
+		if err != nil {
+			return
+		}
+		hash := boxHashBuilder(string(keyBuf), string(valueBuf))
+		var added bool
+		added, err = trie.Add(hash)
+		if err != nil {
+			return
+		}
+		if added {
+			count++
+		}
+	}
+	err = rows.Err()
+	return
+}
+
+// addResourcesToTrie adds the hashes of all the account resources stored in the resources table to the given trie,
+// returning the number of resources added.
+func addResourcesToTrie(ctx context.Context, tx *sql.Tx, trie *merkletrie.Trie) (count int, err error) {
+	rows, err := tx.QueryContext(ctx, "SELECT address, aidx, rtype, data FROM resources")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var addr basics.Address
+	var addrbuf, buf []byte
+	var aidx basics.CreatableIndex
+	var rtype basics.CreatableType
+	for rows.Next() {
+		err = rows.Scan(&addrbuf, &aidx, &rtype, &buf)
+		if err != nil {
+			return
+		}
+		if len(addrbuf) != len(addr) {
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+			return
+		}
+		copy(addr[:], addrbuf)
+		hash := resourceHashBuilder(addr, aidx, rtype, buf)
+		var added bool
+		added, err = trie.Add(hash)
+		if err != nil {
+			return
+		}
+		if added {
+			count++
+		}
+	}
+	err = rows.Err()
+	return
+}
+
+// addAccountsBatchToTrie adds to the given trie the hashes of up to limit accounts, in the order of their addresses,
+// starting right after the cursor address ( or from the first account, when the cursor is nil ). When resourceHashes
+// is set, the asset holdings and application local states of these accounts are added as entries of their own;
+// otherwise, they're hashed along with the account. It returns the address of the last account that was added, and
+// whether all the accounts following the cursor were added.
+func addAccountsBatchToTrie(ctx context.Context, tx *sql.Tx, trie *merkletrie.Trie, cursor []byte, limit int, resourceHashes bool) (last []byte, done bool, err error) {
+	if cursor == nil {
+		cursor = []byte{}
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT address, data FROM accountbase WHERE address > ? ORDER BY address LIMIT ?", cursor, limit)
+	if err != nil {
+		return
+	}
+	type addressData struct {
+		addr basics.Address
+		buf  []byte
+	}
+	accounts := make([]addressData, 0, limit)
+	for rows.Next() {
+		var addrbuf, buf []byte
+		err = rows.Scan(&addrbuf, &buf)
+		if err != nil {
+			rows.Close()
+			return
+		}
+		var acct addressData
+		if len(addrbuf) != len(acct.addr) {
+			rows.Close()
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(acct.addr))
+			return
+		}
+		copy(acct.addr[:], addrbuf)
+		acct.buf = buf
+		accounts = append(accounts, acct)
+	}
+	rows.Close()
+	err = rows.Err()
+	if err != nil {
+		return
+	}
+
+	resourcesStmt, err := tx.PrepareContext(ctx, "SELECT aidx, rtype, data FROM resources WHERE address=?")
+	if err != nil {
+		return
+	}
+	defer resourcesStmt.Close()
+
+	for _, acct := range accounts {
+		var accountData basics.AccountData
+		err = protocol.Decode(acct.buf, &accountData)
+		if err != nil {
+			return
+		}
+		buf := acct.buf
+		if resourceHashes {
+			var resRows *sql.Rows
+			resRows, err = resourcesStmt.QueryContext(ctx, acct.addr[:])
+			if err != nil {
+				return
+			}
+			var aidx basics.CreatableIndex
+			var rtype basics.CreatableType
+			var resBuf []byte
+			for resRows.Next() {
+				err = resRows.Scan(&aidx, &rtype, &resBuf)
+				if err == nil {
+					_, err = trie.Add(resourceHashBuilder(acct.addr, aidx, rtype, resBuf))
+				}
+				if err != nil {
+					resRows.Close()
+					return
+				}
+			}
+			resRows.Close()
+			err = resRows.Err()
+			if err != nil {
+				return
+			}
+		} else {
+			err = loadAccountResources(resourcesStmt, acct.addr, &accountData)
+			if err != nil {
+				return
+			}
+			if len(accountData.Assets) > 0 || len(accountData.AppLocalStates) > 0 {
+				buf = protocol.Encode(&accountData)
+			}
+		}
+		_, err = trie.Add(accountHashBuilder(acct.addr, accountData, buf))
+		if err != nil {
+			return
+		}
+	}
+
+	last = cursor
+	if len(accounts) > 0 {
+		last = append([]byte{}, accounts[len(accounts)-1].addr[:]...)
+	}
+	done = len(accounts) < limit
+	return
+}
+
+// addBoxesBatchToTrie adds to the given trie the hashes of up to limit boxes, in the order of their keys, starting
+// right after the cursor key ( or from the first box, when the cursor is nil ). It returns the key of the last box
+// that was added, and whether all the boxes following the cursor were added.
+func addBoxesBatchToTrie(ctx context.Context, tx *sql.Tx, trie *merkletrie.Trie, cursor []byte, limit int) (last []byte, done bool, err error) {
+	if cursor == nil {
+		cursor = []byte{}
+	}
+	rows, err := tx.QueryContext(ctx, "SELECT key, value FROM kvstore WHERE key > ? ORDER BY key LIMIT ?", cursor, limit)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	last = cursor
+	count := 0
+	var keyBuf, valueBuf []byte
+	for rows.Next() {
+		err = rows.Scan(&keyBuf, &valueBuf)
+		if err != nil {
+			return
+		}
+		_, err = trie.Add(boxHashBuilder(string(keyBuf), string(valueBuf)))
+		if err != nil {
+			return
+		}
+		last = append([]byte{}, keyBuf...)
+		count++
+	}
+	err = rows.Err()
+	done = count < limit
+	return
+}
+
+// totalsNewRounds updates the accountsTotals by applying series of round changes
+func totalsNewRounds(tx *sql.Tx, updates []ledgercore.AccountDeltas, compactUpdates compactAccountDeltas, accountTotals []ledgercore.AccountTotals, proto config.ConsensusParams) (err error) {
+	totals, err := accountsTotals(tx, false)
+	if err != nil {
+		return
+	}
+
+	totals, err = applyTotalsNewRounds(totals, updates, compactUpdates, accountTotals, proto)
+	if err != nil {
+		return
+	}
+
+	return accountsPutTotals(tx, totals, false)
+}
+
+// applyTotalsNewRounds returns the given totals after applying series of round changes, given the old data of the
+// modified accounts in compactUpdates
+func applyTotalsNewRounds(totals ledgercore.AccountTotals, updates []ledgercore.AccountDeltas, compactUpdates compactAccountDeltas, accountTotals []ledgercore.AccountTotals, proto config.ConsensusParams) (ledgercore.AccountTotals, error) {
+	var ot basics.OverflowTracker
+
+	// copy the updates base account map, since we don't want to modify the input map.
+	accounts := make(map[basics.Address]basics.AccountData, compactUpdates.len())
+	for i := 0; i < compactUpdates.len(); i++ {
+		addr, acctData := compactUpdates.getByIdx(i)
+		accounts[addr] = acctData.old.accountData
+	}
+
+	for i := 0; i < len(updates); i++ {
+		totals.ApplyRewards(accountTotals[i].RewardsLevel, &ot)
+
+		for j := 0; j < updates[i].Len(); j++ {
+			addr, data := updates[i].GetByIdx(j)
+
+			if oldAccountData, has := accounts[addr]; has {
+				totals.DelAccount(proto, oldAccountData, &ot)
+			} else {
+				return totals, fmt.Errorf("missing old account data")
+			}
+
+			totals.AddAccount(proto, data, &ot)
+			accounts[addr] = data
+		}
+	}
+
+	if ot.Overflowed {
+		return totals, fmt.Errorf("overflow computing totals")
+	}
+	return totals, nil
 }
 
 // updates the round number associated with the current account data.
@@ -1283,6 +1995,158 @@ func totalAccounts(ctx context.Context, tx *sql.Tx) (total uint64, err error) {
 	return
 }
 
+func totalBoxes(ctx context.Context, tx *sql.Tx) (total uint64, err error) {
+	err = tx.QueryRowContext(ctx, "SELECT count(*) FROM kvstore").Scan(&total)
+	if err == sql.ErrNoRows {
+		total = 0
+		err = nil
+	}
+	return
+}
+
+// readBoxes returns up to limit boxes, ordered by their keys, skipping the first offset ones.
+func readBoxes(ctx context.Context, tx *sql.Tx, offset uint64, limit uint64) (boxes []encodedBoxRecord, err error) {
+	rows, err := tx.QueryContext(ctx, "SELECT key, value FROM kvstore ORDER BY key LIMIT ? OFFSET ?", limit, offset)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var box encodedBoxRecord
+		err = rows.Scan(&box.Key, &box.Value)
+		if err != nil {
+			return
+		}
+		boxes = append(boxes, box)
+	}
+	err = rows.Err()
+	return
+}
+
+func totalResources(ctx context.Context, tx *sql.Tx) (total uint64, err error) {
+	err = tx.QueryRowContext(ctx, "SELECT count(*) FROM resources").Scan(&total)
+	if err == sql.ErrNoRows {
+		total = 0
+		err = nil
+	}
+	return
+}
+
+// readResources returns up to limit account resources, ordered by their address, creatable index and type, starting
+// right after the given one, or from the first one if after is nil. Paging by the resources key rather than by an
+// offset spares each call from scanning over all the resources that precede it.
+func readResources(ctx context.Context, tx *sql.Tx, after *encodedResourceRecord, limit uint64) (resources []encodedResourceRecord, err error) {
+	var rows *sql.Rows
+	if after == nil {
+		rows, err = tx.QueryContext(ctx, "SELECT address, aidx, rtype, data FROM resources ORDER BY address, aidx, rtype LIMIT ?", limit)
+	} else {
+		rows, err = tx.QueryContext(ctx, "SELECT address, aidx, rtype, data FROM resources WHERE (address, aidx, rtype) > (?, ?, ?) ORDER BY address, aidx, rtype LIMIT ?", after.Address[:], after.Aidx, after.Rtype, limit)
+	}
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var resource encodedResourceRecord
+		var addrbuf, buf []byte
+		err = rows.Scan(&addrbuf, &resource.Aidx, &resource.Rtype, &buf)
+		if err != nil {
+			return
+		}
+		if len(addrbuf) != len(resource.Address) {
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(resource.Address))
+			return
+		}
+		copy(resource.Address[:], addrbuf)
+		resource.Data = buf
+		resources = append(resources, resource)
+	}
+	err = rows.Err()
+	return
+}
+
+// splitAccountsResources moves the asset holdings and application local states of all the accounts in the accountbase
+// table into the resources table, and rewrites the remaining account data in accountbase.
+// on return, it returns the number of modified accounts as well as an error ( if we had any )
+func splitAccountsResources(ctx context.Context, tx *sql.Tx) (modifiedAccounts uint, err error) {
+	scannedAccounts := 0
+
+	updateStmt, err := tx.PrepareContext(ctx, "UPDATE accountbase SET data = ? WHERE address = ?")
+	if err != nil {
+		return 0, err
+	}
+	defer updateStmt.Close()
+
+	insertStmt, err := tx.PrepareContext(ctx, "INSERT INTO resources (address, aidx, rtype, data) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer insertStmt.Close()
+
+	rows, err := tx.QueryContext(ctx, "SELECT address, data FROM accountbase")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var addr basics.Address
+	for rows.Next() {
+		// as in reencodeAccounts, update the warning deadline once every 1000 accounts we scan through.
+		if scannedAccounts%1000 == 0 {
+			db.ResetTransactionWarnDeadline(ctx, tx, time.Now().Add(time.Second))
+		}
+
+		var addrbuf []byte
+		var buf []byte
+		err = rows.Scan(&addrbuf, &buf)
+		if err != nil {
+			return
+		}
+
+		if len(addrbuf) != len(addr) {
+			err = fmt.Errorf("Account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+			return
+		}
+		copy(addr[:], addrbuf[:])
+		scannedAccounts++
+
+		var accountData basics.AccountData
+		err = protocol.Decode(buf, &accountData)
+		if err != nil {
+			return
+		}
+		resources := encodeAccountResources(accountData)
+		if len(resources) == 0 {
+			continue
+		}
+
+		for key, resourceData := range resources {
+			_, err = insertStmt.ExecContext(ctx, addrbuf, key.aidx, key.rtype, resourceData)
+			if err != nil {
+				return 0, err
+			}
+		}
+		baseData := accountBaseData(accountData)
+		result, err := updateStmt.ExecContext(ctx, protocol.Encode(&baseData), addrbuf)
+		if err != nil {
+			return 0, err
+		}
+		rowsUpdated, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		if rowsUpdated != 1 {
+			return 0, fmt.Errorf("failed to update account %v, number of rows updated was %d instead of 1", addr, rowsUpdated)
+		}
+		modifiedAccounts++
+	}
+
+	err = rows.Err()
+	return
+}
+
 // reencodeAccounts reads all the accounts in the accountbase table, decode and reencode the account data.
 // if the account data is found to have a different encoding, it would update the encoded account on disk.
 // on return, it returns the number of modified accounts as well as an error ( if we had any )
@@ -1502,20 +2366,24 @@ const (
 
 // orderedAccountsIter allows us to iterate over the accounts addresses in the order of the account hashes.
 type orderedAccountsIter struct {
-	step         orderedAccountsIterStep
-	rows         *sql.Rows
-	tx           *sql.Tx
-	accountCount int
-	insertStmt   *sql.Stmt
+	step           orderedAccountsIterStep
+	rows           *sql.Rows
+	tx             *sql.Tx
+	accountCount   int
+	resourceHashes bool
+	insertStmt     *sql.Stmt
+	resourcesStmt  *sql.Stmt
 }
 
 // makeOrderedAccountsIter creates an ordered account iterator. Note that due to implementation reasons,
-// only a single iterator can be active at a time.
-func makeOrderedAccountsIter(tx *sql.Tx, accountCount int) *orderedAccountsIter {
+// only a single iterator can be active at a time. Unless resourceHashes is set, the accounts are hashed
+// along with their asset holdings and application local states.
+func makeOrderedAccountsIter(tx *sql.Tx, accountCount int, resourceHashes bool) *orderedAccountsIter {
 	return &orderedAccountsIter{
-		tx:           tx,
-		accountCount: accountCount,
-		step:         oaiStepStartup,
+		tx:             tx,
+		accountCount:   accountCount,
+		resourceHashes: resourceHashes,
+		step:           oaiStepStartup,
 	}
 }
 
@@ -1564,6 +2432,12 @@ func (iterator *orderedAccountsIter) Next(ctx context.Context) (acct []accountAd
 		if err != nil {
 			return
 		}
+		if !iterator.resourceHashes {
+			iterator.resourcesStmt, err = iterator.tx.PrepareContext(ctx, "SELECT aidx, rtype, data FROM resources WHERE address=?")
+			if err != nil {
+				return
+			}
+		}
 		iterator.step = oaiStepInsertAccountData
 		return
 	}
@@ -1593,6 +2467,16 @@ func (iterator *orderedAccountsIter) Next(ctx context.Context) (acct []accountAd
 				iterator.Close(ctx)
 				return
 			}
+			if iterator.resourcesStmt != nil {
+				err = loadAccountResources(iterator.resourcesStmt, addr, &accountData)
+				if err != nil {
+					iterator.Close(ctx)
+					return
+				}
+				if len(accountData.Assets) > 0 || len(accountData.AppLocalStates) > 0 {
+					buf = protocol.Encode(&accountData)
+				}
+			}
 			hash := accountHashBuilder(addr, accountData, buf)
 			_, err = iterator.insertStmt.ExecContext(ctx, addrbuf, hash)
 			if err != nil {
@@ -1612,6 +2496,10 @@ func (iterator *orderedAccountsIter) Next(ctx context.Context) (acct []accountAd
 		iterator.rows = nil
 		iterator.insertStmt.Close()
 		iterator.insertStmt = nil
+		if iterator.resourcesStmt != nil {
+			iterator.resourcesStmt.Close()
+			iterator.resourcesStmt = nil
+		}
 		iterator.step = oaiStepCreateOrderingAccountIndex
 		return
 	}
@@ -1690,6 +2578,10 @@ func (iterator *orderedAccountsIter) Close(ctx context.Context) (err error) {
 		iterator.insertStmt.Close()
 		iterator.insertStmt = nil
 	}
+	if iterator.resourcesStmt != nil {
+		iterator.resourcesStmt.Close()
+		iterator.resourcesStmt = nil
+	}
 	_, err = iterator.tx.ExecContext(ctx, "DROP TABLE IF EXISTS accountsiteratorhashes")
 	return
 }
@@ -33,6 +33,7 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/logging"
@@ -554,6 +555,7 @@ func TestAccountDBRound(t *testing.T) {
 		updatesCnt := makeCompactAccountDeltas([]ledgercore.AccountDeltas{updates}, baseAccounts)
 		err = updatesCnt.accountsLoadOld(tx)
 		require.NoError(t, err)
+		updatesCnt.makeResourceDeltas()
 		err = totalsNewRounds(tx, []ledgercore.AccountDeltas{updates}, updatesCnt, []ledgercore.AccountTotals{{}}, proto)
 		require.NoError(t, err)
 		_, err = accountsNewRound(tx, updatesCnt, ctbsWithDeletes, proto, basics.Round(i))
@@ -1028,6 +1030,169 @@ func TestAccountsReencoding(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestAccountsResourcesSplit tests that splitAccountsResources moves the asset holdings and application local states
+// out of accountbase, and that the accounts still look up to their full data.
+func TestAccountsResourcesSplit(t *testing.T) {
+	dbs, _ := dbOpenTest(t, true)
+	setDbLogging(t, dbs)
+	defer dbs.Close()
+
+	accounts := make(map[basics.Address]basics.AccountData)
+	for i := 0; i < 10; i++ {
+		accData := basics.AccountData{
+			MicroAlgos:  basics.MicroAlgos{Raw: uint64(i + 1)},
+			RewardsBase: uint64(i),
+		}
+		if i%2 == 0 {
+			accData.Assets = map[basics.AssetIndex]basics.AssetHolding{
+				basics.AssetIndex(100 + i): {Amount: uint64(i), Frozen: i%4 == 0},
+				basics.AssetIndex(200 + i): {Amount: uint64(2 * i)},
+			}
+		}
+		if i%3 == 0 {
+			accData.AppLocalStates = map[basics.AppIndex]basics.AppLocalState{
+				basics.AppIndex(300 + i): {
+					Schema:   basics.StateSchema{NumUint: 1},
+					KeyValue: basics.TealKeyValue{"k": {Type: basics.TealUintType, Uint: uint64(i)}},
+				},
+			}
+		}
+		accounts[randomAddress()] = accData
+	}
+
+	err := dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		_, err = accountsInit(tx, make(map[basics.Address]basics.AccountData), config.Consensus[protocol.ConsensusCurrentVersion])
+		if err != nil {
+			return err
+		}
+		// write the accounts the way the previous schema did, with the resources inlined.
+		for addr, accData := range accounts {
+			_, err = tx.ExecContext(ctx, "INSERT INTO accountbase (address, data) VALUES (?, ?)", addr[:], protocol.Encode(&accData))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	var expectedResources uint64
+	for _, accData := range accounts {
+		expectedResources += uint64(len(accData.Assets) + len(accData.AppLocalStates))
+	}
+	err = dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		modifiedAccounts, err := splitAccountsResources(ctx, tx)
+		if err != nil {
+			return err
+		}
+		require.Equal(t, 7, int(modifiedAccounts))
+
+		total, err := totalResources(ctx, tx)
+		if err != nil {
+			return err
+		}
+		require.Equal(t, expectedResources, total)
+
+		// reading the resources a few at a time yields the same ones as reading them all at once.
+		all, err := readResources(ctx, tx, nil, total)
+		if err != nil {
+			return err
+		}
+		require.Len(t, all, int(total))
+		var paged []encodedResourceRecord
+		var last *encodedResourceRecord
+		for {
+			chunk, err := readResources(ctx, tx, last, 4)
+			if err != nil {
+				return err
+			}
+			if len(chunk) == 0 {
+				break
+			}
+			paged = append(paged, chunk...)
+			last = &chunk[len(chunk)-1]
+		}
+		require.Equal(t, all, paged)
+
+		for addr := range accounts {
+			var buf []byte
+			err = tx.QueryRowContext(ctx, "SELECT data FROM accountbase WHERE address=?", addr[:]).Scan(&buf)
+			if err != nil {
+				return err
+			}
+			var base basics.AccountData
+			err = protocol.Decode(buf, &base)
+			if err != nil {
+				return err
+			}
+			require.Empty(t, base.Assets)
+			require.Empty(t, base.AppLocalStates)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	qs, err := accountsDbInit(dbs.Rdb.Handle, dbs.Wdb.Handle)
+	require.NoError(t, err)
+	defer qs.close()
+
+	for addr, accData := range accounts {
+		pad, err := qs.lookup(addr)
+		require.NoError(t, err)
+		require.Equal(t, accData, pad.accountData)
+	}
+}
+
+func TestMakeResourceDeltas(t *testing.T) {
+	localState := basics.AppLocalState{
+		Schema:   basics.StateSchema{NumUint: 1},
+		KeyValue: basics.TealKeyValue{"k": {Type: basics.TealUintType, Uint: 1}},
+	}
+	old := basics.AccountData{
+		Assets: map[basics.AssetIndex]basics.AssetHolding{
+			1: {Amount: 1},
+			2: {Amount: 2},
+			3: {Amount: 3},
+		},
+		AppLocalStates: map[basics.AppIndex]basics.AppLocalState{
+			4: localState,
+			5: localState,
+		},
+	}
+	new := basics.AccountData{
+		Assets: map[basics.AssetIndex]basics.AssetHolding{
+			1: {Amount: 1},
+			2: {Amount: 2, Frozen: true},
+			6: {Amount: 6},
+		},
+		AppLocalStates: map[basics.AppIndex]basics.AppLocalState{
+			4: {Schema: localState.Schema, KeyValue: localState.KeyValue.Clone()},
+			5: {Schema: localState.Schema, KeyValue: basics.TealKeyValue{"k": {Type: basics.TealUintType, Uint: 2}}},
+		},
+	}
+
+	// only the added, modified and deleted resources are returned, with their old and new encodings.
+	deltas := make(map[resourceKey]resourceDelta)
+	for _, rdelta := range makeResourceDeltas(old, new) {
+		deltas[rdelta.resourceKey] = rdelta
+	}
+	require.Len(t, deltas, 4)
+	oldResources := encodeAccountResources(old)
+	newResources := encodeAccountResources(new)
+	for _, key := range []resourceKey{
+		{aidx: 2, rtype: basics.AssetCreatable},
+		{aidx: 3, rtype: basics.AssetCreatable},
+		{aidx: 6, rtype: basics.AssetCreatable},
+		{aidx: 5, rtype: basics.AppCreatable},
+	} {
+		require.Contains(t, deltas, key)
+		require.Equal(t, oldResources[key], deltas[key].old)
+		require.Equal(t, newResources[key], deltas[key].new)
+	}
+
+	require.Empty(t, makeResourceDeltas(old, old))
+}
+
 // TestAccountsDbQueriesCreateClose tests to see that we can create the accountsDbQueries and close it.
 // it also verify that double-closing it doesn't create an issue.
 func TestAccountsDbQueriesCreateClose(t *testing.T) {
@@ -1052,6 +1217,111 @@ func TestAccountsDbQueriesCreateClose(t *testing.T) {
 	require.Nil(t, qs.listCreatablesStmt)
 }
 
+// TestBoxesDB tests that boxes written by boxesNewRound can be looked up and listed.
+func TestBoxesDB(t *testing.T) {
+	dbs, _ := dbOpenTest(t, true)
+	setDbLogging(t, dbs)
+	defer dbs.Close()
+
+	value := func(v string) *string { return &v }
+	err := dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		_, err = accountsInit(tx, make(map[basics.Address]basics.AccountData), config.Consensus[protocol.ConsensusCurrentVersion])
+		if err != nil {
+			return err
+		}
+		err = boxesNewRound(tx, map[string]ledgercore.ModifiedBox{
+			ledgercore.MakeBoxKey(1, "b"):     {Value: value("one-b")},
+			ledgercore.MakeBoxKey(1, "a"):     {Value: value("one-a")},
+			ledgercore.MakeBoxKey(1, "gone"):  {Value: value("")},
+			ledgercore.MakeBoxKey(2, "a"):     {Value: value("two-a")},
+			ledgercore.MakeBoxKey(3, "empty"): {Value: value("")},
+		})
+		if err != nil {
+			return err
+		}
+		return boxesNewRound(tx, map[string]ledgercore.ModifiedBox{
+			ledgercore.MakeBoxKey(1, "gone"): {Value: nil},
+			ledgercore.MakeBoxKey(1, "b"):    {Value: value("one-b-updated")},
+		})
+	})
+	require.NoError(t, err)
+
+	qs, err := accountsDbInit(dbs.Rdb.Handle, dbs.Wdb.Handle)
+	require.NoError(t, err)
+	defer qs.close()
+
+	v, ok, _, err := qs.lookupBox(ledgercore.MakeBoxKey(1, "b"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "one-b-updated", v)
+	_, ok, _, err = qs.lookupBox(ledgercore.MakeBoxKey(1, "gone"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	v, ok, _, err = qs.lookupBox(ledgercore.MakeBoxKey(3, "empty"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "", v)
+
+	keys, _, err := qs.listBoxes(1, 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{ledgercore.MakeBoxKey(1, "a"), ledgercore.MakeBoxKey(1, "b")}, keys)
+	keys, _, err = qs.listBoxes(1, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{ledgercore.MakeBoxKey(1, "a")}, keys)
+	keys, _, err = qs.listBoxes(4, 10)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
+
+// TestAddBatchesToTrie tests that adding the accounts and the boxes to the trie a batch at a time yields the same
+// trie as rebuilding it in a single pass.
+func TestAddBatchesToTrie(t *testing.T) {
+	dbs, _ := dbOpenTest(t, true)
+	setDbLogging(t, dbs)
+	defer dbs.Close()
+
+	tx, err := dbs.Wdb.Handle.Begin()
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	_, err = accountsInit(tx, randomAccounts(20, false), config.Consensus[protocol.ConsensusCurrentVersion])
+	require.NoError(t, err)
+	boxes := make(map[string]ledgercore.ModifiedBox)
+	for i := 0; i < 7; i++ {
+		value := fmt.Sprintf("value-%d", i)
+		boxes[ledgercore.MakeBoxKey(basics.AppIndex(i%3+1), fmt.Sprintf("box-%d", i))] = ledgercore.ModifiedBox{Value: &value}
+	}
+	require.NoError(t, boxesNewRound(tx, boxes))
+
+	au := &accountUpdates{log: logging.TestingLog(t)}
+	for _, resourceHashes := range []bool{false, true} {
+		var expectedCommitter merkletrie.InMemoryCommitter
+		expected, err := merkletrie.MakeTrie(&expectedCommitter, TrieMemoryConfig)
+		require.NoError(t, err)
+		require.NoError(t, au.rebuildMerkleTrie(context.Background(), tx, expected, resourceHashes))
+
+		var committer merkletrie.InMemoryCommitter
+		trie, err := merkletrie.MakeTrie(&committer, TrieMemoryConfig)
+		require.NoError(t, err)
+		var cursor []byte
+		for done := false; !done; {
+			cursor, done, err = addAccountsBatchToTrie(context.Background(), tx, trie, cursor, 3, resourceHashes)
+			require.NoError(t, err)
+		}
+		cursor = nil
+		for done := false; !done; {
+			cursor, done, err = addBoxesBatchToTrie(context.Background(), tx, trie, cursor, 3)
+			require.NoError(t, err)
+		}
+
+		expectedRoot, err := expected.RootHash()
+		require.NoError(t, err)
+		root, err := trie.RootHash()
+		require.NoError(t, err)
+		require.Equal(t, expectedRoot, root)
+	}
+}
+
 func benchmarkWriteCatchpointStagingBalancesSub(b *testing.B, ascendingOrder bool) {
 	proto := config.Consensus[protocol.ConsensusCurrentVersion]
 	genesisInitState, _ := testGenerateInitState(b, protocol.ConsensusCurrentVersion, 100)
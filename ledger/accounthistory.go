@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// accountHistorySchema holds the state of every account, and the creator of
+// every creatable, as of every round in which they were modified. The first
+// round of the history holds the state of all of them.
+var accountHistorySchema = []string{
+	`CREATE TABLE IF NOT EXISTS accounthistory (
+		address blob,
+		rnd integer,
+		data blob,
+		PRIMARY KEY (address, rnd))`,
+	`CREATE TABLE IF NOT EXISTS creatablehistory (
+		creatable integer,
+		ctype integer,
+		rnd integer,
+		creator blob,
+		PRIMARY KEY (creatable, ctype, rnd))`,
+	`CREATE TABLE IF NOT EXISTS accounthistoryrounds (
+		id string primary key,
+		rnd integer)`,
+}
+
+// accountHistoryRound holds the changes of a single round that were not
+// written to the database yet.
+type accountHistoryRound struct {
+	rnd        basics.Round
+	accounts   map[basics.Address]basics.AccountData
+	creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable
+}
+
+// accountHistory is a tracker persisting the changes every round makes to
+// the accounts and creatables, so that their state can be looked up at
+// rounds that are no longer available from the accountUpdates tracker.
+// It is only used by archival ledgers, which keep the blocks the
+// lookups of historical rewards depend on.
+//
+// accountHistory is registered as an ExternalTracker. Since external
+// trackers are committed in the background, it has a lock of its own.
+type accountHistory struct {
+	mu deadlock.RWMutex
+
+	dbs db.Pair
+
+	// earliest is the earliest round the history has the state of.
+	earliest basics.Round
+
+	// pending holds the rounds that were not committed yet.
+	pending []accountHistoryRound
+}
+
+func (ah *accountHistory) Name() string {
+	return "accounthistory"
+}
+
+func (ah *accountHistory) Migrations() []TrackerMigration {
+	return []TrackerMigration{
+		func(ctx context.Context, tx *sql.Tx) error {
+			for _, stmt := range accountHistorySchema {
+				_, err := tx.ExecContext(ctx, stmt)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Rebuild restarts the history at round rnd, with the current state of all
+// the accounts.
+func (ah *accountHistory) Rebuild(ctx context.Context, tx *sql.Tx, rnd basics.Round, accounts AccountIterator) error {
+	for _, stmt := range []string{"DELETE FROM accounthistory", "DELETE FROM creatablehistory"} {
+		_, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			return err
+		}
+	}
+
+	insertAccountStmt, err := tx.PrepareContext(ctx, "INSERT INTO accounthistory(address, rnd, data) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertAccountStmt.Close()
+	insertCreatableStmt, err := tx.PrepareContext(ctx, "INSERT INTO creatablehistory(creatable, ctype, rnd, creator) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertCreatableStmt.Close()
+
+	err = accounts(func(addr basics.Address, data basics.AccountData) error {
+		_, err := insertAccountStmt.ExecContext(ctx, addr[:], rnd, protocol.Encode(&data))
+		if err != nil {
+			return err
+		}
+		for aidx := range data.AssetParams {
+			_, err = insertCreatableStmt.ExecContext(ctx, aidx, basics.AssetCreatable, rnd, addr[:])
+			if err != nil {
+				return err
+			}
+		}
+		for aidx := range data.AppParams {
+			_, err = insertCreatableStmt.ExecContext(ctx, aidx, basics.AppCreatable, rnd, addr[:])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT OR REPLACE INTO accounthistoryrounds(id, rnd) VALUES('earliest', ?)", rnd)
+	return err
+}
+
+func (ah *accountHistory) LoadFromDisk(dbs db.Pair, rnd basics.Round) error {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+	ah.dbs = dbs
+	ah.pending = nil
+	return dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, "SELECT rnd FROM accounthistoryrounds WHERE id='earliest'").Scan(&ah.earliest)
+	})
+}
+
+func (ah *accountHistory) NewBlock(blk bookkeeping.Block, delta ledgercore.StateDelta) {
+	hr := accountHistoryRound{
+		rnd:        blk.Round(),
+		accounts:   make(map[basics.Address]basics.AccountData, delta.Accts.Len()),
+		creatables: make(map[basics.CreatableIndex]ledgercore.ModifiedCreatable, len(delta.Creatables)),
+	}
+	for i := 0; i < delta.Accts.Len(); i++ {
+		addr, data := delta.Accts.GetByIdx(i)
+		hr.accounts[addr] = data
+	}
+	for cidx, mc := range delta.Creatables {
+		hr.creatables[cidx] = mc
+	}
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+	ah.pending = append(ah.pending, hr)
+}
+
+func (ah *accountHistory) Commit(ctx context.Context, tx *sql.Tx, rnd basics.Round) error {
+	// the pending rounds are never modified, so only the slice needs the lock.
+	ah.mu.RLock()
+	var committing []accountHistoryRound
+	for _, hr := range ah.pending {
+		if hr.rnd > rnd {
+			break
+		}
+		committing = append(committing, hr)
+	}
+	ah.mu.RUnlock()
+
+	insertAccountStmt, err := tx.PrepareContext(ctx, "INSERT OR REPLACE INTO accounthistory(address, rnd, data) VALUES(?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertAccountStmt.Close()
+	insertCreatableStmt, err := tx.PrepareContext(ctx, "INSERT OR REPLACE INTO creatablehistory(creatable, ctype, rnd, creator) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer insertCreatableStmt.Close()
+
+	for _, hr := range committing {
+		for addr, data := range hr.accounts {
+			_, err = insertAccountStmt.ExecContext(ctx, addr[:], hr.rnd, protocol.Encode(&data))
+			if err != nil {
+				return err
+			}
+		}
+		for cidx, mc := range hr.creatables {
+			var creator []byte
+			if mc.Created {
+				creator = mc.Creator[:]
+			}
+			_, err = insertCreatableStmt.ExecContext(ctx, cidx, mc.Ctype, hr.rnd, creator)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (ah *accountHistory) Committed(rnd basics.Round) {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+	for len(ah.pending) > 0 && ah.pending[0].rnd <= rnd {
+		ah.pending = ah.pending[1:]
+	}
+}
+
+func (ah *accountHistory) Close() {
+	ah.mu.Lock()
+	defer ah.mu.Unlock()
+	ah.pending = nil
+}
+
+// lookup returns the state of the given account as of round rnd.
+func (ah *accountHistory) lookup(rnd basics.Round, addr basics.Address) (data basics.AccountData, err error) {
+	ah.mu.RLock()
+	defer ah.mu.RUnlock()
+	if rnd < ah.earliest {
+		return basics.AccountData{}, &RoundOffsetError{round: rnd, dbRound: ah.earliest}
+	}
+	for i := len(ah.pending) - 1; i >= 0; i-- {
+		if ah.pending[i].rnd > rnd {
+			continue
+		}
+		if data, ok := ah.pending[i].accounts[addr]; ok {
+			return data, nil
+		}
+	}
+
+	err = ah.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var buf []byte
+		err := tx.QueryRowContext(ctx, "SELECT data FROM accounthistory WHERE address=? AND rnd<=? ORDER BY rnd DESC LIMIT 1", addr[:], rnd).Scan(&buf)
+		if err == sql.ErrNoRows {
+			// the account did not exist as of that round.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return protocol.Decode(buf, &data)
+	})
+	return
+}
+
+// getCreator returns the creator of the given creatable as of round rnd.
+func (ah *accountHistory) getCreator(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (creator basics.Address, ok bool, err error) {
+	ah.mu.RLock()
+	defer ah.mu.RUnlock()
+	if rnd < ah.earliest {
+		return basics.Address{}, false, &RoundOffsetError{round: rnd, dbRound: ah.earliest}
+	}
+	for i := len(ah.pending) - 1; i >= 0; i-- {
+		if ah.pending[i].rnd > rnd {
+			continue
+		}
+		if mc, found := ah.pending[i].creatables[cidx]; found && mc.Ctype == ctype {
+			return mc.Creator, mc.Created, nil
+		}
+	}
+
+	err = ah.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var buf []byte
+		err := tx.QueryRowContext(ctx, "SELECT creator FROM creatablehistory WHERE creatable=? AND ctype=? AND rnd<=? ORDER BY rnd DESC LIMIT 1", cidx, ctype, rnd).Scan(&buf)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// a deleted creatable has no creator.
+		ok = len(buf) == len(creator)
+		copy(creator[:], buf)
+		return nil
+	})
+	return
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestAccountHistory(t *testing.T) {
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion, 100)
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		if addr != testPoolAddr && addr != testSinkAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dbTempDir)
+	dbPrefix := filepath.Join(dbTempDir, t.Name())
+	const inMem = false
+	log := logging.TestingLog(t)
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+
+	addTxn := func(l *Ledger, txn transactions.Transaction) {
+		txn.Header = transactions.Header{
+			Sender:      addrs[0],
+			Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+			FirstValid:  l.Latest() + 1,
+			LastValid:   l.Latest() + 10,
+			GenesisID:   t.Name(),
+			GenesisHash: genesisInitState.GenesisHash,
+		}
+		stx := sign(initSecrets, txn)
+		require.NoError(t, l.addBlockTxns(t, genesisInitState.Accounts, []transactions.SignedTxn{stx}, transactions.ApplyData{}))
+	}
+	addPayment := func(l *Ledger) {
+		addTxn(l, transactions.Transaction{
+			Type: protocol.PaymentTx,
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[1+int(l.Latest())%(len(addrs)-1)],
+				Amount:   basics.MicroAlgos{Raw: 1000 + uint64(l.Latest())},
+			},
+		})
+	}
+
+	// start with a ledger whose history is not kept
+	l, err := OpenLedger(log, dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	for l.Latest() < basics.Round(proto.MaxBalLookback)+20 {
+		addPayment(l)
+	}
+	l.WaitForCommit(l.Latest())
+	l.Close()
+
+	cfg.EnableAccountHistory = true
+	l, err = OpenLedger(log, dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	earliest := l.history.earliest
+	require.NotZero(t, earliest)
+	require.Equal(t, l.accts.dbRound, earliest)
+
+	// the history does not go further back than when it was enabled
+	_, err = l.Lookup(earliest-1, addrs[0])
+	require.IsType(t, &RoundOffsetError{}, err)
+
+	// create an asset, and destroy it a few rounds later
+	addTxn(l, transactions.Transaction{
+		Type: protocol.AssetConfigTx,
+		AssetConfigTxnFields: transactions.AssetConfigTxnFields{
+			AssetParams: basics.AssetParams{Total: 100, Manager: addrs[0]},
+		},
+	})
+	created := l.Latest()
+	record, err := l.Lookup(created, addrs[0])
+	require.NoError(t, err)
+	require.Len(t, record.AssetParams, 1)
+	var assetIdx basics.AssetIndex
+	for assetIdx = range record.AssetParams {
+	}
+	for i := 0; i < 3; i++ {
+		addPayment(l)
+	}
+	addTxn(l, transactions.Transaction{
+		Type:                 protocol.AssetConfigTx,
+		AssetConfigTxnFields: transactions.AssetConfigTxnFields{ConfigAsset: assetIdx},
+	})
+	destroyed := l.Latest()
+
+	// remember the state of every round while it is still available from the
+	// accounts tracker
+	expected := make(map[basics.Round]map[basics.Address]basics.AccountData)
+	remember := func(rnd basics.Round) {
+		expected[rnd] = make(map[basics.Address]basics.AccountData)
+		for _, addr := range addrs {
+			data, err := l.Lookup(rnd, addr)
+			require.NoError(t, err)
+			expected[rnd][addr] = data
+		}
+	}
+	for rnd := earliest; rnd <= l.Latest(); rnd++ {
+		remember(rnd)
+	}
+	// add rounds until the accounts tracker no longer has the rounds we compare
+	accountsRound := func() basics.Round {
+		l.accts.accountsMu.RLock()
+		defer l.accts.accountsMu.RUnlock()
+		return l.accts.dbRound
+	}
+	for accountsRound() <= destroyed {
+		addPayment(l)
+		remember(l.Latest())
+		l.WaitForCommit(l.Latest())
+	}
+	l.accts.accountsWriting.Wait()
+
+	check := func() {
+		for rnd, accounts := range expected {
+			for addr, data := range accounts {
+				historical, err := l.Lookup(rnd, addr)
+				require.NoError(t, err)
+				// empty maps are decoded as nil, so compare the encodings
+				require.Equal(t, protocol.Encode(&data), protocol.Encode(&historical), "round %d", rnd)
+			}
+
+			creator, ok, err := l.GetCreatorForRound(rnd, basics.CreatableIndex(assetIdx), basics.AssetCreatable)
+			require.NoError(t, err)
+			require.Equal(t, rnd >= created && rnd < destroyed, ok, "round %d", rnd)
+			if ok {
+				require.Equal(t, addrs[0], creator)
+			}
+		}
+	}
+	check()
+
+	// the history is kept across restarts
+	l.Close()
+	l, err = OpenLedger(log, dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+	require.Equal(t, earliest, l.history.earliest)
+	check()
+}
@@ -0,0 +1,228 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// accountStore is the storage of the account state of the ledger as of a single round: the data of every account,
+// the creators of the assets and applications, the boxes and the account totals. The account updates tracker reads
+// and commits the accounts through it, so that they can be kept either in the SQLite tracker database or in a
+// key-value store.
+//
+// The merkle trie of the accounts and the catchpoints are only maintained on top of the SQLite tracker database, so
+// the other backends can't be used by a node that tracks catchpoints or that runs a catchpoint catchup.
+type accountStore interface {
+	// initialize creates the storage if needed. If it's new, it writes initAccounts to it, along with their totals,
+	// at round 0 and returns true.
+	initialize(initAccounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) (newDatabase bool, err error)
+	// reset deletes all the accounts, so that the storage has to be initialized again
+	reset() error
+
+	// round returns the round the storage is at
+	round() (basics.Round, error)
+	totals() (ledgercore.AccountTotals, error)
+
+	// lookup returns the data of the given account, which is empty if it doesn't exist, along with the round the
+	// storage is at.
+	lookup(addr basics.Address) (persistedAccountData, error)
+	lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, basics.Round, error)
+	// listCreatables returns up to maxResults creatables of the given type with an index of at most maxIdx, in
+	// descending index order.
+	listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) ([]basics.CreatableLocator, basics.Round, error)
+	lookupBox(key string) (string, bool, basics.Round, error)
+	// listBoxes returns up to maxResults keys of the boxes of the given application, in ascending order.
+	listBoxes(appIdx basics.AppIndex, maxResults uint64) ([]string, basics.Round, error)
+	// onlineTop returns the online accounts from position offset to offset+n-1, when sorted by their normalized
+	// online balance and address, both descending, along with the round the storage is at.
+	onlineTop(offset, n uint64, proto config.ConsensusParams) (map[basics.Address]*onlineAccount, basics.Round, error)
+	// iterateAccounts calls visit for every account, until it returns an error
+	iterateAccounts(visit func(addr basics.Address, data basics.AccountData) error) error
+
+	// commit runs fn with a writer whose changes are committed atomically if fn returns nil, and discarded otherwise
+	commit(fn func(ctx context.Context, w accountStoreWriter) error) error
+
+	setSynchronousMode(ctx context.Context, synchronousMode db.SynchronousMode) error
+	close()
+}
+
+// accountStoreWriter writes the changes of a range of rounds to an accountStore, as done by commitRound.
+type accountStoreWriter interface {
+	// accountsLoadOld loads the stored data of the accounts of updates that aren't in the base accounts cache
+	accountsLoadOld(updates *compactAccountDeltas) error
+	totalsNewRounds(updates []ledgercore.AccountDeltas, compactUpdates compactAccountDeltas, accountTotals []ledgercore.AccountTotals, proto config.ConsensusParams) error
+	// accountsNewRound writes the new data of the accounts and the changes of their resources, as set by
+	// compactAccountDeltas.makeResourceDeltas, and returns the updated accounts for the base accounts cache.
+	accountsNewRound(updates compactAccountDeltas, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, proto config.ConsensusParams, lastUpdateRound basics.Round) ([]persistedAccountData, error)
+	boxesNewRound(boxes map[string]ledgercore.ModifiedBox) error
+	// updateAccountsRound moves the storage to round rnd, which can't be before its current round. hashRound is the
+	// round of the merkle trie, which is ignored by the storages that don't have one.
+	updateAccountsRound(rnd basics.Round, hashRound basics.Round) error
+}
+
+// openAccountStore opens the account storage of the given backend. The SQLite one is kept in the tracker database,
+// while the others use a filename based on dbPathPrefix.
+func openAccountStore(backend string, dbPathPrefix string, dbMem bool, trackerDBs db.Pair) (accountStore, error) {
+	switch backend {
+	case StorageBackendSQLite, "":
+		return &sqliteAccountStore{dbs: trackerDBs}, nil
+	case StorageBackendKV:
+		return openKVAccountStore(dbPathPrefix+".tracker.kv", dbMem)
+	default:
+		return nil, fmt.Errorf("unknown account storage backend '%s'", backend)
+	}
+}
+
+// sqliteAccountStore is the accountStore of the SQLite tracker database, implemented in accountdb.go. The database
+// itself is owned by the ledger, which also upgrades its schema in accountUpdates.accountsInitialize.
+type sqliteAccountStore struct {
+	dbs db.Pair
+	qs  *accountsDbQueries
+}
+
+func (as *sqliteAccountStore) initialize(initAccounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) (newDatabase bool, err error) {
+	err = as.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		newDatabase, err0 = accountsInit(tx, initAccounts, proto)
+		if err0 != nil {
+			return err0
+		}
+		return accountsAddNormalizedBalance(tx, proto)
+	})
+	if err != nil {
+		return
+	}
+	if as.qs != nil {
+		as.qs.close()
+	}
+	as.qs, err = accountsDbInit(as.dbs.Rdb.Handle, as.dbs.Wdb.Handle)
+	return
+}
+
+func (as *sqliteAccountStore) reset() error {
+	return as.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return accountsReset(tx)
+	})
+}
+
+func (as *sqliteAccountStore) round() (rnd basics.Round, err error) {
+	err = as.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		rnd, _, err0 = accountsRound(tx)
+		return err0
+	})
+	return
+}
+
+func (as *sqliteAccountStore) totals() (totals ledgercore.AccountTotals, err error) {
+	err = as.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		totals, err0 = accountsTotals(tx, false)
+		return err0
+	})
+	return
+}
+
+func (as *sqliteAccountStore) lookup(addr basics.Address) (persistedAccountData, error) {
+	return as.qs.lookup(addr)
+}
+
+func (as *sqliteAccountStore) lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, basics.Round, error) {
+	return as.qs.lookupCreator(cidx, ctype)
+}
+
+func (as *sqliteAccountStore) listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) ([]basics.CreatableLocator, basics.Round, error) {
+	return as.qs.listCreatables(maxIdx, maxResults, ctype)
+}
+
+func (as *sqliteAccountStore) lookupBox(key string) (string, bool, basics.Round, error) {
+	return as.qs.lookupBox(key)
+}
+
+func (as *sqliteAccountStore) listBoxes(appIdx basics.AppIndex, maxResults uint64) ([]string, basics.Round, error) {
+	return as.qs.listBoxes(appIdx, maxResults)
+}
+
+func (as *sqliteAccountStore) onlineTop(offset, n uint64, proto config.ConsensusParams) (accts map[basics.Address]*onlineAccount, rnd basics.Round, err error) {
+	err = as.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		accts, err = accountsOnlineTop(tx, offset, n, proto)
+		if err != nil {
+			return
+		}
+		rnd, _, err = accountsRound(tx)
+		return
+	})
+	return
+}
+
+func (as *sqliteAccountStore) iterateAccounts(visit func(addr basics.Address, data basics.AccountData) error) error {
+	return as.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return makeAccountIterator(ctx, tx)(visit)
+	})
+}
+
+func (as *sqliteAccountStore) commit(fn func(ctx context.Context, w accountStoreWriter) error) error {
+	return as.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return fn(ctx, &sqliteAccountStoreWriter{tx: tx})
+	})
+}
+
+func (as *sqliteAccountStore) setSynchronousMode(ctx context.Context, synchronousMode db.SynchronousMode) error {
+	return as.dbs.Wdb.SetSynchronousMode(ctx, synchronousMode, synchronousMode >= db.SynchronousModeFull)
+}
+
+// close releases the prepared queries; the tracker database is closed by the ledger.
+func (as *sqliteAccountStore) close() {
+	if as.qs != nil {
+		as.qs.close()
+		as.qs = nil
+	}
+}
+
+// sqliteAccountStoreWriter is the accountStoreWriter of a SQLite tracker database transaction. The merkle trie is
+// updated by commitRound within the same transaction.
+type sqliteAccountStoreWriter struct {
+	tx *sql.Tx
+}
+
+func (w *sqliteAccountStoreWriter) accountsLoadOld(updates *compactAccountDeltas) error {
+	return updates.accountsLoadOld(w.tx)
+}
+
+func (w *sqliteAccountStoreWriter) totalsNewRounds(updates []ledgercore.AccountDeltas, compactUpdates compactAccountDeltas, accountTotals []ledgercore.AccountTotals, proto config.ConsensusParams) error {
+	return totalsNewRounds(w.tx, updates, compactUpdates, accountTotals, proto)
+}
+
+func (w *sqliteAccountStoreWriter) accountsNewRound(updates compactAccountDeltas, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, proto config.ConsensusParams, lastUpdateRound basics.Round) ([]persistedAccountData, error) {
+	return accountsNewRound(w.tx, updates, creatables, proto, lastUpdateRound)
+}
+
+func (w *sqliteAccountStoreWriter) boxesNewRound(boxes map[string]ledgercore.ModifiedBox) error {
+	return boxesNewRound(w.tx, boxes)
+}
+
+func (w *sqliteAccountStoreWriter) updateAccountsRound(rnd basics.Round, hashRound basics.Round) error {
+	return updateAccountsRound(w.tx, rnd, hashRound)
+}
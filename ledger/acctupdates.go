@@ -17,16 +17,20 @@
 package ledger
 
 import (
+	"bytes"
 	"container/heap"
 	"context"
 	"database/sql"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -66,6 +70,10 @@ const (
 // value was calibrated using BenchmarkCalibrateCacheNodeSize
 var trieCachedNodesCount = 9000
 
+// trieBackgroundRebuildBatchSize defines the number of accounts ( or boxes ) that are added to the balances trie
+// in a single transaction while the trie is being rebuilt in the background.
+var trieBackgroundRebuildBatchSize = 4096
+
 // merkleCommitterNodesPerPage controls how many nodes will be stored in a single page
 // value was calibrated using BenchmarkCalibrateNodesPerPage
 var merkleCommitterNodesPerPage = int64(116)
@@ -152,7 +160,12 @@ type accountUpdates struct {
 	// Connection to the database.
 	dbs db.Pair
 
-	// Prepared SQL statements for fast accounts DB lookups.
+	// store is the storage of the accounts. Unless it's the SQLite tracker database, the tracker has no merkle trie
+	// and doesn't generate catchpoints.
+	store accountStore
+
+	// Prepared SQL statements for the catchpoints state, and for fast accounts DB lookups when the accounts are
+	// stored in the tracker database. It is nil otherwise.
 	accountsq *accountsDbQueries
 
 	// dbRound is always exactly accountsRound(),
@@ -173,6 +186,13 @@ type accountUpdates struct {
 	// appears in creatableDeltas
 	creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable
 
+	// boxDeltas stores application box updates for every round after dbRound.
+	boxDeltas []map[string]ledgercore.ModifiedBox
+
+	// boxes stores the most recent state for every box that appears
+	// in boxDeltas
+	boxes map[string]ledgercore.ModifiedBox
+
 	// versions stores consensus version dbRound and every
 	// round after it; i.e., versions is one longer than deltas.
 	versions []protocol.ConsensusVersion
@@ -200,6 +220,15 @@ type accountUpdates struct {
 	// written to the database.
 	balancesTrie *merkletrie.Trie
 
+	// resourceHashes is set when the asset holdings and application local states are hashed into the balancesTrie
+	// as entries of their own, as the consensus protocol of the dbRound requires. Otherwise, they're hashed as part
+	// of the account data.
+	resourceHashes bool
+
+	// trieRebuild is set while the balancesTrie is being rebuilt in the background, after the consensus protocol
+	// changed the way the accounts are hashed. It's only modified by the commitSyncer go-routine, while holding accountsMu.
+	trieRebuild *trieRebuildState
+
 	// The last catchpoint label that was written to the database. Should always align with what's in the database.
 	// note that this is the last catchpoint *label* and not the catchpoint file.
 	lastCatchpointLabel string
@@ -260,6 +289,28 @@ type accountUpdates struct {
 	lastMetricsLogTime time.Time
 }
 
+// trieRebuildState tracks the progress of a background rebuild of the balances trie. The accounts are added to the
+// trie in the order of their addresses, followed by the boxes in the order of their keys. The trie contains the
+// hashes of the accounts up to ( and including ) accountsCursor, and of the boxes up to boxesCursor; the changes
+// made to these are applied to the trie as the rounds are committed, while the remaining ones would get their
+// hashes added by the following batches.
+type trieRebuildState struct {
+	accountsCursor []byte
+	accountsDone   bool
+	boxesCursor    []byte
+	boxesDone      bool
+}
+
+// accountHashed returns true if the hashes of the given account are already part of the trie being rebuilt.
+func (s *trieRebuildState) accountHashed(addr basics.Address) bool {
+	return s.accountsDone || (s.accountsCursor != nil && bytes.Compare(addr[:], s.accountsCursor) <= 0)
+}
+
+// boxHashed returns true if the hash of the given box is already part of the trie being rebuilt.
+func (s *trieRebuildState) boxHashed(key string) bool {
+	return s.boxesDone || (s.boxesCursor != nil && bytes.Compare([]byte(key), s.boxesCursor) <= 0)
+}
+
 type deferredCommit struct {
 	offset   uint64
 	dbRound  basics.Round
@@ -354,9 +405,11 @@ func (au *accountUpdates) loadFromDisk(l ledgerForTracker) error {
 
 	var writingCatchpointDigest crypto.Digest
 
-	writingCatchpointRound, _, err = au.accountsq.readCatchpointStateUint64(context.Background(), catchpointStateWritingCatchpoint)
-	if err != nil {
-		return err
+	if au.accountsq != nil {
+		writingCatchpointRound, _, err = au.accountsq.readCatchpointStateUint64(context.Background(), catchpointStateWritingCatchpoint)
+		if err != nil {
+			return err
+		}
 	}
 
 	writingCatchpointDigest, err = au.initializeCaches(lastBalancesRound, lastestBlockRound, basics.Round(writingCatchpointRound))
@@ -474,7 +527,7 @@ func (au *accountUpdates) listCreatables(maxCreatableIdx basics.CreatableIndex,
 		// Fetch up to maxResults - len(res) + len(deletedCreatables) from the database,
 		// so we have enough extras in case creatables were deleted
 		numToFetch := maxResults - uint64(len(res)) + uint64(len(deletedCreatables))
-		dbResults, dbRound, err := au.accountsq.listCreatables(maxCreatableIdx, numToFetch, ctype)
+		dbResults, dbRound, err := au.store.listCreatables(maxCreatableIdx, numToFetch, ctype)
 		if err != nil {
 			return nil, err
 		}
@@ -508,6 +561,71 @@ func (au *accountUpdates) listCreatables(maxCreatableIdx basics.CreatableIndex,
 	}
 }
 
+// ListBoxes lists the names of the boxes of the given application, in ascending order, limiting to the first
+// maxResults. A zero maxResults lists all of the application boxes.
+func (au *accountUpdates) ListBoxes(appIdx basics.AppIndex, maxResults uint64) ([]string, error) {
+	prefix := ledgercore.BoxKeyAppPrefix(appIdx)
+	au.accountsMu.RLock()
+	for {
+		currentDbRound := au.dbRound
+		currentDeltaLen := len(au.deltas)
+
+		// Collect the boxes of this application that have been modified, but
+		// not yet synced to disk.
+		unsyncedBoxes := make(map[string]bool)
+		for key, mbox := range au.boxes {
+			if strings.HasPrefix(key, prefix) {
+				unsyncedBoxes[key] = mbox.Value != nil
+			}
+		}
+
+		au.accountsMu.RUnlock()
+
+		// Fetch enough names from the database to make up for the in-memory
+		// boxes that might shadow them.
+		numToFetch := uint64(math.MaxInt64)
+		if maxResults > 0 && maxResults < numToFetch-uint64(len(unsyncedBoxes)) {
+			numToFetch = maxResults + uint64(len(unsyncedBoxes))
+		}
+		dbKeys, dbRound, err := au.store.listBoxes(appIdx, numToFetch)
+		if err != nil {
+			return nil, err
+		}
+
+		if dbRound == currentDbRound {
+			keys := make([]string, 0, len(dbKeys)+len(unsyncedBoxes))
+			for key, exists := range unsyncedBoxes {
+				if exists {
+					keys = append(keys, key)
+				}
+			}
+			for _, key := range dbKeys {
+				if _, has := unsyncedBoxes[key]; !has {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			if maxResults > 0 && uint64(len(keys)) > maxResults {
+				keys = keys[:maxResults]
+			}
+
+			names := make([]string, len(keys))
+			for i, key := range keys {
+				names[i] = key[len(prefix):]
+			}
+			return names, nil
+		}
+		if dbRound < currentDbRound {
+			au.log.Errorf("ListBoxes: database round %d is behind in-memory round %d", dbRound, currentDbRound)
+			return nil, &StaleDatabaseRoundError{databaseRound: dbRound, memoryRound: currentDbRound}
+		}
+		au.accountsMu.RLock()
+		for currentDbRound >= au.dbRound && currentDeltaLen == len(au.deltas) {
+			au.accountsReadCond.Wait()
+		}
+	}
+}
+
 // onlineTop returns the top n online accounts, sorted by their normalized
 // balance and address, whose voting keys are valid in voteRnd.  See the
 // normalization description in AccountData.NormalizedOnlineBalance().
@@ -566,14 +684,7 @@ func (au *accountUpdates) onlineTop(rnd basics.Round, voteRnd basics.Round, n ui
 			var accts map[basics.Address]*onlineAccount
 			start := time.Now()
 			ledgerAccountsonlinetopCount.Inc(nil)
-			err = au.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-				accts, err = accountsOnlineTop(tx, batchOffset, batchSize, proto)
-				if err != nil {
-					return
-				}
-				dbRound, _, err = accountsRound(tx)
-				return
-			})
+			accts, dbRound, err = au.store.onlineTop(batchOffset, batchSize, proto)
 			ledgerAccountsonlinetopMicros.AddMicrosecondsSince(start, nil)
 			if err != nil {
 				return nil, err
@@ -649,6 +760,11 @@ func (au *accountUpdates) GetCreatorForRound(rnd basics.Round, cidx basics.Creat
 	return au.getCreatorForRound(rnd, cidx, ctype, true /* take the lock */)
 }
 
+// LookupBox returns the content of the box stored under the given key at a given round
+func (au *accountUpdates) LookupBox(rnd basics.Round, key string) (value string, ok bool, err error) {
+	return au.lookupBox(rnd, key, true /* take the lock */)
+}
+
 // committedUpTo enqueues committing the balances for round committedRound-lookback.
 // The deferred committing is done so that we could calculate the historical balances lookback rounds back.
 // Since we don't want to hold off the tracker's mutex for too long, we'll defer the database persistence of this
@@ -815,6 +931,15 @@ func (r *readCloseSizer) Size() (int64, error) {
 	return r.size, nil
 }
 
+// Seek sets the offset of the next Read on the associated stream, if the stream supports seeking.
+func (r *readCloseSizer) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := r.ReadCloser.(io.Seeker)
+	if !ok {
+		return 0, fmt.Errorf("stream doesn't support seeking")
+	}
+	return seeker.Seek(offset, whence)
+}
+
 // GetCatchpointStream returns a ReadCloseSizer to the catchpoint file associated with the provided round
 func (au *accountUpdates) GetCatchpointStream(round basics.Round) (ReadCloseSizer, error) {
 	dbFileName := ""
@@ -873,6 +998,28 @@ func (au *accountUpdates) GetCatchpointStream(round basics.Round) (ReadCloseSize
 	return nil, ledgercore.ErrNoEntry{}
 }
 
+// getCatchpointFile returns the path and the size of the catchpoint file associated with the provided round
+func (au *accountUpdates) getCatchpointFile(round basics.Round) (catchpointPath string, fileSize int64, err error) {
+	cs, err := au.GetCatchpointStream(round)
+	if err != nil {
+		return "", 0, err
+	}
+	// the stream isn't needed; opening it makes sure the file exists and is registered in the database.
+	cs.Close()
+	var dbFileName string
+	err = au.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		dbFileName, _, fileSize, err = getCatchpoint(tx, round)
+		return
+	})
+	if err == sql.ErrNoRows || (err == nil && dbFileName == "") {
+		return "", 0, ledgercore.ErrNoEntry{}
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("accountUpdates: getCatchpointFile: unable to lookup catchpoint %d: %v", round, err)
+	}
+	return filepath.Join(au.dbDirectory, dbFileName), fileSize, nil
+}
+
 // functions below this line are all internal functions
 
 // accountUpdatesLedgerEvaluator is a "ledger emulator" which is used *only* by initializeCaches, as a way to shortcut
@@ -930,6 +1077,11 @@ func (aul *accountUpdatesLedgerEvaluator) GetCreatorForRound(rnd basics.Round, c
 	return aul.au.getCreatorForRound(rnd, cidx, ctype, false /* don't sync */)
 }
 
+// LookupBox returns the content of the box stored under the given key at a given round
+func (aul *accountUpdatesLedgerEvaluator) LookupBox(rnd basics.Round, key string) (value string, ok bool, err error) {
+	return aul.au.lookupBox(rnd, key, false /* don't sync */)
+}
+
 // totalsImpl returns the totals for a given round
 func (au *accountUpdates) totalsImpl(rnd basics.Round) (totals ledgercore.AccountTotals, err error) {
 	offset, err := au.roundOffset(rnd)
@@ -1011,7 +1163,7 @@ func (au *accountUpdates) initializeCaches(lastBalancesRound, lastestBlockRound,
 	defer func() {
 		if rollbackSynchronousMode {
 			// restore default synchronous mode
-			au.dbs.Wdb.SetSynchronousMode(context.Background(), au.synchronousMode, au.synchronousMode >= db.SynchronousModeFull)
+			au.store.setSynchronousMode(context.Background(), au.synchronousMode)
 		}
 	}()
 
@@ -1039,7 +1191,7 @@ func (au *accountUpdates) initializeCaches(lastBalancesRound, lastestBlockRound,
 
 			if !rollbackSynchronousMode {
 				// switch to rebuild synchronous mode to improve performance
-				au.dbs.Wdb.SetSynchronousMode(context.Background(), au.accountsRebuildSynchronousMode, au.accountsRebuildSynchronousMode >= db.SynchronousModeFull)
+				au.store.setSynchronousMode(context.Background(), au.accountsRebuildSynchronousMode)
 
 				// flip the switch to rollback the synchronous mode once we're done.
 				rollbackSynchronousMode = true
@@ -1106,6 +1258,7 @@ func (au *accountUpdates) initializeCaches(lastBalancesRound, lastestBlockRound,
 // and preparing the accountUpdates for operation, including initializing the commitSyncer goroutine.
 func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRound, lastestBlockRound basics.Round, err error) {
 	au.dbs = l.trackerDB()
+	au.store = l.accountStorage()
 	au.log = l.trackerLog()
 	au.ledger = l
 
@@ -1115,6 +1268,49 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 	}
 
 	lastestBlockRound = l.Latest()
+	if sqliteAccounts, ok := au.store.(*sqliteAccountStore); ok {
+		err = au.initializeTrackerDB(sqliteAccounts, lastestBlockRound)
+	} else {
+		err = au.initializeStore(lastestBlockRound)
+	}
+	if err != nil {
+		return
+	}
+
+	hdr, err := l.BlockHdr(au.dbRound)
+	if err != nil {
+		return
+	}
+
+	au.versions = []protocol.ConsensusVersion{hdr.CurrentProtocol}
+	au.deltas = nil
+	au.creatableDeltas = nil
+	au.accounts = make(map[basics.Address]modifiedAccount)
+	au.creatables = make(map[basics.CreatableIndex]ledgercore.ModifiedCreatable)
+	au.boxDeltas = nil
+	au.boxes = make(map[string]ledgercore.ModifiedBox)
+	au.deltasAccum = []int{0}
+	au.roundDigest = nil
+
+	au.catchpointWriting = 0
+	// an incomplete trie rebuild was reset by accountsInitialize, which rebuilt the whole trie.
+	au.trieRebuild = nil
+	// keep these channel closed if we're not generating catchpoint
+	au.catchpointSlowWriting = make(chan struct{}, 1)
+	close(au.catchpointSlowWriting)
+	au.ctx, au.ctxCancel = context.WithCancel(context.Background())
+	au.committedOffset = make(chan deferredCommit, 1)
+	au.commitSyncerClosed = make(chan struct{})
+	go au.commitSyncer(au.committedOffset)
+
+	lastBalancesRound = au.dbRound
+	au.baseAccounts.init(au.log, baseAccountsPendingAccountsBufferSize, baseAccountsPendingAccountsWarnThreshold)
+	return
+}
+
+// initializeTrackerDB upgrades the schema of the tracker database, which holds the accounts, and loads the dbRound,
+// the totals and the catchpoints state from it.
+func (au *accountUpdates) initializeTrackerDB(store *sqliteAccountStore, lastestBlockRound basics.Round) (err error) {
 	start := time.Now()
 	ledgerAccountsinitCount.Inc(nil)
 	err = au.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
@@ -1156,36 +1352,59 @@ func (au *accountUpdates) initializeFromDisk(l ledgerForTracker) (lastBalancesRo
 		return
 	}
 
-	au.accountsq, err = accountsDbInit(au.dbs.Rdb.Handle, au.dbs.Wdb.Handle)
-	au.lastCatchpointLabel, _, err = au.accountsq.readCatchpointStateString(context.Background(), catchpointStateLastCatchpoint)
+	// the store prepares the queries of the accounts, which are shared with the catchpoint code.
+	_, err = store.initialize(au.initAccounts, au.initProto)
 	if err != nil {
 		return
 	}
+	au.accountsq = store.qs
+	au.lastCatchpointLabel, _, err = au.accountsq.readCatchpointStateString(context.Background(), catchpointStateLastCatchpoint)
+	return
 
-	hdr, err := l.BlockHdr(au.dbRound)
+}
+
+// initializeStore loads the dbRound and the totals from an account storage other than the tracker database. Such a
+// storage has no merkle trie, so the tracker can't generate catchpoints.
+func (au *accountUpdates) initializeStore(lastestBlockRound basics.Round) (err error) {
+	if au.catchpointInterval != 0 {
+		return fmt.Errorf("accountUpdates.initializeStore: catchpoints can only be tracked when the accounts are stored in the tracker database")
+	}
+	au.accountsq = nil
+	au.lastCatchpointLabel = ""
+
+	start := time.Now()
+	ledgerAccountsinitCount.Inc(nil)
+	defer ledgerAccountsinitMicros.AddMicrosecondsSince(start, nil)
+	_, err = au.store.initialize(au.initAccounts, au.initProto)
 	if err != nil {
 		return
 	}
+	au.dbRound, err = au.store.round()
+	if err != nil {
+		return
+	}
+	// Check for blocks DB and accounts storage un-sync
+	if au.dbRound > lastestBlockRound {
+		au.log.Warnf("accountUpdates.initializeStore: resetting accounts storage (on round %v, but blocks DB's latest is %v)", au.dbRound, lastestBlockRound)
+		err = au.store.reset()
+		if err != nil {
+			return
+		}
+		_, err = au.store.initialize(au.initAccounts, au.initProto)
+		if err != nil {
+			return
+		}
+		au.dbRound, err = au.store.round()
+		if err != nil {
+			return
+		}
+	}
 
-	au.versions = []protocol.ConsensusVersion{hdr.CurrentProtocol}
-	au.deltas = nil
-	au.creatableDeltas = nil
-	au.accounts = make(map[basics.Address]modifiedAccount)
-	au.creatables = make(map[basics.CreatableIndex]ledgercore.ModifiedCreatable)
-	au.deltasAccum = []int{0}
-	au.roundDigest = nil
-
-	au.catchpointWriting = 0
-	// keep these channel closed if we're not generating catchpoint
-	au.catchpointSlowWriting = make(chan struct{}, 1)
-	close(au.catchpointSlowWriting)
-	au.ctx, au.ctxCancel = context.WithCancel(context.Background())
-	au.committedOffset = make(chan deferredCommit, 1)
-	au.commitSyncerClosed = make(chan struct{})
-	go au.commitSyncer(au.committedOffset)
-
-	lastBalancesRound = au.dbRound
-	au.baseAccounts.init(au.log, baseAccountsPendingAccountsBufferSize, baseAccountsPendingAccountsWarnThreshold)
+	totals, err := au.store.totals()
+	if err != nil {
+		return
+	}
+	au.roundTotals = []ledgercore.AccountTotals{totals}
 	return
 }
 
@@ -1203,6 +1422,42 @@ func accountHashBuilder(addr basics.Address, accountData basics.AccountData, enc
 	return hash[:]
 }
 
+// boxHashBuilder calculates the hash key used for the trie by combining the box key and its value. The key is
+// prefixed with its length, so that the same bytes split differently between the key and the value hash differently.
+func boxHashBuilder(key string, value string) []byte {
+	hash := make([]byte, 4+crypto.DigestSize)
+	// write out the lowest 32 bits of the application index, so that the boxes of a single application
+	// are kept close to each other in the trie.
+	if appIdx, _, err := ledgercore.SplitBoxKey(key); err == nil {
+		binary.BigEndian.PutUint32(hash[:4], uint32(appIdx))
+	}
+	entry := make([]byte, 8, 8+len(key)+len(value))
+	binary.BigEndian.PutUint64(entry, uint64(len(key)))
+	entry = append(entry, key...)
+	entry = append(entry, value...)
+	entryHash := crypto.Hash(entry)
+	copy(hash[4:], entryHash[:])
+	return hash[:]
+}
+
+// resourceHashBuilder calculates the hash key used for the trie by combining the account address with the index, type
+// and encoded data of one of its asset holdings or application local states
+func resourceHashBuilder(addr basics.Address, aidx basics.CreatableIndex, rtype basics.CreatableType, encodedResource []byte) []byte {
+	hash := make([]byte, 4+crypto.DigestSize)
+	// write out the lowest 32 bits of the creatable index, so that the holdings of a single asset and the local
+	// states of a single application are kept close to each other in the trie.
+	binary.BigEndian.PutUint32(hash[:4], uint32(aidx))
+	entry := make([]byte, 0, len(addr)+9+len(encodedResource))
+	entry = append(entry, addr[:]...)
+	entry = append(entry, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(entry[len(addr):], uint64(aidx))
+	entry = append(entry, byte(rtype))
+	entry = append(entry, encodedResource...)
+	entryHash := crypto.Hash(entry)
+	copy(hash[4:], entryHash[:])
+	return hash[:]
+}
+
 // accountsInitialize initializes the accounts DB if needed and return current account round.
 // as part of the initialization, it tests the current database schema version, and perform upgrade
 // procedures to bring it up to the database schema supported by the binary.
@@ -1258,6 +1513,18 @@ func (au *accountUpdates) accountsInitialize(ctx context.Context, tx *sql.Tx) (b
 					au.log.Warnf("accountsInitialize failed to upgrade accounts database (ledger.tracker.sqlite) from schema 4 : %v", err)
 					return 0, err
 				}
+			case 5:
+				dbVersion, err = au.upgradeDatabaseSchema5(ctx, tx, newDatabase)
+				if err != nil {
+					au.log.Warnf("accountsInitialize failed to upgrade accounts database (ledger.tracker.sqlite) from schema 5 : %v", err)
+					return 0, err
+				}
+			case 6:
+				dbVersion, err = au.upgradeDatabaseSchema6(ctx, tx, newDatabase)
+				if err != nil {
+					au.log.Warnf("accountsInitialize failed to upgrade accounts database (ledger.tracker.sqlite) from schema 6 : %v", err)
+					return 0, err
+				}
 			default:
 				return 0, fmt.Errorf("accountsInitialize unable to upgrade database from schema version %d", dbVersion)
 			}
@@ -1271,9 +1538,19 @@ func (au *accountUpdates) accountsInitialize(ctx context.Context, tx *sql.Tx) (b
 		return 0, err
 	}
 
+	// the way the accounts are hashed into the merkle trie depends on the consensus protocol of the accounts round.
+	// when the accounts are ahead of the blocks, there is no such protocol yet, but the caller is going to reset them.
+	if rnd <= au.ledger.Latest() {
+		hdr, err := au.ledger.BlockHdr(rnd)
+		if err != nil {
+			return 0, fmt.Errorf("accountsInitialize was unable to read the block header of round %d: %v", rnd, err)
+		}
+		au.resourceHashes = config.Consensus[hdr.CurrentProtocol].EnableResourceHashes
+	}
+
 	if hashRound != rnd {
 		// if the hashed round is different then the base round, something was modified, and the accounts aren't in sync
-		// with the hashes.
+		// with the hashes. That is also the case when the trie was being rebuilt in the background.
 		err = resetAccountHashes(tx)
 		if err != nil {
 			return 0, err
@@ -1304,78 +1581,101 @@ func (au *accountUpdates) accountsInitialize(ctx context.Context, tx *sql.Tx) (b
 
 	if rootHash.IsZero() {
 		au.log.Infof("accountsInitialize rebuilding merkle trie for round %d", rnd)
-		accountBuilderIt := makeOrderedAccountsIter(tx, trieRebuildAccountChunkSize)
-		defer accountBuilderIt.Close(ctx)
-		startTrieBuildTime := time.Now()
-		accountsCount := 0
-		lastRebuildTime := startTrieBuildTime
-		pendingAccounts := 0
-		totalOrderedAccounts := 0
-		for {
-			accts, processedRows, err := accountBuilderIt.Next(ctx)
-			if err == sql.ErrNoRows {
-				// the account builder would return sql.ErrNoRows when no more data is available.
-				break
-			} else if err != nil {
-				return rnd, err
-			}
+		err = au.rebuildMerkleTrie(ctx, tx, trie, au.resourceHashes)
+		if err != nil {
+			return 0, err
+		}
 
-			if len(accts) > 0 {
-				accountsCount += len(accts)
-				pendingAccounts += len(accts)
-				for _, acct := range accts {
-					added, err := trie.Add(acct.digest)
-					if err != nil {
-						return rnd, fmt.Errorf("accountsInitialize was unable to add changes to trie: %v", err)
-					}
-					if !added {
-						au.log.Warnf("accountsInitialize attempted to add duplicate hash '%s' to merkle trie for account %v", hex.EncodeToString(acct.digest), acct.address)
-					}
-				}
+		// we've just updated the merkle trie, update the hashRound to reflect that.
+		err = updateAccountsRound(tx, rnd, rnd)
+		if err != nil {
+			return 0, fmt.Errorf("accountsInitialize was unable to update the account round to %d: %v", rnd, err)
+		}
+	}
+	au.balancesTrie = trie
+	return rnd, nil
+}
 
-				if pendingAccounts >= trieRebuildCommitFrequency {
-					// this trie Evict will commit using the current transaction.
-					// if anything goes wrong, it will still get rolled back.
-					_, err = trie.Evict(true)
-					if err != nil {
-						return 0, fmt.Errorf("accountsInitialize was unable to commit changes to trie: %v", err)
-					}
-					pendingAccounts = 0
-				}
+// rebuildMerkleTrie adds the hashes of all the accounts, boxes and (when resourceHashes is set) account resources
+// stored in the database to the given empty trie, committing it using the given transaction.
+func (au *accountUpdates) rebuildMerkleTrie(ctx context.Context, tx *sql.Tx, trie *merkletrie.Trie, resourceHashes bool) (err error) {
+	accountBuilderIt := makeOrderedAccountsIter(tx, trieRebuildAccountChunkSize, resourceHashes)
+	defer accountBuilderIt.Close(ctx)
+	startTrieBuildTime := time.Now()
+	accountsCount := 0
+	lastRebuildTime := startTrieBuildTime
+	pendingAccounts := 0
+	totalOrderedAccounts := 0
+	for {
+		accts, processedRows, err := accountBuilderIt.Next(ctx)
+		if err == sql.ErrNoRows {
+			// the account builder would return sql.ErrNoRows when no more data is available.
+			break
+		} else if err != nil {
+			return err
+		}
 
-				if time.Now().Sub(lastRebuildTime) > 5*time.Second {
-					// let the user know that the trie is still being rebuilt.
-					au.log.Infof("accountsInitialize still building the trie, and processed so far %d accounts", accountsCount)
-					lastRebuildTime = time.Now()
+		if len(accts) > 0 {
+			accountsCount += len(accts)
+			pendingAccounts += len(accts)
+			for _, acct := range accts {
+				added, err := trie.Add(acct.digest)
+				if err != nil {
+					return fmt.Errorf("rebuildMerkleTrie was unable to add changes to trie: %v", err)
 				}
-			} else if processedRows > 0 {
-				totalOrderedAccounts += processedRows
-				// if it's not ordered, we can ignore it for now; we'll just increase the counters and emit logs periodically.
-				if time.Now().Sub(lastRebuildTime) > 5*time.Second {
-					// let the user know that the trie is still being rebuilt.
-					au.log.Infof("accountsInitialize still building the trie, and hashed so far %d accounts", totalOrderedAccounts)
-					lastRebuildTime = time.Now()
+				if !added {
+					au.log.Warnf("rebuildMerkleTrie attempted to add duplicate hash '%s' to merkle trie for account %v", hex.EncodeToString(acct.digest), acct.address)
 				}
 			}
-		}
 
-		// this trie Evict will commit using the current transaction.
-		// if anything goes wrong, it will still get rolled back.
-		_, err = trie.Evict(true)
-		if err != nil {
-			return 0, fmt.Errorf("accountsInitialize was unable to commit changes to trie: %v", err)
+			if pendingAccounts >= trieRebuildCommitFrequency {
+				// this trie Evict will commit using the current transaction.
+				// if anything goes wrong, it will still get rolled back.
+				_, err = trie.Evict(true)
+				if err != nil {
+					return fmt.Errorf("rebuildMerkleTrie was unable to commit changes to trie: %v", err)
+				}
+				pendingAccounts = 0
+			}
+
+			if time.Now().Sub(lastRebuildTime) > 5*time.Second {
+				// let the user know that the trie is still being rebuilt.
+				au.log.Infof("rebuildMerkleTrie still building the trie, and processed so far %d accounts", accountsCount)
+				lastRebuildTime = time.Now()
+			}
+		} else if processedRows > 0 {
+			totalOrderedAccounts += processedRows
+			// if it's not ordered, we can ignore it for now; we'll just increase the counters and emit logs periodically.
+			if time.Now().Sub(lastRebuildTime) > 5*time.Second {
+				// let the user know that the trie is still being rebuilt.
+				au.log.Infof("rebuildMerkleTrie still building the trie, and hashed so far %d accounts", totalOrderedAccounts)
+				lastRebuildTime = time.Now()
+			}
 		}
+	}
 
-		// we've just updated the merkle trie, update the hashRound to reflect that.
-		err = updateAccountsRound(tx, rnd, rnd)
+	boxesCount, err := addBoxesToTrie(ctx, tx, trie)
+	if err != nil {
+		return fmt.Errorf("rebuildMerkleTrie was unable to add boxes to trie: %v", err)
+	}
+
+	resourcesCount := 0
+	if resourceHashes {
+		resourcesCount, err = addResourcesToTrie(ctx, tx, trie)
 		if err != nil {
-			return 0, fmt.Errorf("accountsInitialize was unable to update the account round to %d: %v", rnd, err)
+			return fmt.Errorf("rebuildMerkleTrie was unable to add resources to trie: %v", err)
 		}
+	}
 
-		au.log.Infof("accountsInitialize rebuilt the merkle trie with %d entries in %v", accountsCount, time.Now().Sub(startTrieBuildTime))
+	// this trie Evict will commit using the current transaction.
+	// if anything goes wrong, it will still get rolled back.
+	_, err = trie.Evict(true)
+	if err != nil {
+		return fmt.Errorf("rebuildMerkleTrie was unable to commit changes to trie: %v", err)
 	}
-	au.balancesTrie = trie
-	return rnd, nil
+
+	au.log.Infof("rebuildMerkleTrie rebuilt the merkle trie with %d entries in %v", accountsCount+boxesCount+resourcesCount, time.Now().Sub(startTrieBuildTime))
+	return nil
 }
 
 // upgradeDatabaseSchema0 upgrades the database schema from version 0 to version 1
@@ -1451,6 +1751,16 @@ func (au *accountUpdates) upgradeDatabaseSchema1(ctx context.Context, tx *sql.Tx
 		}
 
 		au.log.Infof("accountsInitialize preparing queries")
+		// the prepared queries refer to the kvstore and resources tables, which are otherwise created only on schema
+		// versions 6 and 7
+		_, err = tx.ExecContext(ctx, boxesSchema)
+		if err != nil {
+			return 0, fmt.Errorf("accountsInitialize unable to create kvstore table : %v", err)
+		}
+		_, err = tx.ExecContext(ctx, resourcesSchema)
+		if err != nil {
+			return 0, fmt.Errorf("accountsInitialize unable to create resources table : %v", err)
+		}
 		// initialize a new accountsq with the incoming transaction.
 		accountsq, err := accountsDbInit(tx, tx)
 		if err != nil {
@@ -1584,6 +1894,61 @@ done:
 	return 5, nil
 }
 
+// upgradeDatabaseSchema5 upgrades the database schema from version 5 to version 6,
+// adding the kvstore table used for storing application boxes.
+func (au *accountUpdates) upgradeDatabaseSchema5(ctx context.Context, tx *sql.Tx, newDatabase bool) (updatedDBVersion int32, err error) {
+	_, err = tx.ExecContext(ctx, boxesSchema)
+	if err != nil {
+		return 0, fmt.Errorf("upgradeDatabaseSchema5 unable to create kvstore table: %v", err)
+	}
+
+	// update version
+	_, err = db.SetUserVersion(ctx, tx, 6)
+	if err != nil {
+		return 0, fmt.Errorf("accountsInitialize unable to update database schema version from 5 to 6: %v", err)
+	}
+	return 6, nil
+}
+
+// upgradeDatabaseSchema6 upgrades the database schema from version 6 to version 7,
+// adding the resources table and moving the asset holdings and application local states of
+// the accounts from accountbase into it. Since the consensus protocol might require these to be
+// hashed into the merkle trie apart from the accounts, the trie is reset and gets rebuilt once
+// the upgrade is complete.
+func (au *accountUpdates) upgradeDatabaseSchema6(ctx context.Context, tx *sql.Tx, newDatabase bool) (updatedDBVersion int32, err error) {
+	var modifiedAccounts uint
+	_, err = tx.ExecContext(ctx, resourcesSchema)
+	if err != nil {
+		return 0, fmt.Errorf("upgradeDatabaseSchema6 unable to create resources table: %v", err)
+	}
+
+	if newDatabase {
+		goto schemaUpdateComplete
+	}
+
+	au.log.Infof("upgradeDatabaseSchema6 moving account resources into the resources table")
+	modifiedAccounts, err = splitAccountsResources(ctx, tx)
+	if err != nil {
+		return 0, fmt.Errorf("upgradeDatabaseSchema6 unable to move account resources: %v", err)
+	}
+	au.log.Infof("upgradeDatabaseSchema6 moved the resources of %d accounts", modifiedAccounts)
+
+	if modifiedAccounts > 0 {
+		err = resetAccountHashes(tx)
+		if err != nil {
+			return 0, fmt.Errorf("upgradeDatabaseSchema6 unable to reset account hashes: %v", err)
+		}
+	}
+
+schemaUpdateComplete:
+	// update version
+	_, err = db.SetUserVersion(ctx, tx, 7)
+	if err != nil {
+		return 0, fmt.Errorf("accountsInitialize unable to update database schema version from 6 to 7: %v", err)
+	}
+	return 7, nil
+}
+
 // deleteStoredCatchpoints iterates over the storedcatchpoints table and deletes all the files stored on disk.
 // once all the files have been deleted, it would go ahead and remove the entries from the table.
 func (au *accountUpdates) deleteStoredCatchpoints(ctx context.Context, dbQueries *accountsDbQueries) (err error) {
@@ -1599,6 +1964,7 @@ func (au *accountUpdates) deleteStoredCatchpoints(ctx context.Context, dbQueries
 
 		for round, fileName := range fileNames {
 			absCatchpointFileName := filepath.Join(au.dbDirectory, fileName)
+			os.Remove(absCatchpointFileName + catchpointChunksFileSuffix)
 			err = os.Remove(absCatchpointFileName)
 			if err == nil || os.IsNotExist(err) {
 				// it's ok if the file doesn't exist. just remove it from the database and we'll be good to go.
@@ -1627,8 +1993,16 @@ func (au *accountUpdates) accountsUpdateBalances(accountsDeltas compactAccountDe
 
 	for i := 0; i < accountsDeltas.len(); i++ {
 		addr, delta := accountsDeltas.getByIdx(i)
+		if au.trieRebuild != nil && !au.trieRebuild.accountHashed(addr) {
+			// the account would get hashed once the trie rebuild reaches it.
+			continue
+		}
 		if !delta.old.accountData.IsZero() {
-			deleteHash := accountHashBuilder(addr, delta.old.accountData, protocol.Encode(&delta.old.accountData))
+			oldData := delta.old.accountData
+			if au.resourceHashes {
+				oldData = accountBaseData(oldData)
+			}
+			deleteHash := accountHashBuilder(addr, oldData, protocol.Encode(&oldData))
 			deleted, err = au.balancesTrie.Delete(deleteHash)
 			if err != nil {
 				return fmt.Errorf("failed to delete hash '%s' from merkle trie for account %v: %w", hex.EncodeToString(deleteHash), addr, err)
@@ -1641,7 +2015,11 @@ func (au *accountUpdates) accountsUpdateBalances(accountsDeltas compactAccountDe
 		}
 
 		if !delta.new.IsZero() {
-			addHash := accountHashBuilder(addr, delta.new, protocol.Encode(&delta.new))
+			newData := delta.new
+			if au.resourceHashes {
+				newData = accountBaseData(newData)
+			}
+			addHash := accountHashBuilder(addr, newData, protocol.Encode(&newData))
 			added, err = au.balancesTrie.Add(addHash)
 			if err != nil {
 				return fmt.Errorf("attempted to add duplicate hash '%s' to merkle trie for account %v: %w", hex.EncodeToString(addHash), addr, err)
@@ -1652,6 +2030,37 @@ func (au *accountUpdates) accountsUpdateBalances(accountsDeltas compactAccountDe
 				accumulatedChanges++
 			}
 		}
+
+		if !au.resourceHashes {
+			continue
+		}
+		// each of the asset holdings and application local states has its own entry in the trie.
+		for _, rdelta := range delta.resources {
+			if rdelta.old != nil {
+				deleteHash := resourceHashBuilder(addr, rdelta.aidx, rdelta.rtype, rdelta.old)
+				deleted, err = au.balancesTrie.Delete(deleteHash)
+				if err != nil {
+					return fmt.Errorf("failed to delete hash '%s' from merkle trie for resource %d of account %v: %w", hex.EncodeToString(deleteHash), rdelta.aidx, addr, err)
+				}
+				if !deleted {
+					au.log.Warnf("failed to delete hash '%s' from merkle trie for resource %d of account %v", hex.EncodeToString(deleteHash), rdelta.aidx, addr)
+				} else {
+					accumulatedChanges++
+				}
+			}
+			if rdelta.new != nil {
+				addHash := resourceHashBuilder(addr, rdelta.aidx, rdelta.rtype, rdelta.new)
+				added, err = au.balancesTrie.Add(addHash)
+				if err != nil {
+					return fmt.Errorf("attempted to add duplicate hash '%s' to merkle trie for resource %d of account %v: %w", hex.EncodeToString(addHash), rdelta.aidx, addr, err)
+				}
+				if !added {
+					au.log.Warnf("attempted to add duplicate hash '%s' to merkle trie for resource %d of account %v", hex.EncodeToString(addHash), rdelta.aidx, addr)
+				} else {
+					accumulatedChanges++
+				}
+			}
+		}
 	}
 	if accumulatedChanges >= trieAccumulatedChangesFlush {
 		accumulatedChanges = 0
@@ -1669,6 +2078,58 @@ func (au *accountUpdates) accountsUpdateBalances(accountsDeltas compactAccountDe
 	return
 }
 
+// boxesUpdateBalances applies the given box changes to the balances merkle trie. The previous
+// values of the modified boxes are read from the database using the given transaction, and
+// therefore this needs to be called before the box changes are written to the database.
+func (au *accountUpdates) boxesUpdateBalances(tx *sql.Tx, boxDeltas map[string]ledgercore.ModifiedBox) (err error) {
+	if au.catchpointInterval == 0 || len(boxDeltas) == 0 {
+		return nil
+	}
+	oldValues, err := boxesLoadOld(tx, boxDeltas)
+	if err != nil {
+		return err
+	}
+
+	var added, deleted bool
+	accumulatedChanges := 0
+	for key, bdelta := range boxDeltas {
+		if au.trieRebuild != nil && !au.trieRebuild.boxHashed(key) {
+			// the box would get hashed once the trie rebuild reaches it.
+			continue
+		}
+		if oldValue, has := oldValues[key]; has {
+			deleteHash := boxHashBuilder(key, oldValue)
+			deleted, err = au.balancesTrie.Delete(deleteHash)
+			if err != nil {
+				return fmt.Errorf("failed to delete hash '%s' from merkle trie for box %x: %w", hex.EncodeToString(deleteHash), key, err)
+			}
+			if !deleted {
+				au.log.Warnf("failed to delete hash '%s' from merkle trie for box %x", hex.EncodeToString(deleteHash), key)
+			} else {
+				accumulatedChanges++
+			}
+		}
+
+		if bdelta.Value != nil {
+			addHash := boxHashBuilder(key, *bdelta.Value)
+			added, err = au.balancesTrie.Add(addHash)
+			if err != nil {
+				return fmt.Errorf("attempted to add duplicate hash '%s' to merkle trie for box %x: %w", hex.EncodeToString(addHash), key, err)
+			}
+			if !added {
+				au.log.Warnf("attempted to add duplicate hash '%s' to merkle trie for box %x", hex.EncodeToString(addHash), key)
+			} else {
+				accumulatedChanges++
+			}
+		}
+	}
+
+	if accumulatedChanges > 0 {
+		_, err = au.balancesTrie.Commit()
+	}
+	return
+}
+
 // newBlockImpl is the accountUpdates implementation of the ledgerTracker interface. This is the "internal" facing function
 // which assumes that no lock need to be taken.
 func (au *accountUpdates) newBlockImpl(blk bookkeeping.Block, delta ledgercore.StateDelta) {
@@ -1686,6 +2147,7 @@ func (au *accountUpdates) newBlockImpl(blk bookkeeping.Block, delta ledgercore.S
 	au.deltas = append(au.deltas, delta.Accts)
 	au.versions = append(au.versions, blk.CurrentProtocol)
 	au.creatableDeltas = append(au.creatableDeltas, delta.Creatables)
+	au.boxDeltas = append(au.boxDeltas, delta.Boxes)
 	au.roundDigest = append(au.roundDigest, blk.Digest())
 	au.deltasAccum = append(au.deltasAccum, delta.Accts.Len()+au.deltasAccum[len(au.deltasAccum)-1])
 
@@ -1705,7 +2167,7 @@ func (au *accountUpdates) newBlockImpl(blk bookkeeping.Block, delta ledgercore.S
 			previousAccountData = baseAccountData.accountData
 		} else {
 			// it's missing from the base accounts, so we'll try to load it from disk.
-			if acctData, err := au.accountsq.lookup(addr); err != nil {
+			if acctData, err := au.store.lookup(addr); err != nil {
 				au.log.Panicf("accountUpdates: newBlockImpl failed to lookup account %v when processing round %d : %v", addr, rnd, err)
 			} else {
 				previousAccountData = acctData.accountData
@@ -1731,6 +2193,13 @@ func (au *accountUpdates) newBlockImpl(blk bookkeeping.Block, delta ledgercore.S
 		au.creatables[cidx] = mcreat
 	}
 
+	for key, bdelta := range delta.Boxes {
+		mbox := au.boxes[key]
+		mbox.Value = bdelta.Value
+		mbox.Ndeltas++
+		au.boxes[key] = mbox
+	}
+
 	if ot.Overflowed {
 		au.log.Panicf("accountUpdates: newBlockImpl %d overflowed totals", rnd)
 	}
@@ -1825,7 +2294,7 @@ func (au *accountUpdates) lookupWithRewards(rnd basics.Round, addr basics.Addres
 		// present in the on-disk DB.  As an optimization, we avoid creating
 		// a separate transaction here, and directly use a prepared SQL query
 		// against the database.
-		persistedData, err = au.accountsq.lookup(addr)
+		persistedData, err = au.store.lookup(addr)
 		if persistedData.round == currentDbRound {
 			au.baseAccounts.writePending(persistedData)
 			return persistedData.accountData, err
@@ -1911,7 +2380,7 @@ func (au *accountUpdates) lookupWithoutRewards(rnd basics.Round, addr basics.Add
 		// present in the on-disk DB.  As an optimization, we avoid creating
 		// a separate transaction here, and directly use a prepared SQL query
 		// against the database.
-		persistedData, err = au.accountsq.lookup(addr)
+		persistedData, err = au.store.lookup(addr)
 		if persistedData.round == currentDbRound {
 			au.baseAccounts.writePending(persistedData)
 			return persistedData.accountData, rnd, err
@@ -1985,7 +2454,7 @@ func (au *accountUpdates) getCreatorForRound(rnd basics.Round, cidx basics.Creat
 			unlock = false
 		}
 		// Check the database
-		creator, ok, dbRound, err = au.accountsq.lookupCreator(cidx, ctype)
+		creator, ok, dbRound, err = au.store.lookupCreator(cidx, ctype)
 
 		if dbRound == currentDbRound {
 			return
@@ -2007,6 +2476,76 @@ func (au *accountUpdates) getCreatorForRound(rnd basics.Round, cidx basics.Creat
 	}
 }
 
+// lookupBox returns the content of the box stored under the given key at a given round
+func (au *accountUpdates) lookupBox(rnd basics.Round, key string, synchronized bool) (value string, ok bool, err error) {
+	unlock := false
+	if synchronized {
+		au.accountsMu.RLock()
+		unlock = true
+	}
+	defer func() {
+		if unlock {
+			au.accountsMu.RUnlock()
+		}
+	}()
+	var dbRound basics.Round
+	var offset uint64
+	for {
+		currentDbRound := au.dbRound
+		currentDeltaLen := len(au.deltas)
+		offset, err = au.roundOffset(rnd)
+		if err != nil {
+			return "", false, err
+		}
+
+		// If this is the most recent round, au.boxes has the latest
+		// state and we can skip scanning backwards over boxDeltas
+		if offset == uint64(len(au.deltas)) {
+			if mbox, has := au.boxes[key]; has {
+				if mbox.Value == nil {
+					return "", false, nil
+				}
+				return *mbox.Value, true, nil
+			}
+		} else {
+			for offset > 0 {
+				offset--
+				if bdelta, has := au.boxDeltas[offset][key]; has {
+					if bdelta.Value == nil {
+						return "", false, nil
+					}
+					return *bdelta.Value, true, nil
+				}
+			}
+		}
+
+		if synchronized {
+			au.accountsMu.RUnlock()
+			unlock = false
+		}
+		// Check the database
+		value, ok, dbRound, err = au.store.lookupBox(key)
+
+		if dbRound == currentDbRound {
+			return
+		}
+		if synchronized {
+			if dbRound < currentDbRound {
+				au.log.Errorf("accountUpdates.lookupBox: database round %d is behind in-memory round %d", dbRound, currentDbRound)
+				return "", false, &StaleDatabaseRoundError{databaseRound: dbRound, memoryRound: currentDbRound}
+			}
+			au.accountsMu.RLock()
+			unlock = true
+			for currentDbRound >= au.dbRound && currentDeltaLen == len(au.deltas) {
+				au.accountsReadCond.Wait()
+			}
+		} else {
+			au.log.Errorf("accountUpdates.lookupBox: database round %d mismatching in-memory round %d", dbRound, currentDbRound)
+			return "", false, &MismatchingDatabaseRoundError{databaseRound: dbRound, memoryRound: currentDbRound}
+		}
+	}
+}
+
 // accountsCreateCatchpointLabel creates a catchpoint label and write it.
 func (au *accountUpdates) accountsCreateCatchpointLabel(committedRound basics.Round, totals ledgercore.AccountTotals, ledgerBlockDigest crypto.Digest, trieBalancesHash crypto.Digest) (label string, err error) {
 	cpLabel := ledgercore.MakeCatchpointLabel(committedRound, ledgerBlockDigest, trieBalancesHash, totals)
@@ -2038,13 +2577,31 @@ func (au *accountUpdates) roundOffset(rnd basics.Round) (offset uint64, err erro
 // send the tasks to commitRound for completing the operation.
 func (au *accountUpdates) commitSyncer(deferedCommits chan deferredCommit) {
 	defer close(au.commitSyncerClosed)
+	// rebuildingTrie is ready whenever the balances trie is being rebuilt, so that the rebuild batches
+	// get interleaved with the pending commits.
+	rebuildingTrie := make(chan struct{})
+	close(rebuildingTrie)
 	for {
+		var trieRebuildBatch <-chan struct{}
+		if au.trieRebuild != nil {
+			trieRebuildBatch = rebuildingTrie
+		}
 		select {
 		case committedOffset, ok := <-deferedCommits:
 			if !ok {
 				return
 			}
 			au.commitRound(committedOffset.offset, committedOffset.dbRound, committedOffset.lookback)
+		case <-trieRebuildBatch:
+			err := au.rebuildTrieBatch()
+			if err != nil {
+				au.log.Warnf("commitSyncer was unable to rebuild the merkle trie: %v", err)
+				// give it some time before retrying.
+				select {
+				case <-time.After(time.Second):
+				case <-au.ctx.Done():
+				}
+			}
 		case <-au.ctx.Done():
 			// drain the pending commits queue:
 			drained := false
@@ -2061,6 +2618,63 @@ func (au *accountUpdates) commitSyncer(deferedCommits chan deferredCommit) {
 	}
 }
 
+// rebuildTrieBatch adds the next batch of accounts ( or boxes, once all the accounts were added ) to the balances trie
+// being rebuilt in the background. Once the trie is complete, the hash round is updated to match the accounts round,
+// and the catchpoints could get generated again.
+func (au *accountUpdates) rebuildTrieBatch() error {
+	state := *au.trieRebuild
+	dbRound := au.dbRound
+	err := au.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		mc, err := MakeMerkleCommitter(tx, false)
+		if err != nil {
+			return err
+		}
+		if au.balancesTrie == nil {
+			au.balancesTrie, err = merkletrie.MakeTrie(mc, TrieMemoryConfig)
+			if err != nil {
+				return err
+			}
+		} else {
+			au.balancesTrie.SetCommitter(mc)
+		}
+
+		if !state.accountsDone {
+			state.accountsCursor, state.accountsDone, err = addAccountsBatchToTrie(ctx, tx, au.balancesTrie, state.accountsCursor, trieBackgroundRebuildBatchSize, au.resourceHashes)
+		} else {
+			state.boxesCursor, state.boxesDone, err = addBoxesBatchToTrie(ctx, tx, au.balancesTrie, state.boxesCursor, trieBackgroundRebuildBatchSize)
+		}
+		if err != nil {
+			return err
+		}
+
+		// this trie Evict will commit using the current transaction.
+		_, err = au.balancesTrie.Evict(true)
+		if err != nil {
+			return err
+		}
+
+		if state.boxesDone {
+			// the trie is complete, and matches the accounts of the current round.
+			err = updateAccountsRound(tx, dbRound, dbRound)
+		}
+		return err
+	})
+	if err != nil {
+		au.balancesTrie = nil
+		return err
+	}
+
+	au.accountsMu.Lock()
+	defer au.accountsMu.Unlock()
+	if state.boxesDone {
+		au.log.Infof("rebuildTrieBatch completed rebuilding the merkle trie for round %d", dbRound)
+		au.trieRebuild = nil
+	} else {
+		au.trieRebuild = &state
+	}
+	return nil
+}
+
 // commitRound write to the database a "chunk" of rounds, and update the dbRound accordingly.
 func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookback basics.Round) {
 	var stats telemetryspec.AccountsUpdateMetrics
@@ -2115,6 +2729,8 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 	roundTotals := make([]ledgercore.AccountTotals, offset+1, offset+1)
 	copy(deltas, au.deltas[:offset])
 	copy(creatableDeltas, au.creatableDeltas[:offset])
+	boxDeltas := make([]map[string]ledgercore.ModifiedBox, offset, offset)
+	copy(boxDeltas, au.boxDeltas[:offset])
 	copy(roundTotals, au.roundTotals[:offset+1])
 
 	// verify version correctness : all the entries in the au.versions[1:offset+1] should have the *same* version, and the committedUpTo should be enforcing that.
@@ -2124,6 +2740,11 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 		return
 	}
 	consensusVersion := au.versions[1]
+	// when the consensus protocol changes the way the accounts are hashed, the trie is reset and then rebuilt from
+	// scratch in the background, in between the following commits.
+	resourceHashes := config.Consensus[consensusVersion].EnableResourceHashes
+	rebuildTrie := au.catchpointInterval > 0 && resourceHashes != au.resourceHashes
+	trieRebuilding := rebuildTrie || au.trieRebuild != nil
 
 	var committedRoundDigest crypto.Digest
 
@@ -2135,6 +2756,7 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 	// being updated multiple times. When that happen, we can safely omit the intermediate updates.
 	compactDeltas := makeCompactAccountDeltas(deltas, au.baseAccounts)
 	compactCreatableDeltas := compactCreatableDeltas(creatableDeltas)
+	compactBoxDeltas := compactBoxDeltas(boxDeltas)
 
 	au.accountsMu.RUnlock()
 
@@ -2159,10 +2781,19 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 	if updateStats {
 		stats.DatabaseCommitDuration = time.Duration(time.Now().UnixNano())
 	}
-	err := au.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+	err := au.store.commit(func(ctx context.Context, w accountStoreWriter) (err error) {
 		treeTargetRound := basics.Round(0)
+		var mc *MerkleCommitter
+		// the merkle trie is kept in the tracker database, so catchpoints are only tracked when the accounts are
+		// stored there, and the trie is updated within the same transaction.
+		var tx *sql.Tx
+		if sqliteWriter, ok := w.(*sqliteAccountStoreWriter); ok {
+			tx = sqliteWriter.tx
+			db.ResetTransactionWarnDeadline(ctx, tx, time.Now().Add(accountsUpdatePerRoundHighWatermark*time.Duration(offset)))
+		}
 		if au.catchpointInterval > 0 {
-			mc, err0 := MakeMerkleCommitter(tx, false)
+			var err0 error
+			mc, err0 = MakeMerkleCommitter(tx, false)
 			if err0 != nil {
 				return err0
 			}
@@ -2176,25 +2807,26 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 			} else {
 				au.balancesTrie.SetCommitter(mc)
 			}
-			treeTargetRound = dbRound + basics.Round(offset)
+			if !trieRebuilding {
+				treeTargetRound = dbRound + basics.Round(offset)
+			}
 		}
 
-		db.ResetTransactionWarnDeadline(ctx, tx, time.Now().Add(accountsUpdatePerRoundHighWatermark*time.Duration(offset)))
-
 		if updateStats {
 			stats.OldAccountPreloadDuration = time.Duration(time.Now().UnixNano())
 		}
 
-		err = compactDeltas.accountsLoadOld(tx)
+		err = w.accountsLoadOld(&compactDeltas)
 		if err != nil {
 			return err
 		}
+		compactDeltas.makeResourceDeltas()
 
 		if updateStats {
 			stats.OldAccountPreloadDuration = time.Duration(time.Now().UnixNano()) - stats.OldAccountPreloadDuration
 		}
 
-		err = totalsNewRounds(tx, deltas[:offset], compactDeltas, roundTotals[1:offset+1], config.Consensus[consensusVersion])
+		err = w.totalsNewRounds(deltas[:offset], compactDeltas, roundTotals[1:offset+1], config.Consensus[consensusVersion])
 		if err != nil {
 			return err
 		}
@@ -2203,9 +2835,16 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 			stats.MerkleTrieUpdateDuration = time.Duration(time.Now().UnixNano())
 		}
 
-		err = au.accountsUpdateBalances(compactDeltas)
-		if err != nil {
-			return err
+		if !rebuildTrie {
+			err = au.accountsUpdateBalances(compactDeltas)
+			if err != nil {
+				return err
+			}
+
+			err = au.boxesUpdateBalances(tx, compactBoxDeltas)
+			if err != nil {
+				return err
+			}
 		}
 
 		if updateStats {
@@ -2216,7 +2855,12 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 
 		// the updates of the actual account data is done last since the accountsNewRound would modify the compactDeltas old values
 		// so that we can update the base account back.
-		updatedPersistedAccounts, err = accountsNewRound(tx, compactDeltas, compactCreatableDeltas, genesisProto, dbRound+basics.Round(offset))
+		updatedPersistedAccounts, err = w.accountsNewRound(compactDeltas, compactCreatableDeltas, genesisProto, dbRound+basics.Round(offset))
+		if err != nil {
+			return err
+		}
+
+		err = w.boxesNewRound(compactBoxDeltas)
 		if err != nil {
 			return err
 		}
@@ -2225,12 +2869,24 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 			stats.AccountsWritingDuration = time.Duration(time.Now().UnixNano()) - stats.AccountsWritingDuration
 		}
 
-		err = updateAccountsRound(tx, dbRound+basics.Round(offset), treeTargetRound)
+		if rebuildTrie {
+			au.log.Infof("commitRound resetting merkle trie for round %d, as protocol %s changes the hashing of the account resources", dbRound+basics.Round(offset), consensusVersion)
+			err = resetAccountHashes(tx)
+			if err != nil {
+				return err
+			}
+			au.balancesTrie, err = merkletrie.MakeTrie(mc, TrieMemoryConfig)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = w.updateAccountsRound(dbRound+basics.Round(offset), treeTargetRound)
 		if err != nil {
 			return err
 		}
 
-		if isCatchpointRound {
+		if isCatchpointRound && !trieRebuilding {
 			trieBalancesHash, err = au.balancesTrie.RootHash()
 			if err != nil {
 				return
@@ -2244,12 +2900,20 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 		au.log.Warnf("unable to advance account snapshot (%d-%d): %v", dbRound, dbRound+basics.Round(offset), err)
 		return
 	}
+	au.resourceHashes = resourceHashes
+	if rebuildTrie {
+		au.accountsMu.Lock()
+		au.trieRebuild = &trieRebuildState{}
+		au.accountsMu.Unlock()
+	}
 
 	if updateStats {
 		stats.DatabaseCommitDuration = time.Duration(time.Now().UnixNano()) - stats.DatabaseCommitDuration - stats.AccountsWritingDuration - stats.MerkleTrieUpdateDuration - stats.OldAccountPreloadDuration
 	}
 
-	if isCatchpointRound {
+	if isCatchpointRound && trieRebuilding {
+		au.log.Infof("commitRound : skipping the catchpoint label of round %d, as the merkle trie is being rebuilt", dbRound+basics.Round(offset)+lookback)
+	} else if isCatchpointRound {
 		catchpointLabel, err = au.accountsCreateCatchpointLabel(dbRound+basics.Round(offset)+lookback, roundTotals[offset], committedRoundDigest, trieBalancesHash)
 		if err != nil {
 			au.log.Warnf("commitRound : unable to create a catchpoint label: %v", err)
@@ -2312,12 +2976,30 @@ func (au *accountUpdates) commitRound(offset uint64, dbRound basics.Round, lookb
 		}
 	}
 
+	for key, modBox := range compactBoxDeltas {
+		cnt := modBox.Ndeltas
+		mbox, ok := au.boxes[key]
+		if !ok {
+			au.log.Panicf("inconsistency: flushed %d changes to box %x, but not in au.boxes", cnt, key)
+		}
+
+		if cnt > mbox.Ndeltas {
+			au.log.Panicf("inconsistency: flushed %d changes to box %x, but au.boxes had %d", cnt, key, mbox.Ndeltas)
+		} else if cnt == mbox.Ndeltas {
+			delete(au.boxes, key)
+		} else {
+			mbox.Ndeltas -= cnt
+			au.boxes[key] = mbox
+		}
+	}
+
 	au.deltas = au.deltas[offset:]
 	au.deltasAccum = au.deltasAccum[offset:]
 	au.roundDigest = au.roundDigest[offset:]
 	au.versions = au.versions[offset:]
 	au.roundTotals = au.roundTotals[offset:]
 	au.creatableDeltas = au.creatableDeltas[offset:]
+	au.boxDeltas = au.boxDeltas[offset:]
 	au.dbRound = newBase
 	au.lastFlushTime = flushTime
 
@@ -2380,6 +3062,26 @@ func compactCreatableDeltas(creatableDeltas []map[basics.CreatableIndex]ledgerco
 	return
 }
 
+// compactBoxDeltas takes an array of box map deltas ( one array entry per round ), and compact the array into a single
+// map that contains the latest value of every modified box. It counts the number of changes per round by specifying it
+// in the Ndeltas field of the ModifiedBox.
+func compactBoxDeltas(boxDeltas []map[string]ledgercore.ModifiedBox) (outBoxDeltas map[string]ledgercore.ModifiedBox) {
+	if len(boxDeltas) == 0 {
+		return
+	}
+	outBoxDeltas = make(map[string]ledgercore.ModifiedBox, 1+len(boxDeltas[0])*len(boxDeltas))
+	for _, roundBoxes := range boxDeltas {
+		for key, bdelta := range roundBoxes {
+			prev := outBoxDeltas[key]
+			outBoxDeltas[key] = ledgercore.ModifiedBox{
+				Value:   bdelta.Value,
+				Ndeltas: prev.Ndeltas + 1,
+			}
+		}
+	}
+	return
+}
+
 // latest returns the latest round
 func (au *accountUpdates) latest() basics.Round {
 	return au.dbRound + basics.Round(len(au.deltas))
@@ -2430,7 +3132,7 @@ func (au *accountUpdates) generateCatchpoint(committedRound basics.Round, label
 	start := time.Now()
 	ledgerGeneratecatchpointCount.Inc(nil)
 	err = au.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		catchpointWriter = makeCatchpointWriter(au.ctx, absCatchpointFileName, tx, committedRound, committedRoundDigest, label)
+		catchpointWriter = makeCatchpointWriter(au.ctx, absCatchpointFileName, tx, committedRound, committedRoundDigest, label, au.resourceHashes)
 		for more {
 			stepCtx, stepCancelFunction := context.WithTimeout(au.ctx, chunkExecutionDuration)
 			writeStepStartTime := time.Now()
@@ -2484,6 +3186,13 @@ func (au *accountUpdates) generateCatchpoint(committedRound basics.Round, label
 		return
 	}
 
+	if au.catchpointFileHistoryLength != 0 {
+		// hash the file for the nodes downloading it in chunks now, rather than when a node first asks for it.
+		_, err = writeCatchpointDownloadChunks(absCatchpointFileName)
+		if err != nil {
+			au.log.Warnf("accountUpdates: generateCatchpoint: unable to write the download chunks of the catchpoint: %v", err)
+		}
+	}
 	err = au.saveCatchpointFile(committedRound, relCatchpointFileName, catchpointWriter.GetSize(), catchpointWriter.GetCatchpoint())
 	if err != nil {
 		au.log.Warnf("accountUpdates: generateCatchpoint: unable to save catchpoint: %v", err)
@@ -2543,6 +3252,7 @@ func (au *accountUpdates) saveCatchpointFile(round basics.Round, fileName string
 	}
 	for round, fileToDelete := range filesToDelete {
 		absCatchpointFileName := filepath.Join(au.dbDirectory, fileToDelete)
+		os.Remove(absCatchpointFileName + catchpointChunksFileSuffix)
 		err = os.Remove(absCatchpointFileName)
 		if err == nil || os.IsNotExist(err) {
 			// it's ok if the file doesn't exist. just remove it from the database and we'll be good to go.
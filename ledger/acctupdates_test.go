@@ -35,6 +35,7 @@ import (
 
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/ledger/ledgercore"
@@ -45,6 +46,7 @@ import (
 
 type mockLedgerForTracker struct {
 	dbs             db.Pair
+	accounts        accountStore
 	blocks          []blockEntry
 	deltas          []ledgercore.StateDelta
 	log             logging.Logger
@@ -158,8 +160,15 @@ func (ml *mockLedgerForTracker) trackerDB() db.Pair {
 	return ml.dbs
 }
 
-func (ml *mockLedgerForTracker) blockDB() db.Pair {
-	return db.Pair{}
+func (ml *mockLedgerForTracker) blockStorage() blockStore {
+	return nil
+}
+
+func (ml *mockLedgerForTracker) accountStorage() accountStore {
+	if ml.accounts != nil {
+		return ml.accounts
+	}
+	return &sqliteAccountStore{dbs: ml.dbs}
 }
 
 func (ml *mockLedgerForTracker) trackerLog() logging.Logger {
@@ -190,10 +199,10 @@ func (au *accountUpdates) allBalances(rnd basics.Round) (bals map[basics.Address
 		return
 	}
 
-	err = au.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		var err0 error
-		bals, err0 = accountsAll(tx)
-		return err0
+	bals = make(map[basics.Address]basics.AccountData)
+	err = au.store.iterateAccounts(func(addr basics.Address, data basics.AccountData) error {
+		bals[addr] = data
+		return nil
 	})
 	if err != nil {
 		return
@@ -312,10 +321,24 @@ func TestAcctUpdates(t *testing.T) {
 	if runtime.GOARCH == "arm" || runtime.GOARCH == "arm64" {
 		t.Skip("This test is too slow on ARM and causes travis builds to time out")
 	}
+	for _, backend := range []string{StorageBackendSQLite, StorageBackendKV} {
+		t.Run(backend, func(t *testing.T) {
+			testAcctUpdates(t, backend)
+		})
+	}
+}
+
+func testAcctUpdates(t *testing.T, backend string) {
 	proto := config.Consensus[protocol.ConsensusCurrentVersion]
 
 	ml := makeMockLedgerForTracker(t, true, 10, protocol.ConsensusCurrentVersion)
 	defer ml.Close()
+	if backend != StorageBackendSQLite {
+		var err error
+		ml.accounts, err = openAccountStore(backend, "", true, ml.dbs)
+		require.NoError(t, err)
+		defer ml.accounts.close()
+	}
 
 	accts := []map[basics.Address]basics.AccountData{randomAccounts(20, true)}
 	rewardsLevels := []uint64{0}
@@ -1042,9 +1065,9 @@ func TestListCreatables(t *testing.T) {
 	err = accountsAddNormalizedBalance(tx, proto)
 	require.NoError(t, err)
 
-	au := &accountUpdates{}
-	au.accountsq, err = accountsDbInit(tx, tx)
+	qs, err := accountsDbInit(tx, tx)
 	require.NoError(t, err)
+	au := &accountUpdates{store: &sqliteAccountStore{dbs: dbs, qs: qs}}
 
 	// ******* All results are obtained from the cache. Empty database *******
 	// ******* No deletes                                              *******
@@ -1180,6 +1203,12 @@ func accountsAll(tx *sql.Tx) (bals map[basics.Address]basics.AccountData, err er
 	}
 	defer rows.Close()
 
+	selectResourcesStmt, err := tx.Prepare("SELECT aidx, rtype, data FROM resources WHERE address=?")
+	if err != nil {
+		return
+	}
+	defer selectResourcesStmt.Close()
+
 	bals = make(map[basics.Address]basics.AccountData)
 	for rows.Next() {
 		var addrbuf []byte
@@ -1202,6 +1231,10 @@ func accountsAll(tx *sql.Tx) (bals map[basics.Address]basics.AccountData, err er
 		}
 
 		copy(addr[:], addrbuf)
+		err = loadAccountResources(selectResourcesStmt, addr, &data)
+		if err != nil {
+			return
+		}
 		bals[addr] = data
 	}
 
@@ -1893,6 +1926,138 @@ func TestSplittingConsensusVersionCommitsBoundry(t *testing.T) {
 	require.Equal(t, basics.Round(initialRounds+2*extraRounds), au.dbRound)
 }
 
+// TestResourceHashesTrieRebuild tests that the balances trie gets rebuilt when the consensus protocol starts
+// hashing the account resources apart from the accounts, and that it keeps matching a freshly built trie.
+func TestResourceHashesTrieRebuild(t *testing.T) {
+	oldProtocolVersion := protocol.ConsensusVersion("test-protocol-TestResourceHashesTrieRebuild-old")
+	newProtocolVersion := protocol.ConsensusVersion("test-protocol-TestResourceHashesTrieRebuild-new")
+	oldProtoParams := config.Consensus[protocol.ConsensusCurrentVersion]
+	oldProtoParams.MaxBalLookback = 8
+	oldProtoParams.EnableResourceHashes = false
+	newProtoParams := oldProtoParams
+	newProtoParams.EnableResourceHashes = true
+	config.Consensus[oldProtocolVersion] = oldProtoParams
+	config.Consensus[newProtocolVersion] = newProtoParams
+	defer func() {
+		delete(config.Consensus, oldProtocolVersion)
+		delete(config.Consensus, newProtocolVersion)
+	}()
+
+	ml := makeMockLedgerForTracker(t, true, 1, oldProtocolVersion)
+	ml.log.SetLevel(logging.Warn)
+	defer ml.Close()
+
+	accts := []map[basics.Address]basics.AccountData{randomAccounts(20, false)}
+	pooldata := basics.AccountData{}
+	pooldata.MicroAlgos.Raw = 1000 * 1000 * 1000 * 1000
+	pooldata.Status = basics.NotParticipating
+	accts[0][testPoolAddr] = pooldata
+
+	conf := config.GetDefaultLocal()
+	conf.CatchpointInterval = 1000
+	conf.CatchpointTracking = 1
+	au := &accountUpdates{}
+	au.initialize(conf, ".", oldProtoParams, accts[0])
+	err := au.loadFromDisk(ml)
+	require.NoError(t, err)
+	defer au.close()
+	require.False(t, au.resourceHashes)
+
+	var lastCreatableID uint64
+	addBlocks := func(first, last basics.Round, version protocol.ConsensusVersion) {
+		for i := first; i <= last; i++ {
+			var updates ledgercore.AccountDeltas
+			var totals map[basics.Address]basics.AccountData
+			updates, totals, lastCreatableID = randomDeltasBalancedFull(2, accts[i-1], 0, lastCreatableID)
+			blk := bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{Round: i}}
+			blk.CurrentProtocol = version
+			delta := ledgercore.MakeStateDelta(&blk.BlockHeader, 0, updates.Len(), 0)
+			delta.Accts.MergeAccounts(updates)
+			ml.addMockBlock(blockEntry{block: blk}, delta)
+			au.newBlock(blk, delta)
+			accts = append(accts, totals)
+		}
+	}
+
+	// the trie root matches the one of a trie rebuilt from the accounts database with the given hashing.
+	rebuiltRoot := func(resourceHashes bool) crypto.Digest {
+		tx, err := au.dbs.Wdb.Handle.Begin()
+		require.NoError(t, err)
+		defer tx.Rollback()
+		require.NoError(t, resetAccountHashes(tx))
+		mc, err := MakeMerkleCommitter(tx, false)
+		require.NoError(t, err)
+		trie, err := merkletrie.MakeTrie(mc, TrieMemoryConfig)
+		require.NoError(t, err)
+		require.NoError(t, au.rebuildMerkleTrie(context.Background(), tx, trie, resourceHashes))
+		root, err := trie.RootHash()
+		require.NoError(t, err)
+		return root
+	}
+	trieRoot := func() crypto.Digest {
+		root, err := au.balancesTrie.RootHash()
+		require.NoError(t, err)
+		return root
+	}
+	commit := func(rnd basics.Round) {
+		// clear the timer to ensure a flush
+		au.lastFlushTime = time.Time{}
+		au.committedUpTo(rnd)
+		au.waitAccountsWriting()
+	}
+	trieRebuilt := func() bool {
+		au.accountsMu.RLock()
+		defer au.accountsMu.RUnlock()
+		return au.trieRebuild == nil
+	}
+	hashRound := func() basics.Round {
+		var hashRound basics.Round
+		err := au.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+			_, hashRound, err = accountsRound(tx)
+			return
+		})
+		require.NoError(t, err)
+		return hashRound
+	}
+
+	// rebuild the trie a few accounts at a time, so that rounds get committed while it's being rebuilt.
+	defer func(batchSize int) {
+		trieBackgroundRebuildBatchSize = batchSize
+	}(trieBackgroundRebuildBatchSize)
+	trieBackgroundRebuildBatchSize = 2
+
+	addBlocks(1, 10, oldProtocolVersion)
+	addBlocks(11, 30, newProtocolVersion)
+
+	// the first commit stops at the protocol upgrade, and keeps hashing the resources with the accounts.
+	commit(30)
+	require.Equal(t, basics.Round(10), au.dbRound)
+	require.False(t, au.resourceHashes)
+	require.Equal(t, rebuiltRoot(false), trieRoot())
+	require.NotEqual(t, rebuiltRoot(true), trieRoot())
+
+	// the next one resets the trie, which then gets rebuilt in the background with the resources hashed apart,
+	// while the following rounds are being committed.
+	commit(30)
+	require.Equal(t, basics.Round(30-oldProtoParams.MaxBalLookback), au.dbRound)
+	require.True(t, au.resourceHashes)
+	for rnd := basics.Round(31); rnd <= 40; rnd++ {
+		addBlocks(rnd, rnd, newProtocolVersion)
+		commit(rnd)
+	}
+	require.Equal(t, basics.Round(40-oldProtoParams.MaxBalLookback), au.dbRound)
+	require.Eventually(t, trieRebuilt, 30*time.Second, 10*time.Millisecond)
+	require.Equal(t, au.dbRound, hashRound())
+	require.Equal(t, rebuiltRoot(true), trieRoot())
+
+	// and the following ones update it in place.
+	addBlocks(41, 50, newProtocolVersion)
+	commit(50)
+	require.Equal(t, basics.Round(50-oldProtoParams.MaxBalLookback), au.dbRound)
+	require.Equal(t, au.dbRound, hashRound())
+	require.Equal(t, rebuiltRoot(true), trieRoot())
+}
+
 // TestConsecutiveVersion tests the consecutiveVersion method correctness.
 func TestConsecutiveVersion(t *testing.T) {
 	var au accountUpdates
@@ -1920,3 +2085,13 @@ func TestConsecutiveVersion(t *testing.T) {
 		protocol.ConsensusV21,
 	}
 }
+
+func TestBoxHashBuilder(t *testing.T) {
+	key := ledgercore.MakeBoxKey(1, "ab")
+	shorterKey := ledgercore.MakeBoxKey(1, "a")
+
+	// the same bytes split differently between the key and the value hash differently
+	require.NotEqual(t, boxHashBuilder(key, "c"), boxHashBuilder(shorterKey, "bc"))
+	require.Equal(t, boxHashBuilder(key, "c"), boxHashBuilder(key, "c"))
+	require.NotEqual(t, boxHashBuilder(key, "c"), boxHashBuilder(key, "d"))
+}
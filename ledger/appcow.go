@@ -445,47 +445,64 @@ func MakeDebugBalances(l ledgerForCowBase, round basics.Round, proto protocol.Co
 
 // StatefulEval runs application.
 // Execution happens in a child cow and all modifications are merged into parent if the program passes
-func (cb *roundCowState) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (pass bool, evalDelta basics.EvalDelta, err error) {
+func (cb *roundCowState) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (pass bool, evalDelta transactions.EvalDelta, err error) {
 	// Make a child cow to eval our program in
 	calf := cb.child(1)
 	params.Ledger, err = newLogicLedger(calf, aidx)
 	if err != nil {
-		return false, basics.EvalDelta{}, err
+		return false, transactions.EvalDelta{}, err
 	}
 
 	// Eval the program
 	pass, err = logic.EvalStateful(program, params)
 	if err != nil {
-		return false, basics.EvalDelta{}, ledgercore.LogicEvalError{Err: err}
+		return false, transactions.EvalDelta{}, ledgercore.LogicEvalError{Err: err}
 	}
 
 	// If program passed, build our eval delta, and commit to state changes
 	if pass {
 		evalDelta, err = calf.BuildEvalDelta(aidx, &params.Txn.Txn)
 		if err != nil {
-			return false, basics.EvalDelta{}, err
+			return false, transactions.EvalDelta{}, err
 		}
+		evalDelta.InnerTxns = params.PastSideEffects[params.GroupIndex].InnerTxns()
+		evalDelta.Logs = params.PastSideEffects[params.GroupIndex].Logs()
+		evalDelta.ReturnData = params.PastSideEffects[params.GroupIndex].ReturnData()
 		calf.commitToParent()
 	}
 
 	return pass, evalDelta, nil
 }
 
-// BuildEvalDelta converts internal sdeltas into basics.EvalDelta
-func (cb *roundCowState) BuildEvalDelta(aidx basics.AppIndex, txn *transactions.Transaction) (evalDelta basics.EvalDelta, err error) {
+// markInnerApp records that aidx was called by an inner transaction, so that
+// its storage deltas are not attributed to the calling application
+func (cb *roundCowState) markInnerApp(aidx basics.AppIndex) {
+	if cb.innerApps == nil {
+		cb.innerApps = make(map[basics.AppIndex]bool)
+	}
+	cb.innerApps[aidx] = true
+}
+
+// BuildEvalDelta converts internal sdeltas into transactions.EvalDelta
+func (cb *roundCowState) BuildEvalDelta(aidx basics.AppIndex, txn *transactions.Transaction) (evalDelta transactions.EvalDelta, err error) {
 	foundGlobal := false
 	for addr, smod := range cb.sdeltas {
 		for aapp, sdelta := range smod {
+			// Deltas of applications called by inner transactions are
+			// reported in the EvalDelta of those transactions
+			if cb.innerApps[aapp.aidx] {
+				continue
+			}
 			// Check that all of these deltas are for the correct app
 			if aapp.aidx != aidx {
 				err = fmt.Errorf("found storage delta for different app during StatefulEval/BuildDelta: %d != %d", aapp.aidx, aidx)
-				return basics.EvalDelta{}, err
+				return transactions.EvalDelta{}, err
 			}
 			if aapp.global {
 				// Check that there is at most one global delta
 				if foundGlobal {
 					err = fmt.Errorf("found more than one global delta during StatefulEval/BuildDelta: %d", aapp.aidx)
-					return basics.EvalDelta{}, err
+					return transactions.EvalDelta{}, err
 				}
 				evalDelta.GlobalDelta = sdelta.kvCow.serialize()
 				foundGlobal = true
@@ -505,7 +522,7 @@ func (cb *roundCowState) BuildEvalDelta(aidx basics.AppIndex, txn *transactions.
 				} else {
 					addrOffset, err = txn.IndexByAddress(addr, txn.Sender)
 					if err != nil {
-						return basics.EvalDelta{}, err
+						return transactions.EvalDelta{}, err
 					}
 				}
 
@@ -61,6 +61,10 @@ func (ml *emptyLedger) getCreator(cidx basics.CreatableIndex, ctype basics.Creat
 	return basics.Address{}, false, nil
 }
 
+func (ml *emptyLedger) getBox(key string) (string, bool, error) {
+	return "", false, nil
+}
+
 func (ml *emptyLedger) getStorageCounts(addr basics.Address, aidx basics.AppIndex, global bool) (basics.StateSchema, error) {
 	return basics.StateSchema{}, nil
 }
@@ -382,7 +386,7 @@ func TestCowBuildDelta(t *testing.T) {
 	cow.sdeltas[creator][storagePtr{aidx, true}] = &storageDelta{}
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
-	a.Equal(basics.EvalDelta{GlobalDelta: basics.StateDelta{}}, ed)
+	a.Equal(transactions.EvalDelta{GlobalDelta: basics.StateDelta{}}, ed)
 
 	cow.sdeltas[creator][storagePtr{aidx + 1, true}] = &storageDelta{}
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
@@ -415,7 +419,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta{},
 			LocalDeltas: map[uint64]basics.StateDelta{0: {}},
 		},
@@ -428,7 +432,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta{},
 			LocalDeltas: map[uint64]basics.StateDelta{},
 		},
@@ -451,7 +455,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta(nil),
 			LocalDeltas: map[uint64]basics.StateDelta{
 				0: {
@@ -491,7 +495,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta(nil),
 			LocalDeltas: map[uint64]basics.StateDelta{
 				1: {
@@ -525,7 +529,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta(nil),
 			LocalDeltas: map[uint64]basics.StateDelta{
 				0: {
@@ -556,7 +560,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta(nil),
 			LocalDeltas: map[uint64]basics.StateDelta{
 				1: {
@@ -584,7 +588,7 @@ func TestCowBuildDelta(t *testing.T) {
 	ed, err = cow.BuildEvalDelta(aidx, &txn)
 	a.NoError(err)
 	a.Equal(
-		basics.EvalDelta{
+		transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta(nil),
 			LocalDeltas: map[uint64]basics.StateDelta{
 				0: {
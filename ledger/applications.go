@@ -22,6 +22,9 @@ import (
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/ledger/apply"
+	"github.com/algorand/go-algorand/protocol"
 )
 
 type logicLedger struct {
@@ -31,18 +34,24 @@ type logicLedger struct {
 }
 
 type cowForLogicLedger interface {
-	Get(addr basics.Address, withPendingRewards bool) (basics.AccountData, error)
+	// apply.Balances lets inner transactions be applied to the cow
+	apply.Balances
+
 	GetCreatableID(groupIdx int) basics.CreatableIndex
-	GetCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (basics.Address, bool, error)
 	GetKey(addr basics.Address, aidx basics.AppIndex, global bool, key string, accountIdx uint64) (basics.TealValue, bool, error)
-	BuildEvalDelta(aidx basics.AppIndex, txn *transactions.Transaction) (basics.EvalDelta, error)
+	BuildEvalDelta(aidx basics.AppIndex, txn *transactions.Transaction) (transactions.EvalDelta, error)
 
 	SetKey(addr basics.Address, aidx basics.AppIndex, global bool, key string, value basics.TealValue, accountIdx uint64) error
 	DelKey(addr basics.Address, aidx basics.AppIndex, global bool, key string, accountIdx uint64) error
 
+	GetBox(aidx basics.AppIndex, name string) (string, bool, error)
+	SetBox(aidx basics.AppIndex, name string, value string) error
+	DelBox(aidx basics.AppIndex, name string) (bool, error)
+
 	round() basics.Round
 	prevTimestamp() int64
 	allocated(addr basics.Address, aidx basics.AppIndex, global bool) (bool, error)
+	markInnerApp(aidx basics.AppIndex)
 }
 
 func newLogicLedger(cow cowForLogicLedger, aidx basics.AppIndex) (*logicLedger, error) {
@@ -134,6 +143,29 @@ func (al *logicLedger) AssetParams(assetIdx basics.AssetIndex) (basics.AssetPara
 	return params, nil
 }
 
+func (al *logicLedger) AppParams(appIdx basics.AppIndex) (basics.AppParams, basics.Address, error) {
+	// Find app creator
+	creator, err := al.fetchAppCreator(appIdx)
+	if err != nil {
+		return basics.AppParams{}, basics.Address{}, err
+	}
+
+	// Fetch the requested balance record
+	record, err := al.cow.Get(creator, false)
+	if err != nil {
+		return basics.AppParams{}, basics.Address{}, err
+	}
+
+	// Ensure account created the requested app
+	params, ok := record.AppParams[appIdx]
+	if !ok {
+		err = fmt.Errorf("account %s has not created app %d", creator, appIdx)
+		return basics.AppParams{}, basics.Address{}, err
+	}
+
+	return params, creator, nil
+}
+
 func (al *logicLedger) Round() basics.Round {
 	return al.cow.round()
 }
@@ -204,6 +236,71 @@ func (al *logicLedger) DelGlobal(key string) error {
 	return al.cow.DelKey(al.creator, al.aidx, true, key, 0)
 }
 
-func (al *logicLedger) GetDelta(txn *transactions.Transaction) (evalDelta basics.EvalDelta, err error) {
+// GetBox returns the content of the named box of the current application
+func (al *logicLedger) GetBox(name string) (string, bool, error) {
+	return al.cow.GetBox(al.aidx, name)
+}
+
+// SetBox creates or overwrites the named box of the current application
+func (al *logicLedger) SetBox(name string, value string) error {
+	return al.cow.SetBox(al.aidx, name, value)
+}
+
+// DelBox deletes the named box of the current application
+func (al *logicLedger) DelBox(name string) (bool, error) {
+	return al.cow.DelBox(al.aidx, name)
+}
+
+func (al *logicLedger) GetDelta(txn *transactions.Transaction) (evalDelta transactions.EvalDelta, err error) {
 	return al.cow.BuildEvalDelta(al.aidx, txn)
 }
+
+// Authorizer returns the address that must authorize transactions sent from
+// addr, taking rekeying into account
+func (al *logicLedger) Authorizer(addr basics.Address) (basics.Address, error) {
+	record, err := al.cow.Get(addr, false) // pending rewards unneeded
+	if err != nil {
+		return basics.Address{}, err
+	}
+	if !record.AuthAddr.IsZero() {
+		return record.AuthAddr, nil
+	}
+	return addr, nil
+}
+
+// Perform applies an inner transaction issued by the application, evaluating
+// it under ep if it calls another application. Minimum balances are not
+// checked here: like every other account touched by the top-level
+// transaction, they are checked once it completes.
+func (al *logicLedger) Perform(stxn *transactions.SignedTxn, ep *logic.EvalParams) (transactions.ApplyData, error) {
+	var ad transactions.ApplyData
+	tx := &stxn.Txn
+	spec := *ep.Specials
+
+	// move fee to pool
+	err := al.cow.Move(tx.Sender, spec.FeeSink, tx.Fee, &ad.SenderRewards, nil)
+	if err != nil {
+		return ad, err
+	}
+
+	switch tx.Type {
+	case protocol.PaymentTx:
+		err = apply.Payment(tx.PaymentTxnFields, tx.Header, al.cow, spec, &ad)
+	case protocol.AssetTransferTx:
+		err = apply.AssetTransfer(tx.AssetTransferTxnFields, tx.Header, al.cow, spec, &ad)
+	case protocol.ApplicationCallTx:
+		// The called application's state changes are merged into this
+		// application's cow, but belong to the inner transaction's
+		// EvalDelta. Inner calls never create applications, so the
+		// transaction counter is not needed.
+		al.cow.markInnerApp(tx.ApplicationID)
+		err = apply.ApplicationCall(tx.ApplicationCallTxnFields, tx.Header, al.cow, &ad, ep, 0)
+	default:
+		err = fmt.Errorf("%s tx in AVM", tx.Type)
+	}
+	if err != nil {
+		return ad, err
+	}
+
+	return ad, nil
+}
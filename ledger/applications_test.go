@@ -29,6 +29,7 @@ import (
 	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/protocol"
 )
@@ -60,6 +61,7 @@ type mockCowForLogicLedger struct {
 	brs    map[basics.Address]basics.AccountData
 	stores map[storeLocator]basics.TealKeyValue
 	tcs    map[int]basics.CreatableIndex
+	boxes  map[string]string
 }
 
 func (c *mockCowForLogicLedger) Get(addr basics.Address, withPendingRewards bool) (basics.AccountData, error) {
@@ -88,8 +90,8 @@ func (c *mockCowForLogicLedger) GetKey(addr basics.Address, aidx basics.AppIndex
 	return tv, found, nil
 }
 
-func (c *mockCowForLogicLedger) BuildEvalDelta(aidx basics.AppIndex, txn *transactions.Transaction) (evalDelta basics.EvalDelta, err error) {
-	return basics.EvalDelta{}, nil
+func (c *mockCowForLogicLedger) BuildEvalDelta(aidx basics.AppIndex, txn *transactions.Transaction) (evalDelta transactions.EvalDelta, err error) {
+	return transactions.EvalDelta{}, nil
 }
 
 func (c *mockCowForLogicLedger) SetKey(addr basics.Address, aidx basics.AppIndex, global bool, key string, value basics.TealValue, accountIdx uint64) error {
@@ -112,6 +114,72 @@ func (c *mockCowForLogicLedger) DelKey(addr basics.Address, aidx basics.AppIndex
 	return nil
 }
 
+func (c *mockCowForLogicLedger) GetBox(aidx basics.AppIndex, name string) (string, bool, error) {
+	value, ok := c.boxes[ledgercore.MakeBoxKey(aidx, name)]
+	return value, ok, nil
+}
+
+func (c *mockCowForLogicLedger) SetBox(aidx basics.AppIndex, name string, value string) error {
+	if c.boxes == nil {
+		c.boxes = make(map[string]string)
+	}
+	c.boxes[ledgercore.MakeBoxKey(aidx, name)] = value
+	return nil
+}
+
+func (c *mockCowForLogicLedger) DelBox(aidx basics.AppIndex, name string) (bool, error) {
+	key := ledgercore.MakeBoxKey(aidx, name)
+	_, ok := c.boxes[key]
+	delete(c.boxes, key)
+	return ok, nil
+}
+
+func (c *mockCowForLogicLedger) Put(addr basics.Address, acct basics.AccountData) error {
+	c.brs[addr] = acct
+	return nil
+}
+
+func (c *mockCowForLogicLedger) PutWithCreatable(addr basics.Address, acct basics.AccountData, newCreatable *basics.CreatableLocator, deletedCreatable *basics.CreatableLocator) error {
+	return fmt.Errorf("PutWithCreatable not implemented in mock cow")
+}
+
+func (c *mockCowForLogicLedger) Allocate(addr basics.Address, aidx basics.AppIndex, global bool, space basics.StateSchema) error {
+	return fmt.Errorf("Allocate not implemented in mock cow")
+}
+
+func (c *mockCowForLogicLedger) Deallocate(addr basics.Address, aidx basics.AppIndex, global bool) error {
+	return fmt.Errorf("Deallocate not implemented in mock cow")
+}
+
+func (c *mockCowForLogicLedger) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (bool, transactions.EvalDelta, error) {
+	return false, transactions.EvalDelta{}, fmt.Errorf("StatefulEval not implemented in mock cow")
+}
+
+func (c *mockCowForLogicLedger) Move(src, dst basics.Address, amount basics.MicroAlgos, srcRewards *basics.MicroAlgos, dstRewards *basics.MicroAlgos) error {
+	srcData, err := c.Get(src, false)
+	if err != nil {
+		return err
+	}
+	var overflowed bool
+	srcData.MicroAlgos, overflowed = basics.OSubA(srcData.MicroAlgos, amount)
+	if overflowed {
+		return fmt.Errorf("overspend (account %v, data %+v, tried to spend %v)", src, srcData, amount)
+	}
+	c.brs[src] = srcData
+
+	dstData := c.brs[dst]
+	dstData.MicroAlgos, overflowed = basics.OAddA(dstData.MicroAlgos, amount)
+	if overflowed {
+		return fmt.Errorf("overflowed tried to receive %v to %v", amount, dst)
+	}
+	c.brs[dst] = dstData
+	return nil
+}
+
+func (c *mockCowForLogicLedger) ConsensusParams() config.ConsensusParams {
+	return config.Consensus[protocol.ConsensusCurrentVersion]
+}
+
 func (c *mockCowForLogicLedger) round() basics.Round {
 	return c.rnd
 }
@@ -125,6 +193,9 @@ func (c *mockCowForLogicLedger) allocated(addr basics.Address, aidx basics.AppIn
 	return found, nil
 }
 
+func (c *mockCowForLogicLedger) markInnerApp(aidx basics.AppIndex) {
+}
+
 func newCowMock(creatables []modsData) *mockCowForLogicLedger {
 	var m mockCowForLogicLedger
 	m.cr = make(map[creatableLocator]basics.Address, len(creatables))
@@ -501,7 +572,7 @@ return`
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, genesisInitState.Accounts, initKeys, appCall,
-		transactions.ApplyData{EvalDelta: basics.EvalDelta{
+		transactions.ApplyData{EvalDelta: transactions.EvalDelta{
 			LocalDeltas: map[uint64]basics.StateDelta{0: {"lk": basics.ValueDelta{Action: basics.SetBytesAction, Bytes: "local"}}}},
 		})
 	a.NoError(err)
@@ -549,7 +620,7 @@ return`
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, genesisInitState.Accounts, initKeys, appCall,
-		transactions.ApplyData{EvalDelta: basics.EvalDelta{
+		transactions.ApplyData{EvalDelta: transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta{"gk": basics.ValueDelta{Action: basics.SetBytesAction, Bytes: "global"}}},
 		})
 	a.NoError(err)
@@ -567,7 +638,7 @@ return`
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, genesisInitState.Accounts, initKeys, appCall,
-		transactions.ApplyData{EvalDelta: basics.EvalDelta{
+		transactions.ApplyData{EvalDelta: transactions.EvalDelta{
 			LocalDeltas: map[uint64]basics.StateDelta{0: {"lk": basics.ValueDelta{Action: basics.SetBytesAction, Bytes: "local"}}}},
 		})
 	a.NoError(err)
@@ -682,7 +753,7 @@ return`
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, genesisInitState.Accounts, initKeys, appCall, transactions.ApplyData{
-		EvalDelta: basics.EvalDelta{
+		EvalDelta: transactions.EvalDelta{
 			LocalDeltas: map[uint64]basics.StateDelta{0: {"lk": basics.ValueDelta{
 				Action: basics.SetBytesAction,
 				Bytes:  "local",
@@ -745,7 +816,7 @@ return`
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, genesisInitState.Accounts, initKeys, appCall,
-		transactions.ApplyData{EvalDelta: basics.EvalDelta{
+		transactions.ApplyData{EvalDelta: transactions.EvalDelta{
 			GlobalDelta: basics.StateDelta{"gk": basics.ValueDelta{Action: basics.SetBytesAction, Bytes: "global"}}},
 		})
 	a.NoError(err)
@@ -814,7 +885,7 @@ return`
 
 	blk = makeNewEmptyBlock(t, l, genesisID, genesisInitState.Accounts)
 	ad1 := transactions.ApplyData{
-		EvalDelta: basics.EvalDelta{
+		EvalDelta: transactions.EvalDelta{
 			LocalDeltas: map[uint64]basics.StateDelta{0: {"lk1": basics.ValueDelta{
 				Action: basics.SetBytesAction,
 				Bytes:  "local1",
@@ -927,7 +998,7 @@ return`
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, nil, initKeys, appCall, transactions.ApplyData{
-		EvalDelta: basics.EvalDelta{
+		EvalDelta: transactions.EvalDelta{
 			LocalDeltas: map[uint64]basics.StateDelta{0: {"lk": basics.ValueDelta{
 				Action: basics.SetBytesAction,
 				Bytes:  "local",
@@ -1092,7 +1163,7 @@ return`
 	stx2 := sign(initKeys, payment)
 
 	blk := makeNewEmptyBlock(t, l, genesisID, genesisInitState.Accounts)
-	txib1, err := blk.EncodeSignedTxn(stx1, transactions.ApplyData{EvalDelta: basics.EvalDelta{
+	txib1, err := blk.EncodeSignedTxn(stx1, transactions.ApplyData{EvalDelta: transactions.EvalDelta{
 		GlobalDelta: basics.StateDelta{
 			"gk": basics.ValueDelta{Action: basics.SetBytesAction, Bytes: "global"},
 		}},
@@ -1264,7 +1335,7 @@ func testAppAccountDeltaIndicesCompatibility(t *testing.T, source string, accoun
 		ApplicationCallTxnFields: appCallFields,
 	}
 	err = l.appendUnvalidatedTx(t, genesisInitState.Accounts, initKeys, appCall, transactions.ApplyData{
-		EvalDelta: basics.EvalDelta{
+		EvalDelta: transactions.EvalDelta{
 			LocalDeltas: map[uint64]basics.StateDelta{
 				accountIdx: {
 					"lk0": basics.ValueDelta{
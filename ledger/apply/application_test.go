@@ -113,7 +113,7 @@ type testBalances struct {
 
 	// logic evaluator control
 	pass  bool
-	delta basics.EvalDelta
+	delta transactions.EvalDelta
 	err   error
 }
 
@@ -186,7 +186,7 @@ func (b *testBalances) Deallocate(addr basics.Address, aidx basics.AppIndex, glo
 	return nil
 }
 
-func (b *testBalances) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (passed bool, evalDelta basics.EvalDelta, err error) {
+func (b *testBalances) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (passed bool, evalDelta transactions.EvalDelta, err error) {
 	return b.pass, b.delta, b.err
 }
 
@@ -226,7 +226,7 @@ func (b *testBalancesPass) Deallocate(addr basics.Address, aidx basics.AppIndex,
 	return nil
 }
 
-func (b *testBalancesPass) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (passed bool, evalDelta basics.EvalDelta, err error) {
+func (b *testBalancesPass) StatefulEval(params logic.EvalParams, aidx basics.AppIndex, program []byte) (passed bool, evalDelta transactions.EvalDelta, err error) {
 	return true, b.delta, nil
 }
 
@@ -247,14 +247,14 @@ func (b *testBalances) SetProto(name protocol.ConsensusVersion) {
 
 type testEvaluator struct {
 	pass   bool
-	delta  basics.EvalDelta
+	delta  transactions.EvalDelta
 	appIdx basics.AppIndex
 }
 
 // Eval for tests that fail on program version > 10 and returns pass/delta from its own state rather than running the program
-func (e *testEvaluator) Eval(program []byte) (pass bool, stateDelta basics.EvalDelta, err error) {
+func (e *testEvaluator) Eval(program []byte) (pass bool, stateDelta transactions.EvalDelta, err error) {
 	if len(program) < 1 || program[0] > 10 {
-		return false, basics.EvalDelta{}, fmt.Errorf("mock eval error")
+		return false, transactions.EvalDelta{}, fmt.Errorf("mock eval error")
 	}
 	return e.pass, e.delta, nil
 }
@@ -574,7 +574,7 @@ func TestAppCallApplyCreateOptIn(t *testing.T) {
 	b.appCreators = map[basics.AppIndex]basics.Address{appIdx: creator}
 
 	gd := map[string]basics.ValueDelta{"uint": {Action: basics.SetUintAction, Uint: 1}}
-	b.delta = basics.EvalDelta{GlobalDelta: gd}
+	b.delta = transactions.EvalDelta{GlobalDelta: gd}
 
 	err := ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.NoError(err)
@@ -739,7 +739,7 @@ func TestAppCallClearState(t *testing.T) {
 	br = b.putBalances[sender]
 	a.Equal(0, len(br.AppLocalStates))
 	a.Equal(basics.StateSchema{}, br.TotalAppSchema)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 
 	b.ResetWrites()
 
@@ -758,7 +758,7 @@ func TestAppCallClearState(t *testing.T) {
 
 	// one put: to opt out
 	b.pass = false
-	b.delta = basics.EvalDelta{GlobalDelta: nil}
+	b.delta = transactions.EvalDelta{GlobalDelta: nil}
 	err = ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.NoError(err)
 	a.Equal(1, b.put)
@@ -773,7 +773,7 @@ func TestAppCallClearState(t *testing.T) {
 	// check existing application with logic err ClearStateProgram.
 	// one to opt out, one deallocate, no error from ApplicationCall
 	b.pass = true
-	b.delta = basics.EvalDelta{GlobalDelta: nil}
+	b.delta = transactions.EvalDelta{GlobalDelta: nil}
 	b.err = ledgercore.LogicEvalError{Err: fmt.Errorf("test error")}
 	err = ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.NoError(err)
@@ -789,7 +789,7 @@ func TestAppCallClearState(t *testing.T) {
 	// check existing application with non-logic err ClearStateProgram.
 	// ApplicationCall must fail
 	b.pass = true
-	b.delta = basics.EvalDelta{GlobalDelta: nil}
+	b.delta = transactions.EvalDelta{GlobalDelta: nil}
 	b.err = fmt.Errorf("test error")
 	err = ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.Error(err)
@@ -805,7 +805,7 @@ func TestAppCallClearState(t *testing.T) {
 	b.pass = true
 	b.err = nil
 	gd := basics.StateDelta{"uint": {Action: basics.SetUintAction, Uint: 1}}
-	b.delta = basics.EvalDelta{GlobalDelta: gd}
+	b.delta = transactions.EvalDelta{GlobalDelta: gd}
 	err = ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.NoError(err)
 	a.Equal(1, b.put)
@@ -813,7 +813,7 @@ func TestAppCallClearState(t *testing.T) {
 	a.Equal(appIdx, b.deAllocatedAppIdx)
 	a.Equal(0, len(br.AppLocalStates))
 	a.Equal(basics.StateSchema{}, br.TotalAppSchema)
-	a.Equal(basics.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
 }
 
 func TestAppCallApplyCloseOut(t *testing.T) {
@@ -863,7 +863,7 @@ func TestAppCallApplyCloseOut(t *testing.T) {
 	a.Equal(0, b.putWith)
 	br := b.balances[creator]
 	a.Equal(cbr, br)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 
 	// check closing on empty sender's balance record
 	b.pass = true
@@ -875,13 +875,13 @@ func TestAppCallApplyCloseOut(t *testing.T) {
 	a.Equal(0, b.putWith)
 	br = b.balances[creator]
 	a.Equal(cbr, br)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 
 	b.ResetWrites()
 
 	// check a happy case
 	gd := map[string]basics.ValueDelta{"uint": {Action: basics.SetUintAction, Uint: 1}}
-	b.delta = basics.EvalDelta{GlobalDelta: gd}
+	b.delta = transactions.EvalDelta{GlobalDelta: gd}
 	b.balances[sender] = basics.AccountData{
 		AppLocalStates: map[basics.AppIndex]basics.AppLocalState{appIdx: {}},
 	}
@@ -894,7 +894,7 @@ func TestAppCallApplyCloseOut(t *testing.T) {
 	a.Equal(basics.TealKeyValue(nil), br.AppParams[appIdx].GlobalState)
 	br = b.putBalances[sender]
 	a.Equal(0, len(br.AppLocalStates))
-	a.Equal(basics.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
 	a.Equal(basics.StateSchema{NumUint: 0}, br.TotalAppSchema)
 }
 
@@ -947,7 +947,7 @@ func TestAppCallApplyUpdate(t *testing.T) {
 	a.Equal(0, b.putWith)
 	br := b.balances[creator]
 	a.Equal(cbr, br)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 
 	// check updating on empty sender's balance record - happy case
 	b.pass = true
@@ -961,7 +961,7 @@ func TestAppCallApplyUpdate(t *testing.T) {
 	br = b.putBalances[creator]
 	a.Equal([]byte{2}, br.AppParams[appIdx].ApprovalProgram)
 	a.Equal([]byte{2}, br.AppParams[appIdx].ClearStateProgram)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 }
 
 func TestAppCallApplyDelete(t *testing.T) {
@@ -1011,7 +1011,7 @@ func TestAppCallApplyDelete(t *testing.T) {
 	a.Equal(0, b.putWith)
 	br := b.balances[creator]
 	a.Equal(cbr, br)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 
 	// check deletion on empty balance record - happy case
 	b.pass = true
@@ -1026,7 +1026,7 @@ func TestAppCallApplyDelete(t *testing.T) {
 	br = b.putBalances[creator]
 	a.Equal(basics.AppParams{}, br.AppParams[appIdx])
 	a.Equal(basics.StateSchema{}, br.TotalAppSchema)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 	a.Equal(uint32(0), br.TotalExtraAppPages)
 }
 
@@ -1061,14 +1061,14 @@ func TestAppCallApplyCreateClearState(t *testing.T) {
 
 	b.pass = true
 	gd := map[string]basics.ValueDelta{"uint": {Action: basics.SetUintAction, Uint: 1}}
-	b.delta = basics.EvalDelta{GlobalDelta: gd}
+	b.delta = transactions.EvalDelta{GlobalDelta: gd}
 
 	// check creation on empty balance record
 	err := ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.Error(err)
 	a.Contains(err.Error(), "not currently opted in")
 	a.Equal(appIdx, b.allocatedAppIdx)
-	a.Equal(basics.EvalDelta{}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{}, ad.EvalDelta)
 	br := b.balances[creator]
 	a.Equal([]byte{1}, br.AppParams[appIdx].ApprovalProgram)
 	a.Equal([]byte{2}, br.AppParams[appIdx].ClearStateProgram)
@@ -1109,13 +1109,13 @@ func TestAppCallApplyCreateDelete(t *testing.T) {
 
 	b.pass = true
 	gd := map[string]basics.ValueDelta{"uint": {Action: basics.SetUintAction, Uint: 1}}
-	b.delta = basics.EvalDelta{GlobalDelta: gd}
+	b.delta = transactions.EvalDelta{GlobalDelta: gd}
 
 	// check creation on empty balance record
 	err := ApplicationCall(ac, h, &b, ad, &ep, txnCounter)
 	a.NoError(err)
 	a.Equal(appIdx, b.allocatedAppIdx)
-	a.Equal(basics.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
+	a.Equal(transactions.EvalDelta{GlobalDelta: gd}, ad.EvalDelta)
 	br := b.balances[creator]
 	a.Equal(basics.AppParams{}, br.AppParams[appIdx])
 }
@@ -73,8 +73,8 @@ func (balances keyregTestBalances) Deallocate(basics.Address, basics.AppIndex, b
 	return nil
 }
 
-func (balances keyregTestBalances) StatefulEval(logic.EvalParams, basics.AppIndex, []byte) (bool, basics.EvalDelta, error) {
-	return false, basics.EvalDelta{}, nil
+func (balances keyregTestBalances) StatefulEval(logic.EvalParams, basics.AppIndex, []byte) (bool, transactions.EvalDelta, error) {
+	return false, transactions.EvalDelta{}, nil
 }
 
 func TestKeyregApply(t *testing.T) {
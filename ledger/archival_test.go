@@ -17,9 +17,7 @@
 package ledger
 
 import (
-	"context"
 	"crypto/rand"
-	"database/sql"
 	"fmt"
 	"io/ioutil"
 	mathrand "math/rand"
@@ -79,8 +77,12 @@ func (wl *wrappedLedger) trackerDB() db.Pair {
 	return wl.l.trackerDB()
 }
 
-func (wl *wrappedLedger) blockDB() db.Pair {
-	return wl.l.blockDB()
+func (wl *wrappedLedger) blockStorage() blockStore {
+	return wl.l.blockStorage()
+}
+
+func (wl *wrappedLedger) accountStorage() accountStore {
+	return wl.l.accountStorage()
 }
 
 func (wl *wrappedLedger) trackerLog() logging.Logger {
@@ -208,14 +210,9 @@ func TestArchivalRestart(t *testing.T) {
 	l.WaitForCommit(blk.Round())
 
 	var latest, earliest basics.Round
-	err = l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
-		require.NoError(t, err)
-
-		earliest, err = blockEarliest(tx)
-		require.NoError(t, err)
-		return err
-	})
+	latest, err = l.blocks.latest()
+	require.NoError(t, err)
+	earliest, err = l.blocks.earliest()
 	require.NoError(t, err)
 	require.Equal(t, basics.Round(maxBlocks), latest)
 	require.Equal(t, basics.Round(0), earliest)
@@ -225,14 +222,9 @@ func TestArchivalRestart(t *testing.T) {
 	require.NoError(t, err)
 	defer l.Close()
 
-	err = l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
-		require.NoError(t, err)
-
-		earliest, err = blockEarliest(tx)
-		require.NoError(t, err)
-		return err
-	})
+	latest, err = l.blocks.latest()
+	require.NoError(t, err)
+	earliest, err = l.blocks.earliest()
 	require.NoError(t, err)
 	require.Equal(t, basics.Round(maxBlocks), latest)
 	require.Equal(t, basics.Round(0), earliest)
@@ -739,14 +731,9 @@ func TestArchivalFromNonArchival(t *testing.T) {
 	l.WaitForCommit(blk.Round())
 
 	var latest, earliest basics.Round
-	err = l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
-		require.NoError(t, err)
-
-		earliest, err = blockEarliest(tx)
-		require.NoError(t, err)
-		return err
-	})
+	latest, err = l.blocks.latest()
+	require.NoError(t, err)
+	earliest, err = l.blocks.earliest()
 	require.NoError(t, err)
 	require.Equal(t, basics.Round(maxBlocks), latest)
 	require.True(t, basics.Round(0) < earliest, fmt.Sprintf("%d < %d", basics.Round(0), earliest))
@@ -759,14 +746,9 @@ func TestArchivalFromNonArchival(t *testing.T) {
 	require.NoError(t, err)
 	defer l.Close()
 
-	err = l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		latest, err = blockLatest(tx)
-		require.NoError(t, err)
-
-		earliest, err = blockEarliest(tx)
-		require.NoError(t, err)
-		return err
-	})
+	latest, err = l.blocks.latest()
+	require.NoError(t, err)
+	earliest, err = l.blocks.earliest()
 	require.NoError(t, err)
 	require.Equal(t, basics.Round(0), earliest)
 	require.Equal(t, basics.Round(0), latest)
@@ -0,0 +1,459 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/algorand/go-codec/codec"
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/metrics"
+)
+
+// Block retention policies, as set by the BlockRetentionPolicy config option
+const (
+	// BlockRetentionProtocol keeps only the blocks the protocol requires
+	BlockRetentionProtocol = ""
+	// BlockRetentionRounds keeps the blocks of the last BlockRetentionPeriod rounds
+	BlockRetentionRounds = "rounds"
+	// BlockRetentionDays keeps the blocks of the last BlockRetentionPeriod days
+	BlockRetentionDays = "days"
+	// BlockRetentionAll keeps every block
+	BlockRetentionAll = "all"
+)
+
+const (
+	// blockArchiveRounds is the number of rounds in each of the files the pruned blocks are exported to
+	blockArchiveRounds = 1000
+
+	// blockArchiveMaxEntryBytes is the maximal size of an entry of a block archive file, holding a block and its
+	// certificate. It's the same bound the catchup service puts on the responses of the block service.
+	blockArchiveMaxEntryBytes = 5 << 20
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+// blockRetention is the retention policy of the blocks of a non-archival ledger
+type blockRetention struct {
+	policy       string
+	period       uint64
+	archiveDir   string
+	vacuumRounds uint64
+}
+
+func makeBlockRetention(cfg config.Local) (blockRetention, error) {
+	br := blockRetention{
+		policy:       cfg.BlockRetentionPolicy,
+		period:       cfg.BlockRetentionPeriod,
+		archiveDir:   cfg.BlockArchiveDir,
+		vacuumRounds: cfg.BlockVacuumRounds,
+	}
+	switch br.policy {
+	case BlockRetentionProtocol, BlockRetentionAll:
+	case BlockRetentionRounds, BlockRetentionDays:
+		if br.period == 0 {
+			return blockRetention{}, fmt.Errorf("block retention policy '%s' requires a non-zero BlockRetentionPeriod", br.policy)
+		}
+	default:
+		return blockRetention{}, fmt.Errorf("unknown block retention policy '%s'", br.policy)
+	}
+	return br, nil
+}
+
+// archivedBlockCert is the content of each entry of a block archive file. It's encoded the same way as the
+// responses of the block service, so that catchupsrv can serve the entries as they are.
+//msgp:ignore archivedBlockCert
+type archivedBlockCert struct {
+	Block       codec.Raw `codec:"block"`
+	Certificate codec.Raw `codec:"cert"`
+}
+
+// blockArchiveEntry is the decoded content of an entry of a block archive file.
+//msgp:ignore blockArchiveEntry
+type blockArchiveEntry struct {
+	Block       bookkeeping.Block     `codec:"block"`
+	Certificate agreement.Certificate `codec:"cert"`
+}
+
+// blockPruner deletes the blocks that are no longer needed, neither by the trackers nor by the retention policy,
+// from the blocks storage. It runs in the background, so that exporting the blocks it deletes and vacuuming the
+// storage don't hold the blockQueue from writing new blocks.
+type blockPruner struct {
+	blocks    blockStore
+	log       logging.Logger
+	retention blockRetention
+
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+
+	mu   deadlock.Mutex
+	cond *sync.Cond
+	// committed is the latest round written to the blocks storage, and minToSave the earliest round the trackers
+	// need. pending is set when they were updated since the last pruning.
+	committed basics.Round
+	minToSave basics.Round
+	pending   bool
+	running   bool
+	closed    chan struct{}
+
+	// prunedSinceVacuum is the number of rounds pruned since the blocks storage was last vacuumed.
+	prunedSinceVacuum uint64
+}
+
+func makeBlockPruner(blocks blockStore, log logging.Logger, retention blockRetention) *blockPruner {
+	bp := &blockPruner{
+		blocks:    blocks,
+		log:       log,
+		retention: retention,
+		running:   true,
+		closed:    make(chan struct{}),
+	}
+	bp.cond = sync.NewCond(&bp.mu)
+	bp.ctx, bp.cancelCtx = context.WithCancel(context.Background())
+	go bp.pruner()
+	return bp
+}
+
+// close stops the pruner, interrupting the export or the vacuuming it might be busy with.
+func (bp *blockPruner) close() {
+	bp.mu.Lock()
+	if bp.running {
+		bp.running = false
+		bp.cancelCtx()
+		bp.cond.Broadcast()
+	}
+	bp.mu.Unlock()
+	<-bp.closed
+}
+
+// notifyCommit lets the pruner know that the blocks up to committed were written, and that the trackers no longer
+// need the blocks before minToSave.
+func (bp *blockPruner) notifyCommit(committed basics.Round, minToSave basics.Round) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.committed = committed
+	bp.minToSave = minToSave
+	bp.pending = true
+	bp.cond.Broadcast()
+}
+
+func (bp *blockPruner) pruner() {
+	defer close(bp.closed)
+	bp.mu.Lock()
+	for {
+		for bp.running && !bp.pending {
+			bp.cond.Wait()
+		}
+		if !bp.running {
+			bp.mu.Unlock()
+			return
+		}
+		committed, minToSave := bp.committed, bp.minToSave
+		bp.pending = false
+		bp.mu.Unlock()
+
+		err := bp.prune(committed, minToSave)
+		if err != nil && bp.ctx.Err() == nil {
+			bp.log.Warnf("blockPruner: unable to prune the blocks before round %d: %v", minToSave, err)
+		}
+
+		bp.mu.Lock()
+	}
+}
+
+// prune deletes the blocks that neither the trackers nor the retention policy need, exporting them first if an
+// archive directory is configured. If vacuuming is enabled, it also vacuums the storage once enough rounds were
+// pruned.
+func (bp *blockPruner) prune(committed basics.Round, minToSave basics.Round) error {
+	if bp.retention.policy == BlockRetentionAll || minToSave == 0 {
+		return nil
+	}
+	earliest, err := bp.blocks.earliest()
+	if err != nil {
+		return err
+	}
+	target, err := bp.retainFrom(earliest, committed)
+	if err != nil {
+		return err
+	}
+	if target > minToSave {
+		target = minToSave
+	}
+
+	if bp.retention.archiveDir == "" {
+		if earliest < target {
+			err = bp.forgetBefore(earliest, target)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// export whole archive files only, rather than a file for each of the few rounds pruned at every commit.
+		for {
+			last := (earliest/blockArchiveRounds+1)*blockArchiveRounds - 1
+			if last >= target {
+				break
+			}
+			err = bp.archive(earliest, last)
+			if err != nil {
+				return err
+			}
+			err = bp.forgetBefore(earliest, last+1)
+			if err != nil {
+				return err
+			}
+			earliest = last + 1
+		}
+	}
+
+	if bp.retention.vacuumRounds != 0 && bp.prunedSinceVacuum >= bp.retention.vacuumRounds {
+		bp.prunedSinceVacuum = 0
+		return bp.vacuum()
+	}
+	return nil
+}
+
+// retainFrom returns the earliest round the retention policy keeps, out of the blocks from earliest to committed.
+func (bp *blockPruner) retainFrom(earliest basics.Round, committed basics.Round) (basics.Round, error) {
+	switch bp.retention.policy {
+	case BlockRetentionRounds:
+		if uint64(committed) < bp.retention.period {
+			return 0, nil
+		}
+		return committed + 1 - basics.Round(bp.retention.period), nil
+	case BlockRetentionDays:
+		// the age of the blocks is measured against the latest block rather than the clock, so that a node
+		// that is catching up doesn't prune the blocks it has just written.
+		hdr, err := bp.blocks.getHdr(committed)
+		if err != nil {
+			return 0, err
+		}
+		cutoff := hdr.TimeStamp - int64(bp.retention.period*secondsPerDay)
+		// the timestamps of the blocks never decrease, so look for the first block that isn't older than cutoff.
+		low, high := earliest, committed
+		for low < high {
+			mid := low + (high-low)/2
+			hdr, err = bp.blocks.getHdr(mid)
+			if err != nil {
+				return 0, err
+			}
+			if hdr.TimeStamp < cutoff {
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+		return low, nil
+	default:
+		return committed + 1, nil
+	}
+}
+
+func (bp *blockPruner) forgetBefore(earliest basics.Round, rnd basics.Round) error {
+	start := time.Now()
+	ledgerSyncBlockforgetCount.Inc(nil)
+	err := bp.blocks.forgetBefore(rnd)
+	ledgerSyncBlockforgetMicros.AddMicrosecondsSince(start, nil)
+	if err != nil {
+		return err
+	}
+	bp.prunedSinceVacuum += uint64(rnd - earliest)
+	return nil
+}
+
+// archive exports the blocks from first to last into the <first>_<last>.tar.gz file of the archive directory.
+func (bp *blockPruner) archive(first basics.Round, last basics.Round) (err error) {
+	err = os.MkdirAll(bp.retention.archiveDir, 0700)
+	if err != nil {
+		return err
+	}
+	fileName := filepath.Join(bp.retention.archiveDir, fmt.Sprintf("%d_%d.tar.gz", first, last))
+	// write a temporary file first, so that a partially written archive is never mistaken for a complete one.
+	tmpFileName := fileName + ".tmp"
+	file, err := os.OpenFile(tmpFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(tmpFileName)
+		}
+	}()
+
+	start := time.Now()
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for rnd := first; rnd <= last; rnd++ {
+		if bp.ctx.Err() != nil {
+			return bp.ctx.Err()
+		}
+		var entry archivedBlockCert
+		entry.Block, entry.Certificate, err = bp.blocks.getEncodedCert(rnd)
+		if err != nil {
+			return err
+		}
+		if len(entry.Certificate) == 0 {
+			// the blocks written by the catchpoint catchup have no certificate
+			entry.Certificate = protocol.Encode(&agreement.Certificate{})
+		}
+		data := protocol.EncodeReflect(&entry)
+		err = tarWriter.WriteHeader(&tar.Header{
+			Name: strconv.FormatUint(uint64(rnd), 10),
+			Mode: 0600,
+			Size: int64(len(data)),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tarWriter.Write(data)
+		if err != nil {
+			return err
+		}
+	}
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+	err = gzipWriter.Close()
+	if err != nil {
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpFileName, fileName)
+	if err != nil {
+		return err
+	}
+	ledgerBlockArchiveMicros.AddMicrosecondsSince(start, nil)
+	bp.log.Infof("blockPruner: exported the blocks of rounds %d to %d into %s", first, last, fileName)
+	return nil
+}
+
+func (bp *blockPruner) vacuum() error {
+	start := time.Now()
+	bp.log.Infof("blockPruner: vacuuming the blocks storage")
+	ledgerBlockVacuumCount.Inc(nil)
+	err := bp.blocks.vacuum(bp.ctx)
+	if err != nil {
+		return err
+	}
+	ledgerBlockVacuumMicros.AddMicrosecondsSince(start, nil)
+	bp.log.Infof("blockPruner: vacuuming the blocks storage completed within %v", time.Now().Sub(start))
+	return nil
+}
+
+// ImportBlockArchive adds the blocks of a block archive file, as exported by a non-archival node pruning its
+// blocks, to the ledger. The blocks the ledger already has are skipped, and the archive has to continue the
+// latest block of the ledger. It returns the number of blocks that were added.
+func (l *Ledger) ImportBlockArchive(r io.Reader) (imported int, err error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+		rnd, err := strconv.ParseUint(header.Name, 10, 64)
+		if err != nil {
+			return imported, fmt.Errorf("invalid block archive entry name '%s': %v", header.Name, err)
+		}
+		if basics.Round(rnd) <= l.Latest() {
+			continue
+		}
+		// don't trust the size in the entry header; a block and its certificate never get larger than this.
+		data, err := ioutil.ReadAll(io.LimitReader(tarReader, blockArchiveMaxEntryBytes+1))
+		if err != nil {
+			return imported, err
+		}
+		if len(data) > blockArchiveMaxEntryBytes {
+			return imported, fmt.Errorf("block archive entry %d exceeds %d bytes", rnd, blockArchiveMaxEntryBytes)
+		}
+		var entry blockArchiveEntry
+		err = protocol.DecodeReflect(data, &entry)
+		if err != nil {
+			return imported, fmt.Errorf("unable to decode the block of round %d: %v", rnd, err)
+		}
+		if entry.Block.Round() != basics.Round(rnd) {
+			return imported, fmt.Errorf("block archive entry %d holds the block of round %d", rnd, entry.Block.Round())
+		}
+		err = l.AddBlock(entry.Block, entry.Certificate)
+		if err != nil {
+			return imported, err
+		}
+		imported++
+	}
+}
+
+// BlockArchiveFiles returns the block archive files of the given directory, ordered by the rounds they hold.
+func BlockArchiveFiles(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*_*.tar.gz"))
+	if err != nil {
+		return nil, err
+	}
+	firstRounds := make(map[string]uint64, len(matches))
+	files := make([]string, 0, len(matches))
+	for _, match := range matches {
+		var first, last uint64
+		_, err = fmt.Sscanf(filepath.Base(match), "%d_%d.tar.gz", &first, &last)
+		if err != nil {
+			// not an archive file
+			continue
+		}
+		firstRounds[match] = first
+		files = append(files, match)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return firstRounds[files[i]] < firstRounds[files[j]]
+	})
+	return files, nil
+}
+
+var ledgerBlockArchiveMicros = metrics.NewCounter("ledger_block_archive_micros", "µs spent exporting pruned blocks")
+var ledgerBlockVacuumCount = metrics.NewCounter("ledger_block_vacuum_count", "calls")
+var ledgerBlockVacuumMicros = metrics.NewCounter("ledger_block_vacuum_micros", "µs spent")
@@ -0,0 +1,251 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+// makePrunerTestStore returns a block store holding the blocks up to latest, a block per hour.
+func makePrunerTestStore(t *testing.T, open func() blockStore, latest basics.Round) blockStore {
+	bs := open()
+	blocks := randomInitChain(protocol.ConsensusCurrentVersion, 1)
+	blocks[0].block.TimeStamp = 0
+	require.NoError(t, bs.initialize(blockChainBlocks(blocks), false, logging.TestingLog(t)))
+	var entries []blockEntry
+	for rnd := basics.Round(1); rnd <= latest; rnd++ {
+		e := randomBlock(rnd)
+		e.block.TimeStamp = int64(rnd) * 3600
+		entries = append(entries, e)
+		if len(entries) == 500 || rnd == latest {
+			require.NoError(t, bs.put(entries))
+			entries = nil
+		}
+	}
+	return bs
+}
+
+func TestBlockPrunerRetention(t *testing.T) {
+	_, err := makeBlockRetention(config.Local{BlockRetentionPolicy: "weeks"})
+	require.Error(t, err)
+	_, err = makeBlockRetention(config.Local{BlockRetentionPolicy: BlockRetentionRounds})
+	require.Error(t, err)
+
+	testCases := []struct {
+		policy           string
+		period           uint64
+		minToSave        basics.Round
+		expectedEarliest basics.Round
+	}{
+		{BlockRetentionProtocol, 0, 2500, 2500},
+		{BlockRetentionProtocol, 0, 0, 0},
+		{BlockRetentionRounds, 300, 2900, 2700},
+		{BlockRetentionRounds, 300, 2500, 2500},
+		{BlockRetentionRounds, 5000, 2500, 0},
+		{BlockRetentionDays, 1, 2999, 2975},
+		{BlockRetentionDays, 1, 2000, 2000},
+		{BlockRetentionAll, 0, 2999, 0},
+	}
+	for name, open := range testBlockStores(t) {
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("%s/%s-%d-%d", name, tc.policy, tc.period, tc.minToSave), func(t *testing.T) {
+				bs := makePrunerTestStore(t, open, 2999)
+				defer bs.close()
+				retention, err := makeBlockRetention(config.Local{BlockRetentionPolicy: tc.policy, BlockRetentionPeriod: tc.period})
+				require.NoError(t, err)
+				bp := makeBlockPruner(bs, logging.TestingLog(t), retention)
+				defer bp.close()
+
+				require.NoError(t, bp.prune(2999, tc.minToSave))
+				earliest, err := bs.earliest()
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedEarliest, earliest)
+				latest, err := bs.latest()
+				require.NoError(t, err)
+				require.Equal(t, basics.Round(2999), latest)
+			})
+		}
+	}
+}
+
+func TestBlockPrunerArchive(t *testing.T) {
+	for name, open := range testBlockStores(t) {
+		t.Run(name, func(t *testing.T) {
+			archiveDir, err := ioutil.TempDir("", "blockarchive")
+			require.NoError(t, err)
+			defer os.RemoveAll(archiveDir)
+
+			bs := makePrunerTestStore(t, open, 2999)
+			defer bs.close()
+			bp := makeBlockPruner(bs, logging.TestingLog(t), blockRetention{archiveDir: archiveDir})
+			defer bp.close()
+
+			// only whole archive files are exported and pruned
+			require.NoError(t, bp.prune(2999, 2500))
+			earliest, err := bs.earliest()
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(2000), earliest)
+			files, err := filepath.Glob(filepath.Join(archiveDir, "*"))
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				filepath.Join(archiveDir, "0_999.tar.gz"),
+				filepath.Join(archiveDir, "1000_1999.tar.gz"),
+			}, files)
+
+			file, err := os.Open(files[1])
+			require.NoError(t, err)
+			defer file.Close()
+			gzipReader, err := gzip.NewReader(file)
+			require.NoError(t, err)
+			tarReader := tar.NewReader(gzipReader)
+			rnd := basics.Round(1000)
+			for {
+				header, err := tarReader.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				require.Equal(t, fmt.Sprintf("%d", rnd), header.Name)
+				data, err := ioutil.ReadAll(tarReader)
+				require.NoError(t, err)
+				var entry blockArchiveEntry
+				require.NoError(t, protocol.DecodeReflect(data, &entry))
+				require.Equal(t, rnd, entry.Block.Round())
+				require.Equal(t, rnd, entry.Certificate.Round)
+				require.Equal(t, int64(rnd)*3600, entry.Block.TimeStamp)
+				rnd++
+			}
+			require.Equal(t, basics.Round(2000), rnd)
+		})
+	}
+}
+
+func TestImportBlockArchive(t *testing.T) {
+	archiveDir, err := ioutil.TempDir("", "blockarchive")
+	require.NoError(t, err)
+	defer os.RemoveAll(archiveDir)
+
+	genesisInitState := getInitState()
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	log := logging.TestingLog(t)
+	source, err := OpenLedger(log, fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64()), inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer source.Close()
+
+	blk := genesisInitState.Block
+	for i := 0; i < 1200; i++ {
+		blk.BlockHeader.Round++
+		blk.BlockHeader.TimeStamp += 1000
+		require.NoError(t, source.AddBlock(blk, agreement.Certificate{Round: blk.Round()}))
+	}
+	source.WaitForCommit(blk.Round())
+
+	bp := makeBlockPruner(source.blocks, log, blockRetention{archiveDir: archiveDir})
+	defer bp.close()
+	require.NoError(t, bp.archive(0, 999))
+
+	target, err := OpenLedger(log, fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64()), inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer target.Close()
+
+	importArchive := func() int {
+		file, err := os.Open(filepath.Join(archiveDir, "0_999.tar.gz"))
+		require.NoError(t, err)
+		defer file.Close()
+		imported, err := target.ImportBlockArchive(file)
+		require.NoError(t, err)
+		return imported
+	}
+	// the genesis block is already in the ledger
+	require.Equal(t, 999, importArchive())
+	require.Equal(t, basics.Round(999), target.Latest())
+	for _, rnd := range []basics.Round{1, 500, 999} {
+		expected, expectedCert, err := source.BlockCert(rnd)
+		require.NoError(t, err)
+		imported, cert, err := target.BlockCert(rnd)
+		require.NoError(t, err)
+		require.Equal(t, expected.Hash(), imported.Hash())
+		require.Equal(t, expectedCert, cert)
+	}
+
+	// importing the same archive again doesn't add anything
+	require.Equal(t, 0, importArchive())
+
+	// the archive files are ordered by rounds rather than by name
+	require.NoError(t, bp.archive(1000, 1099))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(archiveDir, "notes_1.tar.gz"), nil, 0600))
+	files, err := BlockArchiveFiles(archiveDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(archiveDir, "0_999.tar.gz"),
+		filepath.Join(archiveDir, "1000_1099.tar.gz"),
+	}, files)
+
+	// the entry sizes claimed by the archive aren't trusted
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "1000", Mode: 0600, Size: blockArchiveMaxEntryBytes + 1}))
+	_, err = tarWriter.Write(make([]byte, blockArchiveMaxEntryBytes+1))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	_, err = target.ImportBlockArchive(&buf)
+	require.Error(t, err)
+	require.Equal(t, basics.Round(999), target.Latest())
+}
+
+func TestBlockPrunerVacuum(t *testing.T) {
+	for name, open := range testBlockStores(t) {
+		t.Run(name, func(t *testing.T) {
+			bs := makePrunerTestStore(t, open, 2999)
+			defer bs.close()
+
+			// vacuuming is disabled by default
+			bp := makeBlockPruner(bs, logging.TestingLog(t), blockRetention{})
+			defer bp.close()
+			require.NoError(t, bp.prune(2999, 1000))
+			require.Equal(t, uint64(1000), bp.prunedSinceVacuum)
+
+			bp.retention.vacuumRounds = 1500
+			require.NoError(t, bp.prune(2999, 2000))
+			require.Equal(t, uint64(0), bp.prunedSinceVacuum)
+			earliest, err := bs.earliest()
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(2000), earliest)
+		})
+	}
+}
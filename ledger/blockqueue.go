@@ -17,8 +17,6 @@
 package ledger
 
 import (
-	"context"
-	"database/sql"
 	"fmt"
 	"sync"
 	"time"
@@ -42,6 +40,9 @@ type blockEntry struct {
 type blockQueue struct {
 	l *Ledger
 
+	// pruner deletes the blocks that are no longer needed once they're committed
+	pruner *blockPruner
+
 	lastCommitted basics.Round
 	q             []blockEntry
 
@@ -59,16 +60,14 @@ func bqInit(l *Ledger) (*blockQueue, error) {
 	bq.closed = make(chan struct{})
 	ledgerBlockqInitCount.Inc(nil)
 	start := time.Now()
-	err := bq.l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		var err0 error
-		bq.lastCommitted, err0 = blockLatest(tx)
-		return err0
-	})
+	var err error
+	bq.lastCommitted, err = bq.l.blocks.latest()
 	ledgerBlockqInitMicros.AddMicrosecondsSince(start, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	bq.pruner = makeBlockPruner(l.blocks, l.log, l.blockRetention)
 	go bq.syncer()
 	return bq, nil
 }
@@ -82,6 +81,7 @@ func (bq *blockQueue) close() {
 		// to ensure that the sync goroutine isn't busy in a notifyCommit
 		// call which might be blocked inside one of the trackers.
 		<-bq.closed
+		bq.pruner.close()
 	}()
 
 	if bq.running {
@@ -109,15 +109,7 @@ func (bq *blockQueue) syncer() {
 
 		start := time.Now()
 		ledgerSyncBlockputCount.Inc(nil)
-		err := bq.l.blockDBs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-			for _, e := range workQ {
-				err0 := blockPut(tx, e.block, e.cert)
-				if err0 != nil {
-					return err0
-				}
-			}
-			return nil
-		})
+		err := bq.l.blocks.put(workQ)
 		ledgerSyncBlockputMicros.AddMicrosecondsSince(start, nil)
 
 		bq.mu.Lock()
@@ -143,15 +135,7 @@ func (bq *blockQueue) syncer() {
 			bq.mu.Unlock()
 
 			minToSave := bq.l.notifyCommit(committed)
-			bfstart := time.Now()
-			ledgerSyncBlockforgetCount.Inc(nil)
-			err = bq.l.blockDBs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-				return blockForgetBefore(tx, minToSave)
-			})
-			ledgerSyncBlockforgetMicros.AddMicrosecondsSince(bfstart, nil)
-			if err != nil {
-				bq.l.log.Warnf("blockQueue.syncer: blockForgetBefore(%d): %v", minToSave, err)
-			}
+			bq.pruner.notifyCommit(committed, minToSave)
 
 			bq.mu.Lock()
 		}
@@ -259,11 +243,7 @@ func (bq *blockQueue) getBlock(r basics.Round) (blk bookkeeping.Block, err error
 
 	start := time.Now()
 	ledgerGetblockCount.Inc(nil)
-	err = bq.l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		var err0 error
-		blk, err0 = blockGet(tx, r)
-		return err0
-	})
+	blk, err = bq.l.blocks.get(r)
 	ledgerGetblockMicros.AddMicrosecondsSince(start, nil)
 	err = updateErrNoEntry(err, lastCommitted, latest)
 	return
@@ -281,11 +261,7 @@ func (bq *blockQueue) getBlockHdr(r basics.Round) (hdr bookkeeping.BlockHeader,
 
 	start := time.Now()
 	ledgerGetblockhdrCount.Inc(nil)
-	err = bq.l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		var err0 error
-		hdr, err0 = blockGetHdr(tx, r)
-		return err0
-	})
+	hdr, err = bq.l.blocks.getHdr(r)
 	ledgerGetblockhdrMicros.AddMicrosecondsSince(start, nil)
 	err = updateErrNoEntry(err, lastCommitted, latest)
 	return
@@ -307,11 +283,7 @@ func (bq *blockQueue) getEncodedBlockCert(r basics.Round) (blk []byte, cert []by
 
 	start := time.Now()
 	ledgerGeteblockcertCount.Inc(nil)
-	err = bq.l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		var err0 error
-		blk, cert, err0 = blockGetEncodedCert(tx, r)
-		return err0
-	})
+	blk, cert, err = bq.l.blocks.getEncodedCert(r)
 	ledgerGeteblockcertMicros.AddMicrosecondsSince(start, nil)
 	err = updateErrNoEntry(err, lastCommitted, latest)
 	return
@@ -329,11 +301,7 @@ func (bq *blockQueue) getBlockCert(r basics.Round) (blk bookkeeping.Block, cert
 
 	start := time.Now()
 	ledgerGetblockcertCount.Inc(nil)
-	err = bq.l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		var err0 error
-		blk, cert, err0 = blockGetCert(tx, r)
-		return err0
-	})
+	blk, cert, err = bq.l.blocks.getCert(r)
 	ledgerGetblockcertMicros.AddMicrosecondsSince(start, nil)
 	err = updateErrNoEntry(err, lastCommitted, latest)
 	return
@@ -0,0 +1,242 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// Storage backends for the ledger blocks, as set by the BlockStorageBackend config option
+const (
+	// StorageBackendSQLite keeps the blocks in a SQLite database
+	StorageBackendSQLite = "sqlite"
+	// StorageBackendKV keeps the blocks in an embedded key-value store
+	StorageBackendKV = "kv"
+)
+
+// blockStore is the storage of the blocks of the ledger and of their certificates. The ledger and the catchpoint
+// catchup only access the blocks through it, so that they can be kept either in the SQLite blocks database or in
+// a key-value store.
+type blockStore interface {
+	// initialize creates the storage if needed and writes initBlocks to it if it has no blocks. In archival mode,
+	// the storage is reset first if it doesn't start at round 0, since an archival node needs all the blocks.
+	initialize(initBlocks []bookkeeping.Block, isArchival bool, log logging.Logger) error
+
+	get(rnd basics.Round) (bookkeeping.Block, error)
+	getHdr(rnd basics.Round) (bookkeeping.BlockHeader, error)
+	getEncodedCert(rnd basics.Round) (blk []byte, cert []byte, err error)
+	getCert(rnd basics.Round) (bookkeeping.Block, agreement.Certificate, error)
+
+	// put writes the given blocks atomically. The first one has to follow the latest block in the storage.
+	put(entries []blockEntry) error
+	latest() (basics.Round, error)
+	earliest() (basics.Round, error)
+	// forgetBefore deletes the blocks before rnd
+	forgetBefore(rnd basics.Round) error
+
+	// the catchpoint catchup writes the blocks it downloads to a staging area, which replaces the blocks once
+	// the catchup completes.
+	startCatchupStaging(blk bookkeeping.Block) error
+	putStaging(blk bookkeeping.Block) error
+	ensureSingleBlock() (bookkeeping.Block, error)
+	completeCatchup() error
+	abortCatchup() error
+
+	// vacuum releases the space taken by the deleted blocks
+	vacuum(ctx context.Context) error
+	setSynchronousMode(ctx context.Context, synchronousMode db.SynchronousMode) error
+	close()
+}
+
+// openBlockStore opens the block storage of the given backend, using a filename based on dbPathPrefix
+func openBlockStore(backend string, dbPathPrefix string, dbMem bool) (blockStore, error) {
+	switch backend {
+	case StorageBackendSQLite, "":
+		blockDBs, err := db.OpenPair(dbPathPrefix+".block.sqlite", dbMem)
+		if err != nil {
+			return nil, err
+		}
+		return &sqliteBlockStore{dbs: blockDBs}, nil
+	case StorageBackendKV:
+		return openKVBlockStore(dbPathPrefix+".block.kv", dbMem)
+	default:
+		return nil, fmt.Errorf("unknown block storage backend '%s'", backend)
+	}
+}
+
+// sqliteBlockStore is the blockStore of the SQLite blocks database, implemented in blockdb.go
+type sqliteBlockStore struct {
+	dbs db.Pair
+}
+
+func (bs *sqliteBlockStore) initialize(initBlocks []bookkeeping.Block, isArchival bool, log logging.Logger) error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		err := blockInit(tx, initBlocks)
+		if err != nil {
+			return fmt.Errorf("initBlocksDB.blockInit %v", err)
+		}
+
+		// in archival mode check if DB contains all blocks up to the latest
+		if isArchival {
+			earliest, err := blockEarliest(tx)
+			if err != nil {
+				return fmt.Errorf("initBlocksDB.blockEarliest %v", err)
+			}
+
+			// Detect possible problem - archival node needs all block but have only subsequence of them
+			// So reset the DB and init it again
+			if earliest != basics.Round(0) {
+				log.Warnf("resetting blocks DB (earliest block is %v)", earliest)
+				err := blockResetDB(tx)
+				if err != nil {
+					return fmt.Errorf("initBlocksDB.blockResetDB %v", err)
+				}
+				err = blockInit(tx, initBlocks)
+				if err != nil {
+					return fmt.Errorf("initBlocksDB.blockInit 2 %v", err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (bs *sqliteBlockStore) get(rnd basics.Round) (blk bookkeeping.Block, err error) {
+	err = bs.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		blk, err0 = blockGet(tx, rnd)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) getHdr(rnd basics.Round) (hdr bookkeeping.BlockHeader, err error) {
+	err = bs.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		hdr, err0 = blockGetHdr(tx, rnd)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) getEncodedCert(rnd basics.Round) (blk []byte, cert []byte, err error) {
+	err = bs.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		blk, cert, err0 = blockGetEncodedCert(tx, rnd)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) getCert(rnd basics.Round) (blk bookkeeping.Block, cert agreement.Certificate, err error) {
+	err = bs.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		blk, cert, err0 = blockGetCert(tx, rnd)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) put(entries []blockEntry) error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		for _, e := range entries {
+			err := blockPut(tx, e.block, e.cert)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (bs *sqliteBlockStore) latest() (rnd basics.Round, err error) {
+	err = bs.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		rnd, err0 = blockLatest(tx)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) earliest() (rnd basics.Round, err error) {
+	err = bs.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		rnd, err0 = blockEarliest(tx)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) forgetBefore(rnd basics.Round) error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return blockForgetBefore(tx, rnd)
+	})
+}
+
+func (bs *sqliteBlockStore) startCatchupStaging(blk bookkeeping.Block) error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return blockStartCatchupStaging(tx, blk)
+	})
+}
+
+func (bs *sqliteBlockStore) putStaging(blk bookkeeping.Block) error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return blockPutStaging(tx, blk)
+	})
+}
+
+func (bs *sqliteBlockStore) ensureSingleBlock() (blk bookkeeping.Block, err error) {
+	err = bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		var err0 error
+		blk, err0 = blockEnsureSingleBlock(tx)
+		return err0
+	})
+	return
+}
+
+func (bs *sqliteBlockStore) completeCatchup() error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return blockCompleteCatchup(tx)
+	})
+}
+
+func (bs *sqliteBlockStore) abortCatchup() error {
+	return bs.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return blockAbortCatchup(tx)
+	})
+}
+
+func (bs *sqliteBlockStore) vacuum(ctx context.Context) error {
+	_, err := bs.dbs.Wdb.Vacuum(ctx)
+	return err
+}
+
+func (bs *sqliteBlockStore) setSynchronousMode(ctx context.Context, synchronousMode db.SynchronousMode) error {
+	return bs.dbs.Wdb.SetSynchronousMode(ctx, synchronousMode, synchronousMode >= db.SynchronousModeFull)
+}
+
+func (bs *sqliteBlockStore) close() {
+	bs.dbs.Close()
+}
@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+)
+
+// GetBox looks up the box with the given name owned by application aidx
+func (cb *roundCowState) GetBox(aidx basics.AppIndex, name string) (string, bool, error) {
+	return cb.getBox(ledgercore.MakeBoxKey(aidx, name))
+}
+
+// SetBox creates or overwrites the box with the given name owned by
+// application aidx. The box counts stored in the application account are
+// updated, so that the MinBalance check done at the end of the transaction
+// accounts for the storage used.
+func (cb *roundCowState) SetBox(aidx basics.AppIndex, name string, value string) error {
+	if len(name) == 0 || len(name) > cb.proto.MaxAppKeyLen {
+		return fmt.Errorf("box names must be between 1 and %d bytes long, got %d", cb.proto.MaxAppKeyLen, len(name))
+	}
+	if uint64(len(value)) > cb.proto.MaxBoxSize {
+		return fmt.Errorf("box size %d exceeds maximum of %d", len(value), cb.proto.MaxBoxSize)
+	}
+
+	key := ledgercore.MakeBoxKey(aidx, name)
+	old, exists, err := cb.getBox(key)
+	if err != nil {
+		return err
+	}
+
+	appAddr := aidx.Address()
+	record, err := cb.lookup(appAddr)
+	if err != nil {
+		return err
+	}
+	if exists {
+		record.TotalBoxBytes -= uint64(len(old))
+	} else {
+		record.TotalBoxes++
+		record.TotalBoxBytes += uint64(len(name))
+	}
+	record.TotalBoxBytes += uint64(len(value))
+	cb.mods.Accts.Upsert(appAddr, record)
+
+	cb.mods.Boxes[key] = ledgercore.ModifiedBox{Value: &value}
+	return nil
+}
+
+// DelBox deletes the box with the given name owned by application aidx,
+// returning whether such a box existed
+func (cb *roundCowState) DelBox(aidx basics.AppIndex, name string) (bool, error) {
+	key := ledgercore.MakeBoxKey(aidx, name)
+	old, exists, err := cb.getBox(key)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	appAddr := aidx.Address()
+	record, err := cb.lookup(appAddr)
+	if err != nil {
+		return false, err
+	}
+	if record.TotalBoxes == 0 || record.TotalBoxBytes < uint64(len(name)+len(old)) {
+		return false, fmt.Errorf("inconsistent box accounting for app %d: %d boxes, %d bytes", aidx, record.TotalBoxes, record.TotalBoxBytes)
+	}
+	record.TotalBoxes--
+	record.TotalBoxBytes -= uint64(len(name) + len(old))
+	cb.mods.Accts.Upsert(appAddr, record)
+
+	cb.mods.Boxes[key] = ledgercore.ModifiedBox{Value: nil}
+	return true, nil
+}
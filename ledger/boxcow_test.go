@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestBoxAccounting(t *testing.T) {
+	ml := mockLedger{balanceMap: map[basics.Address]basics.AccountData{}}
+	hdr := bookkeeping.BlockHeader{}
+	hdr.CurrentProtocol = protocol.ConsensusFuture
+	proto := config.Consensus[hdr.CurrentProtocol]
+
+	aidx := basics.AppIndex(7)
+	appAddr := aidx.Address()
+	c0 := makeRoundCowState(&ml, hdr, 0, 0)
+	c1 := c0.child(0)
+
+	require.NoError(t, c1.SetBox(aidx, "box", "value"))
+	_, exists, err := c0.GetBox(aidx, "box")
+	require.NoError(t, err)
+	require.False(t, exists)
+	value, exists, err := c1.GetBox(aidx, "box")
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, "value", value)
+
+	// overwriting a box only changes the byte count
+	require.NoError(t, c1.SetBox(aidx, "box", "longer value"))
+	record, err := c1.lookup(appAddr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), record.TotalBoxes)
+	require.Equal(t, uint64(len("box")+len("longer value")), record.TotalBoxBytes)
+	require.Equal(t,
+		proto.MinBalance+proto.BoxFlatMinBalance+proto.BoxByteMinBalance*record.TotalBoxBytes,
+		record.MinBalance(&proto).Raw)
+
+	require.NoError(t, c1.SetBox(aidx, "other", ""))
+	c1.commitToParent()
+	record, err = c0.lookup(appAddr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), record.TotalBoxes)
+	require.Len(t, c0.mods.Boxes, 2)
+
+	existed, err := c0.DelBox(aidx, "box")
+	require.NoError(t, err)
+	require.True(t, existed)
+	existed, err = c0.DelBox(aidx, "box")
+	require.NoError(t, err)
+	require.False(t, existed)
+	record, err = c0.lookup(appAddr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), record.TotalBoxes)
+	require.Equal(t, uint64(len("other")), record.TotalBoxBytes)
+	require.Nil(t, c0.mods.Boxes[ledgercore.MakeBoxKey(aidx, "box")].Value)
+
+	// names and sizes are bounded
+	require.Error(t, c0.SetBox(aidx, "", "value"))
+	require.Error(t, c0.SetBox(aidx, strings.Repeat("x", proto.MaxAppKeyLen+1), "value"))
+	require.Error(t, c0.SetBox(aidx, "big", strings.Repeat("x", int(proto.MaxBoxSize)+1)))
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+const (
+	// CatchpointDownloadChunkSize is the size of the chunks a catchpoint file is split into when it's served to
+	// the nodes downloading it from several peers in parallel.
+	CatchpointDownloadChunkSize = 1024 * 1024
+
+	// MaxCatchpointDownloadChunkSize is the largest download chunk size a node would accept.
+	MaxCatchpointDownloadChunkSize = 16 * 1024 * 1024
+
+	// MaxCatchpointDownloadChunks is the maximal number of download chunks a catchpoint file could be split into.
+	MaxCatchpointDownloadChunks = 64 * 1024
+
+	// catchpointChunksFileSuffix is appended to the name of a catchpoint file to get the name of the file its
+	// download chunks are stored in.
+	catchpointChunksFileSuffix = ".chunks"
+)
+
+// CatchpointDownloadChunks describes how a catchpoint file is split into download chunks, and lists the hash of
+// each one of them, so that chunks fetched from different peers could be verified independently of each other.
+// All the chunks have the same size, except for the last one which holds the remainder of the file.
+type CatchpointDownloadChunks struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	FileSize  uint64          `codec:"size"`
+	ChunkSize uint64          `codec:"chunksize"`
+	Hashes    []crypto.Digest `codec:"hashes,allocbound=MaxCatchpointDownloadChunks"`
+}
+
+// MakeCatchpointDownloadChunks reads the catchpoint file from r and hashes each chunkSize bytes of it.
+func MakeCatchpointDownloadChunks(r io.Reader, chunkSize uint64) (chunks CatchpointDownloadChunks, err error) {
+	if chunkSize == 0 || chunkSize > MaxCatchpointDownloadChunkSize {
+		return CatchpointDownloadChunks{}, fmt.Errorf("invalid catchpoint download chunk size %d", chunkSize)
+	}
+	chunks.ChunkSize = chunkSize
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if len(chunks.Hashes) >= MaxCatchpointDownloadChunks {
+				return CatchpointDownloadChunks{}, fmt.Errorf("catchpoint file is larger than %d chunks of %d bytes", MaxCatchpointDownloadChunks, chunkSize)
+			}
+			chunks.Hashes = append(chunks.Hashes, crypto.Hash(buf[:n]))
+			chunks.FileSize += uint64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return CatchpointDownloadChunks{}, err
+		}
+	}
+}
+
+// Validate checks that the chunks cover the whole file, and that they are within the limits a node would download.
+func (c *CatchpointDownloadChunks) Validate() error {
+	if c.ChunkSize == 0 || c.ChunkSize > MaxCatchpointDownloadChunkSize {
+		return fmt.Errorf("invalid catchpoint download chunk size %d", c.ChunkSize)
+	}
+	if len(c.Hashes) > MaxCatchpointDownloadChunks {
+		return fmt.Errorf("catchpoint file has %d download chunks, exceeding the limit of %d", len(c.Hashes), MaxCatchpointDownloadChunks)
+	}
+	expectedChunks := (c.FileSize + c.ChunkSize - 1) / c.ChunkSize
+	if uint64(len(c.Hashes)) != expectedChunks {
+		return fmt.Errorf("catchpoint file of %d bytes has %d download chunks of %d bytes, rather than %d", c.FileSize, len(c.Hashes), c.ChunkSize, expectedChunks)
+	}
+	return nil
+}
+
+// Chunk returns the offset and the size of the given chunk in the catchpoint file.
+func (c *CatchpointDownloadChunks) Chunk(chunk int) (offset uint64, size uint64) {
+	offset = uint64(chunk) * c.ChunkSize
+	size = c.ChunkSize
+	if offset+size > c.FileSize {
+		size = c.FileSize - offset
+	}
+	return
+}
+
+// VerifyChunk verifies that data is the content of the given chunk.
+func (c *CatchpointDownloadChunks) VerifyChunk(chunk int, data []byte) error {
+	if chunk < 0 || chunk >= len(c.Hashes) {
+		return fmt.Errorf("catchpoint download chunk %d is out of range [0..%d)", chunk, len(c.Hashes))
+	}
+	if _, size := c.Chunk(chunk); uint64(len(data)) != size {
+		return fmt.Errorf("catchpoint download chunk %d has %d bytes rather than %d", chunk, len(data), size)
+	}
+	if crypto.Hash(data) != c.Hashes[chunk] {
+		return fmt.Errorf("catchpoint download chunk %d hash mismatch", chunk)
+	}
+	return nil
+}
+
+// Digest returns a digest of the chunks description. Peers serving the same catchpoint file, split the same way,
+// return the same digest.
+func (c *CatchpointDownloadChunks) Digest() crypto.Digest {
+	return crypto.Hash(protocol.Encode(c))
+}
+
+// writeCatchpointDownloadChunks hashes the catchpoint file at catchpointPath, and stores its download chunks next to it.
+func writeCatchpointDownloadChunks(catchpointPath string) (chunks CatchpointDownloadChunks, err error) {
+	file, err := os.Open(catchpointPath)
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	defer file.Close()
+	chunks, err = MakeCatchpointDownloadChunks(file, CatchpointDownloadChunkSize)
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	chunksPath := catchpointPath + catchpointChunksFileSuffix
+	tmpChunksPath := chunksPath + ".tmp"
+	err = ioutil.WriteFile(tmpChunksPath, protocol.Encode(&chunks), 0600)
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	err = os.Rename(tmpChunksPath, chunksPath)
+	if err != nil {
+		os.Remove(tmpChunksPath)
+		return CatchpointDownloadChunks{}, err
+	}
+	return chunks, nil
+}
+
+// readCatchpointDownloadChunks reads the download chunks stored next to the catchpoint file at catchpointPath,
+// making sure they still describe the file, which has the given size.
+func readCatchpointDownloadChunks(catchpointPath string, fileSize int64) (chunks CatchpointDownloadChunks, err error) {
+	data, err := ioutil.ReadFile(catchpointPath + catchpointChunksFileSuffix)
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	err = protocol.Decode(data, &chunks)
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	err = chunks.Validate()
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	if chunks.FileSize != uint64(fileSize) || chunks.ChunkSize != CatchpointDownloadChunkSize {
+		return CatchpointDownloadChunks{}, fmt.Errorf("download chunks of %s are out of date", catchpointPath)
+	}
+	return chunks, nil
+}
+
+// catchpointChunksWriter hashes the catchpoint files that don't have their download chunks stored yet, such as the
+// ones written by an older version. Each file is hashed once, even if its chunks are requested by many peers at
+// the same time.
+type catchpointChunksWriter struct {
+	mu deadlock.Mutex
+	// hashing holds the catchpoint files being hashed, with a channel that is closed once the hashing is done.
+	hashing map[string]chan struct{}
+}
+
+// get returns the download chunks of the catchpoint file at catchpointPath, which has the given size.
+func (w *catchpointChunksWriter) get(catchpointPath string, fileSize int64) (CatchpointDownloadChunks, error) {
+	for {
+		chunks, err := readCatchpointDownloadChunks(catchpointPath, fileSize)
+		if err == nil {
+			return chunks, nil
+		}
+
+		w.mu.Lock()
+		if done, ok := w.hashing[catchpointPath]; ok {
+			w.mu.Unlock()
+			// wait for the other request to store the chunks, and read them.
+			<-done
+			continue
+		}
+		if w.hashing == nil {
+			w.hashing = make(map[string]chan struct{})
+		}
+		done := make(chan struct{})
+		w.hashing[catchpointPath] = done
+		w.mu.Unlock()
+
+		chunks, err = writeCatchpointDownloadChunks(catchpointPath)
+
+		w.mu.Lock()
+		delete(w.hashing, catchpointPath)
+		close(done)
+		w.mu.Unlock()
+		return chunks, err
+	}
+}
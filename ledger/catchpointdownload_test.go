@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/crypto"
+)
+
+func TestCatchpointDownloadChunks(t *testing.T) {
+	file := make([]byte, 2500)
+	crypto.RandBytes(file)
+
+	chunks, err := MakeCatchpointDownloadChunks(bytes.NewReader(file), 1000)
+	require.NoError(t, err)
+	require.NoError(t, chunks.Validate())
+	require.Equal(t, uint64(len(file)), chunks.FileSize)
+	require.Len(t, chunks.Hashes, 3)
+
+	for i := range chunks.Hashes {
+		offset, size := chunks.Chunk(i)
+		require.Equal(t, uint64(i*1000), offset)
+		data := file[offset : offset+size]
+		require.NoError(t, chunks.VerifyChunk(i, data))
+	}
+	_, size := chunks.Chunk(2)
+	require.Equal(t, uint64(500), size)
+
+	// corrupted, truncated and out of range chunks are rejected
+	corrupted := append([]byte{}, file[:1000]...)
+	corrupted[0]++
+	require.Error(t, chunks.VerifyChunk(0, corrupted))
+	require.Error(t, chunks.VerifyChunk(1, file[1000:1999]))
+	require.Error(t, chunks.VerifyChunk(3, file[:1000]))
+
+	// the digest depends on how the file is split
+	otherChunks, err := MakeCatchpointDownloadChunks(bytes.NewReader(file), 1000)
+	require.NoError(t, err)
+	require.Equal(t, chunks.Digest(), otherChunks.Digest())
+	otherChunks, err = MakeCatchpointDownloadChunks(bytes.NewReader(file), 500)
+	require.NoError(t, err)
+	require.NotEqual(t, chunks.Digest(), otherChunks.Digest())
+
+	// a description which doesn't cover the file isn't valid
+	chunks.FileSize += 1000
+	require.Error(t, chunks.Validate())
+	chunks.ChunkSize = 0
+	require.Error(t, chunks.Validate())
+
+	_, err = MakeCatchpointDownloadChunks(bytes.NewReader(file), MaxCatchpointDownloadChunkSize+1)
+	require.Error(t, err)
+}
+
+func TestCatchpointChunksWriter(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "catchpointchunks")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	catchpointPath := filepath.Join(tempDir, "1000.catchpoint")
+	file := make([]byte, 2*CatchpointDownloadChunkSize+100)
+	crypto.RandBytes(file)
+	require.NoError(t, ioutil.WriteFile(catchpointPath, file, 0600))
+	expected, err := MakeCatchpointDownloadChunks(bytes.NewReader(file), CatchpointDownloadChunkSize)
+	require.NoError(t, err)
+
+	// a catchpoint file without stored chunks is hashed once, however many requests ask for it at once
+	var w catchpointChunksWriter
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			chunks, err := w.get(catchpointPath, int64(len(file)))
+			require.NoError(t, err)
+			require.Equal(t, expected.Digest(), chunks.Digest())
+		}()
+	}
+	wg.Wait()
+	require.Empty(t, w.hashing)
+	stored, err := readCatchpointDownloadChunks(catchpointPath, int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, expected.Digest(), stored.Digest())
+
+	// the stored chunks are used as long as they describe the file
+	require.NoError(t, os.Remove(catchpointPath))
+	chunks, err := w.get(catchpointPath, int64(len(file)))
+	require.NoError(t, err)
+	require.Equal(t, expected.Digest(), chunks.Digest())
+
+	// and the file is hashed again once it changes
+	file = file[:CatchpointDownloadChunkSize]
+	require.NoError(t, ioutil.WriteFile(catchpointPath, file, 0600))
+	_, err = readCatchpointDownloadChunks(catchpointPath, int64(len(file)))
+	require.Error(t, err)
+	chunks, err = w.get(catchpointPath, int64(len(file)))
+	require.NoError(t, err)
+	require.Len(t, chunks.Hashes, 1)
+	require.Equal(t, crypto.Hash(file), chunks.Hashes[0])
+}
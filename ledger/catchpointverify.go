@@ -0,0 +1,318 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// CatchpointOffendingAccount is an account of a catchpoint file that failed the verification, along with the reason
+type CatchpointOffendingAccount struct {
+	Address basics.Address
+	Reason  string
+}
+
+// CatchpointVerificationReport is the outcome of the verification of a catchpoint file by VerifyCatchpointFile
+type CatchpointVerificationReport struct {
+	// FileHeader is the content of the catchpoint file header
+	FileHeader CatchpointFileHeader
+
+	// BalancesRoot is the root of the merkle trie rebuilt from the catchpoint file
+	BalancesRoot crypto.Digest
+	// Totals are the account totals recomputed from the accounts of the catchpoint file
+	Totals ledgercore.AccountTotals
+	// Label is the catchpoint label recomputed from the block header, the merkle trie root and the file totals
+	Label string
+
+	// the number of accounts, resources and boxes found in the catchpoint file
+	Accounts  uint64
+	Resources uint64
+	Boxes     uint64
+
+	// Mismatches describes every value of the catchpoint file that doesn't match the recomputed one
+	Mismatches []string
+	// OffendingAccounts are the accounts of the catchpoint file that aren't valid on their own
+	OffendingAccounts []CatchpointOffendingAccount
+}
+
+// Valid returns true if the verification found neither mismatches nor offending accounts
+func (r *CatchpointVerificationReport) Valid() bool {
+	return len(r.Mismatches) == 0 && len(r.OffendingAccounts) == 0
+}
+
+func (r *CatchpointVerificationReport) mismatchf(format string, args ...interface{}) {
+	r.Mismatches = append(r.Mismatches, fmt.Sprintf(format, args...))
+}
+
+func (r *CatchpointVerificationReport) offending(addr basics.Address, format string, args ...interface{}) {
+	r.OffendingAccounts = append(r.OffendingAccounts, CatchpointOffendingAccount{Address: addr, Reason: fmt.Sprintf(format, args...)})
+}
+
+// VerifyCatchpointFile streams the catchpoint file read from catchpointFile into the catchpoint staging tables of the
+// given ledger, rebuilds the merkle trie the same way the catchpoint catchup does, and checks the file against hdr,
+// the header of the block the catchpoint was generated for. The catchpoint file can either be the gzipped tar
+// written by the catchpoint writer or the plain tar served to the nodes catching up.
+//
+// The ledger is only used for its tracker database, and its catchpoint staging tables are reset in the process.
+// An error is returned if the catchpoint file couldn't be processed at all; otherwise, what doesn't match is listed
+// in the returned report.
+func VerifyCatchpointFile(ctx context.Context, l *Ledger, catchpointFile io.Reader, hdr bookkeeping.BlockHeader) (report CatchpointVerificationReport, err error) {
+	proto, ok := config.Consensus[hdr.CurrentProtocol]
+	if !ok {
+		return report, fmt.Errorf("unsupported protocol %s in the header of block %d", hdr.CurrentProtocol, hdr.Round)
+	}
+
+	accessor := MakeCatchpointCatchupAccessor(l, l.log)
+	if accessor == nil {
+		return report, fmt.Errorf("unable to create a catchpoint catchup accessor")
+	}
+	err = accessor.ResetStagingBalances(ctx, true)
+	if err != nil {
+		return
+	}
+	defer accessor.ResetStagingBalances(ctx, false)
+
+	report.FileHeader, err = loadCatchpointFile(ctx, accessor, catchpointFile)
+	if err != nil {
+		return
+	}
+	err = accessor.BuildMerkleTrie(ctx, nil)
+	if err != nil {
+		return
+	}
+
+	report.Totals.RewardsLevel = report.FileHeader.Totals.RewardsLevel
+	rdb := l.trackerDB().Rdb
+	err = rdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		// walking over all the accounts can take a while.
+		db.ResetTransactionWarnDeadline(ctx, tx, time.Now().Add(time.Hour))
+
+		mc, err := MakeMerkleCommitter(tx, true)
+		if err != nil {
+			return
+		}
+		trie, err := merkletrie.MakeTrie(mc, TrieMemoryConfig)
+		if err != nil {
+			return
+		}
+		report.BalancesRoot, err = trie.RootHash()
+		if err != nil {
+			return
+		}
+		return verifyCatchpointStagingAccounts(tx, proto, &report)
+	})
+	if err != nil {
+		return
+	}
+
+	fileHeader := report.FileHeader
+	if fileHeader.BlocksRound != hdr.Round {
+		report.mismatchf("blocks round: file has %d, block header has %d", fileHeader.BlocksRound, hdr.Round)
+	}
+	blockDigest := crypto.Digest(hdr.Hash())
+	if fileHeader.BlockHeaderDigest != blockDigest {
+		report.mismatchf("block header digest: file has %v, block header has %v", fileHeader.BlockHeaderDigest, blockDigest)
+	}
+	if uint64(hdr.Round) >= proto.MaxBalLookback {
+		balancesRound := hdr.Round - basics.Round(proto.MaxBalLookback)
+		if fileHeader.BalancesRound != balancesRound {
+			report.mismatchf("balances round: file has %d, expected %d", fileHeader.BalancesRound, balancesRound)
+		}
+	}
+	if fileHeader.TotalAccounts != report.Accounts {
+		report.mismatchf("accounts count: file header has %d, file contains %d", fileHeader.TotalAccounts, report.Accounts)
+	}
+	if fileHeader.TotalResources != report.Resources {
+		report.mismatchf("resources count: file header has %d, file contains %d", fileHeader.TotalResources, report.Resources)
+	}
+	if fileHeader.TotalBoxes != report.Boxes {
+		report.mismatchf("boxes count: file header has %d, file contains %d", fileHeader.TotalBoxes, report.Boxes)
+	}
+	compareAlgoCount := func(name string, file ledgercore.AlgoCount, computed ledgercore.AlgoCount) {
+		if file.Money != computed.Money {
+			report.mismatchf("%s money: file has %d, accounts add up to %d", name, file.Money.Raw, computed.Money.Raw)
+		}
+		if file.RewardUnits != computed.RewardUnits {
+			report.mismatchf("%s reward units: file has %d, accounts add up to %d", name, file.RewardUnits, computed.RewardUnits)
+		}
+	}
+	compareAlgoCount("online", fileHeader.Totals.Online, report.Totals.Online)
+	compareAlgoCount("offline", fileHeader.Totals.Offline, report.Totals.Offline)
+	compareAlgoCount("not participating", fileHeader.Totals.NotParticipating, report.Totals.NotParticipating)
+
+	report.Label = ledgercore.MakeCatchpointLabel(hdr.Round, blockDigest, report.BalancesRoot, fileHeader.Totals).String()
+	if fileHeader.Catchpoint != report.Label {
+		report.mismatchf("catchpoint label: file has %s, calculated %s", fileHeader.Catchpoint, report.Label)
+	}
+	return
+}
+
+// loadCatchpointFile passes every section of the catchpoint file to the accessor, and returns the file header
+func loadCatchpointFile(ctx context.Context, accessor CatchpointCatchupAccessor, catchpointFile io.Reader) (fileHeader CatchpointFileHeader, err error) {
+	reader := bufio.NewReader(catchpointFile)
+	// the catchpoint writer compresses the file, while the nodes serving it decompress it on the fly.
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return fileHeader, err
+		}
+		defer gzipReader.Close()
+		catchpointFile = gzipReader
+	} else {
+		catchpointFile = reader
+	}
+
+	tarReader := tar.NewReader(catchpointFile)
+	var progress CatchpointCatchupAccessorProgress
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return fileHeader, err
+		}
+		section, err := ioutil.ReadAll(tarReader)
+		if err != nil {
+			return fileHeader, err
+		}
+		err = accessor.ProgressStagingBalances(ctx, header.Name, section, &progress)
+		if err != nil {
+			return fileHeader, fmt.Errorf("unable to process section '%s' : %v", header.Name, err)
+		}
+		if header.Name == "content.msgpack" {
+			// the accessor already validated it.
+			protocol.Decode(section, &fileHeader)
+		}
+	}
+	if !progress.SeenHeader {
+		return fileHeader, fmt.Errorf("the catchpoint file has no content.msgpack section")
+	}
+	return
+}
+
+// verifyCatchpointStagingAccounts counts the entries of the catchpoint staging tables, adds up the account totals
+// and looks for accounts that can't be part of a valid catchpoint.
+func verifyCatchpointStagingAccounts(tx *sql.Tx, proto config.ConsensusParams, report *CatchpointVerificationReport) error {
+	err := tx.QueryRow("SELECT count(*) FROM catchpointbalances").Scan(&report.Accounts)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRow("SELECT count(*) FROM catchpointresources").Scan(&report.Resources)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRow("SELECT count(*) FROM catchpointkvstore").Scan(&report.Boxes)
+	if err != nil {
+		return err
+	}
+
+	selectResourcesStmt, err := tx.Prepare("SELECT aidx, rtype, data FROM catchpointresources WHERE address=?")
+	if err != nil {
+		return err
+	}
+	defer selectResourcesStmt.Close()
+
+	rows, err := tx.Query("SELECT address, data FROM catchpointbalances ORDER BY address")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var ot basics.OverflowTracker
+	for rows.Next() {
+		var addrbuf []byte
+		var buf []byte
+		err = rows.Scan(&addrbuf, &buf)
+		if err != nil {
+			return err
+		}
+		var addr basics.Address
+		if len(addrbuf) != len(addr) {
+			return fmt.Errorf("catchpoint staging address length mismatch: %d != %d", len(addrbuf), len(addr))
+		}
+		copy(addr[:], addrbuf)
+
+		var data basics.AccountData
+		err = protocol.Decode(buf, &data)
+		if err != nil {
+			report.offending(addr, "undecodable account data: %v", err)
+			continue
+		}
+		err = loadAccountResources(selectResourcesStmt, addr, &data)
+		if err != nil {
+			report.offending(addr, "undecodable resources: %v", err)
+			continue
+		}
+
+		if data.IsZero() {
+			report.offending(addr, "empty account")
+			continue
+		}
+		if data.Status != basics.Offline && data.Status != basics.Online && data.Status != basics.NotParticipating {
+			report.offending(addr, "unknown status %d", data.Status)
+			continue
+		}
+		if data.RewardsBase > report.Totals.RewardsLevel {
+			report.offending(addr, "rewards base %d is ahead of the rewards level %d", data.RewardsBase, report.Totals.RewardsLevel)
+			continue
+		}
+		report.Totals.AddAccount(proto, data, &ot)
+		if ot.Overflowed {
+			report.offending(addr, "account totals overflow when adding %d microalgos", data.MicroAlgos.Raw)
+			ot.Overflowed = false
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+
+	orphans, err := tx.Query("SELECT DISTINCT address FROM catchpointresources WHERE address NOT IN (SELECT address FROM catchpointbalances) ORDER BY address")
+	if err != nil {
+		return err
+	}
+	defer orphans.Close()
+	for orphans.Next() {
+		var addrbuf []byte
+		err = orphans.Scan(&addrbuf)
+		if err != nil {
+			return err
+		}
+		var addr basics.Address
+		copy(addr[:], addrbuf)
+		report.offending(addr, "resources of a missing account")
+	}
+	return orphans.Err()
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/crypto/merkletrie"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestVerifyCatchpointFile(t *testing.T) {
+	t.Run("ResourcesWithAccounts", func(t *testing.T) { testVerifyCatchpointFile(t, false) })
+	t.Run("ResourceHashes", func(t *testing.T) { testVerifyCatchpointFile(t, true) })
+}
+
+func testVerifyCatchpointFile(t *testing.T, resourceHashes bool) {
+	testProtocolVersion := protocol.ConsensusVersion("test-protocol-TestVerifyCatchpointFile")
+	protoParams := config.Consensus[protocol.ConsensusCurrentVersion]
+	protoParams.MaxBalLookback = 32
+	protoParams.SeedLookback = 2
+	protoParams.SeedRefreshInterval = 8
+	protoParams.EnableResourceHashes = resourceHashes
+	config.Consensus[testProtocolVersion] = protoParams
+	temporaryDirectory, _ := ioutil.TempDir(os.TempDir(), "catchpoints")
+	defer func() {
+		delete(config.Consensus, testProtocolVersion)
+		os.RemoveAll(temporaryDirectory)
+	}()
+
+	ml := makeMockLedgerForTracker(t, true, 10, testProtocolVersion)
+	defer ml.Close()
+	accts := randomAccounts(BalancesPerCatchpointFileChunk+100, false)
+
+	au := &accountUpdates{}
+	conf := config.GetDefaultLocal()
+	conf.CatchpointInterval = 1
+	conf.Archival = true
+	au.initialize(conf, ".", protoParams, accts)
+	defer au.close()
+	err := au.loadFromDisk(ml)
+	require.NoError(t, err)
+	require.Equal(t, resourceHashes, au.resourceHashes)
+	au.close()
+
+	// the catchpoint of round MaxBalLookback holds the balances of round 0
+	hdr := bookkeeping.BlockHeader{Round: basics.Round(protoParams.MaxBalLookback)}
+	hdr.CurrentProtocol = testProtocolVersion
+	hdr.TimeStamp = 1234
+	blockDigest := crypto.Digest(hdr.Hash())
+
+	var balancesRoot crypto.Digest
+	var totals ledgercore.AccountTotals
+	readDb := ml.trackerDB().Rdb
+	err = readDb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		mc, err := MakeMerkleCommitter(tx, false)
+		if err != nil {
+			return
+		}
+		trie, err := merkletrie.MakeTrie(mc, TrieMemoryConfig)
+		if err != nil {
+			return
+		}
+		balancesRoot, err = trie.RootHash()
+		if err != nil {
+			return
+		}
+		totals, err = accountsTotals(tx, false)
+		return
+	})
+	require.NoError(t, err)
+	label := ledgercore.MakeCatchpointLabel(hdr.Round, blockDigest, balancesRoot, totals).String()
+
+	fileName := filepath.Join(temporaryDirectory, "32.catchpoint")
+	err = readDb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		writer := makeCatchpointWriter(context.Background(), fileName, tx, hdr.Round, blockDigest, label, resourceHashes)
+		for {
+			more, err := writer.WriteStep(context.Background())
+			require.NoError(t, err)
+			if !more {
+				break
+			}
+		}
+		return
+	})
+	require.NoError(t, err)
+
+	var initState InitState
+	initState.Block.CurrentProtocol = protocol.ConsensusCurrentVersion
+	l, err := OpenLedger(ml.log, t.Name(), true, initState, conf)
+	require.NoError(t, err)
+	defer l.Close()
+
+	verify := func(hdr bookkeeping.BlockHeader) CatchpointVerificationReport {
+		file, err := os.Open(fileName)
+		require.NoError(t, err)
+		defer file.Close()
+		report, err := VerifyCatchpointFile(context.Background(), l, file, hdr)
+		require.NoError(t, err)
+		return report
+	}
+
+	report := verify(hdr)
+	require.True(t, report.Valid(), "%v %v", report.Mismatches, report.OffendingAccounts)
+	require.Equal(t, balancesRoot, report.BalancesRoot)
+	require.Equal(t, totals, report.Totals)
+	require.Equal(t, label, report.Label)
+	require.Equal(t, uint64(len(accts)), report.Accounts)
+
+	// the same file doesn't match the header of another block
+	otherHdr := hdr
+	otherHdr.Round++
+	report = verify(otherHdr)
+	require.False(t, report.Valid())
+	require.Empty(t, report.OffendingAccounts)
+	require.Len(t, report.Mismatches, 4) // blocks round, digest, balances round and label
+	require.Equal(t, balancesRoot, report.BalancesRoot)
+}
+
+func TestVerifyCatchpointFileOffendingAccounts(t *testing.T) {
+	log := logging.TestingLog(t)
+	genesisInitState, _ := testGenerateInitState(t, protocol.ConsensusCurrentVersion, 100)
+	l, err := OpenLedger(log, t.Name(), true, genesisInitState, config.GetDefaultLocal())
+	require.NoError(t, err)
+	defer l.Close()
+
+	const rewardsLevel = 10
+	var good, empty, ahead, orphan basics.Address
+	crypto.RandBytes(good[:])
+	crypto.RandBytes(empty[:])
+	crypto.RandBytes(ahead[:])
+	crypto.RandBytes(orphan[:])
+	goodData := basics.AccountData{Status: basics.Offline, MicroAlgos: basics.MicroAlgos{Raw: 1000000}, RewardsBase: rewardsLevel}
+	aheadData := basics.AccountData{Status: basics.Online, MicroAlgos: basics.MicroAlgos{Raw: 2000000}, RewardsBase: rewardsLevel + 1}
+
+	var totals ledgercore.AccountTotals
+	totals.RewardsLevel = rewardsLevel
+	var ot basics.OverflowTracker
+	totals.AddAccount(config.Consensus[protocol.ConsensusCurrentVersion], goodData, &ot)
+
+	hdr := bookkeeping.BlockHeader{Round: 1000}
+	hdr.CurrentProtocol = protocol.ConsensusCurrentVersion
+	fileHeader := CatchpointFileHeader{
+		Version:           catchpointFileVersion,
+		BalancesRound:     hdr.Round - basics.Round(config.Consensus[protocol.ConsensusCurrentVersion].MaxBalLookback),
+		BlocksRound:       hdr.Round,
+		Totals:            totals,
+		TotalAccounts:     3,
+		TotalChunks:       1,
+		TotalResources:    1,
+		BlockHeaderDigest: crypto.Digest(hdr.Hash()),
+	}
+	balances := catchpointFileBalancesChunk{Balances: []encodedBalanceRecord{
+		{Address: good, AccountData: protocol.Encode(&goodData)},
+		{Address: empty, AccountData: protocol.Encode(&basics.AccountData{})},
+		{Address: ahead, AccountData: protocol.Encode(&aheadData)},
+	}}
+	holding := basics.AssetHolding{Amount: 1}
+	resources := catchpointFileResourcesChunk{Resources: []encodedResourceRecord{
+		{Address: orphan, Aidx: 1, Rtype: basics.AssetCreatable, Data: protocol.Encode(&holding)},
+	}}
+
+	// a plain tar, as served to the nodes catching up
+	var buf bytes.Buffer
+	tarWriter := tar.NewWriter(&buf)
+	for _, section := range []struct {
+		name string
+		data []byte
+	}{
+		{"content.msgpack", protocol.Encode(&fileHeader)},
+		{"resources.1.1.msgpack", protocol.Encode(&resources)},
+		{"balances.1.1.msgpack", protocol.Encode(&balances)},
+	} {
+		err = tarWriter.WriteHeader(&tar.Header{Name: section.name, Mode: 0600, Size: int64(len(section.data))})
+		require.NoError(t, err)
+		_, err = tarWriter.Write(section.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+
+	report, err := VerifyCatchpointFile(context.Background(), l, &buf, hdr)
+	require.NoError(t, err)
+	require.False(t, report.Valid())
+	require.Equal(t, uint64(3), report.Accounts)
+	require.Equal(t, uint64(1), report.Resources)
+	// the offending accounts aren't part of the totals
+	require.Equal(t, totals, report.Totals)
+
+	offending := make(map[basics.Address]string)
+	for _, acct := range report.OffendingAccounts {
+		offending[acct.Address] = acct.Reason
+	}
+	require.Len(t, offending, 3)
+	require.Contains(t, offending[empty], "empty account")
+	require.Contains(t, offending[ahead], "rewards base")
+	require.Contains(t, offending[orphan], "missing account")
+
+	// the file header has no label, so only the label doesn't match
+	require.Len(t, report.Mismatches, 1)
+	require.Contains(t, report.Mismatches[0], "catchpoint label")
+}
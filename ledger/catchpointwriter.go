@@ -40,8 +40,24 @@ const (
 	// note that the last chunk would typically be less than this number.
 	BalancesPerCatchpointFileChunk = 512
 
-	// catchpointFileVersion is the catchpoint file version
-	catchpointFileVersion = uint64(0200)
+	// BoxesPerCatchpointFileChunk defines the number of application boxes that would be stored in each chunk in the catchpoint file.
+	BoxesPerCatchpointFileChunk = 512
+
+	// ResourcesPerCatchpointFileChunk defines the number of account asset holdings and application local states that would
+	// be stored in each chunk in the catchpoint file.
+	ResourcesPerCatchpointFileChunk = 512
+
+	// encodedMaxBoxKeyLen is the maximum length of an encoded box key, which is made of a prefix, the application index and
+	// the box name.
+	encodedMaxBoxKeyLen = 64 + 8 + 3
+
+	// catchpointFileVersion is the catchpoint file version, where the account resources are hashed into the merkle
+	// trie as part of the account data
+	catchpointFileVersion = uint64(0201)
+
+	// catchpointFileResourceHashesVersion is the catchpoint file version, where each of the account resources is hashed
+	// into the merkle trie as an entry of its own
+	catchpointFileResourceHashesVersion = uint64(0202)
 )
 
 // catchpointWriter is the struct managing the persistence of accounts data into the catchpoint file.
@@ -58,6 +74,13 @@ type catchpointWriter struct {
 	gzip              *gzip.Writer
 	tar               *tar.Writer
 	headerWritten     bool
+	boxesWritten      bool
+	boxesOffset       uint64
+	boxesChunkNum     uint64
+	resourcesWritten  bool
+	resourcesLast     *encodedResourceRecord
+	resourcesChunkNum uint64
+	resourceHashes    bool
 	balancesOffset    int
 	balancesChunk     catchpointFileBalancesChunk
 	fileHeader        *CatchpointFileHeader
@@ -81,14 +104,46 @@ type encodedBalanceRecord struct {
 type CatchpointFileHeader struct {
 	_struct struct{} `codec:",omitempty,omitemptyarray"`
 
-	Version           uint64                   `codec:"version"`
-	BalancesRound     basics.Round             `codec:"balancesRound"`
-	BlocksRound       basics.Round             `codec:"blocksRound"`
-	Totals            ledgercore.AccountTotals `codec:"accountTotals"`
-	TotalAccounts     uint64                   `codec:"accountsCount"`
-	TotalChunks       uint64                   `codec:"chunksCount"`
-	Catchpoint        string                   `codec:"catchpoint"`
-	BlockHeaderDigest crypto.Digest            `codec:"blockHeaderDigest"`
+	Version             uint64                   `codec:"version"`
+	BalancesRound       basics.Round             `codec:"balancesRound"`
+	BlocksRound         basics.Round             `codec:"blocksRound"`
+	Totals              ledgercore.AccountTotals `codec:"accountTotals"`
+	TotalAccounts       uint64                   `codec:"accountsCount"`
+	TotalChunks         uint64                   `codec:"chunksCount"`
+	TotalBoxes          uint64                   `codec:"boxesCount"`
+	TotalBoxChunks      uint64                   `codec:"boxChunksCount"`
+	TotalResources      uint64                   `codec:"resourcesCount"`
+	TotalResourceChunks uint64                   `codec:"resourceChunksCount"`
+	Catchpoint          string                   `codec:"catchpoint"`
+	BlockHeaderDigest   crypto.Digest            `codec:"blockHeaderDigest"`
+}
+
+type encodedBoxRecord struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Key   []byte `codec:"k,allocbound=encodedMaxBoxKeyLen"`
+	Value []byte `codec:"v,allocbound=config.MaxBoxSize"`
+}
+
+type catchpointFileBoxesChunk struct {
+	_struct struct{}           `codec:",omitempty,omitemptyarray"`
+	Boxes   []encodedBoxRecord `codec:"bx,allocbound=BoxesPerCatchpointFileChunk"`
+}
+
+// encodedResourceRecord is a single asset holding or application local state of an account, as stored in the
+// resources table.
+type encodedResourceRecord struct {
+	_struct struct{} `codec:",omitempty,omitemptyarray"`
+
+	Address basics.Address        `codec:"pk,allocbound=crypto.DigestSize"`
+	Aidx    basics.CreatableIndex `codec:"ai"`
+	Rtype   basics.CreatableType  `codec:"rt"`
+	Data    msgp.Raw              `codec:"rd,allocbound=basics.MaxEncodedAccountDataSize"`
+}
+
+type catchpointFileResourcesChunk struct {
+	_struct   struct{}                `codec:",omitempty,omitemptyarray"`
+	Resources []encodedResourceRecord `codec:"rs,allocbound=ResourcesPerCatchpointFileChunk"`
 }
 
 type catchpointFileBalancesChunk struct {
@@ -96,7 +151,9 @@ type catchpointFileBalancesChunk struct {
 	Balances []encodedBalanceRecord `codec:"bl,allocbound=BalancesPerCatchpointFileChunk"`
 }
 
-func makeCatchpointWriter(ctx context.Context, filePath string, tx *sql.Tx, blocksRound basics.Round, blockHeaderDigest crypto.Digest, label string) *catchpointWriter {
+// makeCatchpointWriter creates a catchpoint writer. The resourceHashes argument tells whether the merkle trie the label
+// was calculated from hashes the account resources apart from the accounts, and determines the catchpoint file version.
+func makeCatchpointWriter(ctx context.Context, filePath string, tx *sql.Tx, blocksRound basics.Round, blockHeaderDigest crypto.Digest, label string, resourceHashes bool) *catchpointWriter {
 	return &catchpointWriter{
 		ctx:               ctx,
 		filePath:          filePath,
@@ -104,6 +161,7 @@ func makeCatchpointWriter(ctx context.Context, filePath string, tx *sql.Tx, bloc
 		blocksRound:       blocksRound,
 		blockHeaderDigest: blockHeaderDigest,
 		label:             label,
+		resourceHashes:    resourceHashes,
 	}
 }
 
@@ -170,6 +228,30 @@ func (cw *catchpointWriter) WriteStep(stepCtx context.Context) (more bool, err e
 		cw.headerWritten = true
 	}
 
+	// the application boxes are written right after the header, before any of the balances.
+	for !cw.boxesWritten {
+		// have we timed-out / canceled by that point ?
+		if more, err = hasContextDeadlineExceeded(stepCtx); more == true || err != nil {
+			return
+		}
+		err = cw.writeBoxesStep(cw.ctx, cw.tx)
+		if err != nil {
+			return
+		}
+	}
+
+	// followed by the account resources.
+	for !cw.resourcesWritten {
+		// have we timed-out / canceled by that point ?
+		if more, err = hasContextDeadlineExceeded(stepCtx); more == true || err != nil {
+			return
+		}
+		err = cw.writeResourcesStep(cw.ctx, cw.tx)
+		if err != nil {
+			return
+		}
+	}
+
 	writerRequest := make(chan catchpointFileBalancesChunk, 1)
 	writerResponse := make(chan error, 2)
 	go cw.asyncWriter(writerRequest, writerResponse, cw.balancesChunkNum)
@@ -284,6 +366,74 @@ func (cw *catchpointWriter) asyncWriter(balances chan catchpointFileBalancesChun
 	}
 }
 
+// writeBoxesStep writes a single chunk of application boxes to the catchpoint file.
+func (cw *catchpointWriter) writeBoxesStep(ctx context.Context, tx *sql.Tx) (err error) {
+	if cw.boxesChunkNum >= cw.fileHeader.TotalBoxChunks {
+		cw.boxesWritten = true
+		return
+	}
+
+	var chunk catchpointFileBoxesChunk
+	chunk.Boxes, err = readBoxes(ctx, tx, cw.boxesOffset, BoxesPerCatchpointFileChunk)
+	if err != nil {
+		return
+	}
+	if len(chunk.Boxes) == 0 {
+		return fmt.Errorf("catchpoint writer expected %d boxes chunks, but found only %d", cw.fileHeader.TotalBoxChunks, cw.boxesChunkNum)
+	}
+
+	cw.boxesChunkNum++
+	encodedChunk := protocol.Encode(&chunk)
+	err = cw.tar.WriteHeader(&tar.Header{
+		Name: fmt.Sprintf("boxes.%d.%d.msgpack", cw.boxesChunkNum, cw.fileHeader.TotalBoxChunks),
+		Mode: 0600,
+		Size: int64(len(encodedChunk)),
+	})
+	if err != nil {
+		return
+	}
+	_, err = cw.tar.Write(encodedChunk)
+	if err != nil {
+		return
+	}
+	cw.boxesOffset += uint64(len(chunk.Boxes))
+	return
+}
+
+// writeResourcesStep writes a single chunk of account resources to the catchpoint file.
+func (cw *catchpointWriter) writeResourcesStep(ctx context.Context, tx *sql.Tx) (err error) {
+	if cw.resourcesChunkNum >= cw.fileHeader.TotalResourceChunks {
+		cw.resourcesWritten = true
+		return
+	}
+
+	var chunk catchpointFileResourcesChunk
+	chunk.Resources, err = readResources(ctx, tx, cw.resourcesLast, ResourcesPerCatchpointFileChunk)
+	if err != nil {
+		return
+	}
+	if len(chunk.Resources) == 0 {
+		return fmt.Errorf("catchpoint writer expected %d resources chunks, but found only %d", cw.fileHeader.TotalResourceChunks, cw.resourcesChunkNum)
+	}
+
+	cw.resourcesChunkNum++
+	encodedChunk := protocol.Encode(&chunk)
+	err = cw.tar.WriteHeader(&tar.Header{
+		Name: fmt.Sprintf("resources.%d.%d.msgpack", cw.resourcesChunkNum, cw.fileHeader.TotalResourceChunks),
+		Mode: 0600,
+		Size: int64(len(encodedChunk)),
+	})
+	if err != nil {
+		return
+	}
+	_, err = cw.tar.Write(encodedChunk)
+	if err != nil {
+		return
+	}
+	cw.resourcesLast = &chunk.Resources[len(chunk.Resources)-1]
+	return
+}
+
 func (cw *catchpointWriter) readDatabaseStep(ctx context.Context, tx *sql.Tx) (err error) {
 	cw.balancesChunk.Balances, err = cw.accountsIterator.Next(ctx, tx, BalancesPerCatchpointFileChunk)
 	if err == nil {
@@ -307,9 +457,22 @@ func (cw *catchpointWriter) readHeaderFromDatabase(ctx context.Context, tx *sql.
 		return
 	}
 	header.TotalChunks = (header.TotalAccounts + BalancesPerCatchpointFileChunk - 1) / BalancesPerCatchpointFileChunk
+	header.TotalBoxes, err = totalBoxes(context.Background(), tx)
+	if err != nil {
+		return
+	}
+	header.TotalBoxChunks = (header.TotalBoxes + BoxesPerCatchpointFileChunk - 1) / BoxesPerCatchpointFileChunk
+	header.TotalResources, err = totalResources(context.Background(), tx)
+	if err != nil {
+		return
+	}
+	header.TotalResourceChunks = (header.TotalResources + ResourcesPerCatchpointFileChunk - 1) / ResourcesPerCatchpointFileChunk
 	header.BlocksRound = cw.blocksRound
 	header.Catchpoint = cw.label
 	header.Version = catchpointFileVersion
+	if cw.resourceHashes {
+		header.Version = catchpointFileResourceHashesVersion
+	}
 	header.BlockHeaderDigest = cw.blockHeaderDigest
 	cw.fileHeader = &header
 	return
@@ -27,6 +27,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -196,7 +197,7 @@ func TestBasicCatchpointWriter(t *testing.T) {
 
 	readDb := ml.trackerDB().Rdb
 	err = readDb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		writer := makeCatchpointWriter(context.Background(), fileName, tx, blocksRound, blockHeaderDigest, catchpointLabel)
+		writer := makeCatchpointWriter(context.Background(), fileName, tx, blocksRound, blockHeaderDigest, catchpointLabel, false)
 		for {
 			more, err := writer.WriteStep(context.Background())
 			require.NoError(t, err)
@@ -215,6 +216,11 @@ func TestBasicCatchpointWriter(t *testing.T) {
 	require.NoError(t, err)
 	tarReader := tar.NewReader(gzipReader)
 	defer gzipReader.Close()
+	expectedResources := 0
+	for _, acct := range accts {
+		expectedResources += len(acct.Assets) + len(acct.AppLocalStates)
+	}
+	resourcesCount := 0
 	for {
 		header, err := tarReader.Next()
 		if err != nil {
@@ -254,10 +260,16 @@ func TestBasicCatchpointWriter(t *testing.T) {
 			err = protocol.Decode(balancesBlockBytes, &balances)
 			require.NoError(t, err)
 			require.Equal(t, uint64(len(accts)), uint64(len(balances.Balances)))
+		} else if strings.HasPrefix(header.Name, "resources.") {
+			var resources catchpointFileResourcesChunk
+			err = protocol.Decode(balancesBlockBytes, &resources)
+			require.NoError(t, err)
+			resourcesCount += len(resources.Resources)
 		} else {
 			require.Failf(t, "unexpected tar chunk name", "tar chunk name %s", header.Name)
 		}
 	}
+	require.Equal(t, expectedResources, resourcesCount)
 }
 
 func TestFullCatchpointWriter(t *testing.T) {
@@ -293,7 +305,7 @@ func TestFullCatchpointWriter(t *testing.T) {
 	catchpointLabel := fmt.Sprintf("%d#%v", blocksRound, blockHeaderDigest) // this is not a correct way to create a label, but it's good enough for this unit test
 	readDb := ml.trackerDB().Rdb
 	err = readDb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		writer := makeCatchpointWriter(context.Background(), fileName, tx, blocksRound, blockHeaderDigest, catchpointLabel)
+		writer := makeCatchpointWriter(context.Background(), fileName, tx, blocksRound, blockHeaderDigest, catchpointLabel, false)
 		for {
 			more, err := writer.WriteStep(context.Background())
 			require.NoError(t, err)
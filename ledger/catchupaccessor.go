@@ -19,8 +19,11 @@ package ledger
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +54,16 @@ type CatchpointCatchupAccessor interface {
 	// SetLabel set the catchpoint catchup label
 	SetLabel(ctx context.Context, label string) (err error)
 
+	// GetDownloadChunks returns the download chunks of the catchpoint file being downloaded, or nil if there is no such download
+	GetDownloadChunks(ctx context.Context) (chunks *CatchpointDownloadChunks, err error)
+
+	// SetDownloadChunks stores the download chunks of the catchpoint file being downloaded, so that the download could be resumed
+	// after a restart. Setting it to nil deletes the downloaded file.
+	SetDownloadChunks(ctx context.Context, chunks *CatchpointDownloadChunks) (err error)
+
+	// GetDownloadFilePath returns the path of the file the catchpoint file is being downloaded into
+	GetDownloadFilePath() string
+
 	// ResetStagingBalances resets the current staging balances, preparing for a new set of balances to be added
 	ResetStagingBalances(ctx context.Context, newCatchup bool) (err error)
 
@@ -119,6 +132,11 @@ const (
 
 // MakeCatchpointCatchupAccessor creates a CatchpointCatchupAccessor given a ledger
 func MakeCatchpointCatchupAccessor(ledger *Ledger, log logging.Logger) CatchpointCatchupAccessor {
+	if _, ok := ledger.accounts.(*sqliteAccountStore); !ok {
+		// the catchpoint is restored into the tracker database, which doesn't hold the accounts in that case.
+		log.Warnf("MakeCatchpointCatchupAccessor: catchpoint catchup requires the accounts to be stored in the tracker database")
+		return nil
+	}
 	rdb := ledger.trackerDB().Rdb
 	wdb := ledger.trackerDB().Wdb
 	accountsq, err := accountsDbInit(rdb.Handle, wdb.Handle)
@@ -179,6 +197,58 @@ func (c *CatchpointCatchupAccessorImpl) SetLabel(ctx context.Context, label stri
 	return
 }
 
+// GetDownloadChunks returns the download chunks of the catchpoint file being downloaded, or nil if there is no such download
+func (c *CatchpointCatchupAccessorImpl) GetDownloadChunks(ctx context.Context) (chunks *CatchpointDownloadChunks, err error) {
+	encodedChunks, _, err := c.accountsq.readCatchpointStateString(ctx, catchpointStateCatchupDownloadChunks)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read catchpoint catchup state '%s': %v", catchpointStateCatchupDownloadChunks, err)
+	}
+	if encodedChunks == "" {
+		return nil, nil
+	}
+	encoded, err := base64.StdEncoding.DecodeString(encodedChunks)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode catchpoint catchup state '%s': %v", catchpointStateCatchupDownloadChunks, err)
+	}
+	chunks = &CatchpointDownloadChunks{}
+	err = protocol.Decode(encoded, chunks)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode catchpoint catchup state '%s': %v", catchpointStateCatchupDownloadChunks, err)
+	}
+	return chunks, nil
+}
+
+// SetDownloadChunks stores the download chunks of the catchpoint file being downloaded, so that the download could be resumed
+// after a restart. Setting it to nil deletes the downloaded file.
+func (c *CatchpointCatchupAccessorImpl) SetDownloadChunks(ctx context.Context, chunks *CatchpointDownloadChunks) (err error) {
+	encodedChunks := ""
+	if chunks != nil {
+		encodedChunks = base64.StdEncoding.EncodeToString(protocol.Encode(chunks))
+	}
+	_, err = c.accountsq.writeCatchpointStateString(ctx, catchpointStateCatchupDownloadChunks, encodedChunks)
+	if err != nil {
+		return fmt.Errorf("unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupDownloadChunks, err)
+	}
+	if chunks == nil {
+		return c.removeDownloadFile()
+	}
+	return nil
+}
+
+// GetDownloadFilePath returns the path of the file the catchpoint file is being downloaded into
+func (c *CatchpointCatchupAccessorImpl) GetDownloadFilePath() string {
+	return filepath.Join(c.ledger.accts.dbDirectory, "catchpoints", "catchpointcatchup.download")
+}
+
+// removeDownloadFile deletes the file the catchpoint file was downloaded into, if there is one.
+func (c *CatchpointCatchupAccessorImpl) removeDownloadFile() error {
+	err := os.Remove(c.GetDownloadFilePath())
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to remove the downloaded catchpoint file : %v", err)
+	}
+	return nil
+}
+
 // ResetStagingBalances resets the current staging balances, preparing for a new set of balances to be added
 func (c *CatchpointCatchupAccessorImpl) ResetStagingBalances(ctx context.Context, newCatchup bool) (err error) {
 	wdb := c.ledger.trackerDB().Wdb
@@ -216,25 +286,40 @@ func (c *CatchpointCatchupAccessorImpl) ResetStagingBalances(ctx context.Context
 			if err != nil {
 				return fmt.Errorf("unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupState, err)
 			}
+			_, err = sq.writeCatchpointStateString(ctx, catchpointStateCatchupDownloadChunks, "")
+			if err != nil {
+				return fmt.Errorf("unable to write catchpoint catchup state '%s': %v", catchpointStateCatchupDownloadChunks, err)
+			}
 		}
 		return
 	})
 	ledgerResetstagingbalancesMicros.AddMicrosecondsSince(start, nil)
+	if err == nil && !newCatchup {
+		err = c.removeDownloadFile()
+	}
 	return
 }
 
 // CatchpointCatchupAccessorProgress is used by the caller of ProgressStagingBalances to obtain progress information
 type CatchpointCatchupAccessorProgress struct {
-	TotalAccounts     uint64
-	ProcessedAccounts uint64
-	ProcessedBytes    uint64
-	TotalChunks       uint64
-	SeenHeader        bool
+	TotalAccounts      uint64
+	ProcessedAccounts  uint64
+	ProcessedBytes     uint64
+	TotalChunks        uint64
+	TotalBoxes         uint64
+	ProcessedBoxes     uint64
+	TotalResources     uint64
+	ProcessedResources uint64
+	SeenHeader         bool
 
 	// Having the cachedTrie here would help to accelerate the catchup process since the trie maintain an internal cache of nodes.
 	// While rebuilding the trie, we don't want to force and reload (some) of these nodes into the cache for each catchpoint file chunk.
 	cachedTrie     *merkletrie.Trie
 	evictFrequency uint64
+
+	// resourceHashes is set when the catchpoint file version hashes each account resource into the merkle trie as an
+	// entry of its own. Otherwise, the resources are hashed along with the accounts, and have to precede them.
+	resourceHashes bool
 }
 
 // ProgressStagingBalances deserialize the given bytes as a temporary staging balances
@@ -245,6 +330,12 @@ func (c *CatchpointCatchupAccessorImpl) ProgressStagingBalances(ctx context.Cont
 	if strings.HasPrefix(sectionName, "balances.") && strings.HasSuffix(sectionName, ".msgpack") {
 		return c.processStagingBalances(ctx, bytes, progress)
 	}
+	if strings.HasPrefix(sectionName, "boxes.") && strings.HasSuffix(sectionName, ".msgpack") {
+		return c.processStagingBoxes(ctx, bytes, progress)
+	}
+	if strings.HasPrefix(sectionName, "resources.") && strings.HasSuffix(sectionName, ".msgpack") {
+		return c.processStagingResources(ctx, bytes, progress)
+	}
 	// we want to allow undefined sections to support backward compatibility.
 	c.log.Warnf("CatchpointCatchupAccessorImpl::ProgressStagingBalances encountered unexpected section name '%s' of length %d, which would be ignored", sectionName, len(bytes))
 	return nil
@@ -260,7 +351,7 @@ func (c *CatchpointCatchupAccessorImpl) processStagingContent(ctx context.Contex
 	if err != nil {
 		return err
 	}
-	if fileHeader.Version != catchpointFileVersion {
+	if fileHeader.Version != catchpointFileVersion && fileHeader.Version != catchpointFileResourceHashesVersion {
 		return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingContent: unable to process catchpoint - version %d is not supported", fileHeader.Version)
 	}
 
@@ -288,6 +379,9 @@ func (c *CatchpointCatchupAccessorImpl) processStagingContent(ctx context.Contex
 		progress.SeenHeader = true
 		progress.TotalAccounts = fileHeader.TotalAccounts
 		progress.TotalChunks = fileHeader.TotalChunks
+		progress.TotalBoxes = fileHeader.TotalBoxes
+		progress.TotalResources = fileHeader.TotalResources
+		progress.resourceHashes = fileHeader.Version == catchpointFileResourceHashesVersion
 		c.ledger.setSynchronousMode(ctx, c.ledger.accountsRebuildSynchronousMode)
 	}
 	return err
@@ -308,6 +402,9 @@ func (c *CatchpointCatchupAccessorImpl) processStagingBalances(ctx context.Conte
 	if len(balances.Balances) == 0 {
 		return fmt.Errorf("processStagingBalances received a chunk with no accounts")
 	}
+	if !progress.resourceHashes && progress.ProcessedResources != progress.TotalResources {
+		return fmt.Errorf("processStagingBalances received accounts before the %d resources declared in the catchpoint header", progress.TotalResources)
+	}
 
 	wdb := c.ledger.trackerDB().Wdb
 	start := time.Now()
@@ -371,6 +468,12 @@ func (c *CatchpointCatchupAccessorImpl) processStagingBalances(ctx context.Conte
 	go func() {
 		defer wg.Done()
 		err := wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+			if !progress.resourceHashes {
+				err = hashCatchpointStagingAccountsResources(ctx, tx, normalizedAccountBalances)
+				if err != nil {
+					return
+				}
+			}
 			err = writeCatchpointStagingHashes(ctx, tx, normalizedAccountBalances)
 			if err != nil {
 				return
@@ -404,6 +507,66 @@ func (c *CatchpointCatchupAccessorImpl) processStagingBalances(ctx context.Conte
 	return err
 }
 
+// processStagingBoxes deserialize the given bytes as a temporary staging application boxes
+func (c *CatchpointCatchupAccessorImpl) processStagingBoxes(ctx context.Context, bytes []byte, progress *CatchpointCatchupAccessorProgress) (err error) {
+	if !progress.SeenHeader {
+		return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingBoxes: content chunk was missing")
+	}
+
+	var boxes catchpointFileBoxesChunk
+	err = protocol.Decode(bytes, &boxes)
+	if err != nil {
+		return err
+	}
+
+	if len(boxes.Boxes) == 0 {
+		return fmt.Errorf("processStagingBoxes received a chunk with no boxes")
+	}
+	if progress.ProcessedBoxes+uint64(len(boxes.Boxes)) > progress.TotalBoxes {
+		return fmt.Errorf("processStagingBoxes received more boxes than the %d declared in the catchpoint header", progress.TotalBoxes)
+	}
+
+	wdb := c.ledger.trackerDB().Wdb
+	err = wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		return writeCatchpointStagingBoxes(ctx, tx, boxes.Boxes)
+	})
+	if err == nil {
+		progress.ProcessedBoxes += uint64(len(boxes.Boxes))
+		progress.ProcessedBytes += uint64(len(bytes))
+	}
+	return err
+}
+
+// processStagingResources deserialize the given bytes as a temporary staging account resources
+func (c *CatchpointCatchupAccessorImpl) processStagingResources(ctx context.Context, bytes []byte, progress *CatchpointCatchupAccessorProgress) (err error) {
+	if !progress.SeenHeader {
+		return fmt.Errorf("CatchpointCatchupAccessorImpl::processStagingResources: content chunk was missing")
+	}
+
+	var resources catchpointFileResourcesChunk
+	err = protocol.Decode(bytes, &resources)
+	if err != nil {
+		return err
+	}
+
+	if len(resources.Resources) == 0 {
+		return fmt.Errorf("processStagingResources received a chunk with no resources")
+	}
+	if progress.ProcessedResources+uint64(len(resources.Resources)) > progress.TotalResources {
+		return fmt.Errorf("processStagingResources received more resources than the %d declared in the catchpoint header", progress.TotalResources)
+	}
+
+	wdb := c.ledger.trackerDB().Wdb
+	err = wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
+		return writeCatchpointStagingResources(ctx, tx, resources.Resources, progress.resourceHashes)
+	})
+	if err == nil {
+		progress.ProcessedResources += uint64(len(resources.Resources))
+		progress.ProcessedBytes += uint64(len(bytes))
+	}
+	return err
+}
+
 // BuildMerkleTrie would process the catchpointpendinghashes and insert all the items in it into the merkle trie
 func (c *CatchpointCatchupAccessorImpl) BuildMerkleTrie(ctx context.Context, progressUpdates func(uint64)) (err error) {
 	wdb := c.ledger.trackerDB().Wdb
@@ -682,12 +845,9 @@ func (c *CatchpointCatchupAccessorImpl) StoreBalancesRound(ctx context.Context,
 
 // StoreFirstBlock stores a single block to the blocks database.
 func (c *CatchpointCatchupAccessorImpl) StoreFirstBlock(ctx context.Context, blk *bookkeeping.Block) (err error) {
-	blockDbs := c.ledger.blockDB()
 	start := time.Now()
 	ledgerStorefirstblockCount.Inc(nil)
-	err = blockDbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		return blockStartCatchupStaging(tx, *blk)
-	})
+	err = c.ledger.blockStorage().startCatchupStaging(*blk)
 	ledgerStorefirstblockMicros.AddMicrosecondsSince(start, nil)
 	if err != nil {
 		return err
@@ -697,12 +857,9 @@ func (c *CatchpointCatchupAccessorImpl) StoreFirstBlock(ctx context.Context, blk
 
 // StoreBlock stores a single block to the blocks database.
 func (c *CatchpointCatchupAccessorImpl) StoreBlock(ctx context.Context, blk *bookkeeping.Block) (err error) {
-	blockDbs := c.ledger.blockDB()
 	start := time.Now()
 	ledgerCatchpointStoreblockCount.Inc(nil)
-	err = blockDbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		return blockPutStaging(tx, *blk)
-	})
+	err = c.ledger.blockStorage().putStaging(*blk)
 	ledgerCatchpointStoreblockMicros.AddMicrosecondsSince(start, nil)
 	if err != nil {
 		return err
@@ -712,16 +869,14 @@ func (c *CatchpointCatchupAccessorImpl) StoreBlock(ctx context.Context, blk *boo
 
 // FinishBlocks concludes the catchup of the blocks database.
 func (c *CatchpointCatchupAccessorImpl) FinishBlocks(ctx context.Context, applyChanges bool) (err error) {
-	blockDbs := c.ledger.blockDB()
 	start := time.Now()
 	ledgerCatchpointFinishblocksCount.Inc(nil)
-	err = blockDbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		if applyChanges {
-			return blockCompleteCatchup(tx)
-		}
+	if applyChanges {
+		err = c.ledger.blockStorage().completeCatchup()
+	} else {
 		// TODO: unused, either actually implement cleanup on catchpoint failure, or delete this
-		return blockAbortCatchup(tx)
-	})
+		err = c.ledger.blockStorage().abortCatchup()
+	}
 	ledgerCatchpointFinishblocksMicros.AddMicrosecondsSince(start, nil)
 	if err != nil {
 		return err
@@ -731,13 +886,9 @@ func (c *CatchpointCatchupAccessorImpl) FinishBlocks(ctx context.Context, applyC
 
 // EnsureFirstBlock ensure that we have a single block in the staging block table, and returns that block
 func (c *CatchpointCatchupAccessorImpl) EnsureFirstBlock(ctx context.Context) (blk bookkeeping.Block, err error) {
-	blockDbs := c.ledger.blockDB()
 	start := time.Now()
 	ledgerCatchpointEnsureblock1Count.Inc(nil)
-	err = blockDbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) (err error) {
-		blk, err = blockEnsureSingleBlock(tx)
-		return
-	})
+	blk, err = c.ledger.blockStorage().ensureSingleBlock()
 	ledgerCatchpointEnsureblock1Micros.AddMicrosecondsSince(start, nil)
 	if err != nil {
 		return blk, err
@@ -49,6 +49,7 @@ type roundCowParent interface {
 	getStorageLimits(addr basics.Address, aidx basics.AppIndex, global bool) (basics.StateSchema, error)
 	allocated(addr basics.Address, aidx basics.AppIndex, global bool) (bool, error)
 	getKey(addr basics.Address, aidx basics.AppIndex, global bool, key string, accountIdx uint64) (basics.TealValue, bool, error)
+	getBox(key string) (string, bool, error)
 }
 
 type roundCowState struct {
@@ -73,6 +74,11 @@ type roundCowState struct {
 	groupIdx int
 	// track creatables created during each transaction in the round
 	trackedCreatables map[int]basics.CreatableIndex
+
+	// applications called by inner transactions while evaluating an
+	// application in this cow. Their storage deltas are reported in the
+	// ApplyData of those inner transactions rather than by BuildEvalDelta
+	innerApps map[basics.AppIndex]bool
 }
 
 func makeRoundCowState(b roundCowParent, hdr bookkeeping.BlockHeader, prevTimestamp int64, hint int) *roundCowState {
@@ -154,6 +160,17 @@ func (cb *roundCowState) getCreator(cidx basics.CreatableIndex, ctype basics.Cre
 	return cb.lookupParent.getCreator(cidx, ctype)
 }
 
+func (cb *roundCowState) getBox(key string) (value string, ok bool, err error) {
+	delta, ok := cb.mods.Boxes[key]
+	if ok {
+		if delta.Value == nil {
+			return "", false, nil
+		}
+		return *delta.Value, true, nil
+	}
+	return cb.lookupParent.getBox(key)
+}
+
 func (cb *roundCowState) lookup(addr basics.Address) (data basics.AccountData, err error) {
 	d, ok := cb.mods.Accts.Get(addr)
 	if ok {
@@ -268,6 +285,9 @@ func (cb *roundCowState) commitToParent() {
 	for cidx, delta := range cb.mods.Creatables {
 		cb.commitParent.mods.Creatables[cidx] = delta
 	}
+	for key, delta := range cb.mods.Boxes {
+		cb.commitParent.mods.Boxes[key] = delta
+	}
 	for addr, smod := range cb.sdeltas {
 		for aapp, nsd := range smod {
 			lsd, ok := cb.commitParent.sdeltas[addr][aapp]
@@ -282,6 +302,9 @@ func (cb *roundCowState) commitToParent() {
 			}
 		}
 	}
+	for aidx := range cb.innerApps {
+		cb.commitParent.markInnerApp(aidx)
+	}
 	cb.commitParent.mods.CompactCertNext = cb.mods.CompactCertNext
 }
 
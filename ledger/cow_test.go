@@ -53,6 +53,10 @@ func (ml *mockLedger) getCreator(cidx basics.CreatableIndex, ctype basics.Creata
 	return basics.Address{}, false, nil
 }
 
+func (ml *mockLedger) getBox(key string) (string, bool, error) {
+	return "", false, nil
+}
+
 func (ml *mockLedger) getStorageCounts(addr basics.Address, aidx basics.AppIndex, global bool) (basics.StateSchema, error) {
 	return basics.StateSchema{}, nil
 }
@@ -82,6 +82,10 @@ func (x *roundCowBase) getCreator(cidx basics.CreatableIndex, ctype basics.Creat
 	return x.l.GetCreatorForRound(x.rnd, cidx, ctype)
 }
 
+func (x *roundCowBase) getBox(key string) (string, bool, error) {
+	return x.l.LookupBox(x.rnd, key)
+}
+
 // lookup returns the non-rewarded account data for the provided account address. It uses the internal per-round cache
 // first, and if it cannot find it there, it would defer to the underlaying implementation.
 // note that errors in accounts data retrivals are not cached as these typically cause the transaction evaluation to fail.
@@ -361,6 +365,11 @@ type BlockEvaluator struct {
 	blockGenerated bool // prevent repeated GenerateBlock calls
 
 	l ledgerForEvaluator
+
+	// simulation is set when evaluating a simulated transaction group, in
+	// which case minimum balance violations are recorded rather than
+	// rejecting the group.
+	simulation *simulation
 }
 
 type ledgerForEvaluator interface {
@@ -376,6 +385,7 @@ type ledgerForCowBase interface {
 	CheckDup(config.ConsensusParams, basics.Round, basics.Round, basics.Round, transactions.Txid, TxLease) error
 	LookupWithoutRewards(basics.Round, basics.Address) (basics.AccountData, basics.Round, error)
 	GetCreatorForRound(basics.Round, basics.CreatableIndex, basics.CreatableType) (basics.Address, bool, error)
+	LookupBox(basics.Round, string) (string, bool, error)
 }
 
 // StartEvaluator creates a BlockEvaluator, given a ledger and a block header
@@ -679,6 +689,8 @@ func (eval *BlockEvaluator) prepareEvalParams(txgroup []transactions.SignedTxnWi
 	var groupNoAD []transactions.SignedTxn
 	var pastSideEffects []logic.EvalSideEffects
 	var minTealVersion uint64
+	var specials *transactions.SpecialAddresses
+	var pooledBudget *uint64
 	res = make([]*logic.EvalParams, len(txgroup))
 	for i, txn := range txgroup {
 		// Ignore any non-ApplicationCall transactions
@@ -694,15 +706,22 @@ func (eval *BlockEvaluator) prepareEvalParams(txgroup []transactions.SignedTxnWi
 			}
 			pastSideEffects = logic.MakePastSideEffects(len(txgroup))
 			minTealVersion = logic.ComputeMinTealVersion(groupNoAD)
+			pooledBudget = logic.MakePooledBudget(&eval.proto, groupNoAD)
+			specials = &transactions.SpecialAddresses{
+				FeeSink:     eval.block.BlockHeader.FeeSink,
+				RewardsPool: eval.block.BlockHeader.RewardsPool,
+			}
 		}
 
 		res[i] = &logic.EvalParams{
-			Txn:             &groupNoAD[i],
-			Proto:           &eval.proto,
-			TxnGroup:        groupNoAD,
-			GroupIndex:      i,
-			PastSideEffects: pastSideEffects,
-			MinTealVersion:  &minTealVersion,
+			Txn:                     &groupNoAD[i],
+			Proto:                   &eval.proto,
+			TxnGroup:                groupNoAD,
+			GroupIndex:              i,
+			PastSideEffects:         pastSideEffects,
+			MinTealVersion:          &minTealVersion,
+			Specials:                specials,
+			PooledApplicationBudget: pooledBudget,
 		}
 	}
 	return
@@ -729,6 +748,9 @@ func (eval *BlockEvaluator) transactionGroup(txgroup []transactions.SignedTxnWit
 
 	// Prepare eval params for any ApplicationCall transactions in the group
 	evalParams := eval.prepareEvalParams(txgroup)
+	if eval.simulation != nil {
+		eval.simulation.evalParams = evalParams
+	}
 
 	// Evaluate each transaction in the group
 	txibs = make([]transactions.SignedTxnInBlock, 0, len(txgroup))
@@ -808,7 +830,9 @@ func (eval *BlockEvaluator) checkMinBalance(cow *roundCowState) error {
 
 		dataNew := data.WithUpdatedRewards(eval.proto, rewardlvl)
 		effectiveMinBalance := dataNew.MinBalance(&eval.proto)
-		if dataNew.MicroAlgos.Raw < effectiveMinBalance.Raw {
+		if dataNew.MicroAlgos.Raw < effectiveMinBalance.Raw && eval.simulation != nil {
+			eval.simulation.minBalanceViolation(cow.groupIdx, addr, dataNew.MicroAlgos, effectiveMinBalance)
+		} else if dataNew.MicroAlgos.Raw < effectiveMinBalance.Raw {
 			return fmt.Errorf("account %v balance %d below min %d (%d assets)",
 				addr, dataNew.MicroAlgos.Raw, effectiveMinBalance.Raw, len(dataNew.Assets))
 		}
@@ -506,14 +506,14 @@ ok:
 		{
 			SignedTxn: stxn1,
 			ApplyData: transactions.ApplyData{
-				EvalDelta: basics.EvalDelta{GlobalDelta: map[string]basics.ValueDelta{
+				EvalDelta: transactions.EvalDelta{GlobalDelta: map[string]basics.ValueDelta{
 					"creator": {Action: basics.SetBytesAction, Bytes: string(addrs[0][:])}},
 				}},
 		},
 		{
 			SignedTxn: stxn2,
 			ApplyData: transactions.ApplyData{
-				EvalDelta: basics.EvalDelta{GlobalDelta: map[string]basics.ValueDelta{
+				EvalDelta: transactions.EvalDelta{GlobalDelta: map[string]basics.ValueDelta{
 					"caller": {Action: basics.SetBytesAction, Bytes: string(addrs[0][:])}},
 				}},
 		},
@@ -549,6 +549,313 @@ func TestEvalAppAllocStateWithTxnGroup(t *testing.T) {
 	require.Equal(t, basics.TealValue{Type: basics.TealBytesType, Bytes: string(addr[:])}, state["creator"])
 }
 
+// TestEvalAppInnerTxn ensures that inner transactions issued by an app are
+// applied to the ledger and, along with its logs, recorded in the ApplyData
+// of the app call
+func TestEvalAppInnerTxn(t *testing.T) {
+	genesisInitState, addrs, keys := genesis(10)
+	genesisInitState.Block.CurrentProtocol = protocol.ConsensusFuture
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	l, err := OpenLedger(logging.Base(), dbName, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	newBlock := bookkeeping.MakeBlock(genesisInitState.Block.BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, 0)
+	require.NoError(t, err)
+
+	ops, err := logic.AssembleString(`#pragma version 5
+	txn ApplicationID
+	bz ok
+	itxn_begin
+	int pay
+	itxn_field TypeEnum
+	int 5000
+	itxn_field Amount
+	txn Accounts 1
+	itxn_field Receiver
+	itxn_submit
+	byte "paid"
+	log
+ok:
+	int 1`)
+	require.NoError(t, err, ops.Errors)
+	approval := ops.Program
+	ops, err = logic.AssembleString("#pragma version 5\nint 1")
+	require.NoError(t, err)
+	clear := ops.Program
+
+	genHash := genesisInitState.Block.BlockHeader.GenesisHash
+	header := transactions.Header{
+		Sender:      addrs[0],
+		Fee:         minFee,
+		FirstValid:  newBlock.Round(),
+		LastValid:   newBlock.Round(),
+		GenesisHash: genHash,
+	}
+	create := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApprovalProgram:   approval,
+			ClearStateProgram: clear,
+		},
+	}
+	appIndex := basics.AppIndex(1)
+	fund := transactions.Transaction{
+		Type:   protocol.PaymentTx,
+		Header: header,
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver: appIndex.Address(),
+			Amount:   basics.MicroAlgos{Raw: 1000000},
+		},
+	}
+	call := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApplicationID: appIndex,
+			Accounts:      []basics.Address{addrs[1]},
+		},
+	}
+
+	for _, txn := range []transactions.Transaction{create, fund, call} {
+		err = eval.Transaction(txn.Sign(keys[0]), transactions.ApplyData{})
+		require.NoError(t, err)
+	}
+
+	vb, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	payset := vb.Block().Payset
+	require.Len(t, payset, 3)
+	inner := payset[2].ApplyData.EvalDelta.InnerTxns
+	require.Len(t, inner, 1)
+	require.Equal(t, protocol.PaymentTx, inner[0].Txn.Type)
+	require.Equal(t, appIndex.Address(), inner[0].Txn.Sender)
+	require.Equal(t, addrs[1], inner[0].Txn.Receiver)
+	require.Equal(t, uint64(5000), inner[0].Txn.Amount.Raw)
+	require.Equal(t, []string{"paid"}, payset[2].ApplyData.EvalDelta.Logs)
+	require.Empty(t, payset[0].ApplyData.EvalDelta.Logs)
+
+	proto := config.Consensus[protocol.ConsensusFuture]
+	ad, ok := vb.delta.Accts.Get(appIndex.Address())
+	require.True(t, ok)
+	require.Equal(t, 1000000-5000-proto.MinTxnFee, ad.MicroAlgos.Raw)
+	ad, ok = vb.delta.Accts.Get(addrs[1])
+	require.True(t, ok)
+	require.Equal(t, genesisInitState.Accounts[addrs[1]].MicroAlgos.Raw+5000, ad.MicroAlgos.Raw)
+}
+
+// TestEvalAppInnerAppCall ensures that an app may call another app, and that
+// the callee's state changes are applied to the ledger but recorded in the
+// ApplyData of the inner transaction rather than that of the caller
+func TestEvalAppInnerAppCall(t *testing.T) {
+	genesisInitState, addrs, keys := genesis(10)
+	genesisInitState.Block.CurrentProtocol = protocol.ConsensusFuture
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	l, err := OpenLedger(logging.Base(), dbName, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	newBlock := bookkeeping.MakeBlock(genesisInitState.Block.BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, 0)
+	require.NoError(t, err)
+
+	ops, err := logic.AssembleString(`#pragma version 5
+	txn ApplicationID
+	bz ok
+	byte "called"
+	int 1
+	app_global_put
+	byte "hello"
+	log
+ok:
+	int 1`)
+	require.NoError(t, err, ops.Errors)
+	callee := ops.Program
+	ops, err = logic.AssembleString(`#pragma version 5
+	txn ApplicationID
+	bz ok
+	int 1
+	app_params_get AppCreator
+	assert
+	txn Sender
+	==
+	assert
+	itxn_begin
+	int appl
+	itxn_field TypeEnum
+	int 1
+	itxn_field ApplicationID
+	itxn_submit
+	itxn LastLog
+	byte "hello"
+	==
+	assert
+	byte "caller"
+	int 1
+	app_global_put
+ok:
+	int 1`)
+	require.NoError(t, err, ops.Errors)
+	caller := ops.Program
+	ops, err = logic.AssembleString("#pragma version 5\nint 1")
+	require.NoError(t, err)
+	clear := ops.Program
+
+	genHash := genesisInitState.Block.BlockHeader.GenesisHash
+	header := transactions.Header{
+		Sender:      addrs[0],
+		Fee:         minFee,
+		FirstValid:  newBlock.Round(),
+		LastValid:   newBlock.Round(),
+		GenesisHash: genHash,
+	}
+	schema := basics.StateSchema{NumUint: 1}
+	createCallee := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApprovalProgram:   callee,
+			ClearStateProgram: clear,
+			GlobalStateSchema: schema,
+		},
+	}
+	createCaller := createCallee
+	createCaller.ApprovalProgram = caller
+	calleeIndex := basics.AppIndex(1)
+	callerIndex := basics.AppIndex(2)
+	fund := transactions.Transaction{
+		Type:   protocol.PaymentTx,
+		Header: header,
+		PaymentTxnFields: transactions.PaymentTxnFields{
+			Receiver: callerIndex.Address(),
+			Amount:   basics.MicroAlgos{Raw: 1000000},
+		},
+	}
+	call := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApplicationID: callerIndex,
+			ForeignApps:   []basics.AppIndex{calleeIndex},
+		},
+	}
+
+	for _, txn := range []transactions.Transaction{createCallee, createCaller, fund, call} {
+		err = eval.Transaction(txn.Sign(keys[0]), transactions.ApplyData{})
+		require.NoError(t, err)
+	}
+
+	vb, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	payset := vb.Block().Payset
+	require.Len(t, payset, 4)
+	delta := payset[3].ApplyData.EvalDelta
+	require.Equal(t, basics.StateDelta{"caller": {Action: basics.SetUintAction, Uint: 1}}, delta.GlobalDelta)
+	require.Len(t, delta.InnerTxns, 1)
+	inner := delta.InnerTxns[0]
+	require.Equal(t, protocol.ApplicationCallTx, inner.Txn.Type)
+	require.Equal(t, callerIndex.Address(), inner.Txn.Sender)
+	require.Equal(t, calleeIndex, inner.Txn.ApplicationID)
+	require.Equal(t, basics.StateDelta{"called": {Action: basics.SetUintAction, Uint: 1}}, inner.EvalDelta.GlobalDelta)
+	require.Equal(t, []string{"hello"}, inner.EvalDelta.Logs)
+
+	ad, ok := vb.delta.Accts.Get(addrs[0])
+	require.True(t, ok)
+	require.Equal(t, basics.TealValue{Type: basics.TealUintType, Uint: 1}, ad.AppParams[calleeIndex].GlobalState["called"])
+	require.Equal(t, basics.TealValue{Type: basics.TealUintType, Uint: 1}, ad.AppParams[callerIndex].GlobalState["caller"])
+}
+
+func TestEvalAppReturnData(t *testing.T) {
+	genesisInitState, addrs, keys := genesis(10)
+	genesisInitState.Block.CurrentProtocol = protocol.ConsensusFuture
+
+	dbName := fmt.Sprintf("%s.%d", t.Name(), crypto.RandUint64())
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	l, err := OpenLedger(logging.Base(), dbName, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	newBlock := bookkeeping.MakeBlock(genesisInitState.Block.BlockHeader)
+	eval, err := l.StartEvaluator(newBlock.BlockHeader, 0)
+	require.NoError(t, err)
+
+	// the first call in a group leaves return data for the second
+	ops, err := logic.AssembleString(`#pragma version 5
+	txn ApplicationID
+	bz ok
+	txn GroupIndex
+	bnz read
+	byte "answer"
+	retdata_put
+	b ok
+read:
+	gretdata 0 0
+	assert
+	byte "answer"
+	==
+	return
+ok:
+	int 1`)
+	require.NoError(t, err, ops.Errors)
+	approval := ops.Program
+	ops, err = logic.AssembleString("#pragma version 5\nint 1")
+	require.NoError(t, err)
+	clear := ops.Program
+
+	genHash := genesisInitState.Block.BlockHeader.GenesisHash
+	header := transactions.Header{
+		Sender:      addrs[0],
+		Fee:         minFee,
+		FirstValid:  newBlock.Round(),
+		LastValid:   newBlock.Round(),
+		GenesisHash: genHash,
+	}
+	create := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApprovalProgram:   approval,
+			ClearStateProgram: clear,
+		},
+	}
+	err = eval.Transaction(create.Sign(keys[0]), transactions.ApplyData{})
+	require.NoError(t, err)
+
+	call := transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header,
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApplicationID: 1,
+		},
+	}
+	write := call
+	read := call
+	read.Note = []byte("read")
+	var group transactions.TxGroup
+	group.TxGroupHashes = []crypto.Digest{crypto.HashObj(write), crypto.HashObj(read)}
+	write.Group = crypto.HashObj(group)
+	read.Group = write.Group
+	txgroup := []transactions.SignedTxn{write.Sign(keys[0]), read.Sign(keys[0])}
+	err = eval.TransactionGroup(transactions.WrapSignedTxnsWithAD(txgroup))
+	require.NoError(t, err)
+
+	vb, err := eval.GenerateBlock()
+	require.NoError(t, err)
+	payset := vb.Block().Payset
+	require.Len(t, payset, 3)
+	require.Equal(t, []string{"answer"}, payset[1].ApplyData.EvalDelta.ReturnData)
+	require.Empty(t, payset[2].ApplyData.EvalDelta.ReturnData)
+}
+
 func BenchmarkBlockEvaluatorRAMCrypto(b *testing.B) {
 	benchmarkBlockEvaluator(b, true, true)
 }
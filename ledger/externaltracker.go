@@ -0,0 +1,379 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/algorand/go-deadlock"
+
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// ExternalTracker is a state machine, implemented outside of the ledger
+// package, that maintains state derived from the ledger (e.g. the number of
+// holders of every asset) in lockstep with it. External trackers are given
+// to OpenLedgerWithTrackers, and are invoked by the ledger right after all
+// of its own trackers.
+//
+// An external tracker keeps its state in tables of its own in the tracker
+// database. The ledger keeps track of the round up to which that state was
+// persisted, and of the version of the tracker's schema.
+//
+// The ledger ensures that the tracker is invoked with at most one of
+// LoadFromDisk, Rebuild, NewBlock or Close at a time, and with at most one
+// of Commit or Committed at a time. Commit and Committed are called in the
+// background, so that a slow tracker database doesn't hold the ledger, and
+// may run concurrently with NewBlock. Synchronizing them, and serving
+// queries concurrently with all of these, is the tracker's responsibility.
+type ExternalTracker interface {
+	// Name identifies the tracker in the tracker database. It must be
+	// unique among the trackers of a ledger, and must never change.
+	Name() string
+
+	// Migrations returns the schema migrations of the tracker's tables,
+	// where the i-th migration upgrades the tables from version i to
+	// version i+1. Migrations the tracker database has already applied
+	// are skipped, so existing migrations must never be modified.
+	Migrations() []TrackerMigration
+
+	// Rebuild discards whatever state the tracker has persisted, and
+	// recreates it from the given accounts, which are the accounts as of
+	// round rnd. It is called when the tracker is first added to a ledger,
+	// and whenever its persisted state can no longer be caught up with the
+	// ledger, such as after a catchpoint catchup.
+	Rebuild(ctx context.Context, tx *sql.Tx, rnd basics.Round, accounts AccountIterator) error
+
+	// LoadFromDisk discards the tracker's in-memory state, and informs it
+	// that its persisted state is as of round rnd. It is followed by a
+	// NewBlock call for every round after rnd that the ledger has.
+	LoadFromDisk(dbs db.Pair, rnd basics.Round) error
+
+	// NewBlock informs the tracker of a new block, and the state delta
+	// produced by evaluating it. Blocks are always given in order.
+	NewBlock(blk bookkeeping.Block, delta ledgercore.StateDelta)
+
+	// Commit writes the changes of all the blocks up to and including
+	// round rnd, which the ledger has already written to the block
+	// database. The tracker may have received blocks past rnd, and may
+	// receive more while Commit runs, whose changes it must keep in memory
+	// until a later Commit.
+	Commit(ctx context.Context, tx *sql.Tx, rnd basics.Round) error
+
+	// Committed informs the tracker that the transaction given to Commit
+	// was committed, so that the changes up to round rnd may be dropped
+	// from memory.
+	Committed(rnd basics.Round)
+
+	// Close releases the in-memory state of the tracker. When the ledger
+	// is reloaded, Close is followed by another LoadFromDisk.
+	Close()
+}
+
+// TrackerMigration upgrades the schema of an external tracker's tables
+// by a single version.
+type TrackerMigration func(ctx context.Context, tx *sql.Tx) error
+
+// AccountIterator calls visit for every account in the tracker database,
+// stopping at the first error visit returns.
+type AccountIterator func(visit func(addr basics.Address, data basics.AccountData) error) error
+
+// externalTrackersSchema keeps the round up to which the state of every
+// external tracker was persisted.
+var externalTrackersSchema = `CREATE TABLE IF NOT EXISTS externaltrackers (
+		name text primary key,
+		rnd integer)`
+
+// externalTrackerComponentPrefix prefixes the names under which the schema
+// versions of the external trackers are kept.
+const externalTrackerComponentPrefix = "externaltracker:"
+
+// externalTracker adapts an ExternalTracker to the ledgerTracker interface.
+type externalTracker struct {
+	tracker ExternalTracker
+
+	// au is used to evaluate the blocks replayed into the tracker.
+	au *accountUpdates
+
+	dbs db.Pair
+	log logging.Logger
+
+	// mu protects dbRound and commitRound, which are shared with the commitSyncer goroutine.
+	mu deadlock.Mutex
+	// dbRound is the round up to which the tracker's state was persisted.
+	dbRound basics.Round
+	// commitRound is the round up to which the commitSyncer should persist the tracker's state.
+	commitRound basics.Round
+
+	// commitSignal wakes up the commitSyncer once commitRound is updated.
+	commitSignal chan struct{}
+	// ctx is canceled when the tracker is closed, and ctxCancel cancels it.
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	// commitSyncerClosed is closed once the commitSyncer goroutine exits.
+	commitSyncerClosed chan struct{}
+}
+
+func makeExternalTracker(tracker ExternalTracker, au *accountUpdates) *externalTracker {
+	et := &externalTracker{tracker: tracker, au: au}
+	// the commitSyncer isn't running until the tracker is loaded.
+	et.commitSyncerClosed = make(chan struct{})
+	close(et.commitSyncerClosed)
+	return et
+}
+
+// loadFromDisk upgrades the tracker's schema, rebuilds its state if it can't
+// be caught up with the ledger, and replays the blocks it has not persisted yet.
+func (et *externalTracker) loadFromDisk(l ledgerForTracker) error {
+	et.dbs = l.trackerDB()
+	et.log = l.trackerLog()
+	name := et.tracker.Name()
+
+	et.au.accountsMu.RLock()
+	accountsRound := et.au.dbRound
+	et.au.accountsMu.RUnlock()
+	latest := l.Latest()
+
+	err := et.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		err := externalTrackerMigrate(ctx, tx, name, et.tracker.Migrations())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, externalTrackersSchema)
+		if err != nil {
+			return err
+		}
+
+		var rnd basics.Round
+		err = tx.QueryRowContext(ctx, "SELECT rnd FROM externaltrackers WHERE name=?", name).Scan(&rnd)
+		if err == nil && rnd >= accountsRound && rnd <= latest {
+			et.dbRound = rnd
+			return nil
+		}
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		// the tracker was never loaded, or its state is out of the range we
+		// can replay blocks into (e.g. after a catchpoint catchup), so rebuild
+		// it from the accounts database.
+		et.log.Infof("externalTracker.loadFromDisk: rebuilding tracker %s at round %d", name, accountsRound)
+		accounts := makeAccountIterator(ctx, tx)
+		if _, ok := et.au.store.(*sqliteAccountStore); !ok {
+			// the accounts aren't stored in the tracker database.
+			accounts = et.au.store.iterateAccounts
+		}
+		err = et.tracker.Rebuild(ctx, tx, accountsRound, accounts)
+		if err != nil {
+			return fmt.Errorf("unable to rebuild tracker %s: %v", name, err)
+		}
+		et.dbRound = accountsRound
+		return externalTrackerPutRound(ctx, tx, name, accountsRound)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = et.tracker.LoadFromDisk(et.dbs, et.dbRound)
+	if err != nil {
+		return err
+	}
+
+	if et.dbRound < latest {
+		eval := accountUpdatesLedgerEvaluator{au: et.au}
+		eval.prevHeader, err = l.BlockHdr(et.dbRound)
+		if err != nil {
+			return err
+		}
+		for rnd := et.dbRound + 1; rnd <= latest; rnd++ {
+			blk, err := l.Block(rnd)
+			if err != nil {
+				return err
+			}
+			delta, err := l.trackerEvalVerified(blk, &eval)
+			if err != nil {
+				return err
+			}
+			et.tracker.NewBlock(blk, delta)
+			eval.prevHeader = blk.BlockHeader
+		}
+	}
+
+	et.commitRound = et.dbRound
+	et.commitSignal = make(chan struct{}, 1)
+	et.ctx, et.ctxCancel = context.WithCancel(context.Background())
+	et.commitSyncerClosed = make(chan struct{})
+	go et.commitSyncer()
+	return nil
+}
+
+func (et *externalTracker) newBlock(blk bookkeeping.Block, delta ledgercore.StateDelta) {
+	et.tracker.NewBlock(blk, delta)
+}
+
+// committedUpTo schedules committing the tracker's state up to round rnd,
+// and retains the blocks it would need to replay on the next loadFromDisk.
+// The commit itself is done by the commitSyncer goroutine, so that it
+// doesn't hold the trackers lock.
+func (et *externalTracker) committedUpTo(rnd basics.Round) basics.Round {
+	et.mu.Lock()
+	dbRound := et.dbRound
+	if rnd > et.commitRound {
+		et.commitRound = rnd
+	}
+	et.mu.Unlock()
+	if rnd > dbRound {
+		select {
+		case et.commitSignal <- struct{}{}:
+		default:
+			// the commitSyncer was already signaled, and would pick up the new commitRound.
+		}
+	}
+	return dbRound
+}
+
+// commitSyncer persists the tracker's state every time committedUpTo moves commitRound past dbRound.
+func (et *externalTracker) commitSyncer() {
+	defer close(et.commitSyncerClosed)
+	for {
+		select {
+		case <-et.commitSignal:
+			et.mu.Lock()
+			dbRound, rnd := et.dbRound, et.commitRound
+			et.mu.Unlock()
+			if rnd > dbRound {
+				et.commit(rnd)
+			}
+		case <-et.ctx.Done():
+			return
+		}
+	}
+}
+
+// commit writes the tracker's state up to round rnd to the tracker database.
+func (et *externalTracker) commit(rnd basics.Round) {
+	name := et.tracker.Name()
+	err := et.dbs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		err := et.tracker.Commit(ctx, tx, rnd)
+		if err != nil {
+			return err
+		}
+		return externalTrackerPutRound(ctx, tx, name, rnd)
+	})
+	if err != nil {
+		// the next committedUpTo would try again.
+		et.log.Warnf("externalTracker.commit: unable to commit tracker %s up to round %d: %v", name, rnd, err)
+		return
+	}
+	et.tracker.Committed(rnd)
+	et.mu.Lock()
+	et.dbRound = rnd
+	et.mu.Unlock()
+}
+
+// close stops the commitSyncer, dropping the commit it might have pending,
+// and closes the tracker. The rounds that were not committed are replayed
+// on the next loadFromDisk.
+func (et *externalTracker) close() {
+	if et.ctxCancel != nil {
+		et.ctxCancel()
+	}
+	<-et.commitSyncerClosed
+	et.tracker.Close()
+}
+
+// externalTrackerMigrate applies the migrations of the given tracker that
+// were not applied yet.
+func externalTrackerMigrate(ctx context.Context, tx *sql.Tx, name string, migrations []TrackerMigration) error {
+	component := externalTrackerComponentPrefix + name
+	version, err := db.GetComponentVersion(ctx, tx, component)
+	if err != nil {
+		return err
+	}
+	if int(version) > len(migrations) {
+		return fmt.Errorf("tracker %s schema version %d is newer than the supported version %d", name, version, len(migrations))
+	}
+	for ; int(version) < len(migrations); version++ {
+		err = migrations[version](ctx, tx)
+		if err != nil {
+			return fmt.Errorf("unable to upgrade tracker %s schema from version %d: %v", name, version, err)
+		}
+	}
+	_, err = db.SetComponentVersion(ctx, tx, component, version)
+	return err
+}
+
+func externalTrackerPutRound(ctx context.Context, tx *sql.Tx, name string, rnd basics.Round) error {
+	_, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO externaltrackers(name, rnd) VALUES(?, ?)", name, rnd)
+	return err
+}
+
+// makeAccountIterator returns an AccountIterator over the accountbase table.
+func makeAccountIterator(ctx context.Context, tx *sql.Tx) AccountIterator {
+	return func(visit func(addr basics.Address, data basics.AccountData) error) error {
+		rows, err := tx.QueryContext(ctx, "SELECT address, data FROM accountbase")
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		selectResourcesStmt, err := tx.PrepareContext(ctx, "SELECT aidx, rtype, data FROM resources WHERE address=?")
+		if err != nil {
+			return err
+		}
+		defer selectResourcesStmt.Close()
+
+		for rows.Next() {
+			var addrbuf []byte
+			var buf []byte
+			err = rows.Scan(&addrbuf, &buf)
+			if err != nil {
+				return err
+			}
+
+			var addr basics.Address
+			if len(addrbuf) != len(addr) {
+				return fmt.Errorf("account DB address length mismatch: %d != %d", len(addrbuf), len(addr))
+			}
+			copy(addr[:], addrbuf)
+
+			var data basics.AccountData
+			err = protocol.Decode(buf, &data)
+			if err != nil {
+				return err
+			}
+			err = loadAccountResources(selectResourcesStmt, addr, &data)
+			if err != nil {
+				return err
+			}
+
+			err = visit(addr, data)
+			if err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+type balancesRound struct {
+	rnd      basics.Round
+	balances map[basics.Address]uint64
+}
+
+// balancesTracker is an ExternalTracker mirroring the balance of every account
+type balancesTracker struct {
+	mu  sync.Mutex
+	dbs db.Pair
+
+	// pending holds the changes of the rounds that were not committed yet
+	pending []balancesRound
+
+	rebuilds []basics.Round
+	loaded   []basics.Round
+	blocks   []basics.Round
+}
+
+func (bt *balancesTracker) Name() string {
+	return "balances"
+}
+
+func (bt *balancesTracker) Migrations() []TrackerMigration {
+	return []TrackerMigration{
+		func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "CREATE TABLE balancestracker (address blob primary key, microalgos integer)")
+			return err
+		},
+		func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "ALTER TABLE balancestracker ADD COLUMN rnd integer")
+			return err
+		},
+	}
+}
+
+func (bt *balancesTracker) Rebuild(ctx context.Context, tx *sql.Tx, rnd basics.Round, accounts AccountIterator) error {
+	bt.rebuilds = append(bt.rebuilds, rnd)
+	_, err := tx.ExecContext(ctx, "DELETE FROM balancestracker")
+	if err != nil {
+		return err
+	}
+	return accounts(func(addr basics.Address, data basics.AccountData) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO balancestracker(address, microalgos, rnd) VALUES(?, ?, ?)", addr[:], data.MicroAlgos.Raw, rnd)
+		return err
+	})
+}
+
+func (bt *balancesTracker) LoadFromDisk(dbs db.Pair, rnd basics.Round) error {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	bt.dbs = dbs
+	bt.pending = nil
+	bt.loaded = append(bt.loaded, rnd)
+	return nil
+}
+
+func (bt *balancesTracker) NewBlock(blk bookkeeping.Block, delta ledgercore.StateDelta) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	bt.blocks = append(bt.blocks, blk.Round())
+	br := balancesRound{rnd: blk.Round(), balances: make(map[basics.Address]uint64)}
+	for i := 0; i < delta.Accts.Len(); i++ {
+		addr, data := delta.Accts.GetByIdx(i)
+		br.balances[addr] = data.MicroAlgos.Raw
+	}
+	bt.pending = append(bt.pending, br)
+}
+
+func (bt *balancesTracker) Commit(ctx context.Context, tx *sql.Tx, rnd basics.Round) error {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	for _, br := range bt.pending {
+		if br.rnd > rnd {
+			break
+		}
+		for addr, microalgos := range br.balances {
+			_, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO balancestracker(address, microalgos, rnd) VALUES(?, ?, ?)", addr[:], microalgos, br.rnd)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (bt *balancesTracker) Committed(rnd basics.Round) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	for len(bt.pending) > 0 && bt.pending[0].rnd <= rnd {
+		bt.pending = bt.pending[1:]
+	}
+}
+
+func (bt *balancesTracker) Close() {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	bt.pending = nil
+}
+
+// balance returns the balance of addr as of the latest block the tracker was given
+func (bt *balancesTracker) balance(t *testing.T, addr basics.Address) (microalgos uint64) {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	for i := len(bt.pending) - 1; i >= 0; i-- {
+		if microalgos, ok := bt.pending[i].balances[addr]; ok {
+			return microalgos
+		}
+	}
+	err := bt.dbs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, "SELECT microalgos FROM balancestracker WHERE address=?", addr[:]).Scan(&microalgos)
+	})
+	require.NoError(t, err)
+	return
+}
+
+func TestExternalTracker(t *testing.T) {
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion, 100)
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		addrs = append(addrs, addr)
+	}
+
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dbTempDir)
+	dbPrefix := filepath.Join(dbTempDir, t.Name())
+	const inMem = false
+	log := logging.TestingLog(t)
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+
+	addPayments := func(l *Ledger, count int) {
+		for i := 0; i < count; i++ {
+			stx := sign(initSecrets, transactions.Transaction{
+				Type: protocol.PaymentTx,
+				Header: transactions.Header{
+					Sender:      addrs[0],
+					Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+					FirstValid:  l.Latest() + 1,
+					LastValid:   l.Latest() + 10,
+					GenesisID:   t.Name(),
+					GenesisHash: genesisInitState.GenesisHash,
+				},
+				PaymentTxnFields: transactions.PaymentTxnFields{
+					Receiver: addrs[1+i%(len(addrs)-1)],
+					Amount:   basics.MicroAlgos{Raw: 1000},
+				},
+			})
+			require.NoError(t, l.addBlockTxns(t, genesisInitState.Accounts, []transactions.SignedTxn{stx}, transactions.ApplyData{}))
+		}
+	}
+	checkBalances := func(l *Ledger, bt *balancesTracker) {
+		for _, addr := range addrs {
+			data, _, err := l.LookupWithoutRewards(l.Latest(), addr)
+			require.NoError(t, err)
+			require.Equal(t, data.MicroAlgos.Raw, bt.balance(t, addr), "%v", addr)
+		}
+	}
+
+	// a new tracker is built from the accounts
+	bt := &balancesTracker{}
+	l, err := OpenLedgerWithTrackers(log, dbPrefix, inMem, genesisInitState, cfg, []ExternalTracker{bt})
+	require.NoError(t, err)
+	require.Equal(t, []basics.Round{0}, bt.rebuilds)
+	require.Equal(t, []basics.Round{0}, bt.loaded)
+	checkBalances(l, bt)
+
+	addPayments(l, 10)
+	require.Equal(t, []basics.Round{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, bt.blocks)
+	checkBalances(l, bt)
+	l.WaitForCommit(l.Latest())
+	l.Close()
+
+	// a tracker that was persisted only replays the blocks it has not committed
+	bt = &balancesTracker{}
+	l, err = OpenLedgerWithTrackers(log, dbPrefix, inMem, genesisInitState, cfg, []ExternalTracker{bt})
+	require.NoError(t, err)
+	defer l.Close()
+	require.Empty(t, bt.rebuilds)
+	require.Len(t, bt.loaded, 1)
+	require.LessOrEqual(t, uint64(bt.loaded[0]), uint64(10))
+	for i, rnd := range bt.blocks {
+		require.Equal(t, bt.loaded[0]+basics.Round(i+1), rnd)
+	}
+	checkBalances(l, bt)
+
+	addPayments(l, 5)
+	checkBalances(l, bt)
+
+	err = l.trackerDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		version, err := db.GetComponentVersion(ctx, tx, externalTrackerComponentPrefix+bt.Name())
+		require.Equal(t, int32(2), version)
+		return err
+	})
+	require.NoError(t, err)
+
+	// completing a catchpoint catchup drops the external trackers rounds, so the
+	// tracker is rebuilt once the ledger is reloaded.
+	l.WaitForCommit(l.Latest())
+	err = l.trackerDBs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "DROP TABLE IF EXISTS externaltrackers")
+		return err
+	})
+	require.NoError(t, err)
+	require.NoError(t, l.reloadLedger())
+	require.Len(t, bt.rebuilds, 1)
+	require.Equal(t, l.accts.dbRound, bt.rebuilds[0])
+	checkBalances(l, bt)
+
+	addPayments(l, 5)
+	checkBalances(l, bt)
+}
+
+// slowCommitTracker is a balancesTracker whose Commit waits until it's released
+type slowCommitTracker struct {
+	balancesTracker
+	committing chan struct{}
+	release    chan struct{}
+}
+
+func (st *slowCommitTracker) Commit(ctx context.Context, tx *sql.Tx, rnd basics.Round) error {
+	select {
+	case st.committing <- struct{}{}:
+	default:
+	}
+	<-st.release
+	return st.balancesTracker.Commit(ctx, tx, rnd)
+}
+
+func TestExternalTrackerSlowCommit(t *testing.T) {
+	genesisInitState, initSecrets := testGenerateInitState(t, protocol.ConsensusCurrentVersion, 100)
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		addrs = append(addrs, addr)
+	}
+
+	const inMem = true
+	log := logging.TestingLog(t)
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	st := &slowCommitTracker{committing: make(chan struct{}, 1), release: make(chan struct{})}
+	l, err := OpenLedgerWithTrackers(log, t.Name(), inMem, genesisInitState, cfg, []ExternalTracker{st})
+	require.NoError(t, err)
+	defer l.Close()
+
+	addPayment := func() {
+		stx := sign(initSecrets, transactions.Transaction{
+			Type: protocol.PaymentTx,
+			Header: transactions.Header{
+				Sender:      addrs[0],
+				Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+				FirstValid:  l.Latest() + 1,
+				LastValid:   l.Latest() + 10,
+				GenesisID:   t.Name(),
+				GenesisHash: genesisInitState.GenesisHash,
+			},
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: addrs[1],
+				Amount:   basics.MicroAlgos{Raw: 1000},
+			},
+		})
+		require.NoError(t, l.addBlockTxns(t, genesisInitState.Accounts, []transactions.SignedTxn{stx}, transactions.ApplyData{}))
+	}
+
+	addPayment()
+	select {
+	case <-st.committing:
+	case <-time.After(time.Minute):
+		require.FailNow(t, "the tracker wasn't committed")
+	}
+
+	// the ledger keeps going while the tracker is being committed
+	for i := 0; i < 5; i++ {
+		addPayment()
+	}
+	l.WaitForCommit(l.Latest())
+	data, _, err := l.LookupWithoutRewards(l.Latest(), addrs[1])
+	require.NoError(t, err)
+	require.Equal(t, data.MicroAlgos.Raw, st.balance(t, addrs[1]))
+
+	close(st.release)
+}
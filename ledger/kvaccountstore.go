@@ -0,0 +1,502 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+	"github.com/algorand/go-algorand/util/kvstore"
+)
+
+// The keys of a kvAccountStore. As in the SQLite tracker database, the account data is split between the base
+// account data and one entry per asset holding or application local state. The online accounts are indexed by their
+// normalized online balance followed by their address, both in big endian, so that iterating over the index in
+// reverse yields them in the order of accountsOnlineTop.
+var (
+	kvAcctRoundKey       = []byte("acctround")
+	kvAcctTotalsKey      = []byte("accttotals")
+	kvAccountPrefix      = []byte("accounts/")
+	kvResourcePrefix     = []byte("resources/")
+	kvCreatorPrefix      = []byte("creators/")
+	kvBoxPrefix          = []byte("boxes/")
+	kvOnlinePrefix       = []byte("onlineaccounts/")
+	kvResourceKeyLen     = len(kvResourcePrefix) + crypto.DigestSize + 8 + 1
+	kvCreatorKeyPrefixes = map[basics.CreatableType][]byte{
+		basics.AssetCreatable: append(append([]byte{}, kvCreatorPrefix...), byte(basics.AssetCreatable)),
+		basics.AppCreatable:   append(append([]byte{}, kvCreatorPrefix...), byte(basics.AppCreatable)),
+	}
+)
+
+// kvAccountRowID is the rowid of the persistedAccountData of the accounts of a kvAccountStore, which has no rowids.
+// As in the SQLite tracker database, a zero rowid means that the account doesn't exist.
+const kvAccountRowID = 1
+
+func kvAccountKey(addr basics.Address) []byte {
+	return append(append([]byte{}, kvAccountPrefix...), addr[:]...)
+}
+
+// kvResourceKey returns the key of the given resource of addr, or the prefix of all the resources of addr if
+// key is nil
+func kvResourceKey(addr basics.Address, key *resourceKey) []byte {
+	buf := make([]byte, 0, kvResourceKeyLen)
+	buf = append(append(buf, kvResourcePrefix...), addr[:]...)
+	if key == nil {
+		return buf
+	}
+	var aidx [8]byte
+	binary.BigEndian.PutUint64(aidx[:], uint64(key.aidx))
+	return append(append(buf, aidx[:]...), byte(key.rtype))
+}
+
+func kvCreatorKey(cidx basics.CreatableIndex, ctype basics.CreatableType) []byte {
+	return kvRoundKey(kvCreatorKeyPrefixes[ctype], basics.Round(cidx))
+}
+
+func kvBoxKey(key string) []byte {
+	return append(append([]byte{}, kvBoxPrefix...), key...)
+}
+
+func kvOnlineKey(normBalance uint64, addr basics.Address) []byte {
+	key := make([]byte, len(kvOnlinePrefix)+8, len(kvOnlinePrefix)+8+len(addr))
+	copy(key, kvOnlinePrefix)
+	binary.BigEndian.PutUint64(key[len(kvOnlinePrefix):], normBalance)
+	return append(key, addr[:]...)
+}
+
+// kvAccountStore is the accountStore of a kvstore.Store
+type kvAccountStore struct {
+	store *kvstore.Store
+}
+
+func openKVAccountStore(filename string, dbMem bool) (*kvAccountStore, error) {
+	store, err := kvstore.Open(filename, dbMem)
+	if err != nil {
+		return nil, err
+	}
+	return &kvAccountStore{store: store}, nil
+}
+
+func kvAcctRound(tx *kvstore.Tx) (basics.Round, error) {
+	buf, ok := tx.Get(kvAcctRoundKey)
+	if !ok || len(buf) != 8 {
+		return 0, fmt.Errorf("unable to read the accounts round")
+	}
+	return basics.Round(binary.BigEndian.Uint64(buf)), nil
+}
+
+func kvPutAcctRound(tx *kvstore.Tx, rnd basics.Round) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(rnd))
+	return tx.Put(kvAcctRoundKey, buf[:])
+}
+
+func kvAcctTotals(tx *kvstore.Tx) (totals ledgercore.AccountTotals, err error) {
+	buf, ok := tx.Get(kvAcctTotalsKey)
+	if !ok {
+		return totals, fmt.Errorf("unable to read the account totals")
+	}
+	err = protocol.Decode(buf, &totals)
+	return
+}
+
+// kvPutAccount writes the base data of addr, given its previous data, and deletes it if data is empty. The resources
+// are written separately.
+func kvPutAccount(tx *kvstore.Tx, addr basics.Address, old basics.AccountData, data basics.AccountData, proto config.ConsensusParams) (err error) {
+	oldNormBalance := old.NormalizedOnlineBalance(proto)
+	normBalance := data.NormalizedOnlineBalance(proto)
+	if oldNormBalance > 0 && oldNormBalance != normBalance {
+		err = tx.Delete(kvOnlineKey(oldNormBalance, addr))
+		if err != nil {
+			return
+		}
+	}
+
+	if data.IsZero() {
+		return tx.Delete(kvAccountKey(addr))
+	}
+	baseData := accountBaseData(data)
+	err = tx.Put(kvAccountKey(addr), protocol.Encode(&baseData))
+	if err != nil {
+		return
+	}
+	if normBalance > 0 {
+		err = tx.Put(kvOnlineKey(normBalance, addr), nil)
+	}
+	return
+}
+
+// kvLookupAccount returns the data of addr, and false if it doesn't exist
+func kvLookupAccount(tx *kvstore.Tx, addr basics.Address) (data basics.AccountData, ok bool, err error) {
+	buf, ok := tx.Get(kvAccountKey(addr))
+	if !ok {
+		return
+	}
+	err = protocol.Decode(buf, &data)
+	if err != nil {
+		return
+	}
+
+	prefix := kvResourceKey(addr, nil)
+	tx.Iterate(prefix, kvPrefixEnd(prefix), false, func(key []byte, value []byte) bool {
+		if len(key) != kvResourceKeyLen {
+			err = fmt.Errorf("invalid resource key %v", key)
+			return false
+		}
+		aidx := basics.CreatableIndex(binary.BigEndian.Uint64(key[len(prefix):]))
+		rtype := basics.CreatableType(key[len(key)-1])
+		err = mergeAccountResource(&data, aidx, rtype, value)
+		return err == nil
+	})
+	return
+}
+
+func (as *kvAccountStore) initialize(initAccounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) (newDatabase bool, err error) {
+	err = as.store.Update(func(tx *kvstore.Tx) error {
+		if _, ok := tx.Get(kvAcctRoundKey); ok {
+			return nil
+		}
+
+		var ot basics.OverflowTracker
+		var totals ledgercore.AccountTotals
+		for addr, data := range initAccounts {
+			err := kvPutAccount(tx, addr, basics.AccountData{}, data, proto)
+			if err != nil {
+				return err
+			}
+			for key, resourceData := range encodeAccountResources(data) {
+				key := key
+				err = tx.Put(kvResourceKey(addr, &key), resourceData)
+				if err != nil {
+					return err
+				}
+			}
+			totals.AddAccount(proto, data, &ot)
+		}
+		if ot.Overflowed {
+			return fmt.Errorf("overflow computing totals")
+		}
+
+		err := tx.Put(kvAcctTotalsKey, protocol.Encode(&totals))
+		if err != nil {
+			return err
+		}
+		newDatabase = true
+		return kvPutAcctRound(tx, 0)
+	})
+	return
+}
+
+func (as *kvAccountStore) reset() error {
+	return as.store.Update(func(tx *kvstore.Tx) error {
+		return tx.DeleteRange(nil, nil)
+	})
+}
+
+func (as *kvAccountStore) round() (rnd basics.Round, err error) {
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		rnd, err0 = kvAcctRound(tx)
+		return err0
+	})
+	return
+}
+
+func (as *kvAccountStore) totals() (totals ledgercore.AccountTotals, err error) {
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		totals, err0 = kvAcctTotals(tx)
+		return err0
+	})
+	return
+}
+
+func (as *kvAccountStore) lookup(addr basics.Address) (data persistedAccountData, err error) {
+	data.addr = addr
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		data.round, err0 = kvAcctRound(tx)
+		if err0 != nil {
+			return err0
+		}
+		var ok bool
+		data.accountData, ok, err0 = kvLookupAccount(tx, addr)
+		if ok {
+			data.rowid = kvAccountRowID
+		}
+		return err0
+	})
+	return
+}
+
+func (as *kvAccountStore) lookupCreator(cidx basics.CreatableIndex, ctype basics.CreatableType) (addr basics.Address, ok bool, rnd basics.Round, err error) {
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		rnd, err0 = kvAcctRound(tx)
+		if err0 != nil {
+			return err0
+		}
+		buf, has := tx.Get(kvCreatorKey(cidx, ctype))
+		if has {
+			ok = true
+			copy(addr[:], buf)
+		}
+		return nil
+	})
+	return
+}
+
+func (as *kvAccountStore) listCreatables(maxIdx basics.CreatableIndex, maxResults uint64, ctype basics.CreatableType) (results []basics.CreatableLocator, rnd basics.Round, err error) {
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		rnd, err0 = kvAcctRound(tx)
+		if err0 != nil {
+			return err0
+		}
+		prefix := kvCreatorKeyPrefixes[ctype]
+		end := kvPrefixEnd(kvCreatorKey(maxIdx, ctype))
+		tx.Iterate(prefix, end, true, func(key []byte, value []byte) bool {
+			if uint64(len(results)) >= maxResults {
+				return false
+			}
+			cl := basics.CreatableLocator{
+				Type:  ctype,
+				Index: basics.CreatableIndex(binary.BigEndian.Uint64(key[len(prefix):])),
+			}
+			copy(cl.Creator[:], value)
+			results = append(results, cl)
+			return true
+		})
+		return nil
+	})
+	return
+}
+
+func (as *kvAccountStore) lookupBox(key string) (value string, ok bool, rnd basics.Round, err error) {
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		rnd, err0 = kvAcctRound(tx)
+		if err0 != nil {
+			return err0
+		}
+		buf, has := tx.Get(kvBoxKey(key))
+		if has {
+			ok = true
+			value = string(buf)
+		}
+		return nil
+	})
+	return
+}
+
+func (as *kvAccountStore) listBoxes(appIdx basics.AppIndex, maxResults uint64) (keys []string, rnd basics.Round, err error) {
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		rnd, err0 = kvAcctRound(tx)
+		if err0 != nil {
+			return err0
+		}
+		start := kvBoxKey(ledgercore.BoxKeyAppPrefix(appIdx))
+		end := kvBoxKey(ledgercore.BoxKeyAppPrefix(appIdx + 1))
+		tx.Iterate(start, end, false, func(key []byte, value []byte) bool {
+			if uint64(len(keys)) >= maxResults {
+				return false
+			}
+			keys = append(keys, string(key[len(kvBoxPrefix):]))
+			return true
+		})
+		return nil
+	})
+	return
+}
+
+func (as *kvAccountStore) onlineTop(offset, n uint64, proto config.ConsensusParams) (accts map[basics.Address]*onlineAccount, rnd basics.Round, err error) {
+	accts = make(map[basics.Address]*onlineAccount, n)
+	err = as.store.View(func(tx *kvstore.Tx) error {
+		var err0 error
+		rnd, err0 = kvAcctRound(tx)
+		if err0 != nil {
+			return err0
+		}
+		var pos uint64
+		tx.Iterate(kvOnlinePrefix, kvPrefixEnd(kvOnlinePrefix), true, func(key []byte, value []byte) bool {
+			if pos < offset {
+				pos++
+				return true
+			}
+			if uint64(len(accts)) >= n {
+				return false
+			}
+			var addr basics.Address
+			copy(addr[:], key[len(kvOnlinePrefix)+8:])
+			buf, ok := tx.Get(kvAccountKey(addr))
+			if !ok {
+				err0 = fmt.Errorf("online account %v has no data", addr)
+				return false
+			}
+			var data basics.AccountData
+			err0 = protocol.Decode(buf, &data)
+			if err0 != nil {
+				return false
+			}
+			accts[addr] = accountDataToOnline(addr, &data, proto)
+			return true
+		})
+		return err0
+	})
+	return
+}
+
+func (as *kvAccountStore) iterateAccounts(visit func(addr basics.Address, data basics.AccountData) error) error {
+	return as.store.View(func(tx *kvstore.Tx) (err error) {
+		tx.Iterate(kvAccountPrefix, kvPrefixEnd(kvAccountPrefix), false, func(key []byte, value []byte) bool {
+			var addr basics.Address
+			copy(addr[:], key[len(kvAccountPrefix):])
+			var data basics.AccountData
+			data, _, err = kvLookupAccount(tx, addr)
+			if err != nil {
+				return false
+			}
+			err = visit(addr, data)
+			return err == nil
+		})
+		return
+	})
+}
+
+func (as *kvAccountStore) commit(fn func(ctx context.Context, w accountStoreWriter) error) error {
+	return as.store.Update(func(tx *kvstore.Tx) error {
+		return fn(context.Background(), &kvAccountStoreWriter{tx: tx})
+	})
+}
+
+func (as *kvAccountStore) setSynchronousMode(ctx context.Context, synchronousMode db.SynchronousMode) error {
+	as.store.SetSynchronous(synchronousMode >= db.SynchronousModeFull)
+	return nil
+}
+
+func (as *kvAccountStore) close() {
+	as.store.Close()
+}
+
+// kvAccountStoreWriter is the accountStoreWriter of a kvstore.Store read-write transaction
+type kvAccountStoreWriter struct {
+	tx *kvstore.Tx
+}
+
+func (w *kvAccountStoreWriter) accountsLoadOld(updates *compactAccountDeltas) error {
+	for _, idx := range updates.misses {
+		addr := updates.addresses[idx]
+		data, ok, err := kvLookupAccount(w.tx, addr)
+		if err != nil {
+			return err
+		}
+		old := persistedAccountData{addr: addr, accountData: data}
+		if ok {
+			old.rowid = kvAccountRowID
+		}
+		updates.updateOld(idx, old)
+	}
+	updates.misses = nil
+	return nil
+}
+
+func (w *kvAccountStoreWriter) totalsNewRounds(updates []ledgercore.AccountDeltas, compactUpdates compactAccountDeltas, accountTotals []ledgercore.AccountTotals, proto config.ConsensusParams) error {
+	totals, err := kvAcctTotals(w.tx)
+	if err != nil {
+		return err
+	}
+	totals, err = applyTotalsNewRounds(totals, updates, compactUpdates, accountTotals, proto)
+	if err != nil {
+		return err
+	}
+	return w.tx.Put(kvAcctTotalsKey, protocol.Encode(&totals))
+}
+
+func (w *kvAccountStoreWriter) accountsNewRound(updates compactAccountDeltas, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, proto config.ConsensusParams, lastUpdateRound basics.Round) (updatedAccounts []persistedAccountData, err error) {
+	updatedAccounts = make([]persistedAccountData, updates.len())
+	for i := 0; i < updates.len(); i++ {
+		addr, data := updates.getByIdx(i)
+		if data.old.rowid != 0 || !data.new.IsZero() {
+			err = kvPutAccount(w.tx, addr, data.old.accountData, data.new, proto)
+			if err != nil {
+				return
+			}
+		}
+		for _, rdelta := range data.resources {
+			key := rdelta.resourceKey
+			if rdelta.new != nil {
+				err = w.tx.Put(kvResourceKey(addr, &key), rdelta.new)
+			} else {
+				err = w.tx.Delete(kvResourceKey(addr, &key))
+			}
+			if err != nil {
+				return
+			}
+		}
+
+		updatedAccounts[i] = persistedAccountData{addr: addr, round: lastUpdateRound}
+		if !data.new.IsZero() {
+			updatedAccounts[i].accountData = data.new
+			updatedAccounts[i].rowid = kvAccountRowID
+		}
+	}
+
+	for cidx, cdelta := range creatables {
+		if cdelta.Created {
+			err = w.tx.Put(kvCreatorKey(cidx, cdelta.Ctype), cdelta.Creator[:])
+		} else {
+			err = w.tx.Delete(kvCreatorKey(cidx, cdelta.Ctype))
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (w *kvAccountStoreWriter) boxesNewRound(boxes map[string]ledgercore.ModifiedBox) (err error) {
+	for key, bdelta := range boxes {
+		if bdelta.Value != nil {
+			err = w.tx.Put(kvBoxKey(key), []byte(*bdelta.Value))
+		} else {
+			err = w.tx.Delete(kvBoxKey(key))
+		}
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (w *kvAccountStoreWriter) updateAccountsRound(rnd basics.Round, hashRound basics.Round) error {
+	base, err := kvAcctRound(w.tx)
+	if err != nil {
+		return err
+	}
+	if base > rnd {
+		return fmt.Errorf("newRound %d is not after base %d", rnd, base)
+	}
+	return kvPutAcctRound(w.tx, rnd)
+}
@@ -0,0 +1,360 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+	"github.com/algorand/go-algorand/util/kvstore"
+)
+
+// kvBlockTable is the set of key prefixes a table of blocks is stored under in a kvBlockStore. Every block is
+// stored as three entries, its header, its encoded block and its certificate, each under its prefix followed by
+// the round in big endian, so that the entries of a prefix are sorted by round.
+type kvBlockTable struct {
+	hdr  []byte
+	blk  []byte
+	cert []byte
+}
+
+var kvBlocks = kvBlockTable{
+	hdr:  []byte("blocks/hdr/"),
+	blk:  []byte("blocks/blk/"),
+	cert: []byte("blocks/cert/"),
+}
+
+// kvCatchpointBlocks is the staging table of the catchpoint catchup. Its blocks have no certificates.
+var kvCatchpointBlocks = kvBlockTable{
+	hdr:  []byte("catchpointblocks/hdr/"),
+	blk:  []byte("catchpointblocks/blk/"),
+	cert: []byte("catchpointblocks/cert/"),
+}
+
+func (t kvBlockTable) prefixes() [][]byte {
+	return [][]byte{t.hdr, t.blk, t.cert}
+}
+
+func kvRoundKey(prefix []byte, rnd basics.Round) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], uint64(rnd))
+	return key
+}
+
+// kvPrefixEnd returns the first key after all the keys that start with prefix
+func kvPrefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// kvBlockStore is the blockStore of a kvstore.Store
+type kvBlockStore struct {
+	store *kvstore.Store
+}
+
+func openKVBlockStore(filename string, dbMem bool) (*kvBlockStore, error) {
+	store, err := kvstore.Open(filename, dbMem)
+	if err != nil {
+		return nil, err
+	}
+	return &kvBlockStore{store: store}, nil
+}
+
+// kvTableRound returns the first or the last round of the given table, and false if it has no blocks
+func kvTableRound(tx *kvstore.Tx, table kvBlockTable, last bool) (rnd basics.Round, ok bool) {
+	tx.Iterate(table.blk, kvPrefixEnd(table.blk), last, func(key []byte, value []byte) bool {
+		rnd = basics.Round(binary.BigEndian.Uint64(key[len(table.blk):]))
+		ok = true
+		return false
+	})
+	return
+}
+
+func kvBlockPut(tx *kvstore.Tx, table kvBlockTable, blk bookkeeping.Block, cert []byte) error {
+	err := tx.Put(kvRoundKey(table.hdr, blk.Round()), protocol.Encode(&blk.BlockHeader))
+	if err != nil {
+		return err
+	}
+	err = tx.Put(kvRoundKey(table.blk, blk.Round()), protocol.Encode(&blk))
+	if err != nil {
+		return err
+	}
+	if cert != nil {
+		return tx.Put(kvRoundKey(table.cert, blk.Round()), cert)
+	}
+	return nil
+}
+
+func kvBlockPutNext(tx *kvstore.Tx, blk bookkeeping.Block, cert agreement.Certificate) error {
+	latest, ok := kvTableRound(tx, kvBlocks, true)
+	if ok {
+		if blk.Round() != latest+1 {
+			return fmt.Errorf("inserting block %d but expected %d", blk.Round(), latest+1)
+		}
+	} else if blk.Round() != 0 {
+		return fmt.Errorf("inserting block %d but expected 0", blk.Round())
+	}
+	return kvBlockPut(tx, kvBlocks, blk, protocol.Encode(&cert))
+}
+
+func kvDeleteTable(tx *kvstore.Tx, table kvBlockTable) error {
+	for _, prefix := range table.prefixes() {
+		err := tx.DeleteRange(prefix, kvPrefixEnd(prefix))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bs *kvBlockStore) initialize(initBlocks []bookkeeping.Block, isArchival bool, log logging.Logger) error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		earliest, ok := kvTableRound(tx, kvBlocks, false)
+		// an archival node needs all the blocks, so start over if we only have a subsequence of them
+		if ok && isArchival && earliest != basics.Round(0) {
+			log.Warnf("resetting blocks DB (earliest block is %v)", earliest)
+			err := kvDeleteTable(tx, kvBlocks)
+			if err != nil {
+				return fmt.Errorf("initBlocksDB.blockResetDB %v", err)
+			}
+			ok = false
+		}
+		if ok {
+			return nil
+		}
+		for _, blk := range initBlocks {
+			err := kvBlockPutNext(tx, blk, agreement.Certificate{})
+			if err != nil {
+				return fmt.Errorf("initBlocksDB.blockInit %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// getEncoded returns the entry of the given round and table prefix, or ErrNoEntry if there is no such block
+func (bs *kvBlockStore) getEncoded(prefix []byte, rnd basics.Round) (buf []byte, err error) {
+	err = bs.store.View(func(tx *kvstore.Tx) error {
+		value, ok := tx.Get(kvRoundKey(prefix, rnd))
+		if !ok {
+			return ledgercore.ErrNoEntry{Round: rnd}
+		}
+		buf = append([]byte{}, value...)
+		return nil
+	})
+	return
+}
+
+func (bs *kvBlockStore) get(rnd basics.Round) (blk bookkeeping.Block, err error) {
+	buf, err := bs.getEncoded(kvBlocks.blk, rnd)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &blk)
+	return
+}
+
+func (bs *kvBlockStore) getHdr(rnd basics.Round) (hdr bookkeeping.BlockHeader, err error) {
+	buf, err := bs.getEncoded(kvBlocks.hdr, rnd)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(buf, &hdr)
+	return
+}
+
+func (bs *kvBlockStore) getEncodedCert(rnd basics.Round) (blk []byte, cert []byte, err error) {
+	err = bs.store.View(func(tx *kvstore.Tx) error {
+		value, ok := tx.Get(kvRoundKey(kvBlocks.blk, rnd))
+		if !ok {
+			return ledgercore.ErrNoEntry{Round: rnd}
+		}
+		blk = append([]byte{}, value...)
+		if value, ok = tx.Get(kvRoundKey(kvBlocks.cert, rnd)); ok {
+			cert = append([]byte{}, value...)
+		}
+		return nil
+	})
+	return
+}
+
+func (bs *kvBlockStore) getCert(rnd basics.Round) (blk bookkeeping.Block, cert agreement.Certificate, err error) {
+	blkbuf, certbuf, err := bs.getEncodedCert(rnd)
+	if err != nil {
+		return
+	}
+	err = protocol.Decode(blkbuf, &blk)
+	if err != nil {
+		return
+	}
+	if certbuf != nil {
+		err = protocol.Decode(certbuf, &cert)
+	}
+	return
+}
+
+func (bs *kvBlockStore) put(entries []blockEntry) error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		for _, e := range entries {
+			err := kvBlockPutNext(tx, e.block, e.cert)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (bs *kvBlockStore) latest() (rnd basics.Round, err error) {
+	err = bs.store.View(func(tx *kvstore.Tx) error {
+		var ok bool
+		rnd, ok = kvTableRound(tx, kvBlocks, true)
+		if !ok {
+			return fmt.Errorf("no blocks present")
+		}
+		return nil
+	})
+	return
+}
+
+func (bs *kvBlockStore) earliest() (rnd basics.Round, err error) {
+	err = bs.store.View(func(tx *kvstore.Tx) error {
+		var ok bool
+		rnd, ok = kvTableRound(tx, kvBlocks, false)
+		if !ok {
+			return fmt.Errorf("no blocks present")
+		}
+		return nil
+	})
+	return
+}
+
+func (bs *kvBlockStore) forgetBefore(rnd basics.Round) error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		latest, ok := kvTableRound(tx, kvBlocks, true)
+		next := basics.Round(0)
+		if ok {
+			next = latest + 1
+		}
+		if rnd >= next {
+			return fmt.Errorf("forgetting too much: rnd %d >= next %d", rnd, next)
+		}
+		for _, prefix := range kvBlocks.prefixes() {
+			err := tx.DeleteRange(prefix, kvRoundKey(prefix, rnd))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (bs *kvBlockStore) startCatchupStaging(blk bookkeeping.Block) error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		err := kvDeleteTable(tx, kvCatchpointBlocks)
+		if err != nil {
+			return err
+		}
+		return kvBlockPut(tx, kvCatchpointBlocks, blk, nil)
+	})
+}
+
+func (bs *kvBlockStore) putStaging(blk bookkeeping.Block) error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		return kvBlockPut(tx, kvCatchpointBlocks, blk, nil)
+	})
+}
+
+func (bs *kvBlockStore) ensureSingleBlock() (blk bookkeeping.Block, err error) {
+	err = bs.store.Update(func(tx *kvstore.Tx) error {
+		// delete all the blocks that aren't the latest one.
+		round, ok := kvTableRound(tx, kvCatchpointBlocks, true)
+		if !ok {
+			return ledgercore.ErrNoEntry{}
+		}
+		for _, prefix := range kvCatchpointBlocks.prefixes() {
+			err := tx.DeleteRange(prefix, kvRoundKey(prefix, round))
+			if err != nil {
+				return err
+			}
+		}
+		buf, _ := tx.Get(kvRoundKey(kvCatchpointBlocks.blk, round))
+		return protocol.Decode(buf, &blk)
+	})
+	return
+}
+
+func (bs *kvBlockStore) completeCatchup() error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		err := kvDeleteTable(tx, kvBlocks)
+		if err != nil {
+			return err
+		}
+
+		// move the staged blocks into the blocks table
+		var moved [][2][]byte
+		for i, prefix := range kvCatchpointBlocks.prefixes() {
+			target := kvBlocks.prefixes()[i]
+			tx.Iterate(prefix, kvPrefixEnd(prefix), false, func(key []byte, value []byte) bool {
+				newKey := append(append([]byte{}, target...), key[len(prefix):]...)
+				moved = append(moved, [2][]byte{newKey, append([]byte{}, value...)})
+				return true
+			})
+		}
+		for _, kv := range moved {
+			err = tx.Put(kv[0], kv[1])
+			if err != nil {
+				return err
+			}
+		}
+		return kvDeleteTable(tx, kvCatchpointBlocks)
+	})
+}
+
+func (bs *kvBlockStore) abortCatchup() error {
+	return bs.store.Update(func(tx *kvstore.Tx) error {
+		return kvDeleteTable(tx, kvCatchpointBlocks)
+	})
+}
+
+func (bs *kvBlockStore) vacuum(ctx context.Context) error {
+	return bs.store.Compact()
+}
+
+func (bs *kvBlockStore) setSynchronousMode(ctx context.Context, synchronousMode db.SynchronousMode) error {
+	bs.store.SetSynchronous(synchronousMode > db.SynchronousModeOff)
+	return nil
+}
+
+func (bs *kvBlockStore) close() {
+	bs.store.Close()
+}
@@ -18,7 +18,6 @@ package ledger
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"os"
 	"time"
@@ -44,7 +43,10 @@ type Ledger struct {
 	// We use potentially different databases to avoid SQLite contention
 	// during catchup.
 	trackerDBs db.Pair
-	blockDBs   db.Pair
+	blocks     blockStore
+	// accounts is the storage of the account state, which is the tracker database unless another backend is set
+	// by AccountStorageBackend.
+	accounts accountStore
 
 	// blockQ is the buffer of added blocks that will be flushed to
 	// persistent storage
@@ -56,6 +58,10 @@ type Ledger struct {
 	// (archival mode) or trims older blocks to save space (non-archival).
 	archival bool
 
+	// blockRetention determines which blocks a non-archival ledger keeps,
+	// on top of the ones required by the trackers.
+	blockRetention blockRetention
+
 	// the synchronous mode that would be used for the ledger databases.
 	synchronousMode db.SynchronousMode
 
@@ -77,11 +83,21 @@ type Ledger struct {
 	time     timeTracker
 	metrics  metricsTracker
 
+	// history keeps the state of the accounts at every round, when the
+	// account history is enabled.
+	history *accountHistory
+
+	// externalTrackers are the trackers registered by OpenLedgerWithTrackers
+	externalTrackers []ExternalTracker
+
 	trackers  trackerRegistry
 	trackerMu deadlock.RWMutex
 
 	headerCache heapLRUCache
 
+	// catchpointChunks hashes the catchpoint files that don't have their download chunks stored yet
+	catchpointChunks catchpointChunksWriter
+
 	// verifiedTxnCache holds all the verified transactions state
 	verifiedTxnCache verify.VerifiedTransactionCache
 }
@@ -99,6 +115,14 @@ type InitState struct {
 // database wasn't initialized before.
 func OpenLedger(
 	log logging.Logger, dbPathPrefix string, dbMem bool, genesisInitState InitState, cfg config.Local,
+) (*Ledger, error) {
+	return OpenLedgerWithTrackers(log, dbPathPrefix, dbMem, genesisInitState, cfg, nil)
+}
+
+// OpenLedgerWithTrackers is like OpenLedger, but also registers the given
+// external trackers, which are kept in lockstep with the ledger from then on.
+func OpenLedgerWithTrackers(
+	log logging.Logger, dbPathPrefix string, dbMem bool, genesisInitState InitState, cfg config.Local, externalTrackers []ExternalTracker,
 ) (*Ledger, error) {
 	var err error
 	verifiedCacheSize := cfg.VerifiedTranscationsCacheSize
@@ -116,33 +140,48 @@ func OpenLedger(
 		synchronousMode:                db.SynchronousMode(cfg.LedgerSynchronousMode),
 		accountsRebuildSynchronousMode: db.SynchronousMode(cfg.AccountsRebuildSynchronousMode),
 		verifiedTxnCache:               verify.MakeVerifiedTransactionCache(verifiedCacheSize),
+		externalTrackers:               externalTrackers,
+	}
+
+	l.blockRetention, err = makeBlockRetention(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("OpenLedger.makeBlockRetention %v", err)
+	}
+
+	if cfg.Archival && cfg.BlockStorageBackend == StorageBackendKV {
+		// the key-value store keeps all of its data in memory, which can't hold all the blocks of an archival node.
+		return nil, fmt.Errorf("OpenLedger: the '%s' block storage backend cannot be used by an archival node", StorageBackendKV)
 	}
 
 	l.headerCache.maxEntries = 10
 
+	if cfg.Archival && cfg.EnableAccountHistory {
+		l.history = &accountHistory{}
+	}
+
 	defer func() {
 		if err != nil {
 			l.Close()
 		}
 	}()
 
-	l.trackerDBs, l.blockDBs, err = openLedgerDB(dbPathPrefix, dbMem)
+	l.trackerDBs, l.blocks, l.accounts, err = openLedgerDB(dbPathPrefix, dbMem, cfg.BlockStorageBackend, cfg.AccountStorageBackend)
 	if err != nil {
 		err = fmt.Errorf("OpenLedger.openLedgerDB %v", err)
 		return nil, err
 	}
 	l.trackerDBs.Rdb.SetLogger(log)
 	l.trackerDBs.Wdb.SetLogger(log)
-	l.blockDBs.Rdb.SetLogger(log)
-	l.blockDBs.Wdb.SetLogger(log)
+	if sqliteBlocks, ok := l.blocks.(*sqliteBlockStore); ok {
+		sqliteBlocks.dbs.Rdb.SetLogger(log)
+		sqliteBlocks.dbs.Wdb.SetLogger(log)
+	}
 
 	l.setSynchronousMode(context.Background(), l.synchronousMode)
 
 	start := time.Now()
 	ledgerInitblocksdbCount.Inc(nil)
-	err = l.blockDBs.Wdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		return initBlocksDB(tx, l, []bookkeeping.Block{genesisInitState.Block}, cfg.Archival)
-	})
+	err = l.blocks.initialize([]bookkeeping.Block{genesisInitState.Block}, cfg.Archival, l.log)
 	ledgerInitblocksdbMicros.AddMicrosecondsSince(start, nil)
 	if err != nil {
 		err = fmt.Errorf("OpenLedger.initBlocksDB %v", err)
@@ -155,6 +194,12 @@ func OpenLedger(
 
 	l.accts.initialize(cfg, dbPathPrefix, l.genesisProto, l.genesisAccounts)
 
+	if _, ok := l.accounts.(*sqliteAccountStore); !ok && l.accts.catchpointInterval != 0 {
+		// the merkle trie and the catchpoints are only maintained in the SQLite tracker database.
+		err = fmt.Errorf("OpenLedger: the '%s' account storage backend cannot be used by a node which tracks catchpoints", cfg.AccountStorageBackend)
+		return nil, err
+	}
+
 	err = l.reloadLedger()
 	if err != nil {
 		return nil, err
@@ -193,6 +238,12 @@ func (l *Ledger) reloadLedger() error {
 	l.trackers.register(&l.bulletin) // provide closed channel signaling support for completed rounds
 	l.trackers.register(&l.notifier) // send OnNewBlocks to subscribers
 	l.trackers.register(&l.metrics)  // provides metrics reporting support
+	if l.history != nil {
+		l.trackers.register(makeExternalTracker(l.history, &l.accts)) // keeps the accounts history
+	}
+	for _, et := range l.externalTrackers {
+		l.trackers.register(makeExternalTracker(et, &l.accts))
+	}
 
 	err = l.trackers.loadFromDisk(l)
 	if err != nil {
@@ -213,13 +264,13 @@ func (l *Ledger) verifyMatchingGenesisHash() (err error) {
 	// Check that the genesis hash, if present, matches.
 	start := time.Now()
 	ledgerVerifygenhashCount.Inc(nil)
-	err = l.blockDBs.Rdb.Atomic(func(ctx context.Context, tx *sql.Tx) error {
-		latest, err := blockLatest(tx)
+	err = func() error {
+		latest, err := l.blocks.latest()
 		if err != nil {
 			return err
 		}
 
-		hdr, err := blockGetHdr(tx, latest)
+		hdr, err := l.blocks.getHdr(latest)
 		if err != nil {
 			return err
 		}
@@ -232,16 +283,15 @@ func (l *Ledger) verifyMatchingGenesisHash() (err error) {
 			)
 		}
 		return nil
-	})
+	}()
 	ledgerVerifygenhashMicros.AddMicrosecondsSince(start, nil)
 	return
 }
 
-func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs db.Pair, blockDBs db.Pair, err error) {
+func openLedgerDB(dbPathPrefix string, dbMem bool, blockBackend string, accountBackend string) (trackerDBs db.Pair, blocks blockStore, accounts accountStore, err error) {
 	// Backwards compatibility: we used to store both blocks and tracker
 	// state in a single SQLite db file.
 	var trackerDBFilename string
-	var blockDBFilename string
 
 	if !dbMem {
 		commonDBFilename := dbPathPrefix + ".sqlite"
@@ -256,7 +306,6 @@ func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs db.Pair, blockDBs
 	}
 
 	trackerDBFilename = dbPathPrefix + ".tracker.sqlite"
-	blockDBFilename = dbPathPrefix + ".block.sqlite"
 
 	outErr := make(chan error, 2)
 	go func() {
@@ -267,7 +316,7 @@ func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs db.Pair, blockDBs
 
 	go func() {
 		var lerr error
-		blockDBs, lerr = db.OpenPair(blockDBFilename, dbMem)
+		blocks, lerr = openBlockStore(blockBackend, dbPathPrefix, dbMem)
 		outErr <- lerr
 	}()
 
@@ -276,6 +325,11 @@ func openLedgerDB(dbPathPrefix string, dbMem bool) (trackerDBs db.Pair, blockDBs
 		return
 	}
 	err = <-outErr
+	if err != nil {
+		return
+	}
+
+	accounts, err = openAccountStore(accountBackend, dbPathPrefix, dbMem, trackerDBs)
 	return
 }
 
@@ -286,7 +340,7 @@ func (l *Ledger) setSynchronousMode(ctx context.Context, synchronousMode db.Sync
 		return
 	}
 
-	err := l.blockDBs.Wdb.SetSynchronousMode(ctx, synchronousMode, synchronousMode >= db.SynchronousModeFull)
+	err := l.blocks.setSynchronousMode(ctx, synchronousMode)
 	if err != nil {
 		l.log.Warnf("ledger.setSynchronousMode unable to set synchronous mode on blocks db: %v", err)
 		return
@@ -297,44 +351,14 @@ func (l *Ledger) setSynchronousMode(ctx context.Context, synchronousMode db.Sync
 		l.log.Warnf("ledger.setSynchronousMode unable to set synchronous mode on trackers db: %v", err)
 		return
 	}
-}
 
-// initBlocksDB performs DB initialization:
-// - creates and populates it with genesis blocks
-// - ensures DB is in good shape for archival mode and resets it if not
-func initBlocksDB(tx *sql.Tx, l *Ledger, initBlocks []bookkeeping.Block, isArchival bool) (err error) {
-	err = blockInit(tx, initBlocks)
-	if err != nil {
-		err = fmt.Errorf("initBlocksDB.blockInit %v", err)
-		return err
-	}
-
-	// in archival mode check if DB contains all blocks up to the latest
-	if isArchival {
-		earliest, err := blockEarliest(tx)
+	if _, ok := l.accounts.(*sqliteAccountStore); !ok {
+		err = l.accounts.setSynchronousMode(ctx, synchronousMode)
 		if err != nil {
-			err = fmt.Errorf("initBlocksDB.blockEarliest %v", err)
-			return err
-		}
-
-		// Detect possible problem - archival node needs all block but have only subsequence of them
-		// So reset the DB and init it again
-		if earliest != basics.Round(0) {
-			l.log.Warnf("resetting blocks DB (earliest block is %v)", earliest)
-			err := blockResetDB(tx)
-			if err != nil {
-				err = fmt.Errorf("initBlocksDB.blockResetDB %v", err)
-				return err
-			}
-			err = blockInit(tx, initBlocks)
-			if err != nil {
-				err = fmt.Errorf("initBlocksDB.blockInit 2 %v", err)
-				return err
-			}
+			l.log.Warnf("ledger.setSynchronousMode unable to set synchronous mode on accounts storage: %v", err)
+			return
 		}
 	}
-
-	return nil
 }
 
 // Close reclaims resources used by the ledger (namely, the database connection
@@ -355,7 +379,12 @@ func (l *Ledger) Close() {
 	l.trackers.close()
 
 	// last, we close the underlying database connections.
-	l.blockDBs.Close()
+	if l.blocks != nil {
+		l.blocks.close()
+	}
+	if l.accounts != nil {
+		l.accounts.close()
+	}
 	l.trackerDBs.Close()
 }
 
@@ -395,7 +424,11 @@ func (l *Ledger) GetLastCatchpointLabel() string {
 func (l *Ledger) GetCreatorForRound(rnd basics.Round, cidx basics.CreatableIndex, ctype basics.CreatableType) (creator basics.Address, ok bool, err error) {
 	l.trackerMu.RLock()
 	defer l.trackerMu.RUnlock()
-	return l.accts.GetCreatorForRound(rnd, cidx, ctype)
+	creator, ok, err = l.accts.GetCreatorForRound(rnd, cidx, ctype)
+	if l.isHistoricalRound(err) {
+		return l.history.getCreator(rnd, cidx, ctype)
+	}
+	return
 }
 
 // GetCreator is like GetCreatorForRound, but for the latest round and race-free
@@ -433,6 +466,22 @@ func (l *Ledger) ListApplications(maxAppIdx basics.AppIndex, maxResults uint64)
 	return l.accts.ListApplications(maxAppIdx, maxResults)
 }
 
+// ListBoxes returns, in ascending order, up to maxResults names of the boxes
+// of the given application. A zero maxResults returns all of them.
+func (l *Ledger) ListBoxes(appIdx basics.AppIndex, maxResults uint64) ([]string, error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.ListBoxes(appIdx, maxResults)
+}
+
+// LookupBox returns the content of the box stored under the given key, as
+// created by ledgercore.MakeBoxKey, as of round rnd.
+func (l *Ledger) LookupBox(rnd basics.Round, key string) (value string, ok bool, err error) {
+	l.trackerMu.RLock()
+	defer l.trackerMu.RUnlock()
+	return l.accts.LookupBox(rnd, key)
+}
+
 // Lookup uses the accounts tracker to return the account state for a
 // given account in a particular round.  The account values reflect
 // the changes of all blocks up to and including rnd.
@@ -442,6 +491,9 @@ func (l *Ledger) Lookup(rnd basics.Round, addr basics.Address) (basics.AccountDa
 
 	// Intentionally apply (pending) rewards up to rnd.
 	data, err := l.accts.LookupWithRewards(rnd, addr)
+	if l.isHistoricalRound(err) {
+		data, err = l.lookupHistoryWithRewards(rnd, addr)
+	}
 	if err != nil {
 		return basics.AccountData{}, err
 	}
@@ -456,6 +508,10 @@ func (l *Ledger) LookupWithoutRewards(rnd basics.Round, addr basics.Address) (ba
 	defer l.trackerMu.RUnlock()
 
 	data, validThrough, err := l.accts.LookupWithoutRewards(rnd, addr)
+	if l.isHistoricalRound(err) {
+		data, err = l.history.lookup(rnd, addr)
+		validThrough = rnd
+	}
 	if err != nil {
 		return basics.AccountData{}, basics.Round(0), err
 	}
@@ -463,6 +519,30 @@ func (l *Ledger) LookupWithoutRewards(rnd basics.Round, addr basics.Address) (ba
 	return data, validThrough, nil
 }
 
+// isHistoricalRound tells whether err is the error of a lookup at a round
+// the accounts tracker no longer has, which the accounts history might.
+func (l *Ledger) isHistoricalRound(err error) bool {
+	if l.history == nil {
+		return false
+	}
+	_, ok := err.(*RoundOffsetError)
+	return ok
+}
+
+// lookupHistoryWithRewards looks up the accounts history, applying the
+// pending rewards up to round rnd.
+func (l *Ledger) lookupHistoryWithRewards(rnd basics.Round, addr basics.Address) (basics.AccountData, error) {
+	data, err := l.history.lookup(rnd, addr)
+	if err != nil {
+		return basics.AccountData{}, err
+	}
+	hdr, err := l.BlockHdr(rnd)
+	if err != nil {
+		return basics.AccountData{}, err
+	}
+	return data.WithUpdatedRewards(config.Consensus[hdr.CurrentProtocol], hdr.RewardsLevel), nil
+}
+
 // Totals returns the totals of all accounts at the end of round rnd.
 func (l *Ledger) Totals(rnd basics.Round) (ledgercore.AccountTotals, error) {
 	l.trackerMu.RLock()
@@ -597,6 +677,10 @@ func (l *Ledger) GenesisProto() config.ConsensusParams {
 
 // GetCatchpointCatchupState returns the current state of the catchpoint catchup.
 func (l *Ledger) GetCatchpointCatchupState(ctx context.Context) (state CatchpointCatchupState, err error) {
+	if _, ok := l.accounts.(*sqliteAccountStore); !ok {
+		// a catchpoint catchup can't be started without the accounts in the tracker database.
+		return CatchpointCatchupStateInactive, nil
+	}
 	return MakeCatchpointCatchupAccessor(l, l.log).GetState(ctx)
 }
 
@@ -611,14 +695,32 @@ func (l *Ledger) GetCatchpointStream(round basics.Round) (ReadCloseSizer, error)
 	return l.accts.GetCatchpointStream(round)
 }
 
+// GetCatchpointDownloadChunks returns the download chunks of the catchpoint file for the provided round, so that
+// the file could be downloaded from several peers in parallel. The chunks are computed when the catchpoint file is
+// written, and only hashed here for the catchpoint files that were written before.
+func (l *Ledger) GetCatchpointDownloadChunks(round basics.Round) (CatchpointDownloadChunks, error) {
+	l.trackerMu.RLock()
+	catchpointPath, fileSize, err := l.accts.getCatchpointFile(round)
+	l.trackerMu.RUnlock()
+	if err != nil {
+		return CatchpointDownloadChunks{}, err
+	}
+	return l.catchpointChunks.get(catchpointPath, fileSize)
+}
+
 // ledgerForTracker methods
 func (l *Ledger) trackerDB() db.Pair {
 	return l.trackerDBs
 }
 
 // ledgerForTracker methods
-func (l *Ledger) blockDB() db.Pair {
-	return l.blockDBs
+func (l *Ledger) blockStorage() blockStore {
+	return l.blocks
+}
+
+// ledgerForTracker methods
+func (l *Ledger) accountStorage() accountStore {
+	return l.accounts
 }
 
 func (l *Ledger) trackerLog() logging.Logger {
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledgercore
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+// boxKeyPrefix is prepended to every box key, so that box keys are distinguishable
+// from any other kind of key that may share a key/value table in the future.
+const boxKeyPrefix = "bx:"
+
+// MakeBoxKey returns the key under which the box with the given name, owned by
+// the given application, is stored. Keys of boxes belonging to the same
+// application share a common prefix, which is returned by BoxKeyAppPrefix.
+func MakeBoxKey(appIdx basics.AppIndex, name string) string {
+	return BoxKeyAppPrefix(appIdx) + name
+}
+
+// BoxKeyAppPrefix returns the prefix shared by all the box keys of the given application.
+func BoxKeyAppPrefix(appIdx basics.AppIndex) string {
+	var buf [len(boxKeyPrefix) + 8]byte
+	copy(buf[:], boxKeyPrefix)
+	binary.BigEndian.PutUint64(buf[len(boxKeyPrefix):], uint64(appIdx))
+	return string(buf[:])
+}
+
+// SplitBoxKey is the inverse of MakeBoxKey, and returns the application index and
+// the box name encoded in the given key.
+func SplitBoxKey(key string) (basics.AppIndex, string, error) {
+	if len(key) < len(boxKeyPrefix)+8 || key[:len(boxKeyPrefix)] != boxKeyPrefix {
+		return 0, "", fmt.Errorf("SplitBoxKey() invalid box key %x", key)
+	}
+	appIdx := basics.AppIndex(binary.BigEndian.Uint64([]byte(key[len(boxKeyPrefix) : len(boxKeyPrefix)+8])))
+	return appIdx, key[len(boxKeyPrefix)+8:], nil
+}
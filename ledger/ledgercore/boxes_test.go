@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledgercore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/data/basics"
+)
+
+func TestBoxKey(t *testing.T) {
+	a := require.New(t)
+
+	for _, name := range []string{"a", "box", string([]byte{0, 1, 2}), strings.Repeat("x", 64)} {
+		for _, appIdx := range []basics.AppIndex{1, 255, 256, 1 << 40} {
+			key := MakeBoxKey(appIdx, name)
+			a.True(strings.HasPrefix(key, BoxKeyAppPrefix(appIdx)))
+			app, n, err := SplitBoxKey(key)
+			a.NoError(err)
+			a.Equal(appIdx, app)
+			a.Equal(name, n)
+		}
+	}
+
+	// keys of different applications must not share a prefix
+	a.False(strings.HasPrefix(MakeBoxKey(2, "a"), BoxKeyAppPrefix(1)))
+
+	_, _, err := SplitBoxKey("bx:")
+	a.Error(err)
+	_, _, err = SplitBoxKey("ab:12345678")
+	a.Error(err)
+}
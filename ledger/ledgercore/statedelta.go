@@ -28,6 +28,7 @@ const (
 	accountMapCacheEntrySize              = uint64(64)  // Measured by BenchmarkAcctCache
 	txleasesEntrySize                     = uint64(112) // Measured by BenchmarkTxLeases
 	creatablesEntrySize                   = uint64(100) // Measured by BenchmarkCreatables
+	boxesEntrySize                        = uint64(120) // Rough estimate, excluding the box value itself
 	stateDeltaTargetOptimizationThreshold = uint64(50000000)
 )
 
@@ -47,6 +48,16 @@ type ModifiedCreatable struct {
 	Ndeltas int
 }
 
+// ModifiedBox defines the changes to a single application box
+type ModifiedBox struct {
+	// Value is the new content of the box, or nil if the box was deleted
+	Value *string
+
+	// Keeps track of how many times this box appears in
+	// accountUpdates.boxDeltas
+	Ndeltas int
+}
+
 // A Txlease is a transaction (sender, lease) pair which uniquely specifies a
 // transaction lease.
 type Txlease struct {
@@ -68,6 +79,9 @@ type StateDelta struct {
 	// new creatables creator lookup table
 	Creatables map[basics.CreatableIndex]ModifiedCreatable
 
+	// modified application boxes, keyed by MakeBoxKey
+	Boxes map[string]ModifiedBox
+
 	// new block header; read-only
 	Hdr *bookkeeping.BlockHeader
 
@@ -103,6 +117,7 @@ func MakeStateDelta(hdr *bookkeeping.BlockHeader, prevTimestamp int64, hint int,
 		Txleases: make(map[Txlease]basics.Round, hint),
 		// asset or application creation are considered as rare events so do not pre-allocate space for them
 		Creatables:               make(map[basics.CreatableIndex]ModifiedCreatable),
+		Boxes:                    make(map[string]ModifiedBox),
 		Hdr:                      hdr,
 		PrevTimestamp:            prevTimestamp,
 		initialTransactionsCount: hint,
@@ -206,4 +221,13 @@ func (sd *StateDelta) OptimizeAllocatedMemory(proto config.ConsensusParams) {
 		}
 		sd.Creatables = creatableDeltas
 	}
+
+	// Boxes are saved for 320 rounds as well
+	if uint64(len(sd.Boxes))*boxesEntrySize*proto.MaxBalLookback > stateDeltaTargetOptimizationThreshold {
+		boxDeltas := make(map[string]ModifiedBox, len(sd.Boxes))
+		for k, v := range sd.Boxes {
+			boxDeltas[k] = v
+		}
+		sd.Boxes = boxDeltas
+	}
 }
@@ -3,6 +3,8 @@ package ledger
 // Code generated by github.com/algorand/msgp DO NOT EDIT.
 
 import (
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/crypto"
 	"github.com/algorand/msgp/msgp"
 )
 
@@ -15,6 +17,14 @@ import (
 //            |-----> Msgsize
 //            |-----> MsgIsZero
 //
+// CatchpointDownloadChunks
+//             |-----> (*) MarshalMsg
+//             |-----> (*) CanMarshalMsg
+//             |-----> (*) UnmarshalMsg
+//             |-----> (*) CanUnmarshalMsg
+//             |-----> (*) Msgsize
+//             |-----> (*) MsgIsZero
+//
 // CatchpointFileHeader
 //           |-----> (*) MarshalMsg
 //           |-----> (*) CanMarshalMsg
@@ -31,6 +41,22 @@ import (
 //              |-----> (*) Msgsize
 //              |-----> (*) MsgIsZero
 //
+// catchpointFileBoxesChunk
+//             |-----> (*) MarshalMsg
+//             |-----> (*) CanMarshalMsg
+//             |-----> (*) UnmarshalMsg
+//             |-----> (*) CanUnmarshalMsg
+//             |-----> (*) Msgsize
+//             |-----> (*) MsgIsZero
+//
+// catchpointFileResourcesChunk
+//               |-----> (*) MarshalMsg
+//               |-----> (*) CanMarshalMsg
+//               |-----> (*) UnmarshalMsg
+//               |-----> (*) CanUnmarshalMsg
+//               |-----> (*) Msgsize
+//               |-----> (*) MsgIsZero
+//
 // catchpointState
 //        |-----> MarshalMsg
 //        |-----> CanMarshalMsg
@@ -47,6 +73,22 @@ import (
 //           |-----> (*) Msgsize
 //           |-----> (*) MsgIsZero
 //
+// encodedBoxRecord
+//         |-----> (*) MarshalMsg
+//         |-----> (*) CanMarshalMsg
+//         |-----> (*) UnmarshalMsg
+//         |-----> (*) CanUnmarshalMsg
+//         |-----> (*) Msgsize
+//         |-----> (*) MsgIsZero
+//
+// encodedResourceRecord
+//           |-----> (*) MarshalMsg
+//           |-----> (*) CanMarshalMsg
+//           |-----> (*) UnmarshalMsg
+//           |-----> (*) CanUnmarshalMsg
+//           |-----> (*) Msgsize
+//           |-----> (*) MsgIsZero
+//
 // storageAction
 //       |-----> MarshalMsg
 //       |-----> CanMarshalMsg
@@ -102,12 +144,216 @@ func (z CatchpointCatchupState) MsgIsZero() bool {
 	return z == 0
 }
 
+// MarshalMsg implements msgp.Marshaler
+func (z *CatchpointDownloadChunks) MarshalMsg(b []byte) (o []byte) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0002Len := uint32(3)
+	var zb0002Mask uint8 /* 4 bits */
+	if (*z).ChunkSize == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x2
+	}
+	if len((*z).Hashes) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x4
+	}
+	if (*z).FileSize == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x8
+	}
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x2) == 0 { // if not empty
+			// string "chunksize"
+			o = append(o, 0xa9, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x69, 0x7a, 0x65)
+			o = msgp.AppendUint64(o, (*z).ChunkSize)
+		}
+		if (zb0002Mask & 0x4) == 0 { // if not empty
+			// string "hashes"
+			o = append(o, 0xa6, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73)
+			if (*z).Hashes == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).Hashes)))
+			}
+			for zb0001 := range (*z).Hashes {
+				o = (*z).Hashes[zb0001].MarshalMsg(o)
+			}
+		}
+		if (zb0002Mask & 0x8) == 0 { // if not empty
+			// string "size"
+			o = append(o, 0xa4, 0x73, 0x69, 0x7a, 0x65)
+			o = msgp.AppendUint64(o, (*z).FileSize)
+		}
+	}
+	return
+}
+
+func (_ *CatchpointDownloadChunks) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*CatchpointDownloadChunks)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *CatchpointDownloadChunks) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0002 > 0 {
+			zb0002--
+			(*z).FileSize, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "FileSize")
+				return
+			}
+		}
+		if zb0002 > 0 {
+			zb0002--
+			(*z).ChunkSize, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "ChunkSize")
+				return
+			}
+		}
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Hashes")
+				return
+			}
+			if zb0004 > MaxCatchpointDownloadChunks {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(MaxCatchpointDownloadChunks))
+				err = msgp.WrapError(err, "struct-from-array", "Hashes")
+				return
+			}
+			if zb0005 {
+				(*z).Hashes = nil
+			} else if (*z).Hashes != nil && cap((*z).Hashes) >= zb0004 {
+				(*z).Hashes = ((*z).Hashes)[:zb0004]
+			} else {
+				(*z).Hashes = make([]crypto.Digest, zb0004)
+			}
+			for zb0001 := range (*z).Hashes {
+				bts, err = (*z).Hashes[zb0001].UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "struct-from-array", "Hashes", zb0001)
+					return
+				}
+			}
+		}
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0003 {
+			(*z) = CatchpointDownloadChunks{}
+		}
+		for zb0002 > 0 {
+			zb0002--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "size":
+				(*z).FileSize, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "FileSize")
+					return
+				}
+			case "chunksize":
+				(*z).ChunkSize, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "ChunkSize")
+					return
+				}
+			case "hashes":
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Hashes")
+					return
+				}
+				if zb0006 > MaxCatchpointDownloadChunks {
+					err = msgp.ErrOverflow(uint64(zb0006), uint64(MaxCatchpointDownloadChunks))
+					err = msgp.WrapError(err, "Hashes")
+					return
+				}
+				if zb0007 {
+					(*z).Hashes = nil
+				} else if (*z).Hashes != nil && cap((*z).Hashes) >= zb0006 {
+					(*z).Hashes = ((*z).Hashes)[:zb0006]
+				} else {
+					(*z).Hashes = make([]crypto.Digest, zb0006)
+				}
+				for zb0001 := range (*z).Hashes {
+					bts, err = (*z).Hashes[zb0001].UnmarshalMsg(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "Hashes", zb0001)
+						return
+					}
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *CatchpointDownloadChunks) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*CatchpointDownloadChunks)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *CatchpointDownloadChunks) Msgsize() (s int) {
+	s = 1 + 5 + msgp.Uint64Size + 10 + msgp.Uint64Size + 7 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).Hashes {
+		s += (*z).Hashes[zb0001].Msgsize()
+	}
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *CatchpointDownloadChunks) MsgIsZero() bool {
+	return ((*z).FileSize == 0) && ((*z).ChunkSize == 0) && (len((*z).Hashes) == 0)
+}
+
 // MarshalMsg implements msgp.Marshaler
 func (z *CatchpointFileHeader) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
-	zb0001Len := uint32(8)
-	var zb0001Mask uint16 /* 9 bits */
+	zb0001Len := uint32(12)
+	var zb0001Mask uint16 /* 13 bits */
 	if (*z).Totals.MsgIsZero() {
 		zb0001Len--
 		zb0001Mask |= 0x2
@@ -128,18 +374,34 @@ func (z *CatchpointFileHeader) MarshalMsg(b []byte) (o []byte) {
 		zb0001Len--
 		zb0001Mask |= 0x20
 	}
-	if (*z).Catchpoint == "" {
+	if (*z).TotalBoxChunks == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x40
 	}
-	if (*z).TotalChunks == 0 {
+	if (*z).TotalBoxes == 0 {
 		zb0001Len--
 		zb0001Mask |= 0x80
 	}
-	if (*z).Version == 0 {
+	if (*z).Catchpoint == "" {
 		zb0001Len--
 		zb0001Mask |= 0x100
 	}
+	if (*z).TotalChunks == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x200
+	}
+	if (*z).TotalResourceChunks == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x400
+	}
+	if (*z).TotalResources == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x800
+	}
+	if (*z).Version == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x1000
+	}
 	// variable map header, size zb0001Len
 	o = append(o, 0x80|uint8(zb0001Len))
 	if zb0001Len != 0 {
@@ -169,16 +431,36 @@ func (z *CatchpointFileHeader) MarshalMsg(b []byte) (o []byte) {
 			o = (*z).BlocksRound.MarshalMsg(o)
 		}
 		if (zb0001Mask & 0x40) == 0 { // if not empty
+			// string "boxChunksCount"
+			o = append(o, 0xae, 0x62, 0x6f, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+			o = msgp.AppendUint64(o, (*z).TotalBoxChunks)
+		}
+		if (zb0001Mask & 0x80) == 0 { // if not empty
+			// string "boxesCount"
+			o = append(o, 0xaa, 0x62, 0x6f, 0x78, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+			o = msgp.AppendUint64(o, (*z).TotalBoxes)
+		}
+		if (zb0001Mask & 0x100) == 0 { // if not empty
 			// string "catchpoint"
 			o = append(o, 0xaa, 0x63, 0x61, 0x74, 0x63, 0x68, 0x70, 0x6f, 0x69, 0x6e, 0x74)
 			o = msgp.AppendString(o, (*z).Catchpoint)
 		}
-		if (zb0001Mask & 0x80) == 0 { // if not empty
+		if (zb0001Mask & 0x200) == 0 { // if not empty
 			// string "chunksCount"
 			o = append(o, 0xab, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
 			o = msgp.AppendUint64(o, (*z).TotalChunks)
 		}
-		if (zb0001Mask & 0x100) == 0 { // if not empty
+		if (zb0001Mask & 0x400) == 0 { // if not empty
+			// string "resourceChunksCount"
+			o = append(o, 0xb3, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+			o = msgp.AppendUint64(o, (*z).TotalResourceChunks)
+		}
+		if (zb0001Mask & 0x800) == 0 { // if not empty
+			// string "resourcesCount"
+			o = append(o, 0xae, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74)
+			o = msgp.AppendUint64(o, (*z).TotalResources)
+		}
+		if (zb0001Mask & 0x1000) == 0 { // if not empty
 			// string "version"
 			o = append(o, 0xa7, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e)
 			o = msgp.AppendUint64(o, (*z).Version)
@@ -253,6 +535,38 @@ func (z *CatchpointFileHeader) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				return
 			}
 		}
+		if zb0001 > 0 {
+			zb0001--
+			(*z).TotalBoxes, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalBoxes")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			(*z).TotalBoxChunks, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalBoxChunks")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			(*z).TotalResources, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalResources")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			(*z).TotalResourceChunks, bts, err = msgp.ReadUint64Bytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "TotalResourceChunks")
+				return
+			}
+		}
 		if zb0001 > 0 {
 			zb0001--
 			(*z).Catchpoint, bts, err = msgp.ReadStringBytes(bts)
@@ -328,6 +642,30 @@ func (z *CatchpointFileHeader) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "TotalChunks")
 					return
 				}
+			case "boxesCount":
+				(*z).TotalBoxes, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalBoxes")
+					return
+				}
+			case "boxChunksCount":
+				(*z).TotalBoxChunks, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalBoxChunks")
+					return
+				}
+			case "resourcesCount":
+				(*z).TotalResources, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalResources")
+					return
+				}
+			case "resourceChunksCount":
+				(*z).TotalResourceChunks, bts, err = msgp.ReadUint64Bytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "TotalResourceChunks")
+					return
+				}
 			case "catchpoint":
 				(*z).Catchpoint, bts, err = msgp.ReadStringBytes(bts)
 				if err != nil {
@@ -360,13 +698,13 @@ func (_ *CatchpointFileHeader) CanUnmarshalMsg(z interface{}) bool {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *CatchpointFileHeader) Msgsize() (s int) {
-	s = 1 + 8 + msgp.Uint64Size + 14 + (*z).BalancesRound.Msgsize() + 12 + (*z).BlocksRound.Msgsize() + 14 + (*z).Totals.Msgsize() + 14 + msgp.Uint64Size + 12 + msgp.Uint64Size + 11 + msgp.StringPrefixSize + len((*z).Catchpoint) + 18 + (*z).BlockHeaderDigest.Msgsize()
+	s = 1 + 8 + msgp.Uint64Size + 14 + (*z).BalancesRound.Msgsize() + 12 + (*z).BlocksRound.Msgsize() + 14 + (*z).Totals.Msgsize() + 14 + msgp.Uint64Size + 12 + msgp.Uint64Size + 11 + msgp.Uint64Size + 15 + msgp.Uint64Size + 15 + msgp.Uint64Size + 20 + msgp.Uint64Size + 11 + msgp.StringPrefixSize + len((*z).Catchpoint) + 18 + (*z).BlockHeaderDigest.Msgsize()
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
 func (z *CatchpointFileHeader) MsgIsZero() bool {
-	return ((*z).Version == 0) && ((*z).BalancesRound.MsgIsZero()) && ((*z).BlocksRound.MsgIsZero()) && ((*z).Totals.MsgIsZero()) && ((*z).TotalAccounts == 0) && ((*z).TotalChunks == 0) && ((*z).Catchpoint == "") && ((*z).BlockHeaderDigest.MsgIsZero())
+	return ((*z).Version == 0) && ((*z).BalancesRound.MsgIsZero()) && ((*z).BlocksRound.MsgIsZero()) && ((*z).Totals.MsgIsZero()) && ((*z).TotalAccounts == 0) && ((*z).TotalChunks == 0) && ((*z).TotalBoxes == 0) && ((*z).TotalBoxChunks == 0) && ((*z).TotalResources == 0) && ((*z).TotalResourceChunks == 0) && ((*z).Catchpoint == "") && ((*z).BlockHeaderDigest.MsgIsZero())
 }
 
 // MarshalMsg implements msgp.Marshaler
@@ -678,58 +1016,1126 @@ func (z *catchpointFileBalancesChunk) MsgIsZero() bool {
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z catchpointState) MarshalMsg(b []byte) (o []byte) {
+func (z *catchpointFileBoxesChunk) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
-	o = msgp.AppendString(o, string(z))
+	// omitempty: check for empty values
+	zb0002Len := uint32(1)
+	var zb0002Mask uint8 /* 2 bits */
+	if len((*z).Boxes) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x2
+	}
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x2) == 0 { // if not empty
+			// string "bx"
+			o = append(o, 0xa2, 0x62, 0x78)
+			if (*z).Boxes == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).Boxes)))
+			}
+			for zb0001 := range (*z).Boxes {
+				// omitempty: check for empty values
+				zb0003Len := uint32(2)
+				var zb0003Mask uint8 /* 3 bits */
+				if len((*z).Boxes[zb0001].Key) == 0 {
+					zb0003Len--
+					zb0003Mask |= 0x2
+				}
+				if len((*z).Boxes[zb0001].Value) == 0 {
+					zb0003Len--
+					zb0003Mask |= 0x4
+				}
+				// variable map header, size zb0003Len
+				o = append(o, 0x80|uint8(zb0003Len))
+				if (zb0003Mask & 0x2) == 0 { // if not empty
+					// string "k"
+					o = append(o, 0xa1, 0x6b)
+					o = msgp.AppendBytes(o, (*z).Boxes[zb0001].Key)
+				}
+				if (zb0003Mask & 0x4) == 0 { // if not empty
+					// string "v"
+					o = append(o, 0xa1, 0x76)
+					o = msgp.AppendBytes(o, (*z).Boxes[zb0001].Value)
+				}
+			}
+		}
+	}
 	return
 }
 
-func (_ catchpointState) CanMarshalMsg(z interface{}) bool {
-	_, ok := (z).(catchpointState)
-	if !ok {
-		_, ok = (z).(*catchpointState)
-	}
+func (_ *catchpointFileBoxesChunk) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointFileBoxesChunk)
 	return ok
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *catchpointState) UnmarshalMsg(bts []byte) (o []byte, err error) {
-	{
-		var zb0001 string
-		zb0001, bts, err = msgp.ReadStringBytes(bts)
+func (z *catchpointFileBoxesChunk) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
 		if err != nil {
 			err = msgp.WrapError(err)
 			return
 		}
-		(*z) = catchpointState(zb0001)
-	}
-	o = bts
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Boxes")
+				return
+			}
+			if zb0004 > BoxesPerCatchpointFileChunk {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(BoxesPerCatchpointFileChunk))
+				err = msgp.WrapError(err, "struct-from-array", "Boxes")
+				return
+			}
+			if zb0005 {
+				(*z).Boxes = nil
+			} else if (*z).Boxes != nil && cap((*z).Boxes) >= zb0004 {
+				(*z).Boxes = ((*z).Boxes)[:zb0004]
+			} else {
+				(*z).Boxes = make([]encodedBoxRecord, zb0004)
+			}
+			for zb0001 := range (*z).Boxes {
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadMapHeaderBytes(bts)
+				if _, ok := err.(msgp.TypeError); ok {
+					zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001)
+						return
+					}
+					if zb0006 > 0 {
+						zb0006--
+						var zb0008 int
+						zb0008, err = msgp.ReadBytesBytesHeader(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "struct-from-array", "Key")
+							return
+						}
+						if zb0008 > encodedMaxBoxKeyLen {
+							err = msgp.ErrOverflow(uint64(zb0008), uint64(encodedMaxBoxKeyLen))
+							return
+						}
+						(*z).Boxes[zb0001].Key, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Key)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "struct-from-array", "Key")
+							return
+						}
+					}
+					if zb0006 > 0 {
+						zb0006--
+						var zb0009 int
+						zb0009, err = msgp.ReadBytesBytesHeader(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "struct-from-array", "Value")
+							return
+						}
+						if zb0009 > config.MaxBoxSize {
+							err = msgp.ErrOverflow(uint64(zb0009), uint64(config.MaxBoxSize))
+							return
+						}
+						(*z).Boxes[zb0001].Value, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Value)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "struct-from-array", "Value")
+							return
+						}
+					}
+					if zb0006 > 0 {
+						err = msgp.ErrTooManyArrayFields(zb0006)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "struct-from-array")
+							return
+						}
+					}
+				} else {
+					if err != nil {
+						err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001)
+						return
+					}
+					if zb0007 {
+						(*z).Boxes[zb0001] = encodedBoxRecord{}
+					}
+					for zb0006 > 0 {
+						zb0006--
+						field, bts, err = msgp.ReadMapKeyZC(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001)
+							return
+						}
+						switch string(field) {
+						case "k":
+							var zb0010 int
+							zb0010, err = msgp.ReadBytesBytesHeader(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "Key")
+								return
+							}
+							if zb0010 > encodedMaxBoxKeyLen {
+								err = msgp.ErrOverflow(uint64(zb0010), uint64(encodedMaxBoxKeyLen))
+								return
+							}
+							(*z).Boxes[zb0001].Key, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Key)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "Key")
+								return
+							}
+						case "v":
+							var zb0011 int
+							zb0011, err = msgp.ReadBytesBytesHeader(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "Value")
+								return
+							}
+							if zb0011 > config.MaxBoxSize {
+								err = msgp.ErrOverflow(uint64(zb0011), uint64(config.MaxBoxSize))
+								return
+							}
+							(*z).Boxes[zb0001].Value, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Value)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001, "Value")
+								return
+							}
+						default:
+							err = msgp.ErrNoField(string(field))
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Boxes", zb0001)
+								return
+							}
+						}
+					}
+				}
+			}
+		}
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0003 {
+			(*z) = catchpointFileBoxesChunk{}
+		}
+		for zb0002 > 0 {
+			zb0002--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "bx":
+				var zb0012 int
+				var zb0013 bool
+				zb0012, zb0013, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Boxes")
+					return
+				}
+				if zb0012 > BoxesPerCatchpointFileChunk {
+					err = msgp.ErrOverflow(uint64(zb0012), uint64(BoxesPerCatchpointFileChunk))
+					err = msgp.WrapError(err, "Boxes")
+					return
+				}
+				if zb0013 {
+					(*z).Boxes = nil
+				} else if (*z).Boxes != nil && cap((*z).Boxes) >= zb0012 {
+					(*z).Boxes = ((*z).Boxes)[:zb0012]
+				} else {
+					(*z).Boxes = make([]encodedBoxRecord, zb0012)
+				}
+				for zb0001 := range (*z).Boxes {
+					var zb0014 int
+					var zb0015 bool
+					zb0014, zb0015, bts, err = msgp.ReadMapHeaderBytes(bts)
+					if _, ok := err.(msgp.TypeError); ok {
+						zb0014, zb0015, bts, err = msgp.ReadArrayHeaderBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "Boxes", zb0001)
+							return
+						}
+						if zb0014 > 0 {
+							zb0014--
+							var zb0016 int
+							zb0016, err = msgp.ReadBytesBytesHeader(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Boxes", zb0001, "struct-from-array", "Key")
+								return
+							}
+							if zb0016 > encodedMaxBoxKeyLen {
+								err = msgp.ErrOverflow(uint64(zb0016), uint64(encodedMaxBoxKeyLen))
+								return
+							}
+							(*z).Boxes[zb0001].Key, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Key)
+							if err != nil {
+								err = msgp.WrapError(err, "Boxes", zb0001, "struct-from-array", "Key")
+								return
+							}
+						}
+						if zb0014 > 0 {
+							zb0014--
+							var zb0017 int
+							zb0017, err = msgp.ReadBytesBytesHeader(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Boxes", zb0001, "struct-from-array", "Value")
+								return
+							}
+							if zb0017 > config.MaxBoxSize {
+								err = msgp.ErrOverflow(uint64(zb0017), uint64(config.MaxBoxSize))
+								return
+							}
+							(*z).Boxes[zb0001].Value, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Value)
+							if err != nil {
+								err = msgp.WrapError(err, "Boxes", zb0001, "struct-from-array", "Value")
+								return
+							}
+						}
+						if zb0014 > 0 {
+							err = msgp.ErrTooManyArrayFields(zb0014)
+							if err != nil {
+								err = msgp.WrapError(err, "Boxes", zb0001, "struct-from-array")
+								return
+							}
+						}
+					} else {
+						if err != nil {
+							err = msgp.WrapError(err, "Boxes", zb0001)
+							return
+						}
+						if zb0015 {
+							(*z).Boxes[zb0001] = encodedBoxRecord{}
+						}
+						for zb0014 > 0 {
+							zb0014--
+							field, bts, err = msgp.ReadMapKeyZC(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Boxes", zb0001)
+								return
+							}
+							switch string(field) {
+							case "k":
+								var zb0018 int
+								zb0018, err = msgp.ReadBytesBytesHeader(bts)
+								if err != nil {
+									err = msgp.WrapError(err, "Boxes", zb0001, "Key")
+									return
+								}
+								if zb0018 > encodedMaxBoxKeyLen {
+									err = msgp.ErrOverflow(uint64(zb0018), uint64(encodedMaxBoxKeyLen))
+									return
+								}
+								(*z).Boxes[zb0001].Key, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Key)
+								if err != nil {
+									err = msgp.WrapError(err, "Boxes", zb0001, "Key")
+									return
+								}
+							case "v":
+								var zb0019 int
+								zb0019, err = msgp.ReadBytesBytesHeader(bts)
+								if err != nil {
+									err = msgp.WrapError(err, "Boxes", zb0001, "Value")
+									return
+								}
+								if zb0019 > config.MaxBoxSize {
+									err = msgp.ErrOverflow(uint64(zb0019), uint64(config.MaxBoxSize))
+									return
+								}
+								(*z).Boxes[zb0001].Value, bts, err = msgp.ReadBytesBytes(bts, (*z).Boxes[zb0001].Value)
+								if err != nil {
+									err = msgp.WrapError(err, "Boxes", zb0001, "Value")
+									return
+								}
+							default:
+								err = msgp.ErrNoField(string(field))
+								if err != nil {
+									err = msgp.WrapError(err, "Boxes", zb0001)
+									return
+								}
+							}
+						}
+					}
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *catchpointFileBoxesChunk) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointFileBoxesChunk)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *catchpointFileBoxesChunk) Msgsize() (s int) {
+	s = 1 + 3 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).Boxes {
+		s += 1 + 2 + msgp.BytesPrefixSize + len((*z).Boxes[zb0001].Key) + 2 + msgp.BytesPrefixSize + len((*z).Boxes[zb0001].Value)
+	}
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *catchpointFileBoxesChunk) MsgIsZero() bool {
+	return (len((*z).Boxes) == 0)
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *catchpointFileResourcesChunk) MarshalMsg(b []byte) (o []byte) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0002Len := uint32(1)
+	var zb0002Mask uint8 /* 2 bits */
+	if len((*z).Resources) == 0 {
+		zb0002Len--
+		zb0002Mask |= 0x2
+	}
+	// variable map header, size zb0002Len
+	o = append(o, 0x80|uint8(zb0002Len))
+	if zb0002Len != 0 {
+		if (zb0002Mask & 0x2) == 0 { // if not empty
+			// string "rs"
+			o = append(o, 0xa2, 0x72, 0x73)
+			if (*z).Resources == nil {
+				o = msgp.AppendNil(o)
+			} else {
+				o = msgp.AppendArrayHeader(o, uint32(len((*z).Resources)))
+			}
+			for zb0001 := range (*z).Resources {
+				// omitempty: check for empty values
+				zb0003Len := uint32(4)
+				var zb0003Mask uint8 /* 5 bits */
+				if (*z).Resources[zb0001].Aidx.MsgIsZero() {
+					zb0003Len--
+					zb0003Mask |= 0x2
+				}
+				if (*z).Resources[zb0001].Address.MsgIsZero() {
+					zb0003Len--
+					zb0003Mask |= 0x4
+				}
+				if (*z).Resources[zb0001].Data.MsgIsZero() {
+					zb0003Len--
+					zb0003Mask |= 0x8
+				}
+				if (*z).Resources[zb0001].Rtype.MsgIsZero() {
+					zb0003Len--
+					zb0003Mask |= 0x10
+				}
+				// variable map header, size zb0003Len
+				o = append(o, 0x80|uint8(zb0003Len))
+				if (zb0003Mask & 0x2) == 0 { // if not empty
+					// string "ai"
+					o = append(o, 0xa2, 0x61, 0x69)
+					o = (*z).Resources[zb0001].Aidx.MarshalMsg(o)
+				}
+				if (zb0003Mask & 0x4) == 0 { // if not empty
+					// string "pk"
+					o = append(o, 0xa2, 0x70, 0x6b)
+					o = (*z).Resources[zb0001].Address.MarshalMsg(o)
+				}
+				if (zb0003Mask & 0x8) == 0 { // if not empty
+					// string "rd"
+					o = append(o, 0xa2, 0x72, 0x64)
+					o = (*z).Resources[zb0001].Data.MarshalMsg(o)
+				}
+				if (zb0003Mask & 0x10) == 0 { // if not empty
+					// string "rt"
+					o = append(o, 0xa2, 0x72, 0x74)
+					o = (*z).Resources[zb0001].Rtype.MarshalMsg(o)
+				}
+			}
+		}
+	}
+	return
+}
+
+func (_ *catchpointFileResourcesChunk) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointFileResourcesChunk)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *catchpointFileResourcesChunk) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0002 int
+	var zb0003 bool
+	zb0002, zb0003, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0002, zb0003, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0002 > 0 {
+			zb0002--
+			var zb0004 int
+			var zb0005 bool
+			zb0004, zb0005, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Resources")
+				return
+			}
+			if zb0004 > ResourcesPerCatchpointFileChunk {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(ResourcesPerCatchpointFileChunk))
+				err = msgp.WrapError(err, "struct-from-array", "Resources")
+				return
+			}
+			if zb0005 {
+				(*z).Resources = nil
+			} else if (*z).Resources != nil && cap((*z).Resources) >= zb0004 {
+				(*z).Resources = ((*z).Resources)[:zb0004]
+			} else {
+				(*z).Resources = make([]encodedResourceRecord, zb0004)
+			}
+			for zb0001 := range (*z).Resources {
+				var zb0006 int
+				var zb0007 bool
+				zb0006, zb0007, bts, err = msgp.ReadMapHeaderBytes(bts)
+				if _, ok := err.(msgp.TypeError); ok {
+					zb0006, zb0007, bts, err = msgp.ReadArrayHeaderBytes(bts)
+					if err != nil {
+						err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001)
+						return
+					}
+					if zb0006 > 0 {
+						zb0006--
+						bts, err = (*z).Resources[zb0001].Address.UnmarshalMsg(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "struct-from-array", "Address")
+							return
+						}
+					}
+					if zb0006 > 0 {
+						zb0006--
+						bts, err = (*z).Resources[zb0001].Aidx.UnmarshalMsg(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "struct-from-array", "Aidx")
+							return
+						}
+					}
+					if zb0006 > 0 {
+						zb0006--
+						bts, err = (*z).Resources[zb0001].Rtype.UnmarshalMsg(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "struct-from-array", "Rtype")
+							return
+						}
+					}
+					if zb0006 > 0 {
+						zb0006--
+						bts, err = (*z).Resources[zb0001].Data.UnmarshalMsg(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "struct-from-array", "Data")
+							return
+						}
+					}
+					if zb0006 > 0 {
+						err = msgp.ErrTooManyArrayFields(zb0006)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "struct-from-array")
+							return
+						}
+					}
+				} else {
+					if err != nil {
+						err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001)
+						return
+					}
+					if zb0007 {
+						(*z).Resources[zb0001] = encodedResourceRecord{}
+					}
+					for zb0006 > 0 {
+						zb0006--
+						field, bts, err = msgp.ReadMapKeyZC(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001)
+							return
+						}
+						switch string(field) {
+						case "pk":
+							bts, err = (*z).Resources[zb0001].Address.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "Address")
+								return
+							}
+						case "ai":
+							bts, err = (*z).Resources[zb0001].Aidx.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "Aidx")
+								return
+							}
+						case "rt":
+							bts, err = (*z).Resources[zb0001].Rtype.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "Rtype")
+								return
+							}
+						case "rd":
+							bts, err = (*z).Resources[zb0001].Data.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001, "Data")
+								return
+							}
+						default:
+							err = msgp.ErrNoField(string(field))
+							if err != nil {
+								err = msgp.WrapError(err, "struct-from-array", "Resources", zb0001)
+								return
+							}
+						}
+					}
+				}
+			}
+		}
+		if zb0002 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0002)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0003 {
+			(*z) = catchpointFileResourcesChunk{}
+		}
+		for zb0002 > 0 {
+			zb0002--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "rs":
+				var zb0008 int
+				var zb0009 bool
+				zb0008, zb0009, bts, err = msgp.ReadArrayHeaderBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Resources")
+					return
+				}
+				if zb0008 > ResourcesPerCatchpointFileChunk {
+					err = msgp.ErrOverflow(uint64(zb0008), uint64(ResourcesPerCatchpointFileChunk))
+					err = msgp.WrapError(err, "Resources")
+					return
+				}
+				if zb0009 {
+					(*z).Resources = nil
+				} else if (*z).Resources != nil && cap((*z).Resources) >= zb0008 {
+					(*z).Resources = ((*z).Resources)[:zb0008]
+				} else {
+					(*z).Resources = make([]encodedResourceRecord, zb0008)
+				}
+				for zb0001 := range (*z).Resources {
+					var zb0010 int
+					var zb0011 bool
+					zb0010, zb0011, bts, err = msgp.ReadMapHeaderBytes(bts)
+					if _, ok := err.(msgp.TypeError); ok {
+						zb0010, zb0011, bts, err = msgp.ReadArrayHeaderBytes(bts)
+						if err != nil {
+							err = msgp.WrapError(err, "Resources", zb0001)
+							return
+						}
+						if zb0010 > 0 {
+							zb0010--
+							bts, err = (*z).Resources[zb0001].Address.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Resources", zb0001, "struct-from-array", "Address")
+								return
+							}
+						}
+						if zb0010 > 0 {
+							zb0010--
+							bts, err = (*z).Resources[zb0001].Aidx.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Resources", zb0001, "struct-from-array", "Aidx")
+								return
+							}
+						}
+						if zb0010 > 0 {
+							zb0010--
+							bts, err = (*z).Resources[zb0001].Rtype.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Resources", zb0001, "struct-from-array", "Rtype")
+								return
+							}
+						}
+						if zb0010 > 0 {
+							zb0010--
+							bts, err = (*z).Resources[zb0001].Data.UnmarshalMsg(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Resources", zb0001, "struct-from-array", "Data")
+								return
+							}
+						}
+						if zb0010 > 0 {
+							err = msgp.ErrTooManyArrayFields(zb0010)
+							if err != nil {
+								err = msgp.WrapError(err, "Resources", zb0001, "struct-from-array")
+								return
+							}
+						}
+					} else {
+						if err != nil {
+							err = msgp.WrapError(err, "Resources", zb0001)
+							return
+						}
+						if zb0011 {
+							(*z).Resources[zb0001] = encodedResourceRecord{}
+						}
+						for zb0010 > 0 {
+							zb0010--
+							field, bts, err = msgp.ReadMapKeyZC(bts)
+							if err != nil {
+								err = msgp.WrapError(err, "Resources", zb0001)
+								return
+							}
+							switch string(field) {
+							case "pk":
+								bts, err = (*z).Resources[zb0001].Address.UnmarshalMsg(bts)
+								if err != nil {
+									err = msgp.WrapError(err, "Resources", zb0001, "Address")
+									return
+								}
+							case "ai":
+								bts, err = (*z).Resources[zb0001].Aidx.UnmarshalMsg(bts)
+								if err != nil {
+									err = msgp.WrapError(err, "Resources", zb0001, "Aidx")
+									return
+								}
+							case "rt":
+								bts, err = (*z).Resources[zb0001].Rtype.UnmarshalMsg(bts)
+								if err != nil {
+									err = msgp.WrapError(err, "Resources", zb0001, "Rtype")
+									return
+								}
+							case "rd":
+								bts, err = (*z).Resources[zb0001].Data.UnmarshalMsg(bts)
+								if err != nil {
+									err = msgp.WrapError(err, "Resources", zb0001, "Data")
+									return
+								}
+							default:
+								err = msgp.ErrNoField(string(field))
+								if err != nil {
+									err = msgp.WrapError(err, "Resources", zb0001)
+									return
+								}
+							}
+						}
+					}
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *catchpointFileResourcesChunk) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointFileResourcesChunk)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *catchpointFileResourcesChunk) Msgsize() (s int) {
+	s = 1 + 3 + msgp.ArrayHeaderSize
+	for zb0001 := range (*z).Resources {
+		s += 1 + 3 + (*z).Resources[zb0001].Address.Msgsize() + 3 + (*z).Resources[zb0001].Aidx.Msgsize() + 3 + (*z).Resources[zb0001].Rtype.Msgsize() + 3 + (*z).Resources[zb0001].Data.Msgsize()
+	}
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *catchpointFileResourcesChunk) MsgIsZero() bool {
+	return (len((*z).Resources) == 0)
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z catchpointState) MarshalMsg(b []byte) (o []byte) {
+	o = msgp.Require(b, z.Msgsize())
+	o = msgp.AppendString(o, string(z))
+	return
+}
+
+func (_ catchpointState) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(catchpointState)
+	if !ok {
+		_, ok = (z).(*catchpointState)
+	}
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *catchpointState) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	{
+		var zb0001 string
+		zb0001, bts, err = msgp.ReadStringBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		(*z) = catchpointState(zb0001)
+	}
+	o = bts
+	return
+}
+
+func (_ *catchpointState) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*catchpointState)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z catchpointState) Msgsize() (s int) {
+	s = msgp.StringPrefixSize + len(string(z))
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z catchpointState) MsgIsZero() bool {
+	return z == ""
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *encodedBalanceRecord) MarshalMsg(b []byte) (o []byte) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(2)
+	var zb0001Mask uint8 /* 3 bits */
+	if (*z).AccountData.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x2
+	}
+	if (*z).Address.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x4
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len != 0 {
+		if (zb0001Mask & 0x2) == 0 { // if not empty
+			// string "ad"
+			o = append(o, 0xa2, 0x61, 0x64)
+			o = (*z).AccountData.MarshalMsg(o)
+		}
+		if (zb0001Mask & 0x4) == 0 { // if not empty
+			// string "pk"
+			o = append(o, 0xa2, 0x70, 0x6b)
+			o = (*z).Address.MarshalMsg(o)
+		}
+	}
+	return
+}
+
+func (_ *encodedBalanceRecord) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedBalanceRecord)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *encodedBalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 int
+	var zb0002 bool
+	zb0001, zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0001, zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).Address.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Address")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).AccountData.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "AccountData")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0001)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0002 {
+			(*z) = encodedBalanceRecord{}
+		}
+		for zb0001 > 0 {
+			zb0001--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "pk":
+				bts, err = (*z).Address.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Address")
+					return
+				}
+			case "ad":
+				bts, err = (*z).AccountData.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "AccountData")
+					return
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
 	return
 }
 
-func (_ *catchpointState) CanUnmarshalMsg(z interface{}) bool {
-	_, ok := (z).(*catchpointState)
+func (_ *encodedBalanceRecord) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedBalanceRecord)
 	return ok
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z catchpointState) Msgsize() (s int) {
-	s = msgp.StringPrefixSize + len(string(z))
+func (z *encodedBalanceRecord) Msgsize() (s int) {
+	s = 1 + 3 + (*z).Address.Msgsize() + 3 + (*z).AccountData.Msgsize()
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
-func (z catchpointState) MsgIsZero() bool {
-	return z == ""
+func (z *encodedBalanceRecord) MsgIsZero() bool {
+	return ((*z).Address.MsgIsZero()) && ((*z).AccountData.MsgIsZero())
 }
 
 // MarshalMsg implements msgp.Marshaler
-func (z *encodedBalanceRecord) MarshalMsg(b []byte) (o []byte) {
+func (z *encodedBoxRecord) MarshalMsg(b []byte) (o []byte) {
 	o = msgp.Require(b, z.Msgsize())
 	// omitempty: check for empty values
 	zb0001Len := uint32(2)
 	var zb0001Mask uint8 /* 3 bits */
-	if (*z).AccountData.MsgIsZero() {
+	if len((*z).Key) == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x2
+	}
+	if len((*z).Value) == 0 {
+		zb0001Len--
+		zb0001Mask |= 0x4
+	}
+	// variable map header, size zb0001Len
+	o = append(o, 0x80|uint8(zb0001Len))
+	if zb0001Len != 0 {
+		if (zb0001Mask & 0x2) == 0 { // if not empty
+			// string "k"
+			o = append(o, 0xa1, 0x6b)
+			o = msgp.AppendBytes(o, (*z).Key)
+		}
+		if (zb0001Mask & 0x4) == 0 { // if not empty
+			// string "v"
+			o = append(o, 0xa1, 0x76)
+			o = msgp.AppendBytes(o, (*z).Value)
+		}
+	}
+	return
+}
+
+func (_ *encodedBoxRecord) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedBoxRecord)
+	return ok
+}
+
+// UnmarshalMsg implements msgp.Unmarshaler
+func (z *encodedBoxRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
+	var field []byte
+	_ = field
+	var zb0001 int
+	var zb0002 bool
+	zb0001, zb0002, bts, err = msgp.ReadMapHeaderBytes(bts)
+	if _, ok := err.(msgp.TypeError); ok {
+		zb0001, zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0001 > 0 {
+			zb0001--
+			var zb0003 int
+			zb0003, err = msgp.ReadBytesBytesHeader(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Key")
+				return
+			}
+			if zb0003 > encodedMaxBoxKeyLen {
+				err = msgp.ErrOverflow(uint64(zb0003), uint64(encodedMaxBoxKeyLen))
+				return
+			}
+			(*z).Key, bts, err = msgp.ReadBytesBytes(bts, (*z).Key)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Key")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			var zb0004 int
+			zb0004, err = msgp.ReadBytesBytesHeader(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Value")
+				return
+			}
+			if zb0004 > config.MaxBoxSize {
+				err = msgp.ErrOverflow(uint64(zb0004), uint64(config.MaxBoxSize))
+				return
+			}
+			(*z).Value, bts, err = msgp.ReadBytesBytes(bts, (*z).Value)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Value")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			err = msgp.ErrTooManyArrayFields(zb0001)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array")
+				return
+			}
+		}
+	} else {
+		if err != nil {
+			err = msgp.WrapError(err)
+			return
+		}
+		if zb0002 {
+			(*z) = encodedBoxRecord{}
+		}
+		for zb0001 > 0 {
+			zb0001--
+			field, bts, err = msgp.ReadMapKeyZC(bts)
+			if err != nil {
+				err = msgp.WrapError(err)
+				return
+			}
+			switch string(field) {
+			case "k":
+				var zb0005 int
+				zb0005, err = msgp.ReadBytesBytesHeader(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Key")
+					return
+				}
+				if zb0005 > encodedMaxBoxKeyLen {
+					err = msgp.ErrOverflow(uint64(zb0005), uint64(encodedMaxBoxKeyLen))
+					return
+				}
+				(*z).Key, bts, err = msgp.ReadBytesBytes(bts, (*z).Key)
+				if err != nil {
+					err = msgp.WrapError(err, "Key")
+					return
+				}
+			case "v":
+				var zb0006 int
+				zb0006, err = msgp.ReadBytesBytesHeader(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Value")
+					return
+				}
+				if zb0006 > config.MaxBoxSize {
+					err = msgp.ErrOverflow(uint64(zb0006), uint64(config.MaxBoxSize))
+					return
+				}
+				(*z).Value, bts, err = msgp.ReadBytesBytes(bts, (*z).Value)
+				if err != nil {
+					err = msgp.WrapError(err, "Value")
+					return
+				}
+			default:
+				err = msgp.ErrNoField(string(field))
+				if err != nil {
+					err = msgp.WrapError(err)
+					return
+				}
+			}
+		}
+	}
+	o = bts
+	return
+}
+
+func (_ *encodedBoxRecord) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedBoxRecord)
+	return ok
+}
+
+// Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
+func (z *encodedBoxRecord) Msgsize() (s int) {
+	s = 1 + 2 + msgp.BytesPrefixSize + len((*z).Key) + 2 + msgp.BytesPrefixSize + len((*z).Value)
+	return
+}
+
+// MsgIsZero returns whether this is a zero value
+func (z *encodedBoxRecord) MsgIsZero() bool {
+	return (len((*z).Key) == 0) && (len((*z).Value) == 0)
+}
+
+// MarshalMsg implements msgp.Marshaler
+func (z *encodedResourceRecord) MarshalMsg(b []byte) (o []byte) {
+	o = msgp.Require(b, z.Msgsize())
+	// omitempty: check for empty values
+	zb0001Len := uint32(4)
+	var zb0001Mask uint8 /* 5 bits */
+	if (*z).Aidx.MsgIsZero() {
 		zb0001Len--
 		zb0001Mask |= 0x2
 	}
@@ -737,30 +2143,48 @@ func (z *encodedBalanceRecord) MarshalMsg(b []byte) (o []byte) {
 		zb0001Len--
 		zb0001Mask |= 0x4
 	}
+	if (*z).Data.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x8
+	}
+	if (*z).Rtype.MsgIsZero() {
+		zb0001Len--
+		zb0001Mask |= 0x10
+	}
 	// variable map header, size zb0001Len
 	o = append(o, 0x80|uint8(zb0001Len))
 	if zb0001Len != 0 {
 		if (zb0001Mask & 0x2) == 0 { // if not empty
-			// string "ad"
-			o = append(o, 0xa2, 0x61, 0x64)
-			o = (*z).AccountData.MarshalMsg(o)
+			// string "ai"
+			o = append(o, 0xa2, 0x61, 0x69)
+			o = (*z).Aidx.MarshalMsg(o)
 		}
 		if (zb0001Mask & 0x4) == 0 { // if not empty
 			// string "pk"
 			o = append(o, 0xa2, 0x70, 0x6b)
 			o = (*z).Address.MarshalMsg(o)
 		}
+		if (zb0001Mask & 0x8) == 0 { // if not empty
+			// string "rd"
+			o = append(o, 0xa2, 0x72, 0x64)
+			o = (*z).Data.MarshalMsg(o)
+		}
+		if (zb0001Mask & 0x10) == 0 { // if not empty
+			// string "rt"
+			o = append(o, 0xa2, 0x72, 0x74)
+			o = (*z).Rtype.MarshalMsg(o)
+		}
 	}
 	return
 }
 
-func (_ *encodedBalanceRecord) CanMarshalMsg(z interface{}) bool {
-	_, ok := (z).(*encodedBalanceRecord)
+func (_ *encodedResourceRecord) CanMarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedResourceRecord)
 	return ok
 }
 
 // UnmarshalMsg implements msgp.Unmarshaler
-func (z *encodedBalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
+func (z *encodedResourceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	var field []byte
 	_ = field
 	var zb0001 int
@@ -782,9 +2206,25 @@ func (z *encodedBalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 		}
 		if zb0001 > 0 {
 			zb0001--
-			bts, err = (*z).AccountData.UnmarshalMsg(bts)
+			bts, err = (*z).Aidx.UnmarshalMsg(bts)
 			if err != nil {
-				err = msgp.WrapError(err, "struct-from-array", "AccountData")
+				err = msgp.WrapError(err, "struct-from-array", "Aidx")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).Rtype.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Rtype")
+				return
+			}
+		}
+		if zb0001 > 0 {
+			zb0001--
+			bts, err = (*z).Data.UnmarshalMsg(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "struct-from-array", "Data")
 				return
 			}
 		}
@@ -801,7 +2241,7 @@ func (z *encodedBalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 			return
 		}
 		if zb0002 {
-			(*z) = encodedBalanceRecord{}
+			(*z) = encodedResourceRecord{}
 		}
 		for zb0001 > 0 {
 			zb0001--
@@ -817,10 +2257,22 @@ func (z *encodedBalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 					err = msgp.WrapError(err, "Address")
 					return
 				}
-			case "ad":
-				bts, err = (*z).AccountData.UnmarshalMsg(bts)
+			case "ai":
+				bts, err = (*z).Aidx.UnmarshalMsg(bts)
 				if err != nil {
-					err = msgp.WrapError(err, "AccountData")
+					err = msgp.WrapError(err, "Aidx")
+					return
+				}
+			case "rt":
+				bts, err = (*z).Rtype.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Rtype")
+					return
+				}
+			case "rd":
+				bts, err = (*z).Data.UnmarshalMsg(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Data")
 					return
 				}
 			default:
@@ -836,20 +2288,20 @@ func (z *encodedBalanceRecord) UnmarshalMsg(bts []byte) (o []byte, err error) {
 	return
 }
 
-func (_ *encodedBalanceRecord) CanUnmarshalMsg(z interface{}) bool {
-	_, ok := (z).(*encodedBalanceRecord)
+func (_ *encodedResourceRecord) CanUnmarshalMsg(z interface{}) bool {
+	_, ok := (z).(*encodedResourceRecord)
 	return ok
 }
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
-func (z *encodedBalanceRecord) Msgsize() (s int) {
-	s = 1 + 3 + (*z).Address.Msgsize() + 3 + (*z).AccountData.Msgsize()
+func (z *encodedResourceRecord) Msgsize() (s int) {
+	s = 1 + 3 + (*z).Address.Msgsize() + 3 + (*z).Aidx.Msgsize() + 3 + (*z).Rtype.Msgsize() + 3 + (*z).Data.Msgsize()
 	return
 }
 
 // MsgIsZero returns whether this is a zero value
-func (z *encodedBalanceRecord) MsgIsZero() bool {
-	return ((*z).Address.MsgIsZero()) && ((*z).AccountData.MsgIsZero())
+func (z *encodedResourceRecord) MsgIsZero() bool {
+	return ((*z).Address.MsgIsZero()) && ((*z).Aidx.MsgIsZero()) && ((*z).Rtype.MsgIsZero()) && ((*z).Data.MsgIsZero())
 }
 
 // MarshalMsg implements msgp.Marshaler
@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/data/transactions/verify"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+)
+
+// SimulatedTransaction is the outcome of a single transaction of a
+// simulated transaction group.
+type SimulatedTransaction struct {
+	transactions.SignedTxnWithAD
+
+	// OpcodeCost is the cost of the application program run by an
+	// application call transaction.
+	OpcodeCost int
+}
+
+// MinBalanceViolation reports an account whose balance a simulated
+// transaction left below its minimum balance.
+type MinBalanceViolation struct {
+	// GroupIndex is the index of the first transaction of the group after
+	// which the account was below its minimum balance.
+	GroupIndex int
+	Address    basics.Address
+	Balance    basics.MicroAlgos
+	MinBalance basics.MicroAlgos
+}
+
+// SimulationResult is the outcome of a simulated transaction group.
+type SimulationResult struct {
+	// Round is the round whose state the group was evaluated against.
+	Round basics.Round
+
+	// FailureMessage explains why the group would be rejected, regardless
+	// of the minimum balance violations. When it is set, Txns and Delta are
+	// empty.
+	FailureMessage string
+
+	Txns  []SimulatedTransaction
+	Delta ledgercore.StateDelta
+
+	MinBalanceViolations []MinBalanceViolation
+}
+
+// WouldSucceed returns whether the group would be accepted into a block.
+func (sr SimulationResult) WouldSucceed() bool {
+	return sr.FailureMessage == "" && len(sr.MinBalanceViolations) == 0
+}
+
+// simulation collects what a BlockEvaluator observes while evaluating a
+// simulated transaction group.
+type simulation struct {
+	// evalParams are the parameters the application calls of the group
+	// were evaluated with.
+	evalParams []*logic.EvalParams
+
+	minBalanceViolations []MinBalanceViolation
+}
+
+// minBalanceViolation records the first transaction leaving addr below its
+// minimum balance.
+func (s *simulation) minBalanceViolation(groupIdx int, addr basics.Address, balance basics.MicroAlgos, minBalance basics.MicroAlgos) {
+	for _, v := range s.minBalanceViolations {
+		if v.Address == addr {
+			return
+		}
+	}
+	s.minBalanceViolations = append(s.minBalanceViolations, MinBalanceViolation{
+		GroupIndex: groupIdx,
+		Address:    addr,
+		Balance:    balance,
+		MinBalance: minBalance,
+	})
+}
+
+// Simulate evaluates a transaction group against the state of the latest
+// round, as if it was the only group of the next block, without changing the
+// ledger state. The group is checked as the transaction pool would check it,
+// except that signatures, including logic signatures, are not verified, so
+// that unsigned transactions may be simulated. Minimum balance violations are
+// reported rather than failing the group.
+//
+// The returned error is only set if the group could not be evaluated at all;
+// the reasons the group would be rejected are given by the SimulationResult.
+func (l *Ledger) Simulate(txgroup []transactions.SignedTxn) (SimulationResult, error) {
+	latest := l.Latest()
+	prev, err := l.BlockHdr(latest)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	_, upgradeState, err := bookkeeping.ProcessUpgradeParams(prev)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	proto, ok := config.Consensus[upgradeState.CurrentProtocol]
+	if !ok {
+		return SimulationResult{}, fmt.Errorf("next protocol version %v is not supported", upgradeState.CurrentProtocol)
+	}
+
+	next := bookkeeping.MakeBlock(prev)
+	eval, err := l.StartEvaluator(next.BlockHeader, len(txgroup))
+	if err != nil {
+		return SimulationResult{}, err
+	}
+	eval.simulation = &simulation{}
+
+	result := SimulationResult{Round: latest}
+	err = eval.TestTransactionGroup(txgroup)
+	if err == nil {
+		err = verify.TxnGroupFees(txgroup, proto)
+	}
+	if err == nil {
+		txads := make([]transactions.SignedTxnWithAD, len(txgroup))
+		for i := range txgroup {
+			txads[i].SignedTxn = txgroup[i]
+		}
+		err = eval.TransactionGroup(txads)
+	}
+	if err != nil {
+		result.FailureMessage = err.Error()
+		return result, nil
+	}
+
+	result.Txns = make([]SimulatedTransaction, len(eval.block.Payset))
+	for i, txib := range eval.block.Payset {
+		result.Txns[i].SignedTxn, result.Txns[i].ApplyData, err = eval.block.DecodeSignedTxn(txib)
+		if err != nil {
+			return SimulationResult{}, err
+		}
+		if ep := eval.simulation.evalParams[i]; ep != nil {
+			result.Txns[i].OpcodeCost = ep.PastSideEffects[i].Cost()
+		}
+	}
+	result.Delta = eval.state.deltas()
+	result.MinBalanceViolations = eval.simulation.minBalanceViolations
+	return result, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/data/transactions/logic"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+)
+
+func TestSimulate(t *testing.T) {
+	genesisInitState, _ := testGenerateInitState(t, protocol.ConsensusCurrentVersion, 100)
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	var addrs []basics.Address
+	for addr := range genesisInitState.Accounts {
+		if addr != testPoolAddr && addr != testSinkAddr {
+			addrs = append(addrs, addr)
+		}
+	}
+
+	const inMem = true
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	log := logging.TestingLog(t)
+	l, err := OpenLedger(log, t.Name(), inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+
+	header := func(sender basics.Address) transactions.Header {
+		return transactions.Header{
+			Sender:      sender,
+			Fee:         basics.MicroAlgos{Raw: proto.MinTxnFee},
+			FirstValid:  l.Latest() + 1,
+			LastValid:   l.Latest() + 10,
+			GenesisID:   t.Name(),
+			GenesisHash: genesisInitState.GenesisHash,
+		}
+	}
+	payment := func(sender, receiver basics.Address, amount uint64) transactions.SignedTxn {
+		return transactions.SignedTxn{Txn: transactions.Transaction{
+			Type:   protocol.PaymentTx,
+			Header: header(sender),
+			PaymentTxnFields: transactions.PaymentTxnFields{
+				Receiver: receiver,
+				Amount:   basics.MicroAlgos{Raw: amount},
+			},
+		}}
+	}
+	balance := func(addr basics.Address) uint64 {
+		data, _, err := l.LookupWithoutRewards(l.Latest(), addr)
+		require.NoError(t, err)
+		return data.MicroAlgos.Raw
+	}
+
+	// an unsigned payment is evaluated, and its changes are not applied
+	before := balance(addrs[1])
+	result, err := l.Simulate([]transactions.SignedTxn{payment(addrs[0], addrs[1], 1000)})
+	require.NoError(t, err)
+	require.True(t, result.WouldSucceed(), result.FailureMessage)
+	require.Equal(t, l.Latest(), result.Round)
+	require.Len(t, result.Txns, 1)
+	require.Equal(t, addrs[1], result.Txns[0].Txn.Receiver)
+	data, ok := result.Delta.Accts.Get(addrs[1])
+	require.True(t, ok)
+	require.Equal(t, before+1000, data.MicroAlgos.Raw)
+	require.Equal(t, before, balance(addrs[1]))
+
+	// funding a new account with less than the minimum balance is reported
+	var empty basics.Address
+	empty[0] = 0xff
+	result, err = l.Simulate([]transactions.SignedTxn{payment(addrs[0], empty, 1000)})
+	require.NoError(t, err)
+	require.Empty(t, result.FailureMessage)
+	require.False(t, result.WouldSucceed())
+	require.Equal(t, []MinBalanceViolation{{
+		GroupIndex: 0,
+		Address:    empty,
+		Balance:    basics.MicroAlgos{Raw: 1000},
+		MinBalance: basics.MicroAlgos{Raw: proto.MinBalance},
+	}}, result.MinBalanceViolations)
+
+	// insufficient fees fail the group
+	stx := payment(addrs[0], addrs[1], 1000)
+	stx.Txn.Fee = basics.MicroAlgos{}
+	result, err = l.Simulate([]transactions.SignedTxn{stx})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.FailureMessage)
+	require.Empty(t, result.Txns)
+
+	// application calls report their opcode cost
+	ops, err := logic.AssembleStringWithVersion("int 1\nint 2\n+\npop\nint 1", 2)
+	require.NoError(t, err)
+	result, err = l.Simulate([]transactions.SignedTxn{{Txn: transactions.Transaction{
+		Type:   protocol.ApplicationCallTx,
+		Header: header(addrs[0]),
+		ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{
+			ApprovalProgram:   ops.Program,
+			ClearStateProgram: ops.Program,
+		},
+	}}})
+	require.NoError(t, err)
+	require.True(t, result.WouldSucceed(), result.FailureMessage)
+	// the five opcodes, and the intcblock the assembler prepends
+	require.Equal(t, 6, result.Txns[0].OpcodeCost)
+	require.Equal(t, 1, len(result.Delta.Creatables))
+}
@@ -0,0 +1,543 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/algorand/go-algorand/agreement"
+	"github.com/algorand/go-algorand/config"
+	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger/ledgercore"
+	"github.com/algorand/go-algorand/logging"
+	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
+)
+
+// The tests in this file are run against every implementation of blockStore and accountStore, so that all the
+// storage backends behave the same way.
+
+func testBlockStores(t *testing.T) map[string]func() blockStore {
+	return map[string]func() blockStore{
+		StorageBackendSQLite: func() blockStore {
+			dbs, _ := dbOpenTest(t, true)
+			setDbLogging(t, dbs)
+			return &sqliteBlockStore{dbs: dbs}
+		},
+		StorageBackendKV: func() blockStore {
+			bs, err := openKVBlockStore("", true)
+			require.NoError(t, err)
+			return bs
+		},
+	}
+}
+
+func testAccountStores(t *testing.T) map[string]func() accountStore {
+	return map[string]func() accountStore{
+		StorageBackendSQLite: func() accountStore {
+			dbs, _ := dbOpenTest(t, true)
+			setDbLogging(t, dbs)
+			return &sqliteTestAccountStore{sqliteAccountStore: sqliteAccountStore{dbs: dbs}}
+		},
+		StorageBackendKV: func() accountStore {
+			as, err := openKVAccountStore("", true)
+			require.NoError(t, err)
+			return as
+		},
+	}
+}
+
+// sqliteTestAccountStore is a sqliteAccountStore which owns its tracker database, as the ledger does otherwise
+type sqliteTestAccountStore struct {
+	sqliteAccountStore
+}
+
+func (as *sqliteTestAccountStore) close() {
+	as.sqliteAccountStore.close()
+	as.dbs.Close()
+}
+
+func checkBlockStore(t *testing.T, bs blockStore, blocks []blockEntry) {
+	latest, err := bs.latest()
+	require.NoError(t, err)
+	require.Equal(t, blocks[len(blocks)-1].block.Round(), latest)
+	earliest, err := bs.earliest()
+	require.NoError(t, err)
+	require.Equal(t, blocks[0].block.Round(), earliest)
+
+	for _, e := range blocks {
+		blk, err := bs.get(e.block.Round())
+		require.NoError(t, err)
+		require.Equal(t, e.block, blk)
+
+		hdr, err := bs.getHdr(e.block.Round())
+		require.NoError(t, err)
+		require.Equal(t, e.block.BlockHeader, hdr)
+
+		blk, cert, err := bs.getCert(e.block.Round())
+		require.NoError(t, err)
+		require.Equal(t, e.block, blk)
+		require.Equal(t, e.cert, cert)
+
+		blkbuf, certbuf, err := bs.getEncodedCert(e.block.Round())
+		require.NoError(t, err)
+		require.Equal(t, protocol.Encode(&e.block), blkbuf)
+		require.Equal(t, protocol.Encode(&e.cert), certbuf)
+	}
+
+	_, err = bs.get(latest + 1)
+	require.IsType(t, ledgercore.ErrNoEntry{}, err)
+	_, err = bs.getHdr(latest + 1)
+	require.IsType(t, ledgercore.ErrNoEntry{}, err)
+	_, _, err = bs.getEncodedCert(latest + 1)
+	require.IsType(t, ledgercore.ErrNoEntry{}, err)
+}
+
+func TestBlockStoreConformance(t *testing.T) {
+	for name, open := range testBlockStores(t) {
+		t.Run(name, func(t *testing.T) {
+			bs := open()
+			defer bs.close()
+			log := logging.TestingLog(t)
+
+			require.NoError(t, bs.setSynchronousMode(context.Background(), db.SynchronousModeOff))
+
+			_, err := bs.latest()
+			require.Error(t, err)
+			_, err = bs.earliest()
+			require.Error(t, err)
+
+			blocks := randomInitChain(protocol.ConsensusCurrentVersion, 1)
+			require.NoError(t, bs.initialize(blockChainBlocks(blocks), false, log))
+			checkBlockStore(t, bs, blocks)
+			// initializing again doesn't change anything
+			require.NoError(t, bs.initialize(blockChainBlocks(randomInitChain(protocol.ConsensusCurrentVersion, 1)), false, log))
+			checkBlockStore(t, bs, blocks)
+
+			for i := 1; i < 10; i += 3 {
+				entries := []blockEntry{randomBlock(basics.Round(i)), randomBlock(basics.Round(i + 1)), randomBlock(basics.Round(i + 2))}
+				require.NoError(t, bs.put(entries))
+				blocks = append(blocks, entries...)
+			}
+			checkBlockStore(t, bs, blocks)
+
+			// blocks have to be written in order, and a batch is written atomically
+			require.Error(t, bs.put([]blockEntry{randomBlock(11)}))
+			require.Error(t, bs.put([]blockEntry{randomBlock(10), randomBlock(12)}))
+			checkBlockStore(t, bs, blocks)
+
+			require.NoError(t, bs.forgetBefore(4))
+			blocks = blocks[4:]
+			checkBlockStore(t, bs, blocks)
+			_, err = bs.get(3)
+			require.IsType(t, ledgercore.ErrNoEntry{}, err)
+			require.Error(t, bs.forgetBefore(10))
+
+			// an archival node resets a storage that doesn't start at round 0
+			genesis := randomInitChain(protocol.ConsensusCurrentVersion, 1)
+			require.NoError(t, bs.initialize(blockChainBlocks(genesis), true, log))
+			checkBlockStore(t, bs, genesis)
+		})
+	}
+}
+
+func TestBlockStoreCatchupConformance(t *testing.T) {
+	for name, open := range testBlockStores(t) {
+		t.Run(name, func(t *testing.T) {
+			bs := open()
+			defer bs.close()
+
+			blocks := randomInitChain(protocol.ConsensusCurrentVersion, 1)
+			require.NoError(t, bs.initialize(blockChainBlocks(blocks), false, logging.TestingLog(t)))
+			require.NoError(t, bs.put([]blockEntry{randomBlock(1), randomBlock(2)}))
+
+			_, err := bs.ensureSingleBlock()
+			require.Error(t, err)
+
+			// an aborted catchup leaves the blocks untouched
+			require.NoError(t, bs.startCatchupStaging(randomBlock(100).block))
+			require.NoError(t, bs.abortCatchup())
+			latest, err := bs.latest()
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(2), latest)
+
+			staged := []blockEntry{randomBlock(100), randomBlock(99), randomBlock(98)}
+			require.NoError(t, bs.startCatchupStaging(staged[0].block))
+			for _, e := range staged[1:] {
+				require.NoError(t, bs.putStaging(e.block))
+			}
+			blk, err := bs.ensureSingleBlock()
+			require.NoError(t, err)
+			require.Equal(t, staged[0].block, blk)
+			// the blocks are replaced only once the catchup completes
+			latest, err = bs.latest()
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(2), latest)
+
+			require.NoError(t, bs.completeCatchup())
+			earliest, err := bs.earliest()
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(100), earliest)
+			blk, cert, err := bs.getCert(100)
+			require.NoError(t, err)
+			require.Equal(t, staged[0].block, blk)
+			require.Equal(t, agreement.Certificate{}, cert)
+			// staged blocks have no certificate
+			_, certbuf, err := bs.getEncodedCert(100)
+			require.NoError(t, err)
+			require.Nil(t, certbuf)
+
+			require.NoError(t, bs.put([]blockEntry{randomBlock(101)}))
+			latest, err = bs.latest()
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(101), latest)
+		})
+	}
+}
+
+func checkAccountStore(t *testing.T, as accountStore, rnd basics.Round, accounts map[basics.Address]basics.AccountData, totals ledgercore.AccountTotals) {
+	r, err := as.round()
+	require.NoError(t, err)
+	require.Equal(t, rnd, r)
+
+	storedTotals, err := as.totals()
+	require.NoError(t, err)
+	require.Equal(t, totals, storedTotals)
+
+	for addr, data := range accounts {
+		stored, err := as.lookup(addr)
+		require.NoError(t, err)
+		require.Equal(t, rnd, stored.round)
+		require.Equal(t, addr, stored.addr)
+		require.NotZero(t, stored.rowid)
+		require.Equal(t, data, stored.accountData)
+	}
+
+	stored, err := as.lookup(randomAddress())
+	require.NoError(t, err)
+	require.Equal(t, rnd, stored.round)
+	require.Zero(t, stored.rowid)
+	require.True(t, stored.accountData.IsZero())
+
+	iterated := make(map[basics.Address]basics.AccountData)
+	require.NoError(t, as.iterateAccounts(func(addr basics.Address, data basics.AccountData) error {
+		iterated[addr] = data
+		return nil
+	}))
+	require.Equal(t, accounts, iterated)
+}
+
+// commitAccountStore writes the given changes to as, as commitRound does for a single round
+func commitAccountStore(as accountStore, rnd basics.Round, accounts map[basics.Address]basics.AccountData, creatables map[basics.CreatableIndex]ledgercore.ModifiedCreatable, boxes map[string]ledgercore.ModifiedBox, rewardsLevel uint64, proto config.ConsensusParams) error {
+	return as.commit(func(ctx context.Context, w accountStoreWriter) error {
+		var updates ledgercore.AccountDeltas
+		var compactUpdates compactAccountDeltas
+		for addr, data := range accounts {
+			updates.Upsert(addr, data)
+			compactUpdates.insertMissing(addr, accountDelta{new: data, ndeltas: 1})
+		}
+		err := w.accountsLoadOld(&compactUpdates)
+		if err != nil {
+			return err
+		}
+		compactUpdates.makeResourceDeltas()
+		err = w.totalsNewRounds([]ledgercore.AccountDeltas{updates}, compactUpdates, []ledgercore.AccountTotals{{RewardsLevel: rewardsLevel}}, proto)
+		if err != nil {
+			return err
+		}
+		_, err = w.accountsNewRound(compactUpdates, creatables, proto, rnd)
+		if err != nil {
+			return err
+		}
+		err = w.boxesNewRound(boxes)
+		if err != nil {
+			return err
+		}
+		return w.updateAccountsRound(rnd, 0)
+	})
+}
+
+func accountStoreTotals(accounts map[basics.Address]basics.AccountData, proto config.ConsensusParams) ledgercore.AccountTotals {
+	var totals ledgercore.AccountTotals
+	var ot basics.OverflowTracker
+	for _, data := range accounts {
+		totals.AddAccount(proto, data, &ot)
+	}
+	return totals
+}
+
+func TestAccountStoreConformance(t *testing.T) {
+	proto := config.Consensus[protocol.ConsensusCurrentVersion]
+	accounts := randomAccounts(30, false)
+
+	for name, open := range testAccountStores(t) {
+		t.Run(name, func(t *testing.T) {
+			as := open()
+			defer as.close()
+
+			require.NoError(t, as.setSynchronousMode(context.Background(), db.SynchronousModeOff))
+
+			newDatabase, err := as.initialize(accounts, proto)
+			require.NoError(t, err)
+			require.True(t, newDatabase)
+			checkAccountStore(t, as, 0, accounts, accountStoreTotals(accounts, proto))
+			newDatabase, err = as.initialize(nil, proto)
+			require.NoError(t, err)
+			require.False(t, newDatabase)
+
+			expected := make(map[basics.Address]basics.AccountData, len(accounts))
+			updates := make(map[basics.Address]basics.AccountData)
+			i := 0
+			for addr, data := range accounts {
+				switch i % 4 {
+				case 0:
+					// delete the account
+					updates[addr] = basics.AccountData{}
+				case 1:
+					// drop its asset holdings and change its balance
+					data.MicroAlgos.Raw++
+					data.Assets = nil
+					updates[addr] = data
+					expected[addr] = data
+				case 2:
+					// bring it online
+					data.Status = basics.Online
+					updates[addr] = data
+					expected[addr] = data
+				default:
+					expected[addr] = data
+				}
+				i++
+			}
+			added := randomAddress()
+			updates[added] = randomAccountData(0)
+			expected[added] = updates[added]
+
+			creator := randomAddress()
+			creatables := map[basics.CreatableIndex]ledgercore.ModifiedCreatable{
+				10: {Ctype: basics.AssetCreatable, Created: true, Creator: creator},
+				20: {Ctype: basics.AssetCreatable, Created: true, Creator: creator},
+				30: {Ctype: basics.AssetCreatable, Created: true, Creator: creator},
+				15: {Ctype: basics.AppCreatable, Created: true, Creator: creator},
+			}
+			value1, value2, value3 := "value1", "value2", "value3"
+			boxes := map[string]ledgercore.ModifiedBox{
+				ledgercore.MakeBoxKey(15, "a"): {Value: &value1},
+				ledgercore.MakeBoxKey(15, "b"): {Value: &value2},
+				ledgercore.MakeBoxKey(16, "a"): {Value: &value3},
+			}
+			require.NoError(t, commitAccountStore(as, 5, updates, creatables, boxes, 0, proto))
+			checkAccountStore(t, as, 5, expected, accountStoreTotals(expected, proto))
+
+			addr, ok, r, err := as.lookupCreator(20, basics.AssetCreatable)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, creator, addr)
+			require.Equal(t, basics.Round(5), r)
+			_, ok, _, err = as.lookupCreator(20, basics.AppCreatable)
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			cls, r, err := as.listCreatables(25, 10, basics.AssetCreatable)
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(5), r)
+			require.Equal(t, []basics.CreatableLocator{
+				{Type: basics.AssetCreatable, Creator: creator, Index: 20},
+				{Type: basics.AssetCreatable, Creator: creator, Index: 10},
+			}, cls)
+			cls, _, err = as.listCreatables(30, 1, basics.AssetCreatable)
+			require.NoError(t, err)
+			require.Equal(t, []basics.CreatableLocator{{Type: basics.AssetCreatable, Creator: creator, Index: 30}}, cls)
+
+			value, ok, r, err := as.lookupBox(ledgercore.MakeBoxKey(15, "b"))
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, value2, value)
+			require.Equal(t, basics.Round(5), r)
+			_, ok, _, err = as.lookupBox(ledgercore.MakeBoxKey(15, "c"))
+			require.NoError(t, err)
+			require.False(t, ok)
+			keys, r, err := as.listBoxes(15, 10)
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(5), r)
+			require.Equal(t, []string{ledgercore.MakeBoxKey(15, "a"), ledgercore.MakeBoxKey(15, "b")}, keys)
+			keys, _, err = as.listBoxes(15, 1)
+			require.NoError(t, err)
+			require.Equal(t, []string{ledgercore.MakeBoxKey(15, "a")}, keys)
+
+			// the online accounts are sorted by normalized balance and address, both descending
+			var online []basics.Address
+			for addr, data := range expected {
+				if data.NormalizedOnlineBalance(proto) > 0 {
+					online = append(online, addr)
+				}
+			}
+			sort.Slice(online, func(i, j int) bool {
+				bi, bj := expected[online[i]].NormalizedOnlineBalance(proto), expected[online[j]].NormalizedOnlineBalance(proto)
+				if bi != bj {
+					return bi > bj
+				}
+				return bytes.Compare(online[i][:], online[j][:]) > 0
+			})
+			require.Greater(t, len(online), 4)
+			top, r, err := as.onlineTop(2, 3, proto)
+			require.NoError(t, err)
+			require.Equal(t, basics.Round(5), r)
+			require.Len(t, top, 3)
+			for _, addr := range online[2:5] {
+				data := expected[addr]
+				require.Equal(t, accountDataToOnline(addr, &data, proto), top[addr])
+			}
+			top, _, err = as.onlineTop(0, uint64(len(online)+10), proto)
+			require.NoError(t, err)
+			require.Len(t, top, len(online))
+
+			require.NoError(t, commitAccountStore(as, 6, nil, map[basics.CreatableIndex]ledgercore.ModifiedCreatable{
+				20: {Ctype: basics.AssetCreatable, Created: false, Creator: creator},
+			}, map[string]ledgercore.ModifiedBox{
+				ledgercore.MakeBoxKey(15, "a"): {Value: nil},
+			}, 0, proto))
+			cls, _, err = as.listCreatables(25, 10, basics.AssetCreatable)
+			require.NoError(t, err)
+			require.Equal(t, []basics.CreatableLocator{{Type: basics.AssetCreatable, Creator: creator, Index: 10}}, cls)
+			cls, _, err = as.listCreatables(5, 10, basics.AssetCreatable)
+			require.NoError(t, err)
+			require.Empty(t, cls)
+			keys, _, err = as.listBoxes(15, 10)
+			require.NoError(t, err)
+			require.Equal(t, []string{ledgercore.MakeBoxKey(15, "b")}, keys)
+
+			// rounds can't move backward, and a failed commit is discarded
+			require.Error(t, commitAccountStore(as, 5, map[basics.Address]basics.AccountData{added: {}}, nil, nil, 0, proto))
+			checkAccountStore(t, as, 6, expected, accountStoreTotals(expected, proto))
+
+			// a reset storage is initialized again
+			require.NoError(t, as.reset())
+			newDatabase, err = as.initialize(expected, proto)
+			require.NoError(t, err)
+			require.True(t, newDatabase)
+			checkAccountStore(t, as, 0, expected, accountStoreTotals(expected, proto))
+		})
+	}
+}
+
+func TestLedgerKVBlockStorage(t *testing.T) {
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	dbPrefix := filepath.Join(dbTempDir, t.Name())
+	defer os.RemoveAll(dbTempDir)
+
+	genesisInitState := getInitState()
+	const inMem = false
+	cfg := config.GetDefaultLocal()
+	cfg.Archival = true
+	cfg.BlockStorageBackend = StorageBackendKV
+
+	// the key-value store can't hold all the blocks of an archival node.
+	_, err = OpenLedger(logging.TestingLog(t), dbPrefix, inMem, genesisInitState, cfg)
+	require.Error(t, err)
+
+	cfg.Archival = false
+	l, err := OpenLedger(logging.TestingLog(t), dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	blk := genesisInitState.Block
+	const maxBlocks = 100
+	for i := 0; i < maxBlocks; i++ {
+		blk.BlockHeader.Round++
+		blk.BlockHeader.TimeStamp += 1000
+		require.NoError(t, l.AddBlock(blk, agreement.Certificate{}))
+	}
+	l.WaitForCommit(blk.Round())
+	l.Close()
+
+	_, err = os.Stat(dbPrefix + ".block.kv")
+	require.NoError(t, err)
+	_, err = os.Stat(dbPrefix + ".block.sqlite")
+	require.True(t, os.IsNotExist(err))
+
+	l, err = OpenLedger(logging.TestingLog(t), dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+	require.Equal(t, basics.Round(maxBlocks), l.Latest())
+	hdr, err := l.BlockHdr(maxBlocks)
+	require.NoError(t, err)
+	require.Equal(t, blk.BlockHeader, hdr)
+	earliest, err := l.blocks.earliest()
+	require.NoError(t, err)
+	require.Equal(t, basics.Round(0), earliest)
+}
+
+func TestLedgerKVAccountStorage(t *testing.T) {
+	dbTempDir, err := ioutil.TempDir("", "testdir"+t.Name())
+	require.NoError(t, err)
+	dbPrefix := filepath.Join(dbTempDir, t.Name())
+	defer os.RemoveAll(dbTempDir)
+
+	genesisInitState := getInitState()
+	const inMem = false
+	cfg := config.GetDefaultLocal()
+	cfg.AccountStorageBackend = StorageBackendKV
+
+	// catchpoints are only tracked when the accounts are in the tracker database.
+	cfg.CatchpointTracking = 1
+	_, err = OpenLedger(logging.TestingLog(t), dbPrefix, inMem, genesisInitState, cfg)
+	require.Error(t, err)
+
+	cfg.CatchpointTracking = 0
+	l, err := OpenLedger(logging.TestingLog(t), dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	blk := genesisInitState.Block
+	const maxBlocks = 100
+	for i := 0; i < maxBlocks; i++ {
+		blk.BlockHeader.Round++
+		blk.BlockHeader.TimeStamp += 1000
+		require.NoError(t, l.AddBlock(blk, agreement.Certificate{}))
+	}
+	l.WaitForCommit(blk.Round())
+	l.Close()
+
+	_, err = os.Stat(dbPrefix + ".tracker.kv")
+	require.NoError(t, err)
+
+	l, err = OpenLedger(logging.TestingLog(t), dbPrefix, inMem, genesisInitState, cfg)
+	require.NoError(t, err)
+	defer l.Close()
+	require.IsType(t, &kvAccountStore{}, l.accts.store)
+	require.Equal(t, basics.Round(maxBlocks), l.Latest())
+	for addr, data := range genesisInitState.Accounts {
+		stored, _, err := l.LookupWithoutRewards(maxBlocks, addr)
+		require.NoError(t, err)
+		require.Equal(t, data, stored)
+	}
+	totals, err := l.Totals(maxBlocks)
+	require.NoError(t, err)
+	require.Equal(t, accountStoreTotals(genesisInitState.Accounts, config.Consensus[protocol.ConsensusCurrentVersion]), totals)
+
+	// the catchpoint catchup can't restore the accounts into the key-value store.
+	state, err := l.GetCatchpointCatchupState(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, CatchpointCatchupState(CatchpointCatchupStateInactive), state)
+	require.Nil(t, MakeCatchpointCatchupAccessor(l, l.log))
+}
@@ -87,7 +87,8 @@ type ledgerTracker interface {
 // access.  This is particularly useful for testing trackers in isolation.
 type ledgerForTracker interface {
 	trackerDB() db.Pair
-	blockDB() db.Pair
+	blockStorage() blockStore
+	accountStorage() accountStore
 	trackerLog() logging.Logger
 	trackerEvalVerified(bookkeeping.Block, ledgerForEvaluator) (ledgercore.StateDelta, error)
 
@@ -1047,6 +1047,31 @@ func (c *Client) Dryrun(data []byte) (resp generatedV2.DryrunResponse, err error
 	return
 }
 
+// DryrunWithTrace is Dryrun, with a JSON lines trace of every program in
+// the results
+func (c *Client) DryrunWithTrace(data []byte) (resp generatedV2.DryrunResponse, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		data, err = algod.RawDryrunWithTrace(data)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(data, &resp)
+	}
+	return
+}
+
+// DryrunSnapshot returns a JSON dryrun request holding the ledger state that
+// a msgpack encoded transaction group needs, read at round, or at the latest
+// round if round is 0
+func (c *Client) DryrunSnapshot(txgroup []byte, round uint64) (data []byte, err error) {
+	algod, err := c.ensureAlgodClient()
+	if err == nil {
+		return algod.RawDryrunSnapshot(txgroup, round)
+	}
+	return
+}
+
 // TxnProof returns a Merkle proof for a transaction in a block.
 func (c *Client) TxnProof(txid string, round uint64) (resp generatedV2.ProofResponse, err error) {
 	algod, err := c.ensureAlgodClient()
@@ -19,48 +19,122 @@ package indexer
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/algorand/go-algorand/data/basics"
 	"github.com/algorand/go-algorand/data/bookkeeping"
+	"github.com/algorand/go-algorand/data/transactions"
+	"github.com/algorand/go-algorand/protocol"
 	"github.com/algorand/go-algorand/util/db"
 )
 
 const (
 	dbName  = "indexer.sqlite"
 	maxRows = 100
+
+	// maxSearchRows is the maximal number of transactions returned by a
+	// single search
+	maxSearchRows = 1000
 )
 
-var schema = `
-	CREATE TABLE IF NOT EXISTS transactions(
+// schemaMigrations upgrade the indexer database, where the i-th migration
+// upgrades it from schema version i to i+1. The version is kept as the
+// database user_version.
+var schemaMigrations = []func(ctx context.Context, tx *sql.Tx) error{
+	migrateTransactionsSchema,
+}
+
+// transactionsSchema keeps the searchable fields of every transaction. A
+// transaction is identified within the ledger by its round, and by its
+// offset (intra) within the block payset, which is also the order in which
+// search results are returned. Amounts are kept as 8 bytes big-endian
+// blobs, since they might not fit in the signed integers sqlite stores,
+// and such blobs compare the same way the amounts do.
+var transactionsSchema = []string{
+	`CREATE TABLE transactions(
 		txid CHAR(52) PRIMARY KEY NOT NULL,
-		from_addr CHAR(58) DEFAULT NULL,
+		round INTEGER NOT NULL,
+		intra INTEGER NOT NULL,
+		txtype CHAR(6) NOT NULL,
+		from_addr CHAR(58) NOT NULL,
 		to_addr CHAR(58) DEFAULT NULL,
-		round INTEGER DEFAULT NULL,
+		asset INTEGER DEFAULT NULL,
+		application INTEGER DEFAULT NULL,
+		amount BLOB DEFAULT NULL,
+		note BLOB DEFAULT NULL,
 		created_at INTEGER
-	);
-
-	CREATE TABLE IF NOT EXISTS params(
+	)`,
+	`CREATE UNIQUE INDEX transactions_round_intra ON transactions (round, intra)`,
+	`CREATE INDEX transactions_from_addr ON transactions (from_addr, round, intra)`,
+	`CREATE INDEX transactions_to_addr ON transactions (to_addr, round, intra)`,
+	`CREATE INDEX transactions_txtype ON transactions (txtype, round, intra)`,
+	`CREATE INDEX transactions_asset ON transactions (asset, round, intra)`,
+	`CREATE INDEX transactions_application ON transactions (application, round, intra)`,
+	`CREATE INDEX transactions_created_at ON transactions (created_at)`,
+	`CREATE TABLE params(
 		k CHAR(15) PRIMARY KEY DEFAULT NULL,
 		v INTEGER DEFAULT NULL,
 		UNIQUE (k)
-	);
+	)`,
+	`INSERT INTO params (k, v) VALUES ('maxRound', 0)`,
+}
 
-	INSERT OR IGNORE INTO params (k, v) VALUES ('maxRound', 1);
+// migrateTransactionsSchema replaces the original transactions table, which
+// was not versioned and did not keep the fields transactions are searched
+// by, so that all the blocks are indexed again.
+func migrateTransactionsSchema(ctx context.Context, tx *sql.Tx) error {
+	for _, stmt := range []string{"DROP TABLE IF EXISTS transactions", "DROP TABLE IF EXISTS params"} {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	for _, stmt := range transactionsSchema {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	CREATE INDEX IF NOT EXISTS idx ON transactions (
-		created_at	DESC,
-		from_addr,
-		to_addr
-	);
-`
+// upgradeSchema applies the schema migrations the database is missing.
+func upgradeSchema(ctx context.Context, tx *sql.Tx) error {
+	version, err := db.GetUserVersion(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if int(version) > len(schemaMigrations) {
+		return fmt.Errorf("indexer database schema version %d is newer than the supported version %d", version, len(schemaMigrations))
+	}
+	for ; int(version) < len(schemaMigrations); version++ {
+		err = schemaMigrations[version](ctx, tx)
+		if err != nil {
+			return fmt.Errorf("unable to upgrade indexer database schema from version %d: %v", version, err)
+		}
+	}
+	_, err = db.SetUserVersion(ctx, tx, version)
+	return err
+}
 
 // Transaction represents a transaction in the system
 type Transaction struct {
-	TXID      string
-	From      string `db:"from_addr_r"`
-	To        string `db:"to_addr_r"`
-	Round     uint32
-	CreatedAt uint32 `db:"created_at"`
+	TXID  string
+	From  string
+	To    string
+	Round uint32
+	// Intra is the offset of the transaction within the block payset
+	Intra       uint32
+	Type        protocol.TxType
+	Asset       basics.AssetIndex
+	Application basics.AppIndex
+	// Amount is the amount of microalgos of a payment, or the amount of
+	// asset units of an asset transfer
+	Amount    uint64
+	Note      []byte
+	CreatedAt uint32
 }
 
 // DB is a the db access layer for Indexer
@@ -91,7 +165,7 @@ func MakeIndexerDB(dbPath string, inMemory bool) (*DB, error) {
 	}
 	idb.dbw = dbw
 
-	_, err = dbw.Handle.Exec(schema)
+	err = dbw.Atomic(upgradeSchema)
 	if err != nil {
 		return &DB{}, err
 	}
@@ -99,6 +173,52 @@ func MakeIndexerDB(dbPath string, inMemory bool) (*DB, error) {
 	return idb, nil
 }
 
+// makeTransaction extracts the searchable fields of the transaction at
+// offset intra within block b.
+func makeTransaction(b bookkeeping.Block, intra int, txad transactions.SignedTxnWithAD) Transaction {
+	txn := txad.SignedTxn.Txn
+	res := Transaction{
+		TXID:      txn.ID().String(),
+		From:      txn.Sender.String(),
+		Round:     uint32(b.Round()),
+		Intra:     uint32(intra),
+		Type:      txn.Type,
+		Note:      txn.Note,
+		CreatedAt: uint32(b.TimeStamp),
+	}
+	if receiver := txn.GetReceiverAddress(); !receiver.IsZero() {
+		res.To = receiver.String()
+	}
+
+	// the index of a creatable is the value of the transaction counter once
+	// its creating transaction was applied.
+	var createdIndex uint64
+	if b.TxnCounter >= uint64(len(b.Payset)) {
+		createdIndex = b.TxnCounter - uint64(len(b.Payset)) + uint64(intra) + 1
+	}
+
+	switch txn.Type {
+	case protocol.PaymentTx:
+		res.Amount = txn.Amount.Raw
+	case protocol.AssetTransferTx:
+		res.Asset = txn.XferAsset
+		res.Amount = txn.AssetAmount
+	case protocol.AssetConfigTx:
+		res.Asset = txn.ConfigAsset
+		if res.Asset == 0 {
+			res.Asset = basics.AssetIndex(createdIndex)
+		}
+	case protocol.AssetFreezeTx:
+		res.Asset = txn.FreezeAsset
+	case protocol.ApplicationCallTx:
+		res.Application = txn.ApplicationID
+		if res.Application == 0 {
+			res.Application = basics.AppIndex(createdIndex)
+		}
+	}
+	return res
+}
+
 // AddBlock takes an Algorand block and stores its transactions in the DB.
 func (idb *DB) AddBlock(b bookkeeping.Block) error {
 	err := idb.dbw.Atomic(func(ctx context.Context, tx *sql.Tx) error {
@@ -113,7 +233,7 @@ func (idb *DB) AddBlock(b bookkeeping.Block) error {
 			return fmt.Errorf("tryign to add a future block %d, where the last one is %d", b.Round(), rnd)
 		}
 
-		stmt, err := tx.Prepare("INSERT INTO transactions (txid, from_addr, to_addr, round, created_at) VALUES($1,  $2, $3, $4, $5);")
+		stmt, err := tx.Prepare("INSERT INTO transactions (txid, round, intra, txtype, from_addr, to_addr, asset, application, amount, note, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);")
 		if err != nil {
 			return err
 		}
@@ -123,9 +243,23 @@ func (idb *DB) AddBlock(b bookkeeping.Block) error {
 		if err != nil {
 			return err
 		}
-		for _, txad := range payset {
-			txn := txad.SignedTxn
-			_, err = stmt.Exec(txn.ID().String(), txn.Txn.Sender.String(), txn.Txn.GetReceiverAddress().String(), b.Round(), b.TimeStamp)
+		for intra, txad := range payset {
+			txn := makeTransaction(b, intra, txad)
+			var to, asset, application, amount interface{}
+			if txn.To != "" {
+				to = txn.To
+			}
+			if txn.Asset != 0 {
+				asset = txn.Asset
+			}
+			if txn.Application != 0 {
+				application = txn.Application
+			}
+			// only payments and asset transfers are searched by amount
+			if txn.Type == protocol.PaymentTx || txn.Type == protocol.AssetTransferTx {
+				amount = amountBytes(txn.Amount)
+			}
+			_, err = stmt.Exec(txn.TXID, txn.Round, txn.Intra, string(txn.Type), txn.From, to, asset, application, amount, txn.Note, txn.CreatedAt)
 			if err != nil {
 				return err
 			}
@@ -148,29 +282,43 @@ func (idb *DB) AddBlock(b bookkeeping.Block) error {
 	return err
 }
 
-// GetTransactionByID takes a transaction ID and returns its transaction record
-func (idb *DB) GetTransactionByID(txid string) (Transaction, error) {
-	query := `
-		SELECT 
-			txid, 
-			from_addr,
-			to_addr,
-			round,
-			created_at
-		FROM
-			transactions
-		WHERE
-		txid = $1
-	`
+// transactionColumns are the columns scanned by scanTransaction
+const transactionColumns = "txid, round, intra, txtype, from_addr, to_addr, asset, application, amount, note, created_at"
 
+// scanTransaction scans a transaction record selected with transactionColumns
+func scanTransaction(scan func(dest ...interface{}) error) (Transaction, error) {
 	var txn Transaction
-	if err := idb.dbr.Handle.QueryRow(query, txid).Scan(&txn.TXID, &txn.From, &txn.To, &txn.Round, &txn.CreatedAt); err != nil {
+	var txtype string
+	var to sql.NullString
+	var asset, application sql.NullInt64
+	var amount []byte
+	err := scan(&txn.TXID, &txn.Round, &txn.Intra, &txtype, &txn.From, &to, &asset, &application, &amount, &txn.Note, &txn.CreatedAt)
+	if err != nil {
 		return Transaction{}, err
 	}
-
+	txn.Type = protocol.TxType(txtype)
+	txn.To = to.String
+	txn.Asset = basics.AssetIndex(asset.Int64)
+	txn.Application = basics.AppIndex(application.Int64)
+	if len(amount) == 8 {
+		txn.Amount = binary.BigEndian.Uint64(amount)
+	}
 	return txn, nil
 }
 
+// amountBytes encodes an amount the way it's kept in the transactions table
+func amountBytes(amount uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], amount)
+	return buf[:]
+}
+
+// GetTransactionByID takes a transaction ID and returns its transaction record
+func (idb *DB) GetTransactionByID(txid string) (Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM transactions WHERE txid = $1"
+	return scanTransaction(idb.dbr.Handle.QueryRow(query, txid).Scan)
+}
+
 // GetTransactionsRoundsByAddr takes an address and returns all its transaction rounds records
 // if top is 0, it will return 25 transactions by default
 func (idb *DB) GetTransactionsRoundsByAddr(addr string, top uint64) ([]uint64, error) {
@@ -180,8 +328,8 @@ func (idb *DB) GetTransactionsRoundsByAddr(addr string, top uint64) ([]uint64, e
 		FROM
 			transactions
 		WHERE
-		from_addr = $1 OR to_addr = $1 
-		ORDER BY created_at DESC
+		from_addr = $1 OR to_addr = $1
+		ORDER BY round DESC
 		LIMIT $2;
 	`
 
@@ -263,6 +411,149 @@ func (idb *DB) GetTransactionsRoundsByAddrAndDate(addr string, top uint64, from,
 	return rounds, nil
 }
 
+// SearchParams are the criteria transactions are searched by. Zero values
+// do not restrict the search.
+type SearchParams struct {
+	// Address matches either the sender or the receiver of the transaction
+	Address       string
+	Type          protocol.TxType
+	AssetID       basics.AssetIndex
+	ApplicationID basics.AppIndex
+	NotePrefix    []byte
+
+	// AmountGreaterThan and AmountLessThan restrict the amount of asset
+	// transfers if AssetID is set, and the amount of payments otherwise.
+	// Other transactions are never matched by them.
+	AmountGreaterThan *uint64
+	AmountLessThan    *uint64
+
+	MinRound basics.Round
+	MaxRound basics.Round
+
+	// Limit defaults to 100, and may not exceed 1000
+	Limit uint64
+
+	// Next continues a search from the NextToken of its previous page
+	Next string
+}
+
+// ErrInvalidNextToken is returned by searches given a next token they did
+// not produce
+var ErrInvalidNextToken = errors.New("invalid next token")
+
+// encodeNextToken returns a token resuming a search after the transaction at
+// the given round and intra offset.
+func encodeNextToken(round uint32, intra uint32) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:4], round)
+	binary.BigEndian.PutUint32(buf[4:], intra)
+	return base64.RawURLEncoding.EncodeToString(buf[:])
+}
+
+func decodeNextToken(token string) (round uint32, intra uint32, err error) {
+	buf, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(buf) != 8 {
+		return 0, 0, ErrInvalidNextToken
+	}
+	return binary.BigEndian.Uint32(buf[:4]), binary.BigEndian.Uint32(buf[4:]), nil
+}
+
+// SearchTransactions returns the transactions matching params, in the order
+// in which they were confirmed. If more transactions may match, it also
+// returns the token continuing the search.
+func (idb *DB) SearchTransactions(params SearchParams) (txns []Transaction, nextToken string, err error) {
+	var where []string
+	var args []interface{}
+	if params.Address != "" {
+		where = append(where, "(from_addr = ? OR to_addr = ?)")
+		args = append(args, params.Address, params.Address)
+	}
+	if params.Type != "" {
+		where = append(where, "txtype = ?")
+		args = append(args, string(params.Type))
+	}
+	if params.AssetID != 0 {
+		where = append(where, "asset = ?")
+		args = append(args, params.AssetID)
+	}
+	if params.ApplicationID != 0 {
+		where = append(where, "application = ?")
+		args = append(args, params.ApplicationID)
+	}
+	if len(params.NotePrefix) > 0 {
+		where = append(where, "substr(note, 1, ?) = ?")
+		args = append(args, len(params.NotePrefix), params.NotePrefix)
+	}
+	if params.AssetID == 0 && (params.AmountGreaterThan != nil || params.AmountLessThan != nil) {
+		where = append(where, "txtype = ?")
+		args = append(args, string(protocol.PaymentTx))
+	}
+	if params.AmountGreaterThan != nil {
+		where = append(where, "amount > ?")
+		args = append(args, amountBytes(*params.AmountGreaterThan))
+	}
+	if params.AmountLessThan != nil {
+		where = append(where, "amount < ?")
+		args = append(args, amountBytes(*params.AmountLessThan))
+	}
+	if params.MinRound != 0 {
+		where = append(where, "round >= ?")
+		args = append(args, params.MinRound)
+	}
+	if params.MaxRound != 0 {
+		where = append(where, "round <= ?")
+		args = append(args, params.MaxRound)
+	}
+	if params.Next != "" {
+		round, intra, err := decodeNextToken(params.Next)
+		if err != nil {
+			return nil, "", err
+		}
+		where = append(where, "(round > ? OR (round = ? AND intra > ?))")
+		args = append(args, round, round, intra)
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = maxRows
+	}
+	if limit > maxSearchRows {
+		limit = maxSearchRows
+	}
+
+	query := "SELECT " + transactionColumns + " FROM transactions"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY round, intra LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := idb.dbr.Handle.Query(query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		txn, err := scanTransaction(rows.Scan)
+		if err != nil {
+			return nil, "", err
+		}
+		txns = append(txns, txn)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// a full page may be followed by more matching transactions
+	if uint64(len(txns)) == limit {
+		last := txns[len(txns)-1]
+		nextToken = encodeNextToken(last.Round, last.Intra)
+	}
+	return txns, nextToken, nil
+}
+
 // MaxRound returns the latest block in the DB
 func (idb *DB) MaxRound() (uint64, error) {
 	var rnd uint64
@@ -89,6 +89,12 @@ func (idx *Indexer) GetRoundsByAddress(addr string, top uint64) ([]uint64, error
 	return rounds, nil
 }
 
+// SearchTransactions returns the transactions matching params, and the
+// token continuing the search if more transactions may match.
+func (idx *Indexer) SearchTransactions(params SearchParams) ([]Transaction, string, error) {
+	return idx.IDB.SearchTransactions(params)
+}
+
 // NewBlock takes a block and updates the DB
 // If the block exists, return nil.the block must be the next block
 func (idx *Indexer) NewBlock(b bookkeeping.Block) error {
@@ -17,6 +17,9 @@
 package indexer
 
 import (
+	"context"
+	"database/sql"
+	"math"
 	"math/rand"
 	"os"
 	"testing"
@@ -31,6 +34,7 @@ import (
 	"github.com/algorand/go-algorand/data/bookkeeping"
 	"github.com/algorand/go-algorand/data/transactions"
 	"github.com/algorand/go-algorand/protocol"
+	"github.com/algorand/go-algorand/util/db"
 )
 
 type IndexSuite struct {
@@ -61,7 +65,7 @@ func (s *IndexSuite) SetupSuite() {
 		var txnEnc []transactions.SignedTxnInBlock
 		b := bookkeeping.Block{
 			BlockHeader: bookkeeping.BlockHeader{
-				Round:     basics.Round(uint64(i + 2)),
+				Round:     basics.Round(uint64(i + 1)),
 				TimeStamp: time.Now().Unix(),
 			},
 		}
@@ -80,7 +84,7 @@ func (s *IndexSuite) SetupSuite() {
 
 		r, err := s.idx.LastBlock()
 		require.NoError(s.T(), err)
-		require.Equal(s.T(), basics.Round(i+2), r)
+		require.Equal(s.T(), basics.Round(i+1), r)
 	}
 }
 
@@ -149,10 +153,223 @@ func (s *IndexSuite) TestIndexer_Asset() {
 
 }
 
+func (s *IndexSuite) TestIndexer_Search() {
+	chunkSize := len(s.txns) / 10
+	lower := uint64(200)
+	upper := uint64(800)
+	asset := s.txns[1].Txn.XferAsset
+
+	tests := []struct {
+		name   string
+		params SearchParams
+		match  func(txn transactions.Transaction, rnd basics.Round) bool
+	}{
+		{"all", SearchParams{}, func(transactions.Transaction, basics.Round) bool { return true }},
+		{"address", SearchParams{Address: s.addrs[0].String()}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return txn.Sender == s.addrs[0] || txn.GetReceiverAddress() == s.addrs[0]
+		}},
+		{"type", SearchParams{Type: protocol.PaymentTx}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return txn.Type == protocol.PaymentTx
+		}},
+		{"asset", SearchParams{AssetID: asset}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return txn.Type == protocol.AssetTransferTx && txn.XferAsset == asset
+		}},
+		{"note prefix", SearchParams{NotePrefix: []byte{1, 2}}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return txn.Note[0] == 1 && txn.Note[1] == 2
+		}},
+		{"amount", SearchParams{AmountGreaterThan: &lower, AmountLessThan: &upper}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return txn.Type == protocol.PaymentTx && txn.Amount.Raw > lower && txn.Amount.Raw < upper
+		}},
+		{"asset amount", SearchParams{AssetID: asset, AmountGreaterThan: &lower}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return txn.Type == protocol.AssetTransferTx && txn.XferAsset == asset && txn.AssetAmount > lower
+		}},
+		{"rounds", SearchParams{MinRound: 3, MaxRound: 5, Type: protocol.AssetTransferTx}, func(txn transactions.Transaction, rnd basics.Round) bool {
+			return rnd >= 3 && rnd <= 5 && txn.Type == protocol.AssetTransferTx
+		}},
+	}
+	for _, test := range tests {
+		var expected []string
+		for i, stxn := range s.txns {
+			if test.match(stxn.Txn, basics.Round(i/chunkSize+1)) {
+				expected = append(expected, stxn.ID().String())
+			}
+		}
+
+		// page through the results
+		var found []string
+		params := test.params
+		params.Limit = 97
+		for {
+			txns, next, err := s.idx.SearchTransactions(params)
+			require.NoError(s.T(), err, test.name)
+			require.LessOrEqual(s.T(), len(txns), 97, test.name)
+			for _, txn := range txns {
+				found = append(found, txn.TXID)
+			}
+			if next == "" {
+				break
+			}
+			params.Next = next
+		}
+		require.Equal(s.T(), expected, found, test.name)
+	}
+
+	_, _, err := s.idx.SearchTransactions(SearchParams{Next: "invalid"})
+	require.Equal(s.T(), ErrInvalidNextToken, err)
+
+	// the searchable fields are kept
+	txns, _, err := s.idx.SearchTransactions(SearchParams{Limit: 2})
+	require.NoError(s.T(), err)
+	require.Len(s.T(), txns, 2)
+	require.Equal(s.T(), Transaction{
+		TXID:      s.txns[1].ID().String(),
+		From:      s.txns[1].Txn.Sender.String(),
+		To:        s.txns[1].Txn.AssetReceiver.String(),
+		Round:     1,
+		Intra:     1,
+		Type:      protocol.AssetTransferTx,
+		Asset:     s.txns[1].Txn.XferAsset,
+		Amount:    s.txns[1].Txn.AssetAmount,
+		Note:      s.txns[1].Txn.Note,
+		CreatedAt: txns[1].CreatedAt,
+	}, txns[1])
+}
+
 func TestExampleTestSuite(t *testing.T) {
 	suite.Run(t, new(IndexSuite))
 }
 
+func TestIndexer_CreatedIndex(t *testing.T) {
+	idb, err := MakeIndexerDB(t.TempDir(), false)
+	require.NoError(t, err)
+	defer idb.Close()
+
+	sender := basics.Address{1}
+	stxns := []transactions.SignedTxn{
+		{Txn: transactions.Transaction{Type: protocol.PaymentTx, Header: transactions.Header{Sender: sender}}},
+		{Txn: transactions.Transaction{Type: protocol.AssetConfigTx, Header: transactions.Header{Sender: sender}}},
+		{Txn: transactions.Transaction{Type: protocol.ApplicationCallTx, Header: transactions.Header{Sender: sender}}},
+		{Txn: transactions.Transaction{Type: protocol.ApplicationCallTx, Header: transactions.Header{Sender: sender}, ApplicationCallTxnFields: transactions.ApplicationCallTxnFields{ApplicationID: 5}}},
+	}
+	b := bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{Round: 1, TxnCounter: 20}}
+	for _, stxn := range stxns {
+		txib, err := b.EncodeSignedTxn(stxn, transactions.ApplyData{})
+		require.NoError(t, err)
+		b.Payset = append(b.Payset, txib)
+	}
+	require.NoError(t, idb.AddBlock(b))
+
+	// the transactions counter was 16 before the block
+	txns, _, err := idb.SearchTransactions(SearchParams{AssetID: 18})
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	require.Equal(t, stxns[1].ID().String(), txns[0].TXID)
+	txns, _, err = idb.SearchTransactions(SearchParams{ApplicationID: 19})
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	require.Equal(t, stxns[2].ID().String(), txns[0].TXID)
+	txns, _, err = idb.SearchTransactions(SearchParams{ApplicationID: 5})
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	require.Equal(t, stxns[3].ID().String(), txns[0].TXID)
+
+	// transactions without amounts are not matched by amount
+	zero := uint64(0)
+	txns, _, err = idb.SearchTransactions(SearchParams{AmountLessThan: &zero})
+	require.NoError(t, err)
+	require.Empty(t, txns)
+	one := uint64(1)
+	txns, _, err = idb.SearchTransactions(SearchParams{AmountLessThan: &one})
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	require.Equal(t, stxns[0].ID().String(), txns[0].TXID)
+}
+
+func TestIndexer_SchemaMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	// create a database with the original, unversioned schema
+	dbw, err := db.MakeAccessor(dir+"/"+dbName, false, false)
+	require.NoError(t, err)
+	_, err = dbw.Handle.Exec(`
+		CREATE TABLE transactions(txid CHAR(52) PRIMARY KEY NOT NULL, from_addr CHAR(58) DEFAULT NULL, to_addr CHAR(58) DEFAULT NULL, round INTEGER DEFAULT NULL, created_at INTEGER);
+		CREATE TABLE params(k CHAR(15) PRIMARY KEY DEFAULT NULL, v INTEGER DEFAULT NULL, UNIQUE (k));
+		INSERT INTO params (k, v) VALUES ('maxRound', 12);
+		INSERT INTO transactions (txid, from_addr, to_addr, round, created_at) VALUES ('txid', 'from', 'to', 12, 0);
+	`)
+	require.NoError(t, err)
+	dbw.Close()
+
+	// the transactions are indexed again after the migration
+	idb, err := MakeIndexerDB(dir, false)
+	require.NoError(t, err)
+	rnd, err := idb.MaxRound()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), rnd)
+	txns, _, err := idb.SearchTransactions(SearchParams{})
+	require.NoError(t, err)
+	require.Empty(t, txns)
+	err = idb.dbr.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		version, err := db.GetUserVersion(ctx, tx)
+		require.Equal(t, int32(len(schemaMigrations)), version)
+		return err
+	})
+	require.NoError(t, err)
+	idb.Close()
+
+	// an up to date database is kept as is
+	idb, err = MakeIndexerDB(dir, false)
+	require.NoError(t, err)
+	require.NoError(t, idb.AddBlock(bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{Round: 1}}))
+	idb.Close()
+	idb, err = MakeIndexerDB(dir, false)
+	require.NoError(t, err)
+	defer idb.Close()
+	rnd, err = idb.MaxRound()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), rnd)
+}
+
+func TestIndexer_LargeAmounts(t *testing.T) {
+	idb, err := MakeIndexerDB(t.TempDir(), false)
+	require.NoError(t, err)
+	defer idb.Close()
+
+	_, txns, _, _ := generateTestObjects(2, 2)
+	txns[0].Txn.Type = protocol.AssetTransferTx
+	txns[0].Txn.XferAsset = 1
+	txns[0].Txn.AssetAmount = math.MaxUint64
+	txns[1].Txn.Type = protocol.AssetTransferTx
+	txns[1].Txn.XferAsset = 1
+	txns[1].Txn.AssetAmount = 1
+	b := bookkeeping.Block{BlockHeader: bookkeeping.BlockHeader{Round: 1}}
+	for _, stxn := range txns {
+		txib, err := b.EncodeSignedTxn(stxn, transactions.ApplyData{})
+		require.NoError(t, err)
+		b.Payset = append(b.Payset, txib)
+	}
+	require.NoError(t, idb.AddBlock(b))
+
+	search := func(greaterThan, lessThan *uint64) []Transaction {
+		found, _, err := idb.SearchTransactions(SearchParams{AssetID: 1, AmountGreaterThan: greaterThan, AmountLessThan: lessThan})
+		require.NoError(t, err)
+		return found
+	}
+	maxAmount := uint64(math.MaxUint64)
+	almostMaxAmount := uint64(math.MaxUint64 - 1)
+	small := uint64(1 << 32)
+	found := search(&almostMaxAmount, nil)
+	require.Len(t, found, 1)
+	require.Equal(t, txns[0].ID().String(), found[0].TXID)
+	require.Equal(t, uint64(math.MaxUint64), found[0].Amount)
+	require.Empty(t, search(&maxAmount, nil))
+	found = search(nil, &maxAmount)
+	require.Len(t, found, 1)
+	require.Equal(t, txns[1].ID().String(), found[0].TXID)
+	require.Len(t, search(nil, &small), 1)
+	require.Len(t, search(nil, nil), 2)
+}
+
 func BenchmarkORM_AddTransactions(b *testing.B) {
 	idx, _ := MakeIndexer(".", &TestLedger{}, false)
 	_, txns, _, _ := generateTestObjects(5000, 100)
@@ -231,6 +448,7 @@ func generateTestObjects(numTxs, numAccs int) ([]transactions.Transaction, []tra
 				Fee:        basics.MicroAlgos{Raw: f},
 				FirstValid: basics.Round(iss),
 				LastValid:  basics.Round(exp),
+				Note:       []byte{byte(i % 3), byte(i % 5)},
 			},
 		}
 
@@ -246,7 +464,7 @@ func generateTestObjects(numTxs, numAccs int) ([]transactions.Transaction, []tra
 			txs[i].AssetTransferTxnFields = transactions.AssetTransferTxnFields{
 				AssetReceiver: addresses[r],
 				AssetAmount:   uint64(a),
-				XferAsset:     basics.AssetIndex(uint64(rand.Intn(20000))),
+				XferAsset:     basics.AssetIndex(uint64(rand.Intn(20000) + 1)),
 			}
 		}
 		signed[i] = txs[i].Sign(secrets[s])
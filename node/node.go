@@ -32,6 +32,7 @@ import (
 	"github.com/algorand/go-algorand/compactcert"
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/crypto"
+	"github.com/algorand/go-algorand/daemon/partsigner"
 	"github.com/algorand/go-algorand/data"
 	"github.com/algorand/go-algorand/data/account"
 	"github.com/algorand/go-algorand/data/basics"
@@ -52,6 +53,7 @@ import (
 	"github.com/algorand/go-algorand/util/execpool"
 	"github.com/algorand/go-algorand/util/metrics"
 	"github.com/algorand/go-algorand/util/timers"
+	"github.com/algorand/go-algorand/util/tokens"
 	"github.com/algorand/go-deadlock"
 )
 
@@ -74,6 +76,8 @@ type StatusReport struct {
 	CatchpointCatchupVerifiedAccounts  uint64
 	CatchpointCatchupTotalBlocks       uint64
 	CatchpointCatchupAcquiredBlocks    uint64
+	CatchpointCatchupTotalChunks       uint64
+	CatchpointCatchupAcquiredChunks    uint64
 }
 
 // TimeSinceLastRound returns the time since the last block was approved (locally), or 0 if no blocks seen
@@ -679,6 +683,8 @@ func (node *AlgorandFullNode) Status() (s StatusReport, err error) {
 		s.CatchpointCatchupVerifiedAccounts = stats.VerifiedAccounts
 		s.CatchpointCatchupTotalBlocks = stats.TotalBlocks
 		s.CatchpointCatchupAcquiredBlocks = stats.AcquiredBlocks
+		s.CatchpointCatchupTotalChunks = stats.TotalChunks
+		s.CatchpointCatchupAcquiredChunks = stats.AcquiredChunks
 		s.CatchupTime = time.Now().Sub(stats.StartTime)
 	} else {
 		// we're not in catchpoint catchup mode
@@ -738,6 +744,12 @@ func (node *AlgorandFullNode) SuggestedFee() basics.MicroAlgos {
 	return basics.MicroAlgos{Raw: node.transactionPool.FeePerByte()}
 }
 
+// CongestionFee returns the fee per byte a new transaction currently needs to pay to get into the transaction pool,
+// which includes outbidding the lowest fee transactions when the pool is full.
+func (node *AlgorandFullNode) CongestionFee() basics.MicroAlgos {
+	return basics.MicroAlgos{Raw: node.transactionPool.CongestionFeePerByte()}
+}
+
 // GetPendingTxnsFromPool returns a snapshot of every pending transactions from the node's transaction pool in a slice.
 // Transactions are sorted in decreasing order. If no transactions, returns an empty slice.
 func (node *AlgorandFullNode) GetPendingTxnsFromPool() ([]transactions.SignedTxn, error) {
@@ -818,9 +830,37 @@ func (node *AlgorandFullNode) loadParticipationKeys() error {
 		}
 	}
 
+	if node.config.ParticipationSignerAddress != "" {
+		node.loadRemoteParticipationKeys()
+	}
+
 	return nil
 }
 
+// loadRemoteParticipationKeys adds the participation keys held by the participation signer to the
+// AccountManager. Failing to reach the signer is not fatal, as it may only be temporarily unavailable:
+// the keys are loaded again on the next refresh.
+func (node *AlgorandFullNode) loadRemoteParticipationKeys() {
+	apiToken, err := tokens.GetAndValidateAPIToken(node.rootDir, tokens.PartSignerTokenFilename)
+	if err != nil {
+		node.log.Warnf("loadRemoteParticipationKeys: cannot read participation signer API token: %v", err)
+		return
+	}
+
+	client := partsigner.MakeClient(node.config.ParticipationSignerAddress, apiToken)
+	parts, err := client.Participations()
+	if err != nil {
+		node.log.Warnf("loadRemoteParticipationKeys: cannot list participation keys of signer %s: %v", node.config.ParticipationSignerAddress, err)
+		return
+	}
+
+	for _, part := range parts {
+		if node.accountManager.AddParticipation(account.PersistedParticipation{Participation: part}) {
+			node.log.Infof("Loaded participation keys from signer: %s %s", part.Address(), node.config.ParticipationSignerAddress)
+		}
+	}
+}
+
 var txPoolGuage = metrics.MakeGauge(metrics.MetricName{Name: "algod_tx_pool_count", Description: "current number of available transactions in pool"})
 
 func (node *AlgorandFullNode) txPoolGaugeThread() {
@@ -32,6 +32,8 @@ const (
 	CompactCertPart HashID = "ccp"
 	CompactCertSig  HashID = "ccs"
 
+	AppIndex HashID = "appID"
+
 	AgreementSelector HashID = "AS"
 	BlockHeader       HashID = "BH"
 	BalanceRecord     HashID = "BR"
@@ -32,9 +32,11 @@ import (
 	"github.com/algorand/go-algorand/config"
 	"github.com/algorand/go-algorand/data"
 	"github.com/algorand/go-algorand/data/basics"
+	"github.com/algorand/go-algorand/ledger"
 	"github.com/algorand/go-algorand/ledger/ledgercore"
 	"github.com/algorand/go-algorand/logging"
 	"github.com/algorand/go-algorand/network"
+	"github.com/algorand/go-algorand/protocol"
 )
 
 const (
@@ -47,6 +49,19 @@ const (
 	// e.g. .Handle(LedgerServiceLedgerPath, &ls)
 	LedgerServiceLedgerPath = "/v{version:[0-9.]+}/{genesisID}/ledger/{round:[0-9a-z]+}"
 
+	// LedgerChunksResponseContentType is the HTTP Content-Type header for the download chunks of a catchpoint file
+	LedgerChunksResponseContentType = "application/x-algorand-ledger-chunks-v1"
+
+	// LedgerChunkResponseContentType is the HTTP Content-Type header for a single download chunk of a catchpoint file
+	LedgerChunkResponseContentType = "application/x-algorand-ledger-chunk-v1"
+
+	// LedgerServiceChunksPath is the path of the download chunks description of the catchpoint file of a round
+	LedgerServiceChunksPath = "/v{version:[0-9.]+}/{genesisID}/ledger/{round:[0-9a-z]+}/chunks"
+
+	// LedgerServiceChunkPath is the path of a single download chunk of the catchpoint file of a round. The chunk is served
+	// as is, i.e. as a slice of the gzip compressed catchpoint file.
+	LedgerServiceChunkPath = "/v{version:[0-9.]+}/{genesisID}/ledger/{round:[0-9a-z]+}/chunk/{chunk:[0-9]+}"
+
 	// maxCatchpointFileSize is the default catchpoint file size, if we can't get a concreate number from the ledger.
 	maxCatchpointFileSize = 512 * 1024 * 1024 // 512MB
 
@@ -76,6 +91,8 @@ func MakeLedgerService(config config.Local, ledger *data.Ledger, net network.Gos
 	// the underlying gorilla/mux doesn't support "unregister", so we're forced to implement it ourselves.
 	if service.enableService {
 		net.RegisterHTTPHandler(LedgerServiceLedgerPath, service)
+		net.RegisterHTTPHandler(LedgerServiceChunksPath, http.HandlerFunc(service.serveChunks))
+		net.RegisterHTTPHandler(LedgerServiceChunkPath, http.HandlerFunc(service.serveChunk))
 	}
 	return service
 }
@@ -105,6 +122,171 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 		response.WriteHeader(http.StatusNotFound)
 		return
 	}
+	round, ok := ls.parseRound(response, request)
+	if !ok {
+		return
+	}
+	cs, ok := ls.getCatchpointStream(response, round)
+	if !ok {
+		return
+	}
+	defer cs.Close()
+	if conn := ls.net.GetHTTPRequestConnection(request); conn != nil {
+		maxCatchpointFileWritingDuration := 2 * time.Minute
+
+		catchpointFileSize, err := cs.Size()
+		if err != nil || catchpointFileSize <= 0 {
+			maxCatchpointFileWritingDuration += maxCatchpointFileSize * time.Second / expectedWorstUploadSpeedBytesPerSecond
+		} else {
+			maxCatchpointFileWritingDuration += time.Duration(catchpointFileSize) * time.Second / expectedWorstUploadSpeedBytesPerSecond
+		}
+		conn.SetWriteDeadline(time.Now().Add(maxCatchpointFileWritingDuration))
+	} else {
+		logging.Base().Warnf("LedgerService.ServeHTTP unable to set connection timeout")
+	}
+
+	response.Header().Set("Content-Type", LedgerResponseContentType)
+	requestedCompressedResponse := strings.Contains(request.Header.Get("Accept-Encoding"), "gzip")
+	if requestedCompressedResponse {
+		response.Header().Set("Content-Encoding", "gzip")
+		written, err := io.Copy(response, cs)
+		if err != nil {
+			logging.Base().Infof("LedgerService.ServeHTTP : unable to write compressed catchpoint file for round %d, written bytes %d : %v", round, written, err)
+		}
+		return
+	}
+	decompressedGzip, err := gzip.NewReader(cs)
+	if err != nil {
+		logging.Base().Warnf("LedgerService.ServeHTTP : failed to decompress catchpoint %d %v", round, err)
+		response.WriteHeader(http.StatusInternalServerError)
+		response.Write([]byte(fmt.Sprintf("catchpoint file for round %d could not be decompressed due to internal error : %v", round, err)))
+		return
+	}
+	defer decompressedGzip.Close()
+	written, err := io.Copy(response, decompressedGzip)
+	if err != nil {
+		logging.Base().Infof("LedgerService.ServeHTTP : unable to write decompressed catchpoint file for round %d, written bytes %d : %v", round, written, err)
+	}
+}
+
+// serveChunks returns the download chunks of the catchpoint file of a particular round, so that a node could download
+// the file from several peers in parallel.
+// /v{version}/{genesisID}/ledger/{round}/chunks
+func (ls *LedgerService) serveChunks(response http.ResponseWriter, request *http.Request) {
+	ls.stopping.Add(1)
+	defer ls.stopping.Done()
+	if atomic.AddInt32(&ls.running, 0) == 0 {
+		response.WriteHeader(http.StatusNotFound)
+		return
+	}
+	round, ok := ls.parseRound(response, request)
+	if !ok {
+		return
+	}
+	chunks, ok := ls.getChunks(response, round)
+	if !ok {
+		return
+	}
+	response.Header().Set("Content-Type", LedgerChunksResponseContentType)
+	response.WriteHeader(http.StatusOK)
+	_, err := response.Write(protocol.Encode(&chunks))
+	if err != nil {
+		logging.Base().Infof("LedgerService.serveChunks : unable to write the download chunks of catchpoint file for round %d : %v", round, err)
+	}
+}
+
+// serveChunk returns a single download chunk of the catchpoint file of a particular round.
+// /v{version}/{genesisID}/ledger/{round}/chunk/{chunk}
+func (ls *LedgerService) serveChunk(response http.ResponseWriter, request *http.Request) {
+	ls.stopping.Add(1)
+	defer ls.stopping.Done()
+	if atomic.AddInt32(&ls.running, 0) == 0 {
+		response.WriteHeader(http.StatusNotFound)
+		return
+	}
+	round, ok := ls.parseRound(response, request)
+	if !ok {
+		return
+	}
+	chunkStr := mux.Vars(request)["chunk"]
+	chunk, err := strconv.ParseUint(chunkStr, 10, 32)
+	if err != nil {
+		logging.Base().Debugf("http ledger chunk parse fail ('%s'): %v", chunkStr, err)
+		response.WriteHeader(http.StatusBadRequest)
+		response.Write([]byte(fmt.Sprintf("specified chunk number could not be parsed : %v", err)))
+		return
+	}
+	cs, ok := ls.getCatchpointStream(response, round)
+	if !ok {
+		return
+	}
+	defer cs.Close()
+	catchpointFileSize, err := cs.Size()
+	if err != nil {
+		logging.Base().Warnf("LedgerService.serveChunk : failed to get the size of catchpoint %d %v", round, err)
+		response.WriteHeader(http.StatusInternalServerError)
+		response.Write([]byte(fmt.Sprintf("catchpoint file for round %d could not be retrieved due to internal error : %v", round, err)))
+		return
+	}
+	offset := int64(chunk) * ledger.CatchpointDownloadChunkSize
+	if offset >= catchpointFileSize {
+		response.WriteHeader(http.StatusNotFound)
+		response.Write([]byte(fmt.Sprintf("catchpoint file for round %d has no chunk %d", round, chunk)))
+		return
+	}
+	size := catchpointFileSize - offset
+	if size > ledger.CatchpointDownloadChunkSize {
+		size = ledger.CatchpointDownloadChunkSize
+	}
+	// the chunks are served out of order, so reading the file up to the chunk on every request isn't an option.
+	seeker, ok := cs.(io.Seeker)
+	if !ok {
+		err = fmt.Errorf("catchpoint stream doesn't support seeking")
+	} else {
+		_, err = seeker.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		logging.Base().Warnf("LedgerService.serveChunk : failed to seek catchpoint %d to chunk %d %v", round, chunk, err)
+		response.WriteHeader(http.StatusInternalServerError)
+		response.Write([]byte(fmt.Sprintf("catchpoint file for round %d could not be retrieved due to internal error : %v", round, err)))
+		return
+	}
+	if conn := ls.net.GetHTTPRequestConnection(request); conn != nil {
+		conn.SetWriteDeadline(time.Now().Add(time.Minute + time.Duration(size)*time.Second/expectedWorstUploadSpeedBytesPerSecond))
+	} else {
+		logging.Base().Warnf("LedgerService.serveChunk unable to set connection timeout")
+	}
+
+	response.Header().Set("Content-Type", LedgerChunkResponseContentType)
+	response.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	response.WriteHeader(http.StatusOK)
+	written, err := io.CopyN(response, cs, size)
+	if err != nil {
+		logging.Base().Infof("LedgerService.serveChunk : unable to write chunk %d of catchpoint file for round %d, written bytes %d : %v", chunk, round, written, err)
+	}
+}
+
+// getChunks returns the download chunks of the catchpoint file of the given round, writing the error response if
+// they can't be retrieved.
+func (ls *LedgerService) getChunks(response http.ResponseWriter, round uint64) (ledger.CatchpointDownloadChunks, bool) {
+	chunks, err := ls.ledger.GetCatchpointDownloadChunks(basics.Round(round))
+	if err != nil {
+		switch err.(type) {
+		case ledgercore.ErrNoEntry:
+			response.WriteHeader(http.StatusNotFound)
+			response.Write([]byte(fmt.Sprintf("catchpoint file for round %d is not available", round)))
+		default:
+			logging.Base().Warnf("LedgerService.getChunks : failed to retrieve the download chunks of catchpoint %d %v", round, err)
+			response.WriteHeader(http.StatusInternalServerError)
+			response.Write([]byte(fmt.Sprintf("download chunks of catchpoint file for round %d could not be retrieved due to internal error : %v", round, err)))
+		}
+		return ledger.CatchpointDownloadChunks{}, false
+	}
+	return chunks, true
+}
+
+// parseRound parses the round of the requested catchpoint out of the request, writing the error response if it can't be parsed.
+func (ls *LedgerService) parseRound(response http.ResponseWriter, request *http.Request) (round uint64, ok bool) {
 	pathVars := mux.Vars(request)
 	versionStr, hasVersionStr := pathVars["version"]
 	roundStr, hasRoundStr := pathVars["round"]
@@ -114,7 +296,7 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 			logging.Base().Debugf("http ledger bad version '%s'", versionStr)
 			response.WriteHeader(http.StatusBadRequest)
 			response.Write([]byte(fmt.Sprintf("unsupported version '%s'", versionStr)))
-			return
+			return 0, false
 		}
 	}
 	if hasGenesisID {
@@ -122,13 +304,13 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 			logging.Base().Debugf("http ledger bad genesisID mine=%#v theirs=%#v", ls.genesisID, genesisID)
 			response.WriteHeader(http.StatusBadRequest)
 			response.Write([]byte(fmt.Sprintf("mismatching genesisID '%s'", genesisID)))
-			return
+			return 0, false
 		}
 	} else {
 		logging.Base().Debug("http ledger no genesisID")
 		response.WriteHeader(http.StatusBadRequest)
 		response.Write([]byte("missing genesisID"))
-		return
+		return 0, false
 	}
 	if (!hasVersionStr) || (!hasRoundStr) {
 		// try query arg ?r={round}
@@ -138,14 +320,14 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 			logging.Base().Debugf("http ledger parse form err : %v", err)
 			response.WriteHeader(http.StatusBadRequest)
 			response.Write([]byte(fmt.Sprintf("unable to parse form body : %v", err)))
-			return
+			return 0, false
 		}
 		roundStrs, ok := request.Form["r"]
 		if !ok || len(roundStrs) != 1 {
 			logging.Base().Debugf("http ledger bad round number form arg '%s'", roundStrs)
 			response.WriteHeader(http.StatusBadRequest)
 			response.Write([]byte("invalid round number specified in 'r' form argument"))
-			return
+			return 0, false
 		}
 		roundStr = roundStrs[0]
 		versionStrs, ok := request.Form["v"]
@@ -155,13 +337,13 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 					logging.Base().Debugf("http ledger bad version '%s'", versionStr)
 					response.WriteHeader(http.StatusBadRequest)
 					response.Write([]byte(fmt.Sprintf("unsupported version specified '%s'", versionStrs[0])))
-					return
+					return 0, false
 				}
 			} else {
 				logging.Base().Debugf("http ledger wrong number of v=%d args", len(versionStrs))
 				response.WriteHeader(http.StatusBadRequest)
 				response.Write([]byte(fmt.Sprintf("invalid number of version specified %d", len(versionStrs))))
-				return
+				return 0, false
 			}
 		} else {
 			versionStr = "1"
@@ -172,8 +354,13 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 		logging.Base().Debugf("http ledger round parse fail ('%s'): %v", roundStr, err)
 		response.WriteHeader(http.StatusBadRequest)
 		response.Write([]byte(fmt.Sprintf("specified round number could not be parsed using base 36 : %v", err)))
-		return
+		return 0, false
 	}
+	return round, true
+}
+
+// getCatchpointStream opens the catchpoint file of the given round, writing the error response if it can't be opened.
+func (ls *LedgerService) getCatchpointStream(response http.ResponseWriter, round uint64) (ledger.ReadCloseSizer, bool) {
 	cs, err := ls.ledger.GetCatchpointStream(basics.Round(round))
 	if err != nil {
 		switch err.(type) {
@@ -181,50 +368,14 @@ func (ls *LedgerService) ServeHTTP(response http.ResponseWriter, request *http.R
 			// entry cound not be found.
 			response.WriteHeader(http.StatusNotFound)
 			response.Write([]byte(fmt.Sprintf("catchpoint file for round %d is not available", round)))
-			return
+			return nil, false
 		default:
 			// unexpected error.
 			logging.Base().Warnf("ServeHTTP : failed to retrieve catchpoint %d %v", round, err)
 			response.WriteHeader(http.StatusInternalServerError)
 			response.Write([]byte(fmt.Sprintf("catchpoint file for round %d could not be retrieved due to internal error : %v", round, err)))
-			return
+			return nil, false
 		}
 	}
-	defer cs.Close()
-	if conn := ls.net.GetHTTPRequestConnection(request); conn != nil {
-		maxCatchpointFileWritingDuration := 2 * time.Minute
-
-		catchpointFileSize, err := cs.Size()
-		if err != nil || catchpointFileSize <= 0 {
-			maxCatchpointFileWritingDuration += maxCatchpointFileSize * time.Second / expectedWorstUploadSpeedBytesPerSecond
-		} else {
-			maxCatchpointFileWritingDuration += time.Duration(catchpointFileSize) * time.Second / expectedWorstUploadSpeedBytesPerSecond
-		}
-		conn.SetWriteDeadline(time.Now().Add(maxCatchpointFileWritingDuration))
-	} else {
-		logging.Base().Warnf("LedgerService.ServeHTTP unable to set connection timeout")
-	}
-
-	response.Header().Set("Content-Type", LedgerResponseContentType)
-	requestedCompressedResponse := strings.Contains(request.Header.Get("Accept-Encoding"), "gzip")
-	if requestedCompressedResponse {
-		response.Header().Set("Content-Encoding", "gzip")
-		written, err := io.Copy(response, cs)
-		if err != nil {
-			logging.Base().Infof("LedgerService.ServeHTTP : unable to write compressed catchpoint file for round %d, written bytes %d : %v", round, written, err)
-		}
-		return
-	}
-	decompressedGzip, err := gzip.NewReader(cs)
-	if err != nil {
-		logging.Base().Warnf("LedgerService.ServeHTTP : failed to decompress catchpoint %d %v", round, err)
-		response.WriteHeader(http.StatusInternalServerError)
-		response.Write([]byte(fmt.Sprintf("catchpoint file for round %d could not be decompressed due to internal error : %v", round, err)))
-		return
-	}
-	defer decompressedGzip.Close()
-	written, err := io.Copy(response, decompressedGzip)
-	if err != nil {
-		logging.Base().Infof("LedgerService.ServeHTTP : unable to write decompressed catchpoint file for round %d, written bytes %d : %v", round, written, err)
-	}
+	return cs, true
 }
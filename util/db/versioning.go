@@ -56,3 +56,50 @@ func SetUserVersion(ctx context.Context, tx *sql.Tx, userVersion int32) (previou
 	}
 	return
 }
+
+// componentVersionsTable holds the versions of the components sharing a single
+// database, when the user version field alone can't describe them all.
+const componentVersionsTable = "componentversions"
+
+// GetComponentVersion returns the version stored for the given component.
+// if the component was never initialized with a version, it would return 0 as the version.
+func GetComponentVersion(ctx context.Context, tx *sql.Tx, component string) (version int32, err error) {
+	var tableName string
+	err = tx.QueryRowContext(ctx, "SELECT name FROM sqlite_master WHERE type='table' AND name=?", componentVersionsTable).Scan(&tableName)
+	if err == sql.ErrNoRows {
+		// no component was ever versioned in this database.
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.QueryRowContext(ctx, fmt.Sprintf("SELECT version FROM %s WHERE component=?", componentVersionsTable), component).Scan(&version)
+	if err == sql.ErrNoRows {
+		err = nil
+		version = 0
+	}
+	return
+}
+
+// SetComponentVersion sets the version of the given component, and return the old version.
+func SetComponentVersion(ctx context.Context, tx *sql.Tx, component string, version int32) (previousVersion int32, err error) {
+	previousVersion, err = GetComponentVersion(ctx, tx, component)
+	if err != nil {
+		return
+	}
+	if previousVersion == version {
+		return
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (component text PRIMARY KEY, version integer)", componentVersionsTable))
+	if err == nil {
+		_, err = tx.ExecContext(ctx, fmt.Sprintf("INSERT OR REPLACE INTO %s(component, version) VALUES(?, ?)", componentVersionsTable), component, version)
+	}
+	if err != nil {
+		// if we're aborting due to an error, clear the previousVersion so that
+		// on all error cases we'll be returning zero.
+		previousVersion = 0
+		return
+	}
+	return
+}
@@ -92,3 +92,45 @@ func TestVersioning(t *testing.T) {
 	t.Run("InMem", func(t *testing.T) { testVersioning(t, true) })
 	t.Run("OnDisk", func(t *testing.T) { testVersioning(t, false) })
 }
+
+func TestComponentVersioning(t *testing.T) {
+	acc, err := MakeAccessor("fn.db", false, true)
+	require.NoError(t, err)
+	defer acc.Close()
+
+	err = acc.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		ver, err := GetComponentVersion(ctx, tx, "first")
+		require.NoError(t, err)
+		require.Equal(t, int32(0), ver)
+
+		previousVersion, err := SetComponentVersion(ctx, tx, "first", 3)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), previousVersion)
+
+		previousVersion, err = SetComponentVersion(ctx, tx, "second", 7)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), previousVersion)
+
+		previousVersion, err = SetComponentVersion(ctx, tx, "first", 4)
+		require.NoError(t, err)
+		require.Equal(t, int32(3), previousVersion)
+
+		// components are versioned independently of each other, and of the user version
+		ver, err = GetComponentVersion(ctx, tx, "second")
+		require.NoError(t, err)
+		require.Equal(t, int32(7), ver)
+		ver, err = GetUserVersion(ctx, tx)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), ver)
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = acc.Atomic(func(ctx context.Context, tx *sql.Tx) error {
+		ver, err := GetComponentVersion(ctx, tx, "first")
+		require.NoError(t, err)
+		require.Equal(t, int32(4), ver)
+		return nil
+	})
+	require.NoError(t, err)
+}
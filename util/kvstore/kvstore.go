@@ -0,0 +1,642 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package kvstore is a small embedded key-value store written in pure Go.
+//
+// The whole data set is kept in memory, ordered by key, so the store is only
+// suited to data sets which fit in memory: a store refuses the transactions
+// which would take its data above its maximum size, DefaultMaxSize unless set
+// otherwise with SetMaxSize. Every committed transaction is appended to a log
+// file as a single checksummed record, which is replayed when the store is
+// opened. A record that was only partially written, e.g. because of a crash,
+// is discarded along with everything after it. The log is rewritten from the
+// live data once it grows to more than twice its size.
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/algorand/go-deadlock"
+)
+
+const (
+	opPut    = byte(1)
+	opDelete = byte(2)
+
+	// recordHeaderSize is the size of the length and checksum that precede every record of the log
+	recordHeaderSize = 8
+
+	// compactMinSize is the log size below which the log is never compacted
+	compactMinSize = 4 * 1024 * 1024
+
+	// compactRecordSize is the approximate size of the records written when compacting the log
+	compactRecordSize = 1024 * 1024
+)
+
+// DefaultMaxSize is the maximum size of the data of a store, unless set
+// otherwise with SetMaxSize
+const DefaultMaxSize = 2 * 1024 * 1024 * 1024
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrReadOnly is returned when writing in a transaction started with View
+var ErrReadOnly = errors.New("kvstore: transaction is read only")
+
+// ErrClosed is returned when using a store that was closed
+var ErrClosed = errors.New("kvstore: store is closed")
+
+// ErrFull is returned when committing a transaction would take the data of
+// the store above its maximum size
+var ErrFull = errors.New("kvstore: store is full")
+
+// Store is an ordered key-value store. It allows many concurrent read-only
+// transactions or a single read-write transaction at a time.
+type Store struct {
+	mu deadlock.RWMutex
+
+	data map[string][]byte
+	// keys holds the keys of data, sorted
+	keys []string
+
+	file     *os.File
+	filename string
+	sync     bool
+	closed   bool
+
+	// logSize is the size of the log file, liveSize the size the log would have if it only held the current data
+	logSize  int64
+	liveSize int64
+	maxSize  int64
+
+	// compactAfter is the log size the log has to grow beyond before compacting it again after a failed compaction
+	compactAfter int64
+}
+
+// Open opens the store kept in filename, creating it if it doesn't exist.
+// If memory is true, filename is ignored and the store isn't persisted.
+func Open(filename string, memory bool) (*Store, error) {
+	s := &Store{
+		data:    make(map[string][]byte),
+		sync:    true,
+		maxSize: DefaultMaxSize,
+	}
+	if memory {
+		return s, nil
+	}
+
+	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.file = f
+	s.filename = filename
+
+	err = s.replay()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetSynchronous sets whether every commit waits for the log file to be
+// flushed to stable storage. It is set by default.
+func (s *Store) SetSynchronous(sync bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sync = sync
+}
+
+// SetMaxSize sets the maximum size of the data of the store, as measured by
+// Size. Transactions which would take the data above it fail with ErrFull,
+// unless they shrink it. A zero size removes the limit.
+func (s *Store) SetMaxSize(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxSize = size
+}
+
+// Size returns the approximate size of the data of the store, which is also
+// about the size of the log file once compacted.
+func (s *Store) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.liveSize
+}
+
+// Close closes the log file. The store cannot be used after it was closed.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.data = nil
+	s.keys = nil
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+// Compact rewrites the log file from the current data, releasing the space
+// taken by the records of overwritten and deleted keys.
+func (s *Store) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if s.file == nil {
+		return nil
+	}
+	return s.compact()
+}
+
+// View runs fn in a read-only transaction
+func (s *Store) View(fn func(tx *Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	return fn(&Tx{s: s})
+}
+
+// Update runs fn in a read-write transaction. The changes made by fn are
+// committed if it returns nil and discarded otherwise.
+func (s *Store) Update(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	tx := &Tx{s: s, writable: true, pending: make(map[string]pendingOp)}
+	err := fn(tx)
+	if err != nil {
+		return err
+	}
+	return s.commit(tx)
+}
+
+// pendingOp is a change made by a read-write transaction that was not committed yet
+type pendingOp struct {
+	value   []byte
+	deleted bool
+}
+
+// Tx is a transaction of a Store. It must not be used after the function it
+// was given to returns.
+type Tx struct {
+	s        *Store
+	writable bool
+	pending  map[string]pendingOp
+}
+
+// Get returns the value of key. The returned slice must not be modified.
+func (tx *Tx) Get(key []byte) (value []byte, ok bool) {
+	if op, has := tx.pending[string(key)]; has {
+		if op.deleted {
+			return nil, false
+		}
+		return op.value, true
+	}
+	value, ok = tx.s.data[string(key)]
+	return
+}
+
+// Put sets the value of key
+func (tx *Tx) Put(key []byte, value []byte) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+	tx.pending[string(key)] = pendingOp{value: append([]byte{}, value...)}
+	return nil
+}
+
+// Delete removes key, if it exists
+func (tx *Tx) Delete(key []byte) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+	tx.pending[string(key)] = pendingOp{deleted: true}
+	return nil
+}
+
+// Iterate calls fn for every key in [start, end) in order, or in reverse order
+// if reverse is set, until fn returns false. A nil end has no upper bound.
+// The slices given to fn must not be modified or retained.
+func (tx *Tx) Iterate(start []byte, end []byte, reverse bool, fn func(key []byte, value []byte) bool) {
+	committed, added := tx.rangeKeys(start, end)
+	// walk both sorted lists, from the end if reversed
+	i, j := 0, 0
+	for i < len(committed) || j < len(added) {
+		var key string
+		ci, aj := i, j
+		if reverse {
+			ci, aj = len(committed)-1-i, len(added)-1-j
+		}
+		switch {
+		case j == len(added):
+			key = committed[ci]
+			i++
+		case i == len(committed):
+			key = added[aj]
+			j++
+		case (committed[ci] < added[aj]) != reverse:
+			key = committed[ci]
+			i++
+		default:
+			key = added[aj]
+			j++
+		}
+		value, ok := tx.Get([]byte(key))
+		if !ok {
+			// deleted by this transaction
+			continue
+		}
+		if !fn([]byte(key), value) {
+			return
+		}
+	}
+}
+
+// DeleteRange removes every key in [start, end). A nil end has no upper bound.
+func (tx *Tx) DeleteRange(start []byte, end []byte) error {
+	if !tx.writable {
+		return ErrReadOnly
+	}
+	committed, added := tx.rangeKeys(start, end)
+	for _, keys := range [][]string{committed, added} {
+		for _, key := range keys {
+			tx.pending[key] = pendingOp{deleted: true}
+		}
+	}
+	return nil
+}
+
+// rangeKeys returns the committed keys in [start, end) and the keys in that
+// range added by the transaction, both sorted. Some of the committed keys may
+// have been deleted by the transaction.
+func (tx *Tx) rangeKeys(start []byte, end []byte) (committed []string, added []string) {
+	committed = tx.s.keys
+	lo := sort.SearchStrings(committed, string(start))
+	hi := len(committed)
+	if end != nil {
+		hi = sort.SearchStrings(committed, string(end))
+	}
+	if hi < lo {
+		hi = lo
+	}
+	committed = committed[lo:hi]
+
+	for key, op := range tx.pending {
+		if op.deleted || key < string(start) || (end != nil && key >= string(end)) {
+			continue
+		}
+		if _, has := tx.s.data[key]; !has {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	return
+}
+
+// commit writes the changes of tx to the log and applies them to the store
+func (s *Store) commit(tx *Tx) error {
+	if len(tx.pending) == 0 {
+		return nil
+	}
+
+	changed := make([]string, 0, len(tx.pending))
+	var growth int64
+	for key, op := range tx.pending {
+		changed = append(changed, key)
+		if old, has := s.data[key]; has {
+			growth -= opSize(key, old)
+		}
+		if !op.deleted {
+			growth += opSize(key, op.value)
+		}
+	}
+	if s.maxSize != 0 && growth > 0 && s.liveSize+growth > s.maxSize {
+		return ErrFull
+	}
+	sort.Strings(changed)
+
+	if s.file != nil {
+		var payload bytes.Buffer
+		for _, key := range changed {
+			op := tx.pending[key]
+			if op.deleted {
+				writeOp(&payload, opDelete, key, nil)
+			} else {
+				writeOp(&payload, opPut, key, op.value)
+			}
+		}
+		record := makeRecord(payload.Bytes())
+		_, err := s.file.Write(record)
+		if err == nil && s.sync {
+			err = s.file.Sync()
+		}
+		if err != nil {
+			s.discardUncommitted()
+			return err
+		}
+		s.logSize += int64(len(record))
+	}
+
+	for _, key := range changed {
+		op := tx.pending[key]
+		if op.deleted {
+			s.remove(key)
+		} else {
+			s.set(key, op.value)
+		}
+	}
+	s.sortKeys(changed)
+
+	if s.file != nil && s.logSize > compactMinSize && s.logSize > 2*s.liveSize && s.logSize > s.compactAfter {
+		// the transaction is durable at this point, so a failed compaction
+		// doesn't fail it; it is retried once the log doubled in size
+		if s.compact() != nil {
+			s.compactAfter = 2 * s.logSize
+		}
+	}
+	return nil
+}
+
+// discardUncommitted drops what follows the last committed record of the
+// log, so that a record which failed to be written or synced is neither
+// replayed nor followed by later records, which would be discarded with a
+// partial record. If the log can't be truncated, logSize is brought in line
+// with the actual size of the file.
+func (s *Store) discardUncommitted() {
+	err := s.file.Truncate(s.logSize)
+	if err == nil {
+		_, err = s.file.Seek(s.logSize, io.SeekStart)
+	}
+	if err != nil {
+		offset, serr := s.file.Seek(0, io.SeekEnd)
+		if serr == nil {
+			s.logSize = offset
+		}
+	}
+}
+
+// set updates data and liveSize; keys are fixed up by sortKeys
+func (s *Store) set(key string, value []byte) {
+	if old, has := s.data[key]; has {
+		s.liveSize -= opSize(key, old)
+	}
+	s.data[key] = value
+	s.liveSize += opSize(key, value)
+}
+
+func (s *Store) remove(key string) {
+	if old, has := s.data[key]; has {
+		s.liveSize -= opSize(key, old)
+		delete(s.data, key)
+	}
+}
+
+// sortKeys brings keys in line with data after the given sorted keys were changed
+func (s *Store) sortKeys(changed []string) {
+	var added []string
+	removed := false
+	for _, key := range changed {
+		_, has := s.data[key]
+		idx := sort.SearchStrings(s.keys, key)
+		inKeys := idx < len(s.keys) && s.keys[idx] == key
+		if has && !inKeys {
+			added = append(added, key)
+		}
+		if !has && inKeys {
+			removed = true
+		}
+	}
+
+	keys := s.keys
+	if removed {
+		keys = make([]string, 0, len(s.keys))
+		for _, key := range s.keys {
+			if _, has := s.data[key]; has {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if len(added) == 0 {
+		s.keys = keys
+		return
+	}
+	// the common case of keys that are appended in order doesn't need a merge
+	if len(keys) == 0 || keys[len(keys)-1] < added[0] {
+		s.keys = append(keys, added...)
+		return
+	}
+	merged := make([]string, 0, len(keys)+len(added))
+	i, j := 0, 0
+	for i < len(keys) || j < len(added) {
+		if j == len(added) || (i < len(keys) && keys[i] < added[j]) {
+			merged = append(merged, keys[i])
+			i++
+		} else {
+			merged = append(merged, added[j])
+			j++
+		}
+	}
+	s.keys = merged
+}
+
+// replay loads the log file into the store, and truncates it after the last complete record
+func (s *Store) replay() error {
+	stat, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	r := bufio.NewReader(s.file)
+	var offset int64
+	var header [recordHeaderSize]byte
+	for {
+		_, err := io.ReadFull(r, header[:])
+		if err != nil {
+			break
+		}
+		length := binary.LittleEndian.Uint32(header[0:4])
+		checksum := binary.LittleEndian.Uint32(header[4:8])
+		if offset+recordHeaderSize+int64(length) > stat.Size() {
+			break
+		}
+		payload := make([]byte, length)
+		_, err = io.ReadFull(r, payload)
+		if err != nil || crc32.Checksum(payload, crcTable) != checksum {
+			break
+		}
+		err = s.applyPayload(payload)
+		if err != nil {
+			return fmt.Errorf("kvstore: corrupted record at offset %d of %s: %v", offset, s.filename, err)
+		}
+		offset += recordHeaderSize + int64(length)
+	}
+
+	err = s.file.Truncate(offset)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return err
+	}
+	s.logSize = offset
+
+	s.keys = make([]string, 0, len(s.data))
+	for key := range s.data {
+		s.keys = append(s.keys, key)
+	}
+	sort.Strings(s.keys)
+	return nil
+}
+
+func (s *Store) applyPayload(payload []byte) error {
+	for len(payload) > 0 {
+		op := payload[0]
+		payload = payload[1:]
+		key, n := readBytes(payload)
+		if n <= 0 {
+			return fmt.Errorf("invalid key")
+		}
+		payload = payload[n:]
+		switch op {
+		case opPut:
+			value, n := readBytes(payload)
+			if n <= 0 {
+				return fmt.Errorf("invalid value")
+			}
+			payload = payload[n:]
+			s.set(string(key), append([]byte{}, value...))
+		case opDelete:
+			s.remove(string(key))
+		default:
+			return fmt.Errorf("unknown operation %d", op)
+		}
+	}
+	return nil
+}
+
+// compact replaces the log file by one holding only the current data
+func (s *Store) compact() error {
+	tmpFilename := s.filename + ".tmp"
+	f, err := os.OpenFile(tmpFilename, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpFilename)
+		}
+	}()
+
+	w := bufio.NewWriter(f)
+	var size int64
+	var payload bytes.Buffer
+	flush := func() error {
+		if payload.Len() == 0 {
+			return nil
+		}
+		record := makeRecord(payload.Bytes())
+		payload.Reset()
+		size += int64(len(record))
+		_, err := w.Write(record)
+		return err
+	}
+	for _, key := range s.keys {
+		writeOp(&payload, opPut, key, s.data[key])
+		if payload.Len() >= compactRecordSize {
+			err = flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = flush()
+	if err != nil {
+		return err
+	}
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+	err = f.Sync()
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpFilename, s.filename)
+	if err != nil {
+		return err
+	}
+
+	s.file.Close()
+	s.file = f
+	s.logSize = size
+	s.compactAfter = 0
+	return nil
+}
+
+func makeRecord(payload []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(record[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(record[4:8], crc32.Checksum(payload, crcTable))
+	copy(record[recordHeaderSize:], payload)
+	return record
+}
+
+func writeOp(buf *bytes.Buffer, op byte, key string, value []byte) {
+	var lenbuf [binary.MaxVarintLen64]byte
+	buf.WriteByte(op)
+	n := binary.PutUvarint(lenbuf[:], uint64(len(key)))
+	buf.Write(lenbuf[:n])
+	buf.WriteString(key)
+	if op == opPut {
+		n = binary.PutUvarint(lenbuf[:], uint64(len(value)))
+		buf.Write(lenbuf[:n])
+		buf.Write(value)
+	}
+}
+
+// opSize is the approximate size of a put of key and value in the log
+func opSize(key string, value []byte) int64 {
+	return int64(1 + 2*binary.MaxVarintLen32 + len(key) + len(value))
+}
+
+// readBytes reads a length prefixed byte slice, and returns it along with the
+// number of bytes read, or 0 if buf is too short
+func readBytes(buf []byte) ([]byte, int) {
+	length, n := binary.Uvarint(buf)
+	if n <= 0 || uint64(len(buf)-n) < length {
+		return nil, 0
+	}
+	return buf[n : n+int(length)], n + int(length)
+}
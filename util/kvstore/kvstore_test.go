@@ -0,0 +1,273 @@
+// Copyright (C) 2019-2021 Algorand, Inc.
+// This file is part of go-algorand
+//
+// go-algorand is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as
+// published by the Free Software Foundation, either version 3 of the
+// License, or (at your option) any later version.
+//
+// go-algorand is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with go-algorand.  If not, see <https://www.gnu.org/licenses/>.
+
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collect(tx *Tx, start, end []byte, reverse bool) (keys []string) {
+	tx.Iterate(start, end, reverse, func(key, value []byte) bool {
+		keys = append(keys, string(key))
+		return true
+	})
+	return
+}
+
+func TestStoreTransactions(t *testing.T) {
+	s, err := Open("", true)
+	require.NoError(t, err)
+	defer s.Close()
+
+	err = s.Update(func(tx *Tx) error {
+		for _, k := range []string{"b", "d", "a", "c"} {
+			require.NoError(t, tx.Put([]byte(k), []byte("v"+k)))
+		}
+		// pending writes are visible within the transaction
+		require.Equal(t, []string{"a", "b", "c", "d"}, collect(tx, nil, nil, false))
+		return nil
+	})
+	require.NoError(t, err)
+
+	// a failed transaction leaves no trace
+	err = s.Update(func(tx *Tx) error {
+		require.NoError(t, tx.Put([]byte("e"), []byte("ve")))
+		require.NoError(t, tx.Delete([]byte("a")))
+		return errors.New("abort")
+	})
+	require.Error(t, err)
+
+	err = s.Update(func(tx *Tx) error {
+		require.NoError(t, tx.Delete([]byte("b")))
+		require.NoError(t, tx.Put([]byte("bb"), []byte("vbb")))
+		require.NoError(t, tx.Put([]byte("c"), []byte("vc2")))
+		require.Equal(t, []string{"a", "bb", "c"}, collect(tx, []byte("a"), []byte("d"), false))
+		require.Equal(t, []string{"d", "c", "bb"}, collect(tx, []byte("b"), nil, true))
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = s.View(func(tx *Tx) error {
+		require.Equal(t, []string{"a", "bb", "c", "d"}, collect(tx, nil, nil, false))
+		v, ok := tx.Get([]byte("c"))
+		require.True(t, ok)
+		require.Equal(t, "vc2", string(v))
+		_, ok = tx.Get([]byte("b"))
+		require.False(t, ok)
+		require.Equal(t, ErrReadOnly, tx.Put([]byte("x"), nil))
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = s.Update(func(tx *Tx) error {
+		return tx.DeleteRange([]byte("b"), []byte("d"))
+	})
+	require.NoError(t, err)
+	err = s.View(func(tx *Tx) error {
+		require.Equal(t, []string{"a", "d"}, collect(tx, nil, nil, false))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestStoreReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "store.kv")
+	s, err := Open(filename, false)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		err = s.Update(func(tx *Tx) error {
+			return tx.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+		})
+		require.NoError(t, err)
+	}
+	err = s.Update(func(tx *Tx) error {
+		return tx.Delete([]byte("key3"))
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	// a torn write at the end of the log is dropped
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{100, 0, 0, 0, 1, 2, 3, 4, opPut})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	s, err = Open(filename, false)
+	require.NoError(t, err)
+	err = s.View(func(tx *Tx) error {
+		keys := collect(tx, nil, nil, false)
+		require.Len(t, keys, 9)
+		require.NotContains(t, keys, "key3")
+		v, ok := tx.Get([]byte("key7"))
+		require.True(t, ok)
+		require.Equal(t, "value7", string(v))
+		return nil
+	})
+	require.NoError(t, err)
+
+	// and writing resumes after the last complete record
+	err = s.Update(func(tx *Tx) error {
+		return tx.Put([]byte("key3"), []byte("again"))
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = Open(filename, false)
+	require.NoError(t, err)
+	defer s.Close()
+	err = s.View(func(tx *Tx) error {
+		v, ok := tx.Get([]byte("key3"))
+		require.True(t, ok)
+		require.Equal(t, "again", string(v))
+		require.Len(t, collect(tx, nil, nil, false), 10)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestStoreCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "store.kv")
+	s, err := Open(filename, false)
+	require.NoError(t, err)
+	s.SetSynchronous(false)
+
+	value := make([]byte, 64*1024)
+	for i := 0; i < 200; i++ {
+		err = s.Update(func(tx *Tx) error {
+			value[0] = byte(i)
+			return tx.Put([]byte(fmt.Sprintf("key%d", i%10)), value)
+		})
+		require.NoError(t, err)
+	}
+	// 200 writes of 64KB would make a 12.8MB log without compaction
+	stat, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Less(t, stat.Size(), int64(compactMinSize))
+	require.NoError(t, s.Close())
+
+	s, err = Open(filename, false)
+	require.NoError(t, err)
+	defer s.Close()
+	err = s.View(func(tx *Tx) error {
+		require.Len(t, collect(tx, nil, nil, false), 10)
+		v, ok := tx.Get([]byte("key9"))
+		require.True(t, ok)
+		require.Equal(t, byte(199), v[0])
+		return nil
+	})
+	require.NoError(t, err)
+
+	// deleting keys doesn't shrink a small log, unless it's compacted explicitly
+	err = s.Update(func(tx *Tx) error {
+		return tx.DeleteRange([]byte("key1"), nil)
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Compact())
+	stat, err = os.Stat(filename)
+	require.NoError(t, err)
+	require.Less(t, stat.Size(), int64(2*len(value)))
+	require.NoError(t, s.View(func(tx *Tx) error {
+		require.Equal(t, []string{"key0"}, collect(tx, nil, nil, false))
+		return nil
+	}))
+}
+
+func TestStoreCompactionFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "store.kv")
+	s, err := Open(filename, false)
+	require.NoError(t, err)
+	defer s.Close()
+	s.SetSynchronous(false)
+
+	// the compacted log can't be written while a directory is in its way
+	require.NoError(t, os.Mkdir(filename+".tmp", 0700))
+
+	value := make([]byte, 64*1024)
+	for i := 0; i < 200; i++ {
+		err = s.Update(func(tx *Tx) error {
+			value[0] = byte(i)
+			return tx.Put([]byte(fmt.Sprintf("key%d", i%10)), value)
+		})
+		// the writes are durable, so they succeed regardless
+		require.NoError(t, err)
+	}
+	require.Error(t, s.Compact())
+	stat, err := os.Stat(filename)
+	require.NoError(t, err)
+	require.Greater(t, stat.Size(), int64(compactMinSize))
+
+	require.NoError(t, os.Remove(filename+".tmp"))
+	require.NoError(t, s.Compact())
+	require.NoError(t, s.View(func(tx *Tx) error {
+		v, ok := tx.Get([]byte("key9"))
+		require.True(t, ok)
+		require.Equal(t, byte(199), v[0])
+		return nil
+	}))
+}
+
+func TestStoreMaxSize(t *testing.T) {
+	s, err := Open("", true)
+	require.NoError(t, err)
+	defer s.Close()
+	s.SetMaxSize(1000)
+
+	value := make([]byte, 400)
+	put := func(key string) error {
+		return s.Update(func(tx *Tx) error {
+			return tx.Put([]byte(key), value)
+		})
+	}
+	require.NoError(t, put("a"))
+	require.NoError(t, put("b"))
+	require.Equal(t, ErrFull, put("c"))
+	// overwriting a key doesn't grow the store
+	require.NoError(t, put("b"))
+	require.Less(t, s.Size(), int64(1000))
+
+	// a transaction which shrinks the store succeeds even if it stays full
+	s.SetMaxSize(500)
+	require.NoError(t, s.Update(func(tx *Tx) error {
+		require.NoError(t, tx.Delete([]byte("a")))
+		return tx.Put([]byte("c"), value[:100])
+	}))
+	require.Equal(t, ErrFull, put("d"))
+
+	s.SetMaxSize(0)
+	require.NoError(t, put("d"))
+	require.NoError(t, s.View(func(tx *Tx) error {
+		require.Equal(t, []string{"b", "c", "d"}, collect(tx, nil, nil, false))
+		return nil
+	}))
+}
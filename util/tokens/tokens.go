@@ -33,6 +33,7 @@ const (
 	AlgodTokenFilename      = "algod.token"
 	AlgodAdminTokenFilename = "algod.admin.token"
 	KmdTokenFilename        = "kmd.token"
+	PartSignerTokenFilename = "partsigner.token"
 )
 
 func tokenFilepath(dataDir, tokenFilename string) string {